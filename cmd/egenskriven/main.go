@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 	"strings"
 
 	"github.com/pocketbase/pocketbase"
@@ -47,9 +48,42 @@ func main() {
 	// Register custom CLI commands
 	commands.Register(app)
 
+	// Register the REST routes sharing the logic of the CLI commands
+	commands.RegisterRoutes(app)
+
 	// Register comment hooks for auto-resume functionality
 	hooks.RegisterCommentHooks(app)
 
+	// Register time tracking hooks for automatic task timers
+	hooks.RegisterTimeTrackingHooks(app)
+
+	// Register auth hooks to keep API rules in sync when auth is enabled
+	hooks.RegisterAuthHooks(app)
+
+	// Register policy hooks to enforce agent modes on API requests
+	hooks.RegisterPolicyHooks(app)
+
+	// Register attachment hooks to enforce the configured size limits
+	hooks.RegisterAttachmentHooks(app)
+
+	// Register checklist hooks to sync checklists with description checkboxes
+	hooks.RegisterChecklistHooks(app)
+
+	// Register task event hooks to log every task change in task_events
+	hooks.RegisterTaskEventHooks(app)
+
+	// Register undo hooks to record task changes as reversible operations
+	hooks.RegisterUndoHooks(app)
+
+	// Register trash hooks so API deletes move records to the trash
+	hooks.RegisterTrashHooks(app)
+
+	// Register the hourly auto-archive run of the server
+	hooks.RegisterArchiveSchedule(app)
+
+	// Register the calendar feeds of the boards' due dates
+	hooks.RegisterCalendarRoutes(app)
+
 	// Hook: Assign sequence number to tasks created via API
 	// This ensures the UI doesn't need to handle sequence assignment,
 	// avoiding race conditions when multiple tasks are created concurrently.
@@ -96,6 +130,15 @@ func main() {
 		return e.Next()
 	})
 
+	// Remote mode runs the command against a shared server only: the local
+	// database is never opened, so the app is not started (Start bootstraps)
+	if commands.RemoteMode(app, os.Args[1:]) {
+		if err := app.RootCmd.Execute(); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Start the application
 	if err := app.Start(); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,235 @@
+// Package archive hides finished tasks from boards without deleting them.
+//
+// An archived task keeps its column (usually done) and all of its data but
+// has archived_at set, so lists, exports, and the TUI skip it unless asked
+// for archived tasks. Boards can archive done tasks automatically after a
+// number of days; the server runs the policy hourly and `archive run` runs
+// it on demand.
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+)
+
+// AutoActor is recorded as the archiver of tasks archived by the server's
+// schedule.
+const AutoActor = "auto-archive"
+
+// Schedule is the cron expression the server runs the auto-archive
+// policy with.
+const Schedule = "0 * * * *"
+
+var (
+	// ErrArchived is returned when archiving an archived task.
+	ErrArchived = errors.New("task is already archived")
+	// ErrNotArchived is returned when unarchiving a task that is not archived.
+	ErrNotArchived = errors.New("task is not archived")
+)
+
+// Mode selects which tasks a query returns with respect to the archive.
+type Mode int
+
+const (
+	// Active selects tasks that are not archived (the default).
+	Active Mode = iota
+	// All selects archived and active tasks.
+	All
+	// Archived selects only archived tasks.
+	Archived
+)
+
+// ModeFor returns the mode for the --include-archived and --archived flags.
+func ModeFor(includeArchived, archivedOnly bool) Mode {
+	switch {
+	case archivedOnly:
+		return Archived
+	case includeArchived:
+		return All
+	default:
+		return Active
+	}
+}
+
+// Includes reports whether a mode selects a task with the given archive
+// state.
+func (m Mode) Includes(archived bool) bool {
+	switch m {
+	case All:
+		return true
+	case Archived:
+		return archived
+	default:
+		return !archived
+	}
+}
+
+// Filter returns the expression matching tasks for a mode. Databases
+// without the archive fields have no archived tasks.
+func Filter(app core.App, mode Mode) dbx.Expression {
+	c, err := app.FindCachedCollectionByNameOrId("tasks")
+	if err != nil || c.Fields.GetByName("archived_at") == nil {
+		if mode == Archived {
+			return dbx.NewExp("1=0")
+		}
+		return dbx.NewExp("1=1")
+	}
+
+	switch mode {
+	case All:
+		return dbx.NewExp("1=1")
+	case Archived:
+		return dbx.NewExp("archived_at != ''")
+	default:
+		return dbx.NewExp("archived_at = ''")
+	}
+}
+
+// IsArchived reports whether a task is archived.
+func IsArchived(task *core.Record) bool {
+	return !task.GetDateTime("archived_at").IsZero()
+}
+
+// Task archives a task. source ("cli", "tui", "server", ...) and actor are
+// recorded in the task event log.
+func Task(app core.App, task *core.Record, source, actor string) error {
+	if IsArchived(task) {
+		return ErrArchived
+	}
+	task.Set("archived_at", types.NowDateTime())
+	task.Set("archived_by", actor)
+	taskevent.Add(task, "archived", source, actor, nil, nil)
+	return app.Save(task)
+}
+
+// Unarchive returns an archived task to its board.
+func Unarchive(app core.App, task *core.Record, source, actor string) error {
+	if !IsArchived(task) {
+		return ErrNotArchived
+	}
+	task.Set("archived_at", "")
+	task.Set("archived_by", "")
+	taskevent.Add(task, "unarchived", source, actor, nil, nil)
+	return app.Save(task)
+}
+
+// PolicyDays returns the number of days after which a board archives done
+// tasks, or 0 if it does not.
+func PolicyDays(board *core.Record) int {
+	return board.GetInt("auto_archive_days")
+}
+
+// RunOptions configures a run of the auto-archive policy.
+type RunOptions struct {
+	Board  string     // Only this board (default: every board with a policy)
+	Now    time.Time  // Reference time (default: now)
+	Source string     // Recorded in the task event log ("cli", "server")
+	Batch  undo.Batch // Undo batch and actor the archiving is recorded under
+	DryRun bool       // Report the tasks without archiving them
+}
+
+// BoardResult lists the tasks a run archived on one board.
+type BoardResult struct {
+	Board *core.Record
+	Days  int
+	Tasks []*core.Record
+}
+
+// Run archives, on every board with a policy, the done tasks that have been
+// done for longer than the board's auto_archive_days. A task's done time is
+// when it last moved to done, or its last update if no such move was
+// recorded.
+func Run(app core.App, opts RunOptions) ([]BoardResult, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	exprs := []dbx.Expression{
+		dbx.NewExp("auto_archive_days > 0"),
+		trash.Exclude(app, "boards"),
+	}
+	if opts.Board != "" {
+		exprs = append(exprs, dbx.HashExp{"id": opts.Board})
+	}
+	boards, err := app.FindAllRecords("boards", exprs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []BoardResult
+	for _, board := range boards {
+		days := PolicyDays(board)
+		due, err := dueTasks(app, board.Id, opts.Now.AddDate(0, 0, -days))
+		if err != nil {
+			return results, err
+		}
+		if len(due) == 0 {
+			continue
+		}
+
+		if !opts.DryRun {
+			err := app.RunInTransaction(func(txApp core.App) error {
+				for _, task := range due {
+					undo.Tag(task, opts.Batch)
+					if err := Task(txApp, task, opts.Source, opts.Batch.Actor); err != nil {
+						return fmt.Errorf("failed to archive task %s: %w", task.Id, err)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return results, err
+			}
+		}
+		results = append(results, BoardResult{Board: board, Days: days, Tasks: due})
+	}
+	return results, nil
+}
+
+// dueTasks returns the active done tasks of a board done before cutoff.
+func dueTasks(app core.App, boardID string, cutoff time.Time) ([]*core.Record, error) {
+	tasks, err := app.FindAllRecords("tasks",
+		dbx.HashExp{"board": boardID, "column": flow.ColumnDone},
+		Filter(app, Active),
+		trash.Exclude(app, "tasks"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.Id
+	}
+	transitions, err := taskevent.Transitions(app, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []*core.Record
+	for _, task := range tasks {
+		doneAt, ok := flow.NewTimeline(flow.TaskInput{
+			ID:          task.Id,
+			Column:      task.GetString("column"),
+			Created:     task.GetDateTime("created").Time(),
+			Transitions: transitions[task.Id],
+		}).DoneAt()
+		if !ok {
+			doneAt = task.GetDateTime("updated").Time()
+		}
+		if doneAt.Before(cutoff) {
+			due = append(due, task)
+		}
+	}
+	return due, nil
+}
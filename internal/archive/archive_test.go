@@ -0,0 +1,174 @@
+package archive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+)
+
+// setup creates minimal boards and tasks collections with the archive
+// fields, and logs task events.
+func setup(t *testing.T) *pocketbase.PocketBase {
+	t.Helper()
+
+	app := testutil.NewTestApp(t)
+	testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name", Required: true},
+		&core.TextField{Name: "prefix"},
+		&core.NumberField{Name: "auto_archive_days", OnlyInt: true},
+	)
+	testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "board"},
+		&core.TextField{Name: "column"},
+		&core.DateField{Name: "archived_at"},
+		&core.TextField{Name: "archived_by"},
+	)
+	testutil.CreateTaskEventsCollection(t, app)
+	taskevent.Bind(app)
+	return app
+}
+
+func newRecord(t *testing.T, app *pocketbase.PocketBase, collection string, fields map[string]any) *core.Record {
+	t.Helper()
+
+	c, err := app.FindCollectionByNameOrId(collection)
+	require.NoError(t, err)
+	record := core.NewRecord(c)
+	for k, v := range fields {
+		record.Set(k, v)
+	}
+	require.NoError(t, app.Save(record))
+	return record
+}
+
+// doneTask creates a task that moved to done at the given time.
+func doneTask(t *testing.T, app *pocketbase.PocketBase, boardID, title string, doneAt time.Time) *core.Record {
+	t.Helper()
+
+	task := newRecord(t, app, "tasks", map[string]any{
+		"title":  title,
+		"board":  boardID,
+		"column": "done",
+	})
+	require.NoError(t, taskevent.Save(app, taskevent.Event{
+		Task:      task.Id,
+		Board:     boardID,
+		Action:    taskevent.ActionMoved,
+		Actor:     "cli",
+		Changes:   map[string]any{"column": map[string]any{"from": "review", "to": "done"}},
+		Timestamp: doneAt,
+	}))
+	return task
+}
+
+func titles(t *testing.T, app *pocketbase.PocketBase, mode Mode) []string {
+	t.Helper()
+
+	tasks, err := app.FindAllRecords("tasks", Filter(app, mode))
+	require.NoError(t, err)
+	var result []string
+	for _, task := range tasks {
+		result = append(result, task.GetString("title"))
+	}
+	return result
+}
+
+func TestTaskAndUnarchive(t *testing.T) {
+	app := setup(t)
+	a := newRecord(t, app, "tasks", map[string]any{"title": "A", "column": "done"})
+	newRecord(t, app, "tasks", map[string]any{"title": "B", "column": "todo"})
+
+	require.NoError(t, Task(app, a, "cli", "ramtin"))
+	assert.ErrorIs(t, Task(app, a, "cli", "ramtin"), ErrArchived)
+
+	a, err := app.FindRecordById("tasks", a.Id)
+	require.NoError(t, err)
+	assert.True(t, IsArchived(a))
+	assert.Equal(t, "ramtin", a.GetString("archived_by"))
+	assert.Equal(t, "done", a.GetString("column"), "archiving keeps the column")
+
+	assert.Equal(t, []string{"B"}, titles(t, app, Active))
+	assert.Equal(t, []string{"A"}, titles(t, app, Archived))
+	assert.ElementsMatch(t, []string{"A", "B"}, titles(t, app, All))
+
+	require.NoError(t, Unarchive(app, a, "cli", "ramtin"))
+	assert.ErrorIs(t, Unarchive(app, a, "cli", "ramtin"), ErrNotArchived)
+	assert.ElementsMatch(t, []string{"A", "B"}, titles(t, app, Active))
+
+	events, err := taskevent.ForTask(app, a.Id)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "archived", events[1].Action)
+	assert.Equal(t, "unarchived", events[2].Action)
+	assert.Equal(t, "ramtin", events[2].ActorName())
+}
+
+func TestRun(t *testing.T) {
+	app := setup(t)
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	work := newRecord(t, app, "boards", map[string]any{"name": "Work", "prefix": "WRK", "auto_archive_days": 14})
+	home := newRecord(t, app, "boards", map[string]any{"name": "Home", "prefix": "HOM"})
+
+	old := doneTask(t, app, work.Id, "Old", now.AddDate(0, 0, -20))
+	doneTask(t, app, work.Id, "Recent", now.AddDate(0, 0, -3))
+	doneTask(t, app, home.Id, "No policy", now.AddDate(0, 0, -100))
+	newRecord(t, app, "tasks", map[string]any{"title": "Open", "board": work.Id, "column": "todo"})
+
+	results, err := Run(app, RunOptions{Now: now, Source: "cli", DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, work.Id, results[0].Board.Id)
+	assert.Equal(t, 14, results[0].Days)
+	require.Len(t, results[0].Tasks, 1)
+	assert.Equal(t, old.Id, results[0].Tasks[0].Id)
+	assert.Empty(t, titles(t, app, Archived), "a dry run archives nothing")
+
+	results, err = Run(app, RunOptions{
+		Now:    now,
+		Source: "server",
+		Batch:  undo.NewBatch("auto-archive", AutoActor),
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"Old"}, titles(t, app, Archived))
+
+	archived, err := app.FindRecordById("tasks", old.Id)
+	require.NoError(t, err)
+	assert.Equal(t, AutoActor, archived.GetString("archived_by"))
+
+	// Archived tasks are not archived again
+	results, err = Run(app, RunOptions{Now: now, Source: "server"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestModeIncludes(t *testing.T) {
+	assert.True(t, Active.Includes(false))
+	assert.False(t, Active.Includes(true))
+	assert.True(t, All.Includes(true))
+	assert.True(t, All.Includes(false))
+	assert.True(t, Archived.Includes(true))
+	assert.False(t, Archived.Includes(false))
+	assert.Equal(t, Archived, ModeFor(true, true))
+	assert.Equal(t, All, ModeFor(true, false))
+}
+
+func TestFilter_UnmigratedCollection(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+	)
+	newRecord(t, app, "tasks", map[string]any{"title": "A"})
+
+	assert.Equal(t, []string{"A"}, titles(t, app, Active))
+	assert.Empty(t, titles(t, app, Archived))
+}
@@ -0,0 +1,271 @@
+// Package attachment manages the files attached to tasks and comments.
+//
+// Files are kept in the attachments file field of a task or comment
+// record, so PocketBase stores them under the data directory and serves
+// them from /api/files. Each stored file gets a unique name with a random
+// suffix ("report_k3j2h1g0f9.pdf"); DisplayName recovers the name shown to
+// users ("report.pdf").
+package attachment
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+// Field is the file field of tasks and comments holding the attachments.
+const Field = "attachments"
+
+// ErrTooLarge is returned when a file exceeds the configured size limits.
+var ErrTooLarge = errors.New("attachment too large")
+
+// Info describes an attached file.
+type Info struct {
+	Name        string `json:"name"`              // Stored file name
+	DisplayName string `json:"display_name"`      // Name without the random suffix
+	Size        int64  `json:"size"`              // Size in bytes (0 if unknown)
+	Comment     string `json:"comment,omitempty"` // ID of the comment holding the file, "" for the task
+
+	// Record is the task or comment holding the file
+	Record *core.Record `json:"-"`
+}
+
+// storedNamePattern matches the random suffix PocketBase adds to stored
+// file names, before the (up to double) extension.
+var storedNamePattern = regexp.MustCompile(`^(.+)_[a-z0-9]{10}((?:\.[^._]+){0,2})$`)
+
+// Supported reports whether a task or comment collection has the
+// attachments field.
+func Supported(record *core.Record) bool {
+	return record.Collection().Fields.GetByName(Field) != nil
+}
+
+// Names returns the stored names of the saved files of a record. Files
+// added but not yet saved are left out.
+func Names(record *core.Record) []string {
+	var names []string
+	switch value := record.GetRaw(Field).(type) {
+	case string:
+		if value != "" {
+			names = append(names, value)
+		}
+	case []string:
+		names = append(names, value...)
+	case []any:
+		for _, v := range value {
+			if name, ok := v.(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// DisplayName returns a stored file name without its random suffix.
+func DisplayName(name string) string {
+	if m := storedNamePattern.FindStringSubmatch(name); m != nil {
+		return m[1] + m[2]
+	}
+	return name
+}
+
+// Key returns the storage key of a file of a record.
+func Key(record *core.Record, name string) string {
+	return record.BaseFilesPath() + "/" + name
+}
+
+// ForRecord returns the files attached to a task or comment.
+func ForRecord(app core.App, record *core.Record) ([]Info, error) {
+	names := Names(record)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		return nil, err
+	}
+	defer fsys.Close()
+
+	comment := ""
+	if record.Collection().Name == "comments" {
+		comment = record.Id
+	}
+	infos := make([]Info, 0, len(names))
+	for _, name := range names {
+		info := Info{Name: name, DisplayName: DisplayName(name), Comment: comment, Record: record}
+		if attrs, err := fsys.Attributes(Key(record, name)); err == nil {
+			info.Size = attrs.Size
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// ForTask returns the files attached to a task followed by the files
+// attached to its comments, oldest comment first.
+func ForTask(app core.App, task *core.Record) ([]Info, error) {
+	var infos []Info
+	if Supported(task) {
+		taskFiles, err := ForRecord(app, task)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, taskFiles...)
+	}
+
+	comments, err := app.FindRecordsByFilter("comments", "task = {:task}", "+created", 0, 0,
+		dbx.Params{"task": task.Id})
+	if err != nil {
+		return infos, nil // No comments collection
+	}
+	for _, comment := range comments {
+		if !Supported(comment) {
+			break
+		}
+		files, err := ForRecord(app, comment)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, files...)
+	}
+	return infos, nil
+}
+
+// TotalSize returns the combined size of files.
+func TotalSize(infos []Info) int64 {
+	var total int64
+	for _, info := range infos {
+		total += info.Size
+	}
+	return total
+}
+
+// Find returns the index of the file a reference names: a 1-based number,
+// a stored or display name, or a case-insensitive part of a single
+// display name.
+func Find(infos []Info, ref string) (int, error) {
+	ref = strings.TrimSpace(ref)
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(infos) {
+			return -1, fmt.Errorf("no attachment %d (the task has %d)", n, len(infos))
+		}
+		return n - 1, nil
+	}
+
+	for i, info := range infos {
+		if info.Name == ref {
+			return i, nil
+		}
+	}
+	match := -1
+	for i, info := range infos {
+		if info.DisplayName != ref {
+			continue
+		}
+		if match >= 0 {
+			return -1, fmt.Errorf("attachment %q is ambiguous, use its number", ref)
+		}
+		match = i
+	}
+	if match >= 0 {
+		return match, nil
+	}
+
+	lower := strings.ToLower(ref)
+	for i, info := range infos {
+		if !strings.Contains(strings.ToLower(info.DisplayName), lower) {
+			continue
+		}
+		if match >= 0 {
+			return -1, fmt.Errorf("attachment %q is ambiguous, use its number", ref)
+		}
+		match = i
+	}
+	if match < 0 {
+		return -1, fmt.Errorf("no attachment matches %q", ref)
+	}
+	return match, nil
+}
+
+// Open returns a reader for the content of an attached file.
+func Open(app core.App, info Info) (io.ReadCloser, error) {
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		return nil, err
+	}
+	reader, err := fsys.GetReader(Key(info.Record, info.Name))
+	if err != nil {
+		fsys.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", info.DisplayName, err)
+	}
+	return &fileReader{ReadCloser: reader, fsys: fsys}, nil
+}
+
+// fileReader closes the filesystem along with the file.
+type fileReader struct {
+	io.ReadCloser
+	fsys *filesystem.System
+}
+
+func (r *fileReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.fsys.Close()
+	return err
+}
+
+// CheckLimits returns ErrTooLarge when a file about to be saved with a
+// task or comment is larger than maxFile, or when the record's files
+// would add up to more than maxTotal bytes.
+func CheckLimits(app core.App, record *core.Record, maxFile, maxTotal int64) error {
+	if !Supported(record) {
+		return nil
+	}
+	added := record.GetUnsavedFiles(Field)
+	if len(added) == 0 {
+		return nil
+	}
+
+	var total int64
+	for _, file := range added {
+		if file.Size > maxFile {
+			return fmt.Errorf("%w: %s is %s, the limit is %s",
+				ErrTooLarge, file.OriginalName, FormatSize(file.Size), FormatSize(maxFile))
+		}
+		total += file.Size
+	}
+
+	if !record.IsNew() {
+		kept, err := ForRecord(app, record)
+		if err != nil {
+			return err
+		}
+		total += TotalSize(kept)
+	}
+	if total > maxTotal {
+		return fmt.Errorf("%w: attachments would total %s, the limit is %s",
+			ErrTooLarge, FormatSize(total), FormatSize(maxTotal))
+	}
+	return nil
+}
+
+// FormatSize formats a size in bytes for display, e.g. "1.5 MB".
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
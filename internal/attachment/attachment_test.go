@@ -0,0 +1,118 @@
+package attachment
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+func TestDisplayName(t *testing.T) {
+	assert.Equal(t, "report.pdf", DisplayName("report_k3j2h1g0f9.pdf"))
+	assert.Equal(t, "logs.tar.gz", DisplayName("logs_abcdefghij.tar.gz"))
+	assert.Equal(t, "build_log", DisplayName("build_log_0123456789"))
+	assert.Equal(t, "plain.txt", DisplayName("plain.txt"), "names without a suffix are kept")
+}
+
+func TestFind(t *testing.T) {
+	infos := []Info{
+		{Name: "report_aaaaaaaaaa.pdf", DisplayName: "report.pdf"},
+		{Name: "report_bbbbbbbbbb.pdf", DisplayName: "report.pdf"},
+		{Name: "screenshot_cccccccccc.png", DisplayName: "screenshot.png"},
+	}
+
+	i, err := Find(infos, "3")
+	require.NoError(t, err)
+	assert.Equal(t, 2, i)
+	i, err = Find(infos, "report_bbbbbbbbbb.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, 1, i)
+	i, err = Find(infos, "SCREEN")
+	require.NoError(t, err)
+	assert.Equal(t, 2, i)
+
+	_, err = Find(infos, "report.pdf")
+	assert.ErrorContains(t, err, "ambiguous")
+	_, err = Find(infos, "0")
+	assert.ErrorContains(t, err, "no attachment 0")
+	_, err = Find(infos, "notes")
+	assert.ErrorContains(t, err, "no attachment matches")
+}
+
+func TestForTaskAndLimits(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title"},
+		&core.FileField{Name: Field, MaxSelect: 10, MaxSize: 1 << 20},
+	)
+	comments := testutil.CreateTestCollection(t, app, "comments",
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "content"},
+		&core.FileField{Name: Field, MaxSelect: 10, MaxSize: 1 << 20},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+
+	task := core.NewRecord(tasks)
+	task.Set("title", "Task")
+	file, err := filesystem.NewFileFromBytes([]byte("hello world"), "notes.txt")
+	require.NoError(t, err)
+	task.Set(Field+"+", file)
+	require.NoError(t, CheckLimits(app, task, 100, 100))
+	require.NoError(t, app.Save(task))
+
+	comment := core.NewRecord(comments)
+	comment.Set("task", task.Id)
+	comment.Set("content", "See the log")
+	file, err = filesystem.NewFileFromBytes([]byte("log line"), "build.log")
+	require.NoError(t, err)
+	comment.Set(Field+"+", file)
+	require.NoError(t, app.Save(comment))
+
+	infos, err := ForTask(app, task)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "notes.txt", infos[0].DisplayName)
+	assert.Equal(t, int64(11), infos[0].Size)
+	assert.Empty(t, infos[0].Comment)
+	assert.Equal(t, "build.log", infos[1].DisplayName)
+	assert.Equal(t, comment.Id, infos[1].Comment)
+	assert.Equal(t, int64(19), TotalSize(infos))
+
+	reader, err := Open(app, infos[0])
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "hello world", string(content))
+
+	// A single file over the limit
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	file, err = filesystem.NewFileFromBytes([]byte("twelve bytes"), "more.txt")
+	require.NoError(t, err)
+	task.Set(Field+"+", file)
+	err = CheckLimits(app, task, 10, 100)
+	assert.True(t, errors.Is(err, ErrTooLarge))
+	assert.ErrorContains(t, err, "more.txt is 12 B")
+
+	// The total counts the files already attached
+	require.NoError(t, CheckLimits(app, task, 100, 23))
+	err = CheckLimits(app, task, 100, 22)
+	assert.ErrorContains(t, err, "would total 23 B")
+
+	// Removed files do not count
+	task.Set(Field+"-", infos[0].Name)
+	require.NoError(t, CheckLimits(app, task, 100, 12))
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", FormatSize(512))
+	assert.Equal(t, "1.5 KB", FormatSize(1536))
+	assert.Equal(t, "10.0 MB", FormatSize(10<<20))
+}
@@ -0,0 +1,354 @@
+// Package auth implements opt-in authentication for the HTTP API.
+//
+// By default every collection is public, which suits a single-user tool
+// on localhost. When auth is enabled, the API rules of all app collections
+// require an authenticated record from the users collection. Accounts carry
+// a scope (read or write) and an optional board restriction, which the
+// rules enforce. API tokens are long-lived static auth tokens issued for
+// users records of kind "token".
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// UsersCollection is the PocketBase auth collection holding accounts and tokens.
+const UsersCollection = "users"
+
+// Account kinds.
+const (
+	KindUser  = "user"
+	KindToken = "token"
+)
+
+// Scopes limit what an account may do.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// BoardScopePrefix prefixes a board restriction in a scope list (board:WRK).
+const BoardScopePrefix = "board:"
+
+// DefaultTokenDuration is how long API tokens stay valid unless overridden.
+const DefaultTokenDuration = 365 * 24 * time.Hour
+
+// tokenEmailDomain is used for the synthetic email of token accounts,
+// which PocketBase requires on every auth record.
+const tokenEmailDomain = "tokens.egenskriven.local"
+
+// ValidScopes lists the accepted access scopes.
+var ValidScopes = []string{ScopeRead, ScopeWrite}
+
+// ErrTokenNotFound is returned when no token with the given name exists.
+var ErrTokenNotFound = errors.New("token not found")
+
+// boardPaths maps each protected collection to the rule expression that
+// resolves the board a record belongs to.
+var boardPaths = map[string]string{
+	"boards":         "id",
+	"tasks":          "board",
+	"epics":          "board",
+	"sprints":        "board",
+	"views":          "board",
+	"proposals":      "board",
+	"task_events":    "board",
+	"operations":     "board",
+	"task_templates": "board",
+	"comments":       "task.board",
+	"sessions":       "task.board",
+	"time_entries":   "task.board",
+	"task_links":     "source.board",
+}
+
+// readOnlyCollections are written only by the server's hooks; the API
+// may read them but never change them, whether auth is enabled or not.
+var readOnlyCollections = map[string]bool{
+	"task_events": true,
+	"operations":  true,
+}
+
+// Rules holds the API rules applied to a collection.
+type Rules struct {
+	List   *string
+	View   *string
+	Create *string
+	Update *string
+	Delete *string
+}
+
+// Scope is a parsed token scope.
+type Scope struct {
+	Access   string // ScopeRead or ScopeWrite
+	BoardRef string // Board name or prefix, empty for all boards
+}
+
+// String formats the scope the way it is accepted on the command line.
+func (s Scope) String() string {
+	if s.BoardRef == "" {
+		return s.Access
+	}
+	return s.Access + "," + BoardScopePrefix + s.BoardRef
+}
+
+// ParseScope parses scope values such as "read", "write", "board:WRK" or
+// "read,board:WRK". A board restriction without an access level grants
+// write access to that board only.
+func ParseScope(values []string) (Scope, error) {
+	scope := Scope{}
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			switch {
+			case part == "":
+				continue
+			case part == ScopeRead || part == ScopeWrite:
+				if scope.Access != "" && scope.Access != part {
+					return Scope{}, fmt.Errorf("conflicting scopes %q and %q", scope.Access, part)
+				}
+				scope.Access = part
+			case strings.HasPrefix(part, BoardScopePrefix):
+				ref := strings.TrimPrefix(part, BoardScopePrefix)
+				if ref == "" {
+					return Scope{}, fmt.Errorf("missing board in scope %q", part)
+				}
+				if scope.BoardRef != "" && scope.BoardRef != ref {
+					return Scope{}, fmt.Errorf("a token can only be restricted to one board")
+				}
+				scope.BoardRef = ref
+			default:
+				return Scope{}, fmt.Errorf("invalid scope %q (use read, write, or board:<prefix>)", part)
+			}
+		}
+	}
+
+	if scope.Access == "" {
+		if scope.BoardRef == "" {
+			return Scope{}, fmt.Errorf("scope is required (read, write, or board:<prefix>)")
+		}
+		scope.Access = ScopeWrite
+	}
+	return scope, nil
+}
+
+// RulesFor returns the API rules for a protected collection.
+// With auth disabled every rule is public (empty string), except the write
+// rules of read-only collections, which are always locked (nil).
+func RulesFor(collection string, enabled bool) Rules {
+	rules := rulesFor(collection, enabled)
+	if readOnlyCollections[collection] {
+		rules.Create, rules.Update, rules.Delete = nil, nil, nil
+	}
+	return rules
+}
+
+func rulesFor(collection string, enabled bool) Rules {
+	if !enabled {
+		return Rules{
+			List:   ptr(""),
+			View:   ptr(""),
+			Create: ptr(""),
+			Update: ptr(""),
+			Delete: ptr(""),
+		}
+	}
+
+	path := boardPaths[collection]
+	read := `@request.auth.id != "" && (@request.auth.board = "" || ` + path + ` = @request.auth.board)`
+	write := read + ` && @request.auth.scope != "` + ScopeRead + `"`
+
+	create := write
+	if collection == "boards" {
+		// Board-restricted accounts cannot create new boards
+		create = `@request.auth.id != "" && @request.auth.board = "" && @request.auth.scope != "` + ScopeRead + `"`
+	}
+
+	return Rules{
+		List:   ptr(read),
+		View:   ptr(read),
+		Create: ptr(create),
+		Update: ptr(write),
+		Delete: ptr(write),
+	}
+}
+
+// Collections returns the names of the collections protected by auth.
+func Collections() []string {
+	names := make([]string, 0, len(boardPaths))
+	for name := range boardPaths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BoardID resolves the board a record of a protected collection belongs
+// to, following the same path as the API rules (task.board for comments,
+// source.board for task links).
+func BoardID(app core.App, record *core.Record) (string, error) {
+	path, ok := boardPaths[record.Collection().Name]
+	if !ok {
+		return "", fmt.Errorf("collection %q does not belong to a board", record.Collection().Name)
+	}
+
+	parts := strings.Split(path, ".")
+	for _, name := range parts[:len(parts)-1] {
+		field, ok := record.Collection().Fields.GetByName(name).(*core.RelationField)
+		if !ok {
+			return "", fmt.Errorf("%s.%s is not a relation", record.Collection().Name, name)
+		}
+		next, err := app.FindRecordById(field.CollectionId, record.GetString(name))
+		if err != nil {
+			return "", err
+		}
+		record = next
+	}
+
+	last := parts[len(parts)-1]
+	if last == "id" {
+		return record.Id, nil
+	}
+	return record.GetString(last), nil
+}
+
+// Enabled reports whether auth rules are currently applied.
+func Enabled(app core.App) bool {
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	if err != nil {
+		return false
+	}
+	return tasks.ListRule != nil && *tasks.ListRule != ""
+}
+
+// ApplyRules sets the API rules of all protected collections for the
+// given auth mode. Collections that do not exist yet are skipped.
+func ApplyRules(app core.App, enabled bool) error {
+	return app.RunInTransaction(func(txApp core.App) error {
+		for _, name := range Collections() {
+			collection, err := txApp.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+
+			rules := RulesFor(name, enabled)
+			collection.ListRule = rules.List
+			collection.ViewRule = rules.View
+			collection.CreateRule = rules.Create
+			collection.UpdateRule = rules.Update
+			collection.DeleteRule = rules.Delete
+
+			if err := txApp.Save(collection); err != nil {
+				return fmt.Errorf("failed to update rules for %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// SyncRules re-applies the auth rules when auth is enabled, so that
+// collections added by later migrations are protected as well.
+func SyncRules(app core.App) error {
+	if !Enabled(app) {
+		return nil
+	}
+	return ApplyRules(app, true)
+}
+
+// CreateToken creates a token account and issues a static auth token for it.
+// boardID may be empty for tokens valid on all boards.
+func CreateToken(app core.App, name, access, boardID string, duration time.Duration) (*core.Record, string, error) {
+	if name == "" {
+		return nil, "", fmt.Errorf("token name is required")
+	}
+	if existing, _ := FindToken(app, name); existing != nil {
+		return nil, "", fmt.Errorf("token %q already exists", name)
+	}
+
+	users, err := app.FindCollectionByNameOrId(UsersCollection)
+	if err != nil {
+		return nil, "", fmt.Errorf("users collection not found: %w", err)
+	}
+
+	record := core.NewRecord(users)
+	record.Set("name", name)
+	record.Set("kind", KindToken)
+	record.Set("scope", access)
+	record.Set("board", boardID)
+	record.SetEmail(tokenEmail(name))
+	record.SetVerified(true)
+	record.SetRandomPassword()
+
+	if err := app.Save(record); err != nil {
+		return nil, "", fmt.Errorf("failed to save token: %w", err)
+	}
+
+	if duration <= 0 {
+		duration = DefaultTokenDuration
+	}
+	token, err := record.NewStaticAuthToken(duration)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to issue token: %w", err)
+	}
+
+	return record, token, nil
+}
+
+// Tokens returns all token accounts ordered by name.
+func Tokens(app core.App) ([]*core.Record, error) {
+	records, err := app.FindAllRecords(UsersCollection,
+		dbx.NewExp("kind = {:kind}", dbx.Params{"kind": KindToken}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].GetString("name") < records[j].GetString("name")
+	})
+	return records, nil
+}
+
+// FindToken returns the token account with the given name.
+func FindToken(app core.App, name string) (*core.Record, error) {
+	records, err := app.FindAllRecords(UsersCollection,
+		dbx.NewExp("kind = {:kind} AND name = {:name}",
+			dbx.Params{"kind": KindToken, "name": name}),
+	)
+	if err != nil || len(records) == 0 {
+		return nil, ErrTokenNotFound
+	}
+	return records[0], nil
+}
+
+// RevokeToken deletes a token account, invalidating every token issued for it.
+func RevokeToken(app core.App, name string) error {
+	record, err := FindToken(app, name)
+	if err != nil {
+		return err
+	}
+	return app.Delete(record)
+}
+
+// tokenEmail builds the synthetic email address for a token account.
+func tokenEmail(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return "token-" + strings.Trim(b.String(), "-.") + "@" + tokenEmailDomain
+}
+
+func ptr(s string) *string {
+	return &s
+}
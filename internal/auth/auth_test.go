@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+// setupAuthCollections creates a boards and tasks collection and adds the
+// token fields to the users collection, mirroring the app migrations.
+func setupAuthCollections(t *testing.T, app *pocketbase.PocketBase) *core.Record {
+	t.Helper()
+
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "prefix"},
+	)
+	testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title"},
+		&core.RelationField{Name: "board", CollectionId: boards.Id, MaxSelect: 1},
+	)
+
+	users, err := app.FindCollectionByNameOrId(UsersCollection)
+	require.NoError(t, err)
+	users.Fields.Add(&core.SelectField{Name: "kind", Values: []string{KindUser, KindToken}})
+	users.Fields.Add(&core.SelectField{Name: "scope", Values: ValidScopes})
+	users.Fields.Add(&core.RelationField{Name: "board", CollectionId: boards.Id, MaxSelect: 1})
+	require.NoError(t, app.Save(users))
+
+	board := core.NewRecord(boards)
+	board.Set("name", "Work")
+	board.Set("prefix", "WRK")
+	require.NoError(t, app.Save(board))
+	return board
+}
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    Scope
+		wantErr bool
+	}{
+		{"read", []string{"read"}, Scope{Access: ScopeRead}, false},
+		{"write", []string{"write"}, Scope{Access: ScopeWrite}, false},
+		{"board implies write", []string{"board:WRK"}, Scope{Access: ScopeWrite, BoardRef: "WRK"}, false},
+		{"read on board", []string{"read,board:WRK"}, Scope{Access: ScopeRead, BoardRef: "WRK"}, false},
+		{"repeated flags", []string{"read", "board:WRK"}, Scope{Access: ScopeRead, BoardRef: "WRK"}, false},
+		{"empty", nil, Scope{}, true},
+		{"unknown", []string{"admin"}, Scope{}, true},
+		{"conflicting access", []string{"read,write"}, Scope{}, true},
+		{"two boards", []string{"board:WRK,board:OTH"}, Scope{}, true},
+		{"missing board", []string{"board:"}, Scope{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScope(tt.values)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRulesFor(t *testing.T) {
+	public := RulesFor("tasks", false)
+	assert.Equal(t, "", *public.List)
+	assert.Equal(t, "", *public.Delete)
+
+	rules := RulesFor("comments", true)
+	assert.Contains(t, *rules.List, `@request.auth.id != ""`)
+	assert.Contains(t, *rules.List, "task.board = @request.auth.board")
+	assert.NotContains(t, *rules.List, "scope")
+	assert.Contains(t, *rules.Update, `@request.auth.scope != "read"`)
+
+	// Board-restricted accounts cannot create boards
+	boards := RulesFor("boards", true)
+	assert.Contains(t, *boards.Create, `@request.auth.board = ""`)
+
+	// The event log and the undo log are readable but never written
+	// through the API
+	for _, name := range []string{"task_events", "operations"} {
+		for _, enabled := range []bool{false, true} {
+			rules := RulesFor(name, enabled)
+			require.NotNil(t, rules.List, name)
+			require.NotNil(t, rules.View, name)
+			assert.Nil(t, rules.Create, name)
+			assert.Nil(t, rules.Update, name)
+			assert.Nil(t, rules.Delete, name)
+		}
+	}
+	assert.Contains(t, *RulesFor("task_events", true).List, "board = @request.auth.board")
+}
+
+func TestApplyRules(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	setupAuthCollections(t, app)
+
+	assert.False(t, Enabled(app))
+
+	require.NoError(t, ApplyRules(app, true))
+	assert.True(t, Enabled(app))
+
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	assert.Equal(t, *RulesFor("tasks", true).List, *tasks.ListRule)
+
+	// Collections that do not exist are skipped
+	_, err = app.FindCollectionByNameOrId("sprints")
+	assert.Error(t, err)
+
+	require.NoError(t, ApplyRules(app, false))
+	assert.False(t, Enabled(app))
+}
+
+func TestTokens_CreateListRevoke(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	board := setupAuthCollections(t, app)
+
+	record, token, err := CreateToken(app, "ci", ScopeWrite, board.Id, 0)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, KindToken, record.GetString("kind"))
+	assert.Equal(t, board.Id, record.GetString("board"))
+
+	_, _, err = CreateToken(app, "dashboard", ScopeRead, "", 0)
+	require.NoError(t, err)
+
+	// Token names are unique
+	_, _, err = CreateToken(app, "ci", ScopeRead, "", 0)
+	assert.Error(t, err)
+
+	// The issued token authenticates as the token account
+	authRecord, err := app.FindAuthRecordByToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, record.Id, authRecord.Id)
+
+	tokens, err := Tokens(app)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "ci", tokens[0].GetString("name"))
+
+	require.NoError(t, RevokeToken(app, "ci"))
+	_, err = app.FindAuthRecordByToken(token)
+	assert.Error(t, err, "revoked tokens no longer authenticate")
+
+	assert.ErrorIs(t, RevokeToken(app, "ci"), ErrTokenNotFound)
+}
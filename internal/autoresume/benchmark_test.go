@@ -41,7 +41,6 @@ func setupBenchmarkApp(b *testing.B) *pocketbase.PocketBase {
 		tasks.Fields.Add(&core.TextField{Name: "column"})
 		tasks.Fields.Add(&core.TextField{Name: "board"})
 		tasks.Fields.Add(&core.JSONField{Name: "agent_session"})
-		tasks.Fields.Add(&core.JSONField{Name: "history"})
 		tasks.Fields.Add(&core.NumberField{Name: "seq"})
 		tasks.Fields.Add(&core.AutodateField{
 			Name:     "created",
@@ -109,7 +108,6 @@ func createBenchmarkTask(b *testing.B, app *pocketbase.PocketBase, boardId, colu
 	record.Set("board", boardId)
 	record.Set("column", column)
 	record.Set("seq", 1)
-	record.Set("history", []map[string]any{})
 
 	if withSession {
 		record.Set("agent_session", map[string]any{
@@ -308,40 +306,6 @@ func BenchmarkHasAgentMention_JSONParsing(b *testing.B) {
 	}
 }
 
-// BenchmarkEnsureHistorySlice measures history slice conversion.
-func BenchmarkEnsureHistorySlice(b *testing.B) {
-	testCases := []struct {
-		name  string
-		input any
-	}{
-		{"nil", nil},
-		{"empty", []any{}},
-		{"single_entry", []any{map[string]any{"action": "test"}}},
-		{"ten_entries", func() []any {
-			result := make([]any, 10)
-			for i := range result {
-				result[i] = map[string]any{"action": fmt.Sprintf("action_%d", i)}
-			}
-			return result
-		}()},
-		{"fifty_entries", func() []any {
-			result := make([]any, 50)
-			for i := range result {
-				result[i] = map[string]any{"action": fmt.Sprintf("action_%d", i)}
-			}
-			return result
-		}()},
-	}
-
-	for _, tc := range testCases {
-		b.Run(tc.name, func(b *testing.B) {
-			for i := 0; i < b.N; i++ {
-				ensureHistorySlice(tc.input)
-			}
-		})
-	}
-}
-
 // BenchmarkFetchComments measures comment fetching performance.
 func BenchmarkFetchComments(b *testing.B) {
 	commentCounts := []int{1, 10, 50, 100}
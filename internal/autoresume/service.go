@@ -13,12 +13,12 @@ import (
 	"fmt"
 	"log"
 	"os/exec"
-	"time"
 
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 
 	"github.com/ramtinJ95/EgenSkriven/internal/resume"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
 )
 
 // Service handles auto-resume logic for AI agent sessions.
@@ -151,24 +151,15 @@ func (s *Service) triggerResume(task *core.Record, triggerComment *core.Record)
 func (s *Service) updateTaskForResume(task *core.Record, triggerComment *core.Record) error {
 	task.Set("column", "in_progress")
 
-	// Add history entry
-	history := ensureHistorySlice(task.Get("history"))
-	history = append(history, map[string]any{
-		"timestamp":    time.Now().UTC().Format(time.RFC3339),
-		"action":       "auto_resumed",
-		"actor":        "system",
-		"actor_detail": "auto-resume",
-		"changes": map[string]any{
-			"column": map[string]any{
-				"from": "need_input",
-				"to":   "in_progress",
-			},
-		},
-		"metadata": map[string]any{
-			"trigger_comment": triggerComment.Id,
+	// Log the resume with the comment that triggered it
+	taskevent.Add(task, "auto_resumed", "system", "auto-resume", map[string]any{
+		"column": map[string]any{
+			"from": "need_input",
+			"to":   "in_progress",
 		},
+	}, map[string]any{
+		"trigger_comment": triggerComment.Id,
 	})
-	task.Set("history", history)
 
 	return s.app.Save(task)
 }
@@ -284,20 +275,3 @@ func hasAgentMention(comment *core.Record) bool {
 	}
 	return false
 }
-
-// ensureHistorySlice converts history to proper type.
-func ensureHistorySlice(history any) []map[string]any {
-	if history == nil {
-		return []map[string]any{}
-	}
-	if slice, ok := history.([]any); ok {
-		result := make([]map[string]any, 0, len(slice))
-		for _, item := range slice {
-			if m, ok := item.(map[string]any); ok {
-				result = append(result, m)
-			}
-		}
-		return result
-	}
-	return []map[string]any{}
-}
@@ -194,29 +194,6 @@ func TestHasAgentMention(t *testing.T) {
 	}
 }
 
-// TestEnsureHistorySlice tests the ensureHistorySlice helper function.
-func TestEnsureHistorySlice(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    any
-		expected int // length of result
-	}{
-		{"nil input", nil, 0},
-		{"empty slice", []any{}, 0},
-		{"valid slice", []any{map[string]any{"action": "test"}}, 1},
-		{"invalid type", "string", 0},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := ensureHistorySlice(tt.input)
-			if len(result) != tt.expected {
-				t.Errorf("ensureHistorySlice() returned %d items, want %d", len(result), tt.expected)
-			}
-		})
-	}
-}
-
 // --- Test Helpers ---
 
 // setupTestAppWithCollections creates a test app and ensures all required
@@ -247,7 +224,6 @@ func setupTestAppWithCollections(t *testing.T) *pocketbase.PocketBase {
 		tasks.Fields.Add(&core.TextField{Name: "column"})
 		tasks.Fields.Add(&core.TextField{Name: "board"})
 		tasks.Fields.Add(&core.JSONField{Name: "agent_session"})
-		tasks.Fields.Add(&core.JSONField{Name: "history"})
 		tasks.Fields.Add(&core.NumberField{Name: "seq"})
 		if err := app.Save(tasks); err != nil {
 			t.Fatalf("failed to create tasks collection: %v", err)
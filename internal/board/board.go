@@ -7,6 +7,8 @@ import (
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
 )
 
 // DefaultColumns are used when creating a board without custom columns
@@ -104,49 +106,71 @@ func Create(app *pocketbase.PocketBase, input CreateInput) (*Board, error) {
 //   - "WRK" (prefix)
 //   - "wrk" (prefix, case-insensitive)
 func GetByNameOrPrefix(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
+	if strings.TrimSpace(ref) == "" {
+		return nil, fmt.Errorf("board reference is required")
+	}
+
+	// Boards in the trash are not matched
+	boards, err := GetAll(app)
+	if err != nil {
+		return nil, err
+	}
+	return Match(boards, ref)
+}
+
+// Match finds the board a reference names among boards, the way
+// GetByNameOrPrefix does: by exact ID, then prefix, then name (both
+// case-insensitive), then a unique part of the name. It lets clients
+// without a database, such as the remote CLI, resolve board references
+// from the boards the server lists.
+func Match(boards []*core.Record, ref string) (*core.Record, error) {
 	ref = strings.TrimSpace(ref)
 	if ref == "" {
 		return nil, fmt.Errorf("board reference is required")
 	}
+	lower := strings.ToLower(ref)
 
-	// Try exact ID match first
-	if record, err := app.FindRecordById("boards", ref); err == nil {
-		return record, nil
+	matching := func(match func(*core.Record) bool) []*core.Record {
+		var found []*core.Record
+		for _, b := range boards {
+			if match(b) {
+				found = append(found, b)
+			}
+		}
+		return found
 	}
 
-	// Try case-insensitive prefix match
-	records, err := app.FindAllRecords("boards",
-		dbx.NewExp("LOWER(prefix) = {:prefix}", dbx.Params{"prefix": strings.ToLower(ref)}),
-	)
-	if err == nil && len(records) == 1 {
-		return records[0], nil
+	if found := matching(func(b *core.Record) bool { return b.Id == ref }); len(found) == 1 {
+		return found[0], nil
 	}
-
-	// Try case-insensitive name match
-	records, err = app.FindAllRecords("boards",
-		dbx.NewExp("LOWER(name) = {:name}", dbx.Params{"name": strings.ToLower(ref)}),
-	)
-	if err == nil && len(records) == 1 {
-		return records[0], nil
+	if found := matching(func(b *core.Record) bool {
+		return strings.ToLower(b.GetString("prefix")) == lower
+	}); len(found) == 1 {
+		return found[0], nil
+	}
+	if found := matching(func(b *core.Record) bool {
+		return strings.ToLower(b.GetString("name")) == lower
+	}); len(found) == 1 {
+		return found[0], nil
 	}
 
 	// Try partial name match (for convenience)
-	records, err = app.FindAllRecords("boards",
-		dbx.NewExp("LOWER(name) LIKE {:name}", dbx.Params{"name": "%" + strings.ToLower(ref) + "%"}),
-	)
-	if err == nil && len(records) == 1 {
-		return records[0], nil
+	found := matching(func(b *core.Record) bool {
+		return strings.Contains(strings.ToLower(b.GetString("name")), lower)
+	})
+	if len(found) == 1 {
+		return found[0], nil
 	}
-	if len(records) > 1 {
+	if len(found) > 1 {
 		return nil, fmt.Errorf("ambiguous board reference '%s' matches multiple boards", ref)
 	}
 
 	return nil, fmt.Errorf("board not found: %s", ref)
 }
 
-// GetAll returns all boards
+// GetAll returns all boards not in the trash
 func GetAll(app *pocketbase.PocketBase) ([]*core.Record, error) {
-	return app.FindAllRecords("boards", dbx.NewExp("1=1"))
+	return app.FindAllRecords("boards", trash.Exclude(app, "boards"))
 }
 
 // GetNextSequence returns the next sequence number for a board.
@@ -236,6 +260,20 @@ func FormatDisplayID(prefix string, seq int) string {
 	return fmt.Sprintf("%s-%d", prefix, seq)
 }
 
+// TaskDisplayID returns the display ID of a task (e.g. "WRK-123"), or the
+// start of its record ID when it has no board or sequence.
+func TaskDisplayID(app core.App, task *core.Record) string {
+	if seq := task.GetInt("seq"); seq > 0 {
+		if board, err := app.FindRecordById("boards", task.GetString("board")); err == nil {
+			return FormatDisplayID(board.GetString("prefix"), seq)
+		}
+	}
+	if len(task.Id) > 8 {
+		return task.Id[:8]
+	}
+	return task.Id
+}
+
 // ParseDisplayID extracts the prefix and sequence from a display ID
 //
 // Example: ParseDisplayID("WRK-123") returns ("WRK", 123, nil)
@@ -301,10 +339,12 @@ func RecordToBoard(record *core.Record) *Board {
 	}
 }
 
-// Delete removes a board and optionally its tasks
+// Delete permanently removes a board and optionally its tasks
 //
 // If deleteTasks is false, tasks are orphaned (board field cleared).
 // If deleteTasks is true, all tasks in the board are deleted.
+//
+// The CLI moves boards to the trash instead (see trash.Board).
 func Delete(app *pocketbase.PocketBase, boardID string, deleteTasks bool) error {
 	board, err := app.FindRecordById("boards", boardID)
 	if err != nil {
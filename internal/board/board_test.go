@@ -211,6 +211,39 @@ func TestGetByNameOrPrefix_EmptyRefFails(t *testing.T) {
 	assert.Contains(t, err.Error(), "board reference is required")
 }
 
+func TestMatch(t *testing.T) {
+	newBoard := func(id, name, prefix string) *core.Record {
+		record := core.NewRecord(core.NewBaseCollection("boards"))
+		record.Id = id
+		record.Set("name", name)
+		record.Set("prefix", prefix)
+		return record
+	}
+	boards := []*core.Record{
+		newBoard("board000000001", "Work Tasks", "WRK"),
+		newBoard("board000000002", "Work Projects", "PRJ"),
+		newBoard("board000000003", "Home", "HOM"),
+	}
+
+	for ref, want := range map[string]string{
+		"board000000003": "Home",
+		"wrk":            "Work Tasks",
+		"home":           "Home",
+		"projects":       "Work Projects",
+	} {
+		found, err := Match(boards, ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, want, found.GetString("name"), ref)
+	}
+
+	_, err := Match(boards, "work")
+	assert.ErrorContains(t, err, "ambiguous")
+	_, err = Match(boards, "garden")
+	assert.ErrorContains(t, err, "board not found")
+	_, err = Match(boards, " ")
+	assert.ErrorContains(t, err, "board reference is required")
+}
+
 // Test GetAll function
 
 func TestGetAll_Empty(t *testing.T) {
@@ -612,7 +645,6 @@ func setupTasksCollection(t *testing.T, app *pocketbase.PocketBase) {
 		Values:   []string{"user", "agent", "cli"},
 	})
 	collection.Fields.Add(&core.TextField{Name: "created_by_agent"})
-	collection.Fields.Add(&core.JSONField{Name: "history"})
 	collection.Fields.Add(&core.TextField{Name: "board"}) // Board relation as text for simplicity
 	collection.Fields.Add(&core.NumberField{Name: "seq"})
 
@@ -638,7 +670,6 @@ func createTestTask(t *testing.T, app *pocketbase.PocketBase, title, boardID str
 	record.Set("labels", []string{})
 	record.Set("blocked_by", []string{})
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 	record.Set("board", boardID)
 	record.Set("seq", seq)
 
@@ -0,0 +1,101 @@
+package boardreport
+
+import (
+	"html/template"
+	"io"
+	"strings"
+)
+
+// htmlTemplate is a standalone page with inline styles, so the file can be
+// opened or published without other assets.
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"details": func(t Task, epicReport bool) string { return strings.Join(taskDetails(t, epicReport), " · ") },
+	"plural":  plural,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 56rem; margin: 2rem auto; padding: 0 1rem; color: #1f2328; line-height: 1.5; }
+h2 { border-bottom: 1px solid #d1d9e0; padding-bottom: .25rem; margin-top: 2rem; }
+table { border-collapse: collapse; }
+th, td { text-align: left; padding: .25rem .75rem; border-bottom: 1px solid #d1d9e0; }
+progress { width: 8rem; vertical-align: middle; }
+.id { font-family: ui-monospace, monospace; font-weight: 600; }
+.details, .meta { color: #59636e; font-size: .875rem; }
+.epic-swatch { display: inline-block; width: .75rem; height: .75rem; border-radius: 50%; margin-right: .375rem; }
+ul.checklist { list-style: none; padding-left: 1.25rem; }
+blockquote { margin: .5rem 0 .5rem 1.25rem; padding-left: .75rem; border-left: 3px solid #d1d9e0; white-space: pre-wrap; }
+.empty { color: #59636e; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- if .Epic}}
+<p class="meta">Epic on {{.Board}} ({{.Prefix}})</p>
+{{- if .Description}}
+<p style="white-space: pre-wrap">{{.Description}}</p>
+{{- end}}
+{{- end}}
+<p class="meta">{{plural .Total "task"}} · {{.Done}} done</p>
+{{- if .Epics}}
+{{- if .Epic}}
+{{- with index .Epics 0}}
+<p>Progress: <progress value="{{.Done}}" max="{{.Total}}"></progress> {{.Percent}}% ({{.Done}}/{{.Total}} done)</p>
+{{- end}}
+{{- else}}
+<h2>Epics</h2>
+<table>
+<tr><th>Epic</th><th>Progress</th><th>Done</th></tr>
+{{- range .Epics}}
+<tr><td>{{if .Color}}<span class="epic-swatch" style="background: {{.Color}}"></span>{{end}}{{.Title}}</td><td><progress value="{{.Done}}" max="{{.Total}}"></progress> {{.Percent}}%</td><td>{{.Done}}/{{.Total}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+{{- if .Since}}
+<h2>Changes since {{.Since}}</h2>
+{{- if .Changes}}
+<ul>
+{{- range .Changes}}
+<li><span class="meta">{{.Date}}</span> <span class="id">{{.Task}}</span> {{.Title}}: {{.Action}}{{if .Summary}} ({{.Summary}}){{end}} by {{.Actor}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p class="empty">No changes.</p>
+{{- end}}
+{{- end}}
+{{- $epicReport := .Epic}}
+{{- range .Columns}}
+<h2>{{.Name}} ({{len .Tasks}})</h2>
+{{- if .Tasks}}
+<ul>
+{{- range .Tasks}}
+<li><span class="id">{{.DisplayID}}</span> {{.Title}} <span class="details">{{details . $epicReport}}</span>
+{{- if .Checklist}}
+<ul class="checklist">
+{{- range .Checklist}}
+<li><input type="checkbox" disabled{{if .Done}} checked{{end}}> {{.Text}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- range .Comments}}
+<blockquote><strong>{{.Author}}</strong>{{if .Date}} <span class="meta">{{.Date}}</span>{{end}}
+{{.Content}}</blockquote>
+{{- end}}
+</li>
+{{- end}}
+</ul>
+{{- else}}
+<p class="empty">No tasks.</p>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML writes a report as a standalone HTML page.
+func HTML(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, r)
+}
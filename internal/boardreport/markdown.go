@@ -0,0 +1,172 @@
+package boardreport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// progressWidth is the number of cells of a progress bar.
+const progressWidth = 10
+
+// ProgressBar draws done out of total as a bar of block characters, e.g.
+// "███████░░░".
+func ProgressBar(done, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = (done*progressWidth + total/2) / total
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", progressWidth-filled)
+}
+
+// Markdown writes a report as a markdown document.
+func Markdown(w io.Writer, r *Report) error {
+	bw := bufio.NewWriter(w)
+	p := func(format string, args ...any) { fmt.Fprintf(bw, format, args...) }
+
+	p("# %s\n\n", mdEscape(r.Title))
+	if r.Epic {
+		p("Epic on %s (%s)\n\n", mdEscape(r.Board), r.Prefix)
+		if r.Description != "" {
+			p("%s\n\n", r.Description)
+		}
+	}
+	p("%s · %d done\n", plural(r.Total, "task"), r.Done)
+
+	if len(r.Epics) > 0 {
+		if r.Epic {
+			e := r.Epics[0]
+			p("\nProgress: %s %d%% (%d/%d done)\n", ProgressBar(e.Done, e.Total), e.Percent(), e.Done, e.Total)
+		} else {
+			p("\n## Epics\n\n")
+			p("| Epic | Progress | Done |\n")
+			p("| --- | --- | --- |\n")
+			for _, e := range r.Epics {
+				p("| %s | %s %d%% | %d/%d |\n",
+					mdCell(e.Title), ProgressBar(e.Done, e.Total), e.Percent(), e.Done, e.Total)
+			}
+		}
+	}
+
+	if r.Since != "" {
+		p("\n## Changes since %s\n\n", r.Since)
+		if len(r.Changes) == 0 {
+			p("_No changes._\n")
+		}
+		for _, c := range r.Changes {
+			p("- %s **%s** %s: %s", c.Date, c.Task, mdEscape(c.Title), c.Action)
+			if c.Summary != "" {
+				p(" (%s)", mdEscape(c.Summary))
+			}
+			p(" by %s\n", mdEscape(c.Actor))
+		}
+	}
+
+	for _, col := range r.Columns {
+		p("\n## %s (%d)\n\n", mdEscape(col.Name), len(col.Tasks))
+		if len(col.Tasks) == 0 {
+			p("_No tasks._\n")
+		}
+		for i, t := range col.Tasks {
+			p("- **%s** %s", t.DisplayID, mdEscape(t.Title))
+			if details := taskDetails(t, r.Epic); len(details) > 0 {
+				p(" · %s", mdEscape(strings.Join(details, " · ")))
+			}
+			p("\n")
+			for _, item := range t.Checklist {
+				box := " "
+				if item.Done {
+					box = "x"
+				}
+				p("  - [%s] %s\n", box, mdEscape(item.Text))
+			}
+			for _, c := range t.Comments {
+				p("\n  > **%s**", mdEscape(c.Author))
+				if c.Date != "" {
+					p(" · %s", c.Date)
+				}
+				p("\n  >\n")
+				for _, line := range strings.Split(c.Content, "\n") {
+					p("  > %s\n", strings.TrimRight(line, " "))
+				}
+			}
+			// A blank line ends the quote before the next task
+			if len(t.Comments) > 0 && i < len(col.Tasks)-1 {
+				p("\n")
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// taskDetails lists the properties shown after a task's title. The epic is
+// left out of epic reports.
+func taskDetails(t Task, epicReport bool) []string {
+	details := []string{t.Priority, t.Type}
+	if t.DueDate != "" {
+		details = append(details, "due "+t.DueDate)
+	}
+	if t.Epic != "" && !epicReport {
+		details = append(details, "epic "+t.Epic)
+	}
+	if t.Parent != "" {
+		details = append(details, "sub-task of "+t.Parent)
+	}
+	if len(t.Labels) > 0 {
+		details = append(details, "labels "+strings.Join(t.Labels, ", "))
+	}
+	for _, a := range t.Assignees {
+		details = append(details, "@"+a)
+	}
+	if done, total := checklistProgress(t); total > 0 {
+		details = append(details, fmt.Sprintf("checklist %d/%d", done, total))
+	}
+
+	var result []string
+	for _, d := range details {
+		if d != "" {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func checklistProgress(t Task) (done, total int) {
+	for _, item := range t.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(t.Checklist)
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// mdReplacer escapes the characters that markdown would otherwise format.
+var mdReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"\n", " ",
+)
+
+func mdEscape(s string) string {
+	return mdReplacer.Replace(s)
+}
+
+// mdCell escapes text for a table cell.
+func mdCell(s string) string {
+	return strings.ReplaceAll(mdEscape(s), "|", `\|`)
+}
@@ -0,0 +1,441 @@
+// Package boardreport renders a board, or one of its epics, as a markdown
+// or HTML document for sharing in pull requests and wikis.
+//
+// Reports contain no generation time and list everything in a fixed order
+// (columns in board order, tasks by position, epics by title), so a report
+// of an unchanged board is byte-for-byte the same and can be committed to a
+// repository.
+package boardreport
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// Options selects what a report covers.
+type Options struct {
+	// Board is the board to report on.
+	Board *core.Record
+	// Epic limits the report to the tasks of one epic of the board.
+	Epic *core.Record
+	// IncludeComments adds the comments of each task.
+	IncludeComments bool
+	// Since adds a summary of the changes made since then; zero leaves it
+	// out.
+	Since time.Time
+	// Archive selects the archived tasks to include.
+	Archive archive.Mode
+}
+
+// Report is a board or epic ready to render.
+type Report struct {
+	Title       string
+	Board       string // board name
+	Prefix      string
+	Epic        bool   // the report covers a single epic
+	Description string // epic description
+	Total       int
+	Done        int
+	Epics       []Epic
+	Columns     []Column
+	Since       string // YYYY-MM-DD; empty when changes are not reported
+	Changes     []Change
+}
+
+// Epic is the progress of an epic, counted over its tasks including
+// archived ones.
+type Epic struct {
+	Title string
+	Color string
+	Done  int
+	Total int
+}
+
+// Percent returns the share of done tasks, rounded down.
+func (e Epic) Percent() int {
+	if e.Total == 0 {
+		return 0
+	}
+	return e.Done * 100 / e.Total
+}
+
+// Column is a board column and its tasks in position order.
+type Column struct {
+	Key   string
+	Name  string
+	Tasks []Task
+}
+
+// Task is a task as shown in a report.
+type Task struct {
+	DisplayID string
+	Title     string
+	Type      string
+	Priority  string
+	DueDate   string // YYYY-MM-DD
+	Epic      string // epic title
+	Parent    string // display ID of the parent task
+	Labels    []string
+	Assignees []string
+	Checklist []checklist.Item
+	Comments  []Comment
+}
+
+// Comment is a task comment.
+type Comment struct {
+	Author  string
+	Date    string // YYYY-MM-DD HH:MM in UTC
+	Content string
+}
+
+// Change is an entry of the change summary.
+type Change struct {
+	Date    string // YYYY-MM-DD HH:MM in UTC
+	Task    string // display ID
+	Title   string
+	Action  string
+	Summary string
+	Actor   string
+}
+
+// columnNames are the section titles of the default columns.
+var columnNames = map[string]string{
+	"backlog":     "Backlog",
+	"todo":        "Todo",
+	"in_progress": "In Progress",
+	"need_input":  "Need Input",
+	"review":      "Review",
+	"done":        "Done",
+}
+
+// Build collects the data of a report.
+func Build(app core.App, opts Options) (*Report, error) {
+	b := board.RecordToBoard(opts.Board)
+	r := &Report{
+		Title:  b.Name,
+		Board:  b.Name,
+		Prefix: b.Prefix,
+	}
+	if opts.Epic != nil {
+		r.Title = opts.Epic.GetString("title")
+		r.Epic = true
+		r.Description = strings.TrimSpace(opts.Epic.GetString("description"))
+	}
+
+	filters := []dbx.Expression{
+		dbx.HashExp{"board": b.ID},
+		trash.Exclude(app, "tasks"),
+		archive.Filter(app, opts.Archive),
+	}
+	if opts.Epic != nil {
+		filters = append(filters, dbx.HashExp{"epic": opts.Epic.Id})
+	}
+	tasks, err := app.FindAllRecords("tasks", filters...)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if pi, pj := tasks[i].GetFloat("position"), tasks[j].GetFloat("position"); pi != pj {
+			return pi < pj
+		}
+		return tasks[i].GetInt("seq") < tasks[j].GetInt("seq")
+	})
+
+	// Every task of the board (archived ones too) for display IDs and
+	// epic progress
+	boardTasks, err := app.FindAllRecords("tasks",
+		dbx.HashExp{"board": b.ID},
+		trash.Exclude(app, "tasks"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	displayIDs := make(map[string]string, len(boardTasks))
+	for _, t := range boardTasks {
+		displayIDs[t.Id] = displayID(b.Prefix, t)
+	}
+
+	epics, err := reportEpics(app, b.ID, opts.Epic, boardTasks)
+	if err != nil {
+		return nil, err
+	}
+	epicTitles := make(map[string]string, len(epics))
+	for _, e := range epics {
+		epicTitles[e.Id] = e.GetString("title")
+		progress := Epic{Title: e.GetString("title"), Color: e.GetString("color")}
+		for _, t := range boardTasks {
+			if t.GetString("epic") == e.Id {
+				progress.Total++
+				if t.GetString("column") == flow.ColumnDone {
+					progress.Done++
+				}
+			}
+		}
+		r.Epics = append(r.Epics, progress)
+	}
+
+	var comments map[string][]Comment
+	if opts.IncludeComments {
+		comments, err = taskComments(app, tasks)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	byColumn := make(map[string][]Task)
+	var extraColumns []string
+	for _, t := range tasks {
+		column := t.GetString("column")
+		if _, ok := byColumn[column]; !ok && !containsString(b.Columns, column) {
+			extraColumns = append(extraColumns, column)
+		}
+		item := Task{
+			DisplayID: displayIDs[t.Id],
+			Title:     t.GetString("title"),
+			Type:      t.GetString("type"),
+			Priority:  t.GetString("priority"),
+			Epic:      epicTitles[t.GetString("epic")],
+			Labels:    t.GetStringSlice("labels"),
+			Assignees: t.GetStringSlice("assignees"),
+			Checklist: checklist.Get(t),
+			Comments:  comments[t.Id],
+		}
+		if item.DisplayID == "" {
+			item.DisplayID = displayID(b.Prefix, t)
+		}
+		if due := t.GetDateTime("due_date"); !due.IsZero() {
+			item.DueDate = due.Time().UTC().Format("2006-01-02")
+		}
+		if parent := t.GetString("parent"); parent != "" {
+			item.Parent = displayIDs[parent]
+		}
+		byColumn[column] = append(byColumn[column], item)
+
+		r.Total++
+		if column == flow.ColumnDone {
+			r.Done++
+		}
+	}
+	for _, key := range append(append([]string{}, b.Columns...), extraColumns...) {
+		name := columnNames[key]
+		if name == "" {
+			name = key
+		}
+		r.Columns = append(r.Columns, Column{Key: key, Name: name, Tasks: byColumn[key]})
+	}
+
+	if !opts.Since.IsZero() {
+		r.Since = opts.Since.UTC().Format("2006-01-02")
+		r.Changes, err = changes(app, b.ID, opts.Since, tasks, opts.Epic != nil, displayIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// reportEpics returns the epics of a report sorted by title: the given
+// epic, or the epics of the board and those its tasks belong to.
+func reportEpics(app core.App, boardID string, epic *core.Record, tasks []*core.Record) ([]*core.Record, error) {
+	if epic != nil {
+		return []*core.Record{epic}, nil
+	}
+
+	var ids []any
+	for _, t := range tasks {
+		if id := t.GetString("epic"); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	epics, err := app.FindAllRecords("epics",
+		dbx.Or(dbx.HashExp{"board": boardID}, dbx.In("id", ids...)),
+		trash.Exclude(app, "epics"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(epics, func(i, j int) bool {
+		ti, tj := strings.ToLower(epics[i].GetString("title")), strings.ToLower(epics[j].GetString("title"))
+		if ti != tj {
+			return ti < tj
+		}
+		return epics[i].Id < epics[j].Id
+	})
+	return epics, nil
+}
+
+// taskComments returns the comments of tasks by task ID, oldest first.
+func taskComments(app core.App, tasks []*core.Record) (map[string][]Comment, error) {
+	result := make(map[string][]Comment)
+	if len(tasks) == 0 {
+		return result, nil
+	}
+	ids := make([]any, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.Id
+	}
+	records, err := app.FindAllRecords("comments", dbx.In("task", ids...))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		ci, cj := records[i].GetDateTime("created").Time(), records[j].GetDateTime("created").Time()
+		if !ci.Equal(cj) {
+			return ci.Before(cj)
+		}
+		return records[i].Id < records[j].Id
+	})
+	for _, c := range records {
+		author := c.GetString("author_id")
+		if author == "" {
+			author = c.GetString("author_type")
+		}
+		comment := Comment{
+			Author:  author,
+			Content: strings.TrimSpace(c.GetString("content")),
+		}
+		if created := c.GetDateTime("created"); !created.IsZero() {
+			comment.Date = created.Time().UTC().Format("2006-01-02 15:04")
+		}
+		task := c.GetString("task")
+		result[task] = append(result[task], comment)
+	}
+	return result, nil
+}
+
+// changes returns the events of the board since a time, oldest first.
+// Epic reports only include the events of the epic's tasks.
+func changes(app core.App, boardID string, since time.Time, tasks []*core.Record, epicOnly bool, displayIDs map[string]string) ([]Change, error) {
+	events, err := taskevent.Query(app, taskevent.Filter{Board: boardID, Since: since})
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		titles[t.Id] = t.GetString("title")
+	}
+	// Deleted tasks are named by the title their events recorded
+	deletedTitles := make(map[string]string)
+	for _, e := range events {
+		if title, ok := e.Metadata["title"].(string); ok && title != "" {
+			deletedTitles[e.Task] = title
+		}
+	}
+
+	var result []Change
+	for _, e := range events {
+		title, ok := titles[e.Task]
+		if epicOnly && !ok {
+			continue
+		}
+		if !ok {
+			if t, err := app.FindRecordById("tasks", e.Task); err == nil {
+				title = t.GetString("title")
+			} else {
+				title = deletedTitles[e.Task]
+			}
+		}
+		id := displayIDs[e.Task]
+		if id == "" {
+			id = e.Task
+			if len(id) > 8 {
+				id = id[:8]
+			}
+		}
+		result = append(result, Change{
+			Date:    e.Timestamp.UTC().Format("2006-01-02 15:04"),
+			Task:    id,
+			Title:   title,
+			Action:  strings.ReplaceAll(e.Action, "_", " "),
+			Summary: summarize(e),
+			Actor:   e.ActorName(),
+		})
+	}
+	return result, nil
+}
+
+// summaryFields are the fields whose values are short enough to show in a
+// change summary; other changed fields are only named.
+var summaryFields = map[string]bool{
+	"column": true, "priority": true, "type": true, "due_date": true, "estimate": true,
+}
+
+// summarize describes the fields an event changed, e.g.
+// "column: todo → done, description".
+func summarize(e taskevent.Event) string {
+	changes, ok := e.Changes.(map[string]any)
+	if !ok || e.Action == taskevent.ActionCreated || e.Action == taskevent.ActionDeleted {
+		return ""
+	}
+	keys := make([]string, 0, len(changes))
+	for k := range changes {
+		if k != "position" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		diff, isDiff := changes[k].(map[string]any)
+		if _, hasFrom := diff["from"]; !isDiff || !hasFrom || !summaryFields[k] {
+			parts = append(parts, k)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s → %s", k, summaryValue(diff["from"]), summaryValue(diff["to"])))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func summaryValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		if x == "" {
+			return "-"
+		}
+		if len(x) > 10 && x[4] == '-' && x[7] == '-' {
+			return x[:10] // dates
+		}
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// displayID returns the display ID of a task on a board with prefix.
+func displayID(prefix string, task *core.Record) string {
+	if seq := task.GetInt("seq"); seq > 0 && prefix != "" {
+		return board.FormatDisplayID(prefix, seq)
+	}
+	if len(task.Id) > 8 {
+		return task.Id[:8]
+	}
+	return task.Id
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package boardreport
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+// setupReport creates a board WRK with an epic, three tasks (one archived)
+// and a comment.
+func setupReport(t *testing.T) (*pocketbase.PocketBase, *core.Record, *core.Record) {
+	t.Helper()
+	app := testutil.NewTestApp(t)
+
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "prefix"},
+		&core.JSONField{Name: "columns"},
+	)
+	epics := testutil.CreateTestCollection(t, app, "epics",
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "description"},
+		&core.TextField{Name: "color"},
+		&core.TextField{Name: "board"},
+	)
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "type"},
+		&core.TextField{Name: "priority"},
+		&core.TextField{Name: "column"},
+		&core.NumberField{Name: "position"},
+		&core.TextField{Name: "board"},
+		&core.NumberField{Name: "seq"},
+		&core.TextField{Name: "epic"},
+		&core.TextField{Name: "parent"},
+		&core.JSONField{Name: "labels"},
+		&core.JSONField{Name: "assignees"},
+		&core.DateField{Name: "due_date"},
+		&core.JSONField{Name: "checklist"},
+		&core.DateField{Name: "archived_at"},
+	)
+	comments := testutil.CreateTestCollection(t, app, "comments",
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "content"},
+		&core.TextField{Name: "author_type"},
+		&core.TextField{Name: "author_id"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+	testutil.CreateTestCollection(t, app, taskevent.CollectionName,
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "board"},
+		&core.TextField{Name: "action"},
+		&core.TextField{Name: "actor"},
+		&core.TextField{Name: "actor_detail"},
+		&core.JSONField{Name: "changes"},
+		&core.JSONField{Name: "metadata"},
+		&core.DateField{Name: "timestamp"},
+	)
+
+	b := core.NewRecord(boards)
+	b.Set("name", "Work")
+	b.Set("prefix", "WRK")
+	require.NoError(t, app.Save(b))
+
+	epic := core.NewRecord(epics)
+	epic.Set("title", "Launch")
+	epic.Set("description", "Ship 2.0")
+	epic.Set("board", b.Id)
+	require.NoError(t, app.Save(epic))
+
+	newTask := func(seq int, title, column string, position float64, fields map[string]any) *core.Record {
+		task := core.NewRecord(tasks)
+		task.Set("title", title)
+		task.Set("type", "feature")
+		task.Set("priority", "medium")
+		task.Set("column", column)
+		task.Set("position", position)
+		task.Set("board", b.Id)
+		task.Set("seq", seq)
+		for k, v := range fields {
+			task.Set(k, v)
+		}
+		require.NoError(t, app.Save(task))
+		return task
+	}
+	docs := newTask(1, "Write *docs*", "todo", 2000, map[string]any{
+		"epic":      epic.Id,
+		"due_date":  "2026-10-21",
+		"labels":    []string{"docs"},
+		"checklist": `[{"text":"Intro","done":true},{"text":"API","done":false}]`,
+	})
+	newTask(2, "Triage", "todo", 1000, nil)
+	newTask(3, "Tag release", "done", 1000, map[string]any{
+		"epic":        epic.Id,
+		"parent":      docs.Id,
+		"archived_at": "2026-10-01 10:00:00.000Z",
+	})
+
+	c := core.NewRecord(comments)
+	c.Set("task", docs.Id)
+	c.Set("content", "Drafted\nthe intro")
+	c.Set("author_type", "agent")
+	c.Set("author_id", "claude")
+	require.NoError(t, app.Save(c))
+
+	return app, b, epic
+}
+
+func TestBuild_Board(t *testing.T) {
+	app, b, _ := setupReport(t)
+
+	r, err := Build(app, Options{Board: b, IncludeComments: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Work", r.Title)
+	assert.Equal(t, 2, r.Total, "archived tasks are left out by default")
+	require.Len(t, r.Columns, 6, "every column of the board is listed")
+	todo := r.Columns[1]
+	assert.Equal(t, "Todo", todo.Name)
+	require.Len(t, todo.Tasks, 2)
+	assert.Equal(t, "WRK-2", todo.Tasks[0].DisplayID, "tasks are in position order")
+
+	docs := todo.Tasks[1]
+	assert.Equal(t, "Launch", docs.Epic)
+	assert.Equal(t, "2026-10-21", docs.DueDate)
+	assert.Len(t, docs.Checklist, 2)
+	require.Len(t, docs.Comments, 1)
+	assert.Equal(t, "claude", docs.Comments[0].Author)
+
+	// Epic progress counts archived tasks
+	require.Len(t, r.Epics, 1)
+	assert.Equal(t, Epic{Title: "Launch", Done: 1, Total: 2}, r.Epics[0])
+
+	r, err = Build(app, Options{Board: b, Archive: archive.All})
+	require.NoError(t, err)
+	assert.Equal(t, 3, r.Total)
+	assert.Equal(t, "WRK-1", r.Columns[5].Tasks[0].Parent)
+	assert.Empty(t, r.Columns[1].Tasks[1].Comments)
+}
+
+func TestBuild_Epic(t *testing.T) {
+	app, b, epic := setupReport(t)
+
+	r, err := Build(app, Options{Board: b, Epic: epic, Archive: archive.All})
+	require.NoError(t, err)
+	assert.Equal(t, "Launch", r.Title)
+	assert.True(t, r.Epic)
+	assert.Equal(t, "Ship 2.0", r.Description)
+	assert.Equal(t, 2, r.Total)
+	assert.Equal(t, 1, r.Done)
+}
+
+func TestBuild_Changes(t *testing.T) {
+	app, b, _ := setupReport(t)
+	task, err := app.FindFirstRecordByData("tasks", "seq", 2)
+	require.NoError(t, err)
+
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for _, e := range []taskevent.Event{
+		{Action: taskevent.ActionMoved, Timestamp: since.Add(-time.Hour),
+			Changes: map[string]any{"column": map[string]any{"from": "backlog", "to": "todo"}}},
+		{Action: taskevent.ActionUpdated, Timestamp: since.Add(time.Hour),
+			Changes: map[string]any{
+				"description": map[string]any{"from": "", "to": "A long description"},
+				"due_date":    map[string]any{"from": nil, "to": "2026-10-21 00:00:00.000Z"},
+			}},
+	} {
+		e.Task, e.Board, e.Actor = task.Id, b.Id, "cli"
+		require.NoError(t, taskevent.Save(app, e))
+	}
+
+	r, err := Build(app, Options{Board: b, Since: since})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-01", r.Since)
+	require.Len(t, r.Changes, 1)
+	c := r.Changes[0]
+	assert.Equal(t, "WRK-2", c.Task)
+	assert.Equal(t, "Triage", c.Title)
+	assert.Equal(t, "description, due_date: - → 2026-10-21", c.Summary)
+}
+
+func TestMarkdown(t *testing.T) {
+	app, b, _ := setupReport(t)
+	r, err := Build(app, Options{Board: b, IncludeComments: true})
+	require.NoError(t, err)
+
+	var first, second bytes.Buffer
+	require.NoError(t, Markdown(&first, r))
+	require.NoError(t, Markdown(&second, r))
+	assert.Equal(t, first.String(), second.String())
+
+	assert.Equal(t, `# Work
+
+2 tasks · 0 done
+
+## Epics
+
+| Epic | Progress | Done |
+| --- | --- | --- |
+| Launch | █████░░░░░ 50% | 1/2 |
+
+## Backlog (0)
+
+_No tasks._
+
+## Todo (2)
+
+- **WRK-2** Triage · medium · feature
+- **WRK-1** Write \*docs\* · medium · feature · due 2026-10-21 · epic Launch · labels docs · checklist 1/2
+  - [x] Intro
+  - [ ] API
+
+  > **claude** · `+r.Columns[1].Tasks[1].Comments[0].Date+`
+  >
+  > Drafted
+  > the intro
+
+## In Progress (0)
+
+_No tasks._
+
+## Need Input (0)
+
+_No tasks._
+
+## Review (0)
+
+_No tasks._
+
+## Done (0)
+
+_No tasks._
+`, first.String())
+}
+
+func TestHTML(t *testing.T) {
+	app, b, _ := setupReport(t)
+	r, err := Build(app, Options{Board: b})
+	require.NoError(t, err)
+	r.Columns[1].Tasks[0].Title = "<script>alert(1)</script>"
+
+	var buf bytes.Buffer
+	require.NoError(t, HTML(&buf, r))
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.Contains(t, out, "&lt;script&gt;")
+	assert.NotContains(t, out, "<script>")
+	assert.Contains(t, out, `<progress value="1" max="2"></progress> 50%`)
+	assert.Contains(t, out, `<input type="checkbox" disabled checked> Intro`)
+}
+
+func TestProgressBar(t *testing.T) {
+	assert.Equal(t, "░░░░░░░░░░", ProgressBar(0, 0))
+	assert.Equal(t, "███░░░░░░░", ProgressBar(1, 3))
+	assert.Equal(t, "██████████", ProgressBar(4, 4))
+}
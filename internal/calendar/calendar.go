@@ -0,0 +1,264 @@
+// Package calendar publishes the due dates of a board as an iCalendar
+// (RFC 5545) document, for `export --format ics` and the calendar feed of
+// the server.
+//
+// Every open task with a due date becomes an all-day VEVENT or a VTODO.
+// The UID of an entry is derived from the task ID, so calendar clients
+// refreshing a subscription update entries in place instead of adding
+// duplicates when a task is renamed or its due date moves. Like board
+// reports, calendars contain no generation time: an unchanged board gives
+// the same document.
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// Entry kinds.
+const (
+	// KindEvent publishes tasks as all-day events on their due date,
+	// which every calendar client shows.
+	KindEvent = "event"
+	// KindTodo publishes tasks as to-dos due on their due date.
+	KindTodo = "todo"
+)
+
+// Kinds lists the accepted entry kinds.
+var Kinds = []string{KindEvent, KindTodo}
+
+// uidDomain qualifies entry UIDs, as RFC 5545 recommends.
+const uidDomain = "egenskriven"
+
+// priorities maps task priorities to iCalendar priorities (1 highest,
+// 9 lowest).
+var priorities = map[string]int{
+	"urgent": 1,
+	"high":   3,
+	"medium": 5,
+	"low":    9,
+}
+
+// Options selects what a calendar covers.
+type Options struct {
+	// Board is the board whose tasks are published.
+	Board *core.Record
+	// Kind is KindEvent (the default) or KindTodo.
+	Kind string
+}
+
+// Calendar is a board's due dates ready to write.
+type Calendar struct {
+	Name    string
+	Kind    string
+	Entries []Entry
+}
+
+// Entry is one task with a due date.
+type Entry struct {
+	UID         string
+	Summary     string // display ID and title
+	Description string
+	Due         time.Time // the due day, at midnight UTC
+	Priority    int       // 0 when the task has no known priority
+	Status      string    // NEEDS-ACTION or IN-PROCESS; to-dos only
+	Categories  []string  // labels
+	Stamp       time.Time // last change of the task
+}
+
+// ValidateKind checks if an entry kind is valid.
+func ValidateKind(kind string) error {
+	for _, valid := range Kinds {
+		if kind == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid calendar entry kind '%s', must be one of: %v", kind, Kinds)
+}
+
+// UID returns the UID of the entry of a task.
+func UID(taskID string) string {
+	return taskID + "@" + uidDomain
+}
+
+// Build collects the open tasks of a board that have a due date. Done,
+// archived, and trashed tasks are left out.
+func Build(app core.App, opts Options) (*Calendar, error) {
+	kind := opts.Kind
+	if kind == "" {
+		kind = KindEvent
+	}
+	if err := ValidateKind(kind); err != nil {
+		return nil, err
+	}
+
+	b := board.RecordToBoard(opts.Board)
+	c := &Calendar{
+		Name: fmt.Sprintf("%s (%s)", b.Name, b.Prefix),
+		Kind: kind,
+	}
+
+	tasks, err := app.FindAllRecords("tasks",
+		dbx.HashExp{"board": b.ID},
+		dbx.NewExp("due_date != ''"),
+		dbx.Not(dbx.HashExp{"column": flow.ColumnDone}),
+		trash.Exclude(app, "tasks"),
+		archive.Filter(app, archive.Active),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tasks {
+		due := t.GetDateTime("due_date").Time().UTC()
+		due = time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+
+		e := Entry{
+			UID:         UID(t.Id),
+			Summary:     displayID(b.Prefix, t) + " " + t.GetString("title"),
+			Description: strings.TrimSpace(t.GetString("description")),
+			Due:         due,
+			Priority:    priorities[t.GetString("priority")],
+			Categories:  t.GetStringSlice("labels"),
+			Stamp:       stamp(t, due),
+		}
+		if kind == KindTodo {
+			e.Status = "NEEDS-ACTION"
+			if t.GetString("column") == flow.ColumnInProgress {
+				e.Status = "IN-PROCESS"
+			}
+		}
+		c.Entries = append(c.Entries, e)
+	}
+
+	sort.SliceStable(c.Entries, func(i, j int) bool {
+		if !c.Entries[i].Due.Equal(c.Entries[j].Due) {
+			return c.Entries[i].Due.Before(c.Entries[j].Due)
+		}
+		return c.Entries[i].UID < c.Entries[j].UID
+	})
+	return c, nil
+}
+
+// Write writes a calendar as an iCalendar document.
+func Write(w io.Writer, c *Calendar) error {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		writeLine(&b, fmt.Sprintf(format, args...))
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//EgenSkriven//EgenSkriven//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:%s", escapeText(c.Name))
+	line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	line("X-PUBLISHED-TTL:PT1H")
+
+	for _, e := range c.Entries {
+		component := "VEVENT"
+		if c.Kind == KindTodo {
+			component = "VTODO"
+		}
+		line("BEGIN:%s", component)
+		line("UID:%s", e.UID)
+		line("DTSTAMP:%s", e.Stamp.UTC().Format("20060102T150405Z"))
+		if c.Kind == KindTodo {
+			line("DUE;VALUE=DATE:%s", e.Due.Format("20060102"))
+		} else {
+			line("DTSTART;VALUE=DATE:%s", e.Due.Format("20060102"))
+			line("DTEND;VALUE=DATE:%s", e.Due.AddDate(0, 0, 1).Format("20060102"))
+			line("TRANSP:TRANSPARENT")
+		}
+		line("SUMMARY:%s", escapeText(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:%s", escapeText(e.Description))
+		}
+		if e.Priority > 0 {
+			line("PRIORITY:%d", e.Priority)
+		}
+		if e.Status != "" {
+			line("STATUS:%s", e.Status)
+		}
+		if len(e.Categories) > 0 {
+			categories := make([]string, len(e.Categories))
+			for i, category := range e.Categories {
+				categories[i] = escapeText(category)
+			}
+			line("CATEGORIES:%s", strings.Join(categories, ","))
+		}
+		line("END:%s", component)
+	}
+
+	line("END:VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeLine writes a content line ending in CRLF, folded into lines of at
+// most 75 octets without splitting UTF-8 sequences.
+func writeLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // the leading space of a continuation line counts
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+// escapeText escapes a TEXT value: backslashes, semicolons, commas, and
+// newlines.
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// stamp returns the time of the last change of a task, falling back to its
+// creation and then its due date, so that DTSTAMP only changes with the task.
+func stamp(task *core.Record, due time.Time) time.Time {
+	for _, field := range []string{"updated", "created"} {
+		if t := task.GetDateTime(field); !t.IsZero() {
+			return t.Time()
+		}
+	}
+	return due
+}
+
+// displayID returns the display ID of a task on a board with prefix.
+func displayID(prefix string, task *core.Record) string {
+	if seq := task.GetInt("seq"); seq > 0 && prefix != "" {
+		return board.FormatDisplayID(prefix, seq)
+	}
+	if len(task.Id) > 8 {
+		return task.Id[:8]
+	}
+	return task.Id
+}
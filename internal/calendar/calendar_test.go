@@ -0,0 +1,139 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+// setupCalendar creates a board WRK with tasks with and without due dates.
+func setupCalendar(t *testing.T) (*pocketbase.PocketBase, *core.Record) {
+	t.Helper()
+	app := testutil.NewTestApp(t)
+
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "prefix"},
+		&core.JSONField{Name: "columns"},
+	)
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "description"},
+		&core.TextField{Name: "priority"},
+		&core.TextField{Name: "column"},
+		&core.TextField{Name: "board"},
+		&core.NumberField{Name: "seq"},
+		&core.JSONField{Name: "labels"},
+		&core.DateField{Name: "due_date"},
+		&core.DateField{Name: "archived_at"},
+	)
+
+	b := core.NewRecord(boards)
+	b.Set("name", "Work")
+	b.Set("prefix", "WRK")
+	require.NoError(t, app.Save(b))
+
+	newTask := func(id string, seq int, title, column, due string, fields map[string]any) {
+		task := core.NewRecord(tasks)
+		task.Id = id
+		task.Set("title", title)
+		task.Set("priority", "medium")
+		task.Set("column", column)
+		task.Set("board", b.Id)
+		task.Set("seq", seq)
+		task.Set("due_date", due)
+		for k, v := range fields {
+			task.Set(k, v)
+		}
+		require.NoError(t, app.Save(task))
+	}
+	newTask("task00000000002", 2, "Plan Q1, budget", "in_progress", "2026-11-02", map[string]any{
+		"priority":    "urgent",
+		"description": "Line one\nLine two; done",
+		"labels":      []string{"planning", "finance"},
+	})
+	newTask("task00000000001", 1, "Ship docs", "todo", "2026-10-21", nil)
+	newTask("task00000000003", 3, "Released", "done", "2026-10-01", nil)
+	newTask("task00000000004", 4, "No date", "todo", "", nil)
+	newTask("task00000000005", 5, "Old", "todo", "2026-09-01", map[string]any{"archived_at": "2026-09-15"})
+
+	return app, b
+}
+
+func TestBuild(t *testing.T) {
+	app, b := setupCalendar(t)
+
+	c, err := Build(app, Options{Board: b})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Work (WRK)", c.Name)
+	assert.Equal(t, KindEvent, c.Kind)
+	require.Len(t, c.Entries, 2, "done, archived, and undated tasks are left out")
+	assert.Equal(t, "WRK-1 Ship docs", c.Entries[0].Summary, "entries are in due date order")
+	assert.Equal(t, "task00000000001@egenskriven", c.Entries[0].UID)
+	assert.Equal(t, 1, c.Entries[1].Priority)
+	assert.Empty(t, c.Entries[1].Status, "events have no status")
+
+	c, err = Build(app, Options{Board: b, Kind: KindTodo})
+	require.NoError(t, err)
+	assert.Equal(t, "NEEDS-ACTION", c.Entries[0].Status)
+	assert.Equal(t, "IN-PROCESS", c.Entries[1].Status)
+
+	_, err = Build(app, Options{Board: b, Kind: "journal"})
+	assert.ErrorContains(t, err, "invalid calendar entry kind")
+}
+
+func TestWrite(t *testing.T) {
+	app, b := setupCalendar(t)
+
+	c, err := Build(app, Options{Board: b, Kind: KindTodo})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, c))
+	ics := buf.String()
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VTODO\r\n"))
+	assert.Contains(t, ics, "UID:task00000000002@egenskriven\r\n")
+	assert.Contains(t, ics, "DUE;VALUE=DATE:20261102\r\n")
+	assert.Contains(t, ics, `SUMMARY:WRK-2 Plan Q1\, budget`)
+	assert.Contains(t, ics, `DESCRIPTION:Line one\nLine two\; done`)
+	assert.Contains(t, ics, "CATEGORIES:planning,finance\r\n")
+
+	// The same board gives the same document
+	var again bytes.Buffer
+	require.NoError(t, Write(&again, c))
+	assert.Equal(t, ics, again.String())
+
+	c, err = Build(app, Options{Board: b})
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, Write(&buf, c))
+	assert.Contains(t, buf.String(), "BEGIN:VEVENT\r\n")
+	assert.Contains(t, buf.String(), "DTSTART;VALUE=DATE:20261021\r\nDTEND;VALUE=DATE:20261022\r\n")
+}
+
+func TestWriteLine_Folding(t *testing.T) {
+	var b strings.Builder
+	writeLine(&b, "SUMMARY:"+strings.Repeat("å", 80))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	require.Len(t, lines, 3)
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, strings.ToValidUTF8(line, "?") == line, "line %d splits a character", i)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(line, " "))
+		}
+	}
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ", "")
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("å", 80), unfolded)
+}
@@ -0,0 +1,269 @@
+// Package checklist manages the checklist items of tasks.
+//
+// Items are stored in the task's checklist field and mirrored as markdown
+// checkboxes ("- [ ] step") in its description, so both the CLI and the
+// description editors in the TUI and web UI see the same list. Changing
+// either side updates the other (see Sync).
+package checklist
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// Field is the task field holding the checklist items.
+const Field = "checklist"
+
+// Heading starts the section added to descriptions without checkboxes.
+const Heading = "## Checklist"
+
+// Item is a single checklist entry.
+type Item struct {
+	Text   string `json:"text"`
+	Done   bool   `json:"done"`
+	DoneBy string `json:"done_by,omitempty"`
+	DoneAt string `json:"done_at,omitempty"`
+}
+
+// checkboxPattern matches markdown task list lines: an optional indent, a
+// bullet, the box, and the item text.
+var checkboxPattern = regexp.MustCompile(`^(\s*[-*+]\s+)\[([ xX])\]\s+(.*?)\s*$`)
+
+// Supported reports whether a task collection has the checklist field.
+func Supported(task *core.Record) bool {
+	return task.Collection().Fields.GetByName(Field) != nil
+}
+
+// Get returns a task's checklist items.
+func Get(task *core.Record) []Item {
+	return Decode(task.Get(Field))
+}
+
+// Decode converts a checklist field value, such as the JSON of a realtime
+// event, to items.
+func Decode(raw any) []Item {
+	var items []Item
+	if raw != nil {
+		if data, err := json.Marshal(raw); err == nil {
+			json.Unmarshal(data, &items)
+		}
+	}
+	return items
+}
+
+// Set stores items on a task and rewrites the checkboxes of its
+// description to match.
+func Set(task *core.Record, items []Item) {
+	if items == nil {
+		items = []Item{}
+	}
+	task.Set(Field, items)
+	task.Set("description", Render(task.GetString("description"), items))
+}
+
+// Progress returns the number of done items and the total.
+func Progress(items []Item) (done, total int) {
+	for _, item := range items {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(items)
+}
+
+// FormatProgress formats progress as "2/5", or "" for an empty checklist.
+func FormatProgress(items []Item) string {
+	done, total := Progress(items)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", done, total)
+}
+
+// Parse returns the markdown checkboxes of a description as items.
+func Parse(description string) []Item {
+	var items []Item
+	for _, line := range strings.Split(description, "\n") {
+		m := checkboxPattern.FindStringSubmatch(line)
+		if m == nil || m[3] == "" {
+			continue
+		}
+		items = append(items, Item{Text: m[3], Done: m[2] != " "})
+	}
+	return items
+}
+
+// Render rewrites the checkboxes of a description to show items. Existing
+// checkbox lines are updated in order, keeping their bullets; extra
+// items are added after the last one, or under a new "## Checklist"
+// section when the description has none; surplus lines are removed.
+func Render(description string, items []Item) string {
+	var lines []string
+	if description != "" {
+		lines = strings.Split(description, "\n")
+	}
+
+	var result []string
+	next, last := 0, -1
+	for _, line := range lines {
+		m := checkboxPattern.FindStringSubmatch(line)
+		if m == nil || m[3] == "" {
+			result = append(result, line)
+			continue
+		}
+		if next < len(items) {
+			item := items[next]
+			if m[3] != item.Text || (m[2] != " ") != item.Done {
+				line = m[1] + checkbox(item)
+			}
+			result = append(result, line)
+			last = len(result) - 1
+			next++
+		}
+	}
+
+	if next == len(items) {
+		return strings.Join(result, "\n")
+	}
+
+	var extra []string
+	for _, item := range items[next:] {
+		extra = append(extra, "- "+checkbox(item))
+	}
+	if last >= 0 {
+		result = append(result[:last+1], append(extra, result[last+1:]...)...)
+		return strings.Join(result, "\n")
+	}
+
+	text := strings.TrimRight(strings.Join(result, "\n"), "\n")
+	if text != "" {
+		text += "\n\n"
+	}
+	return text + Heading + "\n" + strings.Join(extra, "\n")
+}
+
+// checkbox formats an item as "[ ] text" or "[x] text".
+func checkbox(item Item) string {
+	if item.Done {
+		return "[x] " + item.Text
+	}
+	return "[ ] " + item.Text
+}
+
+// Merge returns the items parsed from an edited description, keeping who
+// completed an item and when from the previous items with the same text.
+// Items newly checked in the description are marked done by actor at now.
+func Merge(parsed, previous []Item, actor string, now time.Time) []Item {
+	used := make([]bool, len(previous))
+	merged := make([]Item, 0, len(parsed))
+	for _, item := range parsed {
+		for i, prev := range previous {
+			if used[i] || prev.Text != item.Text {
+				continue
+			}
+			used[i] = true
+			if prev.Done && item.Done {
+				item.DoneBy, item.DoneAt = prev.DoneBy, prev.DoneAt
+			}
+			break
+		}
+		if item.Done && item.DoneAt == "" {
+			item.DoneBy = actor
+			item.DoneAt = now.UTC().Format(time.RFC3339)
+		}
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// Sync keeps the checklist field and the description's checkboxes of a
+// task that is about to be saved in agreement. When only the checklist
+// changed, the description is rewritten; when the description changed, the
+// checklist is parsed from it. A new task fills whichever side is empty.
+func Sync(task *core.Record, actor string, now time.Time) {
+	if !Supported(task) {
+		return
+	}
+
+	items := Get(task)
+	description := task.GetString("description")
+
+	var listChanged, descChanged bool
+	if task.IsNew() {
+		listChanged = len(items) > 0
+		descChanged = !listChanged && len(Parse(description)) > 0
+	} else {
+		original := task.Original()
+		listChanged = !equal(items, Get(original))
+		descChanged = description != original.GetString("description")
+	}
+
+	switch {
+	case listChanged && descChanged:
+		// Both sides were written together (by Set or an undo)
+	case listChanged:
+		task.Set("description", Render(description, items))
+	case descChanged:
+		var previous []Item
+		if !task.IsNew() {
+			previous = Get(task.Original())
+		}
+		merged := Merge(Parse(description), previous, actor, now)
+		if len(merged) > 0 || len(items) > 0 {
+			task.Set(Field, merged)
+		}
+	}
+}
+
+// equal reports whether two checklists hold the same items.
+func equal(a, b []Item) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the index of the item a reference names: a 1-based number
+// or text matching a single item (exactly, or as a case-insensitive
+// substring).
+func Find(items []Item, ref string) (int, error) {
+	ref = strings.TrimSpace(ref)
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(items) {
+			return -1, fmt.Errorf("no checklist item %d (the task has %d)", n, len(items))
+		}
+		return n - 1, nil
+	}
+
+	for i, item := range items {
+		if item.Text == ref {
+			return i, nil
+		}
+	}
+	match := -1
+	lower := strings.ToLower(ref)
+	for i, item := range items {
+		if !strings.Contains(strings.ToLower(item.Text), lower) {
+			continue
+		}
+		if match >= 0 {
+			return -1, fmt.Errorf("checklist item %q is ambiguous, use its number", ref)
+		}
+		match = i
+	}
+	if match < 0 {
+		return -1, fmt.Errorf("no checklist item matches %q", ref)
+	}
+	return match, nil
+}
@@ -0,0 +1,160 @@
+package checklist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+const description = `## Approach
+Split the parser.
+
+## Checklist
+- [x] Write the migration
+- [ ] Add tests
+  * [X] Nested step
+
+Notes: [ ] is not a checkbox here.`
+
+func TestParse(t *testing.T) {
+	items := Parse(description)
+	assert.Equal(t, []Item{
+		{Text: "Write the migration", Done: true},
+		{Text: "Add tests"},
+		{Text: "Nested step", Done: true},
+	}, items)
+
+	assert.Empty(t, Parse("no boxes\n- [ ]\n- plain item"))
+	done, total := Progress(items)
+	assert.Equal(t, 2, done)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, "2/3", FormatProgress(items))
+	assert.Empty(t, FormatProgress(nil))
+}
+
+func TestRender(t *testing.T) {
+	items := Parse(description)
+
+	// Unchanged items render the same description
+	assert.Equal(t, description, Render(description, items))
+
+	// Ticking an item keeps the bullet and indentation
+	items[2].Done = false
+	items[1].Done = true
+	rendered := Render(description, items)
+	assert.Contains(t, rendered, "- [x] Add tests\n  * [ ] Nested step\n")
+
+	// Extra items go after the last checkbox
+	items = append(items, Item{Text: "Update docs"})
+	rendered = Render(description, items)
+	assert.Contains(t, rendered, "  * [ ] Nested step\n- [ ] Update docs\n\nNotes:")
+
+	// Removed items drop their lines
+	rendered = Render(description, items[:1])
+	assert.Equal(t, []Item{{Text: "Write the migration", Done: true}}, Parse(rendered))
+	assert.Contains(t, rendered, "Split the parser.")
+
+	// A description without checkboxes gets a checklist section
+	assert.Equal(t, "Some context\n\n## Checklist\n- [ ] First",
+		Render("Some context\n", []Item{{Text: "First"}}))
+	assert.Equal(t, "## Checklist\n- [x] First", Render("", []Item{{Text: "First", Done: true}}))
+}
+
+func TestMerge(t *testing.T) {
+	now := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	previous := []Item{
+		{Text: "A", Done: true, DoneBy: "claude", DoneAt: "2026-03-30T09:00:00Z"},
+		{Text: "B"},
+		{Text: "C", Done: true, DoneBy: "ramtin", DoneAt: "2026-03-31T09:00:00Z"},
+	}
+	parsed := []Item{{Text: "A", Done: true}, {Text: "B", Done: true}, {Text: "C"}, {Text: "D"}}
+
+	merged := Merge(parsed, previous, "alice", now)
+	assert.Equal(t, []Item{
+		{Text: "A", Done: true, DoneBy: "claude", DoneAt: "2026-03-30T09:00:00Z"},
+		{Text: "B", Done: true, DoneBy: "alice", DoneAt: "2026-04-01T10:00:00Z"},
+		{Text: "C"},
+		{Text: "D"},
+	}, merged)
+}
+
+func TestFind(t *testing.T) {
+	items := []Item{{Text: "Write migration"}, {Text: "Write tests"}, {Text: "Docs"}}
+
+	i, err := Find(items, "2")
+	require.NoError(t, err)
+	assert.Equal(t, 1, i)
+	i, err = Find(items, "docs")
+	require.NoError(t, err)
+	assert.Equal(t, 2, i)
+	i, err = Find(items, "Write tests")
+	require.NoError(t, err)
+	assert.Equal(t, 1, i)
+
+	_, err = Find(items, "4")
+	assert.ErrorContains(t, err, "no checklist item 4")
+	_, err = Find(items, "write")
+	assert.ErrorContains(t, err, "ambiguous")
+	_, err = Find(items, "deploy")
+	assert.ErrorContains(t, err, "no checklist item matches")
+}
+
+func TestSync(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	collection := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "description"},
+		&core.JSONField{Name: Field},
+	)
+	now := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	// A new task with checkboxes gets a checklist
+	task := core.NewRecord(collection)
+	task.Set("title", "Task")
+	task.Set("description", "- [ ] One\n- [ ] Two")
+	Sync(task, "ramtin", now)
+	assert.Equal(t, []Item{{Text: "One"}, {Text: "Two"}}, Get(task))
+	require.NoError(t, app.Save(task))
+
+	task, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+
+	// Ticking a box in the description ticks the item
+	task.Set("description", "- [x] One\n- [ ] Two")
+	Sync(task, "claude", now)
+	assert.Equal(t, []Item{{Text: "One", Done: true, DoneBy: "claude", DoneAt: "2026-04-01T10:00:00Z"}, {Text: "Two"}}, Get(task))
+	require.NoError(t, app.Save(task))
+
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+
+	// Changing the checklist rewrites the description
+	items := Get(task)
+	items[1].Done = true
+	task.Set(Field, items)
+	Sync(task, "claude", now)
+	assert.Equal(t, "- [x] One\n- [x] Two", task.GetString("description"))
+
+	// Set writes both sides, which Sync leaves alone
+	require.NoError(t, app.Save(task))
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	Set(task, []Item{{Text: "Only"}})
+	Sync(task, "claude", now)
+	assert.Equal(t, "- [ ] Only", task.GetString("description"))
+	assert.Equal(t, []Item{{Text: "Only"}}, Get(task))
+
+	// Collections without the field are left alone
+	plain := testutil.CreateTestCollection(t, app, "plain",
+		&core.TextField{Name: "description"},
+	)
+	record := core.NewRecord(plain)
+	record.Set("description", "- [ ] One")
+	Sync(record, "ramtin", now)
+	assert.Nil(t, record.Get(Field))
+}
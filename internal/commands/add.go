@@ -5,19 +5,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/spf13/cobra"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/auth"
 	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
 	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/dedupe"
 	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
 	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
 )
 
 // TaskInput represents a task for batch creation
@@ -32,23 +38,30 @@ type TaskInput struct {
 	Epic        string   `json:"epic,omitempty"`
 	DueDate     string   `json:"due_date,omitempty"`
 	Parent      string   `json:"parent,omitempty"`
+	Estimate    float64  `json:"estimate,omitempty"`
+	Assignees   []string `json:"assignees,omitempty"`
 }
 
 func newAddCmd(app *pocketbase.PocketBase) *cobra.Command {
 	var (
-		taskType  string
-		priority  string
-		column    string
-		labels    []string
-		customID  string
-		createdBy string
-		agentName string
-		epic      string
-		stdin     bool
-		file      string
-		boardRef  string
-		dueDate   string
-		parent    string
+		taskType     string
+		priority     string
+		column       string
+		labels       []string
+		customID     string
+		createdBy    string
+		agentName    string
+		epic         string
+		stdin        bool
+		file         string
+		boardRef     string
+		dueDate      string
+		parent       string
+		estimateVal  float64
+		assign       []string
+		templateName string
+		templateVars []string
+		dedupeOpts   dedupeOptions
 	)
 
 	cmd := &cobra.Command{
@@ -65,6 +78,13 @@ Examples:
   egenskriven add "Setup CI" --id ci-setup-001
   egenskriven add "Refactor auth" --agent claude
   egenskriven add "Add login" --epic "Auth Refactor"
+  egenskriven add "Write migration" --estimate 3
+  egenskriven add "Review PR" --assign me --assign claude
+  egenskriven add --template bug "Crash on save" --var version=1.2
+
+  # Likely duplicates of open tasks on the board are reported (--dedupe warn),
+  # or the task is not created (--dedupe skip) or the command fails (--dedupe fail)
+  egenskriven add "Crash when saving" --dedupe skip
   
   # Batch from stdin (JSON lines)
   echo '{"title":"Task 1"}
@@ -77,13 +97,73 @@ Examples:
 			out := getFormatter()
 
 			// Bootstrap the app to ensure database is ready
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
+			if err := dedupeOpts.validate(); err != nil {
+				return out.Error(ExitValidation, err.Error(), nil)
+			}
+
 			// Handle batch input
 			if stdin || file != "" {
-				return addBatch(app, out, stdin, file, agentName, boardRef)
+				return addBatch(app, out, stdin, file, agentName, boardRef, dedupeOpts)
+			}
+
+			// Determine and validate creator
+			if createdBy == "" {
+				if agentName != "" {
+					createdBy = "agent"
+				} else {
+					// Detect if running in a TTY
+					if fileInfo, _ := os.Stdin.Stat(); (fileInfo.Mode() & os.ModeCharDevice) != 0 {
+						createdBy = "user"
+					} else {
+						createdBy = "cli"
+					}
+				}
+			} else if createdBy != "user" && createdBy != "agent" && createdBy != "cli" {
+				return out.Error(ExitValidation,
+					fmt.Sprintf("invalid created-by '%s', must be one of: user, agent, cli", createdBy), nil)
+			}
+
+			// Create the task and its subtasks from a template
+			if templateName != "" {
+				if isRemoteMode() {
+					return out.ErrorWithSuggestion(ExitValidation,
+						"--template is not available in remote mode",
+						"Run it on the server machine", nil)
+				}
+				opts := templateAddOptions{
+					Name:      templateName,
+					Vars:      templateVars,
+					BoardRef:  boardRef,
+					Agent:     agentName,
+					CreatedBy: createdBy,
+					Epic:      epic,
+					DueDate:   dueDate,
+					Labels:    labels,
+					Assign:    assign,
+					Estimate:  estimateVal,
+				}
+				if len(args) > 0 {
+					opts.Title = args[0]
+				}
+				// Unset flags keep the template's values
+				if cmd.Flags().Changed("type") {
+					opts.Type = taskType
+				}
+				if cmd.Flags().Changed("priority") {
+					opts.Priority = priority
+				}
+				if cmd.Flags().Changed("column") {
+					opts.Column = column
+				}
+				if estimateVal < 0 {
+					return out.Error(ExitValidation,
+						fmt.Sprintf("invalid estimate '%g', must be zero or positive", estimateVal), nil)
+				}
+				return addFromTemplate(app, out, opts)
 			}
 
 			// Single task creation requires title argument
@@ -107,34 +187,18 @@ Examples:
 				return out.Error(ExitValidation,
 					fmt.Sprintf("invalid column '%s', must be one of: %v", column, ValidColumns), nil)
 			}
-
-			// Determine and validate creator
-			if createdBy == "" {
-				if agentName != "" {
-					createdBy = "agent"
-				} else {
-					// Detect if running in a TTY
-					if fileInfo, _ := os.Stdin.Stat(); (fileInfo.Mode() & os.ModeCharDevice) != 0 {
-						createdBy = "user"
-					} else {
-						createdBy = "cli"
-					}
-				}
-			} else if createdBy != "user" && createdBy != "agent" && createdBy != "cli" {
+			if estimateVal < 0 {
 				return out.Error(ExitValidation,
-					fmt.Sprintf("invalid created-by '%s', must be one of: user, agent, cli", createdBy), nil)
+					fmt.Sprintf("invalid estimate '%g', must be zero or positive", estimateVal), nil)
 			}
 
-			// Find the tasks collection
-			collection, err := app.FindCollectionByNameOrId("tasks")
+			// Create the record
+			record, err := newRecord(app, "tasks")
 			if err != nil {
 				return out.Error(ExitGeneralError,
 					"tasks collection not found - run migrations first", nil)
 			}
 
-			// Create the record
-			record := core.NewRecord(collection)
-
 			// Set custom ID if provided
 			if customID != "" {
 				// Validate custom ID format
@@ -142,7 +206,7 @@ Examples:
 					return out.Error(ExitValidation, formatCustomIDError(customID), nil)
 				}
 				// Check if task with this ID already exists (idempotency)
-				existing, err := app.FindRecordById("tasks", customID)
+				existing, err := findRecordByID(app, "tasks", customID)
 				if err == nil {
 					// Task exists, return it (idempotent behavior)
 					out.Task(existing, "Existing")
@@ -167,9 +231,73 @@ Examples:
 				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
 			}
 
-			// Get next sequence number for this board
-			var seq int
+			// Enforce the agent mode of the board
+			boardID := ""
 			if boardRecord != nil {
+				boardID = boardRecord.Id
+			}
+			caller := resolveCaller(app, agentName)
+			if err := checkPolicy(app, caller, boardID, policy.ActionCreate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+			column, err = policyColumn(app, caller, boardID, "", column)
+			if err != nil {
+				return policyDenied(out, err)
+			}
+
+			// Resolve the parent task (sub-task), using the full resolver
+			// (supports display IDs like TST-4)
+			var parentTask *core.Record
+			if parent != "" {
+				parentTask, err = resolveTask(app, parent)
+				if err != nil {
+					if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+						return out.AmbiguousError(parent, ambErr.Matches)
+					}
+					return out.Error(ExitValidation, fmt.Sprintf("invalid parent: %v", err), nil)
+				}
+			}
+
+			// Check for likely duplicates among the open tasks of the
+			// board, other than the parent
+			var duplicates []dedupe.Match
+			idx, err := dedupeOpts.index(app, boardID)
+			if err != nil {
+				return out.Error(ExitGeneralError, err.Error(), nil)
+			}
+			if idx != nil {
+				parentID := ""
+				if parentTask != nil {
+					parentID = parentTask.Id
+				}
+				duplicates = idx.Similar(title, "", parentID, dedupeOpts.Threshold)
+			}
+			if len(duplicates) > 0 {
+				switch dedupeOpts.Mode {
+				case dedupe.ModeFail:
+					return out.ErrorWithSuggestion(ExitConflict,
+						fmt.Sprintf("likely duplicate of %s", describeDuplicates(duplicates)),
+						"Reuse the existing task, or add it anyway with --dedupe warn",
+						map[string]any{"duplicates": duplicates})
+				case dedupe.ModeSkip:
+					if out.JSON {
+						out.WriteJSON(map[string]any{
+							"created":    false,
+							"skipped":    true,
+							"title":      title,
+							"duplicates": duplicates,
+						})
+					} else if !quietMode {
+						fmt.Printf("Skipped: %s (likely duplicate of %s)\n", title, describeDuplicates(duplicates))
+					}
+					return nil
+				}
+			}
+
+			// Get next sequence number for this board (the server assigns
+			// it in remote mode)
+			var seq int
+			if boardRecord != nil && !isRemoteMode() {
 				seq, err = board.GetNextSequence(app, boardRecord.Id)
 				if err != nil {
 					return out.Error(ExitGeneralError, fmt.Sprintf("failed to get sequence: %v", err), nil)
@@ -193,7 +321,9 @@ Examples:
 			}
 			if boardRecord != nil {
 				record.Set("board", boardRecord.Id)
-				record.Set("seq", seq)
+				if seq > 0 {
+					record.Set("seq", seq)
+				}
 			}
 
 			// Handle due date
@@ -206,29 +336,21 @@ Examples:
 			}
 
 			// Handle parent (sub-task)
-			if parent != "" {
-				// Resolve parent task using full resolver (supports display IDs like TST-4)
-				parentTask, err := resolver.MustResolve(app, parent)
-				if err != nil {
-					if ambErr, ok := err.(*resolver.AmbiguousError); ok {
-						return out.AmbiguousError(parent, ambErr.Matches)
-					}
-					return out.Error(ExitValidation, fmt.Sprintf("invalid parent: %v", err), nil)
-				}
+			if parentTask != nil {
 				record.Set("parent", parentTask.Id)
 			}
 
-			// Initialize history
-			history := []map[string]any{
-				{
-					"timestamp":    time.Now().UTC().Format(time.RFC3339),
-					"action":       "created",
-					"actor":        createdBy,
-					"actor_detail": agentName,
-					"changes":      nil,
-				},
+			// Handle estimate (in the board's estimate unit)
+			if estimateVal > 0 {
+				record.Set("estimate", estimateVal)
 			}
-			record.Set("history", history)
+
+			// Handle assignees ("me" expands to the current user)
+			if len(assign) > 0 {
+				record.Set("assignees", updateAssignees(nil, resolveAssigneeNames(assign), nil))
+			}
+
+			taskevent.Add(record, "created", createdBy, agentName, nil, nil)
 
 			// Save the record using hybrid approach (API first, then fallback to direct)
 			if err := saveRecordHybrid(app, record, out); err != nil {
@@ -239,15 +361,22 @@ Examples:
 			// Format display ID for output
 			var displayID string
 			if boardRecord != nil {
-				displayID = board.FormatDisplayID(boardRecord.GetString("prefix"), seq)
+				displayID = board.FormatDisplayID(boardRecord.GetString("prefix"), record.GetInt("seq"))
 			} else {
 				displayID = output.ShortID(record.Id)
 			}
 
 			if out.JSON {
-				out.Task(record, "Created")
+				if len(duplicates) > 0 {
+					out.TaskWithExtra(record, "Created", map[string]any{"duplicates": duplicates})
+				} else {
+					out.Task(record, "Created")
+				}
 			} else {
 				fmt.Printf("Created: %s [%s]\n", title, displayID)
+				if len(duplicates) > 0 {
+					warnDuplicates(displayID, duplicates)
+				}
 			}
 			return nil
 		},
@@ -280,6 +409,18 @@ Examples:
 		"Due date (ISO 8601 format: YYYY-MM-DD, or relative: 'tomorrow', 'next week')")
 	cmd.Flags().StringVar(&parent, "parent", "",
 		"Parent task ID (creates sub-task)")
+	cmd.Flags().Float64Var(&estimateVal, "estimate", 0,
+		"Estimate in the board's unit (points or hours)")
+	cmd.Flags().StringSliceVar(&assign, "assign", nil,
+		"Assign to a person or agent (repeatable, 'me' for yourself)")
+	cmd.Flags().StringVar(&templateName, "template", "",
+		"Create the task and its subtasks from a template (see 'egenskriven template')")
+	cmd.Flags().StringArrayVar(&templateVars, "var", nil,
+		"Template variable as name=value (repeatable)")
+	cmd.Flags().StringVar(&dedupeOpts.Mode, "dedupe", dedupe.ModeWarn,
+		"Handling of likely duplicates of open tasks (warn, skip, fail, off)")
+	cmd.Flags().Float64Var(&dedupeOpts.Threshold, "dedupe-threshold", dedupe.DefaultThreshold,
+		"Similarity from which a task counts as a likely duplicate (0-1)")
 
 	return cmd
 }
@@ -289,13 +430,13 @@ Examples:
 func resolveBoard(app *pocketbase.PocketBase, boardRef string) (*core.Record, error) {
 	// If explicit board reference provided, use it
 	if boardRef != "" {
-		return board.GetByNameOrPrefix(app, boardRef)
+		return findBoard(app, boardRef)
 	}
 
 	// Check config for default board
 	cfg, _ := config.LoadProjectConfig()
 	if cfg != nil && cfg.DefaultBoard != "" {
-		record, err := board.GetByNameOrPrefix(app, cfg.DefaultBoard)
+		record, err := findBoard(app, cfg.DefaultBoard)
 		if err == nil {
 			return record, nil
 		}
@@ -303,9 +444,15 @@ func resolveBoard(app *pocketbase.PocketBase, boardRef string) (*core.Record, er
 	}
 
 	// Get existing boards
-	boards, err := board.GetAll(app)
+	boards, err := allBoards(app)
+	if err != nil && isRemoteMode() {
+		return nil, fmt.Errorf("failed to load boards: %w", err)
+	}
 	if err != nil || len(boards) == 0 {
 		// No boards exist - create a default board
+		if isRemoteMode() {
+			return createRemoteDefaultBoard(app)
+		}
 		b, err := board.Create(app, board.CreateInput{
 			Name:   "Default",
 			Prefix: "DEF",
@@ -320,17 +467,32 @@ func resolveBoard(app *pocketbase.PocketBase, boardRef string) (*core.Record, er
 	return boards[0], nil
 }
 
+// createRemoteDefaultBoard creates the default board on the server, like
+// board.Create does locally.
+func createRemoteDefaultBoard(app *pocketbase.PocketBase) (*core.Record, error) {
+	record, _ := newRecord(app, "boards")
+	record.Set("name", "Default")
+	record.Set("prefix", "DEF")
+	record.Set("columns", board.DefaultColumns)
+	record.Set("next_seq", 1)
+	if err := saveRecord(app, record); err != nil {
+		return nil, fmt.Errorf("failed to create default board: %w", err)
+	}
+	return record, nil
+}
+
 // resolveTaskByID finds a task by ID or ID prefix
 func resolveTaskByID(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
 	// Try exact ID match
 	record, err := app.FindRecordById("tasks", ref)
-	if err == nil {
+	if err == nil && !trash.IsTrashed(record) {
 		return record, nil
 	}
 
 	// Try ID prefix match (for short IDs like "abc1234")
 	records, err := app.FindAllRecords("tasks",
 		dbx.NewExp("id LIKE {:prefix}", dbx.Params{"prefix": ref + "%"}),
+		trash.Exclude(app, "tasks"),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search tasks: %w", err)
@@ -352,7 +514,7 @@ func resolveTaskByID(app *pocketbase.PocketBase, ref string) (*core.Record, erro
 }
 
 // addBatch handles batch task creation from stdin or file
-func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool, filePath string, agent string, boardRef string) error {
+func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool, filePath string, agent string, boardRef string, dedupeOpts dedupeOptions) error {
 	var reader io.Reader
 
 	if useStdin {
@@ -413,8 +575,7 @@ func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool,
 	}
 
 	// Find the tasks collection
-	collection, err := app.FindCollectionByNameOrId("tasks")
-	if err != nil {
+	if _, err := newRecord(app, "tasks"); err != nil {
 		return out.Error(ExitGeneralError, "tasks collection not found - run migrations first", nil)
 	}
 
@@ -424,10 +585,28 @@ func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool,
 		return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
 	}
 
+	// Enforce the agent mode of the board for the whole batch
+	boardID := ""
+	if boardRecord != nil {
+		boardID = boardRecord.Id
+	}
+	caller := resolveCaller(app, agent)
+	if err := checkPolicy(app, caller, boardID, policy.ActionCreate).Err(); err != nil {
+		return policyDenied(out, err)
+	}
+
+	// Check new tasks against the open tasks of the board and each other
+	idx, err := dedupeOpts.index(app, boardID)
+	if err != nil {
+		return out.Error(ExitGeneralError, err.Error(), nil)
+	}
+
 	// Create all tasks
 	var created []*core.Record
 	var createdDisplayIDs []string
 	var errors []string
+	duplicates := make(map[string][]dedupe.Match)
+	var skipped []map[string]any
 
 	for i, input := range inputs {
 		if input.Title == "" {
@@ -456,8 +635,13 @@ func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool,
 				i+1, input.Title, column, ValidColumns))
 			continue
 		}
+		column, err = policyColumn(app, caller, boardID, "", column)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("task %d (%s): %v", i+1, input.Title, err))
+			continue
+		}
 
-		record := core.NewRecord(collection)
+		record, _ := newRecord(app, "tasks")
 
 		if input.ID != "" {
 			// Validate custom ID format
@@ -467,7 +651,7 @@ func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool,
 				continue
 			}
 			// Check idempotency
-			existing, err := app.FindRecordById("tasks", input.ID)
+			existing, err := findRecordByID(app, "tasks", input.ID)
 			if err == nil {
 				created = append(created, existing)
 				continue
@@ -475,6 +659,26 @@ func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool,
 			record.Id = input.ID
 		}
 
+		// Handle likely duplicates
+		var matches []dedupe.Match
+		if idx != nil {
+			matches = idx.Similar(input.Title, input.Description, "", dedupeOpts.Threshold)
+		}
+		if len(matches) > 0 {
+			switch dedupeOpts.Mode {
+			case dedupe.ModeFail:
+				errors = append(errors, fmt.Sprintf("task %d (%s): likely duplicate of %s",
+					i+1, input.Title, describeDuplicates(matches)))
+				continue
+			case dedupe.ModeSkip:
+				skipped = append(skipped, map[string]any{
+					"title":      input.Title,
+					"duplicates": matches,
+				})
+				continue
+			}
+		}
+
 		// Set fields with validated values
 		record.Set("title", input.Title)
 		record.Set("type", taskType)
@@ -510,6 +714,21 @@ func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool,
 			record.Set("due_date", parsedDate)
 		}
 
+		// Handle estimate
+		if input.Estimate < 0 {
+			errors = append(errors, fmt.Sprintf("task %d (%s): invalid estimate '%g': must be zero or positive",
+				i+1, input.Title, input.Estimate))
+			continue
+		}
+		if input.Estimate > 0 {
+			record.Set("estimate", input.Estimate)
+		}
+
+		// Handle assignees
+		if len(input.Assignees) > 0 {
+			record.Set("assignees", updateAssignees(nil, resolveAssigneeNames(input.Assignees), nil))
+		}
+
 		// Set creator info
 		createdBy := "cli"
 		if agent != "" {
@@ -519,38 +738,37 @@ func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool,
 		record.Set("created_by", createdBy)
 
 		// Set board and sequence
-		var displayID string
 		if boardRecord != nil {
-			seq, err := board.GetNextSequence(app, boardRecord.Id)
-			if err != nil {
-				errors = append(errors, fmt.Sprintf("task %d (%s): failed to get sequence: %v", i+1, input.Title, err))
-				continue
-			}
 			record.Set("board", boardRecord.Id)
-			record.Set("seq", seq)
-			displayID = board.FormatDisplayID(boardRecord.GetString("prefix"), seq)
-		} else {
-			displayID = output.ShortID(record.Id)
+			// The server assigns the sequence in remote mode
+			if !isRemoteMode() {
+				seq, err := board.GetNextSequence(app, boardRecord.Id)
+				if err != nil {
+					errors = append(errors, fmt.Sprintf("task %d (%s): failed to get sequence: %v", i+1, input.Title, err))
+					continue
+				}
+				record.Set("seq", seq)
+			}
 		}
 
-		// Initialize history
-		history := []map[string]any{
-			{
-				"timestamp":    time.Now().UTC().Format(time.RFC3339),
-				"action":       "created",
-				"actor":        createdBy,
-				"actor_detail": agent,
-				"changes":      nil,
-			},
-		}
-		record.Set("history", history)
+		taskevent.Add(record, "created", createdBy, agent, nil, nil)
 
 		if err := saveRecordHybrid(app, record, out); err != nil {
 			errors = append(errors, fmt.Sprintf("task %d (%s): failed to save: %v", i+1, input.Title, err))
 			continue
 		}
+		displayID := output.ShortID(record.Id)
+		if boardRecord != nil {
+			displayID = board.FormatDisplayID(boardRecord.GetString("prefix"), record.GetInt("seq"))
+		}
 		created = append(created, record)
 		createdDisplayIDs = append(createdDisplayIDs, displayID)
+		if idx != nil {
+			idx.Add(record)
+		}
+		if len(matches) > 0 {
+			duplicates[record.Id] = matches
+		}
 	}
 
 	// Output results
@@ -571,14 +789,22 @@ func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool,
 				taskData["board"] = record.GetString("board")
 				taskData["seq"] = record.GetInt("seq")
 			}
+			if matches := duplicates[record.Id]; len(matches) > 0 {
+				taskData["duplicates"] = matches
+			}
 			tasks = append(tasks, taskData)
 		}
-		return json.NewEncoder(os.Stdout).Encode(map[string]any{
+		result := map[string]any{
 			"created": len(created),
 			"failed":  len(errors),
 			"tasks":   tasks,
 			"errors":  errors,
-		})
+		}
+		if dedupeOpts.Mode == dedupe.ModeSkip {
+			result["skipped"] = len(skipped)
+			result["skipped_tasks"] = skipped
+		}
+		return json.NewEncoder(os.Stdout).Encode(result)
 	}
 
 	// Human output
@@ -588,6 +814,12 @@ func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool,
 			displayID = createdDisplayIDs[i]
 		}
 		fmt.Printf("Created: %s [%s]\n", record.GetString("title"), displayID)
+		if matches := duplicates[record.Id]; len(matches) > 0 {
+			warnDuplicates(displayID, matches)
+		}
+	}
+	for _, s := range skipped {
+		fmt.Printf("Skipped: %s (likely duplicate of %s)\n", s["title"], describeDuplicates(s["duplicates"].([]dedupe.Match)))
 	}
 
 	if len(errors) > 0 {
@@ -598,6 +830,9 @@ func addBatch(app *pocketbase.PocketBase, out *output.Formatter, useStdin bool,
 	}
 
 	fmt.Printf("\nCreated %d tasks", len(created))
+	if len(skipped) > 0 {
+		fmt.Printf(", %d skipped", len(skipped))
+	}
 	if len(errors) > 0 {
 		fmt.Printf(", %d failed", len(errors))
 	}
@@ -639,6 +874,11 @@ func formatCustomIDError(id string) string {
 // saveRecordHybrid attempts to save a record via HTTP API first (for real-time updates),
 // falling back to direct database access if the server is not running.
 func saveRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *output.Formatter) error {
+	// In remote mode the server is the only database, without fallback
+	if isRemoteMode() {
+		return remoteRejected(app, saveRecord(app, record))
+	}
+
 	// If direct mode is enabled, skip API attempt
 	if isDirectMode() {
 		verboseLog("Using direct database access (--direct flag)")
@@ -658,7 +898,7 @@ func saveRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *outp
 		if err != nil {
 			// Check if it's a validation error (don't fall back)
 			if apiErr, ok := IsAPIError(err); ok && apiErr.IsValidationError() {
-				return fmt.Errorf("validation error: %s", apiErr.Message)
+				return apiRejectedError(app, "validation error", apiErr)
 			}
 
 			// Network or other error - fall back with warning
@@ -667,6 +907,8 @@ func saveRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *outp
 			return app.Save(record)
 		}
 
+		// The server logged the queued events
+		taskevent.Clear(record)
 		verboseLog("Task created via API (real-time updates enabled)")
 		return nil
 	}
@@ -679,6 +921,11 @@ func saveRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *outp
 // updateRecordHybrid attempts to update a record via HTTP API first (for real-time updates),
 // falling back to direct database access if the server is not running.
 func updateRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *output.Formatter) error {
+	// In remote mode the server is the only database, without fallback
+	if isRemoteMode() {
+		return remoteRejected(app, saveRecord(app, record))
+	}
+
 	// If direct mode is enabled, skip API attempt
 	if isDirectMode() {
 		verboseLog("Using direct database access (--direct flag)")
@@ -698,7 +945,7 @@ func updateRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *ou
 		if err != nil {
 			// Check if it's a validation error (don't fall back)
 			if apiErr, ok := IsAPIError(err); ok && apiErr.IsValidationError() {
-				return fmt.Errorf("validation error: %s", apiErr.Message)
+				return apiRejectedError(app, "validation error", apiErr)
 			}
 
 			// Network or other error - fall back with warning
@@ -707,6 +954,8 @@ func updateRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *ou
 			return app.Save(record)
 		}
 
+		// The server logged the queued events
+		taskevent.Clear(record)
 		verboseLog("Task updated via API (real-time updates enabled)")
 		return nil
 	}
@@ -716,13 +965,19 @@ func updateRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *ou
 	return app.Save(record)
 }
 
-// deleteRecordHybrid attempts to delete a record via HTTP API first (for real-time updates),
-// falling back to direct database access if the server is not running.
-func deleteRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *output.Formatter) error {
+// deleteRecordHybrid moves a task to the trash via HTTP API first (for
+// real-time updates), falling back to direct database access if the server
+// is not running. The server turns API deletes into trash moves.
+func deleteRecordHybrid(app *pocketbase.PocketBase, record *core.Record, actor string, out *output.Formatter) error {
+	// In remote mode the server is the only database, without fallback
+	if isRemoteMode() {
+		return remoteRejected(app, remoteClient().DeleteRecord("tasks", record.Id))
+	}
+
 	// If direct mode is enabled, skip API attempt
 	if isDirectMode() {
 		verboseLog("Using direct database access (--direct flag)")
-		return app.Delete(record)
+		return trash.Task(app, record, "cli", actor)
 	}
 
 	// Try HTTP API first
@@ -735,13 +990,13 @@ func deleteRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *ou
 		if err != nil {
 			// Check if it's a validation error (don't fall back)
 			if apiErr, ok := IsAPIError(err); ok && apiErr.IsValidationError() {
-				return fmt.Errorf("error: %s", apiErr.Message)
+				return apiRejectedError(app, "error", apiErr)
 			}
 
 			// Network or other error - fall back with warning
 			warnLog("API request failed, falling back to direct database: %v", err)
 			verboseLog("Falling back to direct database access")
-			return app.Delete(record)
+			return trash.Task(app, record, "cli", actor)
 		}
 
 		verboseLog("Task deleted via API (real-time updates enabled)")
@@ -750,7 +1005,35 @@ func deleteRecordHybrid(app *pocketbase.PocketBase, record *core.Record, out *ou
 
 	// Server not running - fall back to direct
 	verboseLog("Server not running, using direct database access")
-	return app.Delete(record)
+	return trash.Task(app, record, "cli", actor)
+}
+
+// apiRejectedError formats a client error returned by the API.
+// With auth enabled, PocketBase reports rule failures as generic validation
+// or not-found errors, so a hint about the API token is added. In remote
+// mode, where the auth setting of the server is unknown, the hint is added
+// to authorization errors.
+func apiRejectedError(app *pocketbase.PocketBase, prefix string, apiErr *APIError) error {
+	var authHint bool
+	if isRemoteMode() {
+		authHint = apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden
+	} else {
+		authHint = auth.Enabled(app)
+	}
+	if authHint {
+		return fmt.Errorf("%s: %s (API auth is enabled: set $%s to a token with access to this board)",
+			prefix, apiErr.Message, config.TokenEnvVar)
+	}
+	return fmt.Errorf("%s: %s", prefix, apiErr.Message)
+}
+
+// remoteRejected formats the client errors of a request to the server in
+// remote mode like apiRejectedError.
+func remoteRejected(app *pocketbase.PocketBase, err error) error {
+	if apiErr, ok := IsAPIError(err); ok && apiErr.IsValidationError() {
+		return apiRejectedError(app, "error", apiErr)
+	}
+	return err
 }
 
 // recordToTaskData converts a core.Record to TaskData for API calls.
@@ -781,15 +1064,7 @@ func recordToTaskData(record *core.Record) TaskData {
 	// Get blocked_by as string slice (use getTaskBlockedBy for proper type handling)
 	blockedBy := getTaskBlockedBy(record)
 
-	// Get history
-	var history []any
-	if rawHistory := record.Get("history"); rawHistory != nil {
-		if h, ok := rawHistory.([]map[string]any); ok {
-			for _, entry := range h {
-				history = append(history, entry)
-			}
-		}
-	}
+	estimateValue := record.GetFloat("estimate")
 
 	return TaskData{
 		ID:             record.Id,
@@ -808,6 +1083,9 @@ func recordToTaskData(record *core.Record) TaskData {
 		Seq:            record.GetInt("seq"),
 		Parent:         record.GetString("parent"),
 		DueDate:        record.GetString("due_date"),
-		History:        history,
+		Estimate:       &estimateValue,
+		Assignees:      getTaskAssignees(record),
+		Checklist:      checklist.Get(record),
+		Events:         taskevent.Pending(record),
 	}
 }
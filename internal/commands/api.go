@@ -0,0 +1,1425 @@
+package commands
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/auth"
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/proposal"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasklink"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+	"github.com/ramtinJ95/EgenSkriven/internal/view"
+)
+
+// APIPrefix is the path under which the server exposes the operations of
+// the CLI that are more than record CRUD.
+const APIPrefix = "/api/egenskriven"
+
+// openAPISpec documents the routes of RegisterRoutes.
+//
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// commandError is an error of an operation shared by a command and its
+// REST route, carrying the exit code of the command. Routes answer with
+// the matching HTTP status and data.
+type commandError struct {
+	code       int
+	message    string
+	suggestion string
+	data       map[string]any
+}
+
+func (e *commandError) Error() string {
+	return e.message
+}
+
+func newCommandError(code int, format string, args ...any) error {
+	return &commandError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// commandFailed reports an error of a shared operation and exits with its
+// exit code, or ExitGeneralError for unexpected errors.
+func commandFailed(out *output.Formatter, err error) error {
+	var ce *commandError
+	if errors.As(err, &ce) {
+		if ce.suggestion != "" {
+			return out.ErrorWithSuggestion(ce.code, ce.message, ce.suggestion, ce.data)
+		}
+		return out.Error(ce.code, ce.message, ce.data)
+	}
+	return out.Error(ExitGeneralError, err.Error(), nil)
+}
+
+// RegisterRoutes registers the REST routes sharing the logic of the
+// list, show, suggest, context, resume, block, link, unlink, log, time,
+// report, export, sprint, view, archive, trash, undo, redo, attachments,
+// and proposals commands, and of task references, so the web UI and
+// remote clients get the same answers as the CLI:
+//
+//	POST /api/egenskriven/list
+//	GET  /api/egenskriven/tasks/{id}
+//	GET  /api/egenskriven/suggest?limit=&for=
+//	GET  /api/egenskriven/context
+//	GET  /api/egenskriven/resolve/{ref}
+//	POST /api/egenskriven/tasks/{id}/resume
+//	POST /api/egenskriven/tasks/{id}/block
+//	POST /api/egenskriven/tasks/{id}/link
+//	POST /api/egenskriven/tasks/{id}/unlink
+//	GET  /api/egenskriven/log?task=&board=&actor=&since=&limit=
+//	POST /api/egenskriven/tasks/{id}/time/{start,stop,log}
+//	GET  /api/egenskriven/time/report?by=&since=&board=
+//	GET  /api/egenskriven/reports/flow?board=&since=
+//	GET  /api/egenskriven/export?format=&board=&include_archived=&archived=&epic=&include_comments=&since=&entry=
+//	GET  /api/egenskriven/sprints?board=
+//	POST /api/egenskriven/sprints
+//	GET  /api/egenskriven/sprints/{ref}?board=
+//	POST /api/egenskriven/sprints/{ref}/{start,close}
+//	POST /api/egenskriven/tasks/{id}/sprint
+//	GET  /api/egenskriven/views?board=
+//	POST /api/egenskriven/views
+//	GET  /api/egenskriven/views/{ref}?board=
+//	POST /api/egenskriven/views/{ref}/{update,favorite,delete}
+//	POST /api/egenskriven/tasks/{id}/archive
+//	POST /api/egenskriven/tasks/{id}/unarchive
+//	POST /api/egenskriven/archive/run
+//	GET  /api/egenskriven/trash?board=
+//	POST /api/egenskriven/trash/restore
+//	POST /api/egenskriven/trash/purge
+//	POST /api/egenskriven/undo
+//	POST /api/egenskriven/redo
+//	GET  /api/egenskriven/tasks/{id}/attachments
+//	GET  /api/egenskriven/proposals?board=&status=
+//	GET  /api/egenskriven/proposals/{ref}
+//	POST /api/egenskriven/proposals/{ref}/{approve,reject}
+//	GET  /api/egenskriven/openapi.yaml
+//
+// Responses are the JSON the commands print with --json, except that list
+// and show answer with the task records, which the CLI formats itself in
+// remote mode, and export answers with the exported file. Errors use the PocketBase error format, with the exit code
+// of the command as data.exit_code. With auth enabled the routes need an
+// API token, the write routes one with write scope, and board-restricted
+// tokens only see their board.
+func RegisterRoutes(app *pocketbase.PocketBase) {
+	app.OnServe().BindFunc(func(e *core.ServeEvent) error {
+		g := e.Router.Group(APIPrefix)
+		g.POST("/list", apiList(app))
+		g.GET("/tasks/{id}", apiShow(app))
+		g.GET("/suggest", apiSuggest(app))
+		g.GET("/context", apiContext(app))
+		g.GET("/resolve/{ref}", apiResolve(app))
+		g.POST("/tasks/{id}/resume", apiResume(app))
+		g.POST("/tasks/{id}/block", apiBlock(app))
+		g.POST("/tasks/{id}/link", apiLink(app))
+		g.POST("/tasks/{id}/unlink", apiUnlink(app))
+		g.GET("/log", apiLog(app))
+		g.POST("/tasks/{id}/time/{action}", apiTrackTime(app))
+		g.GET("/time/report", apiTimeReport(app))
+		g.GET("/reports/flow", apiFlowReport(app))
+		g.GET("/export", apiExport(app))
+		g.GET("/sprints", apiSprints(app))
+		g.POST("/sprints", apiCreateSprint(app))
+		g.GET("/sprints/{ref}", apiShowSprint(app))
+		g.POST("/sprints/{ref}/{action}", apiSprintAction(app))
+		g.POST("/tasks/{id}/sprint", apiTaskSprint(app))
+		g.GET("/views", apiViews(app))
+		g.POST("/views", apiCreateView(app))
+		g.GET("/views/{ref}", apiShowView(app))
+		g.POST("/views/{ref}/{action}", apiViewAction(app))
+		g.POST("/tasks/{id}/archive", apiArchive(app, false))
+		g.POST("/tasks/{id}/unarchive", apiArchive(app, true))
+		g.POST("/archive/run", apiRunArchive(app))
+		g.GET("/trash", apiTrash(app))
+		g.POST("/trash/restore", apiRestoreTrash(app))
+		g.POST("/trash/purge", apiPurgeTrash(app))
+		g.POST("/undo", apiUndo(app, false))
+		g.POST("/redo", apiUndo(app, true))
+		g.GET("/tasks/{id}/attachments", apiAttachments(app))
+		g.GET("/proposals", apiProposals(app))
+		g.GET("/proposals/{ref}", apiShowProposal(app))
+		g.POST("/proposals/{ref}/{action}", apiReviewProposal(app))
+		g.GET("/openapi.yaml", func(re *core.RequestEvent) error {
+			return re.Blob(http.StatusOK, "application/yaml", openAPISpec)
+		})
+		return e.Next()
+	})
+}
+
+// apiRoute adapts a route returning the value to answer with as JSON.
+// Errors are answered in the PocketBase error format, with the exit code
+// of the command as data.exit_code.
+func apiRoute(fn func(re *core.RequestEvent) (any, error)) func(*core.RequestEvent) error {
+	return func(re *core.RequestEvent) error {
+		result, err := fn(re)
+		if err != nil {
+			return apiError(re, err)
+		}
+		return re.JSON(http.StatusOK, result)
+	}
+}
+
+// apiList answers POST /list with the tasks matching the filters of the
+// list command.
+func apiList(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var opts listOptions
+		if err := apiBody(re, &opts); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+
+		// Board-restricted tokens only list their board
+		if boardID != "" {
+			if opts.Board == "" {
+				opts.Board = boardID
+			} else if b, err := board.GetByNameOrPrefix(app, opts.Board); err != nil || b.Id != boardID {
+				return nil, newCommandError(ExitValidation, "invalid board: board not found: %s", opts.Board)
+			}
+		}
+
+		// "me" is the account of the API token
+		if strings.EqualFold(strings.TrimSpace(opts.Assignee), AssigneeMe) {
+			opts.Assignee = policy.ActorFromAuth(re.Auth).Name
+			if opts.Assignee == "" {
+				return nil, newCommandError(ExitValidation, "cannot determine who 'me' is; pass a name")
+			}
+		}
+
+		tasks, err := findListTasks(app, opts)
+		if err != nil {
+			return nil, err
+		}
+		if tasks == nil {
+			tasks = []*core.Record{}
+		}
+		return map[string]any{"tasks": tasks}, nil
+	})
+}
+
+// apiShow answers GET /tasks/{id} with a task, its sub-tasks, and the
+// related data the show command prints.
+func apiShow(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		task, err := apiTask(app, re.Request.PathValue("id"), boardID)
+		if err != nil {
+			return nil, err
+		}
+
+		subtasks, extras := taskDetail(app, task)
+		if subtasks == nil {
+			subtasks = []*core.Record{}
+		}
+		return map[string]any{"task": task, "subtasks": subtasks, "extras": extras}, nil
+	})
+}
+
+// apiSuggest answers GET /suggest like 'suggest --json'.
+func apiSuggest(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+
+		query := re.Request.URL.Query()
+		limit := 5
+		if v := query.Get("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+				return nil, newCommandError(ExitValidation, "invalid limit %q", v)
+			}
+		}
+
+		// "me" is the account of the API token
+		forName := strings.TrimSpace(query.Get("for"))
+		if strings.EqualFold(forName, AssigneeMe) {
+			forName = policy.ActorFromAuth(re.Auth).Name
+			if forName == "" {
+				return nil, newCommandError(ExitValidation, "cannot determine who 'me' is; pass a name")
+			}
+		}
+
+		tasks, err := apiTasks(app, boardID)
+		if err != nil {
+			return nil, err
+		}
+		return SuggestResponse{Suggestions: buildSuggestions(tasks, limit, forName)}, nil
+	})
+}
+
+// apiContext answers GET /context like 'context --json'.
+func apiContext(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		tasks, err := apiTasks(app, boardID)
+		if err != nil {
+			return nil, err
+		}
+		return buildContextSummary(tasks), nil
+	})
+}
+
+// apiResolve answers GET /resolve/{ref} with the task a reference names,
+// resolved like task references on the command line.
+func apiResolve(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		task, err := apiTask(app, re.Request.PathValue("ref"), boardID)
+		if err != nil {
+			return nil, err
+		}
+
+		boardsMap := make(map[string]*core.Record)
+		if b, err := app.FindRecordById("boards", task.GetString("board")); err == nil {
+			boardsMap[b.Id] = b
+		}
+		return map[string]any{"task": output.TaskMap(task, boardsMap)}, nil
+	})
+}
+
+// resumeRequest is the body of POST /tasks/{id}/resume.
+type resumeRequest struct {
+	Minimal bool   `json:"minimal"`
+	Prompt  string `json:"prompt"`
+	// Start moves the task to in_progress and marks its session active,
+	// as 'resume --exec' does before running the command. The command
+	// itself is never run by the server.
+	Start bool `json:"start"`
+}
+
+// apiResume answers POST /tasks/{id}/resume like 'resume --json'.
+func apiResume(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body resumeRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, body.Start)
+		if err != nil {
+			return nil, err
+		}
+		task, err := apiTask(app, re.Request.PathValue("id"), boardID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Starting moves the task, which the board's agent mode may deny
+		if body.Start {
+			if err := checkTaskPolicy(app, policy.ActorFromAuth(re.Auth), task, policy.ActionMove).Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		info, err := prepareResume(app, task, body.Minimal, body.Prompt)
+		if err != nil {
+			return nil, err
+		}
+		if body.Start {
+			if err := startResume(app, task, info.SessionRef); err != nil {
+				return nil, fmt.Errorf("failed to update task: %w", err)
+			}
+		}
+		return info.result(), nil
+	})
+}
+
+// blockRequest is the body of POST /tasks/{id}/block.
+type blockRequest struct {
+	Question string `json:"question"`
+	// Agent names the blocking agent when the API token is not an
+	// agent's; defaults.agent of the server's config otherwise.
+	Agent string `json:"agent"`
+}
+
+// apiBlock answers POST /tasks/{id}/block like 'block --json'.
+func apiBlock(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body blockRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		task, err := apiTask(app, re.Request.PathValue("id"), boardID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Blocking is always done by an agent: the token's, or the named one
+		caller := policy.ActorFromAuth(re.Auth)
+		if !caller.IsAgent() {
+			name := strings.TrimSpace(body.Agent)
+			if name == "" {
+				name = getDefaultAgentName()
+			}
+			caller = policy.Agent(name)
+		}
+
+		commentId, err := blockTask(app, task, strings.TrimSpace(body.Question), caller)
+		if err != nil {
+			return nil, err
+		}
+		return blockResult(app, task, commentId), nil
+	})
+}
+
+// linkRequest is the body of POST /tasks/{id}/link.
+type linkRequest struct {
+	Target string `json:"target"`
+	Type   string `json:"type"`
+	OneWay bool   `json:"one_way"`
+	// Close closes a duplicate, as 'link --close' does
+	Close bool   `json:"close"`
+	Agent string `json:"agent"`
+}
+
+// apiLink answers POST /tasks/{id}/link like 'link --json'.
+func apiLink(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body linkRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		linkType, err := tasklink.ParseType(body.Type)
+		if err != nil {
+			return nil, newCommandError(ExitValidation, "%s", err.Error())
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		source, err := apiTask(app, re.Request.PathValue("id"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		target, err := apiTask(app, body.Target, boardID)
+		if err != nil {
+			return nil, err
+		}
+
+		caller := apiActor(re, body.Agent)
+		if err := checkTaskPolicy(app, caller, source, policy.ActionUpdate).Err(); err != nil {
+			return nil, err
+		}
+		return linkTasks(app, caller, source, target, linkType, body.OneWay, body.Close)
+	})
+}
+
+// unlinkRequest is the body of POST /tasks/{id}/unlink.
+type unlinkRequest struct {
+	Other string `json:"other"`
+	// Type limits the links removed to one type (all types if empty)
+	Type  string `json:"type"`
+	Agent string `json:"agent"`
+}
+
+// apiUnlink answers POST /tasks/{id}/unlink like 'unlink --json'.
+func apiUnlink(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body unlinkRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		linkType := ""
+		if body.Type != "" {
+			t, err := tasklink.ParseType(body.Type)
+			if err != nil {
+				return nil, newCommandError(ExitValidation, "%s", err.Error())
+			}
+			linkType = t
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		task, err := apiTask(app, re.Request.PathValue("id"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		other, err := apiTask(app, body.Other, boardID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkTaskPolicy(app, apiActor(re, body.Agent), task, policy.ActionUpdate).Err(); err != nil {
+			return nil, err
+		}
+		return unlinkTasks(app, task, other, linkType)
+	})
+}
+
+// apiLog answers GET /log?task=&board=&actor=&since=&limit= like
+// 'log --json'. since is an RFC 3339 time.
+func apiLog(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+
+		query := re.Request.URL.Query()
+		q := logQuery{Task: query.Get("task"), Board: query.Get("board"), Actor: query.Get("actor")}
+		if v := query.Get("since"); v != "" {
+			if q.Since, err = time.Parse(time.RFC3339, v); err != nil {
+				return nil, newCommandError(ExitValidation, "invalid since %q", v)
+			}
+		}
+		if v := query.Get("limit"); v != "" {
+			if q.Limit, err = strconv.Atoi(v); err != nil || q.Limit < 0 {
+				return nil, newCommandError(ExitValidation, "invalid limit %q", v)
+			}
+		}
+
+		entries, err := findLog(app, q, boardID)
+		if err != nil {
+			var ambErr *resolver.AmbiguousError
+			if errors.As(err, &ambErr) {
+				return nil, apiAmbiguous(q.Task, ambErr.Matches)
+			}
+			return nil, err
+		}
+		return logResult(entries), nil
+	})
+}
+
+// timeRequest is the body of POST /tasks/{id}/time/{action}.
+type timeRequest struct {
+	Actor    string    `json:"actor"`    // who works on the task (start, log)
+	Note     string    `json:"note"`     // stop, log
+	Duration string    `json:"duration"` // log, e.g. 1h30m
+	EndedAt  time.Time `json:"ended_at"` // log, default now
+	Agent    string    `json:"agent"`
+}
+
+// apiTrackTime answers POST /tasks/{id}/time/{action}, where action is
+// start, stop, or log, like 'time <action> --json'.
+func apiTrackTime(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body timeRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		task, err := apiTask(app, re.Request.PathValue("id"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkTaskPolicy(app, apiActor(re, body.Agent), task, policy.ActionUpdate).Err(); err != nil {
+			return nil, err
+		}
+
+		actor := strings.TrimSpace(body.Actor)
+		if actor == "" {
+			actor = apiActor(re, body.Agent).Name
+		}
+
+		var entry *core.Record
+		switch action := re.Request.PathValue("action"); action {
+		case "start":
+			entry, err = startTimer(app, task, actor)
+		case "stop":
+			entry, err = stopTimer(app, task, body.Note)
+		case "log":
+			duration, parseErr := time.ParseDuration(body.Duration)
+			if parseErr != nil || duration <= 0 {
+				return nil, newCommandError(ExitValidation, "invalid duration '%s' (use e.g. 30m, 1h30m, 1.5h)", body.Duration)
+			}
+			endedAt := body.EndedAt
+			if endedAt.IsZero() {
+				endedAt = time.Now()
+			}
+			entry, err = logTime(app, task, actor, duration, endedAt, body.Note)
+		default:
+			return nil, newCommandError(ExitNotFound, "unknown time action %q (use start, stop, or log)", action)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return timeResult(app, task, entry), nil
+	})
+}
+
+// apiTimeReport answers GET /time/report?by=&since=&board= like
+// 'time report --json'. since is an RFC 3339 time.
+func apiTimeReport(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+
+		query := re.Request.URL.Query()
+		groupBy := query.Get("by")
+		if groupBy == "" {
+			groupBy = "task"
+		}
+		if !containsString(validTimeReportGroups, groupBy) {
+			return nil, newCommandError(ExitValidation, "invalid --by %q: must be one of %v", groupBy, validTimeReportGroups)
+		}
+		var since time.Time
+		if v := query.Get("since"); v != "" {
+			if since, err = time.Parse(time.RFC3339, v); err != nil {
+				return nil, newCommandError(ExitValidation, "invalid since %q", v)
+			}
+		}
+		if ref := query.Get("board"); ref != "" {
+			b, err := apiBoard(app, ref, boardID)
+			if err != nil {
+				return nil, err
+			}
+			boardID = b.Id
+		}
+
+		rows, err := buildTimeReport(app, groupBy, since, boardID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build report: %w", err)
+		}
+		return timeReportResult(groupBy, since, rows), nil
+	})
+}
+
+// apiFlowReport answers GET /reports/flow?board=&since= like
+// 'report flow --json'. since is RFC 3339. Board-restricted tokens get
+// the report of their board.
+func apiFlowReport(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+
+		query := re.Request.URL.Query()
+		var since time.Time
+		if v := query.Get("since"); v != "" {
+			if since, err = time.Parse(time.RFC3339, v); err != nil {
+				return nil, newCommandError(ExitValidation, "invalid since %q", v)
+			}
+		}
+		var b *core.Record
+		if ref := query.Get("board"); ref != "" || boardID != "" {
+			if b, err = apiRequestBoard(app, ref, boardID); err != nil {
+				return nil, err
+			}
+		}
+		return flowReportResult(app, b, since, time.Now())
+	})
+}
+
+// exportSummaryHeader is the response header of GET /export with the
+// summary line the export command prints.
+const exportSummaryHeader = "X-Egenskriven-Export-Summary"
+
+// apiExport answers GET /export?format=&board=&... with the file the
+// export command writes for the same flags, and its summary line in the
+// X-Egenskriven-Export-Summary header. since is RFC 3339. The json, zip,
+// and tar formats cover every board, so board-restricted tokens may only
+// export the other formats, of their board.
+func apiExport(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return func(re *core.RequestEvent) error {
+		opts, err := apiExportOptions(app, re)
+		if err != nil {
+			return apiError(re, err)
+		}
+
+		// Buffered so that a failed export still answers with an error
+		var buf bytes.Buffer
+		summary, err := writeExport(app, opts, &buf)
+		if err != nil {
+			return apiError(re, err)
+		}
+		re.Response.Header().Set(exportSummaryHeader, summary)
+		return re.Blob(http.StatusOK, exportContentType(opts.format), buf.Bytes())
+	}
+}
+
+// apiExportOptions reads the export flags of a GET /export request.
+func apiExportOptions(app *pocketbase.PocketBase, re *core.RequestEvent) (exportOptions, error) {
+	boardID, err := apiCaller(re, false)
+	if err != nil {
+		return exportOptions{}, err
+	}
+
+	query := re.Request.URL.Query()
+	opts := exportOptions{
+		format:          query.Get("format"),
+		board:           query.Get("board"),
+		mode:            archive.ModeFor(query.Get("include_archived") == "true", query.Get("archived") == "true"),
+		epic:            query.Get("epic"),
+		includeComments: query.Get("include_comments") == "true",
+		entry:           query.Get("entry"),
+	}
+	if opts.format == "" {
+		opts.format = "json"
+	}
+	if v := query.Get("since"); v != "" {
+		if opts.since, err = time.Parse(time.RFC3339, v); err != nil {
+			return exportOptions{}, newCommandError(ExitValidation, "invalid since %q", v)
+		}
+	}
+	if err := validateExport(&opts); err != nil {
+		return exportOptions{}, err
+	}
+
+	if boardID != "" {
+		if opts.format == "json" || isBundleFormat(opts.format) {
+			return exportOptions{}, re.ForbiddenError("Exporting all data needs an API token for all boards.", nil)
+		}
+		b, err := apiRequestBoard(app, opts.board, boardID)
+		if err != nil {
+			return exportOptions{}, err
+		}
+		opts.board = b.GetString("prefix")
+	}
+	return opts, nil
+}
+
+// sprintRequest is the body of POST /sprints and
+// POST /sprints/{ref}/{action}.
+type sprintRequest struct {
+	Board string `json:"board"`
+	Name  string `json:"name"`  // create
+	Start string `json:"start"` // create, YYYY-MM-DD
+	End   string `json:"end"`   // create, YYYY-MM-DD
+	Goal  string `json:"goal"`  // create
+	// Carry carries the unfinished tasks into the next planned sprint,
+	// CarryTo into the named one (close)
+	Carry   bool   `json:"carry"`
+	CarryTo string `json:"carry_to"`
+	Agent   string `json:"agent"`
+}
+
+// apiSprints answers GET /sprints?board= like 'sprint list --json'.
+func apiSprints(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		b, err := apiRequestBoard(app, re.Request.URL.Query().Get("board"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		return sprintList(app, b)
+	})
+}
+
+// apiShowSprint answers GET /sprints/{ref}?board= like
+// 'sprint show --json'.
+func apiShowSprint(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		b, err := apiRequestBoard(app, re.Request.URL.Query().Get("board"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		return showSprint(app, b, re.Request.PathValue("ref"))
+	})
+}
+
+// apiCreateSprint answers POST /sprints like 'sprint create --json'.
+func apiCreateSprint(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body sprintRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		b, err := apiRequestBoard(app, body.Board, boardID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPolicy(app, apiActor(re, body.Agent), b.Id, policy.ActionCreate).Err(); err != nil {
+			return nil, err
+		}
+		return createSprint(app, b, body)
+	})
+}
+
+// apiSprintAction answers POST /sprints/{ref}/{action}, where action is
+// start or close, like 'sprint <action> --json'.
+func apiSprintAction(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body sprintRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		action := re.Request.PathValue("action")
+		if action != "start" && action != "close" {
+			return nil, newCommandError(ExitNotFound, "unknown sprint action %q (use start or close)", action)
+		}
+		b, err := apiRequestBoard(app, body.Board, boardID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPolicy(app, apiActor(re, body.Agent), b.Id, policy.ActionUpdate).Err(); err != nil {
+			return nil, err
+		}
+
+		ref := re.Request.PathValue("ref")
+		if action == "start" {
+			return startSprint(app, b, ref)
+		}
+		return closeSprint(app, b, ref, body.CarryTo, body.Carry)
+	})
+}
+
+// taskSprintRequest is the body of POST /tasks/{id}/sprint.
+type taskSprintRequest struct {
+	// Sprint is an ID, name, or "current" on the task's board; empty
+	// removes the task from its sprint
+	Sprint string `json:"sprint"`
+	Agent  string `json:"agent"`
+}
+
+// apiTaskSprint answers POST /tasks/{id}/sprint, moving a task into a
+// sprint or out of its sprint, like one task of 'sprint add' or
+// 'sprint remove'.
+func apiTaskSprint(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body taskSprintRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		task, err := apiTask(app, re.Request.PathValue("id"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkTaskPolicy(app, apiActor(re, body.Agent), task, policy.ActionUpdate).Err(); err != nil {
+			return nil, err
+		}
+		return setSprint(app, task, strings.TrimSpace(body.Sprint))
+	})
+}
+
+// viewRequest is the body of POST /views and POST /views/{ref}/{action}.
+type viewRequest struct {
+	Board    string `json:"board"`
+	Name     string `json:"name"`     // create, update
+	Match    string `json:"match"`    // create, update
+	Favorite bool   `json:"favorite"` // create, favorite
+	// Filters are the filter flags of the view; nil keeps the filters of
+	// an updated view
+	Filters *viewFilterFlags `json:"filters"`
+	Agent   string           `json:"agent"`
+}
+
+// apiViews answers GET /views?board= like 'view list --json'.
+func apiViews(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		b, err := apiRequestBoard(app, re.Request.URL.Query().Get("board"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		views, err := view.List(app, b.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load views: %w", err)
+		}
+		return viewListResult(b, views), nil
+	})
+}
+
+// apiShowView answers GET /views/{ref}?board= like 'view show --json'.
+func apiShowView(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		b, err := apiRequestBoard(app, re.Request.URL.Query().Get("board"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		_, v, err := findView(app, b, re.Request.PathValue("ref"))
+		return v, err
+	})
+}
+
+// apiCreateView answers POST /views like 'view create --json'.
+func apiCreateView(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body viewRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		b, err := apiRequestBoard(app, body.Board, boardID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPolicy(app, apiActor(re, body.Agent), b.Id, policy.ActionCreate).Err(); err != nil {
+			return nil, err
+		}
+		return createView(app, b, body)
+	})
+}
+
+// apiViewAction answers POST /views/{ref}/{action}, where action is
+// update, favorite, or delete, like 'view <action> --json'.
+func apiViewAction(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body viewRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		action := re.Request.PathValue("action")
+		if action != "update" && action != "favorite" && action != "delete" {
+			return nil, newCommandError(ExitNotFound, "unknown view action %q (use update, favorite, or delete)", action)
+		}
+		b, err := apiRequestBoard(app, body.Board, boardID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPolicy(app, apiActor(re, body.Agent), b.Id, policy.ActionUpdate).Err(); err != nil {
+			return nil, err
+		}
+
+		record, v, err := findView(app, b, re.Request.PathValue("ref"))
+		if err != nil {
+			return nil, err
+		}
+		switch action {
+		case "update":
+			return updateView(app, record, v, body)
+		case "favorite":
+			return favoriteView(app, record, v, body.Favorite)
+		}
+		if err := deleteView(app, record); err != nil {
+			return nil, err
+		}
+		return map[string]any{"deleted": v.Name, "id": v.ID}, nil
+	})
+}
+
+// archiveRequest is the body of POST /tasks/{id}/archive,
+// POST /tasks/{id}/unarchive, and POST /archive/run.
+type archiveRequest struct {
+	Board  string `json:"board"`   // run: only this board's policy
+	DryRun bool   `json:"dry_run"` // run
+	Agent  string `json:"agent"`
+}
+
+// apiArchive answers POST /tasks/{id}/archive, or with unarchive
+// POST /tasks/{id}/unarchive, like one task of 'archive' or 'unarchive'.
+func apiArchive(app *pocketbase.PocketBase, unarchive bool) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body archiveRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		task, err := apiTask(app, re.Request.PathValue("id"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		caller := apiActor(re, body.Agent)
+		if err := checkTaskPolicy(app, caller, task, policy.ActionUpdate).Err(); err != nil {
+			return nil, err
+		}
+		undo.Tag(task, apiBatch(re, caller))
+		return archiveTask(app, task, unarchive, apiSource(caller), caller.Name)
+	})
+}
+
+// apiRunArchive answers POST /archive/run like 'archive run --json'.
+// Board-restricted tokens only run their board's policy.
+func apiRunArchive(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body archiveRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		var b *core.Record
+		if body.Board != "" || boardID != "" {
+			if b, err = apiRequestBoard(app, body.Board, boardID); err != nil {
+				return nil, err
+			}
+		}
+		caller := apiActor(re, body.Agent)
+		return runArchive(app, b, body.DryRun, apiSource(caller), apiBatch(re, caller))
+	})
+}
+
+// trashRequest is the body of POST /trash/restore and POST /trash/purge.
+type trashRequest struct {
+	Ref string `json:"ref"` // restore: task, epic, or board reference
+	// Before purges only the records trashed before this RFC 3339 time
+	// (purge; the whole trash if empty)
+	Before string `json:"before"`
+	Agent  string `json:"agent"`
+}
+
+// apiTrash answers GET /trash?board= like 'trash list --json'.
+func apiTrash(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		ref := re.Request.URL.Query().Get("board")
+		if ref == "" {
+			ref = boardID
+		}
+		var b *core.Record
+		if ref != "" {
+			b, err = findBoardInAnyState(app, ref)
+			if err != nil || (boardID != "" && b.Id != boardID) {
+				return nil, newCommandError(ExitNotFound, "board not found: %s", ref)
+			}
+		}
+		return trashList(app, b)
+	})
+}
+
+// apiRestoreTrash answers POST /trash/restore like one reference of
+// 'trash restore'.
+func apiRestoreTrash(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body trashRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		record, err := findTrashed(app, body.Ref)
+		if err != nil {
+			return nil, err
+		}
+		if boardID != "" && trashBoardID(record) != boardID {
+			return nil, newCommandError(ExitNotFound, "nothing in the trash matches: %s", body.Ref)
+		}
+		caller := apiActor(re, body.Agent)
+		if err := checkPolicy(app, caller, trashBoardID(record), policy.ActionCreate).Err(); err != nil {
+			return nil, err
+		}
+		undo.Tag(record, apiBatch(re, caller))
+		return restoreTrashed(app, record, body.Ref, apiSource(caller), caller.Name)
+	})
+}
+
+// apiPurgeTrash answers POST /trash/purge like 'trash purge --force
+// --json'. Purging deletes on every board, so board-restricted tokens
+// may not.
+func apiPurgeTrash(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body trashRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		if boardID != "" {
+			return nil, re.ForbiddenError("Purging the trash needs an API token for all boards.", nil)
+		}
+		var before time.Time
+		if body.Before != "" {
+			if before, err = time.Parse(time.RFC3339, body.Before); err != nil {
+				return nil, newCommandError(ExitValidation, "invalid before time %q: use RFC 3339", body.Before)
+			}
+		}
+
+		boards, err := app.FindAllRecords("boards")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load boards: %w", err)
+		}
+		if err := checkPurgePolicy(app, apiActor(re, body.Agent), boards); err != nil {
+			return nil, err
+		}
+		result, err := purgeTrash(app, before)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"purged": result}, nil
+	})
+}
+
+// undoRequest is the body of POST /undo and POST /redo.
+type undoRequest struct {
+	Count int    `json:"count"` // number of batches (default 1)
+	Agent string `json:"agent"` // whose changes, when not the caller's own
+}
+
+// apiUndo answers POST /undo, or with redo POST /redo, like
+// 'undo --json' or 'redo --json'. Board-restricted tokens may only undo
+// changes on their board.
+func apiUndo(app *pocketbase.PocketBase, redo bool) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body undoRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		if body.Count == 0 {
+			body.Count = 1
+		}
+
+		caller := apiActor(re, body.Agent)
+		by := undoActor(app, caller, apiSource(caller))
+		check := by.Check
+		by.Check = func(board, kind, fromColumn, toColumn string) error {
+			if boardID != "" && board != boardID {
+				return newCommandError(ExitPolicyDenied, "the API token may not change tasks on other boards")
+			}
+			return check(board, kind, fromColumn, toColumn)
+		}
+		return undoBatches(app, by, body.Count, redo)
+	})
+}
+
+// apiAttachments answers GET /tasks/{id}/attachments like
+// 'attachments list --json', with the task's title. The files are
+// downloaded from the PocketBase files API.
+func apiAttachments(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		task, err := apiTask(app, re.Request.PathValue("id"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		return taskAttachments(app, task)
+	})
+}
+
+// proposalRequest is the body of POST /proposals/{ref}/{action}.
+type proposalRequest struct {
+	Note string `json:"note"`
+}
+
+// apiProposals answers GET /proposals?board=&status= like
+// 'proposals list --json'. An empty status lists proposals in any state.
+func apiProposals(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		query := re.Request.URL.Query()
+		status := query.Get("status")
+		if status != "" && !containsString(proposal.ValidStatuses, status) {
+			return nil, newCommandError(ExitInvalidArguments,
+				"invalid status '%s', must be one of: %v or all", status, proposal.ValidStatuses)
+		}
+		if ref := query.Get("board"); ref != "" {
+			b, err := apiBoard(app, ref, boardID)
+			if err != nil {
+				return nil, newCommandError(ExitValidation, "invalid board: board not found: %s", ref)
+			}
+			boardID = b.Id
+		}
+		return listProposals(app, boardID, status)
+	})
+}
+
+// apiShowProposal answers GET /proposals/{ref} like
+// 'proposals show --json'.
+func apiShowProposal(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		boardID, err := apiCaller(re, false)
+		if err != nil {
+			return nil, err
+		}
+		record, err := apiProposal(app, re.Request.PathValue("ref"), boardID)
+		if err != nil {
+			return nil, err
+		}
+		return proposalToMap(record), nil
+	})
+}
+
+// apiReviewProposal answers POST /proposals/{ref}/{action}, where action
+// is approve or reject, like 'proposals <action> --json'. Only humans may
+// review proposals.
+func apiReviewProposal(app *pocketbase.PocketBase) func(*core.RequestEvent) error {
+	return apiRoute(func(re *core.RequestEvent) (any, error) {
+		var body proposalRequest
+		if err := apiBody(re, &body); err != nil {
+			return nil, err
+		}
+		boardID, err := apiCaller(re, true)
+		if err != nil {
+			return nil, err
+		}
+		action := re.Request.PathValue("action")
+		if action != "approve" && action != "reject" {
+			return nil, newCommandError(ExitNotFound, "unknown proposal action %q (use approve or reject)", action)
+		}
+		record, err := apiProposal(app, re.Request.PathValue("ref"), boardID)
+		if err != nil {
+			return nil, err
+		}
+
+		caller := apiActor(re, "")
+		if err := checkPolicy(app, caller, record.GetString("board"), policy.ActionReview).Err(); err != nil {
+			return nil, err
+		}
+		reviewer := caller.Name
+		if reviewer == "" {
+			reviewer = apiSource(caller)
+		}
+		return reviewProposal(app, record, reviewer, body.Note, action == "approve")
+	})
+}
+
+// apiProposal finds the proposal a route names among the proposals of
+// the boards the caller may see.
+func apiProposal(app *pocketbase.PocketBase, ref, boardID string) (*core.Record, error) {
+	record, err := findProposal(app, ref)
+	if err != nil {
+		return nil, err
+	}
+	if boardID != "" && record.GetString("board") != boardID {
+		return nil, findProposalError(ref)
+	}
+	return record, nil
+}
+
+// apiRequestBoard finds the board of a sprint or view route: the named
+// one, or the caller's board for board-restricted tokens.
+func apiRequestBoard(app *pocketbase.PocketBase, ref, boardID string) (*core.Record, error) {
+	if ref == "" {
+		ref = boardID
+	}
+	if ref == "" {
+		return nil, newCommandError(ExitInvalidArguments, "a board is required")
+	}
+	return apiBoard(app, ref, boardID)
+}
+
+// apiCaller checks that the caller may use a route, or with write a route
+// that changes tasks, and returns the board the caller is restricted to
+// ("" for all boards). With auth disabled every caller may.
+func apiCaller(re *core.RequestEvent, write bool) (string, error) {
+	if !auth.Enabled(re.App) || re.HasSuperuserAuth() {
+		return "", nil
+	}
+	if re.Auth == nil || re.Auth.Collection().Name != auth.UsersCollection {
+		return "", re.UnauthorizedError("The request requires a valid API token.", nil)
+	}
+	if write && re.Auth.GetString("scope") == auth.ScopeRead {
+		return "", re.ForbiddenError("The API token is read-only.", nil)
+	}
+	return re.Auth.GetString("board"), nil
+}
+
+// apiTasks returns the tasks outside the trash, of one board if boardID is
+// set.
+func apiTasks(app *pocketbase.PocketBase, boardID string) ([]*core.Record, error) {
+	filters := []dbx.Expression{trash.Exclude(app, "tasks")}
+	if boardID != "" {
+		filters = append(filters, dbx.HashExp{"board": boardID})
+	}
+	return app.FindAllRecords("tasks", filters...)
+}
+
+// apiTask resolves the task reference of a route among the tasks of the
+// caller's board, so an ambiguous reference only lists tasks the caller
+// may see.
+func apiTask(app *pocketbase.PocketBase, ref, boardID string) (*core.Record, error) {
+	task, err := resolver.MustResolveInBoard(app, ref, boardID)
+	if err != nil {
+		var ambErr *resolver.AmbiguousError
+		if errors.As(err, &ambErr) {
+			return nil, apiAmbiguous(ref, ambErr.Matches)
+		}
+		return nil, newCommandError(ExitNotFound, "%s", err.Error())
+	}
+	return task, nil
+}
+
+// apiAmbiguous is the error of a task reference matching several tasks,
+// listing the matches.
+func apiAmbiguous(ref string, matches []*core.Record) error {
+	return &commandError{
+		code:    ExitAmbiguous,
+		message: fmt.Sprintf("Ambiguous task reference: '%s' matches multiple tasks", ref),
+		data:    map[string]any{"reference": ref, "matches": output.TaskMaps(matches)},
+	}
+}
+
+// apiBoard finds the board a route names by ID, name, or prefix, among
+// the boards the caller may see.
+func apiBoard(app *pocketbase.PocketBase, ref, boardID string) (*core.Record, error) {
+	b, err := board.GetByNameOrPrefix(app, ref)
+	if err != nil || (boardID != "" && b.Id != boardID) {
+		return nil, newCommandError(ExitNotFound, "board not found: %s", ref)
+	}
+	return b, nil
+}
+
+// apiActor identifies the caller of a route that changes data: the agent
+// of the API token, else the agent the request names, else the account of
+// the token. Without a token (auth disabled) the X-Egenskriven-Actor
+// header of the client names the user.
+func apiActor(re *core.RequestEvent, agent string) policy.Actor {
+	actor := policy.ActorFromAuth(re.Auth)
+	if actor.IsAgent() {
+		return actor
+	}
+	if agent = strings.TrimSpace(agent); agent != "" {
+		return policy.Agent(agent)
+	}
+	if re.Auth == nil {
+		if name := re.Request.Header.Get(undo.HeaderActor); name != "" {
+			return policy.User(name)
+		}
+	}
+	return actor
+}
+
+// apiSource is the source a route records in the task event log: the
+// agent type for agents, else "api", as for record API requests.
+func apiSource(caller policy.Actor) string {
+	if caller.IsAgent() {
+		return caller.Type
+	}
+	return "api"
+}
+
+// apiBatch is the undo batch of the changes of a route: the batch of the
+// calling command, sent in the X-Egenskriven-* headers, on the caller's
+// undo stack.
+func apiBatch(re *core.RequestEvent, caller policy.Actor) undo.Batch {
+	return undo.Batch{
+		ID:    re.Request.Header.Get(undo.HeaderBatch),
+		Label: re.Request.Header.Get(undo.HeaderLabel),
+		Actor: caller.Name,
+	}
+}
+
+// apiBody decodes an optional JSON request body.
+func apiBody(re *core.RequestEvent, v any) error {
+	if re.Request.Body == nil {
+		return nil
+	}
+	if err := json.NewDecoder(re.Request.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return newCommandError(ExitInvalidArguments, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+// apiError answers with an error. PocketBase API errors are passed
+// through; policy violations and command errors carry the exit code of
+// the command as data.exit_code.
+func apiError(re *core.RequestEvent, err error) error {
+	var apiErr *router.ApiError
+	if errors.As(err, &apiErr) {
+		return err
+	}
+
+	code, data := ExitGeneralError, map[string]any{}
+	var v *policy.Violation
+	var ce *commandError
+	switch {
+	case errors.As(err, &v):
+		code, data = ExitPolicyDenied, map[string]any{"policy": v.Data()}
+	case errors.As(err, &ce):
+		code = ce.code
+		for k, val := range ce.data {
+			data[k] = val
+		}
+		if ce.suggestion != "" {
+			data["suggestion"] = ce.suggestion
+		}
+	}
+	data["exit_code"] = code
+
+	status := apiStatus(code)
+	return re.JSON(status, map[string]any{
+		"status":  status,
+		"message": err.Error(),
+		"data":    data,
+	})
+}
+
+// apiStatus maps a command exit code to an HTTP status.
+func apiStatus(exitCode int) int {
+	switch exitCode {
+	case ExitInvalidArguments, ExitValidation:
+		return http.StatusBadRequest
+	case ExitNotFound:
+		return http.StatusNotFound
+	case ExitAmbiguous, ExitConflict:
+		return http.StatusConflict
+	case ExitPolicyDenied:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
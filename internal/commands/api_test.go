@@ -0,0 +1,1005 @@
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/proposal"
+	"github.com/ramtinJ95/EgenSkriven/internal/sprint"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasklink"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+	"github.com/ramtinJ95/EgenSkriven/internal/view"
+)
+
+// callRoute calls a route handler with the path values of the request and
+// decodes the JSON response.
+func callRoute(t *testing.T, app *pocketbase.PocketBase, handler func(*core.RequestEvent) error, method, target string, pathValues map[string]string, body string) (int, map[string]any) {
+	t.Helper()
+	return callRouteAs(t, app, nil, handler, method, target, pathValues, body)
+}
+
+// callRouteAs calls a route handler as the owner of an API token.
+func callRouteAs(t *testing.T, app *pocketbase.PocketBase, authRecord *core.Record, handler func(*core.RequestEvent) error, method, target string, pathValues map[string]string, body string) (int, map[string]any) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for k, v := range pathValues {
+		req.SetPathValue(k, v)
+	}
+	rec := httptest.NewRecorder()
+
+	re := &core.RequestEvent{App: app}
+	re.Auth = authRecord
+	re.Request = req
+	re.Response = rec
+	require.NoError(t, handler(re))
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result), rec.Body.String())
+	return rec.Code, result
+}
+
+func TestAPISuggestAndContext(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+
+	urgent := CreateTestTask(t, app, "Fix login", "todo")
+	urgent.Set("priority", "urgent")
+	require.NoError(t, app.Save(urgent))
+	CreateTestTask(t, app, "Write docs", "in_progress")
+	CreateTestTask(t, app, "Shipped", "done")
+
+	code, result := callRoute(t, app, apiSuggest(app), http.MethodGet, "/api/egenskriven/suggest?limit=1", nil, "")
+	assert.Equal(t, http.StatusOK, code)
+	suggestions := result["suggestions"].([]any)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "Continue current work", suggestions[0].(map[string]any)["reason"])
+
+	code, result = callRoute(t, app, apiSuggest(app), http.MethodGet, "/api/egenskriven/suggest?limit=x", nil, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, float64(ExitValidation), result["data"].(map[string]any)["exit_code"])
+
+	code, result = callRoute(t, app, apiContext(app), http.MethodGet, "/api/egenskriven/context", nil, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(3), result["summary"].(map[string]any)["total"])
+	assert.Equal(t, float64(1), result["ready_count"])
+}
+
+func TestAPIResolve(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+
+	task := CreateTestTask(t, app, "Fix login page", "todo")
+	CreateTestTask(t, app, "Fix signup page", "todo")
+
+	code, result := callRoute(t, app, apiResolve(app), http.MethodGet, "/", map[string]string{"ref": task.Id}, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Fix login page", result["task"].(map[string]any)["title"])
+
+	code, result = callRoute(t, app, apiResolve(app), http.MethodGet, "/", map[string]string{"ref": "fix"}, "")
+	assert.Equal(t, http.StatusConflict, code)
+	data := result["data"].(map[string]any)
+	assert.Equal(t, float64(ExitAmbiguous), data["exit_code"])
+	assert.Len(t, data["matches"], 2)
+
+	code, result = callRoute(t, app, apiResolve(app), http.MethodGet, "/", map[string]string{"ref": "nothing"}, "")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, float64(ExitNotFound), result["data"].(map[string]any)["exit_code"])
+}
+
+func TestAPITask_BoardScoped(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	tasks.Fields.Add(&core.TextField{Name: "board"})
+	require.NoError(t, app.Save(tasks))
+
+	login := CreateTestTask(t, app, "Fix login page", "todo")
+	login.Set("board", "board1")
+	require.NoError(t, app.Save(login))
+	signup := CreateTestTask(t, app, "Fix signup page", "todo")
+	signup.Set("board", "board2")
+	require.NoError(t, app.Save(signup))
+
+	// Tasks of other boards neither match nor make a reference ambiguous
+	task, err := apiTask(app, "fix", "board1")
+	require.NoError(t, err)
+	assert.Equal(t, login.Id, task.Id)
+
+	_, err = apiTask(app, signup.Id, "board1")
+	var ce *commandError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, ExitNotFound, ce.code)
+
+	_, err = apiTask(app, "fix", "")
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, ExitAmbiguous, ce.code)
+}
+
+func TestAPIBlockAndResume(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollectionWithAgentSession(t, app)
+	SetupCommentsCollectionWithAutodate(t, app)
+	SetupSessionsCollection(t, app)
+
+	task := CreateTestTask(t, app, "Add auth", "in_progress")
+	task.Set("agent_session", map[string]any{
+		"tool":        "claude-code",
+		"ref":         "550e8400-e29b-41d4-a716-446655440000",
+		"working_dir": "/tmp/project",
+	})
+	require.NoError(t, app.Save(task))
+	ids := map[string]string{"id": task.Id}
+
+	// Resuming a task that is not blocked fails like the command
+	code, result := callRoute(t, app, apiResume(app), http.MethodPost, "/", ids, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, result["message"], "not in need_input state")
+
+	code, result = callRoute(t, app, apiBlock(app), http.MethodPost, "/", ids, `{"question": ""}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "question cannot be empty", result["message"])
+
+	code, result = callRoute(t, app, apiBlock(app), http.MethodPost, "/", ids,
+		`{"question": "JWT or sessions?", "agent": "claude"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, "need_input", result["column"])
+
+	comments := GetCommentsForTask(t, app, task.Id)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "claude", comments[0].GetString("author_id"))
+
+	code, result = callRoute(t, app, apiResume(app), http.MethodPost, "/", ids, `{"minimal": true}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "claude-code", result["tool"])
+	assert.Contains(t, result["prompt"], "JWT or sessions?")
+	assert.Contains(t, result["command"], "claude --resume")
+
+	task, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "need_input", task.GetString("column"), "resume only builds the command")
+
+	// Starting moves the task, which a supervised board denies agents
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "agent_mode"},
+	)
+	b := core.NewRecord(boards)
+	b.Set("name", "Work")
+	b.Set("agent_mode", policy.ModeSupervised)
+	require.NoError(t, app.Save(b))
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	tasks.Fields.Add(&core.TextField{Name: "board"})
+	require.NoError(t, app.Save(tasks))
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	task.Set("board", b.Id)
+	require.NoError(t, app.Save(task))
+
+	tokens := testutil.CreateTestCollection(t, app, "tokens", &core.TextField{Name: "agent"})
+	agentToken := core.NewRecord(tokens)
+	agentToken.Set("agent", "claude")
+
+	code, result = callRouteAs(t, app, agentToken, apiResume(app), http.MethodPost, "/", ids, `{"start": true}`)
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, float64(ExitPolicyDenied), result["data"].(map[string]any)["exit_code"])
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "need_input", task.GetString("column"))
+
+	code, _ = callRoute(t, app, apiResume(app), http.MethodPost, "/", ids, `{"start": true}`)
+	assert.Equal(t, http.StatusOK, code)
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "in_progress", task.GetString("column"))
+}
+
+func TestAPIListAndShow(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+
+	parent := CreateTestTask(t, app, "Fix login", "todo")
+	parent.Set("priority", "urgent")
+	require.NoError(t, app.Save(parent))
+	child := CreateTestTask(t, app, "Add test", "todo")
+	child.Set("parent", parent.Id)
+	require.NoError(t, app.Save(child))
+	CreateTestTask(t, app, "Write docs", "backlog")
+
+	code, result := callRoute(t, app, apiList(app), http.MethodPost, "/", nil, `{"columns": ["todo"]}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Len(t, result["tasks"], 2)
+
+	code, result = callRoute(t, app, apiList(app), http.MethodPost, "/", nil, `{"priorities": ["urgent"]}`)
+	require.Equal(t, http.StatusOK, code, result)
+	tasks := result["tasks"].([]any)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, parent.Id, tasks[0].(map[string]any)["id"])
+
+	code, result = callRoute(t, app, apiList(app), http.MethodPost, "/", nil, `{"columns": ["nope"]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, float64(ExitValidation), result["data"].(map[string]any)["exit_code"])
+
+	code, result = callRoute(t, app, apiShow(app), http.MethodGet, "/", map[string]string{"id": parent.Id}, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "Fix login", result["task"].(map[string]any)["title"])
+	require.Len(t, result["subtasks"], 1)
+	assert.Equal(t, child.Id, result["subtasks"].([]any)[0].(map[string]any)["id"])
+	assert.Contains(t, result, "extras")
+
+	code, _ = callRoute(t, app, apiShow(app), http.MethodGet, "/", map[string]string{"id": "nothing"}, "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestAPITrackTime(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+	SetupTimeEntriesCollection(t, app)
+
+	task := CreateTestTask(t, app, "Parser", "in_progress")
+	start := map[string]string{"id": task.Id, "action": "start"}
+	stop := map[string]string{"id": task.Id, "action": "stop"}
+	logTime := map[string]string{"id": task.Id, "action": "log"}
+
+	code, result := callRoute(t, app, apiTrackTime(app), http.MethodPost, "/", start, `{"actor": "alice"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "alice", result["entry"].(map[string]any)["actor"])
+	assert.Equal(t, true, result["entry"].(map[string]any)["running"])
+
+	code, result = callRoute(t, app, apiTrackTime(app), http.MethodPost, "/", start, `{"actor": "alice"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, result["message"], "timer already running")
+
+	code, result = callRoute(t, app, apiTrackTime(app), http.MethodPost, "/", stop, `{"note": "Grammar"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "Grammar", result["entry"].(map[string]any)["note"])
+	assert.Equal(t, false, result["entry"].(map[string]any)["running"])
+
+	code, result = callRoute(t, app, apiTrackTime(app), http.MethodPost, "/", stop, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, result["message"], "no timer running")
+
+	code, result = callRoute(t, app, apiTrackTime(app), http.MethodPost, "/", logTime, `{"duration": "soon"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, float64(ExitValidation), result["data"].(map[string]any)["exit_code"])
+
+	code, result = callRoute(t, app, apiTrackTime(app), http.MethodPost, "/", logTime,
+		`{"actor": "bob", "duration": "1h30m0s", "ended_at": "2025-01-15T17:00:00Z"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(5400), result["entry"].(map[string]any)["duration_seconds"])
+	assert.Equal(t, "2025-01-15T15:30:00Z", result["entry"].(map[string]any)["started_at"])
+
+	code, result = callRoute(t, app, apiTimeReport(app), http.MethodGet, "/api/egenskriven/time/report?by=actor", nil, "")
+	require.Equal(t, http.StatusOK, code, result)
+	rows := result["rows"].([]any)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "bob", rows[0].(map[string]any)["key"])
+
+	code, _ = callRoute(t, app, apiTimeReport(app), http.MethodGet, "/api/egenskriven/time/report?by=week", nil, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestAPILinkAndUnlink(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+	testutil.CreateTestCollection(t, app, tasklink.CollectionName,
+		&core.TextField{Name: "source"},
+		&core.TextField{Name: "target"},
+		&core.TextField{Name: "type"},
+		&core.BoolField{Name: "one_way"},
+		&core.TextField{Name: "created_by"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+
+	crash := CreateTestTask(t, app, "Crash on save", "todo")
+	cause := CreateTestTask(t, app, "Rewrite storage", "done")
+	ids := map[string]string{"id": crash.Id}
+
+	code, result := callRoute(t, app, apiLink(app), http.MethodPost, "/", ids,
+		`{"target": "`+cause.Id+`", "type": "caused-by", "agent": "claude"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "caused_by", result["type"])
+	assert.Contains(t, result["message"], "caused by")
+
+	links, err := tasklink.Find(app, crash.Id, cause.Id, "")
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "claude", links[0].GetString("created_by"))
+
+	code, result = callRoute(t, app, apiLink(app), http.MethodPost, "/", ids,
+		`{"target": "`+cause.Id+`", "type": "caused_by"}`)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, float64(ExitConflict), result["data"].(map[string]any)["exit_code"])
+
+	code, _ = callRoute(t, app, apiLink(app), http.MethodPost, "/", ids, `{"target": "`+crash.Id+`", "type": "relates"}`)
+	assert.Equal(t, http.StatusBadRequest, code, "a task cannot link to itself")
+
+	code, result = callRoute(t, app, apiUnlink(app), http.MethodPost, "/", ids, `{"other": "`+cause.Id+`"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(1), result["removed"])
+
+	code, _ = callRoute(t, app, apiUnlink(app), http.MethodPost, "/", ids, `{"other": "`+cause.Id+`"}`)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestAPILog(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+
+	task := CreateTestTask(t, app, "Parser", "todo")
+	other := CreateTestTask(t, app, "Lexer", "todo")
+	day := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, taskevent.Save(app, taskevent.Event{
+		Task: task.Id, Action: "moved", Actor: "agent", ActorDetail: "claude", Timestamp: day,
+	}))
+	require.NoError(t, taskevent.Save(app, taskevent.Event{
+		Task: other.Id, Action: "updated", Actor: "user", ActorDetail: "alice", Timestamp: day.Add(time.Hour),
+	}))
+
+	// The task's creation and its move
+	code, result := callRoute(t, app, apiLog(app), http.MethodGet, "/api/egenskriven/log?task="+task.Id, nil, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(2), result["count"])
+
+	code, result = callRoute(t, app, apiLog(app), http.MethodGet,
+		"/api/egenskriven/log?actor=alice&since=2025-01-15T12:30:00Z", nil, "")
+	require.Equal(t, http.StatusOK, code, result)
+	events := result["events"].([]any)
+	require.Len(t, events, 1)
+	assert.Equal(t, other.Id, events[0].(map[string]any)["task"])
+
+	code, result = callRoute(t, app, apiLog(app), http.MethodGet, "/api/egenskriven/log?task=nothing", nil, "")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, float64(ExitNotFound), result["data"].(map[string]any)["exit_code"])
+}
+
+// setupSprintBoard creates the boards and sprints collections and a board
+// WRK with a task WRK-1.
+func setupSprintBoard(t *testing.T, app *pocketbase.PocketBase) (*core.Record, *core.Record) {
+	t.Helper()
+	SetupTasksCollection(t, app)
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	tasks.Fields.Add(&core.TextField{Name: "board"})
+	tasks.Fields.Add(&core.NumberField{Name: "seq"})
+	tasks.Fields.Add(&core.TextField{Name: "sprint"})
+	require.NoError(t, app.Save(tasks))
+
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name", Required: true},
+		&core.TextField{Name: "prefix", Required: true},
+	)
+	testutil.CreateTestCollection(t, app, sprint.CollectionName,
+		&core.TextField{Name: "board", Required: true},
+		&core.TextField{Name: "name", Required: true},
+		&core.DateField{Name: "start_date"},
+		&core.DateField{Name: "end_date"},
+		&core.TextField{Name: "goal"},
+		&core.TextField{Name: "state", Required: true},
+		&core.DateField{Name: "closed_at"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+
+	b := core.NewRecord(boards)
+	b.Set("name", "Work")
+	b.Set("prefix", "WRK")
+	require.NoError(t, app.Save(b))
+
+	task := CreateTestTask(t, app, "Parser", "todo")
+	task.Set("board", b.Id)
+	task.Set("seq", 1)
+	require.NoError(t, app.Save(task))
+	return b, task
+}
+
+func TestAPISprints(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	b, task := setupSprintBoard(t, app)
+
+	code, result := callRoute(t, app, apiCreateSprint(app), http.MethodPost, "/", nil,
+		`{"board": "WRK", "name": "Sprint 1", "start": "2025-01-06", "end": "2025-01-17"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, sprint.StatePlanned, result["state"])
+	assert.Equal(t, "2025-01-17", result["end_date"])
+
+	code, result = callRoute(t, app, apiCreateSprint(app), http.MethodPost, "/", nil,
+		`{"board": "WRK", "name": "Sprint 2", "start": "2025-01-20", "end": "2025-01-17"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, result["message"], "end date must not be before start date")
+
+	code, result = callRoute(t, app, apiCreateSprint(app), http.MethodPost, "/", nil,
+		`{"board": "WRK", "name": "Sprint 2", "start": "2025-01-20"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "2025-02-02", result["end_date"], "two weeks by default")
+
+	board := `{"board": "` + b.Id + `"}`
+	code, result = callRoute(t, app, apiSprintAction(app), http.MethodPost, "/",
+		map[string]string{"ref": "Sprint 1", "action": "start"}, board)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, sprint.StateActive, result["state"])
+
+	code, result = callRoute(t, app, apiSprintAction(app), http.MethodPost, "/",
+		map[string]string{"ref": "Sprint 2", "action": "start"}, board)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, result["message"], sprint.ErrAlreadyActive.Error())
+
+	code, result = callRoute(t, app, apiTaskSprint(app), http.MethodPost, "/",
+		map[string]string{"id": "WRK-1"}, `{"sprint": "current"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "WRK-1", result["display_id"])
+	assert.Equal(t, "Sprint 1", result["sprint"].(map[string]any)["name"])
+
+	code, result = callRoute(t, app, apiShowSprint(app), http.MethodGet, "/api/egenskriven/sprints/current?board=WRK",
+		map[string]string{"ref": "current"}, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(1), result["task_count"])
+	assert.Equal(t, float64(0), result["completed"])
+
+	code, result = callRoute(t, app, apiSprints(app), http.MethodGet, "/api/egenskriven/sprints?board=WRK", nil, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(2), result["count"])
+
+	code, result = callRoute(t, app, apiSprintAction(app), http.MethodPost, "/",
+		map[string]string{"ref": "current", "action": "close"}, `{"board": "WRK", "carry": true}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, []any{"WRK-1"}, result["carried"])
+	assert.Equal(t, "Sprint 2", result["carried_to"].(map[string]any)["name"])
+
+	task, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, result["carried_to"].(map[string]any)["id"], task.GetString("sprint"))
+
+	code, result = callRoute(t, app, apiTaskSprint(app), http.MethodPost, "/", map[string]string{"id": "WRK-1"}, `{}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Nil(t, result["sprint"])
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.Empty(t, task.GetString("sprint"))
+
+	code, _ = callRoute(t, app, apiSprints(app), http.MethodGet, "/api/egenskriven/sprints", nil, "")
+	assert.Equal(t, http.StatusBadRequest, code, "a board is required")
+
+	code, _ = callRoute(t, app, apiSprintAction(app), http.MethodPost, "/",
+		map[string]string{"ref": "current", "action": "reopen"}, board)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+// setupViewBoard creates the views collection next to the sprint board.
+func setupViewBoard(t *testing.T, app *pocketbase.PocketBase) *core.Record {
+	t.Helper()
+	b, _ := setupSprintBoard(t, app)
+	testutil.CreateTestCollection(t, app, view.CollectionName,
+		&core.TextField{Name: "name", Required: true},
+		&core.TextField{Name: "board", Required: true},
+		&core.JSONField{Name: "filters"},
+		&core.TextField{Name: "match_mode"},
+		&core.JSONField{Name: "display"},
+		&core.BoolField{Name: "is_favorite"},
+	)
+	return b
+}
+
+func TestAPIViews(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	b := setupViewBoard(t, app)
+
+	code, result := callRoute(t, app, apiCreateView(app), http.MethodPost, "/", nil,
+		`{"board": "WRK", "name": "Urgent bugs", "filters": {"types": ["bug"], "priorities": ["urgent", "high"]}}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, view.MatchAll, result["match_mode"])
+	assert.Len(t, result["filters"], 2)
+
+	code, result = callRoute(t, app, apiCreateView(app), http.MethodPost, "/", nil,
+		`{"board": "WRK", "name": "urgent bugs"}`)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Contains(t, result["message"], "already exists")
+
+	code, result = callRoute(t, app, apiCreateView(app), http.MethodPost, "/", nil,
+		`{"board": "WRK", "name": "Bad", "filters": {"columns": ["nowhere"]}}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, result["message"], "invalid column 'nowhere'")
+
+	board := `{"board": "` + b.Id + `"}`
+	code, result = callRoute(t, app, apiViewAction(app), http.MethodPost, "/",
+		map[string]string{"ref": "Urgent bugs", "action": "update"},
+		`{"board": "WRK", "name": "P0 bugs", "match": "any"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, view.MatchAny, result["match_mode"])
+	assert.Len(t, result["filters"], 2, "filters are kept without filter flags")
+
+	code, _ = callRoute(t, app, apiViewAction(app), http.MethodPost, "/",
+		map[string]string{"ref": "P0 bugs", "action": "update"}, board)
+	assert.Equal(t, http.StatusBadRequest, code, "nothing to update")
+
+	code, result = callRoute(t, app, apiViewAction(app), http.MethodPost, "/",
+		map[string]string{"ref": "P0 bugs", "action": "favorite"}, `{"board": "WRK", "favorite": true}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, true, result["is_favorite"])
+
+	code, result = callRoute(t, app, apiShowView(app), http.MethodGet, "/api/egenskriven/views/P0%20bugs?board=WRK",
+		map[string]string{"ref": "P0 bugs"}, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "P0 bugs", result["name"])
+
+	code, result = callRoute(t, app, apiViews(app), http.MethodGet, "/api/egenskriven/views?board=WRK", nil, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(1), result["count"])
+	assert.Equal(t, "Work", result["board"])
+
+	code, result = callRoute(t, app, apiViewAction(app), http.MethodPost, "/",
+		map[string]string{"ref": "P0 bugs", "action": "delete"}, board)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "P0 bugs", result["deleted"])
+
+	code, _ = callRoute(t, app, apiShowView(app), http.MethodGet, "/api/egenskriven/views/P0%20bugs?board=WRK",
+		map[string]string{"ref": "P0 bugs"}, "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = callRoute(t, app, apiViewAction(app), http.MethodPost, "/",
+		map[string]string{"ref": "P0 bugs", "action": "rename"}, board)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+// setupArchiveBoard adds the archive fields to the sprint board, with an
+// auto-archive policy of 14 days.
+func setupArchiveBoard(t *testing.T, app *pocketbase.PocketBase) (*core.Record, *core.Record) {
+	t.Helper()
+	b, task := setupSprintBoard(t, app)
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	tasks.Fields.Add(&core.DateField{Name: "archived_at"})
+	tasks.Fields.Add(&core.TextField{Name: "archived_by"})
+	require.NoError(t, app.Save(tasks))
+	boards, err := app.FindCollectionByNameOrId("boards")
+	require.NoError(t, err)
+	boards.Fields.Add(&core.NumberField{Name: "auto_archive_days", OnlyInt: true})
+	require.NoError(t, app.Save(boards))
+
+	b, err = app.FindRecordById("boards", b.Id)
+	require.NoError(t, err)
+	b.Set("auto_archive_days", 14)
+	require.NoError(t, app.Save(b))
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	return b, task
+}
+
+func TestAPIArchive(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	_, task := setupArchiveBoard(t, app)
+
+	code, result := callRoute(t, app, apiArchive(app, false), http.MethodPost, "/",
+		map[string]string{"id": "WRK-1"}, `{"agent": "claude"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "WRK-1", result["display_id"])
+	assert.Nil(t, result["skipped"])
+
+	task, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.True(t, archive.IsArchived(task))
+	assert.Equal(t, "claude", task.GetString("archived_by"))
+	events, err := taskevent.ForTask(app, task.Id)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	assert.Equal(t, "archived", events[len(events)-1].Action)
+	assert.Equal(t, policy.ActorAgent, events[len(events)-1].Actor)
+
+	code, result = callRoute(t, app, apiArchive(app, false), http.MethodPost, "/",
+		map[string]string{"id": "WRK-1"}, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, archive.ErrArchived.Error(), result["skipped"])
+
+	code, result = callRoute(t, app, apiArchive(app, true), http.MethodPost, "/",
+		map[string]string{"id": "WRK-1"}, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Nil(t, result["skipped"])
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.False(t, archive.IsArchived(task))
+
+	// Done for three weeks, without logging a move now
+	_, err = app.DB().NewQuery("UPDATE tasks SET [[column]] = 'done' WHERE id = {:id}").
+		Bind(dbx.Params{"id": task.Id}).Execute()
+	require.NoError(t, err)
+	require.NoError(t, taskevent.Save(app, taskevent.Event{
+		Task:      task.Id,
+		Board:     task.GetString("board"),
+		Action:    taskevent.ActionMoved,
+		Actor:     "cli",
+		Changes:   map[string]any{"column": map[string]any{"from": "review", "to": "done"}},
+		Timestamp: time.Now().AddDate(0, 0, -21),
+	}))
+
+	code, result = callRoute(t, app, apiRunArchive(app), http.MethodPost, "/", nil, `{"board": "WRK", "dry_run": true}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(1), result["count"])
+	assert.Equal(t, []any{"WRK-1"}, result["boards"].([]any)[0].(map[string]any)["tasks"])
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.False(t, archive.IsArchived(task), "a dry run archives nothing")
+
+	code, result = callRoute(t, app, apiRunArchive(app), http.MethodPost, "/", nil, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(1), result["count"])
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.True(t, archive.IsArchived(task))
+
+	code, _ = callRoute(t, app, apiRunArchive(app), http.MethodPost, "/", nil, `{"board": "nowhere"}`)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+// setupTrashBoard adds the trash fields to the sprint board.
+func setupTrashBoard(t *testing.T, app *pocketbase.PocketBase) (*core.Record, *core.Record) {
+	t.Helper()
+	b, task := setupSprintBoard(t, app)
+	for _, name := range []string{"tasks", "boards"} {
+		c, err := app.FindCollectionByNameOrId(name)
+		require.NoError(t, err)
+		c.Fields.Add(&core.DateField{Name: "deleted_at"})
+		c.Fields.Add(&core.TextField{Name: "deleted_by"})
+		c.Fields.Add(&core.TextField{Name: "deleted_with"})
+		require.NoError(t, app.Save(c))
+	}
+	testutil.CreateTestCollection(t, app, "epics",
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "board"},
+		&core.DateField{Name: "deleted_at"},
+		&core.TextField{Name: "deleted_by"},
+		&core.TextField{Name: "deleted_with"},
+	)
+	task, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	return b, task
+}
+
+func TestAPITrash(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	_, task := setupTrashBoard(t, app)
+	require.NoError(t, trash.Task(app, task, "cli", "alice"))
+
+	code, result := callRoute(t, app, apiTrash(app), http.MethodGet, "/api/egenskriven/trash?board=WRK", nil, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(1), result["count"])
+	item := result["items"].([]any)[0].(map[string]any)
+	assert.Equal(t, "task", item["kind"])
+	assert.Equal(t, "WRK-1", item["ref"])
+	assert.Equal(t, "alice", item["deleted_by"])
+
+	code, result = callRoute(t, app, apiRestoreTrash(app), http.MethodPost, "/", nil, `{"ref": "nothing here"}`)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "Use 'egenskriven trash list' to see trashed records", result["data"].(map[string]any)["suggestion"])
+
+	code, result = callRoute(t, app, apiRestoreTrash(app), http.MethodPost, "/", nil, `{"ref": "WRK-1"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "task", result["kind"])
+	assert.Equal(t, "Parser", result["name"])
+	task, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.False(t, trash.IsTrashed(task))
+
+	require.NoError(t, trash.Task(app, task, "cli", "alice"))
+	code, result = callRoute(t, app, apiPurgeTrash(app), http.MethodPost, "/", nil,
+		`{"before": "2000-01-01T00:00:00Z"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(0), result["purged"].(map[string]any)["tasks"])
+
+	code, result = callRoute(t, app, apiPurgeTrash(app), http.MethodPost, "/", nil, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(1), result["purged"].(map[string]any)["tasks"])
+	_, err = app.FindRecordById("tasks", task.Id)
+	assert.Error(t, err)
+
+	code, _ = callRoute(t, app, apiPurgeTrash(app), http.MethodPost, "/", nil, `{"before": "last week"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+// setupUndoBoard creates the operations collection next to the sprint
+// board.
+func setupUndoBoard(t *testing.T, app *pocketbase.PocketBase) (*core.Record, *core.Record) {
+	t.Helper()
+	b, task := setupSprintBoard(t, app)
+	testutil.CreateTestCollection(t, app, undo.CollectionName,
+		&core.TextField{Name: "batch"},
+		&core.TextField{Name: "label"},
+		&core.TextField{Name: "actor"},
+		&core.TextField{Name: "kind"},
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "board"},
+		&core.JSONField{Name: "before"},
+		&core.JSONField{Name: "after"},
+		&core.TextField{Name: "status"},
+		&core.NumberField{Name: "seq"},
+		&core.DateField{Name: "undone_at"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+	return b, task
+}
+
+func TestAPIUndo(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	b, task := setupUndoBoard(t, app)
+
+	// An agent moved the task to review
+	before := undo.Snapshot(task)
+	task.Set("column", "review")
+	require.NoError(t, app.Save(task))
+	require.NoError(t, undo.Record(app, undo.NewBatch("move WRK-1 review", "claude"),
+		undo.KindUpdate, task.Id, b.Id, before, undo.Snapshot(task)))
+
+	code, result := callRoute(t, app, apiUndo(app, false), http.MethodPost, "/", nil, `{"agent": "claude"}`)
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(1), result["count"])
+	assert.Equal(t, "move WRK-1 review", result["undone"].([]any)[0].(map[string]any)["label"])
+	task, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "todo", task.GetString("column"))
+
+	code, result = callRoute(t, app, apiUndo(app, false), http.MethodPost, "/", nil, `{"agent": "claude"}`)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, undo.ErrNothingToUndo.Error(), result["message"])
+
+	// Someone else changed the task since
+	task.Set("column", "in_progress")
+	require.NoError(t, app.Save(task))
+	code, result = callRoute(t, app, apiUndo(app, true), http.MethodPost, "/", nil, `{"agent": "claude", "count": 2}`)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Contains(t, result["message"], `cannot redo "move WRK-1 review"`)
+	conflicts := result["data"].(map[string]any)["conflicts"].([]any)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "WRK-1", conflicts[0].(map[string]any)["display_id"])
+
+	code, _ = callRoute(t, app, apiUndo(app, false), http.MethodPost, "/", nil, `{"count": -1}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	var spec struct {
+		OpenAPI string                    `yaml:"openapi"`
+		Paths   map[string]map[string]any `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(openAPISpec, &spec))
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+
+	for path, method := range map[string]string{
+		"/list":                     "post",
+		"/tasks/{id}":               "get",
+		"/suggest":                  "get",
+		"/context":                  "get",
+		"/resolve/{ref}":            "get",
+		"/tasks/{id}/resume":        "post",
+		"/tasks/{id}/block":         "post",
+		"/tasks/{id}/link":          "post",
+		"/tasks/{id}/unlink":        "post",
+		"/log":                      "get",
+		"/tasks/{id}/time/{action}": "post",
+		"/time/report":              "get",
+		"/reports/flow":             "get",
+		"/export":                   "get",
+		"/sprints":                  "get",
+		"/sprints/{ref}":            "get",
+		"/sprints/{ref}/{action}":   "post",
+		"/tasks/{id}/sprint":        "post",
+		"/views":                    "get",
+		"/views/{ref}":              "get",
+		"/views/{ref}/{action}":     "post",
+		"/tasks/{id}/archive":       "post",
+		"/tasks/{id}/unarchive":     "post",
+		"/archive/run":              "post",
+		"/trash":                    "get",
+		"/trash/restore":            "post",
+		"/trash/purge":              "post",
+		"/undo":                     "post",
+		"/redo":                     "post",
+		"/tasks/{id}/attachments":   "get",
+		"/proposals":                "get",
+		"/proposals/{ref}":          "get",
+		"/proposals/{ref}/{action}": "post",
+	} {
+		assert.Contains(t, spec.Paths[APIPrefix+path], method, path)
+	}
+}
+
+// setupProposalBoard creates the proposals collection next to the sprint
+// board, with a pending proposal to move its task to review.
+func setupProposalBoard(t *testing.T, app *pocketbase.PocketBase) (*core.Record, *core.Record) {
+	t.Helper()
+	b, task := setupSprintBoard(t, app)
+	testutil.CreateTestCollection(t, app, proposal.CollectionName,
+		&core.TextField{Name: "board"},
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "action"},
+		&core.JSONField{Name: "changes"},
+		&core.TextField{Name: "command"},
+		&core.TextField{Name: "summary"},
+		&core.TextField{Name: "rationale"},
+		&core.TextField{Name: "status"},
+		&core.TextField{Name: "proposed_by"},
+		&core.TextField{Name: "reviewed_by"},
+		&core.TextField{Name: "review_note"},
+		&core.DateField{Name: "reviewed_at"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+	record, err := proposal.Create(app, proposal.Input{
+		BoardID:    b.Id,
+		Change:     proposal.Change{Action: proposal.ActionMove, Task: task.Id, Column: "review"},
+		Command:    "move WRK-1 review",
+		Summary:    "Move WRK-1 to review",
+		ProposedBy: "claude",
+	})
+	require.NoError(t, err)
+	return b, record
+}
+
+func TestAPIProposals(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	_, p := setupProposalBoard(t, app)
+	ref := map[string]string{"ref": p.Id[:6]}
+
+	code, result := callRoute(t, app, apiProposals(app), http.MethodGet, "/api/egenskriven/proposals?board=WRK&status=pending", nil, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, float64(1), result["count"])
+
+	code, result = callRoute(t, app, apiProposals(app), http.MethodGet, "/api/egenskriven/proposals?status=open", nil, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, result = callRoute(t, app, apiShowProposal(app), http.MethodGet, "/", ref, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "Move WRK-1 to review", result["summary"])
+	assert.Equal(t, "claude", result["proposed_by"])
+
+	// Agents cannot review proposals
+	tokens := testutil.CreateTestCollection(t, app, "tokens", &core.TextField{Name: "agent"})
+	agentToken := core.NewRecord(tokens)
+	agentToken.Set("agent", "claude")
+	code, result = callRouteAs(t, app, agentToken, apiReviewProposal(app), http.MethodPost, "/",
+		map[string]string{"ref": p.Id, "action": "approve"}, "")
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, float64(ExitPolicyDenied), result["data"].(map[string]any)["exit_code"])
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"note": "Looks done"}`))
+	req.SetPathValue("ref", p.Id)
+	req.SetPathValue("action", "approve")
+	req.Header.Set(undo.HeaderActor, "alice")
+	rec := httptest.NewRecorder()
+	re := &core.RequestEvent{App: app}
+	re.Request, re.Response = req, rec
+	require.NoError(t, apiReviewProposal(app)(re))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, proposal.StatusApproved, result["status"])
+	assert.Equal(t, "alice", result["reviewed_by"])
+	assert.Equal(t, "WRK-1", result["display_id"])
+	assert.Equal(t, "review", result["column"])
+
+	code, result = callRoute(t, app, apiReviewProposal(app), http.MethodPost, "/",
+		map[string]string{"ref": p.Id, "action": "reject"}, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, float64(ExitValidation), result["data"].(map[string]any)["exit_code"])
+
+	code, _ = callRoute(t, app, apiShowProposal(app), http.MethodGet, "/", map[string]string{"ref": "nothing"}, "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestAPIAttachments(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	_, task := setupSprintBoard(t, app)
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	tasks.Fields.Add(&core.FileField{Name: attachment.Field, MaxSelect: 10, MaxSize: 1 << 20})
+	require.NoError(t, app.Save(tasks))
+
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	file, err := filesystem.NewFileFromBytes([]byte("panic: nil map"), "build.log")
+	require.NoError(t, err)
+	task.Set(attachment.Field, []*filesystem.File{file})
+	require.NoError(t, app.Save(task))
+
+	code, result := callRoute(t, app, apiAttachments(app), http.MethodGet, "/", map[string]string{"id": "WRK-1"}, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "WRK-1", result["display_id"])
+	assert.Equal(t, "Parser", result["title"])
+	assert.Equal(t, float64(1), result["count"])
+	assert.Equal(t, float64(14), result["total_size"])
+	info := result["attachments"].([]any)[0].(map[string]any)
+	assert.Equal(t, "build.log", info["display_name"])
+
+	code, _ = callRoute(t, app, apiAttachments(app), http.MethodGet, "/", map[string]string{"id": "WRK-9"}, "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestAPIFlowReport(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	_, task := setupSprintBoard(t, app)
+
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	tasks.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+	require.NoError(t, app.Save(tasks))
+
+	// An agent finished the task an hour ago, a day after it was created
+	created := types.NowDateTime().Add(-25 * time.Hour)
+	_, err = app.DB().NewQuery("UPDATE tasks SET [[column]] = 'done', created = {:created} WHERE id = {:id}").
+		Bind(dbx.Params{"id": task.Id, "created": created.String()}).Execute()
+	require.NoError(t, err)
+	require.NoError(t, taskevent.Save(app, taskevent.Event{
+		Task: task.Id, Action: "moved", Actor: "agent", ActorDetail: "claude",
+		Changes:   map[string]any{"column": map[string]any{"from": "todo", "to": "done"}},
+		Timestamp: time.Now().Add(-time.Hour),
+	}))
+	since := url.QueryEscape(time.Now().Add(-2 * time.Hour).Format(time.RFC3339))
+
+	code, result := callRoute(t, app, apiFlowReport(app), http.MethodGet, "/api/egenskriven/reports/flow?board=WRK&since="+since, nil, "")
+	require.Equal(t, http.StatusOK, code, result)
+	assert.Equal(t, "Work", result["board"])
+	var report flow.Report
+	require.NoError(t, resultValue(result, "report", &report))
+	assert.Equal(t, 1, report.Overall.Completed)
+	assert.Equal(t, 1, report.ByActor[flow.ActorAgent].Completed)
+
+	code, _ = callRoute(t, app, apiFlowReport(app), http.MethodGet, "/api/egenskriven/reports/flow?since=yesterday", nil, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = callRoute(t, app, apiFlowReport(app), http.MethodGet, "/api/egenskriven/reports/flow?board=OPS", nil, "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestAPIExport(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	setupSprintBoard(t, app)
+
+	export := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		re := &core.RequestEvent{App: app}
+		re.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		re.Response = rec
+		require.NoError(t, apiExport(app)(re))
+		return rec
+	}
+
+	rec := export("/api/egenskriven/export?board=WRK")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var data ExportData
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &data))
+	require.Len(t, data.Tasks, 1)
+	assert.Equal(t, "Parser", data.Tasks[0].Title)
+	assert.Equal(t, "Exported 1 boards, 0 epics, 1 tasks", rec.Header().Get(exportSummaryHeader))
+
+	rec = export("/api/egenskriven/export?format=csv")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "Parser")
+
+	code, result := callRoute(t, app, apiExport(app), http.MethodGet, "/api/egenskriven/export?format=pdf", nil, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, float64(ExitValidation), result["data"].(map[string]any)["exit_code"])
+	code, _ = callRoute(t, app, apiExport(app), http.MethodGet, "/api/egenskriven/export?format=csv&epic=Q1", nil, "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = callRoute(t, app, apiExport(app), http.MethodGet, "/api/egenskriven/export?board=OPS", nil, "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,320 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+)
+
+func newArchiveCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := newArchiveTasksCmd(app, false)
+	cmd.AddCommand(newArchiveRunCmd(app))
+	return cmd
+}
+
+func newUnarchiveCmd(app *pocketbase.PocketBase) *cobra.Command {
+	return newArchiveTasksCmd(app, true)
+}
+
+// newArchiveTasksCmd creates the 'archive' or 'unarchive' command.
+func newArchiveTasksCmd(app *pocketbase.PocketBase, unarchive bool) *cobra.Command {
+	var agentName string
+
+	cmd := &cobra.Command{
+		Use:   "archive <task> [task...]",
+		Short: "Archive tasks",
+		Long: `Archive tasks to hide them from the board without deleting them.
+
+Archived tasks keep their column and data. They are left out of 'list',
+'export', and the TUI unless --include-archived or --archived is given,
+and can be brought back with 'egenskriven unarchive'.
+
+Boards can archive done tasks automatically, see 'egenskriven archive run'
+and 'egenskriven board update --auto-archive'.`,
+		Example: `  egenskriven archive WRK-12
+  egenskriven archive WRK-12 WRK-15 WRK-16
+  egenskriven list --archived`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			caller := resolveCaller(app, agentName)
+
+			// Resolve all tasks first
+			var tasks []*core.Record
+			for _, ref := range args {
+				task, err := resolveTask(app, ref)
+				if err != nil {
+					if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+						return out.AmbiguousError(ref, ambErr.Matches)
+					}
+					return out.Error(ExitNotFound, err.Error(), nil)
+				}
+				if err := checkTaskPolicy(app, caller, task, policy.ActionUpdate).Err(); err != nil {
+					return policyDenied(out, err)
+				}
+				tasks = append(tasks, task)
+			}
+
+			action, verb := "archive", "Archived"
+			if unarchive {
+				action, verb = "unarchive", "Unarchived"
+			}
+
+			var changed []map[string]any
+			for _, task := range tasks {
+				var result map[string]any
+				var err error
+				if isRemoteMode() {
+					result, err = remoteClient().ArchiveTask(task.Id, action, archiveRequest{Agent: requestAgent(caller)})
+					if err != nil {
+						return remoteFailed(out, err)
+					}
+				} else {
+					result, err = archiveTask(app, task, unarchive, "cli", caller.Name)
+					if err != nil {
+						return commandFailed(out, err)
+					}
+				}
+
+				displayID := resultString(result, "display_id")
+				if skipped := resultString(result, "skipped"); skipped != "" {
+					warnLog("%s: %s", displayID, skipped)
+					continue
+				}
+				changed = append(changed, result)
+				if !jsonOutput && !quietMode {
+					fmt.Printf("%s: [%s] %s\n", verb, displayID, resultString(result, "title"))
+				}
+			}
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{
+					strings.ToLower(verb): changed,
+					"count":               len(changed),
+				})
+			}
+			return nil
+		},
+	}
+
+	if unarchive {
+		cmd.Use = "unarchive <task> [task...]"
+		cmd.Short = "Unarchive tasks"
+		cmd.Long = `Return archived tasks to their board, in the column they were archived in.
+
+Find archived tasks with 'egenskriven list --archived'.`
+		cmd.Example = `  egenskriven unarchive WRK-12
+  egenskriven unarchive WRK-12 WRK-15`
+	}
+
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent identifier (subject to the board's agent mode)")
+
+	return cmd
+}
+
+// newArchiveRunCmd creates the 'archive run' subcommand.
+func newArchiveRunCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef string
+		dryRun   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the auto-archive policies now",
+		Long: `Archive the done tasks of every board with an auto-archive policy that
+have been done for longer than the board's limit. The server runs this
+every hour; this command runs it immediately.
+
+Set a board's policy with 'egenskriven board update <board> --auto-archive 14d'.`,
+		Example: `  egenskriven archive run
+  egenskriven archive run --board WRK --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			var result map[string]any
+			var err error
+			if isRemoteMode() {
+				result, err = remoteClient().RunArchive(archiveRequest{Board: boardRef, DryRun: dryRun})
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				var boardRecord *core.Record
+				if boardRef != "" {
+					if boardRecord, err = board.GetByNameOrPrefix(app, boardRef); err != nil {
+						return out.Error(ExitNotFound, fmt.Sprintf("board not found: %s", boardRef), nil)
+					}
+				}
+				result, err = runArchive(app, boardRecord, dryRun, "cli", undo.Current())
+				if err != nil {
+					return commandFailed(out, err)
+				}
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			verb := "Archived"
+			if dryRun {
+				verb = "Would archive"
+			}
+			if !quietMode {
+				var boards []struct {
+					Board string   `json:"board"`
+					Days  int      `json:"days"`
+					Tasks []string `json:"tasks"`
+				}
+				if err := resultValue(result, "boards", &boards); err != nil {
+					return out.Error(ExitGeneralError, err.Error(), nil)
+				}
+				for _, b := range boards {
+					fmt.Printf("%s %d task(s) on %s (done over %d days): %s\n", verb, len(b.Tasks),
+						b.Board, b.Days, strings.Join(b.Tasks, ", "))
+				}
+			}
+			out.Success(fmt.Sprintf("%s %d task(s)", verb, resultInt(result, "count")))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Only run the policy of this board")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the tasks that would be archived")
+
+	return cmd
+}
+
+// archiveTask archives or unarchives a task. A task that already is (or
+// is not) archived is left alone and reported as skipped.
+func archiveTask(app *pocketbase.PocketBase, task *core.Record, unarchive bool, source, actor string) (map[string]any, error) {
+	var err error
+	if unarchive {
+		err = archive.Unarchive(app, task, source, actor)
+	} else {
+		err = archive.Task(app, task, source, actor)
+	}
+
+	displayID := getTaskDisplayID(app, task)
+	result := map[string]any{
+		"id":         task.Id,
+		"display_id": displayID,
+		"title":      task.GetString("title"),
+	}
+	if errors.Is(err, archive.ErrArchived) || errors.Is(err, archive.ErrNotArchived) {
+		result["skipped"] = err.Error()
+		return result, nil
+	}
+	if err != nil {
+		action := "archive"
+		if unarchive {
+			action = "unarchive"
+		}
+		return nil, fmt.Errorf("failed to %s task %s: %w", action, displayID, err)
+	}
+	return result, nil
+}
+
+// runArchive runs the auto-archive policy of a board, or of every board
+// with one when boardRecord is nil.
+func runArchive(app *pocketbase.PocketBase, boardRecord *core.Record, dryRun bool, source string, batch undo.Batch) (map[string]any, error) {
+	opts := archive.RunOptions{
+		Source: source,
+		Batch:  batch,
+		DryRun: dryRun,
+	}
+	if boardRecord != nil {
+		if archive.PolicyDays(boardRecord) == 0 {
+			return nil, &commandError{
+				code:    ExitValidation,
+				message: fmt.Sprintf("board %s has no auto-archive policy", boardRecord.GetString("name")),
+				suggestion: fmt.Sprintf("Set one with 'egenskriven board update %s --auto-archive 14d'",
+					boardRecord.GetString("prefix")),
+			}
+		}
+		opts.Board = boardRecord.Id
+	}
+
+	results, err := archive.Run(app, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run auto-archive: %w", err)
+	}
+
+	total := 0
+	boards := make([]map[string]any, 0, len(results))
+	for _, r := range results {
+		prefix := r.Board.GetString("prefix")
+		ids := []string{}
+		for _, task := range r.Tasks {
+			ids = append(ids, board.FormatDisplayID(prefix, task.GetInt("seq")))
+		}
+		total += len(r.Tasks)
+		boards = append(boards, map[string]any{
+			"board": r.Board.GetString("name"),
+			"days":  r.Days,
+			"tasks": ids,
+			"count": len(ids),
+		})
+	}
+	return map[string]any{
+		"boards":  boards,
+		"count":   total,
+		"dry_run": dryRun,
+	}, nil
+}
+
+// parseAutoArchive parses an auto-archive policy: a number of days ("14",
+// "14d"), weeks ("2w"), or "off".
+func parseAutoArchive(value string) (int, error) {
+	input := strings.TrimSpace(strings.ToLower(value))
+	if input == "off" || input == "never" {
+		return 0, nil
+	}
+
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(input, "d"):
+		input = strings.TrimSuffix(input, "d")
+	case strings.HasSuffix(input, "w"):
+		input = strings.TrimSuffix(input, "w")
+		multiplier = 7
+	}
+
+	n, err := strconv.Atoi(input)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid auto-archive policy %q: use a number of days (14d), weeks (2w), or off", value)
+	}
+	return n * multiplier, nil
+}
+
+// describeAutoArchive formats a board's auto-archive policy.
+func describeAutoArchive(days int) string {
+	if days == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("done tasks after %d days", days)
+}
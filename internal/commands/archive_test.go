@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAutoArchive(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"14", 14},
+		{"14d", 14},
+		{"2w", 14},
+		{" 30D ", 30},
+		{"off", 0},
+		{"never", 0},
+		{"0", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseAutoArchive(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, input := range []string{"", "soon", "-3", "1.5d"} {
+		_, err := parseAutoArchive(input)
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,519 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+)
+
+// ========== Attach ==========
+
+func newAttachCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var agentName string
+
+	cmd := &cobra.Command{
+		Use:   "attach <task> <file> [file...]",
+		Short: "Attach files to a task",
+		Long: `Attach files such as screenshots, logs, or specs to a task.
+
+Files are stored in the data directory and served by the web UI. The size
+limits come from the attachments section of .egenskriven/config.json:
+
+  "attachments": {"max_file_mb": 10, "max_total_mb": 50}
+
+max_file_mb limits each file and max_total_mb all files of one task
+(defaults: 10 and 50). Use 'comment --attach' to attach files to a comment.`,
+		Example: `  egenskriven attach WRK-1 screenshot.png
+  egenskriven attach WRK-1 build.log trace.txt --json`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			task, err := resolveAttachmentTask(app, out, args[0], agentName)
+			if err != nil {
+				return err
+			}
+
+			files, err := openAttachmentFiles(out, args[1:])
+			if err != nil {
+				return err
+			}
+
+			var added []string
+			for _, file := range files {
+				added = append(added, attachment.DisplayName(file.Name))
+			}
+			before := len(attachment.Names(task))
+			if isRemoteMode() {
+				// The API appends uploaded files to the stored ones
+				task.Set(attachment.Field, files)
+			} else {
+				task.Set(attachment.Field+"+", files)
+			}
+			addTaskEvent(task, "updated", agentName, map[string]any{
+				"attachments": map[string]any{
+					"from":  before,
+					"to":    before + len(files),
+					"files": added,
+				},
+			})
+
+			// Files cannot be sent through the task update API, so
+			// attachments are saved directly, or uploaded through the
+			// record API in remote mode
+			if err := saveRecord(app, task); err != nil {
+				return attachmentSaveError(out, err)
+			}
+
+			result, err := listAttachments(app, task)
+			if err != nil {
+				return out.Error(ExitGeneralError, err.Error(), nil)
+			}
+			return writeAttachments(out, result,
+				fmt.Sprintf("Attached %d file(s) to %s", len(files), resultString(result, "display_id")))
+		},
+	}
+
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent identifier (subject to the board's agent mode)")
+
+	return cmd
+}
+
+// ========== Attachments ==========
+
+func newAttachmentsCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attachments",
+		Short: "List, download, and remove task attachments",
+		Long: `Manage the files attached to a task and its comments.
+
+Attachments are referred to by number (see 'attachments list'), by file
+name, or by a unique part of the name.`,
+	}
+
+	cmd.AddCommand(newAttachmentsListCmd(app))
+	cmd.AddCommand(newAttachmentsGetCmd(app))
+	cmd.AddCommand(newAttachmentsRmCmd(app))
+
+	return cmd
+}
+
+// ========== Attachments List ==========
+
+func newAttachmentsListCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <task>",
+		Short: "List the files attached to a task and its comments",
+		Example: `  egenskriven attachments list WRK-1
+  egenskriven attachments list WRK-1 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			result, _, err := resolveTaskAttachments(app, out, args[0])
+			if err != nil {
+				return err
+			}
+
+			if !jsonOutput && resultInt(result, "count") == 0 {
+				fmt.Printf("No attachments on %s\n", resultString(result, "display_id"))
+				return nil
+			}
+			return writeAttachments(out, result, "")
+		},
+	}
+
+	return cmd
+}
+
+// ========== Attachments Get ==========
+
+func newAttachmentsGetCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		outputPath string
+		force      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get <task> <attachment>",
+		Short: "Download an attached file",
+		Long: `Save an attached file to disk.
+
+The file is written to the current directory under its name, or to the path
+given with --output (a directory or a file name). Use '--output -' to write
+it to stdout.`,
+		Example: `  egenskriven attachments get WRK-1 1
+  egenskriven attachments get WRK-1 screenshot.png -o /tmp
+  egenskriven attachments get WRK-1 build.log -o - | less`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			result, infos, err := resolveTaskAttachments(app, out, args[0])
+			if err != nil {
+				return err
+			}
+			i, err := attachment.Find(infos, args[1])
+			if err != nil {
+				return out.Error(ExitNotFound, err.Error(), nil)
+			}
+			info := infos[i]
+
+			reader, err := openAttachment(app, resultString(result, "task_id"), info)
+			if err != nil {
+				return out.Error(ExitGeneralError, err.Error(), nil)
+			}
+			defer reader.Close()
+
+			if outputPath == "-" {
+				if _, err := io.Copy(os.Stdout, reader); err != nil {
+					return out.Error(ExitGeneralError, fmt.Sprintf("failed to write %s: %v", info.DisplayName, err), nil)
+				}
+				return nil
+			}
+
+			path := info.DisplayName
+			if outputPath != "" {
+				path = outputPath
+				if stat, err := os.Stat(outputPath); err == nil && stat.IsDir() {
+					path = filepath.Join(outputPath, info.DisplayName)
+				}
+			}
+			if _, err := os.Stat(path); err == nil && !force {
+				return out.ErrorWithSuggestion(ExitConflict,
+					fmt.Sprintf("%s already exists", path),
+					"Use --force to overwrite it or --output to choose another path", nil)
+			}
+
+			file, err := os.Create(path)
+			if err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to create %s: %v", path, err), nil)
+			}
+			defer file.Close()
+			written, err := io.Copy(file, reader)
+			if err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to write %s: %v", path, err), nil)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{
+					"name": info.Name,
+					"path": path,
+					"size": written,
+				})
+				return nil
+			}
+			out.Success(fmt.Sprintf("Saved %s (%s)", path, attachment.FormatSize(written)))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output directory or file ('-' for stdout)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing file")
+
+	return cmd
+}
+
+// ========== Attachments Rm ==========
+
+func newAttachmentsRmCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var agentName string
+
+	cmd := &cobra.Command{
+		Use:     "rm <task> <attachment> [attachment...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove attached files",
+		Long: `Remove files attached to a task or its comments.
+
+Removed files are deleted from the data directory and cannot be restored
+with undo.`,
+		Example: `  egenskriven attachments rm WRK-1 2
+  egenskriven attachments rm WRK-1 screenshot.png build.log`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			task, err := resolveAttachmentTask(app, out, args[0], agentName)
+			if err != nil {
+				return err
+			}
+			result, err := listAttachments(app, task)
+			if err != nil {
+				return out.Error(ExitGeneralError, err.Error(), nil)
+			}
+			var infos []attachment.Info
+			if err := resultValue(result, "attachments", &infos); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to list attachments: %v", err), nil)
+			}
+
+			// Resolve all references before removing anything, grouping
+			// the files by the task or comment holding them
+			var removed []attachment.Info
+			byRecord := make(map[string][]string)
+			var holders []string
+			seen := make(map[int]bool)
+			for _, ref := range args[1:] {
+				i, err := attachment.Find(infos, ref)
+				if err != nil {
+					return out.Error(ExitNotFound, err.Error(), nil)
+				}
+				if seen[i] {
+					continue
+				}
+				seen[i] = true
+				info := infos[i]
+				if _, ok := byRecord[info.Comment]; !ok {
+					holders = append(holders, info.Comment)
+				}
+				byRecord[info.Comment] = append(byRecord[info.Comment], info.Name)
+				removed = append(removed, info)
+			}
+
+			for _, comment := range holders {
+				record := task
+				if comment != "" {
+					if record, err = findRecordByID(app, "comments", comment); err != nil {
+						return out.Error(ExitGeneralError, fmt.Sprintf("failed to load comment %s: %v", shortID(comment), err), nil)
+					}
+				}
+				names := byRecord[comment]
+				before := attachment.Names(record)
+				removeAttachments(record, before, names)
+				if record == task {
+					var displayNames []string
+					for _, name := range names {
+						displayNames = append(displayNames, attachment.DisplayName(name))
+					}
+					addTaskEvent(task, "updated", agentName, map[string]any{
+						"attachments": map[string]any{
+							"from":  len(before),
+							"to":    len(before) - len(names),
+							"files": displayNames,
+						},
+					})
+				}
+				if err := saveRecord(app, record); err != nil {
+					return attachmentSaveError(out, err)
+				}
+			}
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{
+					"task_id":    task.Id,
+					"display_id": resultString(result, "display_id"),
+					"removed":    removed,
+				})
+				return nil
+			}
+			out.Success(fmt.Sprintf("Removed %d attachment(s) from %s", len(removed), resultString(result, "display_id")))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent identifier (subject to the board's agent mode)")
+
+	return cmd
+}
+
+// resolveTaskAttachments resolves a task and lists the files attached to
+// it and its comments, on the server in remote mode.
+func resolveTaskAttachments(app *pocketbase.PocketBase, out *output.Formatter, ref string) (map[string]any, []attachment.Info, error) {
+	var result map[string]any
+	var err error
+	if isRemoteMode() {
+		if result, err = remoteClient().Attachments(ref); err != nil {
+			return nil, nil, remoteFailed(out, err)
+		}
+	} else {
+		task, err := resolver.MustResolve(app, ref)
+		if err != nil {
+			if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+				return nil, nil, out.AmbiguousError(ref, ambErr.Matches)
+			}
+			return nil, nil, out.Error(ExitNotFound, err.Error(), nil)
+		}
+		if result, err = taskAttachments(app, task); err != nil {
+			return nil, nil, out.Error(ExitGeneralError, err.Error(), nil)
+		}
+	}
+
+	var infos []attachment.Info
+	if err := resultValue(result, "attachments", &infos); err != nil {
+		return nil, nil, out.Error(ExitGeneralError, fmt.Sprintf("failed to list attachments: %v", err), nil)
+	}
+	return result, infos, nil
+}
+
+// listAttachments returns the files attached to a task and its comments,
+// as printed by 'attachments list --json', with the task's title. In
+// remote mode the server lists them.
+func listAttachments(app *pocketbase.PocketBase, task *core.Record) (map[string]any, error) {
+	if isRemoteMode() {
+		return remoteClient().Attachments(task.Id)
+	}
+	return taskAttachments(app, task)
+}
+
+// taskAttachments lists the files attached to a task and its comments
+// like listAttachments, on this database.
+func taskAttachments(app *pocketbase.PocketBase, task *core.Record) (map[string]any, error) {
+	infos, err := attachment.ForTask(app, task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	if infos == nil {
+		infos = []attachment.Info{}
+	}
+	return map[string]any{
+		"task_id":     task.Id,
+		"display_id":  getTaskDisplayID(app, task),
+		"title":       task.GetString("title"),
+		"attachments": infos,
+		"count":       len(infos),
+		"total_size":  attachment.TotalSize(infos),
+	}, nil
+}
+
+// openAttachment returns a reader for the content of a file attached to
+// a task or its comments, downloaded from the server in remote mode.
+func openAttachment(app *pocketbase.PocketBase, taskID string, info attachment.Info) (io.ReadCloser, error) {
+	if !isRemoteMode() {
+		return attachment.Open(app, info)
+	}
+	if info.Comment != "" {
+		return remoteClient().DownloadFile("comments", info.Comment, info.Name)
+	}
+	return remoteClient().DownloadFile("tasks", taskID, info.Name)
+}
+
+// removeAttachments removes the files names from a task or comment
+// holding the files stored. The record API of remote mode takes the files
+// that remain.
+func removeAttachments(record *core.Record, stored, names []string) {
+	if !isRemoteMode() {
+		record.Set(attachment.Field+"-", names)
+		return
+	}
+	remaining := make([]string, 0, len(stored))
+	for _, name := range stored {
+		if !containsString(names, name) {
+			remaining = append(remaining, name)
+		}
+	}
+	record.Set(attachment.Field, remaining)
+}
+
+// resolveAttachmentTask resolves a task whose attachments are about to
+// change and checks that the caller may update it.
+func resolveAttachmentTask(app *pocketbase.PocketBase, out *output.Formatter, ref, agentName string) (*core.Record, error) {
+	task, err := resolveTask(app, ref)
+	if err != nil {
+		if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+			return nil, out.AmbiguousError(ref, ambErr.Matches)
+		}
+		return nil, out.Error(ExitNotFound, err.Error(), nil)
+	}
+	if !isRemoteMode() && !attachment.Supported(task) {
+		return nil, out.Error(ExitGeneralError, "tasks have no attachments field - run migrations first", nil)
+	}
+	if err := checkTaskPolicy(app, resolveCaller(app, agentName), task, policy.ActionUpdate).Err(); err != nil {
+		return nil, policyDenied(out, err)
+	}
+	return task, nil
+}
+
+// openAttachmentFiles prepares files on disk for upload.
+func openAttachmentFiles(out *output.Formatter, paths []string) ([]*filesystem.File, error) {
+	files := make([]*filesystem.File, 0, len(paths))
+	for _, path := range paths {
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, out.Error(ExitNotFound, fmt.Sprintf("cannot attach %s: %v", path, err), nil)
+		}
+		if stat.IsDir() {
+			return nil, out.Error(ExitValidation, fmt.Sprintf("cannot attach %s: is a directory", path), nil)
+		}
+		file, err := filesystem.NewFileFromPath(path)
+		if err != nil {
+			return nil, out.Error(ExitGeneralError, fmt.Sprintf("cannot attach %s: %v", path, err), nil)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// attachmentSaveError reports a failed save of a task or comment with
+// files, as a validation error when a size limit was exceeded.
+func attachmentSaveError(out *output.Formatter, err error) error {
+	if errors.Is(err, attachment.ErrTooLarge) {
+		return out.ErrorWithSuggestion(ExitValidation, err.Error(),
+			"Raise attachments.max_file_mb or attachments.max_total_mb in .egenskriven/config.json", nil)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return remoteFailed(out, err)
+	}
+	return out.Error(ExitGeneralError, fmt.Sprintf("failed to save attachments: %v", err), nil)
+}
+
+// writeAttachments prints the attachments of a task as listed by
+// listAttachments, after message when given.
+func writeAttachments(out *output.Formatter, result map[string]any, message string) error {
+	if jsonOutput {
+		out.WriteJSON(result)
+		return nil
+	}
+
+	if message != "" {
+		out.Success(message)
+		if quietMode {
+			return nil
+		}
+	}
+	var infos []attachment.Info
+	if err := resultValue(result, "attachments", &infos); err != nil {
+		return out.Error(ExitGeneralError, fmt.Sprintf("failed to list attachments: %v", err), nil)
+	}
+	fmt.Printf("Attachments of %s: %s (%d, %s)\n", resultString(result, "display_id"), resultString(result, "title"),
+		len(infos), attachment.FormatSize(attachment.TotalSize(infos)))
+	for i, info := range infos {
+		line := fmt.Sprintf("  %2d. %-32s %10s", i+1, info.DisplayName, attachment.FormatSize(info.Size))
+		if info.Comment != "" {
+			line += "  on comment " + shortID(info.Comment)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/auth"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+)
+
+func newAuthCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Enable or disable API authentication",
+		Long: `Manage opt-in authentication for the HTTP API.
+
+By default the API is open to anything that can reach the server, which
+is fine on localhost. With auth enabled, every API request must carry a
+token created with 'egenskriven token create'. Tokens are limited to read
+or write access and optionally to a single board.
+
+The CLI and TUI send the token from $EGENSKRIVEN_TOKEN, falling back to
+server.token in ~/.config/egenskriven/config.json. Direct database access
+(--direct) is not affected.`,
+	}
+
+	cmd.AddCommand(newAuthEnableCmd(app))
+	cmd.AddCommand(newAuthDisableCmd(app))
+	cmd.AddCommand(newAuthStatusCmd(app))
+
+	return cmd
+}
+
+// ========== Auth Enable ==========
+
+func newAuthEnableCmd(app *pocketbase.PocketBase) *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable",
+		Short: "Require a token for API access",
+		Example: `  egenskriven auth enable
+  egenskriven token create --scope write --name laptop`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setAuthMode(app, true)
+		},
+	}
+}
+
+// ========== Auth Disable ==========
+
+func newAuthDisableCmd(app *pocketbase.PocketBase) *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable",
+		Short: "Make the API public again",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setAuthMode(app, false)
+		},
+	}
+}
+
+// ========== Auth Status ==========
+
+func newAuthStatusCmd(app *pocketbase.PocketBase) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether API authentication is enabled",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := app.Bootstrap(); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			enabled := auth.Enabled(app)
+			tokens, _ := auth.Tokens(app)
+			hasToken := config.APIToken() != ""
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{
+					"enabled":          enabled,
+					"tokens":           len(tokens),
+					"token_configured": hasToken,
+				})
+				return nil
+			}
+
+			if enabled {
+				fmt.Println("Auth: enabled")
+			} else {
+				fmt.Println("Auth: disabled (API is public)")
+			}
+			fmt.Printf("Tokens: %d\n", len(tokens))
+			if hasToken {
+				fmt.Println("Client token: configured")
+			} else {
+				fmt.Printf("Client token: not set (use $%s or server.token in the global config)\n", config.TokenEnvVar)
+			}
+			return nil
+		},
+	}
+}
+
+// ========== Helper Functions ==========
+
+// setAuthMode applies the API rules for the given auth mode.
+func setAuthMode(app *pocketbase.PocketBase, enabled bool) error {
+	out := getFormatter()
+
+	if err := app.Bootstrap(); err != nil {
+		return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+	}
+
+	if err := checkPolicy(app, resolveCaller(app, ""), "", policy.ActionSetMode).Err(); err != nil {
+		return policyDenied(out, err)
+	}
+
+	if err := auth.ApplyRules(app, enabled); err != nil {
+		return out.Error(ExitGeneralError, fmt.Sprintf("failed to update API rules: %v", err), nil)
+	}
+
+	// A running server caches collection rules
+	serverRunning := !isDirectMode() && NewAPIClient().IsServerRunning()
+
+	if jsonOutput {
+		out.WriteJSON(map[string]any{
+			"enabled":          enabled,
+			"restart_required": serverRunning,
+		})
+		return nil
+	}
+
+	if enabled {
+		out.Success("API authentication enabled")
+		if tokens, _ := auth.Tokens(app); len(tokens) == 0 {
+			fmt.Println("Create a token with: egenskriven token create --scope write --name <name>")
+		}
+	} else {
+		out.Success("API authentication disabled")
+	}
+	if serverRunning {
+		fmt.Println("Restart the server for the change to take effect.")
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"time"
@@ -30,7 +31,8 @@ before major version upgrades or schema migrations to ensure you can
 restore your data if needed.
 
 The backup is a direct copy of the database file, which preserves all
-data including tasks, boards, epics, comments, and sessions.
+data including tasks, boards, epics, comments, and sessions. Attached
+files are copied to a <backup>.storage directory next to it.
 
 Examples:
   egenskriven backup                      # Create timestamped backup
@@ -86,6 +88,14 @@ Examples:
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to create backup: %v", err), nil)
 			}
 
+			// Copy attached files
+			storagePath := filepath.Join(dataDir, storageDirName)
+			storageBackupPath := backupPath + storageBackupSuffix
+			files, err := copyStorageDir(storagePath, storageBackupPath)
+			if err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to back up attachments: %v", err), nil)
+			}
+
 			// Get file size for display
 			var size string
 			var sizeBytes int64
@@ -98,18 +108,29 @@ Examples:
 			}
 
 			if jsonOutput {
-				out.WriteJSON(map[string]any{
+				result := map[string]any{
 					"backup_path": backupPath,
 					"source_path": dbPath,
 					"size":        sizeBytes,
 					"created":     time.Now().Format(time.RFC3339),
-				})
+				}
+				if files > 0 {
+					result["storage_path"] = storageBackupPath
+					result["files"] = files
+				}
+				out.WriteJSON(result)
 				return nil
 			}
 
 			fmt.Printf("Backup created: %s (%s)\n", backupPath, size)
+			if files > 0 {
+				fmt.Printf("Attachments:    %s (%d files)\n", storageBackupPath, files)
+			}
 			fmt.Printf("\nTo restore, stop egenskriven and run:\n")
 			fmt.Printf("  cp %s %s\n", backupPath, dbPath)
+			if files > 0 {
+				fmt.Printf("  rm -rf %s && cp -r %s %s\n", storagePath, storageBackupPath, storagePath)
+			}
 
 			return nil
 		},
@@ -157,6 +178,39 @@ func copyDatabaseFile(src, dst string) error {
 	return nil
 }
 
+// copyStorageDir copies the file storage directory (holding attachments)
+// to dst and returns the number of files copied. A missing or empty
+// storage directory copies nothing.
+func copyStorageDir(src, dst string) (int, error) {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	files := 0
+	err := filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := copyDatabaseFile(path, target); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	return files, err
+}
+
 // listBackups lists existing backup files in the data directory
 func listBackups(dataDir string, out *output.Formatter) error {
 	entries, err := os.ReadDir(dataDir)
@@ -176,12 +230,16 @@ func listBackups(dataDir string, out *output.Formatter) error {
 			if err != nil {
 				continue
 			}
-			backups = append(backups, map[string]any{
+			backup := map[string]any{
 				"name":    name,
 				"path":    filepath.Join(dataDir, name),
 				"size":    info.Size(),
 				"created": info.ModTime().Format(time.RFC3339),
-			})
+			}
+			if stat, err := os.Stat(filepath.Join(dataDir, name+storageBackupSuffix)); err == nil && stat.IsDir() {
+				backup["storage_path"] = filepath.Join(dataDir, name+storageBackupSuffix)
+			}
+			backups = append(backups, backup)
 		}
 	}
 
@@ -202,6 +260,9 @@ func listBackups(dataDir string, out *output.Formatter) error {
 	fmt.Printf("Backups in %s:\n\n", dataDir)
 	for _, b := range backups {
 		size := formatFileSize(b["size"].(int64))
+		if _, ok := b["storage_path"]; ok {
+			size += ", with attachments"
+		}
 		fmt.Printf("  %s (%s)\n", b["name"], size)
 	}
 
@@ -210,8 +271,10 @@ func listBackups(dataDir string, out *output.Formatter) error {
 
 // Backup file naming constants
 const (
-	backupPrefix = "data.db.backup"
-	mainDBName   = "data.db"
+	backupPrefix        = "data.db.backup"
+	mainDBName          = "data.db"
+	storageDirName      = "storage"  // PocketBase file storage in the data directory
+	storageBackupSuffix = ".storage" // Directory holding a backup's attachments
 )
 
 // isBackupFile checks if a filename looks like a backup file
@@ -8,6 +8,7 @@ import (
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
 	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
 )
 
@@ -25,6 +26,8 @@ func setupBenchmarkEnv(b *testing.B) *pocketbase.PocketBase {
 	// Set up collections
 	setupBenchmarkTasksCollection(b, app)
 	setupBenchmarkCommentsCollection(b, app)
+	testutil.CreateTaskEventsCollection(t, app)
+	taskevent.Bind(app)
 
 	return app
 }
@@ -65,7 +68,6 @@ func setupBenchmarkTasksCollection(b *testing.B, app *pocketbase.PocketBase) {
 		Values:   []string{"user", "agent", "cli"},
 	})
 	collection.Fields.Add(&core.TextField{Name: "created_by_agent"})
-	collection.Fields.Add(&core.JSONField{Name: "history"})
 	collection.Fields.Add(&core.AutodateField{
 		Name:     "updated",
 		OnCreate: true,
@@ -124,7 +126,6 @@ func createBenchmarkTask(b *testing.B, app *pocketbase.PocketBase, title, column
 	record.Set("labels", []string{})
 	record.Set("blocked_by", []string{})
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 
 	if err := app.Save(record); err != nil {
 		b.Fatalf("failed to create task: %v", err)
@@ -205,7 +206,7 @@ func BenchmarkBlockTask(b *testing.B) {
 		err := app.RunInTransaction(func(txApp core.App) error {
 			task.Set("column", "need_input")
 
-			addHistoryEntry(task, "blocked", "benchmark-agent", map[string]any{
+			addTaskEvent(task, "blocked", "benchmark-agent", map[string]any{
 				"column": map[string]any{
 					"from": currentColumn,
 					"to":   "need_input",
@@ -483,18 +484,19 @@ func BenchmarkMentionExtraction(b *testing.B) {
 	}
 }
 
-// BenchmarkHistoryEntry measures the performance of adding history entries.
-func BenchmarkHistoryEntry(b *testing.B) {
+// BenchmarkTaskEvent measures the performance of saving a task with a
+// queued task event.
+func BenchmarkTaskEvent(b *testing.B) {
 	app := setupBenchmarkEnv(b)
 
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
 		b.StopTimer()
-		task := createBenchmarkTask(b, app, fmt.Sprintf("History benchmark task %d", i), "todo")
+		task := createBenchmarkTask(b, app, fmt.Sprintf("Event benchmark task %d", i), "todo")
 		b.StartTimer()
 
-		addHistoryEntry(task, "blocked", "benchmark-agent", map[string]any{
+		addTaskEvent(task, "blocked", "benchmark-agent", map[string]any{
 			"column": map[string]any{
 				"from": "todo",
 				"to":   "need_input",
@@ -503,7 +505,7 @@ func BenchmarkHistoryEntry(b *testing.B) {
 		})
 
 		if err := app.Save(task); err != nil {
-			b.Fatalf("failed to save task with history: %v", err)
+			b.Fatalf("failed to save task with event: %v", err)
 		}
 	}
 }
@@ -657,8 +659,8 @@ func BenchmarkSessionLink(b *testing.B) {
 			}
 			task.Set("agent_session", newSession)
 
-			// Add history entry
-			addHistoryEntry(task, "session_linked", "agent", map[string]any{
+			// Record the change in the event log
+			addTaskEvent(task, "session_linked", "agent", map[string]any{
 				"tool":        "claude-code",
 				"session_ref": fmt.Sprintf("benchmark-session-%d", i),
 			})
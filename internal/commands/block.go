@@ -2,6 +2,7 @@ package commands
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
 	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
 )
 
@@ -46,7 +48,7 @@ Use this when you're blocked and need human guidance to proceed.`,
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
@@ -66,95 +68,47 @@ Use this when you're blocked and need human guidance to proceed.`,
 				return out.Error(ExitInvalidArguments, "question is required: provide as argument or use --stdin", nil)
 			}
 
-			if question == "" {
-				return out.Error(ExitValidation, "question cannot be empty", nil)
+			// Determine agent name. Blocking is always done by an agent,
+			// so the board's agent mode applies.
+			caller := resolveCaller(app, agentName)
+			if !caller.IsAgent() {
+				caller = policy.Agent(getDefaultAgentName())
 			}
 
-			// Resolve the task
-			task, err := resolver.MustResolve(app, taskRef)
-			if err != nil {
-				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
-					return out.AmbiguousError(taskRef, ambErr.Matches)
+			var result map[string]any
+			if isRemoteMode() {
+				// The server resolves the task and blocks it atomically
+				var err error
+				result, err = remoteClient().BlockTask(taskRef, blockRequest{Question: question, Agent: caller.Name})
+				if err != nil {
+					return remoteFailed(out, err)
 				}
-				return out.Error(ExitNotFound, err.Error(), nil)
-			}
-
-			// Validate current state - can't block if already in need_input or done
-			currentColumn := task.GetString("column")
-			if currentColumn == "need_input" {
-				return out.Error(ExitValidation,
-					fmt.Sprintf("task %s is already blocked (in need_input)", shortID(task.Id)), nil)
-			}
-			if currentColumn == "done" {
-				return out.Error(ExitValidation, "cannot block a completed task", nil)
-			}
-
-			// Determine agent name
-			if agentName == "" {
-				agentName = getDefaultAgentName()
-			}
-
-			// Get comments collection
-			commentsCollection, err := app.FindCollectionByNameOrId("comments")
-			if err != nil {
-				return out.Error(ExitGeneralError, fmt.Sprintf("comments collection not found: %v", err), nil)
-			}
-
-			// Execute in transaction for atomicity
-			var commentId string
-			err = app.RunInTransaction(func(txApp core.App) error {
-				// 1. Update task column to need_input
-				task.Set("column", "need_input")
-
-				// 2. Add history entry
-				addHistoryEntry(task, "blocked", agentName, map[string]any{
-					"column": map[string]any{
-						"from": currentColumn,
-						"to":   "need_input",
-					},
-					"reason": question,
-				})
-
-				// Save task
-				if err := txApp.Save(task); err != nil {
-					return fmt.Errorf("failed to update task: %w", err)
+			} else {
+				// Resolve the task
+				task, err := resolver.MustResolve(app, taskRef)
+				if err != nil {
+					if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+						return out.AmbiguousError(taskRef, ambErr.Matches)
+					}
+					return out.Error(ExitNotFound, err.Error(), nil)
 				}
 
-				// 3. Create comment
-				comment := core.NewRecord(commentsCollection)
-				comment.Set("task", task.Id)
-				comment.Set("content", question)
-				comment.Set("author_type", "agent")
-				comment.Set("author_id", agentName)
-				comment.Set("metadata", map[string]any{
-					"action": "block_question",
-				})
-
-				if err := txApp.Save(comment); err != nil {
-					return fmt.Errorf("failed to create comment: %w", err)
+				commentId, err := blockTask(app, task, question, caller)
+				if err != nil {
+					var v *policy.Violation
+					if errors.As(err, &v) {
+						return policyDenied(out, err)
+					}
+					return commandFailed(out, err)
 				}
-
-				commentId = comment.Id
-				return nil
-			})
-
-			if err != nil {
-				return out.Error(ExitGeneralError, fmt.Sprintf("failed to block task: %v", err), nil)
+				result = blockResult(app, task, commentId)
 			}
 
 			// Get display ID for output
-			displayId := getTaskDisplayID(app, task)
+			displayId, _ := result["display_id"].(string)
 
 			// Output result
 			if jsonOutput {
-				result := map[string]any{
-					"success":    true,
-					"task_id":    task.Id,
-					"display_id": displayId,
-					"column":     "need_input",
-					"comment_id": commentId,
-					"message":    fmt.Sprintf("Task %s blocked, awaiting human input", displayId),
-				}
 				encoder := json.NewEncoder(os.Stdout)
 				encoder.SetIndent("", "  ")
 				if err := encoder.Encode(result); err != nil {
@@ -177,6 +131,91 @@ Use this when you're blocked and need human guidance to proceed.`,
 	return cmd
 }
 
+// blockTask moves a task to need_input and adds the question as a comment
+// of the calling agent, atomically, and returns the comment ID. The board's
+// agent mode applies to the move.
+func blockTask(app *pocketbase.PocketBase, task *core.Record, question string, caller policy.Actor) (string, error) {
+	if question == "" {
+		return "", newCommandError(ExitValidation, "question cannot be empty")
+	}
+
+	// Validate current state - can't block if already in need_input or done
+	currentColumn := task.GetString("column")
+	if currentColumn == "need_input" {
+		return "", newCommandError(ExitValidation, "task %s is already blocked (in need_input)", shortID(task.Id))
+	}
+	if currentColumn == "done" {
+		return "", newCommandError(ExitValidation, "cannot block a completed task")
+	}
+
+	if err := policy.CheckTask(app, caller, task, policy.ActionMove).Err(); err != nil {
+		return "", err
+	}
+	agentName := caller.Name
+
+	// Get comments collection
+	commentsCollection, err := app.FindCollectionByNameOrId("comments")
+	if err != nil {
+		return "", fmt.Errorf("comments collection not found: %w", err)
+	}
+
+	// Execute in transaction for atomicity
+	var commentId string
+	err = app.RunInTransaction(func(txApp core.App) error {
+		// 1. Update task column to need_input
+		task.Set("column", "need_input")
+
+		// 2. Record the change in the event log
+		addTaskEvent(task, "blocked", agentName, map[string]any{
+			"column": map[string]any{
+				"from": currentColumn,
+				"to":   "need_input",
+			},
+			"reason": question,
+		})
+
+		// Save task
+		if err := txApp.Save(task); err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+
+		// 3. Create comment
+		comment := core.NewRecord(commentsCollection)
+		comment.Set("task", task.Id)
+		comment.Set("content", question)
+		comment.Set("author_type", "agent")
+		comment.Set("author_id", agentName)
+		comment.Set("metadata", map[string]any{
+			"action": "block_question",
+		})
+
+		if err := txApp.Save(comment); err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+
+		commentId = comment.Id
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to block task: %w", err)
+	}
+
+	return commentId, nil
+}
+
+// blockResult is the JSON output of a block, also returned by the REST route.
+func blockResult(app *pocketbase.PocketBase, task *core.Record, commentId string) map[string]any {
+	displayId := getTaskDisplayID(app, task)
+	return map[string]any{
+		"success":    true,
+		"task_id":    task.Id,
+		"display_id": displayId,
+		"column":     "need_input",
+		"comment_id": commentId,
+		"message":    fmt.Sprintf("Task %s blocked, awaiting human input", displayId),
+	}
+}
+
 // getDefaultAgentName returns the default agent name from config.
 func getDefaultAgentName() string {
 	cfg, err := config.LoadGlobalConfig()
@@ -195,7 +234,7 @@ func getTaskDisplayID(app *pocketbase.PocketBase, task *core.Record) string {
 	seq := task.GetInt("seq")
 
 	if boardID != "" && seq > 0 {
-		boardRecord, err := app.FindRecordById("boards", boardID)
+		boardRecord, err := findRecordByID(app, "boards", boardID)
 		if err == nil {
 			prefix := boardRecord.GetString("prefix")
 			return fmt.Sprintf("%s-%d", prefix, seq)
@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"encoding/json"
 	"testing"
 
 	"github.com/pocketbase/dbx"
@@ -13,43 +12,8 @@ import (
 	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
 )
 
-// getHistoryFromTask extracts history from a task record, handling the types.JSONRaw type
-func getHistoryFromTask(t *testing.T, task *core.Record) []map[string]any {
-	t.Helper()
-
-	raw := task.Get("history")
-	if raw == nil {
-		return []map[string]any{}
-	}
-
-	// Handle native []any type (before save)
-	if h, ok := raw.([]any); ok {
-		result := make([]map[string]any, len(h))
-		for i, entry := range h {
-			if m, ok := entry.(map[string]any); ok {
-				result[i] = m
-			}
-		}
-		return result
-	}
-
-	// Handle types.JSONRaw (from database)
-	if jsonRaw, ok := raw.(interface{ String() string }); ok {
-		var history []map[string]any
-		jsonStr := jsonRaw.String()
-		if jsonStr == "" || jsonStr == "null" {
-			return []map[string]any{}
-		}
-		if err := json.Unmarshal([]byte(jsonStr), &history); err == nil {
-			return history
-		}
-	}
-
-	return []map[string]any{}
-}
-
 // simulateBlockTask simulates what the block command does:
-// moves task to need_input and adds history entry
+// moves task to need_input and records a blocked event
 func simulateBlockTask(t *testing.T, app *pocketbase.PocketBase, task *core.Record, question string, agentName string) {
 	t.Helper()
 
@@ -58,8 +22,8 @@ func simulateBlockTask(t *testing.T, app *pocketbase.PocketBase, task *core.Reco
 	// Update task column to need_input
 	task.Set("column", "need_input")
 
-	// Add history entry (same as block command does)
-	addHistoryEntry(task, "blocked", agentName, map[string]any{
+	// Record the event (same as block command does)
+	addTaskEvent(task, "blocked", agentName, map[string]any{
 		"column": map[string]any{
 			"from": currentColumn,
 			"to":   "need_input",
@@ -71,7 +35,7 @@ func simulateBlockTask(t *testing.T, app *pocketbase.PocketBase, task *core.Reco
 }
 
 // simulateBlockTaskWithComment simulates the full block command:
-// moves task to need_input, adds history entry, AND creates a comment
+// moves task to need_input, records a blocked event, AND creates a comment
 // This is used for integration tests that need the full workflow.
 func simulateBlockTaskWithComment(t *testing.T, app *pocketbase.PocketBase, task *core.Record, question string, agentName string) {
 	t.Helper()
@@ -87,8 +51,8 @@ func simulateBlockTaskWithComment(t *testing.T, app *pocketbase.PocketBase, task
 		// Update task column to need_input
 		task.Set("column", "need_input")
 
-		// Add history entry
-		addHistoryEntry(task, "blocked", agentName, map[string]any{
+		// Record the event
+		addTaskEvent(task, "blocked", agentName, map[string]any{
 			"column": map[string]any{
 				"from": currentColumn,
 				"to":   "need_input",
@@ -118,14 +82,14 @@ func simulateBlockTaskWithComment(t *testing.T, app *pocketbase.PocketBase, task
 
 // ========== Tests ==========
 
-func TestBlockCommand_HistoryIsUpdatedCorrectly(t *testing.T) {
+func TestBlockCommand_EventIsLoggedCorrectly(t *testing.T) {
 	app := testutil.NewTestApp(t)
 	SetupTasksCollection(t, app)
 	SetupCommentsCollection(t, app)
 
 	// Create a test task in todo
 	task := CreateTestTask(t, app, "Test Task", "todo")
-	initialHistoryLen := 0
+	initialEvents := len(GetTaskEvents(t, app, task.Id))
 
 	// Simulate blocking the task
 	question := "What authentication approach should I use?"
@@ -139,21 +103,21 @@ func TestBlockCommand_HistoryIsUpdatedCorrectly(t *testing.T) {
 	// Verify task is in need_input
 	assert.Equal(t, "need_input", task.GetString("column"))
 
-	// Verify history was updated
-	history := getHistoryFromTask(t, task)
-	assert.Len(t, history, initialHistoryLen+1, "history should have one new entry")
+	// Verify an event was logged
+	events := GetTaskEvents(t, app, task.Id)
+	require.Len(t, events, initialEvents+1, "blocking should log one new event")
 
-	// Get the last history entry
-	lastEntry := history[len(history)-1]
+	// Get the last event
+	lastEvent := events[len(events)-1]
 
-	// Verify history entry fields
-	assert.Equal(t, "blocked", lastEntry["action"], "action should be 'blocked'")
-	assert.Equal(t, "cli", lastEntry["actor"], "actor should be 'cli'")
-	assert.Equal(t, agentName, lastEntry["actor_detail"], "actor_detail should match agent name")
-	assert.NotEmpty(t, lastEntry["timestamp"], "timestamp should not be empty")
+	// Verify event fields
+	assert.Equal(t, "blocked", lastEvent.Action, "action should be 'blocked'")
+	assert.Equal(t, "cli", lastEvent.Actor, "actor should be 'cli'")
+	assert.Equal(t, agentName, lastEvent.ActorDetail, "actor_detail should match agent name")
+	assert.False(t, lastEvent.Timestamp.IsZero(), "timestamp should be set")
 
-	// Verify changes in history
-	changes, ok := lastEntry["changes"].(map[string]any)
+	// Verify changes in the event
+	changes, ok := lastEvent.Changes.(map[string]any)
 	require.True(t, ok, "changes should be a map")
 
 	// Verify column change
@@ -166,7 +130,7 @@ func TestBlockCommand_HistoryIsUpdatedCorrectly(t *testing.T) {
 	assert.Equal(t, question, changes["reason"], "reason should match the question")
 }
 
-func TestBlockCommand_HistoryFromInProgress(t *testing.T) {
+func TestBlockCommand_EventFromInProgress(t *testing.T) {
 	app := testutil.NewTestApp(t)
 	SetupTasksCollection(t, app)
 	SetupCommentsCollection(t, app)
@@ -182,10 +146,9 @@ func TestBlockCommand_HistoryFromInProgress(t *testing.T) {
 	task, err := app.FindRecordById("tasks", task.Id)
 	require.NoError(t, err)
 
-	history := getHistoryFromTask(t, task)
-	require.Greater(t, len(history), 0, "history should not be empty")
-	lastEntry := history[len(history)-1]
-	changes, ok := lastEntry["changes"].(map[string]any)
+	events := GetTaskEvents(t, app, task.Id)
+	require.NotEmpty(t, events, "events should not be empty")
+	changes, ok := events[len(events)-1].Changes.(map[string]any)
 	require.True(t, ok, "changes should be a map")
 	columnChange, ok := changes["column"].(map[string]any)
 	require.True(t, ok, "column change should be a map")
@@ -195,8 +158,8 @@ func TestBlockCommand_HistoryFromInProgress(t *testing.T) {
 	assert.Equal(t, "need_input", columnChange["to"], "column.to should be 'need_input'")
 }
 
-func TestBlockCommand_HistoryBlockedEntryStructure(t *testing.T) {
-	// This test verifies the complete structure of the blocked history entry
+func TestBlockCommand_BlockedEventStructure(t *testing.T) {
+	// This test verifies the complete structure of the blocked event
 	app := testutil.NewTestApp(t)
 	SetupTasksCollection(t, app)
 	SetupCommentsCollection(t, app)
@@ -213,19 +176,21 @@ func TestBlockCommand_HistoryBlockedEntryStructure(t *testing.T) {
 	task, err := app.FindRecordById("tasks", task.Id)
 	require.NoError(t, err)
 
-	history := getHistoryFromTask(t, task)
-	require.Len(t, history, 1, "should have exactly one history entry")
+	events := GetTaskEvents(t, app, task.Id)
+	require.Len(t, events, 2, "should have the created and blocked events")
+	assert.Equal(t, "created", events[0].Action)
 
-	entry := history[0]
+	event := events[1]
 
 	// Verify all required fields are present
-	assert.Equal(t, "blocked", entry["action"], "action should be 'blocked'")
-	assert.Equal(t, "cli", entry["actor"], "actor should be 'cli'")
-	assert.Equal(t, agentName, entry["actor_detail"], "actor_detail should be agent name")
-	assert.NotEmpty(t, entry["timestamp"], "timestamp should be set")
+	assert.Equal(t, "blocked", event.Action, "action should be 'blocked'")
+	assert.Equal(t, "cli", event.Actor, "actor should be 'cli'")
+	assert.Equal(t, agentName, event.ActorDetail, "actor_detail should be agent name")
+	assert.Equal(t, task.Id, event.Task, "event should reference the task")
+	assert.False(t, event.Timestamp.IsZero(), "timestamp should be set")
 
 	// Verify changes structure
-	changes, ok := entry["changes"].(map[string]any)
+	changes, ok := event.Changes.(map[string]any)
 	require.True(t, ok, "changes should be a map")
 
 	columnChange, ok := changes["column"].(map[string]any)
@@ -236,7 +201,7 @@ func TestBlockCommand_HistoryBlockedEntryStructure(t *testing.T) {
 	assert.Equal(t, question, changes["reason"], "reason should match question")
 }
 
-func TestBlockCommand_HistoryWithDifferentAgents(t *testing.T) {
+func TestBlockCommand_EventWithDifferentAgents(t *testing.T) {
 	app := testutil.NewTestApp(t)
 	SetupTasksCollection(t, app)
 	SetupCommentsCollection(t, app)
@@ -262,11 +227,10 @@ func TestBlockCommand_HistoryWithDifferentAgents(t *testing.T) {
 			simulateBlockTask(t, app, task, "Question?", agentToUse)
 
 			task, _ = app.FindRecordById("tasks", task.Id)
-			history := getHistoryFromTask(t, task)
-			require.Greater(t, len(history), 0, "history should not be empty")
-			lastEntry := history[len(history)-1]
+			events := GetTaskEvents(t, app, task.Id)
+			require.NotEmpty(t, events, "events should not be empty")
 
-			assert.Equal(t, agentToUse, lastEntry["actor_detail"])
+			assert.Equal(t, agentToUse, events[len(events)-1].ActorDetail)
 		})
 	}
 }
@@ -358,15 +322,15 @@ func TestFullWorkflow_CreateBlockCommentList(t *testing.T) {
 	assert.True(t, foundAgent, "should find the agent's blocking question comment")
 	assert.True(t, foundHuman, "should find the human's response comment")
 
-	// Verify history was updated correctly
-	history := getHistoryFromTask(t, task)
-	require.Greater(t, len(history), 0, "task should have history")
+	// Verify the event log was updated correctly
+	events := GetTaskEvents(t, app, task.Id)
+	require.NotEmpty(t, events, "task should have events")
 
-	lastHistoryEntry := history[len(history)-1]
-	assert.Equal(t, "blocked", lastHistoryEntry["action"], "last history action should be 'blocked'")
-	changes, ok := lastHistoryEntry["changes"].(map[string]any)
+	lastEvent := events[len(events)-1]
+	assert.Equal(t, "blocked", lastEvent.Action, "last event action should be 'blocked'")
+	changes, ok := lastEvent.Changes.(map[string]any)
 	require.True(t, ok, "changes should be a map")
-	assert.Equal(t, agentQuestion, changes["reason"], "history should record the blocking reason")
+	assert.Equal(t, agentQuestion, changes["reason"], "event should record the blocking reason")
 }
 
 // TestAtomicBlock_RollbackOnCommentFailure verifies that if comment creation fails,
@@ -386,7 +350,7 @@ func TestAtomicBlock_RollbackOnCommentFailure(t *testing.T) {
 	err := app.RunInTransaction(func(txApp core.App) error {
 		// Update task column to need_input
 		task.Set("column", "need_input")
-		addHistoryEntry(task, "blocked", "test-agent", map[string]any{
+		addTaskEvent(task, "blocked", "test-agent", map[string]any{
 			"column": map[string]any{
 				"from": originalColumn,
 				"to":   "need_input",
@@ -122,7 +122,6 @@ func TestGetTaskBlockedBy_Nil(t *testing.T) {
 	record.Set("position", 1000.0)
 	record.Set("labels", []string{})
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 	// Note: blocked_by is intentionally not set
 
 	require.NoError(t, app.Save(record))
@@ -281,7 +280,6 @@ func createTestTaskWithBlockedBy(t *testing.T, app *pocketbase.PocketBase, title
 	record.Set("labels", []string{})
 	record.Set("blocked_by", blockedBy)
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 
 	require.NoError(t, app.Save(record))
 
@@ -9,8 +9,12 @@ import (
 	"github.com/pocketbase/pocketbase"
 	"github.com/spf13/cobra"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
 	"github.com/ramtinJ95/EgenSkriven/internal/board"
 	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/estimate"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
 )
 
 // newBoardCmd creates the board command and its subcommands
@@ -130,6 +134,11 @@ The prefix is used in task IDs (e.g., WRK-123) and must be:
 				return fmt.Errorf("--prefix is required")
 			}
 
+			caller := resolveCaller(app, "")
+			if err := checkPolicy(app, caller, "", policy.ActionCreate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
 			b, err := board.Create(app, board.CreateInput{
 				Name:    name,
 				Prefix:  prefix,
@@ -183,18 +192,24 @@ func newBoardShowCmd(app *pocketbase.PocketBase) *cobra.Command {
 			// Count tasks in this board
 			tasks, _ := app.FindAllRecords("tasks",
 				dbx.NewExp("board = {:board}", dbx.Params{"board": record.Id}),
+				trash.Exclude(app, "tasks"),
 			)
 			taskCount := len(tasks)
+			estimateUnit := estimate.NormalizeUnit(record.GetString("estimate_unit"))
+			agentMode := policy.EffectiveMode(app, record.Id)
 
 			if out.JSON {
 				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-					"id":          b.ID,
-					"name":        b.Name,
-					"prefix":      b.Prefix,
-					"columns":     b.Columns,
-					"color":       b.Color,
-					"resume_mode": resumeMode,
-					"task_count":  taskCount,
+					"id":            b.ID,
+					"name":          b.Name,
+					"prefix":        b.Prefix,
+					"columns":       b.Columns,
+					"color":         b.Color,
+					"resume_mode":   resumeMode,
+					"estimate_unit": estimateUnit,
+					"agent_mode":    agentMode,
+					"auto_archive":  archive.PolicyDays(record),
+					"task_count":    taskCount,
 				})
 			}
 
@@ -205,6 +220,13 @@ func newBoardShowCmd(app *pocketbase.PocketBase) *cobra.Command {
 				fmt.Printf("Color: %s\n", b.Color)
 			}
 			fmt.Printf("Resume Mode: %s\n", resumeMode)
+			fmt.Printf("Estimate Unit: %s\n", estimateUnit)
+			if record.GetString("agent_mode") != "" {
+				fmt.Printf("Agent Mode: %s\n", agentMode)
+			} else {
+				fmt.Printf("Agent Mode: %s (from config)\n", agentMode)
+			}
+			fmt.Printf("Auto-archive: %s\n", describeAutoArchive(archive.PolicyDays(record)))
 			fmt.Printf("Tasks: %d\n", taskCount)
 
 			return nil
@@ -218,9 +240,12 @@ var ValidResumeModes = []string{"manual", "command", "auto"}
 // newBoardUpdateCmd creates the 'board update' subcommand
 func newBoardUpdateCmd(app *pocketbase.PocketBase) *cobra.Command {
 	var (
-		resumeMode string
-		color      string
-		name       string
+		resumeMode   string
+		color        string
+		name         string
+		estimateUnit string
+		agentMode    string
+		autoArchive  string
 	)
 
 	cmd := &cobra.Command{
@@ -230,12 +255,25 @@ func newBoardUpdateCmd(app *pocketbase.PocketBase) *cobra.Command {
 
 Available settings:
 - --resume-mode: How blocked tasks should be resumed (manual, command, auto)
+- --estimate-unit: Unit for task estimates (points, hours)
+- --agent-mode: What agents may do on this board (autonomous,
+  collaborative, supervised, or default to use agent.mode from config)
+- --auto-archive: Archive done tasks after this long (14d, 2w, or off)
 - --color: Accent color (hex format)
 - --name: Board display name`,
 		Args: cobra.ExactArgs(1),
 		Example: `  # Set resume mode to auto (triggers on @agent mention)
   egenskriven board update work --resume-mode auto
 
+  # Estimate tasks in hours instead of points
+  egenskriven board update work --estimate-unit hours
+
+  # Let agents work but not delete or complete tasks
+  egenskriven board update work --agent-mode collaborative
+
+  # Archive tasks two weeks after they are done
+  egenskriven board update work --auto-archive 14d
+
   # Change board color
   egenskriven board update work --color "#22C55E"
 
@@ -263,6 +301,37 @@ Available settings:
 				updated = true
 			}
 
+			// Update estimate unit if specified
+			if estimateUnit != "" {
+				if !estimate.IsValidUnit(estimateUnit) {
+					return fmt.Errorf("invalid estimate unit %q: must be one of %v", estimateUnit, estimate.ValidUnits)
+				}
+				record.Set("estimate_unit", estimateUnit)
+				updated = true
+			}
+
+			// Update agent mode if specified ("default" clears the override)
+			if agentMode != "" {
+				if agentMode == "default" {
+					record.Set("agent_mode", "")
+				} else if containsString(config.ValidModes, agentMode) {
+					record.Set("agent_mode", agentMode)
+				} else {
+					return fmt.Errorf("invalid agent mode %q: must be one of %v or default", agentMode, config.ValidModes)
+				}
+				updated = true
+			}
+
+			// Update auto-archive policy if specified
+			if autoArchive != "" {
+				days, err := parseAutoArchive(autoArchive)
+				if err != nil {
+					return err
+				}
+				record.Set("auto_archive_days", days)
+				updated = true
+			}
+
 			// Update color if specified
 			if color != "" {
 				record.Set("color", color)
@@ -276,7 +345,16 @@ Available settings:
 			}
 
 			if !updated {
-				return fmt.Errorf("no updates specified; use --resume-mode, --color, or --name")
+				return fmt.Errorf("no updates specified; use --resume-mode, --estimate-unit, --agent-mode, --auto-archive, --color, or --name")
+			}
+
+			// Agents may never change the agent mode of a board
+			action := policy.ActionUpdate
+			if agentMode != "" {
+				action = policy.ActionSetMode
+			}
+			if err := checkPolicy(app, resolveCaller(app, ""), record.Id, action).Err(); err != nil {
+				return policyDenied(out, err)
 			}
 
 			if err := app.Save(record); err != nil {
@@ -285,12 +363,15 @@ Available settings:
 
 			if out.JSON {
 				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-					"success":     true,
-					"id":          record.Id,
-					"name":        record.GetString("name"),
-					"prefix":      record.GetString("prefix"),
-					"resume_mode": record.GetString("resume_mode"),
-					"color":       record.GetString("color"),
+					"success":       true,
+					"id":            record.Id,
+					"name":          record.GetString("name"),
+					"prefix":        record.GetString("prefix"),
+					"resume_mode":   record.GetString("resume_mode"),
+					"estimate_unit": estimate.NormalizeUnit(record.GetString("estimate_unit")),
+					"agent_mode":    policy.EffectiveMode(app, record.Id),
+					"auto_archive":  archive.PolicyDays(record),
+					"color":         record.GetString("color"),
 				})
 			}
 
@@ -298,6 +379,15 @@ Available settings:
 			if resumeMode != "" {
 				fmt.Printf("  Resume Mode: %s\n", resumeMode)
 			}
+			if estimateUnit != "" {
+				fmt.Printf("  Estimate Unit: %s\n", estimateUnit)
+			}
+			if agentMode != "" {
+				fmt.Printf("  Agent Mode: %s\n", policy.EffectiveMode(app, record.Id))
+			}
+			if autoArchive != "" {
+				fmt.Printf("  Auto-archive: %s\n", describeAutoArchive(archive.PolicyDays(record)))
+			}
 			if color != "" {
 				fmt.Printf("  Color: %s\n", color)
 			}
@@ -309,6 +399,9 @@ Available settings:
 	}
 
 	cmd.Flags().StringVar(&resumeMode, "resume-mode", "", "Resume mode (manual, command, auto)")
+	cmd.Flags().StringVar(&estimateUnit, "estimate-unit", "", "Estimate unit (points, hours)")
+	cmd.Flags().StringVar(&agentMode, "agent-mode", "", "Agent mode (autonomous, collaborative, supervised, default)")
+	cmd.Flags().StringVar(&autoArchive, "auto-archive", "", "Archive done tasks after this long (14d, 2w, off)")
 	cmd.Flags().StringVarP(&color, "color", "c", "", "Accent color (hex, e.g., #3B82F6)")
 	cmd.Flags().StringVarP(&name, "name", "n", "", "Board display name")
 
@@ -372,9 +465,10 @@ func newBoardDeleteCmd(app *pocketbase.PocketBase) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "delete [name-or-prefix]",
 		Short: "Delete a board",
-		Long: `Delete a board and all its tasks.
+		Long: `Delete a board by moving it, with all its tasks and epics, to the trash.
 
-WARNING: This permanently deletes all tasks in the board!
+Restoring the board with 'egenskriven trash restore' brings its tasks and
+epics back; 'egenskriven trash purge' removes them for good.
 Use --force to skip the confirmation prompt.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -388,12 +482,18 @@ Use --force to skip the confirmation prompt.`,
 				return err
 			}
 
+			caller := resolveCaller(app, "")
+			if err := checkPolicy(app, caller, record.Id, policy.ActionDelete).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
 			boardName := record.GetString("name")
 			boardPrefix := record.GetString("prefix")
 
-			// Count tasks that will be deleted
+			// Count tasks that will be trashed
 			tasks, _ := app.FindAllRecords("tasks",
 				dbx.NewExp("board = {:board}", dbx.Params{"board": record.Id}),
+				trash.Exclude(app, "tasks"),
 			)
 			taskCount := len(tasks)
 
@@ -410,8 +510,9 @@ Use --force to skip the confirmation prompt.`,
 				}
 			}
 
-			// Delete board and tasks
-			if err := board.Delete(app, record.Id, true); err != nil {
+			// Move board and tasks to the trash
+			taskCount, err = trash.Board(app, record, "cli", caller.Name)
+			if err != nil {
 				return err
 			}
 
@@ -424,7 +525,7 @@ Use --force to skip the confirmation prompt.`,
 				})
 			}
 
-			fmt.Printf("Deleted board '%s' and %d task(s)\n", boardName, taskCount)
+			fmt.Printf("Moved board '%s' and %d task(s) to the trash\n", boardName, taskCount)
 			return nil
 		},
 	}
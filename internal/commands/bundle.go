@@ -0,0 +1,295 @@
+package commands
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
+)
+
+// Bundle layout: the JSON export and the attached files of each task.
+const (
+	bundleDataName       = "export.json"
+	bundleAttachmentsDir = "attachments"
+)
+
+// isBundleFormat reports whether an export format is a bundle of the JSON
+// export and the attached files.
+func isBundleFormat(format string) bool {
+	return format == "zip" || format == "tar"
+}
+
+// bundleFile is a file of a bundle.
+type bundleFile struct {
+	Name string
+	Data []byte
+}
+
+// exportBundle exports all data with the task attachments as a zip or
+// gzipped tar archive.
+func exportBundle(app *pocketbase.PocketBase, boardFilter string, mode archive.Mode, format string, writer io.Writer) (string, error) {
+	data, tasks, err := buildExportData(app, boardFilter, mode)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", newCommandError(ExitGeneralError, "failed to encode JSON: %v", err)
+	}
+	files := []bundleFile{{Name: bundleDataName, Data: content}}
+
+	for _, task := range tasks {
+		infos, err := attachment.ForRecord(app, task)
+		if err != nil {
+			return "", newCommandError(ExitGeneralError, "failed to list attachments: %v", err)
+		}
+		for _, info := range infos {
+			content, err := readAttachment(app, info)
+			if err != nil {
+				return "", newCommandError(ExitGeneralError, "%s", err.Error())
+			}
+			files = append(files, bundleFile{
+				Name: path.Join(bundleAttachmentsDir, task.Id, info.Name),
+				Data: content,
+			})
+		}
+	}
+
+	if format == "zip" {
+		err = writeZipBundle(writer, files)
+	} else {
+		err = writeTarBundle(writer, files)
+	}
+	if err != nil {
+		return "", newCommandError(ExitGeneralError, "failed to write %s bundle: %v", format, err)
+	}
+
+	return fmt.Sprintf("Exported %d boards, %d epics, %d tasks, %d attachments",
+		len(data.Boards), len(data.Epics), len(data.Tasks), len(files)-1), nil
+}
+
+// readAttachment returns the content of an attached file.
+func readAttachment(app *pocketbase.PocketBase, info attachment.Info) ([]byte, error) {
+	reader, err := attachment.Open(app, info)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", info.DisplayName, err)
+	}
+	return content, nil
+}
+
+// writeZipBundle writes files as a zip archive.
+func writeZipBundle(w io.Writer, files []bundleFile) error {
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.Name,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(file.Data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// writeTarBundle writes files as a gzipped tar archive.
+func writeTarBundle(w io.Writer, files []bundleFile) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:    file.Name,
+			Mode:    0644,
+			Size:    int64(len(file.Data)),
+			ModTime: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.Data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readImportFile reads a JSON export or a zip or tar bundle. For bundles it
+// also returns the attached files by task ID.
+func readImportFile(filename string) (*ExportData, map[string][]bundleFile, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	var files []bundleFile
+	switch {
+	case bytes.HasPrefix(content, []byte("PK\x03\x04")):
+		files, err = readZipBundle(content)
+	case bytes.HasPrefix(content, []byte{0x1f, 0x8b}):
+		files, err = readTarBundle(content)
+	default:
+		var data ExportData
+		if err := json.Unmarshal(content, &data); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		return &data, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	var data *ExportData
+	attachments := make(map[string][]bundleFile)
+	for _, file := range files {
+		if file.Name == bundleDataName {
+			data = &ExportData{}
+			if err := json.Unmarshal(file.Data, data); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse %s: %w", bundleDataName, err)
+			}
+			continue
+		}
+		// attachments/<task id>/<name>
+		parts := strings.Split(file.Name, "/")
+		if len(parts) != 3 || parts[0] != bundleAttachmentsDir || parts[1] == "" || parts[2] == "" {
+			continue
+		}
+		attachments[parts[1]] = append(attachments[parts[1]], bundleFile{Name: parts[2], Data: file.Data})
+	}
+	if data == nil {
+		return nil, nil, fmt.Errorf("bundle has no %s", bundleDataName)
+	}
+	return data, attachments, nil
+}
+
+// readZipBundle returns the files of a zip archive.
+func readZipBundle(content []byte) ([]bundleFile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+	var files []bundleFile
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bundleFile{Name: f.Name, Data: data})
+	}
+	return files, nil
+}
+
+// readTarBundle returns the files of a gzipped tar archive.
+func readTarBundle(content []byte) ([]bundleFile, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var files []bundleFile
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bundleFile{Name: header.Name, Data: data})
+	}
+}
+
+// importAttachments attaches the files of a bundle to the imported tasks.
+// Files whose name a task already has are skipped, so importing a bundle
+// twice does not duplicate them.
+func importAttachments(app *pocketbase.PocketBase, files map[string][]bundleFile, dryRun bool, stats *ImportStats) error {
+	for taskID, taskFiles := range files {
+		task, err := app.FindRecordById("tasks", taskID)
+		if err != nil {
+			if dryRun {
+				// The task would be created by the import
+				stats.AttachmentsCreated += len(taskFiles)
+			} else {
+				stats.AttachmentsSkipped += len(taskFiles)
+			}
+			continue
+		}
+		if !attachment.Supported(task) {
+			stats.AttachmentsSkipped += len(taskFiles)
+			continue
+		}
+
+		existing := make(map[string]bool)
+		for _, name := range attachment.Names(task) {
+			existing[attachment.DisplayName(name)] = true
+		}
+		var added []*filesystem.File
+		for _, f := range taskFiles {
+			displayName := attachment.DisplayName(f.Name)
+			if existing[displayName] || len(f.Data) == 0 {
+				stats.AttachmentsSkipped++
+				continue
+			}
+			existing[displayName] = true
+			stats.AttachmentsCreated++
+			if dryRun {
+				continue
+			}
+			file, err := filesystem.NewFileFromBytes(f.Data, displayName)
+			if err != nil {
+				return fmt.Errorf("failed to read attachment %s: %w", displayName, err)
+			}
+			added = append(added, file)
+		}
+		if len(added) == 0 {
+			continue
+		}
+		task.Set(attachment.Field+"+", added)
+		if err := app.Save(task); err != nil {
+			return fmt.Errorf("failed to attach files to task %s: %w", task.GetString("title"), err)
+		}
+	}
+	return nil
+}
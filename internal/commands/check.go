@@ -0,0 +1,288 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+)
+
+func newCheckCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Manage task checklists",
+		Long: `Add and tick off the checklist items of a task.
+
+A task's checklist is kept in sync with the markdown checkboxes of its
+description:
+
+  ## Checklist
+  - [x] Write the migration
+  - [ ] Update the docs
+
+Checking a box in an editor ticks the item, and 'check done' ticks the box.
+Items are referred to by number (see 'check list') or by a unique part of
+their text.`,
+	}
+
+	cmd.AddCommand(newCheckAddCmd(app))
+	cmd.AddCommand(newCheckDoneCmd(app, true))
+	cmd.AddCommand(newCheckDoneCmd(app, false))
+	cmd.AddCommand(newCheckListCmd(app))
+
+	return cmd
+}
+
+// ========== Check Add ==========
+
+func newCheckAddCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var agentName string
+
+	cmd := &cobra.Command{
+		Use:   "add <task> <item> [item...]",
+		Short: "Add checklist items to a task",
+		Example: `  egenskriven check add WRK-1 "Write the migration"
+  egenskriven check add WRK-1 "Add tests" "Update the docs"`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			task, err := resolveChecklistTask(app, out, args[0], agentName)
+			if err != nil {
+				return err
+			}
+
+			items := checklist.Get(task)
+			before := checklist.FormatProgress(items)
+			var added []string
+			for _, text := range args[1:] {
+				text = strings.TrimSpace(text)
+				if text == "" || strings.Contains(text, "\n") {
+					return out.Error(ExitValidation, "checklist items must be a single non-empty line", nil)
+				}
+				items = append(items, checklist.Item{Text: text})
+				added = append(added, text)
+			}
+
+			if err := saveChecklist(app, out, task, items, before, agentName, added); err != nil {
+				return err
+			}
+			return writeChecklist(app, out, task,
+				fmt.Sprintf("Added %d item(s) to %s", len(added), getTaskDisplayID(app, task)))
+		},
+	}
+
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent identifier (subject to the board's agent mode)")
+
+	return cmd
+}
+
+// ========== Check Done / Undo ==========
+
+// newCheckDoneCmd creates 'check done', or 'check undo' when done is false.
+func newCheckDoneCmd(app *pocketbase.PocketBase, done bool) *cobra.Command {
+	var agentName string
+
+	cmd := &cobra.Command{
+		Use:   "done <task> <item> [item...]",
+		Short: "Tick off checklist items",
+		Example: `  egenskriven check done WRK-1 1
+  egenskriven check done WRK-1 migration docs`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			task, err := resolveChecklistTask(app, out, args[0], agentName)
+			if err != nil {
+				return err
+			}
+			displayID := getTaskDisplayID(app, task)
+
+			items := checklist.Get(task)
+			if len(items) == 0 {
+				return out.ErrorWithSuggestion(ExitValidation,
+					fmt.Sprintf("%s has no checklist", displayID),
+					fmt.Sprintf("Add items with 'egenskriven check add %s <item>'", displayID), nil)
+			}
+			before := checklist.FormatProgress(items)
+
+			actor := resolveCaller(app, agentName).Name
+			now := time.Now().UTC().Format(time.RFC3339)
+			var changed []string
+			for _, ref := range args[1:] {
+				i, err := checklist.Find(items, ref)
+				if err != nil {
+					return out.Error(ExitNotFound, err.Error(), nil)
+				}
+				if items[i].Done == done {
+					state := "done"
+					if !done {
+						state = "not done"
+					}
+					warnLog("%s: %q is already %s", displayID, items[i].Text, state)
+					continue
+				}
+				items[i].Done = done
+				items[i].DoneBy, items[i].DoneAt = "", ""
+				if done {
+					items[i].DoneBy, items[i].DoneAt = actor, now
+				}
+				changed = append(changed, items[i].Text)
+			}
+
+			if len(changed) > 0 {
+				if err := saveChecklist(app, out, task, items, before, agentName, changed); err != nil {
+					return err
+				}
+			}
+
+			verb := "Checked"
+			if !done {
+				verb = "Unchecked"
+			}
+			return writeChecklist(app, out, task, fmt.Sprintf("%s %d item(s) on %s", verb, len(changed), displayID))
+		},
+	}
+
+	if !done {
+		cmd.Use = "undo <task> <item> [item...]"
+		cmd.Short = "Untick checklist items"
+		cmd.Example = `  egenskriven check undo WRK-1 2`
+	}
+
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent identifier (subject to the board's agent mode)")
+
+	return cmd
+}
+
+// ========== Check List ==========
+
+func newCheckListCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <task>",
+		Short: "List the checklist of a task",
+		Example: `  egenskriven check list WRK-1
+  egenskriven check list WRK-1 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			task, err := resolveTask(app, args[0])
+			if err != nil {
+				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+					return out.AmbiguousError(args[0], ambErr.Matches)
+				}
+				return out.Error(ExitNotFound, err.Error(), nil)
+			}
+
+			if !jsonOutput && len(checklist.Get(task)) == 0 {
+				fmt.Printf("No checklist on %s\n", getTaskDisplayID(app, task))
+				return nil
+			}
+			return writeChecklist(app, out, task, "")
+		},
+	}
+
+	return cmd
+}
+
+// resolveChecklistTask resolves a task whose checklist is about to change
+// and checks that the caller may update it.
+func resolveChecklistTask(app *pocketbase.PocketBase, out *output.Formatter, ref, agentName string) (*core.Record, error) {
+	task, err := resolveTask(app, ref)
+	if err != nil {
+		if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+			return nil, out.AmbiguousError(ref, ambErr.Matches)
+		}
+		return nil, out.Error(ExitNotFound, err.Error(), nil)
+	}
+	if !isRemoteMode() && !checklist.Supported(task) {
+		return nil, out.Error(ExitGeneralError, "tasks have no checklist field - run migrations first", nil)
+	}
+	if err := checkTaskPolicy(app, resolveCaller(app, agentName), task, policy.ActionUpdate).Err(); err != nil {
+		return nil, policyDenied(out, err)
+	}
+	return task, nil
+}
+
+// saveChecklist stores a changed checklist and records it in the task
+// event log with the progress before and after.
+func saveChecklist(app *pocketbase.PocketBase, out *output.Formatter, task *core.Record, items []checklist.Item, before, agentName string, texts []string) error {
+	checklist.Set(task, items)
+	addTaskEvent(task, "updated", agentName, map[string]any{
+		"checklist": map[string]any{
+			"from":  before,
+			"to":    checklist.FormatProgress(items),
+			"items": texts,
+		},
+	})
+	if err := updateRecordHybrid(app, task, out); err != nil {
+		return out.Error(ExitGeneralError, fmt.Sprintf("failed to update task: %v", err), nil)
+	}
+	return nil
+}
+
+// writeChecklist prints a task's checklist, after message when given.
+func writeChecklist(app *pocketbase.PocketBase, out *output.Formatter, task *core.Record, message string) error {
+	items := checklist.Get(task)
+	done, total := checklist.Progress(items)
+	displayID := getTaskDisplayID(app, task)
+
+	if jsonOutput {
+		out.WriteJSON(map[string]any{
+			"task_id":    task.Id,
+			"display_id": displayID,
+			"items":      items,
+			"done":       done,
+			"total":      total,
+		})
+		return nil
+	}
+
+	if message != "" {
+		out.Success(message)
+		if quietMode {
+			return nil
+		}
+	}
+	fmt.Printf("Checklist for %s: %s (%d/%d)\n", displayID, task.GetString("title"), done, total)
+	for i, item := range items {
+		box := "[ ]"
+		if item.Done {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("  %2d. %s %s", i+1, box, item.Text)
+		if item.Done && item.DoneBy != "" {
+			line += fmt.Sprintf("  (%s", item.DoneBy)
+			if t, err := time.Parse(time.RFC3339, item.DoneAt); err == nil {
+				line += ", " + formatRelativeTime(t)
+			}
+			line += ")"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
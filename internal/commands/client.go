@@ -5,10 +5,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/auth"
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
 	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+	"github.com/ramtinJ95/EgenSkriven/internal/view"
 )
 
 const (
@@ -23,15 +37,19 @@ const (
 )
 
 // APIClient handles HTTP requests to the PocketBase server.
-// It provides methods for CRUD operations on tasks that trigger real-time events.
+// It provides methods for CRUD operations on records that trigger real-time
+// events, and for the routes of RegisterRoutes used in remote mode.
 type APIClient struct {
 	baseURL       string
+	token         string       // API token for servers with auth enabled
 	healthClient  *http.Client // Quick timeout for health checks
 	requestClient *http.Client // Longer timeout for actual requests
+	authRecord    *core.Record // Account of the token, once fetched
 }
 
 // NewAPIClient creates a new API client with the configured or default server URL.
 // Uses merged config (global + project) to determine the server URL.
+// The API token is read from $EGENSKRIVEN_TOKEN or the global config.
 func NewAPIClient() *APIClient {
 	baseURL := DefaultServerURL
 
@@ -42,6 +60,7 @@ func NewAPIClient() *APIClient {
 
 	return &APIClient{
 		baseURL: baseURL,
+		token:   config.APIToken(),
 		healthClient: &http.Client{
 			Timeout: HealthCheckTimeout,
 		},
@@ -55,6 +74,7 @@ func NewAPIClient() *APIClient {
 func NewAPIClientWithURL(url string) *APIClient {
 	return &APIClient{
 		baseURL: url,
+		token:   config.APIToken(),
 		healthClient: &http.Client{
 			Timeout: HealthCheckTimeout,
 		},
@@ -95,23 +115,26 @@ func (e *APIError) IsValidationError() bool {
 
 // TaskData represents the data for creating or updating a task via API.
 type TaskData struct {
-	ID             string   `json:"id,omitempty"`
-	Title          string   `json:"title,omitempty"`
-	Description    string   `json:"description,omitempty"`
-	Type           string   `json:"type,omitempty"`
-	Priority       string   `json:"priority,omitempty"`
-	Column         string   `json:"column,omitempty"`
-	Position       float64  `json:"position,omitempty"`
-	Labels         []string `json:"labels,omitempty"`
-	BlockedBy      []string `json:"blocked_by,omitempty"`
-	CreatedBy      string   `json:"created_by,omitempty"`
-	CreatedByAgent string   `json:"created_by_agent,omitempty"`
-	Epic           string   `json:"epic,omitempty"`
-	Board          string   `json:"board,omitempty"`
-	Seq            int      `json:"seq,omitempty"`
-	Parent         string   `json:"parent,omitempty"`
-	DueDate        string   `json:"due_date,omitempty"`
-	History        []any    `json:"history,omitempty"`
+	ID             string            `json:"id,omitempty"`
+	Title          string            `json:"title,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	Type           string            `json:"type,omitempty"`
+	Priority       string            `json:"priority,omitempty"`
+	Column         string            `json:"column,omitempty"`
+	Position       float64           `json:"position,omitempty"`
+	Labels         []string          `json:"labels,omitempty"`
+	BlockedBy      []string          `json:"blocked_by,omitempty"`
+	CreatedBy      string            `json:"created_by,omitempty"`
+	CreatedByAgent string            `json:"created_by_agent,omitempty"`
+	Epic           string            `json:"epic,omitempty"`
+	Board          string            `json:"board,omitempty"`
+	Seq            int               `json:"seq,omitempty"`
+	Parent         string            `json:"parent,omitempty"`
+	DueDate        string            `json:"due_date,omitempty"`
+	Estimate       *float64          `json:"estimate,omitempty"`
+	Assignees      []string          `json:"assignees"`
+	Checklist      []checklist.Item  `json:"checklist,omitempty"`
+	Events         []taskevent.Event `json:"@events,omitempty"`
 }
 
 // TaskResponse represents a task returned from the API.
@@ -137,127 +160,696 @@ type TaskResponse struct {
 // CreateTask creates a task via the HTTP API.
 // Returns the created task or an error.
 func (c *APIClient) CreateTask(data TaskData) (*TaskResponse, error) {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal task data: %w", err)
+	var task TaskResponse
+	if err := c.do("POST", "/api/collections/tasks/records", data, &task); err != nil {
+		return nil, err
 	}
+	return &task, nil
+}
 
-	resp, err := c.requestClient.Post(
-		c.baseURL+"/api/collections/tasks/records",
-		"application/json",
-		bytes.NewBuffer(jsonData),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+// UpdateTask updates a task via the HTTP API.
+// Only non-zero fields in data will be updated.
+func (c *APIClient) UpdateTask(id string, data TaskData) (*TaskResponse, error) {
+	var task TaskResponse
+	if err := c.do("PATCH", "/api/collections/tasks/records/"+id, data, &task); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return &task, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+// DeleteTask deletes a task via the HTTP API.
+func (c *APIClient) DeleteTask(id string) error {
+	return c.DeleteRecord("tasks", id)
+}
+
+// GetTask fetches a single task by ID via the HTTP API.
+func (c *APIClient) GetTask(id string) (*TaskResponse, error) {
+	var task TaskResponse
+	if err := c.do("GET", "/api/collections/tasks/records/"+id, nil, &task); err != nil {
+		return nil, err
 	}
+	return &task, nil
+}
 
-	if resp.StatusCode >= 400 {
-		return nil, parseAPIError(resp.StatusCode, body)
+// recordsPerPage is the page size used to list records.
+const recordsPerPage = 500
+
+// ListRecords returns the records of a collection matching a PocketBase
+// filter, in sort order, fetching all pages. limit caps the number of
+// records (0 = no limit).
+func (c *APIClient) ListRecords(collection, filter, sort string, limit int) ([]*core.Record, error) {
+	var records []*core.Record
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("perPage", strconv.Itoa(recordsPerPage))
+		query.Set("skipTotal", "1")
+		if filter != "" {
+			query.Set("filter", filter)
+		}
+		if sort != "" {
+			query.Set("sort", sort)
+		}
+
+		var result struct {
+			Items []map[string]any `json:"items"`
+		}
+		path := "/api/collections/" + url.PathEscape(collection) + "/records?" + query.Encode()
+		if err := c.do("GET", path, nil, &result); err != nil {
+			return nil, err
+		}
+		for _, item := range result.Items {
+			records = append(records, remoteRecord(collection, item))
+			if limit > 0 && len(records) == limit {
+				return records, nil
+			}
+		}
+		if len(result.Items) < recordsPerPage {
+			return records, nil
+		}
 	}
+}
 
-	var task TaskResponse
-	if err := json.Unmarshal(body, &task); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// GetRecord fetches a single record by ID.
+func (c *APIClient) GetRecord(collection, id string) (*core.Record, error) {
+	var data map[string]any
+	if err := c.do("GET", recordPath(collection, id), nil, &data); err != nil {
+		return nil, err
 	}
+	return remoteRecord(collection, data), nil
+}
 
-	return &task, nil
+// CreateRecord creates a record from its field values and returns it as
+// stored by the server. Files are uploaded with the record.
+func (c *APIClient) CreateRecord(collection string, data map[string]any, files map[string][]*filesystem.File) (*core.Record, error) {
+	return c.saveRecord("POST", recordPath(collection, ""), collection, data, files)
 }
 
-// UpdateTask updates a task via the HTTP API.
-// Only non-zero fields in data will be updated.
-func (c *APIClient) UpdateTask(id string, data TaskData) (*TaskResponse, error) {
-	jsonData, err := json.Marshal(data)
+// UpdateRecord updates the given fields of a record and returns it as
+// stored by the server. Files are appended to the record's files.
+func (c *APIClient) UpdateRecord(collection, id string, data map[string]any, files map[string][]*filesystem.File) (*core.Record, error) {
+	appended := make(map[string][]*filesystem.File, len(files))
+	for field, f := range files {
+		appended[field+"+"] = f
+	}
+	return c.saveRecord("PATCH", recordPath(collection, id), collection, data, appended)
+}
+
+// DeleteRecord deletes a record. The server moves tasks, epics, and
+// boards to the trash instead.
+func (c *APIClient) DeleteRecord(collection, id string) error {
+	return c.do("DELETE", recordPath(collection, id), nil, nil)
+}
+
+// ResolveTask returns the ID of the task a reference names, resolved by
+// the server like references on the command line.
+func (c *APIClient) ResolveTask(ref string) (string, error) {
+	var result struct {
+		Task struct {
+			ID string `json:"id"`
+		} `json:"task"`
+	}
+	if err := c.do("GET", APIPrefix+"/resolve/"+url.PathEscape(ref), nil, &result); err != nil {
+		return "", err
+	}
+	return result.Task.ID, nil
+}
+
+// ListTasks returns the tasks matching the filters of the list command.
+func (c *APIClient) ListTasks(opts listOptions) ([]*core.Record, error) {
+	var result struct {
+		Tasks []map[string]any `json:"tasks"`
+	}
+	if err := c.do("POST", APIPrefix+"/list", opts, &result); err != nil {
+		return nil, err
+	}
+	tasks := make([]*core.Record, 0, len(result.Tasks))
+	for _, data := range result.Tasks {
+		tasks = append(tasks, remoteRecord("tasks", data))
+	}
+	return tasks, nil
+}
+
+// ShowTask returns a task with its sub-tasks and the related data the
+// show command prints.
+func (c *APIClient) ShowTask(ref string) (*core.Record, []*core.Record, output.TaskExtras, error) {
+	var result struct {
+		Task     map[string]any    `json:"task"`
+		Subtasks []map[string]any  `json:"subtasks"`
+		Extras   output.TaskExtras `json:"extras"`
+	}
+	if err := c.do("GET", APIPrefix+"/tasks/"+url.PathEscape(ref), nil, &result); err != nil {
+		return nil, nil, output.TaskExtras{}, err
+	}
+	subtasks := make([]*core.Record, 0, len(result.Subtasks))
+	for _, data := range result.Subtasks {
+		subtasks = append(subtasks, remoteRecord("tasks", data))
+	}
+	return remoteRecord("tasks", result.Task), subtasks, result.Extras, nil
+}
+
+// Suggest returns the suggestions of the suggest command.
+func (c *APIClient) Suggest(limit int, forName string) (*SuggestResponse, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	if forName != "" {
+		query.Set("for", forName)
+	}
+	var result SuggestResponse
+	if err := c.do("GET", APIPrefix+"/suggest?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Context returns the summary of the context command.
+func (c *APIClient) Context() (*ContextSummary, error) {
+	var result ContextSummary
+	if err := c.do("GET", APIPrefix+"/context", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ResumeTask returns the command resuming a blocked task's agent session,
+// as printed by 'resume --json'.
+func (c *APIClient) ResumeTask(ref string, req resumeRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/tasks/"+url.PathEscape(ref)+"/resume", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BlockTask blocks a task with a question, as printed by 'block --json'.
+func (c *APIClient) BlockTask(ref string, req blockRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/tasks/"+url.PathEscape(ref)+"/block", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LinkTasks links two tasks, as printed by 'link --json'.
+func (c *APIClient) LinkTasks(ref string, req linkRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/tasks/"+url.PathEscape(ref)+"/link", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UnlinkTasks removes the links between two tasks, as printed by
+// 'unlink --json'.
+func (c *APIClient) UnlinkTasks(ref string, req unlinkRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/tasks/"+url.PathEscape(ref)+"/unlink", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Log returns the events of the log command.
+func (c *APIClient) Log(q logQuery) ([]logEntry, error) {
+	query := url.Values{}
+	for key, value := range map[string]string{"task": q.Task, "board": q.Board, "actor": q.Actor} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if !q.Since.IsZero() {
+		query.Set("since", q.Since.UTC().Format(time.RFC3339))
+	}
+	if q.Limit > 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	var result struct {
+		Events []logEntry `json:"events"`
+	}
+	if err := c.do("GET", APIPrefix+"/log?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Events, nil
+}
+
+// TrackTime starts or stops the timer of a task, or logs time spent on
+// it, as printed by 'time <action> --json'.
+func (c *APIClient) TrackTime(ref, action string, req timeRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/tasks/"+url.PathEscape(ref)+"/time/"+action, req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TimeReport returns the rows of the time report.
+func (c *APIClient) TimeReport(groupBy string, since time.Time, boardRef string) ([]TimeReportRow, error) {
+	query := url.Values{}
+	query.Set("by", groupBy)
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	if boardRef != "" {
+		query.Set("board", boardRef)
+	}
+
+	var result struct {
+		Rows []TimeReportRow `json:"rows"`
+	}
+	if err := c.do("GET", APIPrefix+"/time/report?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	if result.Rows == nil {
+		result.Rows = []TimeReportRow{}
+	}
+	return result.Rows, nil
+}
+
+// Sprints returns the sprints of a board, as printed by
+// 'sprint list --json'.
+func (c *APIClient) Sprints(boardID string) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("GET", APIPrefix+"/sprints?board="+url.QueryEscape(boardID), nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ShowSprint returns a sprint of a board, as printed by
+// 'sprint show --json'.
+func (c *APIClient) ShowSprint(boardID, ref string) (map[string]any, error) {
+	var result map[string]any
+	path := APIPrefix + "/sprints/" + url.PathEscape(ref) + "?board=" + url.QueryEscape(boardID)
+	if err := c.do("GET", path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateSprint creates a sprint, as printed by 'sprint create --json'.
+func (c *APIClient) CreateSprint(req sprintRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/sprints", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SprintAction starts or closes a sprint, as printed by
+// 'sprint <action> --json'.
+func (c *APIClient) SprintAction(ref, action string, req sprintRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/sprints/"+url.PathEscape(ref)+"/"+action, req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetTaskSprint moves a task into a sprint, or out of its sprint with an
+// empty req.Sprint.
+func (c *APIClient) SetTaskSprint(ref string, req taskSprintRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/tasks/"+url.PathEscape(ref)+"/sprint", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Views returns the saved views of a board.
+func (c *APIClient) Views(boardID string) ([]view.View, error) {
+	var result struct {
+		Views []view.View `json:"views"`
+	}
+	if err := c.do("GET", APIPrefix+"/views?board="+url.QueryEscape(boardID), nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Views, nil
+}
+
+// ShowView returns a saved view of a board by name or ID.
+func (c *APIClient) ShowView(boardID, ref string) (view.View, error) {
+	var v view.View
+	path := APIPrefix + "/views/" + url.PathEscape(ref) + "?board=" + url.QueryEscape(boardID)
+	err := c.do("GET", path, nil, &v)
+	return v, err
+}
+
+// CreateView saves filters as a view.
+func (c *APIClient) CreateView(req viewRequest) (view.View, error) {
+	var v view.View
+	err := c.do("POST", APIPrefix+"/views", req, &v)
+	return v, err
+}
+
+// ViewAction updates, favorites, or deletes a view and returns the
+// updated view.
+func (c *APIClient) ViewAction(ref, action string, req viewRequest) (view.View, error) {
+	var v view.View
+	err := c.do("POST", APIPrefix+"/views/"+url.PathEscape(ref)+"/"+action, req, &v)
+	return v, err
+}
+
+// ArchiveTask archives a task, or unarchives it with action "unarchive",
+// as listed by 'archive --json'.
+func (c *APIClient) ArchiveTask(ref, action string, req archiveRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/tasks/"+url.PathEscape(ref)+"/"+action, req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RunArchive runs the auto-archive policies, as printed by
+// 'archive run --json'.
+func (c *APIClient) RunArchive(req archiveRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/archive/run", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Trash lists the trash, or the trash of a board, as printed by
+// 'trash list --json'.
+func (c *APIClient) Trash(boardRef string) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("GET", APIPrefix+"/trash?board="+url.QueryEscape(boardRef), nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RestoreTrash restores a trashed record, as listed by
+// 'trash restore --json'.
+func (c *APIClient) RestoreTrash(req trashRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/trash/restore", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PurgeTrash permanently deletes trashed records.
+func (c *APIClient) PurgeTrash(req trashRequest) (trash.PurgeResult, error) {
+	var result struct {
+		Purged trash.PurgeResult `json:"purged"`
+	}
+	err := c.do("POST", APIPrefix+"/trash/purge", req, &result)
+	return result.Purged, err
+}
+
+// Undo undoes, or with action "redo" redoes, the caller's last batches,
+// as printed by 'undo --json'.
+func (c *APIClient) Undo(action string, req undoRequest) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("POST", APIPrefix+"/"+action, req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FlowReport returns the flow report of a board, or of all boards for an
+// empty boardRef, as printed by 'report flow --json'.
+func (c *APIClient) FlowReport(boardRef string, since time.Time) (map[string]any, error) {
+	query := url.Values{}
+	query.Set("since", since.UTC().Format(time.RFC3339))
+	if boardRef != "" {
+		query.Set("board", boardRef)
+	}
+	var result map[string]any
+	if err := c.do("GET", APIPrefix+"/reports/flow?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Attachments returns the files attached to a task and its comments, as
+// printed by 'attachments list --json', with the task's title.
+func (c *APIClient) Attachments(ref string) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("GET", APIPrefix+"/tasks/"+url.PathEscape(ref)+"/attachments", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DownloadFile returns a reader for a file of a record, served by the
+// PocketBase files API. The caller closes it.
+func (c *APIClient) DownloadFile(collection, recordID, name string) (io.ReadCloser, error) {
+	resp, err := c.download("/api/files/" + url.PathEscape(collection) + "/" + url.PathEscape(recordID) + "/" + url.PathEscape(name))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Export returns a reader for an export as written by the export command,
+// and the summary line the command prints. The caller closes the reader.
+func (c *APIClient) Export(opts exportOptions) (io.ReadCloser, string, error) {
+	query := url.Values{}
+	query.Set("format", opts.format)
+	if opts.board != "" {
+		query.Set("board", opts.board)
+	}
+	switch opts.mode {
+	case archive.All:
+		query.Set("include_archived", "true")
+	case archive.Archived:
+		query.Set("archived", "true")
+	}
+	if opts.epic != "" {
+		query.Set("epic", opts.epic)
+	}
+	if opts.includeComments {
+		query.Set("include_comments", "true")
+	}
+	if !opts.since.IsZero() {
+		query.Set("since", opts.since.UTC().Format(time.RFC3339))
+	}
+	if opts.entry != "" {
+		query.Set("entry", opts.entry)
+	}
+
+	resp, err := c.download(APIPrefix + "/export?" + query.Encode())
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal task data: %w", err)
+		return nil, "", err
 	}
+	return resp.Body, resp.Header.Get(exportSummaryHeader), nil
+}
 
-	req, err := http.NewRequest(
-		"PATCH",
-		c.baseURL+"/api/collections/tasks/records/"+id,
-		bytes.NewBuffer(jsonData),
-	)
+// download sends a GET request for a file and returns the response, whose
+// body the caller closes.
+func (c *APIClient) download(path string) (*http.Response, error) {
+	req, err := c.newRequest("GET", path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
-
 	resp, err := c.requestClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
-	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, parseAPIError(resp.StatusCode, body)
+	}
+	return resp, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+// Proposals returns the proposals of a board, or of all boards for an
+// empty boardRef, as printed by 'proposals list --json'. An empty status
+// lists proposals in any state.
+func (c *APIClient) Proposals(boardRef, status string) (map[string]any, error) {
+	query := url.Values{}
+	query.Set("board", boardRef)
+	query.Set("status", status)
+	var result map[string]any
+	if err := c.do("GET", APIPrefix+"/proposals?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	if resp.StatusCode >= 400 {
-		return nil, parseAPIError(resp.StatusCode, body)
+// ShowProposal returns a proposal by ID or unique ID prefix, as printed
+// by 'proposals show --json'.
+func (c *APIClient) ShowProposal(ref string) (map[string]any, error) {
+	var result map[string]any
+	if err := c.do("GET", APIPrefix+"/proposals/"+url.PathEscape(ref), nil, &result); err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	var task TaskResponse
-	if err := json.Unmarshal(body, &task); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// ReviewProposal approves or rejects a proposal, as printed by
+// 'proposals approve --json'.
+func (c *APIClient) ReviewProposal(ref, action string, req proposalRequest) (map[string]any, error) {
+	var result map[string]any
+	path := APIPrefix + "/proposals/" + url.PathEscape(ref) + "/" + action
+	if err := c.do("POST", path, req, &result); err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	return &task, nil
+// AuthRecord returns the account of the API token, as the server sees it.
+// The account of a token issued to an agent names the agent.
+func (c *APIClient) AuthRecord() (*core.Record, error) {
+	if c.authRecord != nil {
+		return c.authRecord, nil
+	}
+	var result struct {
+		Record map[string]any `json:"record"`
+	}
+	if err := c.do("POST", "/api/collections/"+url.PathEscape(auth.UsersCollection)+"/auth-refresh", nil, &result); err != nil {
+		return nil, err
+	}
+	c.authRecord = remoteRecord(auth.UsersCollection, result.Record)
+	return c.authRecord, nil
 }
 
-// DeleteTask deletes a task via the HTTP API.
-func (c *APIClient) DeleteTask(id string) error {
-	req, err := http.NewRequest(
-		"DELETE",
-		c.baseURL+"/api/collections/tasks/records/"+id,
-		nil,
-	)
+// do sends a request with body encoded as JSON (none if nil) and decodes
+// the JSON response into result (ignored if nil).
+func (c *APIClient) do(method, path string, body any, result any) error {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	req, err := c.newRequest(method, path, reader)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
+	return c.send(req, result)
+}
 
+// send sends a request and decodes the JSON response into result.
+func (c *APIClient) send(req *http.Request, result any) error {
 	resp, err := c.requestClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
 	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
 		return parseAPIError(resp.StatusCode, body)
 	}
 
+	if result == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
 	return nil
 }
 
-// GetTask fetches a single task by ID via the HTTP API.
-func (c *APIClient) GetTask(id string) (*TaskResponse, error) {
-	resp, err := c.requestClient.Get(c.baseURL + "/api/collections/tasks/records/" + id)
+// saveRecord creates or updates a record, as multipart form data when
+// there are files to upload.
+func (c *APIClient) saveRecord(method, path, collection string, data map[string]any, files map[string][]*filesystem.File) (*core.Record, error) {
+	var saved map[string]any
+	if len(files) == 0 {
+		if err := c.do(method, path, data, &saved); err != nil {
+			return nil, err
+		}
+		return remoteRecord(collection, saved), nil
+	}
+
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	payload, err := json.Marshal(data)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if err := form.WriteField("@jsonPayload", string(payload)); err != nil {
+		return nil, err
+	}
+	for field, fieldFiles := range files {
+		for _, f := range fieldFiles {
+			if err := writeFormFile(form, field, f); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", f.OriginalName, err)
+			}
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	req, err := c.newRequest(method, path, &buf)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if err := c.send(req, &saved); err != nil {
+		return nil, err
+	}
+	return remoteRecord(collection, saved), nil
+}
 
-	if resp.StatusCode >= 400 {
-		return nil, parseAPIError(resp.StatusCode, body)
+// writeFormFile adds a file to a multipart form.
+func writeFormFile(form *multipart.Writer, field string, f *filesystem.File) error {
+	r, err := f.Reader.Open()
+	if err != nil {
+		return err
 	}
+	defer r.Close()
 
-	var task TaskResponse
-	if err := json.Unmarshal(body, &task); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	part, err := form.CreateFormFile(field, f.OriginalName)
+	if err != nil {
+		return err
 	}
+	_, err = io.Copy(part, r)
+	return err
+}
 
-	return &task, nil
+// recordPath returns the record API path of a collection, or of one of
+// its records.
+func recordPath(collection, id string) string {
+	path := "/api/collections/" + url.PathEscape(collection) + "/records"
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	return path
+}
+
+// remoteRecord turns the JSON of a record returned by the server into a
+// record. The fields are kept as decoded from JSON and the record is
+// marked as stored.
+func remoteRecord(collection string, data map[string]any) *core.Record {
+	record := core.NewRecord(core.NewBaseCollection(collection))
+	for _, key := range []string{"collectionId", "collectionName", "expand"} {
+		delete(data, key)
+	}
+	record.Load(data)
+	record.WithCustomData(true)
+	record.MarkAsNotNew()
+	return record
+}
+
+// newRequest builds an API request, attaching the JSON content type for
+// requests with a body and the API token when one is configured.
+func (c *APIClient) newRequest(method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	undo.SetHeaders(req)
+	return req, nil
 }
 
 // parseAPIError parses a PocketBase error response.
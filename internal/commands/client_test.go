@@ -1,11 +1,16 @@
 package commands
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
 )
 
 func TestNewAPIClient_DefaultURL(t *testing.T) {
@@ -255,3 +260,116 @@ func TestAPIClient_GetTask_Success(t *testing.T) {
 	assert.Equal(t, "test123", task.ID)
 	assert.Equal(t, "Test Task", task.Title)
 }
+
+func TestAPIClient_SendsTokenFromEnv(t *testing.T) {
+	t.Setenv(config.TokenEnvVar, "secret-token")
+
+	var authHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "test123"}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClientWithURL(server.URL)
+
+	_, err := client.CreateTask(TaskData{Title: "Test Task"})
+	assert.NoError(t, err)
+	_, err = client.GetTask("test123")
+	assert.NoError(t, err)
+	assert.NoError(t, client.DeleteTask("test123"))
+
+	assert.Equal(t, []string{"Bearer secret-token", "Bearer secret-token", "Bearer secret-token"}, authHeaders)
+}
+
+func TestAPIClient_NoTokenNoAuthHeader(t *testing.T) {
+	t.Setenv(config.TokenEnvVar, "")
+
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "test123"}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClientWithURL(server.URL)
+	client.token = ""
+
+	_, err := client.GetTask("test123")
+	assert.NoError(t, err)
+	assert.Empty(t, authHeader)
+}
+
+func TestAPIClient_ListRecords(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/collections/tasks/records", r.URL.Path)
+		query = r.URL.Query()
+		w.Write([]byte(`{"items": [
+			{"id": "a", "collectionName": "tasks", "title": "First", "seq": 1},
+			{"id": "b", "collectionName": "tasks", "title": "Second", "seq": 2}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClientWithURL(server.URL)
+	records, err := client.ListRecords("tasks", `column = "todo"`, "-seq", 0)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "a", records[0].Id)
+	assert.Equal(t, "First", records[0].GetString("title"))
+	assert.Equal(t, 2, records[1].GetInt("seq"))
+	assert.Equal(t, "tasks", records[0].Collection().Name)
+	assert.Nil(t, records[0].Get("collectionName"))
+	assert.Equal(t, `column = "todo"`, query.Get("filter"))
+	assert.Equal(t, "-seq", query.Get("sort"))
+
+	records, err = client.ListRecords("tasks", "", "", 1)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
+
+func TestAPIClient_CreateUpdateDeleteRecord(t *testing.T) {
+	var bodies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if r.Body != nil {
+			json.NewDecoder(r.Body).Decode(&body)
+		}
+		bodies = append(bodies, body)
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/collections/epics/records":
+			w.Write([]byte(`{"id": "epic123", "title": "Auth", "created": "2026-01-07 10:00:00.000Z"}`))
+		case r.Method == "PATCH" && r.URL.Path == "/api/collections/epics/records/epic123":
+			w.Write([]byte(`{"id": "epic123", "title": "Auth v2"}`))
+		case r.Method == "DELETE" && r.URL.Path == "/api/collections/epics/records/epic123":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status": 404, "message": "not found", "data": {}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewAPIClientWithURL(server.URL)
+
+	record, err := client.CreateRecord("epics", map[string]any{"title": "Auth"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "epic123", record.Id)
+	assert.Equal(t, "Auth", bodies[0]["title"])
+	assert.False(t, record.GetDateTime("created").IsZero())
+
+	record, err = client.UpdateRecord("epics", "epic123", map[string]any{"title": "Auth v2"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Auth v2", record.GetString("title"))
+
+	require.NoError(t, client.DeleteRecord("epics", "epic123"))
+
+	err = client.DeleteRecord("epics", "missing")
+	apiErr, ok := IsAPIError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
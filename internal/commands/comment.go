@@ -9,10 +9,12 @@ import (
 	"strings"
 
 	"github.com/pocketbase/pocketbase"
-	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/spf13/cobra"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
 	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
 	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
 )
 
@@ -20,6 +22,7 @@ func newCommentCmd(app *pocketbase.PocketBase) *cobra.Command {
 	var (
 		useStdin bool
 		author   string
+		attach   []string
 	)
 
 	cmd := &cobra.Command{
@@ -40,13 +43,16 @@ comment, it may trigger an auto-resume (depending on board configuration).`,
   cat response.txt | egenskriven comment WRK-123 --stdin
   
   # Specify author
-  egenskriven comment WRK-123 "Approved" --author "jane.doe"`,
+  egenskriven comment WRK-123 "Approved" --author "jane.doe"
+
+  # Attach files to the comment
+  egenskriven comment WRK-123 "Stack trace from CI" --attach build.log`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
@@ -71,7 +77,7 @@ comment, it may trigger an auto-resume (depending on board configuration).`,
 			}
 
 			// Resolve task
-			task, err := resolver.MustResolve(app, taskRef)
+			task, err := resolveTask(app, taskRef)
 			if err != nil {
 				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 					return out.AmbiguousError(taskRef, ambErr.Matches)
@@ -91,17 +97,23 @@ comment, it may trigger an auto-resume (depending on board configuration).`,
 				}
 			}
 
+			// Enforce the agent mode of the board
+			caller := resolveCaller(app, "")
+			if authorType == "agent" {
+				caller = policy.Agent(authorId)
+			}
+			if err := checkTaskPolicy(app, caller, task, policy.ActionComment).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
 			// Extract mentions from text
 			mentions := extractMentions(text)
 
-			// Get comments collection
-			commentsCollection, err := app.FindCollectionByNameOrId("comments")
+			// Create comment
+			comment, err := newRecord(app, "comments")
 			if err != nil {
-				return out.Error(ExitGeneralError, fmt.Sprintf("comments collection not found: %v", err), nil)
+				return out.Error(ExitGeneralError, err.Error(), nil)
 			}
-
-			// Create comment
-			comment := core.NewRecord(commentsCollection)
 			comment.Set("task", task.Id)
 			comment.Set("content", text)
 			comment.Set("author_type", authorType)
@@ -110,10 +122,34 @@ comment, it may trigger an auto-resume (depending on board configuration).`,
 				"mentions": mentions,
 			})
 
-			if err := app.Save(comment); err != nil {
+			var files []*filesystem.File
+			if len(attach) > 0 {
+				// The server checks the field in remote mode
+				if !isRemoteMode() && !attachment.Supported(comment) {
+					return out.Error(ExitGeneralError, "comments have no attachments field - run migrations first", nil)
+				}
+				if files, err = openAttachmentFiles(out, attach); err != nil {
+					return err
+				}
+				comment.Set(attachment.Field, files)
+			}
+
+			if err := saveRecord(app, comment); err != nil {
+				if len(files) > 0 {
+					return attachmentSaveError(out, err)
+				}
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to save comment: %v", err), nil)
 			}
 
+			var attachments []string
+			for _, file := range files {
+				attachments = append(attachments, file.Name)
+			}
+			if isRemoteMode() && len(files) > 0 {
+				// The server names the stored files
+				attachments = attachment.Names(comment)
+			}
+
 			// Get display ID for output
 			displayId := getTaskDisplayID(app, task)
 
@@ -128,6 +164,9 @@ comment, it may trigger an auto-resume (depending on board configuration).`,
 					"author_id":   authorId,
 					"mentions":    mentions,
 				}
+				if len(attachments) > 0 {
+					result["attachments"] = attachments
+				}
 				encoder := json.NewEncoder(os.Stdout)
 				encoder.SetIndent("", "  ")
 				if err := encoder.Encode(result); err != nil {
@@ -140,12 +179,20 @@ comment, it may trigger an auto-resume (depending on board configuration).`,
 			if !quietMode && len(mentions) > 0 {
 				fmt.Printf("Mentions: %s\n", strings.Join(mentions, ", "))
 			}
+			if !quietMode && len(attachments) > 0 {
+				names := make([]string, len(attachments))
+				for i, name := range attachments {
+					names[i] = attachment.DisplayName(name)
+				}
+				fmt.Printf("Attached: %s\n", strings.Join(names, ", "))
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&useStdin, "stdin", false, "Read comment from stdin")
 	cmd.Flags().StringVarP(&author, "author", "a", "", "Author identifier")
+	cmd.Flags().StringArrayVar(&attach, "attach", nil, "Attach a file to the comment (repeatable)")
 
 	return cmd
 }
@@ -156,15 +203,8 @@ func resolveAuthor(flagValue string) string {
 	if flagValue != "" {
 		return flagValue
 	}
-	// Try to get from global config
-	if cfg, err := config.LoadGlobalConfig(); err == nil && cfg.Defaults.Author != "" {
-		return cfg.Defaults.Author
-	}
-	// Fallback to system user
-	if user := os.Getenv("USER"); user != "" {
-		return user
-	}
-	return ""
+	// Global config defaults.author, falling back to the system user
+	return config.CurrentUser()
 }
 
 // isAgentContext checks if we're running in an AI agent context.
@@ -10,6 +10,7 @@ import (
 	"github.com/pocketbase/pocketbase"
 	"github.com/spf13/cobra"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
 	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
 )
 
@@ -42,14 +43,14 @@ or for reviewing the discussion history on a task.`,
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
 			taskRef := args[0]
 
 			// Resolve task
-			task, err := resolver.MustResolve(app, taskRef)
+			task, err := resolveTask(app, taskRef)
 			if err != nil {
 				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 					return out.AmbiguousError(taskRef, ambErr.Matches)
@@ -71,12 +72,12 @@ or for reviewing the discussion history on a task.`,
 			}
 
 			// Query comments
-			records, err := app.FindRecordsByFilter(
+			records, err := findRecordsByFilter(
+				app,
 				"comments",
 				filter,
 				"+created", // Sort ascending (oldest first)
 				limit,      // 0 means no limit
-				0,          // No offset
 			)
 			if err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to fetch comments: %v", err), nil)
@@ -97,6 +98,9 @@ or for reviewing the discussion history on a task.`,
 						"metadata":    r.Get("metadata"),
 						"created":     r.GetDateTime("created").Time().Format(time.RFC3339),
 					}
+					if names := attachment.Names(r); len(names) > 0 {
+						comments[i]["attachments"] = names
+					}
 				}
 				result := map[string]any{
 					"task_id":    task.Id,
@@ -145,6 +149,12 @@ or for reviewing the discussion history on a task.`,
 				for _, line := range lines {
 					fmt.Printf("  %s\n", line)
 				}
+				if names := attachment.Names(r); len(names) > 0 {
+					for i, name := range names {
+						names[i] = attachment.DisplayName(name)
+					}
+					fmt.Printf("  Attached: %s\n", strings.Join(names, ", "))
+				}
 				fmt.Println()
 			}
 
@@ -23,7 +23,7 @@ Configuration is loaded from two locations:
 
 Project settings override global settings for:
 - agent.workflow, agent.mode, agent.resume_mode
-- server.url
+- server.url, server.mode
 
 Global-only settings (cannot be overridden per-project):
 - data_dir (database location)
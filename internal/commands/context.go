@@ -2,10 +2,14 @@ package commands
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/estimate"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
 )
 
 // ContextSummary holds project state summary.
@@ -13,6 +17,8 @@ type ContextSummary struct {
 	Summary      Summary `json:"summary"`
 	BlockedCount int     `json:"blocked_count"`
 	ReadyCount   int     `json:"ready_count"`
+	// RemainingByColumn sums the estimates of unfinished tasks per column
+	RemainingByColumn map[string]float64 `json:"remaining_by_column,omitempty"`
 }
 
 // Summary holds task counts.
@@ -34,6 +40,7 @@ This provides agents with a quick overview of:
 - Tasks by column/status
 - Tasks by priority
 - Number of blocked vs ready tasks
+- Remaining estimated work per column
 
 Examples:
   egenskriven context
@@ -42,19 +49,28 @@ Examples:
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
-			// Get all tasks
-			tasks, err := app.FindAllRecords("tasks")
-			if err != nil {
-				return out.Error(ExitGeneralError, fmt.Sprintf("failed to list tasks: %v", err), nil)
+			var summary ContextSummary
+			if isRemoteMode() {
+				remote, err := remoteClient().Context()
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+				summary = *remote
+			} else {
+				// Get all tasks
+				tasks, err := app.FindAllRecords("tasks", trash.Exclude(app, "tasks"))
+				if err != nil {
+					return out.Error(ExitGeneralError, fmt.Sprintf("failed to list tasks: %v", err), nil)
+				}
+
+				// Build summary
+				summary = buildContextSummary(tasks)
 			}
 
-			// Build summary
-			summary := buildContextSummary(tasks)
-
 			if jsonOutput {
 				out.WriteJSON(summary)
 			} else {
@@ -103,9 +119,32 @@ func buildContextSummary(tasks []*core.Record) ContextSummary {
 		}
 	}
 
+	// Remaining estimated work per column
+	if remaining := remainingByColumn(tasks); len(remaining) > 0 {
+		summary.RemainingByColumn = remaining
+	}
+
 	return summary
 }
 
+// remainingByColumn sums the remaining estimate of unfinished work in each
+// column, counting each unit of work once.
+func remainingByColumn(tasks []*core.Record) map[string]float64 {
+	columnOf := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		columnOf[t.Id] = t.GetString("column")
+	}
+
+	result := make(map[string]float64)
+	for _, it := range estimate.Effective(estimate.Items(tasks)) {
+		if it.Done || it.Estimate <= 0 {
+			continue
+		}
+		result[columnOf[it.ID]] += it.Estimate
+	}
+	return result
+}
+
 func printContextSummary(s ContextSummary) {
 	fmt.Printf("Project Summary\n")
 	fmt.Printf("===============\n\n")
@@ -118,7 +157,12 @@ func printContextSummary(s ContextSummary) {
 	for _, col := range ValidColumns {
 		count := s.Summary.ByColumn[col]
 		if count > 0 {
-			fmt.Printf("  %-12s %d\n", col+":", count)
+			if remaining := s.RemainingByColumn[col]; remaining > 0 {
+				fmt.Printf("  %-12s %d (%s estimated remaining)\n", col+":", count,
+					strconv.FormatFloat(remaining, 'f', -1, 64))
+			} else {
+				fmt.Printf("  %-12s %d\n", col+":", count)
+			}
 		}
 	}
 
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -37,16 +38,7 @@ func parseDate(input string) (string, error) {
 	}
 
 	// Try common formats
-	formats := []string{
-		"Jan 2",           // "Jan 15"
-		"January 2",       // "January 15"
-		"Jan 2, 2006",     // "Jan 15, 2025"
-		"January 2, 2006", // "January 15, 2025"
-		"1/2/2006",        // "1/15/2025"
-		"1/2",             // "1/15"
-	}
-
-	for _, format := range formats {
+	for _, format := range dateFormats {
 		if t, err := time.Parse(format, input); err == nil {
 			// For formats without year, use current year
 			// If date is in past, use next year
@@ -63,3 +55,106 @@ func parseDate(input string) (string, error) {
 
 	return "", fmt.Errorf("could not parse date: %s", input)
 }
+
+// parsePastDate converts user input to the start of a day that is not in
+// the future, for recording work already done.
+// Supports:
+// - ISO 8601: "2025-01-15"
+// - Relative: "today", "yesterday"
+// - Shorthand: "jan 15", "january 15", resolved to the most recent one
+func parsePastDate(input string) (time.Time, error) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	if input == "" {
+		return time.Time{}, fmt.Errorf("empty date input")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	switch input {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", input, time.Local)
+	if err != nil {
+		for _, format := range dateFormats {
+			t, err := time.ParseInLocation(format, input, time.Local)
+			if err != nil {
+				continue
+			}
+			// For formats without year, use the current year unless
+			// that is still ahead
+			if t.Year() == 0 {
+				t = time.Date(now.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+				if t.After(today) {
+					t = t.AddDate(-1, 0, 0)
+				}
+			}
+			day = t
+			break
+		}
+	}
+	if day.IsZero() {
+		return time.Time{}, fmt.Errorf("could not parse date: %s (use e.g. 2025-01-15, yesterday or jan 15)", input)
+	}
+	if day.After(today) {
+		return time.Time{}, fmt.Errorf("date %s is in the future", day.Format("2006-01-02"))
+	}
+	return day, nil
+}
+
+// dateFormats are the shorthand date formats accepted besides ISO 8601.
+var dateFormats = []string{
+	"Jan 2",           // "Jan 15"
+	"January 2",       // "January 15"
+	"Jan 2, 2006",     // "Jan 15, 2025"
+	"January 2, 2006", // "January 15, 2025"
+	"1/2/2006",        // "1/15/2025"
+	"1/2",             // "1/15"
+}
+
+// parseSince converts a --since value to a point in time.
+// Supports:
+// - Relative durations: "30d", "2w", "12h", "90m"
+// - RFC3339 timestamps: "2026-01-07T10:00:00Z"
+// - Anything accepted by parseDate: "2025-01-15", "today", "jan 15"
+func parseSince(input string) (time.Time, error) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	if input == "" {
+		return time.Time{}, fmt.Errorf("empty --since value")
+	}
+
+	now := time.Now()
+
+	// Relative duration with day/week suffix
+	if n := len(input); n > 1 {
+		if count, err := strconv.Atoi(input[:n-1]); err == nil && count >= 0 {
+			switch input[n-1] {
+			case 'd':
+				return now.AddDate(0, 0, -count), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*count), nil
+			}
+		}
+	}
+
+	// Relative duration with hour/minute units
+	if d, err := time.ParseDuration(input); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(input)); err == nil {
+		return t, nil
+	}
+
+	// parseDate resolves "next week" style inputs into the future, which
+	// makes no sense for --since, but explicit dates work as expected.
+	date, err := parseDate(input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse --since value: %s (use e.g. 30d, 2w, 12h or 2025-01-15)", input)
+	}
+	return time.ParseInLocation("2006-01-02", date, time.Local)
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -117,3 +118,82 @@ func TestParseDate_WhitespaceHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePastDate(t *testing.T) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	yesterday := today.AddDate(0, 0, -1)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"today", today},
+		{"Yesterday", yesterday},
+		{"2025-01-15", time.Date(2025, 1, 15, 0, 0, 0, 0, time.Local)},
+		{"jan 15, 2025", time.Date(2025, 1, 15, 0, 0, 0, 0, time.Local)},
+		// Dates without a year resolve to the most recent one
+		{strings.ToLower(yesterday.Format("Jan 2")), yesterday},
+		{today.Format("January 2"), today},
+		{tomorrow.Format("1/2"), time.Date(tomorrow.Year()-1, tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parsePastDate(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected.Format("2006-01-02"), got.Format("2006-01-02"))
+		})
+	}
+}
+
+func TestParsePastDate_RejectsFutureDates(t *testing.T) {
+	tests := []string{
+		"",
+		"someday",
+		"tomorrow",
+		"next week",
+		"next month",
+		time.Now().AddDate(0, 0, 1).Format("2006-01-02"),
+		time.Now().AddDate(1, 0, 0).Format("Jan 2, 2006"),
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, err := parsePastDate(input)
+			assert.Error(t, err, "should reject %q", input)
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"30d", now.AddDate(0, 0, -30)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"12h", now.Add(-12 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"2025-01-15", time.Date(2025, 1, 15, 0, 0, 0, 0, time.Local)},
+		{"2026-01-07T10:00:00Z", time.Date(2026, 1, 7, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseSince(tt.input)
+			require.NoError(t, err)
+			assert.WithinDuration(t, tt.want, got, time.Minute)
+		})
+	}
+}
+
+func TestParseSince_Invalid(t *testing.T) {
+	for _, input := range []string{"", "yesterday-ish", "-5d", "d"} {
+		_, err := parseSince(input)
+		assert.Error(t, err, "input: %q", input)
+	}
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/dedupe"
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+)
+
+// dedupeOptions controls the duplicate check of `add`.
+type dedupeOptions struct {
+	Mode      string
+	Threshold float64
+}
+
+// validate checks the mode and threshold flags.
+func (o dedupeOptions) validate() error {
+	if !dedupe.ValidMode(o.Mode) {
+		return fmt.Errorf("invalid dedupe mode '%s', must be one of: %s", o.Mode, strings.Join(dedupe.Modes, ", "))
+	}
+	if o.Threshold <= 0 || o.Threshold > 1 {
+		return fmt.Errorf("invalid dedupe threshold '%g', must be between 0 and 1", o.Threshold)
+	}
+	return nil
+}
+
+// index returns the open tasks of a board to check new tasks against, or
+// nil when the check is off.
+func (o dedupeOptions) index(app *pocketbase.PocketBase, boardID string) (*dedupe.Index, error) {
+	if o.Mode == dedupe.ModeOff {
+		return nil, nil
+	}
+	if isRemoteMode() {
+		candidates, err := findActiveRecords(app, "tasks",
+			`board = {:board} && column != {:done} && archived_at = ""`, "",
+			dbx.Params{"board": boardID, "done": flow.ColumnDone})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tasks for the duplicate check: %w", err)
+		}
+		return dedupe.NewIndexFunc(func(task *core.Record) string {
+			return getTaskDisplayID(app, task)
+		}, candidates), nil
+	}
+	candidates, err := dedupe.Candidates(app, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks for the duplicate check: %w", err)
+	}
+	return dedupe.NewIndex(app, candidates), nil
+}
+
+// describeDuplicates lists likely duplicates as `WRK-5 "Title" (83%)`.
+func describeDuplicates(matches []dedupe.Match) string {
+	parts := make([]string, len(matches))
+	for i, m := range matches {
+		parts[i] = fmt.Sprintf("%s %q (%s)", m.DisplayID, m.Title, m.Percent())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// warnDuplicates reports the likely duplicates of a created task on stderr.
+func warnDuplicates(displayID string, matches []dedupe.Match) {
+	warnLog("%s may duplicate %s", displayID, describeDuplicates(matches))
+	warnLog("if so, link it: egenskriven link %s duplicates %s", displayID, matches[0].DisplayID)
+}
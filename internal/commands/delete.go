@@ -10,19 +10,25 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/spf13/cobra"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
 	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
 )
 
 func newDeleteCmd(app *pocketbase.PocketBase) *cobra.Command {
 	var (
-		force bool
-		stdin bool
+		force     bool
+		stdin     bool
+		agentName string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "delete <task> [task...]",
 		Short: "Delete tasks",
-		Long: `Delete one or more tasks.
+		Long: `Delete one or more tasks by moving them to the trash.
+
+Trashed tasks keep their comments and relations and can be brought back
+with 'egenskriven trash restore'; 'egenskriven trash purge' removes them
+for good.
 
 By default, asks for confirmation before deleting.
 Use --force to skip confirmation (useful for scripts).
@@ -38,7 +44,7 @@ Examples:
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
@@ -69,6 +75,8 @@ Examples:
 				return out.Error(ExitInvalidArguments, "no tasks to delete", nil)
 			}
 
+			caller := resolveCaller(app, agentName)
+
 			// Resolve all tasks first
 			type taskInfo struct {
 				ref  string
@@ -77,13 +85,17 @@ Examples:
 			var tasksToDelete []taskInfo
 
 			for _, ref := range refs {
-				task, err := resolver.MustResolve(app, ref)
+				task, err := resolveTask(app, ref)
 				if err != nil {
 					if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 						return out.AmbiguousError(ref, ambErr.Matches)
 					}
 					return out.Error(ExitNotFound, err.Error(), nil)
 				}
+				// Enforce the agent mode before anything is deleted
+				if err := checkTaskPolicy(app, caller, task, policy.ActionDelete).Err(); err != nil {
+					return policyDenied(out, err)
+				}
 				tasksToDelete = append(tasksToDelete, taskInfo{ref, task})
 			}
 
@@ -109,19 +121,19 @@ Examples:
 			var deleted int
 			for _, t := range tasksToDelete {
 				// Need to get the actual record for deletion
-				record, err := app.FindRecordById("tasks", t.task.Id)
+				record, err := findRecordByID(app, "tasks", t.task.Id)
 				if err != nil {
 					continue
 				}
 
-				if err := deleteRecordHybrid(app, record, out); err != nil {
+				if err := deleteRecordHybrid(app, record, caller.Name, out); err != nil {
 					return out.Error(ExitGeneralError,
 						fmt.Sprintf("failed to delete task %s: %v", t.ref, err), nil)
 				}
 				deleted++
 			}
 
-			out.Success(fmt.Sprintf("Deleted %d task(s)", deleted))
+			out.Success(fmt.Sprintf("Moved %d task(s) to the trash", deleted))
 
 			return nil
 		},
@@ -130,6 +142,7 @@ Examples:
 	// Define flags
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation")
 	cmd.Flags().BoolVar(&stdin, "stdin", false, "Read task references from stdin (one per line)")
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent identifier (subject to the board's agent mode)")
 
 	return cmd
 }
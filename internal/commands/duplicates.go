@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/dedupe"
+)
+
+func newDuplicatesCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef  string
+		threshold float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "duplicates",
+		Short: "Find open tasks that are likely duplicates",
+		Long: `Find pairs of open tasks on a board whose titles and descriptions are
+similar enough to be likely duplicates. Done, archived and trashed tasks are
+not compared, nor a parent with its subtasks or subtasks created together
+from one template.
+
+Without --board, the default board is checked, or every board when there is
+no default. Tasks are only compared with tasks on the same board.
+
+Mark a duplicate with 'egenskriven link <duplicate> duplicates <task>'.`,
+		Example: `  egenskriven duplicates --board WRK
+  egenskriven duplicates --board WRK --threshold 0.8
+  egenskriven duplicates --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if threshold <= 0 || threshold > 1 {
+				return out.Error(ExitValidation,
+					fmt.Sprintf("invalid threshold '%g', must be between 0 and 1", threshold), nil)
+			}
+
+			// Bootstrap the app
+			if err := app.Bootstrap(); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			// Board filter, falling back to the configured default board
+			if boardRef == "" {
+				cfg, _ := config.LoadProjectConfig()
+				if cfg != nil && cfg.DefaultBoard != "" {
+					boardRef = cfg.DefaultBoard
+				}
+			}
+			var boards []*core.Record
+			if boardRef != "" {
+				boardRecord, err := board.GetByNameOrPrefix(app, boardRef)
+				if err != nil {
+					return out.Error(ExitNotFound, fmt.Sprintf("board not found: %s", boardRef), nil)
+				}
+				boards = []*core.Record{boardRecord}
+			} else {
+				all, err := board.GetAll(app)
+				if err != nil {
+					return out.Error(ExitGeneralError, fmt.Sprintf("failed to list boards: %v", err), nil)
+				}
+				boards = all
+			}
+
+			type boardPairs struct {
+				Board *core.Record
+				Pairs []dedupe.Pair
+			}
+			var results []boardPairs
+			total := 0
+			for _, b := range boards {
+				candidates, err := dedupe.Candidates(app, b.Id)
+				if err != nil {
+					return out.Error(ExitGeneralError, fmt.Sprintf("failed to load tasks: %v", err), nil)
+				}
+				pairs := dedupe.NewIndex(app, candidates).Pairs(threshold)
+				results = append(results, boardPairs{Board: b, Pairs: pairs})
+				total += len(pairs)
+			}
+
+			if jsonOutput {
+				pairs := make([]map[string]any, 0, total)
+				for _, r := range results {
+					for _, p := range r.Pairs {
+						pairs = append(pairs, map[string]any{
+							"board": r.Board.GetString("prefix"),
+							"a":     p.A,
+							"b":     p.B,
+							"score": p.Score,
+						})
+					}
+				}
+				out.WriteJSON(map[string]any{
+					"pairs":     pairs,
+					"count":     total,
+					"threshold": threshold,
+				})
+				return nil
+			}
+
+			if total == 0 {
+				out.Success("No likely duplicates found")
+				return nil
+			}
+
+			for _, r := range results {
+				if len(r.Pairs) == 0 {
+					continue
+				}
+				fmt.Printf("\n%s (%d likely duplicate pairs):\n", r.Board.GetString("name"), len(r.Pairs))
+				for _, p := range r.Pairs {
+					fmt.Printf("  %4s  %-8s %s\n", dedupe.Percent(p.Score), p.A.DisplayID, p.A.Title)
+					fmt.Printf("        %-8s %s\n", p.B.DisplayID, p.B.Title)
+				}
+			}
+			if !quietMode {
+				fmt.Println("\nMark a duplicate with: egenskriven link <duplicate> duplicates <task>")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board to check (name or prefix)")
+	cmd.Flags().Float64Var(&threshold, "threshold", dedupe.DefaultThreshold,
+		"Similarity from which tasks count as likely duplicates (0-1)")
+
+	return cmd
+}
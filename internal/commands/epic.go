@@ -11,8 +11,10 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/spf13/cobra"
 
-	"github.com/ramtinJ95/EgenSkriven/internal/board"
 	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/estimate"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
 )
 
 func newEpicCmd(app *pocketbase.PocketBase) *cobra.Command {
@@ -50,7 +52,7 @@ func newEpicListCmd(app *pocketbase.PocketBase) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := getFormatter()
 
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
@@ -61,9 +63,8 @@ func newEpicListCmd(app *pocketbase.PocketBase) *cobra.Command {
 			}
 
 			// Find epics for this board
-			records, err := app.FindAllRecords("epics",
-				dbx.NewExp("board = {:boardId}", dbx.Params{"boardId": boardRecord.Id}),
-			)
+			records, err := findActiveRecords(app, "epics", "board = {:boardId}", "",
+				dbx.Params{"boardId": boardRecord.Id})
 			if err != nil {
 				return out.ErrorWithSuggestion(ExitGeneralError,
 					fmt.Sprintf("failed to list epics: %v", err),
@@ -72,6 +73,7 @@ func newEpicListCmd(app *pocketbase.PocketBase) *cobra.Command {
 
 			boardName := boardRecord.GetString("name")
 			boardPrefix := boardRecord.GetString("prefix")
+			unit := estimate.NormalizeUnit(boardRecord.GetString("estimate_unit"))
 
 			// Output
 			if out.JSON {
@@ -85,6 +87,7 @@ func newEpicListCmd(app *pocketbase.PocketBase) *cobra.Command {
 						"color":       record.GetString("color"),
 						"board":       record.GetString("board"),
 						"task_count":  taskCount,
+						"estimate":    getEpicEstimate(app, record.Id),
 						"created":     record.GetDateTime("created").String(),
 						"updated":     record.GetDateTime("updated").String(),
 					})
@@ -114,8 +117,12 @@ func newEpicListCmd(app *pocketbase.PocketBase) *cobra.Command {
 				if color := record.GetString("color"); color != "" {
 					colorIndicator = fmt.Sprintf(" %s", color)
 				}
-				fmt.Printf("  [%s] %s%s (%d tasks)\n",
-					shortID(record.Id), record.GetString("title"), colorIndicator, taskCount)
+				estimateText := ""
+				if totals := getEpicEstimate(app, record.Id); totals.Estimated > 0 {
+					estimateText = ", " + estimate.FormatProgress(totals, unit)
+				}
+				fmt.Printf("  [%s] %s%s (%d tasks%s)\n",
+					shortID(record.Id), record.GetString("title"), colorIndicator, taskCount, estimateText)
 			}
 			fmt.Printf("\nTotal: %d epics\n", len(records))
 
@@ -152,7 +159,7 @@ Color must be a valid hex code (e.g., #3B82F6).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := getFormatter()
 
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
@@ -170,16 +177,17 @@ Color must be a valid hex code (e.g., #3B82F6).`,
 				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
 			}
 
-			// Find epics collection
-			collection, err := app.FindCollectionByNameOrId("epics")
+			if err := checkPolicy(app, resolveCaller(app, ""), boardRecord.Id, policy.ActionCreate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
+			// Create record
+			record, err := newRecord(app, "epics")
 			if err != nil {
 				return out.ErrorWithSuggestion(ExitGeneralError,
 					"epics collection not found",
 					"Run 'egenskriven serve' first to initialize the database", nil)
 			}
-
-			// Create record
-			record := core.NewRecord(collection)
 			record.Set("title", title)
 			record.Set("board", boardRecord.Id)
 			if description != "" {
@@ -189,7 +197,7 @@ Color must be a valid hex code (e.g., #3B82F6).`,
 				record.Set("color", color)
 			}
 
-			if err := app.Save(record); err != nil {
+			if err := saveRecord(app, record); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to create epic: %v", err), nil)
 			}
 
@@ -244,7 +252,7 @@ You can reference an epic by:
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := getFormatter()
 
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
@@ -260,32 +268,40 @@ You can reference an epic by:
 			// Get board info
 			boardID := record.GetString("board")
 			var boardName, boardPrefix string
+			unit := estimate.UnitPoints
 			if boardID != "" {
-				boardRecord, err := app.FindRecordById("boards", boardID)
+				boardRecord, err := findRecordByID(app, "boards", boardID)
 				if err == nil {
 					boardName = boardRecord.GetString("name")
 					boardPrefix = boardRecord.GetString("prefix")
+					unit = estimate.NormalizeUnit(boardRecord.GetString("estimate_unit"))
 				}
 			}
 
 			// Get linked tasks
-			linkedTasks, err := app.FindAllRecords("tasks",
-				dbx.NewExp("epic = {:epicId}", dbx.Params{"epicId": record.Id}),
-			)
+			linkedTasks, err := findActiveRecords(app, "tasks", "epic = {:epicId}", "",
+				dbx.Params{"epicId": record.Id})
 			if err != nil {
 				linkedTasks = []*core.Record{}
 			}
 
+			// Roll up estimates from linked tasks and their sub-tasks
+			totals := tasksEstimate(app, linkedTasks)
+
 			// Output
 			if out.JSON {
 				tasks := make([]map[string]any, 0, len(linkedTasks))
 				for _, task := range linkedTasks {
-					tasks = append(tasks, map[string]any{
+					taskMap := map[string]any{
 						"id":       task.Id,
 						"title":    task.GetString("title"),
 						"column":   task.GetString("column"),
 						"priority": task.GetString("priority"),
-					})
+					}
+					if est := task.GetFloat("estimate"); est > 0 {
+						taskMap["estimate"] = est
+					}
+					tasks = append(tasks, taskMap)
 				}
 
 				return json.NewEncoder(os.Stdout).Encode(map[string]any{
@@ -301,7 +317,9 @@ You can reference an epic by:
 						"created":      record.GetDateTime("created").String(),
 						"updated":      record.GetDateTime("updated").String(),
 					},
-					"tasks": tasks,
+					"estimate":      totals,
+					"estimate_unit": unit,
+					"tasks":         tasks,
 				})
 			}
 
@@ -319,17 +337,29 @@ You can reference an epic by:
 			}
 			fmt.Printf("Created:     %s\n", record.GetDateTime("created").String())
 			fmt.Printf("Updated:     %s\n", record.GetDateTime("updated").String())
+			if totals.Estimated > 0 {
+				fmt.Printf("Estimate:    %s", estimate.FormatProgress(totals, unit))
+				if totals.Unestimated > 0 {
+					fmt.Printf(" (%d unestimated)", totals.Unestimated)
+				}
+				fmt.Println()
+			}
 			fmt.Printf("\nLinked Tasks (%d):\n", len(linkedTasks))
 
 			if len(linkedTasks) == 0 {
 				fmt.Println("  (no tasks)")
 			} else {
 				for _, task := range linkedTasks {
-					fmt.Printf("  [%s] %s (%s, %s)\n",
+					estimateText := ""
+					if est := task.GetFloat("estimate"); est > 0 {
+						estimateText = ", " + estimate.Format(est, unit)
+					}
+					fmt.Printf("  [%s] %s (%s, %s%s)\n",
 						shortID(task.Id),
 						task.GetString("title"),
 						task.GetString("column"),
 						task.GetString("priority"),
+						estimateText,
 					)
 				}
 			}
@@ -349,9 +379,10 @@ func newEpicDeleteCmd(app *pocketbase.PocketBase) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "delete <epic>",
 		Short: "Delete an epic",
-		Long: `Delete an epic.
+		Long: `Delete an epic by moving it to the trash.
 
-Tasks linked to the epic will remain but will no longer be associated with the epic.
+Tasks linked to the epic remain and keep the link, so restoring the epic
+with 'egenskriven trash restore' reattaches them.
 Use --force to skip the confirmation prompt.`,
 		Example: `  egenskriven epic delete abc123
   egenskriven epic delete "Q1 Launch" --force`,
@@ -359,7 +390,7 @@ Use --force to skip the confirmation prompt.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := getFormatter()
 
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
@@ -372,6 +403,11 @@ Use --force to skip the confirmation prompt.`,
 					"Use 'egenskriven epic list' to see available epics", nil)
 			}
 
+			caller := resolveCaller(app, "")
+			if err := checkPolicy(app, caller, record.GetString("board"), policy.ActionDelete).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
 			// Count linked tasks
 			taskCount := getEpicTaskCount(app, record.Id)
 
@@ -379,7 +415,7 @@ Use --force to skip the confirmation prompt.`,
 			if !force && !out.Quiet && !out.JSON {
 				fmt.Printf("Delete epic '%s'?", record.GetString("title"))
 				if taskCount > 0 {
-					fmt.Printf(" (%d tasks will be unlinked)", taskCount)
+					fmt.Printf(" (%d linked tasks remain)", taskCount)
 				}
 				fmt.Print("\nType 'yes' to confirm: ")
 
@@ -391,24 +427,26 @@ Use --force to skip the confirmation prompt.`,
 				}
 			}
 
-			// Delete the epic
-			if err := app.Delete(record); err != nil {
+			// Move the epic to the trash (the server does on delete)
+			if isRemoteMode() {
+				err = remoteClient().DeleteRecord("epics", record.Id)
+			} else {
+				err = trash.Epic(app, record, caller.Name)
+			}
+			if err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to delete epic: %v", err), nil)
 			}
 
 			// Output
 			if out.JSON {
 				return json.NewEncoder(os.Stdout).Encode(map[string]any{
-					"deleted":        record.Id,
-					"title":          record.GetString("title"),
-					"tasks_unlinked": taskCount,
+					"deleted":      record.Id,
+					"title":        record.GetString("title"),
+					"linked_tasks": taskCount,
 				})
 			}
 
-			fmt.Printf("Deleted epic: %s [%s]\n", record.GetString("title"), shortID(record.Id))
-			if taskCount > 0 {
-				fmt.Printf("  %d tasks unlinked\n", taskCount)
-			}
+			fmt.Printf("Moved epic to the trash: %s [%s]\n", record.GetString("title"), shortID(record.Id))
 
 			return nil
 		},
@@ -425,13 +463,13 @@ Use --force to skip the confirmation prompt.`,
 func resolveBoardForEpic(app *pocketbase.PocketBase, boardRef string) (*core.Record, error) {
 	// If explicit board reference provided, use it
 	if boardRef != "" {
-		return board.GetByNameOrPrefix(app, boardRef)
+		return findBoard(app, boardRef)
 	}
 
 	// Check config for default board
 	cfg, _ := config.LoadProjectConfig()
 	if cfg != nil && cfg.DefaultBoard != "" {
-		record, err := board.GetByNameOrPrefix(app, cfg.DefaultBoard)
+		record, err := findBoard(app, cfg.DefaultBoard)
 		if err == nil {
 			return record, nil
 		}
@@ -439,7 +477,7 @@ func resolveBoardForEpic(app *pocketbase.PocketBase, boardRef string) (*core.Rec
 	}
 
 	// Get existing boards
-	boards, err := board.GetAll(app)
+	boards, err := allBoards(app)
 	if err != nil || len(boards) == 0 {
 		return nil, fmt.Errorf("no boards exist - create one with 'egenskriven board add'")
 	}
@@ -451,15 +489,14 @@ func resolveBoardForEpic(app *pocketbase.PocketBase, boardRef string) (*core.Rec
 // resolveEpic finds an epic by ID, ID prefix, or title
 func resolveEpic(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
 	// Try exact ID match
-	record, err := app.FindRecordById("epics", ref)
-	if err == nil {
+	record, err := findRecordByID(app, "epics", ref)
+	if err == nil && !trash.IsTrashed(record) {
 		return record, nil
 	}
 
 	// Try ID prefix match
-	records, err := app.FindAllRecords("epics",
-		dbx.NewExp("id LIKE {:prefix}", dbx.Params{"prefix": ref + "%"}),
-	)
+	records, err := findActiveRecords(app, "epics", "id ~ {:prefix}", "",
+		dbx.Params{"prefix": ref + "%"})
 	if err == nil {
 		switch len(records) {
 		case 1:
@@ -478,10 +515,8 @@ func resolveEpic(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
 	}
 
 	// Try title match (case-insensitive)
-	records, err = app.FindAllRecords("epics",
-		dbx.NewExp("LOWER(title) LIKE {:title}",
-			dbx.Params{"title": "%" + strings.ToLower(ref) + "%"}),
-	)
+	records, err = findActiveRecords(app, "epics", "title ~ {:title}", "",
+		dbx.Params{"title": "%" + ref + "%"})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search epics: %w", err)
 	}
@@ -504,15 +539,41 @@ func resolveEpic(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
 
 // getEpicTaskCount returns the number of tasks linked to an epic
 func getEpicTaskCount(app *pocketbase.PocketBase, epicID string) int {
-	tasks, err := app.FindAllRecords("tasks",
-		dbx.NewExp("epic = {:epicId}", dbx.Params{"epicId": epicID}),
-	)
+	tasks, err := findActiveRecords(app, "tasks", "epic = {:epicId}", "",
+		dbx.Params{"epicId": epicID})
 	if err != nil {
 		return 0
 	}
 	return len(tasks)
 }
 
+// getEpicEstimate returns the rolled-up estimate of tasks linked to an epic.
+func getEpicEstimate(app *pocketbase.PocketBase, epicID string) estimate.Totals {
+	tasks, err := findActiveRecords(app, "tasks", "epic = {:epicId}", "",
+		dbx.Params{"epicId": epicID})
+	if err != nil {
+		return estimate.Totals{}
+	}
+	return tasksEstimate(app, tasks)
+}
+
+// tasksEstimate returns the combined estimate of tasks and their
+// sub-tasks. In remote mode it rolls up the tasks of all boards, fetched at
+// once instead of level by level.
+func tasksEstimate(app *pocketbase.PocketBase, tasks []*core.Record) estimate.Totals {
+	if !isRemoteMode() {
+		return estimate.ForTasks(app, tasks)
+	}
+	if len(tasks) == 0 {
+		return estimate.Totals{}
+	}
+	all, err := findActiveRecords(app, "tasks", "", "")
+	if err != nil {
+		return estimate.Totals{}
+	}
+	return estimate.Sum(estimate.Items(tasks), estimate.Rollup(estimate.Items(all)))
+}
+
 // isValidHexColor validates a hex color string (#RRGGBB format)
 func isValidHexColor(color string) bool {
 	if len(color) != 7 || color[0] != '#' {
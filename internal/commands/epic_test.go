@@ -228,7 +228,6 @@ func setupTasksCollectionWithEpic(t *testing.T, app *pocketbase.PocketBase) {
 		Values:   []string{"user", "agent", "cli"},
 	})
 	collection.Fields.Add(&core.TextField{Name: "created_by_agent"})
-	collection.Fields.Add(&core.JSONField{Name: "history"})
 	collection.Fields.Add(&core.RelationField{
 		Name:          "epic",
 		CollectionId:  epicsCollection.Id,
@@ -273,7 +272,6 @@ func createTestTaskWithEpic(t *testing.T, app *pocketbase.PocketBase, title, epi
 	record.Set("labels", []string{})
 	record.Set("blocked_by", []string{})
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 	if epicID != "" {
 		record.Set("epic", epicID)
 	}
@@ -4,6 +4,7 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -13,7 +14,13 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/spf13/cobra"
 
-	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
+	"github.com/ramtinJ95/EgenSkriven/internal/boardreport"
+	"github.com/ramtinJ95/EgenSkriven/internal/calendar"
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
 )
 
 // ExportData represents the full export structure
@@ -23,6 +30,8 @@ type ExportData struct {
 	Boards   []ExportBoard `json:"boards"`
 	Epics    []ExportEpic  `json:"epics"`
 	Tasks    []ExportTask  `json:"tasks"`
+	// Comments are only written by the importers of other trackers
+	Comments []ExportComment `json:"comments,omitempty"`
 }
 
 // ExportBoard represents a board in export format
@@ -40,61 +49,117 @@ type ExportEpic struct {
 	Title       string `json:"title"`
 	Description string `json:"description,omitempty"`
 	Color       string `json:"color,omitempty"`
+	Board       string `json:"board,omitempty"`
 }
 
 // ExportTask represents a task in export format
 type ExportTask struct {
-	ID          string   `json:"id"`
-	Title       string   `json:"title"`
-	Description string   `json:"description,omitempty"`
-	Type        string   `json:"type"`
-	Priority    string   `json:"priority"`
-	Column      string   `json:"column"`
-	Position    float64  `json:"position"`
-	Board       string   `json:"board,omitempty"`
-	Epic        string   `json:"epic,omitempty"`
-	Parent      string   `json:"parent,omitempty"`
-	Labels      []string `json:"labels,omitempty"`
-	BlockedBy   []string `json:"blocked_by,omitempty"`
-	DueDate     string   `json:"due_date,omitempty"`
-	CreatedBy   string   `json:"created_by,omitempty"`
-	Created     string   `json:"created"`
-	Updated     string   `json:"updated"`
+	ID          string           `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description,omitempty"`
+	Type        string           `json:"type"`
+	Priority    string           `json:"priority"`
+	Column      string           `json:"column"`
+	Position    float64          `json:"position"`
+	Board       string           `json:"board,omitempty"`
+	Epic        string           `json:"epic,omitempty"`
+	Parent      string           `json:"parent,omitempty"`
+	Labels      []string         `json:"labels,omitempty"`
+	Assignees   []string         `json:"assignees,omitempty"`
+	BlockedBy   []string         `json:"blocked_by,omitempty"`
+	DueDate     string           `json:"due_date,omitempty"`
+	CreatedBy   string           `json:"created_by,omitempty"`
+	ArchivedAt  string           `json:"archived_at,omitempty"`
+	Checklist   []checklist.Item `json:"checklist,omitempty"`
+	Attachments []string         `json:"attachments,omitempty"`
+	Created     string           `json:"created"`
+	Updated     string           `json:"updated"`
+}
+
+// ExportComment represents a task comment in export format
+type ExportComment struct {
+	ID      string `json:"id"`
+	Task    string `json:"task"`
+	Content string `json:"content"`
+	Author  string `json:"author,omitempty"`
+	Created string `json:"created,omitempty"`
 }
 
 // newExportCmd creates the export command
 func newExportCmd(app *pocketbase.PocketBase) *cobra.Command {
 	var (
-		format     string
-		boardName  string
-		outputFile string
+		format          string
+		boardName       string
+		outputFile      string
+		includeArchived bool
+		archivedOnly    bool
+		epicRef         string
+		withComments    bool
+		since           string
+		entryKind       string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "export",
 		Short: "Export tasks and boards to a file",
-		Long: `Export all data as JSON or CSV for backup or migration.
+		Long: `Export all data as JSON or CSV for backup or migration, a board as a
+markdown or HTML report, or the due dates of a board as a calendar.
 
 The JSON format includes all boards, epics, and tasks with full metadata.
 The CSV format exports only tasks in a flat table format.
+The zip and tar (gzipped) formats bundle the JSON export as export.json
+with the files attached to the tasks under attachments/<task id>/; import
+restores both.
+The markdown and html formats render one board (--board, the default
+board, or the only board) or one epic (--epic) as a document: epics with
+progress bars, then a section per column with the tasks' display IDs,
+priorities, due dates, and checklists. --include-comments adds the task
+comments and --since a summary of the changes since then. Reports contain
+no generation time, so an unchanged board gives the same file.
+The ics format writes the open tasks of one board that have a due date as
+an iCalendar file of all-day events, or of to-dos with --entry todo. Entry
+UIDs are derived from the task IDs, so importing the file again updates the
+entries instead of duplicating them. The server publishes the same calendar
+at /api/egenskriven/calendar/<board>.ics for calendar subscriptions.
+Archived tasks are left out unless --include-archived or --archived is given.
 
 Examples:
   egenskriven export                           # JSON to stdout
   egenskriven export --format json > backup.json
   egenskriven export --format csv > tasks.csv
   egenskriven export --board work --format json
-  egenskriven export -o backup.json            # Write to file`,
+  egenskriven export -o backup.json            # Write to file
+  egenskriven export --format zip -o backup.zip
+  egenskriven export --include-archived -o full-backup.json
+  egenskriven export --format markdown --board WRK -o docs/board.md
+  egenskriven export --format html --epic "Q1 Launch" --include-comments -o launch.html
+  egenskriven export --format md --since 7d
+  egenskriven export --format ics --board WRK -o work.ics
+  egenskriven export --format ics --entry todo > work-todos.ics`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := getFormatter()
 
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
-			// Validate format
-			format = strings.ToLower(format)
-			if format != "json" && format != "csv" {
-				return out.Error(ExitValidation, fmt.Sprintf("unsupported format: %s (use 'json' or 'csv')", format), nil)
+			opts := exportOptions{
+				format:          format,
+				board:           boardName,
+				mode:            archive.ModeFor(includeArchived, archivedOnly),
+				epic:            epicRef,
+				includeComments: withComments,
+				entry:           entryKind,
+			}
+			if since != "" {
+				var err error
+				opts.since, err = parseSince(since)
+				if err != nil {
+					return out.Error(ExitValidation, err.Error(), nil)
+				}
+			}
+			if err := validateExport(&opts); err != nil {
+				return commandFailed(out, err)
 			}
 
 			// Determine output destination
@@ -110,27 +175,155 @@ Examples:
 				writer = os.Stdout
 			}
 
-			switch format {
-			case "json":
-				return exportJSON(app, boardName, writer, out)
-			case "csv":
-				return exportCSV(app, boardName, writer, out)
-			default:
-				return out.Error(ExitValidation, fmt.Sprintf("unsupported format: %s", format), nil)
+			var summary string
+			if isRemoteMode() {
+				// Reports and calendars fall back to the default board of
+				// this project, not the one of the server
+				if opts.board == "" && opts.epic == "" && (isReportFormat(opts.format) || opts.format == "ics") {
+					if cfg, _ := config.LoadProjectConfig(); cfg != nil {
+						opts.board = cfg.DefaultBoard
+					}
+				}
+				body, s, err := remoteClient().Export(opts)
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+				defer body.Close()
+				if _, err := io.Copy(writer, body); err != nil {
+					return out.Error(ExitGeneralError, fmt.Sprintf("failed to write export: %v", err), nil)
+				}
+				summary = s
+			} else {
+				s, err := writeExport(app, opts, writer)
+				if err != nil {
+					return commandFailed(out, err)
+				}
+				summary = s
 			}
+
+			// Print summary to stderr if not quiet
+			if !quietMode && writer != os.Stdout && summary != "" {
+				fmt.Fprintln(os.Stderr, summary)
+			}
+
+			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&format, "format", "f", "json", "Output format: json, csv")
+	cmd.Flags().StringVarP(&format, "format", "f", "json", "Output format: json, csv, markdown, html, ics, zip, tar")
 	cmd.Flags().StringVarP(&boardName, "board", "b", "", "Export specific board only")
 	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file path (default: stdout)")
+	cmd.Flags().BoolVar(&includeArchived, "include-archived", false, "Include archived tasks")
+	cmd.Flags().BoolVar(&archivedOnly, "archived", false, "Export only archived tasks")
+	cmd.Flags().StringVarP(&epicRef, "epic", "e", "", "Report on one epic (markdown and html)")
+	cmd.Flags().BoolVar(&withComments, "include-comments", false, "Include task comments (markdown and html)")
+	cmd.Flags().StringVar(&entryKind, "entry", "", "Calendar entry kind: event (default) or todo (ics)")
+	cmd.Flags().StringVar(&since, "since", "", "Summarize changes since (e.g. 7d, 2w, 2025-01-15; markdown and html)")
 
 	return cmd
 }
 
+// exportOptions are the flags of the export command.
+type exportOptions struct {
+	format          string
+	board           string
+	mode            archive.Mode
+	epic            string
+	includeComments bool
+	since           time.Time
+	entry           string
+}
+
+// validateExport normalizes the format of an export and checks that the
+// options apply to it.
+func validateExport(opts *exportOptions) error {
+	opts.format = strings.ToLower(opts.format)
+	if opts.format == "md" {
+		opts.format = "markdown"
+	}
+	format := opts.format
+	if format != "json" && format != "csv" && format != "ics" && !isBundleFormat(format) && !isReportFormat(format) {
+		return newCommandError(ExitValidation, "unsupported format: %s (use 'json', 'csv', 'markdown', 'html', 'ics', 'zip', or 'tar')", format)
+	}
+	if !isReportFormat(format) && (opts.epic != "" || opts.includeComments || !opts.since.IsZero()) {
+		return newCommandError(ExitValidation, "--epic, --include-comments, and --since only apply to the markdown and html formats")
+	}
+
+	if format != "ics" && opts.entry != "" {
+		return newCommandError(ExitValidation, "--entry only applies to the ics format")
+	}
+	if format == "ics" && opts.mode != archive.Active {
+		return newCommandError(ExitValidation, "the ics format only covers open tasks; --include-archived and --archived do not apply")
+	}
+	if opts.entry != "" {
+		if err := calendar.ValidateKind(opts.entry); err != nil {
+			return newCommandError(ExitValidation, "%s", err.Error())
+		}
+	}
+	return nil
+}
+
+// writeExport writes an export to writer and returns a summary of what it
+// exported.
+func writeExport(app *pocketbase.PocketBase, opts exportOptions, writer io.Writer) (string, error) {
+	switch opts.format {
+	case "json":
+		return exportJSON(app, opts.board, opts.mode, writer)
+	case "csv":
+		return exportCSV(app, opts.board, opts.mode, writer)
+	case "zip", "tar":
+		return exportBundle(app, opts.board, opts.mode, opts.format, writer)
+	case "markdown", "html":
+		return exportReport(app, opts, writer)
+	case "ics":
+		return exportCalendar(app, opts.board, opts.entry, writer)
+	default:
+		return "", newCommandError(ExitValidation, "unsupported format: %s", opts.format)
+	}
+}
+
+// exportContentType returns the media type of an export format.
+func exportContentType(format string) string {
+	switch format {
+	case "json":
+		return "application/json"
+	case "csv":
+		return "text/csv; charset=utf-8"
+	case "markdown":
+		return "text/markdown; charset=utf-8"
+	case "html":
+		return "text/html; charset=utf-8"
+	case "ics":
+		return "text/calendar; charset=utf-8"
+	case "zip":
+		return "application/zip"
+	default:
+		return "application/gzip"
+	}
+}
+
 // exportJSON exports all data in JSON format
-func exportJSON(app *pocketbase.PocketBase, boardFilter string, writer *os.File, out *output.Formatter) error {
-	data := ExportData{
+func exportJSON(app *pocketbase.PocketBase, boardFilter string, mode archive.Mode, writer io.Writer) (string, error) {
+	data, _, err := buildExportData(app, boardFilter, mode)
+	if err != nil {
+		return "", err
+	}
+
+	// Output JSON
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return "", newCommandError(ExitGeneralError, "failed to encode JSON: %v", err)
+	}
+
+	return fmt.Sprintf("Exported %d boards, %d epics, %d tasks",
+		len(data.Boards), len(data.Epics), len(data.Tasks)), nil
+}
+
+// buildExportData collects the boards, epics, and tasks to export, along
+// with the task records.
+func buildExportData(app *pocketbase.PocketBase, boardFilter string, mode archive.Mode) (*ExportData, []*core.Record, error) {
+	data := &ExportData{
 		Version:  "1.0",
 		Exported: time.Now().UTC().Format(time.RFC3339),
 		Boards:   []ExportBoard{},
@@ -139,7 +332,7 @@ func exportJSON(app *pocketbase.PocketBase, boardFilter string, writer *os.File,
 	}
 
 	// Export boards
-	boards, err := app.FindAllRecords("boards")
+	boards, err := app.FindAllRecords("boards", trash.Exclude(app, "boards"))
 	if err == nil {
 		for _, b := range boards {
 			columns := getExportStringSlice(b.Get("columns"))
@@ -154,7 +347,7 @@ func exportJSON(app *pocketbase.PocketBase, boardFilter string, writer *os.File,
 	}
 
 	// Export epics
-	epics, err := app.FindAllRecords("epics")
+	epics, err := app.FindAllRecords("epics", trash.Exclude(app, "epics"))
 	if err == nil {
 		for _, e := range epics {
 			data.Epics = append(data.Epics, ExportEpic{
@@ -162,6 +355,7 @@ func exportJSON(app *pocketbase.PocketBase, boardFilter string, writer *os.File,
 				Title:       e.GetString("title"),
 				Description: e.GetString("description"),
 				Color:       e.GetString("color"),
+				Board:       e.GetString("board"),
 			})
 		}
 	}
@@ -171,7 +365,7 @@ func exportJSON(app *pocketbase.PocketBase, boardFilter string, writer *os.File,
 	if boardFilter != "" {
 		board, err := findExportBoardByNameOrPrefix(app, boardFilter)
 		if err != nil {
-			return out.Error(ExitNotFound, fmt.Sprintf("board not found: %s", boardFilter), nil)
+			return nil, nil, newCommandError(ExitNotFound, "board not found: %s", boardFilter)
 		}
 		boardID = board.Id
 	}
@@ -180,12 +374,14 @@ func exportJSON(app *pocketbase.PocketBase, boardFilter string, writer *os.File,
 	if boardID != "" {
 		tasks, err = app.FindAllRecords("tasks",
 			dbx.NewExp("board = {:board}", dbx.Params{"board": boardID}),
+			trash.Exclude(app, "tasks"),
+			archive.Filter(app, mode),
 		)
 	} else {
-		tasks, err = app.FindAllRecords("tasks")
+		tasks, err = app.FindAllRecords("tasks", trash.Exclude(app, "tasks"), archive.Filter(app, mode))
 	}
 	if err != nil {
-		return out.Error(ExitGeneralError, fmt.Sprintf("failed to fetch tasks: %v", err), nil)
+		return nil, nil, newCommandError(ExitGeneralError, "failed to fetch tasks: %v", err)
 	}
 
 	for _, t := range tasks {
@@ -201,38 +397,29 @@ func exportJSON(app *pocketbase.PocketBase, boardFilter string, writer *os.File,
 			Epic:        t.GetString("epic"),
 			Parent:      t.GetString("parent"),
 			Labels:      getExportStringSlice(t.Get("labels")),
+			Assignees:   getExportStringSlice(t.Get("assignees")),
 			BlockedBy:   getExportStringSlice(t.Get("blocked_by")),
 			DueDate:     t.GetString("due_date"),
 			CreatedBy:   t.GetString("created_by"),
+			ArchivedAt:  exportDate(t, "archived_at"),
+			Checklist:   checklist.Get(t),
+			Attachments: attachment.Names(t),
 			Created:     t.GetDateTime("created").Time().Format(time.RFC3339),
 			Updated:     t.GetDateTime("updated").Time().Format(time.RFC3339),
 		})
 	}
 
-	// Output JSON
-	encoder := json.NewEncoder(writer)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(data); err != nil {
-		return out.Error(ExitGeneralError, fmt.Sprintf("failed to encode JSON: %v", err), nil)
-	}
-
-	// Print summary to stderr if not quiet
-	if !quietMode && writer != os.Stdout {
-		fmt.Fprintf(os.Stderr, "Exported %d boards, %d epics, %d tasks\n",
-			len(data.Boards), len(data.Epics), len(data.Tasks))
-	}
-
-	return nil
+	return data, tasks, nil
 }
 
 // exportCSV exports tasks in CSV format
-func exportCSV(app *pocketbase.PocketBase, boardFilter string, writer *os.File, out *output.Formatter) error {
+func exportCSV(app *pocketbase.PocketBase, boardFilter string, mode archive.Mode, writer io.Writer) (string, error) {
 	// Get tasks
 	var boardID string
 	if boardFilter != "" {
 		board, err := findExportBoardByNameOrPrefix(app, boardFilter)
 		if err != nil {
-			return out.Error(ExitNotFound, fmt.Sprintf("board not found: %s", boardFilter), nil)
+			return "", newCommandError(ExitNotFound, "board not found: %s", boardFilter)
 		}
 		boardID = board.Id
 	}
@@ -242,26 +429,27 @@ func exportCSV(app *pocketbase.PocketBase, boardFilter string, writer *os.File,
 	if boardID != "" {
 		tasks, err = app.FindAllRecords("tasks",
 			dbx.NewExp("board = {:board}", dbx.Params{"board": boardID}),
+			trash.Exclude(app, "tasks"),
+			archive.Filter(app, mode),
 		)
 	} else {
-		tasks, err = app.FindAllRecords("tasks")
+		tasks, err = app.FindAllRecords("tasks", trash.Exclude(app, "tasks"), archive.Filter(app, mode))
 	}
 	if err != nil {
-		return out.Error(ExitGeneralError, fmt.Sprintf("failed to fetch tasks: %v", err), nil)
+		return "", newCommandError(ExitGeneralError, "failed to fetch tasks: %v", err)
 	}
 
 	// Write CSV
 	csvWriter := csv.NewWriter(writer)
-	defer csvWriter.Flush()
 
 	// Header
 	header := []string{
 		"id", "title", "description", "type", "priority", "column",
 		"position", "board", "epic", "parent", "labels", "blocked_by",
-		"due_date", "created", "updated",
+		"due_date", "archived_at", "created", "updated",
 	}
 	if err := csvWriter.Write(header); err != nil {
-		return out.Error(ExitGeneralError, fmt.Sprintf("failed to write CSV header: %v", err), nil)
+		return "", newCommandError(ExitGeneralError, "failed to write CSV header: %v", err)
 	}
 
 	// Rows
@@ -280,20 +468,129 @@ func exportCSV(app *pocketbase.PocketBase, boardFilter string, writer *os.File,
 			strings.Join(getExportStringSlice(t.Get("labels")), ";"),
 			strings.Join(getExportStringSlice(t.Get("blocked_by")), ";"),
 			t.GetString("due_date"),
+			exportDate(t, "archived_at"),
 			t.GetDateTime("created").Time().Format(time.RFC3339),
 			t.GetDateTime("updated").Time().Format(time.RFC3339),
 		}
 		if err := csvWriter.Write(row); err != nil {
-			return out.Error(ExitGeneralError, fmt.Sprintf("failed to write CSV row: %v", err), nil)
+			return "", newCommandError(ExitGeneralError, "failed to write CSV row: %v", err)
 		}
 	}
 
-	// Print summary to stderr if not quiet
-	if !quietMode && writer != os.Stdout {
-		fmt.Fprintf(os.Stderr, "Exported %d tasks\n", len(tasks))
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return "", newCommandError(ExitGeneralError, "failed to write CSV: %v", err)
 	}
 
-	return nil
+	return fmt.Sprintf("Exported %d tasks", len(tasks)), nil
+}
+
+// isReportFormat reports whether an export format renders a board report.
+func isReportFormat(format string) bool {
+	return format == "markdown" || format == "html"
+}
+
+// exportReport renders a board or epic as a markdown or HTML report
+func exportReport(app *pocketbase.PocketBase, opts exportOptions, writer io.Writer) (string, error) {
+	var epicRecord *core.Record
+	if opts.epic != "" {
+		var err error
+		epicRecord, err = resolveEpic(app, opts.epic)
+		if err != nil {
+			return "", &commandError{code: ExitNotFound, message: err.Error(),
+				suggestion: "Use 'egenskriven epic list' to see available epics"}
+		}
+	}
+
+	boardRecord, err := reportBoard(app, opts.board, epicRecord)
+	if err != nil {
+		return "", &commandError{code: ExitValidation, message: err.Error(),
+			suggestion: "Choose a board with --board or an epic with --epic"}
+	}
+
+	report, err := boardreport.Build(app, boardreport.Options{
+		Board:           boardRecord,
+		Epic:            epicRecord,
+		IncludeComments: opts.includeComments,
+		Since:           opts.since,
+		Archive:         opts.mode,
+	})
+	if err != nil {
+		return "", newCommandError(ExitGeneralError, "failed to build report: %v", err)
+	}
+
+	if opts.format == "html" {
+		err = boardreport.HTML(writer, report)
+	} else {
+		err = boardreport.Markdown(writer, report)
+	}
+	if err != nil {
+		return "", newCommandError(ExitGeneralError, "failed to write report: %v", err)
+	}
+
+	return fmt.Sprintf("Exported report of %s (%d tasks)", report.Title, report.Total), nil
+}
+
+// reportBoard returns the board of a report: the given board, the epic's
+// board, the default board, or the only board.
+func reportBoard(app *pocketbase.PocketBase, boardFilter string, epic *core.Record) (*core.Record, error) {
+	if boardFilter != "" {
+		b, err := findExportBoardByNameOrPrefix(app, boardFilter)
+		if err != nil {
+			return nil, err
+		}
+		if epic != nil && epic.GetString("board") != "" && epic.GetString("board") != b.Id {
+			return nil, fmt.Errorf("epic %q is not on board %s", epic.GetString("title"), b.GetString("name"))
+		}
+		return b, nil
+	}
+
+	if epic != nil && epic.GetString("board") != "" {
+		return app.FindRecordById("boards", epic.GetString("board"))
+	}
+
+	if cfg, _ := config.LoadProjectConfig(); cfg != nil && cfg.DefaultBoard != "" {
+		if b, err := findExportBoardByNameOrPrefix(app, cfg.DefaultBoard); err == nil {
+			return b, nil
+		}
+	}
+
+	boards, err := app.FindAllRecords("boards", trash.Exclude(app, "boards"))
+	if err != nil {
+		return nil, err
+	}
+	if len(boards) != 1 {
+		return nil, fmt.Errorf("a report covers one board, and there are %d", len(boards))
+	}
+	return boards[0], nil
+}
+
+// exportCalendar writes the due dates of a board as an iCalendar file
+func exportCalendar(app *pocketbase.PocketBase, boardFilter, kind string, writer io.Writer) (string, error) {
+	boardRecord, err := reportBoard(app, boardFilter, nil)
+	if err != nil {
+		return "", &commandError{code: ExitValidation, message: err.Error(),
+			suggestion: "Choose a board with --board"}
+	}
+
+	cal, err := calendar.Build(app, calendar.Options{Board: boardRecord, Kind: kind})
+	if err != nil {
+		return "", newCommandError(ExitGeneralError, "failed to build calendar: %v", err)
+	}
+	if err := calendar.Write(writer, cal); err != nil {
+		return "", newCommandError(ExitGeneralError, "failed to write calendar: %v", err)
+	}
+
+	return fmt.Sprintf("Exported calendar of %s (%d tasks with due dates)", cal.Name, len(cal.Entries)), nil
+}
+
+// exportDate formats an optional date field as RFC 3339, or "" if unset.
+func exportDate(record *core.Record, field string) string {
+	date := record.GetDateTime(field)
+	if date.IsZero() {
+		return ""
+	}
+	return date.Time().Format(time.RFC3339)
 }
 
 // getExportStringSlice safely converts an interface to a string slice
@@ -324,6 +621,7 @@ func findExportBoardByNameOrPrefix(app *pocketbase.PocketBase, query string) (*c
 	boards, err := app.FindAllRecords("boards",
 		dbx.NewExp("name = {:query} OR prefix = {:query}",
 			dbx.Params{"query": query}),
+		trash.Exclude(app, "boards"),
 	)
 	if err != nil {
 		return nil, err
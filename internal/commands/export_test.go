@@ -9,9 +9,12 @@ import (
 
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
 	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
 )
 
@@ -149,8 +152,7 @@ func TestExportJSON_EmptyDatabase(t *testing.T) {
 	require.NoError(t, err)
 	defer writer.Close()
 
-	out := getFormatter()
-	err = exportJSON(app, "", writer, out)
+	_, err = exportJSON(app, "", archive.Active, writer)
 	require.NoError(t, err)
 	writer.Close()
 
@@ -188,8 +190,7 @@ func TestExportJSON_WithData(t *testing.T) {
 	require.NoError(t, err)
 	defer writer.Close()
 
-	out := getFormatter()
-	err = exportJSON(app, "", writer, out)
+	_, err = exportJSON(app, "", archive.Active, writer)
 	require.NoError(t, err)
 	writer.Close()
 
@@ -243,8 +244,7 @@ func TestExportJSON_BoardFilter(t *testing.T) {
 	require.NoError(t, err)
 	defer writer.Close()
 
-	out := getFormatter()
-	err = exportJSON(app, "Work", writer, out)
+	_, err = exportJSON(app, "Work", archive.Active, writer)
 	require.NoError(t, err)
 	writer.Close()
 
@@ -281,8 +281,7 @@ func TestExportJSON_BoardFilterByPrefix(t *testing.T) {
 	require.NoError(t, err)
 	defer writer.Close()
 
-	out := getFormatter()
-	err = exportJSON(app, "WRK", writer, out)
+	_, err = exportJSON(app, "WRK", archive.Active, writer)
 	require.NoError(t, err)
 	writer.Close()
 
@@ -314,8 +313,7 @@ func TestExportCSV_EmptyDatabase(t *testing.T) {
 	require.NoError(t, err)
 	defer writer.Close()
 
-	out := getFormatter()
-	err = exportCSV(app, "", writer, out)
+	_, err = exportCSV(app, "", archive.Active, writer)
 	require.NoError(t, err)
 	writer.Close()
 
@@ -354,8 +352,7 @@ func TestExportCSV_WithData(t *testing.T) {
 	require.NoError(t, err)
 	defer writer.Close()
 
-	out := getFormatter()
-	err = exportCSV(app, "", writer, out)
+	_, err = exportCSV(app, "", archive.Active, writer)
 	require.NoError(t, err)
 	writer.Close()
 
@@ -403,8 +400,7 @@ func TestExportCSV_BoardFilter(t *testing.T) {
 	require.NoError(t, err)
 	defer writer.Close()
 
-	out := getFormatter()
-	err = exportCSV(app, "Work", writer, out)
+	_, err = exportCSV(app, "Work", archive.Active, writer)
 	require.NoError(t, err)
 	writer.Close()
 
@@ -487,6 +483,26 @@ func TestFindExportBoardByNameOrPrefix(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestReportBoard(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	setupExportTestCollections(t, app)
+
+	work := createExportTestBoard(t, app, "Work", "WRK")
+
+	// The only board is reported without --board
+	found, err := reportBoard(app, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, work.Id, found.Id)
+
+	personal := createExportTestBoard(t, app, "Personal", "PER")
+	_, err = reportBoard(app, "", nil)
+	assert.ErrorContains(t, err, "there are 2")
+
+	found, err = reportBoard(app, "PER", nil)
+	require.NoError(t, err)
+	assert.Equal(t, personal.Id, found.Id)
+}
+
 // ========== Export to File Tests ==========
 
 func TestExportToFile(t *testing.T) {
@@ -508,8 +524,7 @@ func TestExportToFile(t *testing.T) {
 	writer, err := os.Create(outputPath)
 	require.NoError(t, err)
 
-	out := getFormatter()
-	err = exportJSON(app, "", writer, out)
+	_, err = exportJSON(app, "", archive.Active, writer)
 	require.NoError(t, err)
 	writer.Close()
 
@@ -554,8 +569,7 @@ func TestExportCSV_FieldValues(t *testing.T) {
 	require.NoError(t, err)
 	defer writer.Close()
 
-	out := getFormatter()
-	err = exportCSV(app, "", writer, out)
+	_, err = exportCSV(app, "", archive.Active, writer)
 	require.NoError(t, err)
 	writer.Close()
 
@@ -598,3 +612,67 @@ func indexOf(slice []string, val string) int {
 	}
 	return -1
 }
+
+// ========== Bundle Tests ==========
+
+func TestExportBundle_RoundTrip(t *testing.T) {
+	for _, format := range []string{"zip", "tar"} {
+		t.Run(format, func(t *testing.T) {
+			app := testutil.NewTestApp(t)
+			setupExportTestCollections(t, app)
+			addExportTestAttachmentsField(t, app)
+
+			board := createExportTestBoard(t, app, "Work", "WRK")
+			task := createExportTestTask(t, app, "With files", board.Id)
+			createExportTestTask(t, app, "Without files", board.Id)
+			file, err := filesystem.NewFileFromBytes([]byte("stack trace"), "crash.log")
+			require.NoError(t, err)
+			task.Set(attachment.Field, []*filesystem.File{file})
+			require.NoError(t, app.Save(task))
+
+			bundlePath := filepath.Join(t.TempDir(), "backup."+format)
+			writer, err := os.Create(bundlePath)
+			require.NoError(t, err)
+			_, err = exportBundle(app, "", archive.Active, format, writer)
+			require.NoError(t, err)
+			writer.Close()
+
+			data, files, err := readImportFile(bundlePath)
+			require.NoError(t, err)
+			assert.Len(t, data.Tasks, 2)
+			require.Len(t, files[task.Id], 1)
+			assert.Equal(t, "stack trace", string(files[task.Id][0].Data))
+
+			// Import into an empty database restores the files
+			target := testutil.NewTestApp(t)
+			setupExportTestCollections(t, target)
+			addExportTestAttachmentsField(t, target)
+			require.NoError(t, runImport(target, bundlePath, "merge", false, getFormatter()))
+
+			imported, err := target.FindRecordById("tasks", task.Id)
+			require.NoError(t, err)
+			infos, err := attachment.ForRecord(target, imported)
+			require.NoError(t, err)
+			require.Len(t, infos, 1)
+			assert.Equal(t, "crash.log", infos[0].DisplayName)
+			assert.Equal(t, int64(len("stack trace")), infos[0].Size)
+
+			// Importing again does not duplicate the files
+			require.NoError(t, runImport(target, bundlePath, "merge", false, getFormatter()))
+			imported, err = target.FindRecordById("tasks", task.Id)
+			require.NoError(t, err)
+			assert.Len(t, attachment.Names(imported), 1)
+		})
+	}
+}
+
+// addExportTestAttachmentsField adds the attachments field to the tasks
+// collection.
+func addExportTestAttachmentsField(t *testing.T, app *pocketbase.PocketBase) {
+	t.Helper()
+
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	tasks.Fields.Add(&core.FileField{Name: attachment.Field, MaxSelect: 10, MaxSize: 1 << 20})
+	require.NoError(t, app.Save(tasks))
+}
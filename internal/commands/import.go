@@ -1,28 +1,52 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
 	"github.com/spf13/cobra"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+	"github.com/ramtinJ95/EgenSkriven/internal/importer"
 	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
 )
 
 // newImportCmd creates the import command
 func newImportCmd(app *pocketbase.PocketBase) *cobra.Command {
 	var (
-		strategy string
-		dryRun   bool
+		strategy    string
+		dryRun      bool
+		from        string
+		mappingFile string
+		boardName   string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "import [file]",
 		Short: "Import tasks and boards from a file",
-		Long: `Import data from a JSON backup file.
+		Long: `Import data from a JSON backup file, or from a zip or tar bundle
+written by 'export --format zip|tar' (which also restores attachments).
+
+With --from, import the export of another tracker into one board:
+  github    - JSON list from 'gh issue list --json number,title,body,state,
+              labels,milestone,assignees,comments,createdAt,url'
+  jira-csv  - Jira "Export Excel CSV (all fields)"
+  trello    - Trello board "Export as JSON"
+  linear    - Linear "Export CSV"
+
+States, statuses, and list names map to columns, priority and type fields
+or labels (such as "P1" or "bug") to priorities and types, and milestones,
+Jira epics, and Linear projects to epics. Parent links and comments are
+kept. Override the built-in mappings with a YAML or JSON --mapping file.
+The board is named by --board, the mapping file, or the source, and is
+created if it does not exist. Importing the same file again updates the
+tasks of the first import rather than duplicating them.
 
 Strategies:
   merge   - Skip existing records, add new ones (default)
@@ -31,7 +55,11 @@ Strategies:
 Examples:
   egenskriven import backup.json
   egenskriven import backup.json --strategy replace
-  egenskriven import backup.json --dry-run`,
+  egenskriven import backup.json --dry-run
+  egenskriven import backup.zip
+  egenskriven import --from github issues.json --board Work --dry-run
+  egenskriven import --from jira-csv jira.csv --mapping jira-mapping.yaml
+  egenskriven import --from trello board.json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := getFormatter()
@@ -46,12 +74,25 @@ Examples:
 			}
 
 			filename := args[0]
-			return runImport(app, filename, strategy, dryRun, out)
+			if from == "" {
+				if mappingFile != "" || boardName != "" {
+					return out.Error(ExitValidation, "--mapping and --board require --from", nil)
+				}
+				return runImport(app, filename, strategy, dryRun, out)
+			}
+			if !containsString(importer.Sources, from) {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid --from: %s (use %s)",
+					from, strings.Join(importer.Sources, ", ")), nil)
+			}
+			return runExternalImport(app, from, filename, mappingFile, boardName, strategy, dryRun, out)
 		},
 	}
 
 	cmd.Flags().StringVar(&strategy, "strategy", "merge", "Import strategy: merge, replace")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview changes without applying")
+	cmd.Flags().StringVar(&from, "from", "", "Import the export of another tracker: github, jira-csv, trello, linear")
+	cmd.Flags().StringVar(&mappingFile, "mapping", "", "YAML or JSON file overriding how values map (with --from)")
+	cmd.Flags().StringVarP(&boardName, "board", "b", "", "Board to import into, created if missing (with --from)")
 
 	return cmd
 }
@@ -67,28 +108,41 @@ type ImportStats struct {
 	TasksCreated  int
 	TasksUpdated  int
 	TasksSkipped  int
+
+	AttachmentsCreated int
+	AttachmentsSkipped int
+
+	CommentsCreated int
+	CommentsUpdated int
+	CommentsSkipped int
 }
 
 // runImport performs the actual import
 func runImport(app *pocketbase.PocketBase, filename, strategy string, dryRun bool, out *output.Formatter) error {
-	// Read file
-	file, err := os.Open(filename)
+	// Read the JSON export or bundle
+	data, files, err := readImportFile(filename)
 	if err != nil {
-		return out.Error(ExitGeneralError, fmt.Sprintf("failed to open file: %v", err), nil)
-	}
-	defer file.Close()
-
-	// Parse JSON
-	var data ExportData
-	if err := json.NewDecoder(file).Decode(&data); err != nil {
-		return out.Error(ExitGeneralError, fmt.Sprintf("failed to parse JSON: %v", err), nil)
+		return out.Error(ExitGeneralError, err.Error(), nil)
 	}
 
 	if !quietMode {
 		fmt.Fprintf(os.Stderr, "Importing from %s (version %s, exported %s)\n",
 			filename, data.Version, data.Exported)
-		fmt.Fprintf(os.Stderr, "Found: %d boards, %d epics, %d tasks\n",
+	}
+
+	return importData(app, data, files, strategy, dryRun, out)
+}
+
+// importData imports the boards, epics, tasks, comments, and attachments of
+// an export and reports what was, or with dryRun would be, changed.
+func importData(app *pocketbase.PocketBase, data *ExportData, files map[string][]bundleFile, strategy string, dryRun bool, out *output.Formatter) error {
+	if !quietMode {
+		fmt.Fprintf(os.Stderr, "Found: %d boards, %d epics, %d tasks",
 			len(data.Boards), len(data.Epics), len(data.Tasks))
+		if len(data.Comments) > 0 {
+			fmt.Fprintf(os.Stderr, ", %d comments", len(data.Comments))
+		}
+		fmt.Fprintln(os.Stderr)
 
 		if dryRun {
 			fmt.Fprintln(os.Stderr, "\n[DRY RUN - no changes will be made]")
@@ -96,6 +150,12 @@ func runImport(app *pocketbase.PocketBase, filename, strategy string, dryRun boo
 		fmt.Fprintln(os.Stderr)
 	}
 
+	if !dryRun {
+		if err := importPolicy(app, data, strategy); err != nil {
+			return policyDenied(out, err)
+		}
+	}
+
 	stats := ImportStats{}
 
 	// Import boards
@@ -113,6 +173,16 @@ func runImport(app *pocketbase.PocketBase, filename, strategy string, dryRun boo
 		return out.Error(ExitGeneralError, fmt.Sprintf("failed to import tasks: %v", err), nil)
 	}
 
+	// Import comments
+	if err := importComments(app, data.Comments, strategy, dryRun, &stats); err != nil {
+		return out.Error(ExitGeneralError, fmt.Sprintf("failed to import comments: %v", err), nil)
+	}
+
+	// Import the attachments of a bundle
+	if err := importAttachments(app, files, dryRun, &stats); err != nil {
+		return out.Error(ExitGeneralError, fmt.Sprintf("failed to import attachments: %v", err), nil)
+	}
+
 	// Output results
 	if out.JSON {
 		out.WriteJSON(map[string]any{
@@ -134,6 +204,15 @@ func runImport(app *pocketbase.PocketBase, filename, strategy string, dryRun boo
 					"updated": stats.TasksUpdated,
 					"skipped": stats.TasksSkipped,
 				},
+				"comments": map[string]int{
+					"created": stats.CommentsCreated,
+					"updated": stats.CommentsUpdated,
+					"skipped": stats.CommentsSkipped,
+				},
+				"attachments": map[string]int{
+					"created": stats.AttachmentsCreated,
+					"skipped": stats.AttachmentsSkipped,
+				},
 			},
 		})
 	} else if !quietMode {
@@ -144,6 +223,14 @@ func runImport(app *pocketbase.PocketBase, filename, strategy string, dryRun boo
 			stats.EpicsCreated, stats.EpicsUpdated, stats.EpicsSkipped)
 		fmt.Fprintf(os.Stderr, "  Tasks:  %d created, %d updated, %d skipped\n",
 			stats.TasksCreated, stats.TasksUpdated, stats.TasksSkipped)
+		if len(data.Comments) > 0 {
+			fmt.Fprintf(os.Stderr, "  Comments: %d created, %d updated, %d skipped\n",
+				stats.CommentsCreated, stats.CommentsUpdated, stats.CommentsSkipped)
+		}
+		if len(files) > 0 {
+			fmt.Fprintf(os.Stderr, "  Attachments: %d created, %d skipped\n",
+				stats.AttachmentsCreated, stats.AttachmentsSkipped)
+		}
 
 		if dryRun {
 			fmt.Fprintln(os.Stderr, "\n[DRY RUN - no changes were made]")
@@ -153,6 +240,43 @@ func runImport(app *pocketbase.PocketBase, filename, strategy string, dryRun boo
 	return nil
 }
 
+// importPolicy checks that the caller may write to every board an import
+// touches. Existing boards are checked with their agent mode, new boards
+// with agent.mode from the config.
+func importPolicy(app *pocketbase.PocketBase, data *ExportData, strategy string) error {
+	action := policy.ActionCreate
+	if strategy == "replace" {
+		action = policy.ActionUpdate
+	}
+	caller := resolveCaller(app, "")
+
+	boardIDs := make([]string, 0, len(data.Boards))
+	for _, b := range data.Boards {
+		boardIDs = append(boardIDs, b.ID)
+	}
+	for _, e := range data.Epics {
+		boardIDs = append(boardIDs, e.Board)
+	}
+	for _, t := range data.Tasks {
+		boardIDs = append(boardIDs, t.Board)
+	}
+
+	checked := make(map[string]bool)
+	for _, boardID := range boardIDs {
+		if checked[boardID] {
+			continue
+		}
+		checked[boardID] = true
+		if _, err := app.FindRecordById("boards", boardID); err != nil {
+			boardID = ""
+		}
+		if err := policy.Check(app, caller, boardID, action).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // importBoards imports board records
 func importBoards(app *pocketbase.PocketBase, boards []ExportBoard, strategy string, dryRun bool, stats *ImportStats) error {
 	collection, err := app.FindCollectionByNameOrId("boards")
@@ -229,6 +353,9 @@ func importEpics(app *pocketbase.PocketBase, epics []ExportEpic, strategy string
 					if e.Color != "" {
 						existing.Set("color", e.Color)
 					}
+					if e.Board != "" {
+						existing.Set("board", e.Board)
+					}
 					if err := app.Save(existing); err != nil {
 						return fmt.Errorf("failed to update epic %s: %w", e.Title, err)
 					}
@@ -249,6 +376,9 @@ func importEpics(app *pocketbase.PocketBase, epics []ExportEpic, strategy string
 			if e.Color != "" {
 				record.Set("color", e.Color)
 			}
+			if e.Board != "" {
+				record.Set("board", e.Board)
+			}
 			if err := app.Save(record); err != nil {
 				return fmt.Errorf("failed to import epic %s: %w", e.Title, err)
 			}
@@ -285,8 +415,12 @@ func importTasks(app *pocketbase.PocketBase, tasks []ExportTask, strategy string
 					existing.Set("epic", t.Epic)            // Clear if empty
 					existing.Set("parent", t.Parent)        // Clear if empty
 					existing.Set("labels", t.Labels)        // Clear if nil/empty
+					existing.Set("assignees", t.Assignees)  // Clear if nil/empty
 					existing.Set("blocked_by", t.BlockedBy) // Clear if nil/empty
 					existing.Set("due_date", t.DueDate)     // Clear if empty
+					if len(t.Checklist) > 0 && checklist.Supported(existing) {
+						checklist.Set(existing, t.Checklist)
+					}
 					if err := app.Save(existing); err != nil {
 						return fmt.Errorf("failed to update task %s: %w", t.Title, err)
 					}
@@ -326,12 +460,33 @@ func importTasks(app *pocketbase.PocketBase, tasks []ExportTask, strategy string
 			if len(t.Labels) > 0 {
 				record.Set("labels", t.Labels)
 			}
+			if len(t.Assignees) > 0 {
+				record.Set("assignees", t.Assignees)
+			}
 			if len(t.BlockedBy) > 0 {
 				record.Set("blocked_by", t.BlockedBy)
 			}
 			if t.DueDate != "" {
 				record.Set("due_date", t.DueDate)
 			}
+			if t.ArchivedAt != "" {
+				record.Set("archived_at", t.ArchivedAt)
+			}
+			if len(t.Checklist) > 0 && checklist.Supported(record) {
+				checklist.Set(record, t.Checklist)
+			}
+			// Keep the original creation time
+			if created, err := types.ParseDateTime(t.Created); err == nil && !created.IsZero() {
+				record.SetRaw("created", created)
+			}
+			// New tasks get the next display ID of their board
+			if t.Board != "" && collection.Fields.GetByName("seq") != nil {
+				seq, err := board.GetNextSequence(app, t.Board)
+				if err != nil {
+					return fmt.Errorf("failed to import task %s: %w", t.Title, err)
+				}
+				record.Set("seq", seq)
+			}
 			if err := app.Save(record); err != nil {
 				return fmt.Errorf("failed to import task %s: %w", t.Title, err)
 			}
@@ -341,3 +496,55 @@ func importTasks(app *pocketbase.PocketBase, tasks []ExportTask, strategy string
 
 	return nil
 }
+
+// importComments imports comment records. Comments are written by humans,
+// under the author's name from the export.
+func importComments(app *pocketbase.PocketBase, comments []ExportComment, strategy string, dryRun bool, stats *ImportStats) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	collection, err := app.FindCollectionByNameOrId("comments")
+	if err != nil {
+		return err
+	}
+
+	for _, c := range comments {
+		existing, err := app.FindRecordById("comments", c.ID)
+
+		if err == nil && existing != nil {
+			// Record exists
+			if strategy == "replace" {
+				if !dryRun {
+					existing.Set("content", c.Content)
+					existing.Set("author_id", c.Author)
+					if err := app.Save(existing); err != nil {
+						return fmt.Errorf("failed to update comment %s: %w", c.ID, err)
+					}
+				}
+				stats.CommentsUpdated++
+			} else {
+				stats.CommentsSkipped++
+			}
+			continue
+		}
+
+		// Create new record
+		if !dryRun {
+			record := core.NewRecord(collection)
+			record.Id = c.ID
+			record.Set("task", c.Task)
+			record.Set("content", c.Content)
+			record.Set("author_type", "human")
+			record.Set("author_id", c.Author)
+			if created, err := types.ParseDateTime(c.Created); err == nil && !created.IsZero() {
+				record.SetRaw("created", created)
+			}
+			if err := app.Save(record); err != nil {
+				return fmt.Errorf("failed to import comment %s: %w", c.ID, err)
+			}
+		}
+		stats.CommentsCreated++
+	}
+
+	return nil
+}
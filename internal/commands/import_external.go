@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pocketbase/pocketbase"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/importer"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/position"
+)
+
+// runExternalImport imports the export of another tracker through the
+// same steps, strategies, and summary as a backup import.
+func runExternalImport(app *pocketbase.PocketBase, from, filename, mappingFile, boardName, strategy string, dryRun bool, out *output.Formatter) error {
+	mapping := &importer.Mapping{}
+	if mappingFile != "" {
+		var err error
+		if mapping, err = importer.LoadMapping(mappingFile); err != nil {
+			return out.Error(ExitValidation, err.Error(), nil)
+		}
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return out.Error(ExitGeneralError, fmt.Sprintf("failed to open file: %v", err), nil)
+	}
+	parsed, err := importer.Parse(from, content, mapping)
+	if err != nil {
+		return out.Error(ExitValidation, err.Error(), nil)
+	}
+
+	data := externalImportData(app, from, parsed, mapping, boardName)
+
+	if !quietMode {
+		fmt.Fprintf(os.Stderr, "Importing from %s (%s export)\n", filename, from)
+		for _, w := range parsed.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+	}
+
+	return importData(app, data, nil, strategy, dryRun, out)
+}
+
+// externalImportData converts a parsed export to the records of an import
+// into one board: the named board if it exists, or a new board.
+//
+// Record IDs are derived from the board and the IDs of the source, so
+// importing the same file again skips (merge) or updates (replace) the
+// records of the first import instead of duplicating them.
+func externalImportData(app *pocketbase.PocketBase, from string, parsed *importer.Data, mapping *importer.Mapping, boardName string) *ExportData {
+	name := boardName
+	if name == "" {
+		name = mapping.Board
+	}
+	if name == "" {
+		name = parsed.Board
+	}
+	if name == "" {
+		name = "Imported"
+	}
+
+	data := &ExportData{
+		Version:  "1.0",
+		Exported: time.Now().UTC().Format(time.RFC3339),
+		Boards:   []ExportBoard{},
+		Epics:    []ExportEpic{},
+		Tasks:    []ExportTask{},
+	}
+
+	var boardID string
+	if existing, err := findExportBoardByNameOrPrefix(app, name); err == nil {
+		boardID = existing.Id
+	} else {
+		prefix := strings.ToUpper(mapping.Prefix)
+		if prefix == "" {
+			prefix = importPrefix(app, name)
+		}
+		boardID = importID(from, "board", strings.ToLower(name))
+		data.Boards = append(data.Boards, ExportBoard{
+			ID:      boardID,
+			Name:    name,
+			Prefix:  prefix,
+			Columns: board.DefaultColumns,
+		})
+	}
+
+	epicIDs := make(map[string]string, len(parsed.Epics))
+	for _, e := range parsed.Epics {
+		epicIDs[e.Key] = importID(from, boardID, "epic", e.Key)
+		data.Epics = append(data.Epics, ExportEpic{
+			ID:          epicIDs[e.Key],
+			Title:       e.Title,
+			Description: e.Description,
+			Board:       boardID,
+		})
+	}
+
+	taskIDs := make(map[string]string, len(parsed.Tasks))
+	for _, t := range parsed.Tasks {
+		taskIDs[t.Key] = importID(from, boardID, "task", t.Key)
+	}
+
+	// Imported tasks go after the tasks already in each column; tasks of
+	// an earlier import keep their place
+	positions := make(map[string]float64)
+	for _, t := range parsed.Tasks {
+		var pos float64
+		if existing, err := app.FindRecordById("tasks", taskIDs[t.Key]); err == nil && existing.GetString("column") == t.Column {
+			pos = existing.GetFloat("position")
+		} else if last, ok := positions[t.Column]; ok {
+			pos = last + position.DefaultGap
+			positions[t.Column] = pos
+		} else {
+			pos = position.GetNext(app, t.Column)
+			positions[t.Column] = pos
+		}
+
+		data.Tasks = append(data.Tasks, ExportTask{
+			ID:          taskIDs[t.Key],
+			Title:       t.Title,
+			Description: t.Description,
+			Type:        t.Type,
+			Priority:    t.Priority,
+			Column:      t.Column,
+			Position:    pos,
+			Board:       boardID,
+			Epic:        epicIDs[t.Epic],
+			Parent:      taskIDs[t.Parent],
+			Labels:      t.Labels,
+			Assignees:   t.Assignees,
+			DueDate:     t.DueDate,
+			CreatedBy:   "cli",
+			ArchivedAt:  t.Archived,
+			Checklist:   t.Checklist,
+			Created:     t.Created,
+		})
+
+		for i, c := range t.Comments {
+			data.Comments = append(data.Comments, ExportComment{
+				ID:      importID(from, boardID, "comment", fmt.Sprintf("%s/%d", t.Key, i)),
+				Task:    taskIDs[t.Key],
+				Content: c.Content,
+				Author:  c.Author,
+				Created: c.Created,
+			})
+		}
+	}
+
+	return data
+}
+
+// importID derives a record ID from the parts naming a record in an
+// import. Record IDs are 15 lowercase letters and digits.
+func importID(parts ...string) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	id := make([]byte, 15)
+	for i := range id {
+		id[i] = alphabet[int(sum[i])%len(alphabet)]
+	}
+	return string(id)
+}
+
+// importPrefix derives an unused board prefix from the letters and digits
+// of a board name, such as "MYREPO" for "my-repo", or "MYREPO2" when
+// MYREPO is taken.
+func importPrefix(app *pocketbase.PocketBase, name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) && b.Len() < 8 {
+			b.WriteRune(r)
+		}
+	}
+	prefix := b.String()
+	if prefix == "" {
+		prefix = "IMP"
+	}
+
+	candidate := prefix
+	for n := 2; ; n++ {
+		if _, err := app.FindFirstRecordByData("boards", "prefix", candidate); err != nil {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", prefix, n)
+	}
+}
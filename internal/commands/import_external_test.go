@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+const externalGitHubIssues = `[
+  {"number": 1, "title": "Epic work", "state": "OPEN", "milestone": {"title": "v1"},
+   "url": "https://github.com/acme/widgets/issues/1",
+   "comments": [{"author": {"login": "octocat"}, "body": "First!", "createdAt": "2026-09-02T11:00:00Z"}]},
+  {"number": 2, "title": "Sub-issue", "state": "CLOSED", "labels": [{"name": "bug"}],
+   "parent_issue_url": "https://api.github.com/repos/acme/widgets/issues/1"}
+]`
+
+// setupExternalImportCollections creates the import collections and the
+// comments collection
+func setupExternalImportCollections(t *testing.T, app *pocketbase.PocketBase) {
+	t.Helper()
+	setupImportTestCollections(t, app)
+	testutil.CreateTestCollection(t, app, "comments",
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "content"},
+		&core.TextField{Name: "author_type"},
+		&core.TextField{Name: "author_id"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+}
+
+func TestRunExternalImport(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	setupExternalImportCollections(t, app)
+
+	dir := t.TempDir()
+	issues := filepath.Join(dir, "issues.json")
+	require.NoError(t, os.WriteFile(issues, []byte(externalGitHubIssues), 0o644))
+	mapping := filepath.Join(dir, "mapping.yaml")
+	require.NoError(t, os.WriteFile(mapping, []byte("prefix: ACME\nusers:\n  octocat: ramtin\n"), 0o644))
+
+	out := getFormatter()
+
+	// A dry run changes nothing
+	require.NoError(t, runExternalImport(app, "github", issues, mapping, "", "merge", true, out))
+	boards, err := app.FindAllRecords("boards")
+	require.NoError(t, err)
+	assert.Empty(t, boards)
+
+	require.NoError(t, runExternalImport(app, "github", issues, mapping, "", "merge", false, out))
+
+	b, err := findExportBoardByNameOrPrefix(app, "ACME")
+	require.NoError(t, err)
+	assert.Equal(t, "widgets", b.GetString("name"), "the board is named after the repository")
+
+	tasks, err := app.FindRecordsByFilter("tasks", "board = {:board}", "position", 0, 0, map[string]any{"board": b.Id})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	parent, sub := tasks[0], tasks[1]
+	if parent.GetString("title") != "Epic work" {
+		parent, sub = sub, parent
+	}
+	assert.Equal(t, parent.Id, sub.GetString("parent"))
+	assert.Equal(t, "done", sub.GetString("column"))
+	assert.Equal(t, "bug", sub.GetString("type"))
+	assert.NotEmpty(t, parent.GetString("epic"))
+
+	comments, err := app.FindAllRecords("comments")
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, parent.Id, comments[0].GetString("task"))
+	assert.Equal(t, "ramtin", comments[0].GetString("author_id"))
+	assert.Equal(t, "2026-09-02 11:00:00.000Z", comments[0].GetString("created"), "comments keep their date")
+
+	// Importing again skips the records of the first import
+	require.NoError(t, runExternalImport(app, "github", issues, mapping, "", "merge", false, out))
+	all, err := app.FindAllRecords("tasks")
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+	all, err = app.FindAllRecords("comments")
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestRunExternalImport_ExistingBoard(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	setupExternalImportCollections(t, app)
+	board := createImportTestBoard(t, app, "Work", "WRK")
+
+	dir := t.TempDir()
+	issues := filepath.Join(dir, "issues.json")
+	require.NoError(t, os.WriteFile(issues, []byte(externalGitHubIssues), 0o644))
+
+	require.NoError(t, runExternalImport(app, "github", issues, "", "WRK", "merge", false, getFormatter()))
+
+	boards, err := app.FindAllRecords("boards")
+	require.NoError(t, err)
+	assert.Len(t, boards, 1, "no board is created")
+	tasks, err := app.FindAllRecords("tasks")
+	require.NoError(t, err)
+	for _, task := range tasks {
+		assert.Equal(t, board.Id, task.GetString("board"))
+	}
+}
+
+func TestImportID(t *testing.T) {
+	id := importID("github", "board1", "task", "#12")
+	assert.Len(t, id, 15)
+	assert.Regexp(t, "^[a-z0-9]+$", id)
+	assert.Equal(t, id, importID("github", "board1", "task", "#12"))
+	assert.NotEqual(t, id, importID("github", "board2", "task", "#12"))
+}
+
+func TestImportPrefix(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	setupImportTestCollections(t, app)
+
+	assert.Equal(t, "MYREPO", importPrefix(app, "my-repo"))
+	createImportTestBoard(t, app, "Other", "MYREPO")
+	assert.Equal(t, "MYREPO2", importPrefix(app, "my-repo"))
+	assert.Equal(t, "IMP", importPrefix(app, "—"))
+}
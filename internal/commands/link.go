@@ -0,0 +1,362 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasklink"
+)
+
+func newLinkCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		oneWay    bool
+		closeDup  bool
+		noClose   bool
+		agentName string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "link <task> <type> <task>",
+		Short: "Link two tasks with a typed relation",
+		Long: `Link two tasks with a typed relation. The link reads "<task> <type> <task>".
+
+Link types:
+  relates        relates to (shown as "relates to" on both tasks)
+  duplicates     duplicates (shown as "duplicated by" on the other task)
+  caused_by      caused by (shown as "causes" on the other task)
+  follow_up_of   follow-up of (shown as "followed up by" on the other task)
+
+Dashes work in place of underscores (caused-by, follow-up-of).
+Links are shown on both tasks unless --one-way is given.
+
+Blocking relations are managed with 'add --blocked-by' and 'update --blocked-by'.
+
+Marking a task as a duplicate offers to close it: the duplicate is moved to
+done and its comments are moved to the canonical task. Use --close to do
+this without asking, or --no-close to only record the link.`,
+		Example: `  egenskriven link WRK-1 duplicates WRK-9
+  egenskriven link WRK-1 duplicates WRK-9 --close
+  egenskriven link WRK-12 caused-by WRK-7
+  egenskriven link WRK-20 follow-up-of WRK-14 --one-way
+  egenskriven link WRK-3 relates WRK-4`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if closeDup && noClose {
+				return out.Error(ExitInvalidArguments, "--close and --no-close cannot be used together", nil)
+			}
+
+			linkType, err := tasklink.ParseType(args[1])
+			if err != nil {
+				return out.Error(ExitValidation, err.Error(), nil)
+			}
+			if (closeDup || noClose) && linkType != tasklink.TypeDuplicates {
+				return out.Error(ExitInvalidArguments, "--close and --no-close only apply to duplicates links", nil)
+			}
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			source, target, err := resolveLinkTasks(app, out, args[0], args[2])
+			if err != nil {
+				return err
+			}
+
+			caller := resolveCaller(app, agentName)
+			if err := checkTaskPolicy(app, caller, source, policy.ActionUpdate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
+			sourceID := getTaskDisplayID(app, source)
+			targetID := getTaskDisplayID(app, target)
+
+			// Offer to close the duplicate
+			closed := false
+			if linkType == tasklink.TypeDuplicates && !noClose && source.GetString("column") != "done" {
+				closed = closeDup
+				if !closed && !quietMode && !jsonOutput && stdinIsTerminal() {
+					fmt.Printf("Close %s and move its comments to %s? [y/N]: ", sourceID, targetID)
+					reader := bufio.NewReader(os.Stdin)
+					response, _ := reader.ReadString('\n')
+					response = strings.TrimSpace(strings.ToLower(response))
+					closed = response == "y" || response == "yes"
+				}
+			}
+
+			var result map[string]any
+			if isRemoteMode() {
+				result, err = remoteClient().LinkTasks(source.Id, linkRequest{
+					Target: target.Id,
+					Type:   linkType,
+					OneWay: oneWay,
+					Close:  closed,
+					Agent:  requestAgent(caller),
+				})
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				result, err = linkTasks(app, caller, source, target, linkType, oneWay, closed)
+				if err != nil {
+					var v *policy.Violation
+					if errors.As(err, &v) {
+						return policyDenied(out, err)
+					}
+					return commandFailed(out, err)
+				}
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			message, _ := result["message"].(string)
+			out.Success("Linked: " + message)
+			if closed && !quietMode {
+				fmt.Printf("Closed %s as a duplicate; moved %d comment(s) to %s\n",
+					sourceID, resultInt(result, "comments_moved"), targetID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&oneWay, "one-way", false, "Only show the link on the first task")
+	cmd.Flags().BoolVar(&closeDup, "close", false, "Close the duplicate and move its comments without asking")
+	cmd.Flags().BoolVar(&noClose, "no-close", false, "Only record the duplicate link")
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent identifier (subject to the board's agent mode)")
+
+	return cmd
+}
+
+func newUnlinkCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var agentName string
+
+	cmd := &cobra.Command{
+		Use:   "unlink <task> [type] <task>",
+		Short: "Remove links between two tasks",
+		Long: `Remove the links between two tasks, in either direction.
+
+Without a type, all links between the tasks are removed.`,
+		Example: `  egenskriven unlink WRK-1 WRK-9
+  egenskriven unlink WRK-1 duplicates WRK-9`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			linkType := ""
+			otherRef := args[1]
+			if len(args) == 3 {
+				t, err := tasklink.ParseType(args[1])
+				if err != nil {
+					return out.Error(ExitValidation, err.Error(), nil)
+				}
+				linkType = t
+				otherRef = args[2]
+			}
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			task, other, err := resolveLinkTasks(app, out, args[0], otherRef)
+			if err != nil {
+				return err
+			}
+			caller := resolveCaller(app, agentName)
+			if err := checkTaskPolicy(app, caller, task, policy.ActionUpdate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
+			var result map[string]any
+			if isRemoteMode() {
+				result, err = remoteClient().UnlinkTasks(task.Id, unlinkRequest{
+					Other: other.Id,
+					Type:  linkType,
+					Agent: requestAgent(caller),
+				})
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				result, err = unlinkTasks(app, task, other, linkType)
+				if err != nil {
+					return commandFailed(out, err)
+				}
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+			out.Success(fmt.Sprintf("Removed %d link(s) between %s and %s",
+				resultInt(result, "removed"), getTaskDisplayID(app, task), getTaskDisplayID(app, other)))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent identifier (subject to the board's agent mode)")
+
+	return cmd
+}
+
+// resolveLinkTasks resolves the two tasks of a link.
+func resolveLinkTasks(app *pocketbase.PocketBase, out *output.Formatter, refA, refB string) (*core.Record, *core.Record, error) {
+	tasks := make([]*core.Record, 2)
+	for i, ref := range []string{refA, refB} {
+		task, err := resolveTask(app, ref)
+		if err != nil {
+			if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+				return nil, nil, out.AmbiguousError(ref, ambErr.Matches)
+			}
+			return nil, nil, out.Error(ExitNotFound, err.Error(), nil)
+		}
+		tasks[i] = task
+	}
+	return tasks[0], tasks[1], nil
+}
+
+// linkTasks links source to target and, with closeDup, closes the
+// duplicate source, and returns the result printed by 'link --json'. The
+// close is checked against the agent mode before the link is saved, so a
+// denied close leaves nothing behind.
+func linkTasks(app *pocketbase.PocketBase, caller policy.Actor, source, target *core.Record, linkType string, oneWay, closeDup bool) (map[string]any, error) {
+	sourceID := getTaskDisplayID(app, source)
+	targetID := getTaskDisplayID(app, target)
+
+	closed := closeDup && linkType == tasklink.TypeDuplicates && source.GetString("column") != "done"
+	toColumn := ""
+	if closed {
+		var err error
+		toColumn, err = policyColumn(app, caller, source.GetString("board"), source.GetString("column"), "done")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	link, err := tasklink.Create(app, source, target, linkType, oneWay, caller.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, tasklink.ErrSelfLink):
+			return nil, newCommandError(ExitValidation, "%s", err.Error())
+		case errors.Is(err, tasklink.ErrExists):
+			return nil, newCommandError(ExitConflict, "%s already %s %s",
+				sourceID, tasklink.Label(linkType, tasklink.Outgoing), targetID)
+		}
+		return nil, fmt.Errorf("failed to create link: %w", err)
+	}
+
+	commentsMoved := 0
+	if closed {
+		commentsMoved, err = closeDuplicate(app, caller.Name, source, target, toColumn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to close duplicate: %w", err)
+		}
+	}
+
+	result := map[string]any{
+		"success":        true,
+		"id":             link.Id,
+		"source":         source.Id,
+		"source_display": sourceID,
+		"target":         target.Id,
+		"target_display": targetID,
+		"type":           linkType,
+		"one_way":        oneWay,
+		"message":        fmt.Sprintf("%s %s %s", sourceID, tasklink.Label(linkType, tasklink.Outgoing), targetID),
+	}
+	if linkType == tasklink.TypeDuplicates {
+		result["closed"] = closed
+		result["comments_moved"] = commentsMoved
+	}
+	return result, nil
+}
+
+// unlinkTasks removes the links of a type (all types if empty) between two
+// tasks and returns the result printed by 'unlink --json'.
+func unlinkTasks(app *pocketbase.PocketBase, task, other *core.Record, linkType string) (map[string]any, error) {
+	links, err := tasklink.Find(app, task.Id, other.Id, linkType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find links: %w", err)
+	}
+	if len(links) == 0 {
+		return nil, newCommandError(ExitNotFound, "no links between %s and %s",
+			getTaskDisplayID(app, task), getTaskDisplayID(app, other))
+	}
+
+	for _, link := range links {
+		if err := app.Delete(link); err != nil {
+			return nil, fmt.Errorf("failed to remove link: %w", err)
+		}
+	}
+
+	return map[string]any{
+		"success": true,
+		"removed": len(links),
+		"task":    task.Id,
+		"other":   other.Id,
+	}, nil
+}
+
+// closeDuplicate moves a duplicate task to toColumn (done, unless the
+// agent mode of the board downgrades it) and its comments to the canonical
+// task, recording both in the task event log. It returns the number of
+// comments moved.
+func closeDuplicate(app *pocketbase.PocketBase, agent string, dup, canonical *core.Record, toColumn string) (int, error) {
+	fromColumn := dup.GetString("column")
+	moved := 0
+	err := app.RunInTransaction(func(txApp core.App) error {
+		n, err := tasklink.MoveComments(txApp, dup.Id, canonical.Id)
+		if err != nil {
+			return err
+		}
+		moved = n
+
+		dup.Set("column", toColumn)
+		dup.Set("position", GetNextPosition(app, toColumn))
+		addTaskEvent(dup, "closed_as_duplicate", agent, map[string]any{
+			"column": map[string]any{
+				"from": fromColumn,
+				"to":   toColumn,
+			},
+			"duplicate_of":   getTaskDisplayID(app, canonical),
+			"comments_moved": n,
+		})
+		if err := txApp.Save(dup); err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+
+		if n > 0 {
+			addTaskEvent(canonical, "comments_merged", agent, map[string]any{
+				"duplicate":      getTaskDisplayID(app, dup),
+				"comments_moved": n,
+			})
+			if err := txApp.Save(canonical); err != nil {
+				return fmt.Errorf("failed to update task: %w", err)
+			}
+		}
+		return nil
+	})
+	return moved, err
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal, so
+// prompts are not shown to scripts and agents piping input.
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
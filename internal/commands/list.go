@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -9,35 +10,48 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/spf13/cobra"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
 	"github.com/ramtinJ95/EgenSkriven/internal/board"
 	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskquery"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+	"github.com/ramtinJ95/EgenSkriven/internal/view"
 )
 
 func newListCmd(app *pocketbase.PocketBase) *cobra.Command {
 	var (
-		columns    []string
-		types      []string
-		priorities []string
-		search     string
-		createdBy  string
-		agentName  string
-		ready      bool
-		isBlocked  bool
-		notBlocked bool
-		fields     string
-		epicFilter string
-		labels     []string
-		limit      int
-		sort       string
-		boardRef   string
-		allBoards  bool
-		dueBefore  string
-		dueAfter   string
-		hasDue     bool
-		noDue      bool
-		hasParent  bool
-		noParent   bool
-		needInput  bool
+		columns         []string
+		types           []string
+		priorities      []string
+		search          string
+		createdBy       string
+		agentName       string
+		ready           bool
+		isBlocked       bool
+		notBlocked      bool
+		fields          string
+		epicFilter      string
+		labels          []string
+		limit           int
+		sort            string
+		boardRef        string
+		allBoards       bool
+		dueBefore       string
+		dueAfter        string
+		hasDue          bool
+		noDue           bool
+		hasParent       bool
+		noParent        bool
+		needInput       bool
+		sprintRef       string
+		assignee        string
+		includeArchived bool
+		archivedOnly    bool
+		viewRef         string
+		queryText       string
+		groupBy         string
+		format          string
 	)
 
 	cmd := &cobra.Command{
@@ -45,7 +59,30 @@ func newListCmd(app *pocketbase.PocketBase) *cobra.Command {
 		Short: "List tasks",
 		Long: `List and filter tasks on the kanban board.
 
-By default, shows all tasks grouped by column. Use flags to filter.
+By default, shows all tasks grouped by column. Use flags to filter, or
+--query for the query language shared with the TUI and saved views:
+
+  field:value        priority:high  column:todo  label:backend  epic:"Auth"
+  field:a,b          any of the values: priority:high,urgent
+  -field:value       negation: -label:wontfix
+  due:<7d            dates: due, created, updated with <, <=, >, >= and
+                     YYYY-MM-DD, today, tomorrow, or offsets like 7d, -2w, 1m
+  is:<state>         blocked, unblocked, ready, open, done, overdue,
+                     subtask, toplevel, assigned, unassigned
+  has:<field>        due, epic, sprint, parent, estimate, label, assignee
+  word, "a phrase"   search title and description (text:"token")
+  a OR b, ( )        combine terms; terms without OR must all match
+
+Other fields: type, by (user, agent, cli), agent, sprint, parent,
+assignee (me, none), estimate, title. Use none/any for empty/set fields.
+
+--group-by groups the tasks by column, priority, epic, label, assignee,
+board, or due-week, with a count per group. --format prints the tasks as
+text, json, ndjson, yaml, or markdown (a table), or with a Go template
+that is executed for each task with its JSON fields. Named templates can
+be set in the list_formats config:
+
+  {"list_formats": {"short": "{{.display_id}} {{.title}} {{join .labels \",\"}}"}}
 
 Examples:
   egenskriven list
@@ -57,292 +94,126 @@ Examples:
   egenskriven list --epic "Q1 Launch"
   egenskriven list --label frontend --label ui
   egenskriven list --limit 10
-  egenskriven list --sort "-priority,position"`,
+  egenskriven list --sort "-priority,position"
+  egenskriven list --sprint current
+  egenskriven list --assignee me
+  egenskriven list --assignee none
+  egenskriven list --archived
+  egenskriven list --column done --include-archived
+  egenskriven list --view "Urgent bugs"
+  egenskriven list --query 'priority:high,urgent -label:wontfix due:<7d'
+  egenskriven list --query 'is:open (type:bug OR label:urgent) assignee:me'
+  egenskriven list --group-by epic
+  egenskriven list --group-by due-week --format markdown
+  egenskriven list --format ndjson --fields display_id,title,column
+  egenskriven list --format '{{.display_id}} {{.priority}} {{.title}}'
+  egenskriven list --format short`,
 		Aliases: []string{"ls"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
-			// Build filter expressions
-			var filters []dbx.Expression
-
-			// Validate mutually exclusive flags
-			if isBlocked && notBlocked {
-				return out.Error(ExitValidation,
-					"--is-blocked and --not-blocked are mutually exclusive", nil)
-			}
-
-			// Board filter (unless --all-boards is set)
-			var boardsMap map[string]*core.Record
-			if !allBoards {
-				// Determine which board to filter by
-				boardRefToUse := boardRef
-				if boardRefToUse == "" {
-					// Check config for default board
-					cfg, _ := config.LoadProjectConfig()
-					if cfg != nil && cfg.DefaultBoard != "" {
-						boardRefToUse = cfg.DefaultBoard
-					}
-				}
-
-				if boardRefToUse != "" {
-					boardRecord, err := board.GetByNameOrPrefix(app, boardRefToUse)
-					if err != nil {
-						return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
-					}
-					filters = append(filters, dbx.NewExp(
-						"board = {:board}",
-						dbx.Params{"board": boardRecord.Id},
-					))
-				}
-			}
-
-			// Load boards for display ID mapping
-			allBoardRecords, _ := board.GetAll(app)
-			boardsMap = make(map[string]*core.Record)
-			for _, b := range allBoardRecords {
-				boardsMap[b.Id] = b
+			// Resolve the output format (--json takes precedence)
+			if jsonOutput {
+				format = output.FormatJSON
 			}
-
-			// Ready filter: unblocked tasks in todo/backlog
-			if ready {
-				columns = []string{"todo", "backlog"}
-				notBlocked = true
+			listFormat, listTemplate, err := resolveListFormat(format)
+			if err != nil {
+				return out.Error(ExitValidation, err.Error(), nil)
 			}
-
-			// Need input filter: tasks awaiting human input
-			if needInput {
-				filters = append(filters, dbx.NewExp("column = 'need_input'"))
+			if groupBy != "" && !containsString(validListGroups, groupBy) {
+				return out.Error(ExitValidation,
+					fmt.Sprintf("invalid --group-by %q: must be one of %s", groupBy, strings.Join(validListGroups, ", ")), nil)
 			}
 
-			// Column filter
-			if len(columns) > 0 {
-				for _, col := range columns {
-					if !isValidColumn(col) {
-						return out.Error(ExitValidation,
-							fmt.Sprintf("invalid column '%s', must be one of: %v", col, ValidColumns), nil)
-					}
-				}
-				filters = append(filters, buildInFilter("column", columns))
+			opts := listOptions{
+				Columns:         columns,
+				Types:           types,
+				Priorities:      priorities,
+				Search:          search,
+				CreatedBy:       createdBy,
+				Agent:           agentName,
+				Ready:           ready,
+				Blocked:         isBlocked,
+				NotBlocked:      notBlocked,
+				Epic:            epicFilter,
+				Labels:          labels,
+				Limit:           limit,
+				Sort:            sort,
+				Board:           boardRef,
+				DueBefore:       dueBefore,
+				DueAfter:        dueAfter,
+				HasDue:          hasDue,
+				NoDue:           noDue,
+				HasParent:       hasParent,
+				NoParent:        noParent,
+				NeedInput:       needInput,
+				Sprint:          sprintRef,
+				Assignee:        assignee,
+				IncludeArchived: includeArchived,
+				ArchivedOnly:    archivedOnly,
+				View:            viewRef,
+				Query:           queryText,
 			}
 
-			// Type filter
-			if len(types) > 0 {
-				for _, t := range types {
-					if !isValidType(t) {
-						return out.Error(ExitValidation,
-							fmt.Sprintf("invalid type '%s', must be one of: %v", t, ValidTypes), nil)
-					}
+			// Board filter: the default board from the project config,
+			// unless --all-boards is set
+			if allBoards {
+				opts.Board = ""
+			} else if opts.Board == "" {
+				if cfg, _ := config.LoadProjectConfig(); cfg != nil {
+					opts.Board = cfg.DefaultBoard
 				}
-				filters = append(filters, buildInFilter("type", types))
 			}
 
-			// Priority filter
-			if len(priorities) > 0 {
-				for _, p := range priorities {
-					if !isValidPriority(p) {
-						return out.Error(ExitValidation,
-							fmt.Sprintf("invalid priority '%s', must be one of: %v", p, ValidPriorities), nil)
-					}
+			// Assignee filter ("me" expands to the current user)
+			if opts.Assignee != "" {
+				opts.Assignee = resolveAssigneeName(opts.Assignee)
+				if opts.Assignee == "" {
+					return out.ErrorWithSuggestion(ExitValidation, "cannot determine who 'me' is",
+						"Set defaults.author in ~/.config/egenskriven/config.json", nil)
 				}
-				filters = append(filters, buildInFilter("priority", priorities))
 			}
 
-			// Search filter
-			if search != "" {
-				filters = append(filters, dbx.NewExp(
-					"LOWER(title) LIKE {:search} ESCAPE '\\'",
-					dbx.Params{"search": "%" + escapeLikePattern(strings.ToLower(search)) + "%"},
-				))
-			}
-
-			// Created by filter
-			if createdBy != "" {
-				filters = append(filters, dbx.NewExp(
-					"created_by = {:created_by}",
-					dbx.Params{"created_by": createdBy},
-				))
-			}
-
-			// Agent name filter
-			if agentName != "" {
-				filters = append(filters, dbx.NewExp(
-					"created_by_agent = {:agent}",
-					dbx.Params{"agent": agentName},
-				))
-			}
-
-			// Is blocked filter
-			if isBlocked {
-				filters = append(filters, dbx.NewExp(
-					"json_array_length(blocked_by) > 0",
-				))
-			}
-
-			// Not blocked filter
-			if notBlocked {
-				filters = append(filters, dbx.Or(
-					dbx.NewExp("blocked_by IS NULL"),
-					dbx.NewExp("blocked_by = '[]'"),
-					dbx.NewExp("json_array_length(blocked_by) = 0"),
-				))
-			}
-
-			// Epic filter
-			if epicFilter != "" {
-				epicRecord, err := resolveEpic(app, epicFilter)
+			var tasks []*core.Record
+			if isRemoteMode() {
+				tasks, err = remoteClient().ListTasks(opts)
 				if err != nil {
-					return out.Error(ExitValidation, fmt.Sprintf("invalid epic filter: %v", err), nil)
+					return remoteFailed(out, err)
 				}
-				filters = append(filters, dbx.NewExp(
-					"epic = {:epic}",
-					dbx.Params{"epic": epicRecord.Id},
-				))
+			} else if tasks, err = findListTasks(app, opts); err != nil {
+				return commandFailed(out, err)
 			}
 
-			// Label filter
-			if len(labels) > 0 {
-				for _, label := range labels {
-					filters = append(filters, dbx.NewExp(
-						"labels LIKE {:label}",
-						dbx.Params{"label": "%" + label + "%"},
-					))
-				}
-			}
-
-			// Validate mutually exclusive due date flags
-			if hasDue && noDue {
-				return out.Error(ExitValidation,
-					"--has-due and --no-due are mutually exclusive", nil)
+			// Load boards for display ID mapping
+			boardsMap, err := boardsByID(app)
+			if err != nil {
+				return commandFailed(out, err)
 			}
 
-			// Due date filters
-			if dueBefore != "" {
-				date, err := parseDate(dueBefore)
-				if err != nil {
-					return out.Error(ExitValidation, fmt.Sprintf("invalid --due-before date: %v", err), nil)
-				}
-				filters = append(filters, dbx.NewExp(
-					"due_date <= {:due_before}",
-					dbx.Params{"due_before": date},
-				))
+			list := output.TaskList{Tasks: tasks, Boards: boardsMap}
+			if fields != "" {
+				list.Fields = strings.Split(fields, ",")
 			}
-
-			if dueAfter != "" {
-				date, err := parseDate(dueAfter)
+			if groupBy != "" {
+				list.GroupBy = groupBy
+				list.Groups, err = groupTasks(app, tasks, groupBy, boardsMap)
 				if err != nil {
-					return out.Error(ExitValidation, fmt.Sprintf("invalid --due-after date: %v", err), nil)
+					return out.Error(ExitGeneralError, err.Error(), nil)
 				}
-				filters = append(filters, dbx.NewExp(
-					"due_date >= {:due_after}",
-					dbx.Params{"due_after": date},
-				))
 			}
 
-			if hasDue {
-				filters = append(filters, dbx.NewExp("due_date != '' AND due_date IS NOT NULL"))
-			}
-
-			if noDue {
-				filters = append(filters, dbx.Or(
-					dbx.NewExp("due_date = ''"),
-					dbx.NewExp("due_date IS NULL"),
-				))
-			}
-
-			// Validate mutually exclusive parent flags
-			if hasParent && noParent {
-				return out.Error(ExitValidation,
-					"--has-parent and --no-parent are mutually exclusive", nil)
-			}
-
-			// Parent filters (for sub-tasks)
-			if hasParent {
-				filters = append(filters, dbx.NewExp("parent != '' AND parent IS NOT NULL"))
-			}
-
-			if noParent {
-				filters = append(filters, dbx.Or(
-					dbx.NewExp("parent = ''"),
-					dbx.NewExp("parent IS NULL"),
-				))
-			}
-
-			// Execute query using RecordQuery for limit/sort support
-			var tasks []*core.Record
-
-			collection, err := app.FindCollectionByNameOrId("tasks")
-			if err != nil {
-				return out.Error(ExitGeneralError, "tasks collection not found", nil)
-			}
-
-			query := app.RecordQuery(collection)
-
-			if len(filters) > 0 {
-				combined := dbx.And(filters...)
-				query = query.AndWhere(combined)
-			}
-
-			// Apply custom sort if specified
-			if sort != "" {
-				// Valid sortable fields
-				validSortFields := map[string]bool{
-					"id": true, "title": true, "type": true, "priority": true,
-					"column": true, "position": true, "created": true, "updated": true,
-					"created_by": true,
-				}
-
-				// Parse sort string (e.g., "-priority,position")
-				sortFields := strings.Split(sort, ",")
-				for _, field := range sortFields {
-					field = strings.TrimSpace(field)
-					if field == "" {
-						continue
-					}
-					fieldName := field
-					if strings.HasPrefix(field, "-") {
-						fieldName = field[1:]
-					}
-					if !validSortFields[fieldName] {
-						return out.ErrorWithSuggestion(ExitValidation,
-							fmt.Sprintf("invalid sort field '%s'", fieldName),
-							fmt.Sprintf("Valid sort fields: id, title, type, priority, column, position, created, updated, created_by"),
-							nil)
-					}
-					if strings.HasPrefix(field, "-") {
-						query = query.OrderBy(fieldName + " DESC")
-					} else {
-						query = query.OrderBy(field + " ASC")
-					}
-				}
+			if listTemplate != nil {
+				err = out.TaskListTemplate(list, listTemplate)
 			} else {
-				query = query.OrderBy("column ASC", "position ASC")
+				err = out.TaskList(list, listFormat)
 			}
-
-			// Apply limit
-			if limit > 0 {
-				query = query.Limit(int64(limit))
-			}
-
-			err = query.All(&tasks)
 			if err != nil {
-				return out.Error(ExitGeneralError, fmt.Sprintf("failed to list tasks: %v", err), nil)
-			}
-
-			// Sort by position within each column (only if no custom sort)
-			if sort == "" {
-				sortTasksByPosition(tasks)
-			}
-
-			// Handle field selection for JSON output
-			if jsonOutput && fields != "" {
-				out.TasksWithFieldsAndBoards(tasks, strings.Split(fields, ","), boardsMap)
-			} else {
-				out.TasksWithBoards(tasks, boardsMap)
+				return out.Error(ExitValidation, fmt.Sprintf("failed to format tasks: %v", err), nil)
 			}
 
 			return nil
@@ -369,7 +240,7 @@ Examples:
 	cmd.Flags().BoolVar(&notBlocked, "not-blocked", false,
 		"Show only tasks not blocked by others")
 	cmd.Flags().StringVar(&fields, "fields", "",
-		"Comma-separated fields to include in JSON output")
+		"Comma-separated fields to include in JSON, NDJSON, YAML, and markdown output")
 	cmd.Flags().StringVarP(&epicFilter, "epic", "e", "",
 		"Filter by epic (ID or title)")
 	cmd.Flags().StringSliceVarP(&labels, "label", "l", nil,
@@ -396,10 +267,394 @@ Examples:
 		"Only show top-level tasks (exclude sub-tasks)")
 	cmd.Flags().BoolVar(&needInput, "need-input", false,
 		"Show only tasks awaiting human input (in need_input column)")
+	cmd.Flags().StringVar(&assignee, "assignee", "",
+		"Filter by assignee (me, <name>, or none)")
+	cmd.Flags().StringVar(&sprintRef, "sprint", "",
+		"Filter by sprint (name, ID, 'current', or 'none')")
+	cmd.Flags().BoolVar(&includeArchived, "include-archived", false,
+		"Include archived tasks")
+	cmd.Flags().BoolVar(&archivedOnly, "archived", false,
+		"Show only archived tasks")
+	cmd.Flags().StringVar(&viewRef, "view", "",
+		"Apply a saved view's filters (name or ID)")
+	cmd.Flags().StringVar(&queryText, "query", "",
+		"Filter with a query, e.g. 'priority:high -label:wontfix due:<7d'")
+	cmd.Flags().StringVar(&groupBy, "group-by", "",
+		"Group tasks by column, priority, epic, label, assignee, board, or due-week")
+	cmd.Flags().StringVarP(&format, "format", "f", "",
+		"Output format: text, json, ndjson, yaml, markdown, a template, or a list_formats name")
 
 	return cmd
 }
 
+// listOptions are the filters of the list command. They are also the
+// body of the list route, so a remote CLI lists tasks like a local one.
+type listOptions struct {
+	Columns    []string `json:"columns,omitempty"`
+	Types      []string `json:"types,omitempty"`
+	Priorities []string `json:"priorities,omitempty"`
+	Search     string   `json:"search,omitempty"`
+	CreatedBy  string   `json:"created_by,omitempty"`
+	Agent      string   `json:"agent,omitempty"`
+	Ready      bool     `json:"ready,omitempty"`
+	Blocked    bool     `json:"blocked,omitempty"`
+	NotBlocked bool     `json:"not_blocked,omitempty"`
+	Epic       string   `json:"epic,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	Limit      int      `json:"limit,omitempty"`
+	Sort       string   `json:"sort,omitempty"`
+	// Board limits the tasks to one board; all boards when empty
+	Board           string `json:"board,omitempty"`
+	DueBefore       string `json:"due_before,omitempty"`
+	DueAfter        string `json:"due_after,omitempty"`
+	HasDue          bool   `json:"has_due,omitempty"`
+	NoDue           bool   `json:"no_due,omitempty"`
+	HasParent       bool   `json:"has_parent,omitempty"`
+	NoParent        bool   `json:"no_parent,omitempty"`
+	NeedInput       bool   `json:"need_input,omitempty"`
+	Sprint          string `json:"sprint,omitempty"`
+	Assignee        string `json:"assignee,omitempty"`
+	IncludeArchived bool   `json:"include_archived,omitempty"`
+	ArchivedOnly    bool   `json:"archived_only,omitempty"`
+	View            string `json:"view,omitempty"`
+	Query           string `json:"query,omitempty"`
+}
+
+// findListTasks returns the tasks matching the filters of the list
+// command, in list order. The assignee "me" must already be expanded.
+// Invalid filters are command errors.
+func findListTasks(app *pocketbase.PocketBase, opts listOptions) ([]*core.Record, error) {
+	// Build filter expressions
+	var filters []dbx.Expression
+
+	// Validate mutually exclusive flags
+	if opts.Blocked && opts.NotBlocked {
+		return nil, newCommandError(ExitValidation,
+			"--is-blocked and --not-blocked are mutually exclusive")
+	}
+
+	// Board filter
+	var filterBoardID string
+	if opts.Board != "" {
+		boardRecord, err := board.GetByNameOrPrefix(app, opts.Board)
+		if err != nil {
+			return nil, newCommandError(ExitValidation, "invalid board: %v", err)
+		}
+		filters = append(filters, dbx.NewExp(
+			"board = {:board}",
+			dbx.Params{"board": boardRecord.Id},
+		))
+		filterBoardID = boardRecord.Id
+	}
+
+	// Saved view: its filters are applied after the query, on the
+	// view's board
+	var savedView *view.View
+	if opts.View != "" {
+		v, err := findListView(app, filterBoardID, opts.View)
+		if err != nil {
+			if errors.Is(err, view.ErrNotFound) {
+				return nil, &commandError{
+					code:       ExitNotFound,
+					message:    fmt.Sprintf("view '%s' not found", opts.View),
+					suggestion: "List views with 'egenskriven view list'",
+				}
+			}
+			return nil, newCommandError(ExitValidation, "%s", err.Error())
+		}
+		if filterBoardID == "" {
+			filters = append(filters, dbx.NewExp(
+				"board = {:board}",
+				dbx.Params{"board": v.Board},
+			))
+			filterBoardID = v.Board
+		}
+		savedView = &v
+	}
+
+	// Query filter
+	if opts.Query != "" {
+		compiled, err := taskquery.ParseAndCompile(app, opts.Query, taskquery.Options{BoardID: filterBoardID})
+		if err != nil {
+			return nil, &commandError{
+				code:       ExitValidation,
+				message:    err.Error(),
+				suggestion: "See 'egenskriven list --help' for the query syntax",
+			}
+		}
+		filters = append(filters, compiled.Expression())
+	}
+
+	// Sprint filter
+	if opts.Sprint != "" {
+		sprintIDs, matchNone, err := resolveSprintFilter(app, filterBoardID, opts.Sprint)
+		if err != nil {
+			return nil, newCommandError(ExitValidation, "invalid sprint filter: %v", err)
+		}
+		if matchNone {
+			filters = append(filters, dbx.Or(
+				dbx.NewExp("sprint = ''"),
+				dbx.NewExp("sprint IS NULL"),
+			))
+		} else {
+			filters = append(filters, buildInFilter("sprint", sprintIDs))
+		}
+	}
+
+	// Assignee filter ("me" is expanded by the caller)
+	if opts.Assignee != "" {
+		filters = append(filters, buildAssigneeFilter(opts.Assignee))
+	}
+
+	// Ready filter: unblocked tasks in todo/backlog
+	columns, notBlocked := opts.Columns, opts.NotBlocked
+	if opts.Ready {
+		columns = []string{"todo", "backlog"}
+		notBlocked = true
+	}
+
+	// Need input filter: tasks awaiting human input
+	if opts.NeedInput {
+		filters = append(filters, dbx.NewExp("column = 'need_input'"))
+	}
+
+	// Column filter
+	if len(columns) > 0 {
+		for _, col := range columns {
+			if !isValidColumn(col) {
+				return nil, newCommandError(ExitValidation,
+					"invalid column '%s', must be one of: %v", col, ValidColumns)
+			}
+		}
+		filters = append(filters, buildInFilter("column", columns))
+	}
+
+	// Type filter
+	if len(opts.Types) > 0 {
+		for _, t := range opts.Types {
+			if !isValidType(t) {
+				return nil, newCommandError(ExitValidation,
+					"invalid type '%s', must be one of: %v", t, ValidTypes)
+			}
+		}
+		filters = append(filters, buildInFilter("type", opts.Types))
+	}
+
+	// Priority filter
+	if len(opts.Priorities) > 0 {
+		for _, p := range opts.Priorities {
+			if !isValidPriority(p) {
+				return nil, newCommandError(ExitValidation,
+					"invalid priority '%s', must be one of: %v", p, ValidPriorities)
+			}
+		}
+		filters = append(filters, buildInFilter("priority", opts.Priorities))
+	}
+
+	// Search filter
+	if opts.Search != "" {
+		filters = append(filters, dbx.NewExp(
+			"LOWER(title) LIKE {:search} ESCAPE '\\'",
+			dbx.Params{"search": "%" + escapeLikePattern(strings.ToLower(opts.Search)) + "%"},
+		))
+	}
+
+	// Created by filter
+	if opts.CreatedBy != "" {
+		filters = append(filters, dbx.NewExp(
+			"created_by = {:created_by}",
+			dbx.Params{"created_by": opts.CreatedBy},
+		))
+	}
+
+	// Agent name filter
+	if opts.Agent != "" {
+		filters = append(filters, dbx.NewExp(
+			"created_by_agent = {:agent}",
+			dbx.Params{"agent": opts.Agent},
+		))
+	}
+
+	// Is blocked filter
+	if opts.Blocked {
+		filters = append(filters, dbx.NewExp(
+			"json_array_length(blocked_by) > 0",
+		))
+	}
+
+	// Not blocked filter
+	if notBlocked {
+		filters = append(filters, dbx.Or(
+			dbx.NewExp("blocked_by IS NULL"),
+			dbx.NewExp("blocked_by = '[]'"),
+			dbx.NewExp("json_array_length(blocked_by) = 0"),
+		))
+	}
+
+	// Epic filter
+	if opts.Epic != "" {
+		epicRecord, err := resolveEpic(app, opts.Epic)
+		if err != nil {
+			return nil, newCommandError(ExitValidation, "invalid epic filter: %v", err)
+		}
+		filters = append(filters, dbx.NewExp(
+			"epic = {:epic}",
+			dbx.Params{"epic": epicRecord.Id},
+		))
+	}
+
+	// Label filter
+	for _, label := range opts.Labels {
+		filters = append(filters, dbx.NewExp(
+			"labels LIKE {:label}",
+			dbx.Params{"label": "%" + label + "%"},
+		))
+	}
+
+	// Validate mutually exclusive due date flags
+	if opts.HasDue && opts.NoDue {
+		return nil, newCommandError(ExitValidation,
+			"--has-due and --no-due are mutually exclusive")
+	}
+
+	// Due date filters
+	if opts.DueBefore != "" {
+		date, err := parseDate(opts.DueBefore)
+		if err != nil {
+			return nil, newCommandError(ExitValidation, "invalid --due-before date: %v", err)
+		}
+		filters = append(filters, dbx.NewExp(
+			"due_date <= {:due_before}",
+			dbx.Params{"due_before": date},
+		))
+	}
+
+	if opts.DueAfter != "" {
+		date, err := parseDate(opts.DueAfter)
+		if err != nil {
+			return nil, newCommandError(ExitValidation, "invalid --due-after date: %v", err)
+		}
+		filters = append(filters, dbx.NewExp(
+			"due_date >= {:due_after}",
+			dbx.Params{"due_after": date},
+		))
+	}
+
+	if opts.HasDue {
+		filters = append(filters, dbx.NewExp("due_date != '' AND due_date IS NOT NULL"))
+	}
+
+	if opts.NoDue {
+		filters = append(filters, dbx.Or(
+			dbx.NewExp("due_date = ''"),
+			dbx.NewExp("due_date IS NULL"),
+		))
+	}
+
+	// Validate mutually exclusive parent flags
+	if opts.HasParent && opts.NoParent {
+		return nil, newCommandError(ExitValidation,
+			"--has-parent and --no-parent are mutually exclusive")
+	}
+
+	// Parent filters (for sub-tasks)
+	if opts.HasParent {
+		filters = append(filters, dbx.NewExp("parent != '' AND parent IS NOT NULL"))
+	}
+
+	if opts.NoParent {
+		filters = append(filters, dbx.Or(
+			dbx.NewExp("parent = ''"),
+			dbx.NewExp("parent IS NULL"),
+		))
+	}
+
+	// Tasks in the trash are never listed
+	filters = append(filters, trash.Exclude(app, "tasks"))
+	filters = append(filters, archive.Filter(app, archive.ModeFor(opts.IncludeArchived, opts.ArchivedOnly)))
+
+	// Execute query using RecordQuery for limit/sort support
+	var tasks []*core.Record
+
+	collection, err := app.FindCollectionByNameOrId("tasks")
+	if err != nil {
+		return nil, fmt.Errorf("tasks collection not found")
+	}
+
+	query := app.RecordQuery(collection)
+
+	if len(filters) > 0 {
+		combined := dbx.And(filters...)
+		query = query.AndWhere(combined)
+	}
+
+	// Apply custom sort if specified
+	if opts.Sort != "" {
+		// Valid sortable fields
+		validSortFields := map[string]bool{
+			"id": true, "title": true, "type": true, "priority": true,
+			"column": true, "position": true, "created": true, "updated": true,
+			"created_by": true,
+		}
+
+		// Parse sort string (e.g., "-priority,position")
+		sortFields := strings.Split(opts.Sort, ",")
+		for _, field := range sortFields {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			fieldName := field
+			if strings.HasPrefix(field, "-") {
+				fieldName = field[1:]
+			}
+			if !validSortFields[fieldName] {
+				return nil, &commandError{
+					code:       ExitValidation,
+					message:    fmt.Sprintf("invalid sort field '%s'", fieldName),
+					suggestion: "Valid sort fields: id, title, type, priority, column, position, created, updated, created_by",
+				}
+			}
+			if strings.HasPrefix(field, "-") {
+				query = query.OrderBy(fieldName + " DESC")
+			} else {
+				query = query.OrderBy(field + " ASC")
+			}
+		}
+	} else {
+		query = query.OrderBy("column ASC", "position ASC")
+	}
+
+	// Apply limit (after the view's filters when listing a view)
+	if opts.Limit > 0 && savedView == nil {
+		query = query.Limit(int64(opts.Limit))
+	}
+
+	if err := query.All(&tasks); err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %v", err)
+	}
+
+	if savedView != nil {
+		matched := tasks[:0]
+		for _, task := range tasks {
+			if savedView.Matches(view.TaskFromRecord(task)) {
+				matched = append(matched, task)
+			}
+		}
+		tasks = matched
+		if opts.Limit > 0 && len(tasks) > opts.Limit {
+			tasks = tasks[:opts.Limit]
+		}
+	}
+
+	// Sort by position within each column (only if no custom sort)
+	if opts.Sort == "" {
+		sortTasksByPosition(tasks)
+	}
+
+	return tasks, nil
+}
+
 // buildInFilter creates a SQL IN expression for a list of values.
 // The field name is included in parameter names to avoid collisions when
 // multiple filters are combined.
@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+)
+
+// validListGroups are the supported --group-by values for list
+var validListGroups = []string{"column", "priority", "epic", "label", "assignee", "board", "due-week"}
+
+// listGroupPriorities orders priority groups from most to least urgent.
+var listGroupPriorities = []string{"urgent", "high", "medium", "low"}
+
+// groupTasks splits tasks into the groups of a --group-by value, keeping
+// the order of the tasks within each group. Columns and priorities are
+// listed in board order, including empty groups; other groups are sorted
+// by name with the group of tasks without a value last. A task with
+// several labels or assignees is in each of their groups.
+func groupTasks(app core.App, tasks []*core.Record, by string, boardsMap map[string]*core.Record) ([]output.TaskGroup, error) {
+	var (
+		fixed  []string
+		keysOf func(task *core.Record) []string
+		nameOf = func(key string) string { return key }
+		none   = "(none)"
+	)
+	field := func(name string) func(*core.Record) []string {
+		return func(task *core.Record) []string {
+			if v := task.GetString(name); v != "" {
+				return []string{v}
+			}
+			return nil
+		}
+	}
+
+	switch by {
+	case "column":
+		fixed = ValidColumns
+		keysOf = field("column")
+	case "priority":
+		fixed = listGroupPriorities
+		keysOf = field("priority")
+	case "epic":
+		keysOf = field("epic")
+		none = "(no epic)"
+		titles, err := epicTitles(app, tasks)
+		if err != nil {
+			return nil, err
+		}
+		nameOf = func(key string) string {
+			if title, ok := titles[key]; ok {
+				return title
+			}
+			return key
+		}
+	case "label":
+		keysOf = func(task *core.Record) []string { return task.GetStringSlice("labels") }
+		none = "(no label)"
+	case "assignee":
+		keysOf = func(task *core.Record) []string { return task.GetStringSlice("assignees") }
+		none = "(unassigned)"
+	case "board":
+		keysOf = field("board")
+		none = "(no board)"
+		nameOf = func(key string) string {
+			if b, ok := boardsMap[key]; ok {
+				return b.GetString("name")
+			}
+			return key
+		}
+	case "due-week":
+		weekStarts := make(map[string]string)
+		keysOf = func(task *core.Record) []string {
+			due := task.GetDateTime("due_date")
+			if due.IsZero() {
+				return nil
+			}
+			t := due.Time().UTC()
+			year, week := t.ISOWeek()
+			key := fmt.Sprintf("%d-W%02d", year, week)
+			monday := t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+			weekStarts[key] = monday.Format("2006-01-02")
+			return []string{key}
+		}
+		none = "(no due date)"
+		nameOf = func(key string) string { return "Week of " + weekStarts[key] }
+	default:
+		return nil, fmt.Errorf("invalid --group-by %q: must be one of %s", by, strings.Join(validListGroups, ", "))
+	}
+
+	groups := make(map[string]*output.TaskGroup)
+	for _, task := range tasks {
+		keys := keysOf(task)
+		if len(keys) == 0 {
+			keys = []string{""}
+		}
+		seen := make(map[string]bool)
+		for _, key := range keys {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			g, ok := groups[key]
+			if !ok {
+				g = &output.TaskGroup{Key: key, Name: none}
+				if key != "" {
+					g.Name = nameOf(key)
+				}
+				groups[key] = g
+			}
+			g.Tasks = append(g.Tasks, task)
+		}
+	}
+
+	var result []output.TaskGroup
+	for _, key := range fixed {
+		if g, ok := groups[key]; ok {
+			result = append(result, *g)
+			delete(groups, key)
+		} else {
+			result = append(result, output.TaskGroup{Key: key, Name: key})
+		}
+	}
+
+	var rest []output.TaskGroup
+	for _, g := range groups {
+		rest = append(rest, *g)
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		a, b := rest[i], rest[j]
+		if (a.Key == "") != (b.Key == "") {
+			return b.Key == ""
+		}
+		if an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name); an != bn {
+			return an < bn
+		}
+		return a.Key < b.Key
+	})
+	return append(result, rest...), nil
+}
+
+// epicTitles returns the titles of the epics of tasks by epic ID.
+func epicTitles(app core.App, tasks []*core.Record) (map[string]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, task := range tasks {
+		if id := task.GetString("epic"); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	titles := make(map[string]string)
+	if len(ids) == 0 {
+		return titles, nil
+	}
+	epics, err := findRecordsByIds(app, "epics", ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load epics: %w", err)
+	}
+	for _, epic := range epics {
+		titles[epic.Id] = epic.GetString("title")
+	}
+	return titles, nil
+}
+
+// resolveListFormat resolves a list --format value: one of the output
+// formats, a template named in the list_formats config, or a template
+// string. It returns the format, or the parsed template for templates.
+func resolveListFormat(format string) (string, *template.Template, error) {
+	if format == "" {
+		return output.FormatText, nil, nil
+	}
+	if name := output.NormalizeListFormat(format); name != "" {
+		return name, nil, nil
+	}
+
+	var named map[string]string
+	if cfg, err := config.Load(); err == nil {
+		named = cfg.ListFormats
+	}
+	if text, ok := named[format]; ok {
+		tmpl, err := output.ParseTaskTemplate(text)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid list format %q in config: %w", format, err)
+		}
+		return "", tmpl, nil
+	}
+
+	if strings.Contains(format, "{{") {
+		tmpl, err := output.ParseTaskTemplate(format)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid --format template: %w", err)
+		}
+		return "", tmpl, nil
+	}
+
+	known := output.ListFormats()
+	for name := range named {
+		known = append(known, name)
+	}
+	sort.Strings(known[len(output.ListFormats()):])
+	return "", nil, fmt.Errorf("unknown format %q: use %s, or a template such as '{{.display_id}} {{.title}}'",
+		format, strings.Join(known, ", "))
+}
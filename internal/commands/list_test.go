@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/pocketbase/pocketbase"
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
 	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
 )
 
@@ -88,7 +90,6 @@ func TestListCommand_NeedInputWorksWithOtherFilters(t *testing.T) {
 	bugTask.Set("labels", []string{})
 	bugTask.Set("blocked_by", []string{})
 	bugTask.Set("created_by", "cli")
-	bugTask.Set("history", []map[string]any{})
 	require.NoError(t, app.Save(bugTask))
 
 	// Create a feature task in need_input
@@ -101,7 +102,6 @@ func TestListCommand_NeedInputWorksWithOtherFilters(t *testing.T) {
 	featureTask.Set("labels", []string{})
 	featureTask.Set("blocked_by", []string{})
 	featureTask.Set("created_by", "cli")
-	featureTask.Set("history", []map[string]any{})
 	require.NoError(t, app.Save(featureTask))
 
 	// Create a bug task NOT in need_input
@@ -114,7 +114,6 @@ func TestListCommand_NeedInputWorksWithOtherFilters(t *testing.T) {
 	bugNotBlocked.Set("labels", []string{})
 	bugNotBlocked.Set("blocked_by", []string{})
 	bugNotBlocked.Set("created_by", "cli")
-	bugNotBlocked.Set("history", []map[string]any{})
 	require.NoError(t, app.Save(bugNotBlocked))
 
 	// Query combining --need-input with --type bug
@@ -220,7 +219,6 @@ func TestListCommand_NeedInputWithPriorityFilter(t *testing.T) {
 	urgentTask.Set("labels", []string{})
 	urgentTask.Set("blocked_by", []string{})
 	urgentTask.Set("created_by", "cli")
-	urgentTask.Set("history", []map[string]any{})
 	require.NoError(t, app.Save(urgentTask))
 
 	// Create low priority task in need_input
@@ -233,7 +231,6 @@ func TestListCommand_NeedInputWithPriorityFilter(t *testing.T) {
 	lowTask.Set("labels", []string{})
 	lowTask.Set("blocked_by", []string{})
 	lowTask.Set("created_by", "cli")
-	lowTask.Set("history", []map[string]any{})
 	require.NoError(t, app.Save(lowTask))
 
 	// Query combining --need-input with --priority urgent
@@ -289,7 +286,6 @@ func TestBuildInFilter_NoParameterCollision(t *testing.T) {
 	task1.Set("labels", []string{})
 	task1.Set("blocked_by", []string{})
 	task1.Set("created_by", "cli")
-	task1.Set("history", []map[string]any{})
 	require.NoError(t, app.Save(task1))
 
 	task2 := core.NewRecord(collection)
@@ -301,7 +297,6 @@ func TestBuildInFilter_NoParameterCollision(t *testing.T) {
 	task2.Set("labels", []string{})
 	task2.Set("blocked_by", []string{})
 	task2.Set("created_by", "cli")
-	task2.Set("history", []map[string]any{})
 	require.NoError(t, app.Save(task2))
 
 	task3 := core.NewRecord(collection)
@@ -313,7 +308,6 @@ func TestBuildInFilter_NoParameterCollision(t *testing.T) {
 	task3.Set("labels", []string{})
 	task3.Set("blocked_by", []string{})
 	task3.Set("created_by", "cli")
-	task3.Set("history", []map[string]any{})
 	require.NoError(t, app.Save(task3))
 
 	// Build filters for both column and priority - this would have collided before the fix
@@ -374,7 +368,6 @@ func TestBuildInFilter_MultipleFiltersWithMultipleValues(t *testing.T) {
 		record.Set("labels", []string{})
 		record.Set("blocked_by", []string{})
 		record.Set("created_by", "cli")
-		record.Set("history", []map[string]any{})
 		require.NoError(t, app.Save(record))
 		taskRecords[i] = record
 	}
@@ -394,3 +387,94 @@ func TestBuildInFilter_MultipleFiltersWithMultipleValues(t *testing.T) {
 	// - Bug high review (matches all)
 	assert.Len(t, records, 3, "should find tasks matching all three filter criteria")
 }
+
+// ========== --group-by Tests ==========
+
+// groupNames returns "name:count" for each group
+func groupNames(groups []output.TaskGroup) []string {
+	var names []string
+	for _, g := range groups {
+		names = append(names, fmt.Sprintf("%s:%d", g.Name, len(g.Tasks)))
+	}
+	return names
+}
+
+func TestGroupTasks(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	epics := testutil.CreateTestCollection(t, app, "epics",
+		&core.TextField{Name: "title"},
+	)
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "column"},
+		&core.TextField{Name: "priority"},
+		&core.TextField{Name: "epic"},
+		&core.TextField{Name: "board"},
+		&core.JSONField{Name: "labels"},
+		&core.JSONField{Name: "assignees"},
+		&core.DateField{Name: "due_date"},
+	)
+
+	epic := core.NewRecord(epics)
+	epic.Set("title", "Launch")
+	require.NoError(t, app.Save(epic))
+
+	newTask := func(fields map[string]any) *core.Record {
+		record := core.NewRecord(tasks)
+		record.Set("column", "todo")
+		record.Set("priority", "medium")
+		for k, v := range fields {
+			record.Set(k, v)
+		}
+		require.NoError(t, app.Save(record))
+		return record
+	}
+	records := []*core.Record{
+		newTask(map[string]any{"priority": "urgent", "epic": epic.Id, "labels": []string{"ui", "api", "ui"}, "due_date": "2026-03-11"}),
+		newTask(map[string]any{"column": "done", "labels": []string{"Backend"}, "assignees": []string{"alice"}, "due_date": "2026-03-15"}),
+		newTask(map[string]any{"due_date": "2026-03-16"}),
+	}
+
+	tests := []struct {
+		by   string
+		want []string
+	}{
+		{"column", []string{"backlog:0", "todo:2", "in_progress:0", "need_input:0", "review:0", "done:1"}},
+		{"priority", []string{"urgent:1", "high:0", "medium:2", "low:0"}},
+		{"epic", []string{"Launch:1", "(no epic):2"}},
+		{"label", []string{"api:1", "Backend:1", "ui:1", "(no label):1"}},
+		{"assignee", []string{"alice:1", "(unassigned):2"}},
+		{"board", []string{"(no board):3"}},
+		{"due-week", []string{"Week of 2026-03-09:2", "Week of 2026-03-16:1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			groups, err := groupTasks(app, records, tt.by, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, groupNames(groups))
+		})
+	}
+
+	groups, err := groupTasks(app, records, "due-week", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-W11", groups[0].Key)
+
+	_, err = groupTasks(app, records, "type", nil)
+	assert.Error(t, err)
+}
+
+func TestResolveListFormat(t *testing.T) {
+	format, tmpl, err := resolveListFormat("md")
+	require.NoError(t, err)
+	assert.Equal(t, output.FormatMarkdown, format)
+	assert.Nil(t, tmpl)
+
+	_, tmpl, err = resolveListFormat("{{.display_id}} {{.title}}")
+	require.NoError(t, err)
+	assert.NotNil(t, tmpl)
+
+	_, _, err = resolveListFormat("{{.title")
+	assert.Error(t, err)
+	_, _, err = resolveListFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+)
+
+func newLogCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef string
+		since    string
+		actor    string
+		limit    int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "log [task]",
+		Short: "Show the task event log",
+		Long: `Show recorded task changes, oldest first.
+
+Every create, update, move, and delete of a task is logged, whether it was
+made from the CLI, the TUI, the web UI, or the API. Without a task, events
+of the given board (default: configured default board, else all boards) are
+shown. Events of deleted tasks are kept.
+
+--actor matches either the actor kind (cli, tui, user, agent, api) or the
+specific user or agent name.`,
+		Example: `  egenskriven log WRK-12
+  egenskriven log --board WRK --since 7d
+  egenskriven log --actor claude --since 2025-01-15 --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			q := logQuery{Actor: actor, Limit: limit}
+
+			if since != "" {
+				sinceTime, err := parseSince(since)
+				if err != nil {
+					return out.Error(ExitValidation, err.Error(), nil)
+				}
+				q.Since = sinceTime
+			}
+
+			if len(args) == 1 {
+				q.Task = args[0]
+			} else {
+				// Board filter, falling back to the configured default board
+				if boardRef == "" {
+					cfg, _ := config.LoadProjectConfig()
+					if cfg != nil && cfg.DefaultBoard != "" {
+						boardRef = cfg.DefaultBoard
+					}
+				}
+				q.Board = boardRef
+			}
+
+			var entries []logEntry
+			var err error
+			if isRemoteMode() {
+				entries, err = remoteClient().Log(q)
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				entries, err = findLog(app, q, "")
+				if err != nil {
+					var ambErr *resolver.AmbiguousError
+					if errors.As(err, &ambErr) {
+						return out.AmbiguousError(q.Task, ambErr.Matches)
+					}
+					return commandFailed(out, err)
+				}
+			}
+
+			if jsonOutput {
+				out.WriteJSON(logResult(entries))
+				return nil
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("No events found.")
+				return nil
+			}
+
+			for _, e := range entries {
+				printEvent(e.Event, e.DisplayID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board to show (default: configured default board, else all)")
+	cmd.Flags().StringVar(&since, "since", "", "Only events after this time (e.g. 7d, 2w, 2025-01-15)")
+	cmd.Flags().StringVar(&actor, "actor", "", "Only events by this actor or agent name")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "Show only the most recent N events")
+
+	return cmd
+}
+
+// ========== Helper Functions ==========
+
+// logQuery holds the filters of the log command, as sent to GET /log.
+type logQuery struct {
+	Task  string    // Task reference; tasks in the trash and purged task IDs match too
+	Board string    // Board reference, when there is no task
+	Actor string    // Actor kind or name
+	Since time.Time // Zero for all events
+	Limit int       // Most recent events only; 0 for all
+}
+
+// logEntry is an event of the log with the display ID of its task.
+type logEntry struct {
+	taskevent.Event
+	DisplayID string `json:"display_id"`
+}
+
+// findLog returns the events matching the filters of the log command,
+// oldest first. boardID restricts them to one board ("" for all boards).
+func findLog(app *pocketbase.PocketBase, q logQuery, boardID string) ([]logEntry, error) {
+	filter := taskevent.Filter{Board: boardID, Actor: q.Actor, Since: q.Since, Limit: q.Limit}
+
+	var task *core.Record
+	if q.Task != "" {
+		var err error
+		task, err = resolver.MustResolveInBoard(app, q.Task, boardID)
+		if err != nil {
+			var ambErr *resolver.AmbiguousError
+			if errors.As(err, &ambErr) {
+				return nil, err
+			}
+			// Tasks in the trash keep their history; purged tasks are
+			// only known by ID
+			task = nil
+			filter.Task = q.Task
+			if trashed, err := resolveTrashed(app, q.Task); err == nil && trashed.Collection().Name == "tasks" &&
+				(boardID == "" || trashed.GetString("board") == boardID) {
+				task = trashed
+				filter.Task = trashed.Id
+			}
+		} else {
+			filter.Task = task.Id
+		}
+	} else if q.Board != "" {
+		boardRecord, err := board.GetByNameOrPrefix(app, q.Board)
+		if err != nil || (boardID != "" && boardRecord.Id != boardID) {
+			return nil, newCommandError(ExitNotFound, "board not found: %s", q.Board)
+		}
+		filter.Board = boardRecord.Id
+	}
+
+	events, err := taskevent.Query(app, filter)
+	if err != nil {
+		return nil, &commandError{
+			code:       ExitGeneralError,
+			message:    fmt.Sprintf("failed to read task events: %v", err),
+			suggestion: "Run 'egenskriven serve' first to initialize the database",
+		}
+	}
+
+	if task == nil && filter.Task != "" && len(events) == 0 {
+		return nil, newCommandError(ExitNotFound, "no task found matching: %s", filter.Task)
+	}
+
+	displayIDs := newDisplayIDCache(app)
+	entries := make([]logEntry, len(events))
+	for i, e := range events {
+		entries[i] = logEntry{Event: e, DisplayID: displayIDs.get(e)}
+	}
+	return entries, nil
+}
+
+// logResult returns the output of 'log --json'.
+func logResult(entries []logEntry) map[string]any {
+	result := make([]map[string]any, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, eventToMap(e.Event, e.DisplayID))
+	}
+	return map[string]any{
+		"events": result,
+		"count":  len(result),
+	}
+}
+
+// displayIDCache resolves display IDs (e.g. WRK-12) for event tasks,
+// falling back to short IDs for deleted tasks.
+type displayIDCache struct {
+	app *pocketbase.PocketBase
+	ids map[string]string
+}
+
+func newDisplayIDCache(app *pocketbase.PocketBase) *displayIDCache {
+	return &displayIDCache{app: app, ids: make(map[string]string)}
+}
+
+func (c *displayIDCache) get(e taskevent.Event) string {
+	if id, ok := c.ids[e.Task]; ok {
+		return id
+	}
+	id := shortID(e.Task)
+	if task, err := c.app.FindRecordById("tasks", e.Task); err == nil {
+		id = getTaskDisplayID(c.app, task)
+	}
+	c.ids[e.Task] = id
+	return id
+}
+
+// eventToMap converts an event to a map for JSON output.
+func eventToMap(e taskevent.Event, displayID string) map[string]any {
+	m := map[string]any{
+		"id":           e.ID,
+		"task":         e.Task,
+		"display_id":   displayID,
+		"board":        e.Board,
+		"action":       e.Action,
+		"actor":        e.Actor,
+		"actor_detail": e.ActorDetail,
+		"changes":      e.Changes,
+		"timestamp":    e.Timestamp.UTC().Format(time.RFC3339),
+	}
+	if len(e.Metadata) > 0 {
+		m["metadata"] = e.Metadata
+	}
+	return m
+}
+
+// printEvent prints one event as a log line.
+func printEvent(e taskevent.Event, displayID string) {
+	fmt.Printf("%s  %-9s %-16s %-12s %s\n",
+		e.Timestamp.Local().Format("2006-01-02 15:04"),
+		displayID,
+		e.Action,
+		truncateString(e.ActorName(), 12),
+		e.Describe(),
+	)
+}
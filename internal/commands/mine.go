@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// AssigneeMe refers to the current user (defaults.author in global config).
+const AssigneeMe = "me"
+
+// AssigneeNone matches tasks without assignees.
+const AssigneeNone = "none"
+
+func newMineCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef    string
+		allBoards   bool
+		includeDone bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mine",
+		Short: "List tasks assigned to you",
+		Long: `List tasks assigned to you, grouped by column.
+
+Your name is taken from defaults.author in the global config
+(~/.config/egenskriven/config.json), falling back to $USER.
+Done tasks are hidden unless --include-done is set.
+
+This is a shortcut for: egenskriven list --assignee me
+
+Examples:
+  egenskriven mine
+  egenskriven mine --all-boards
+  egenskriven mine --include-done --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			me := config.CurrentUser()
+			if me == "" {
+				return out.ErrorWithSuggestion(ExitValidation, "cannot determine who you are",
+					"Set defaults.author in ~/.config/egenskriven/config.json", nil)
+			}
+
+			// Board filter (unless --all-boards is set)
+			var boardID string
+			if !allBoards {
+				if boardRef == "" {
+					if cfg, _ := config.LoadProjectConfig(); cfg != nil {
+						boardRef = cfg.DefaultBoard
+					}
+				}
+				if boardRef != "" {
+					boardRecord, err := findBoard(app, boardRef)
+					if err != nil {
+						return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+					}
+					boardID = boardRecord.Id
+				}
+			}
+
+			tasks, err := findAssignedTasks(app, me, boardID, includeDone)
+			if err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to list tasks: %v", err), nil)
+			}
+			sortTasksByPosition(tasks)
+
+			boardsMap, _ := boardsByID(app)
+
+			if !jsonOutput {
+				fmt.Printf("Tasks assigned to %s (%d)\n", me, len(tasks))
+			}
+			out.TasksWithBoards(tasks, boardsMap)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Filter by board (name or prefix)")
+	cmd.Flags().BoolVar(&allBoards, "all-boards", false, "Show tasks from all boards")
+	cmd.Flags().BoolVar(&includeDone, "include-done", false, "Include tasks in the done column")
+
+	return cmd
+}
+
+// ========== Helper Functions ==========
+
+// findAssignedTasks returns the tasks assigned to name, on one board unless
+// boardID is empty, leaving out done tasks unless includeDone is set.
+func findAssignedTasks(app *pocketbase.PocketBase, name, boardID string, includeDone bool) ([]*core.Record, error) {
+	if isRemoteMode() {
+		// Narrow down with the filter syntax of the API, then match names
+		// exactly
+		filter := "assignees ~ {:assignee}"
+		if !includeDone {
+			filter += ` && column != "done"`
+		}
+		if boardID != "" {
+			filter += " && board = {:board}"
+		}
+		candidates, err := findActiveRecords(app, "tasks", filter, "",
+			dbx.Params{"assignee": name, "board": boardID})
+		if err != nil {
+			return nil, err
+		}
+		var tasks []*core.Record
+		for _, task := range candidates {
+			if hasAssignee(task, name) {
+				tasks = append(tasks, task)
+			}
+		}
+		return tasks, nil
+	}
+
+	filters := []dbx.Expression{buildAssigneeFilter(name)}
+	if !includeDone {
+		filters = append(filters, dbx.NewExp("column != 'done'"))
+	}
+	if boardID != "" {
+		filters = append(filters, dbx.NewExp("board = {:board}", dbx.Params{"board": boardID}))
+	}
+	filters = append(filters, trash.Exclude(app, "tasks"))
+	return app.FindAllRecords("tasks", filters...)
+}
+
+// resolveAssigneeName expands "me" to the current user.
+func resolveAssigneeName(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(name, AssigneeMe) {
+		return config.CurrentUser()
+	}
+	return name
+}
+
+// resolveAssigneeNames expands "me" in a list of assignee names and drops
+// empty entries.
+func resolveAssigneeNames(names []string) []string {
+	result := make([]string, 0, len(names))
+	for _, n := range names {
+		if resolved := resolveAssigneeName(n); resolved != "" {
+			result = append(result, resolved)
+		}
+	}
+	return result
+}
+
+// updateAssignees adds and removes assignees, preserving order.
+// Names are compared case-insensitively.
+func updateAssignees(current, add, remove []string) []string {
+	result := make([]string, 0, len(current)+len(add))
+	for _, a := range current {
+		if !containsFold(remove, a) && !containsFold(result, a) {
+			result = append(result, a)
+		}
+	}
+	for _, a := range add {
+		if !containsFold(result, a) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// hasAssignee reports whether a task is assigned to name.
+func hasAssignee(task interface{ Get(string) any }, name string) bool {
+	return containsFold(getTaskAssignees(task), name)
+}
+
+// buildAssigneeFilter returns a filter matching tasks assigned to name,
+// or tasks without assignees when name is "none".
+func buildAssigneeFilter(name string) dbx.Expression {
+	if strings.EqualFold(name, AssigneeNone) {
+		return dbx.Or(
+			dbx.NewExp("assignees IS NULL"),
+			dbx.NewExp("assignees = ''"),
+			dbx.NewExp("assignees = 'null'"),
+			dbx.NewExp("assignees = '[]'"),
+		)
+	}
+	return dbx.NewExp(
+		"json_valid(assignees) AND EXISTS (SELECT 1 FROM json_each(assignees) WHERE LOWER(json_each.value) = LOWER({:assignee}))",
+		dbx.Params{"assignee": name},
+	)
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+func TestUpdateAssignees(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []string
+		add     []string
+		remove  []string
+		want    []string
+	}{
+		{"assign to empty", nil, []string{"alice"}, nil, []string{"alice"}},
+		{"append preserves order", []string{"alice"}, []string{"bob"}, nil, []string{"alice", "bob"}},
+		{"no duplicates ignoring case", []string{"alice"}, []string{"Alice"}, nil, []string{"alice"}},
+		{"unassign ignoring case", []string{"alice", "bob"}, nil, []string{"ALICE"}, []string{"bob"}},
+		{"unassign all", []string{"alice"}, nil, []string{"alice"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, updateAssignees(tt.current, tt.add, tt.remove))
+		})
+	}
+}
+
+func TestBuildAssigneeFilter(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+
+	alice := CreateTestTask(t, app, "Alice's task", "todo")
+	alice.Set("assignees", []string{"alice"})
+	require.NoError(t, app.Save(alice))
+
+	shared := CreateTestTask(t, app, "Shared task", "todo")
+	shared.Set("assignees", []string{"Bob", "alice"})
+	require.NoError(t, app.Save(shared))
+
+	unassigned := CreateTestTask(t, app, "Unassigned task", "todo")
+
+	cleared := CreateTestTask(t, app, "Cleared task", "todo")
+	cleared.Set("assignees", []string{})
+	require.NoError(t, app.Save(cleared))
+
+	ids := func(name string) []string {
+		records, err := app.FindAllRecords("tasks", buildAssigneeFilter(name))
+		require.NoError(t, err)
+		result := make([]string, 0, len(records))
+		for _, r := range records {
+			result = append(result, r.Id)
+		}
+		return result
+	}
+
+	assert.ElementsMatch(t, []string{alice.Id, shared.Id}, ids("alice"))
+	assert.ElementsMatch(t, []string{shared.Id}, ids("bob"), "matching is case-insensitive")
+	assert.ElementsMatch(t, []string{unassigned.Id, cleared.Id}, ids(AssigneeNone))
+	assert.Empty(t, ids("carol"))
+}
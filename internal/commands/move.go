@@ -2,19 +2,21 @@ package commands
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
 	"github.com/spf13/cobra"
 
 	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
 )
 
 func newMoveCmd(app *pocketbase.PocketBase) *cobra.Command {
 	var (
-		position int
-		afterID  string
-		beforeID string
+		position  int
+		afterID   string
+		beforeID  string
+		agentName string
 	)
 
 	cmd := &cobra.Command{
@@ -39,14 +41,14 @@ Examples:
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
 			taskRef := args[0]
 
 			// Resolve the task
-			task, err := resolver.MustResolve(app, taskRef)
+			task, err := resolveTask(app, taskRef)
 			if err != nil {
 				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 					return out.AmbiguousError(taskRef, ambErr.Matches)
@@ -71,7 +73,7 @@ Examples:
 
 			if afterID != "" {
 				// Resolve the reference task (supports display IDs like TST-4)
-				refTask, err := resolver.MustResolve(app, afterID)
+				refTask, err := resolveTask(app, afterID)
 				if err != nil {
 					if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 						return out.AmbiguousError(afterID, ambErr.Matches)
@@ -94,7 +96,7 @@ Examples:
 				}
 			} else if beforeID != "" {
 				// Resolve the reference task (supports display IDs like TST-4)
-				refTask, err := resolver.MustResolve(app, beforeID)
+				refTask, err := resolveTask(app, beforeID)
 				if err != nil {
 					if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 						return out.AmbiguousError(beforeID, ambErr.Matches)
@@ -127,7 +129,18 @@ Examples:
 					fmt.Sprintf("invalid position %d, use 0 for top or -1 for bottom", position), nil)
 			}
 
-			// Track changes for history
+			// Enforce the agent mode of the board
+			caller := resolveCaller(app, agentName)
+			allowedColumn, err := policyColumn(app, caller, task.GetString("board"), currentColumn, targetColumn)
+			if err != nil {
+				return policyDenied(out, err)
+			}
+			if allowedColumn != targetColumn {
+				targetColumn = allowedColumn
+				newPosition = GetNextPosition(app, targetColumn)
+			}
+
+			// Track changes for the event log
 			oldColumn := currentColumn
 			oldPosition := task.GetFloat("position")
 
@@ -135,8 +148,8 @@ Examples:
 			task.Set("column", targetColumn)
 			task.Set("position", newPosition)
 
-			// Add to history
-			addHistoryEntry(task, "moved", "", map[string]any{
+			// Record the change in the event log
+			addTaskEvent(task, "moved", agentName, map[string]any{
 				"column": map[string]any{
 					"from": oldColumn,
 					"to":   targetColumn,
@@ -168,38 +181,13 @@ Examples:
 		"Position after this task")
 	cmd.Flags().StringVar(&beforeID, "before", "",
 		"Position before this task")
+	cmd.Flags().StringVar(&agentName, "agent", "",
+		"Agent identifier (subject to the board's agent mode)")
 
 	return cmd
 }
 
-// addHistoryEntry appends an entry to the task's history.
-func addHistoryEntry(task interface {
-	Get(string) any
-	Set(string, any)
-}, action, agent string, changes any) {
-	var history []map[string]any
-
-	// Get existing history
-	raw := task.Get("history")
-	if raw != nil {
-		if h, ok := raw.([]any); ok {
-			for _, entry := range h {
-				if m, ok := entry.(map[string]any); ok {
-					history = append(history, m)
-				}
-			}
-		}
-	}
-
-	// Create new entry
-	entry := map[string]any{
-		"timestamp":    time.Now().UTC().Format(time.RFC3339),
-		"action":       action,
-		"actor":        "cli",
-		"actor_detail": agent,
-		"changes":      changes,
-	}
-
-	history = append(history, entry)
-	task.Set("history", history)
+// addTaskEvent queues a task event recorded when the task is saved.
+func addTaskEvent(task *core.Record, action, agent string, changes any) {
+	taskevent.Add(task, action, "cli", agent, changes, nil)
 }
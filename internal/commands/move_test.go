@@ -60,7 +60,6 @@ func setupMoveTestCollections(t *testing.T, app *pocketbase.PocketBase) {
 			Values:   []string{"user", "agent", "cli"},
 		})
 		tasksCollection.Fields.Add(&core.TextField{Name: "created_by_agent"})
-		tasksCollection.Fields.Add(&core.JSONField{Name: "history"})
 		tasksCollection.Fields.Add(&core.TextField{Name: "board"})    // Board reference
 		tasksCollection.Fields.Add(&core.NumberField{Name: "seq"})    // Sequence number
 		require.NoError(t, app.Save(tasksCollection))
@@ -101,7 +100,6 @@ func createMoveTestTask(t *testing.T, app *pocketbase.PocketBase, title, column
 	record.Set("labels", []string{})
 	record.Set("blocked_by", []string{})
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 	record.Set("board", boardID)
 	record.Set("seq", seq)
 
@@ -266,3 +264,46 @@ func TestMoveCommand_PositionCalculation(t *testing.T) {
 	// Keep task2 reference
 	_ = task2
 }
+
+// TestAddTaskEvent_LoggedOnce verifies that queued events are logged when
+// the task is saved, and not again on later saves.
+func TestAddTaskEvent_LoggedOnce(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+	collection, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+
+	task := core.NewRecord(collection)
+	task.Set("title", "Event task")
+	task.Set("type", "feature")
+	task.Set("priority", "medium")
+	task.Set("column", "todo")
+	task.Set("position", 1000.0)
+	task.Set("created_by", "user")
+	addTaskEvent(task, "created", "", nil)
+	require.NoError(t, app.Save(task))
+
+	reloaded, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	reloaded.Set("column", "in_progress")
+	addTaskEvent(reloaded, "moved", "claude", map[string]any{
+		"column": map[string]any{"from": "todo", "to": "in_progress"},
+	})
+	require.NoError(t, app.Save(reloaded))
+
+	reloaded, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	reloaded.Set("description", "Details")
+	require.NoError(t, app.Save(reloaded))
+
+	events := GetTaskEvents(t, app, task.Id)
+	require.Len(t, events, 3)
+	assert.Equal(t, "created", events[0].Action)
+	assert.Equal(t, "moved", events[1].Action)
+	assert.Equal(t, "cli", events[1].Actor)
+	assert.Equal(t, "claude", events[1].ActorDetail)
+	assert.Equal(t, "updated", events[2].Action, "later saves log their own diff")
+	assert.Equal(t, map[string]any{
+		"description": map[string]any{"from": "", "to": "Details"},
+	}, events[2].Changes)
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"errors"
+	"os"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+)
+
+// AgentEnvVar names the agent running the CLI when --agent is not given.
+const AgentEnvVar = "EGENSKRIVEN_AGENT"
+
+// resolveCaller identifies who runs a mutating command.
+// Priority: --agent flag > $EGENSKRIVEN_AGENT > API token issued to an
+// agent > agent runtime environment > the current user.
+func resolveCaller(app *pocketbase.PocketBase, agentFlag string) policy.Actor {
+	if agentFlag != "" {
+		return policy.Agent(agentFlag)
+	}
+	if name := os.Getenv(AgentEnvVar); name != "" {
+		return policy.Agent(name)
+	}
+	if token := config.APIToken(); token != "" {
+		if record := tokenAccount(app, token); record != nil {
+			if actor := policy.ActorFromAuth(record); actor.IsAgent() {
+				return actor
+			}
+		}
+	}
+	if isAgentContext() {
+		return policy.Agent(getDefaultAgentName())
+	}
+	return policy.User(config.CurrentUser())
+}
+
+// tokenAccount returns the account of an API token, asking the server in
+// remote mode. It returns nil when the token is unknown.
+func tokenAccount(app *pocketbase.PocketBase, token string) *core.Record {
+	if isRemoteMode() {
+		record, err := remoteClient().AuthRecord()
+		if err != nil {
+			return nil
+		}
+		return record
+	}
+	if !app.IsBootstrapped() {
+		return nil
+	}
+	record, err := app.FindAuthRecordByToken(token)
+	if err != nil {
+		return nil
+	}
+	return record
+}
+
+// policyColumn returns the column a task may be moved into. A completion
+// the caller may not perform is downgraded to the review column with a
+// warning, or rejected when the board has no review column.
+func policyColumn(app *pocketbase.PocketBase, caller policy.Actor, boardID, fromColumn, toColumn string) (string, error) {
+	d := checkPolicy(app, caller, boardID, policy.MoveAction(fromColumn, toColumn))
+	if d.Downgraded() {
+		warnLog("%s is in %s mode and cannot complete tasks; moving to %s instead",
+			caller.Name, d.Mode, d.DowngradeTo)
+		return d.DowngradeTo, nil
+	}
+	return toColumn, d.Err()
+}
+
+// policyDenied outputs a policy violation with its error code.
+func policyDenied(out *output.Formatter, err error) error {
+	var v *policy.Violation
+	if !errors.As(err, &v) {
+		return out.Error(ExitGeneralError, err.Error(), nil)
+	}
+
+	suggestion := `File a proposal for a human to approve: egenskriven propose --rationale "<why>" <command>`
+	if v.Code == policy.CodeReviewDenied {
+		suggestion = "Ask a human to review the proposal"
+	}
+	return out.ErrorWithSuggestion(ExitPolicyDenied, v.Error(), suggestion, v.Data())
+}
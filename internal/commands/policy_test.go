@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+// clearAgentEnv unsets the variables resolveCaller inspects.
+func clearAgentEnv(t *testing.T) {
+	t.Helper()
+	for _, env := range []string{AgentEnvVar, "EGENSKRIVEN_TOKEN", "OPENCODE_SESSION_ID", "CLAUDE_SESSION_ID", "CODEX_THREAD_ID"} {
+		t.Setenv(env, "")
+	}
+}
+
+func TestResolveCaller(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	clearAgentEnv(t)
+
+	assert.False(t, resolveCaller(app, "").IsAgent())
+	assert.Equal(t, policy.Agent("claude"), resolveCaller(app, "claude"))
+
+	t.Setenv(AgentEnvVar, "opencode")
+	assert.Equal(t, policy.Agent("opencode"), resolveCaller(app, ""))
+	assert.Equal(t, policy.Agent("claude"), resolveCaller(app, "claude"), "flag wins over environment")
+}
+
+func TestPolicyColumn(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.JSONField{Name: "columns"},
+		&core.TextField{Name: "agent_mode"},
+	)
+	board := core.NewRecord(boards)
+	board.Set("name", "Work")
+	board.Set("agent_mode", policy.ModeCollaborative)
+	require.NoError(t, app.Save(board))
+
+	agent := policy.Agent("claude")
+
+	column, err := policyColumn(app, agent, board.Id, "in_progress", "done")
+	require.NoError(t, err)
+	assert.Equal(t, "review", column, "completion is downgraded to review")
+
+	column, err = policyColumn(app, agent, board.Id, "todo", "in_progress")
+	require.NoError(t, err)
+	assert.Equal(t, "in_progress", column)
+
+	column, err = policyColumn(app, policy.User("ramtin"), board.Id, "review", "done")
+	require.NoError(t, err)
+	assert.Equal(t, "done", column, "humans are not restricted")
+
+	board.Set("agent_mode", policy.ModeSupervised)
+	require.NoError(t, app.Save(board))
+	_, err = policyColumn(app, agent, board.Id, "todo", "in_progress")
+	assert.Error(t, err)
+}
+
+func TestImportPolicy(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	clearAgentEnv(t)
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.JSONField{Name: "columns"},
+		&core.TextField{Name: "agent_mode"},
+	)
+	board := core.NewRecord(boards)
+	board.Set("name", "Work")
+	board.Set("agent_mode", policy.ModeSupervised)
+	require.NoError(t, app.Save(board))
+
+	data := &ExportData{Tasks: []ExportTask{{ID: "task1", Title: "Imported", Board: board.Id}}}
+
+	// Humans may import into any board
+	assert.NoError(t, importPolicy(app, data, "merge"))
+
+	// Agents may not write to a supervised board they import into
+	t.Setenv(AgentEnvVar, "claude")
+	var v *policy.Violation
+	require.ErrorAs(t, importPolicy(app, data, "merge"), &v)
+	assert.Equal(t, policy.CodeReadOnly, v.Code)
+}
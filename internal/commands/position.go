@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 
@@ -20,6 +21,13 @@ const (
 // If the column is empty, returns DefaultPositionGap.
 // Otherwise, returns the last position + DefaultPositionGap.
 func GetNextPosition(app *pocketbase.PocketBase, column string) float64 {
+	if isRemoteMode() {
+		tasks, err := remoteColumnTasks(app, column)
+		if err != nil {
+			return position.DefaultGap
+		}
+		return position.NextIn(tasks)
+	}
 	return position.GetNext(app, column)
 }
 
@@ -33,19 +41,59 @@ func GetPositionBetween(before, after float64) float64 {
 // index 0 = top of column
 // index -1 = bottom of column (same as GetNextPosition)
 func GetPositionAtIndex(app *pocketbase.PocketBase, column string, index int) float64 {
+	if isRemoteMode() {
+		tasks, err := remoteColumnTasks(app, column)
+		if err != nil {
+			return position.DefaultGap
+		}
+		return position.AtIndexIn(tasks, index)
+	}
 	return position.GetAtIndex(app, column, index)
 }
 
 // GetPositionAfter returns a position after a specific task.
 func GetPositionAfter(app *pocketbase.PocketBase, taskID string) (float64, error) {
+	if isRemoteMode() {
+		target, tasks, err := remoteTargetColumn(app, taskID)
+		if err != nil {
+			return 0, err
+		}
+		return position.AfterIn(tasks, target), nil
+	}
 	return position.GetAfter(app, taskID)
 }
 
 // GetPositionBefore returns a position before a specific task.
 func GetPositionBefore(app *pocketbase.PocketBase, taskID string) (float64, error) {
+	if isRemoteMode() {
+		target, tasks, err := remoteTargetColumn(app, taskID)
+		if err != nil {
+			return 0, err
+		}
+		return position.BeforeIn(tasks, target), nil
+	}
 	return position.GetBefore(app, taskID)
 }
 
+// remoteColumnTasks returns the tasks in a column from the server.
+func remoteColumnTasks(app *pocketbase.PocketBase, column string) ([]*core.Record, error) {
+	return findRecordsByFilter(app, "tasks", "column = {:col}", "", 0, dbx.Params{"col": column})
+}
+
+// remoteTargetColumn returns a task and the tasks in its column from the
+// server.
+func remoteTargetColumn(app *pocketbase.PocketBase, taskID string) (*core.Record, []*core.Record, error) {
+	target, err := findRecordByID(app, "tasks", taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks, err := remoteColumnTasks(app, target.GetString("column"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return target, tasks, nil
+}
+
 // sortTasksByPosition sorts tasks by their position field in ascending order.
 // Wrapper for backward compatibility.
 func sortTasksByPosition(tasks []*core.Record) {
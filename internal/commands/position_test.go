@@ -149,7 +149,6 @@ func setupTasksCollection(t *testing.T, app *pocketbase.PocketBase) {
 		Values:   []string{"user", "agent", "cli"},
 	})
 	collection.Fields.Add(&core.TextField{Name: "created_by_agent"})
-	collection.Fields.Add(&core.JSONField{Name: "history"})
 
 	if err := app.Save(collection); err != nil {
 		t.Fatalf("failed to create tasks collection: %v", err)
@@ -173,7 +172,6 @@ func createTestTask(t *testing.T, app *pocketbase.PocketBase, title, taskType, p
 	record.Set("labels", []string{})
 	record.Set("blocked_by", []string{})
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 
 	if err := app.Save(record); err != nil {
 		t.Fatalf("failed to create test task: %v", err)
@@ -0,0 +1,396 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/proposal"
+)
+
+func newProposalsCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proposals",
+		Short: "Review changes proposed by agents",
+		Long: `List, inspect, approve, and reject proposals filed with 'egenskriven propose'.
+
+Approving a proposal applies its change and marks it approved in a single
+transaction. The task event records the reviewer together with the
+proposing agent. Only humans can review proposals.`,
+	}
+
+	cmd.AddCommand(newProposalsListCmd(app))
+	cmd.AddCommand(newProposalsShowCmd(app))
+	cmd.AddCommand(newProposalsApproveCmd(app))
+	cmd.AddCommand(newProposalsRejectCmd(app))
+
+	return cmd
+}
+
+// ========== Proposals List ==========
+
+func newProposalsListCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef string
+		status   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List proposals",
+		Example: `  egenskriven proposals list
+  egenskriven proposals list --status all --board WRK --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if status == "all" {
+				status = ""
+			} else if !containsString(proposal.ValidStatuses, status) {
+				return out.Error(ExitInvalidArguments,
+					fmt.Sprintf("invalid status '%s', must be one of: %v or all", status, proposal.ValidStatuses), nil)
+			}
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			var result map[string]any
+			var err error
+			if isRemoteMode() {
+				result, err = remoteClient().Proposals(boardRef, status)
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				boardID := ""
+				if boardRef != "" {
+					boardRecord, err := board.GetByNameOrPrefix(app, boardRef)
+					if err != nil {
+						return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+					}
+					boardID = boardRecord.Id
+				}
+				result, err = listProposals(app, boardID, status)
+				if err != nil {
+					return commandFailed(out, err)
+				}
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			var proposals []map[string]any
+			if err := resultValue(result, "proposals", &proposals); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to read proposals: %v", err), nil)
+			}
+
+			fmt.Println("PROPOSALS")
+			fmt.Println(strings.Repeat("-", 40))
+
+			if len(proposals) == 0 {
+				if status == proposal.StatusPending {
+					fmt.Println("No pending proposals.")
+				} else {
+					fmt.Println("No proposals found.")
+				}
+				return nil
+			}
+
+			for _, p := range proposals {
+				fmt.Printf("  [%s] %-8s %-40s by %s, %s\n",
+					shortID(resultString(p, "id")),
+					resultString(p, "status"),
+					truncateString(resultString(p, "summary"), 40),
+					resultString(p, "proposed_by"),
+					formatRelativeTime(proposalTime(p, "created")),
+				)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Filter by board name or prefix")
+	cmd.Flags().StringVar(&status, "status", proposal.StatusPending, "Filter by status (pending, approved, rejected, all)")
+
+	return cmd
+}
+
+// ========== Proposals Show ==========
+
+func newProposalsShowCmd(app *pocketbase.PocketBase) *cobra.Command {
+	return &cobra.Command{
+		Use:     "show <proposal>",
+		Short:   "Show a proposal",
+		Example: `  egenskriven proposals show k3x9`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			var result map[string]any
+			if isRemoteMode() {
+				var err error
+				if result, err = remoteClient().ShowProposal(args[0]); err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				record, err := findProposal(app, args[0])
+				if err != nil {
+					return commandFailed(out, err)
+				}
+				result = proposalToMap(record)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			printProposal(result)
+			return nil
+		},
+	}
+}
+
+// ========== Proposals Approve ==========
+
+func newProposalsApproveCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var note string
+
+	cmd := &cobra.Command{
+		Use:   "approve <proposal>",
+		Short: "Approve and apply a proposal",
+		Example: `  egenskriven proposals approve k3x9
+  egenskriven proposals approve k3x9 --note "Thanks, looks right"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			result, err := runProposalReview(app, args[0], note, true)
+			if err != nil {
+				return reviewFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			out.Success(fmt.Sprintf("Approved [%s]: %s", shortID(resultString(result, "id")), resultString(result, "summary")))
+			if displayID := resultString(result, "display_id"); displayID != "" && resultString(result, "action") == proposal.ActionCreate {
+				fmt.Printf("Created: %s [%s]\n", resultString(result, "title"), displayID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&note, "note", "", "Note for the agent")
+
+	return cmd
+}
+
+// ========== Proposals Reject ==========
+
+func newProposalsRejectCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var note string
+
+	cmd := &cobra.Command{
+		Use:     "reject <proposal>",
+		Short:   "Reject a proposal",
+		Example: `  egenskriven proposals reject k3x9 --note "Keep it open until QA signs off"`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			result, err := runProposalReview(app, args[0], note, false)
+			if err != nil {
+				return reviewFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			out.Success(fmt.Sprintf("Rejected [%s]: %s", shortID(resultString(result, "id")), resultString(result, "summary")))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&note, "note", "", "Reason for the agent")
+
+	return cmd
+}
+
+// ========== Helper Functions ==========
+
+// runProposalReview approves or rejects a proposal as the calling human,
+// on the server in remote mode.
+func runProposalReview(app *pocketbase.PocketBase, ref, note string, approve bool) (map[string]any, error) {
+	if isRemoteMode() {
+		action := "reject"
+		if approve {
+			action = "approve"
+		}
+		return remoteClient().ReviewProposal(ref, action, proposalRequest{Note: note})
+	}
+
+	record, err := findProposal(app, ref)
+	if err != nil {
+		return nil, err
+	}
+	// Agents cannot review proposals, including their own
+	caller := resolveCaller(app, "")
+	if err := policy.Check(app, caller, record.GetString("board"), policy.ActionReview).Err(); err != nil {
+		return nil, err
+	}
+	return reviewProposal(app, record, caller.Name, note, approve)
+}
+
+// reviewFailed outputs an approve or reject failure.
+func reviewFailed(out *output.Formatter, err error) error {
+	var v *policy.Violation
+	switch {
+	case errors.As(err, &v):
+		return policyDenied(out, err)
+	case isRemoteMode():
+		return remoteFailed(out, err)
+	default:
+		return commandFailed(out, err)
+	}
+}
+
+// listProposals returns proposals, newest first, as printed by
+// 'proposals list --json'. Empty boardID or status match all.
+func listProposals(app core.App, boardID, status string) (map[string]any, error) {
+	records, err := proposal.List(app, boardID, status)
+	if err != nil {
+		return nil, &commandError{
+			code:       ExitGeneralError,
+			message:    fmt.Sprintf("failed to list proposals: %v", err),
+			suggestion: "Run 'egenskriven serve' first to initialize the database",
+		}
+	}
+	proposals := make([]map[string]any, 0, len(records))
+	for _, r := range records {
+		proposals = append(proposals, proposalToMap(r))
+	}
+	return map[string]any{
+		"proposals": proposals,
+		"count":     len(proposals),
+	}, nil
+}
+
+// findProposal resolves a proposal reference.
+func findProposal(app core.App, ref string) (*core.Record, error) {
+	record, err := proposal.Find(app, ref)
+	if err != nil {
+		if errors.Is(err, proposal.ErrAmbiguous) {
+			return nil, newCommandError(ExitAmbiguous, "%s", err.Error())
+		}
+		return nil, findProposalError(ref)
+	}
+	return record, nil
+}
+
+// findProposalError is the error of a reference matching no proposal.
+func findProposalError(ref string) error {
+	return &commandError{
+		code:       ExitNotFound,
+		message:    fmt.Sprintf("%v: %s", proposal.ErrNotFound, ref),
+		suggestion: "Run 'egenskriven proposals list --status all' to see proposals",
+	}
+}
+
+// reviewProposal approves or rejects a proposal and returns it as printed
+// by 'proposals approve --json', with the display ID, title, and column of
+// the task an approval created or changed.
+func reviewProposal(app *pocketbase.PocketBase, record *core.Record, reviewer, note string, approve bool) (map[string]any, error) {
+	if !approve {
+		if err := proposal.Reject(app, record, reviewer, note); err != nil {
+			return nil, proposalReviewError(err)
+		}
+		return proposalToMap(record), nil
+	}
+
+	task, err := proposal.Approve(app, record, reviewer, note)
+	if err != nil {
+		return nil, proposalReviewError(err)
+	}
+	m := proposalToMap(record)
+	if task != nil {
+		m["display_id"] = getTaskDisplayID(app, task)
+		m["title"] = task.GetString("title")
+		m["column"] = task.GetString("column")
+	}
+	return m, nil
+}
+
+// proposalReviewError describes an approve or reject failure.
+func proposalReviewError(err error) error {
+	switch {
+	case errors.Is(err, proposal.ErrNotPending):
+		return newCommandError(ExitValidation, "%s", err.Error())
+	case errors.Is(err, proposal.ErrTaskGone):
+		return &commandError{
+			code:       ExitNotFound,
+			message:    err.Error(),
+			suggestion: "Reject the proposal: egenskriven proposals reject <proposal>",
+		}
+	default:
+		return err
+	}
+}
+
+// proposalTime parses a time of a proposal as printed with --json.
+func proposalTime(m map[string]any, key string) time.Time {
+	t, _ := time.Parse(time.RFC3339, resultString(m, key))
+	return t
+}
+
+// printProposal prints a proposal in human-readable form.
+func printProposal(m map[string]any) {
+	fmt.Printf("Proposal:  %s\n", resultString(m, "id"))
+	fmt.Printf("Summary:   %s\n", resultString(m, "summary"))
+	fmt.Printf("Status:    %s\n", resultString(m, "status"))
+	fmt.Printf("Proposed:  by %s, %s\n", resultString(m, "proposed_by"),
+		formatRelativeTime(proposalTime(m, "created")))
+	fmt.Printf("Command:   egenskriven %s\n", resultString(m, "command"))
+
+	if rationale := resultString(m, "rationale"); rationale != "" {
+		fmt.Println("\nRationale:")
+		for _, line := range strings.Split(rationale, "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+
+	if status := resultString(m, "status"); status != proposal.StatusPending {
+		fmt.Printf("\nReviewed:  %s by %s, %s\n", status, resultString(m, "reviewed_by"),
+			formatRelativeTime(proposalTime(m, "reviewed_at")))
+		if note := resultString(m, "review_note"); note != "" {
+			fmt.Printf("Note:      %s\n", note)
+		}
+	}
+}
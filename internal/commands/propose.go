@@ -0,0 +1,420 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/proposal"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+)
+
+func newProposeCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		rationale string
+		agentName string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "propose <command> [args...]",
+		Short: "Propose a change for a human to approve",
+		Long: `File a proposal instead of changing a task directly.
+
+Takes an add, update, move, or delete command with its usual arguments
+and stores the intended change as a pending proposal. A human reviews it
+with 'egenskriven proposals' or in the TUI; approving applies the change
+with both the agent and the reviewer recorded in the task event log.
+
+Use this when the board's agent mode does not let you make the change
+yourself (e.g., completing or deleting tasks in collaborative mode).
+Flags for propose itself go before the command.`,
+		Example: `  egenskriven propose --rationale "Tests pass, PR merged" move WRK-12 done
+  egenskriven propose -r "Superseded by WRK-15" delete WRK-9
+  egenskriven propose -r "Found while fixing login" add "Session leaks on logout" --type bug
+  egenskriven propose -r "Blocks release" update WRK-3 --priority urgent`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if strings.TrimSpace(rationale) == "" {
+				return out.Error(ExitInvalidArguments, "--rationale is required: explain why the change is needed", nil)
+			}
+
+			if err := app.Bootstrap(); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			parsed, err := parseProposedCommand(app, args)
+			if err != nil {
+				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+					return out.AmbiguousError(ambErr.Reference, ambErr.Matches)
+				}
+				return out.Error(ExitValidation, err.Error(), nil)
+			}
+
+			if agentName == "" {
+				agentName = parsed.agent
+			}
+			caller := resolveCaller(app, agentName)
+
+			record, err := proposal.Create(app, proposal.Input{
+				BoardID:    parsed.boardID,
+				Change:     parsed.change,
+				Command:    strings.Join(args, " "),
+				Summary:    parsed.summary,
+				Rationale:  rationale,
+				ProposedBy: caller.Name,
+			})
+			if err != nil {
+				return out.ErrorWithSuggestion(ExitGeneralError, err.Error(),
+					"Run 'egenskriven serve' first to initialize the database", nil)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(proposalToMap(record))
+				return nil
+			}
+
+			out.Success(fmt.Sprintf("Proposed: %s [%s]", parsed.summary, shortID(record.Id)))
+			fmt.Println("A human can review it with: egenskriven proposals show " + shortID(record.Id))
+			return nil
+		},
+	}
+
+	// Flags after the command belong to it, not to propose
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringVarP(&rationale, "rationale", "r", "", "Why the change is needed (required)")
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent identifier")
+
+	return cmd
+}
+
+// proposedCommand is a command line parsed into a proposal change.
+type proposedCommand struct {
+	change  proposal.Change
+	boardID string
+	summary string
+	agent   string // --agent given to the inner command
+}
+
+// parseProposedCommand parses an add, update, move, or delete command line
+// using that command's own flags, without running it.
+func parseProposedCommand(app *pocketbase.PocketBase, args []string) (*proposedCommand, error) {
+	name, rest := args[0], args[1:]
+
+	var cmd *cobra.Command
+	switch name {
+	case "add":
+		cmd = newAddCmd(app)
+	case "update":
+		cmd = newUpdateCmd(app)
+	case "move":
+		cmd = newMoveCmd(app)
+	case "delete":
+		cmd = newDeleteCmd(app)
+	default:
+		return nil, fmt.Errorf("cannot propose '%s': use add, update, move, or delete", name)
+	}
+
+	if err := cmd.ParseFlags(rest); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	flags := cmd.Flags()
+	positional := flags.Args()
+	agent, _ := flags.GetString("agent")
+
+	var parsed *proposedCommand
+	var err error
+	switch name {
+	case "add":
+		parsed, err = parseProposedAdd(app, cmd, positional)
+	case "update":
+		parsed, err = parseProposedUpdate(app, cmd, positional)
+	case "move":
+		parsed, err = parseProposedMove(app, cmd, positional)
+	case "delete":
+		parsed, err = parseProposedDelete(app, cmd, positional)
+	}
+	if err != nil {
+		return nil, err
+	}
+	parsed.agent = agent
+	return parsed, nil
+}
+
+func parseProposedAdd(app *pocketbase.PocketBase, cmd *cobra.Command, args []string) (*proposedCommand, error) {
+	flags := cmd.Flags()
+	if len(args) != 1 {
+		return nil, fmt.Errorf("add: expected one title argument")
+	}
+	for _, unsupported := range []string{"stdin", "file", "id", "created-by"} {
+		if flags.Changed(unsupported) {
+			return nil, fmt.Errorf("add: --%s cannot be proposed", unsupported)
+		}
+	}
+
+	title := args[0]
+	taskType, _ := flags.GetString("type")
+	priority, _ := flags.GetString("priority")
+	column, _ := flags.GetString("column")
+	labels, _ := flags.GetStringSlice("label")
+	boardRef, _ := flags.GetString("board")
+
+	if !isValidType(taskType) {
+		return nil, fmt.Errorf("invalid type '%s', must be one of: %v", taskType, ValidTypes)
+	}
+	if !isValidPriority(priority) {
+		return nil, fmt.Errorf("invalid priority '%s', must be one of: %v", priority, ValidPriorities)
+	}
+	if !isValidColumn(column) {
+		return nil, fmt.Errorf("invalid column '%s', must be one of: %v", column, ValidColumns)
+	}
+
+	boardRecord, err := resolveBoard(app, boardRef)
+	if err != nil {
+		return nil, fmt.Errorf("invalid board: %v", err)
+	}
+	if boardRecord == nil {
+		return nil, fmt.Errorf("no board found: create one with 'egenskriven board add'")
+	}
+
+	set := map[string]any{
+		"title":    title,
+		"type":     taskType,
+		"priority": priority,
+		"labels":   labels,
+	}
+
+	if ref, _ := flags.GetString("epic"); ref != "" {
+		epicRecord, err := resolveEpic(app, ref)
+		if err != nil {
+			return nil, fmt.Errorf("invalid epic: %v", err)
+		}
+		set["epic"] = epicRecord.Id
+	}
+	if due, _ := flags.GetString("due"); due != "" {
+		parsedDate, err := parseDate(due)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date: %v", err)
+		}
+		set["due_date"] = parsedDate
+	}
+	if ref, _ := flags.GetString("parent"); ref != "" {
+		parentTask, err := resolver.MustResolve(app, ref)
+		if err != nil {
+			return nil, err
+		}
+		set["parent"] = parentTask.Id
+	}
+	if estimateVal, _ := flags.GetFloat64("estimate"); flags.Changed("estimate") {
+		if estimateVal < 0 {
+			return nil, fmt.Errorf("invalid estimate '%g', must be zero or positive", estimateVal)
+		}
+		set["estimate"] = estimateVal
+	}
+	if assign, _ := flags.GetStringSlice("assign"); len(assign) > 0 {
+		set["assignees"] = updateAssignees(nil, resolveAssigneeNames(assign), nil)
+	}
+
+	return &proposedCommand{
+		change: proposal.Change{
+			Action: proposal.ActionCreate,
+			Column: column,
+			Set:    set,
+		},
+		boardID: boardRecord.Id,
+		summary: fmt.Sprintf("add %q to %s", title, column),
+	}, nil
+}
+
+func parseProposedUpdate(app *pocketbase.PocketBase, cmd *cobra.Command, args []string) (*proposedCommand, error) {
+	flags := cmd.Flags()
+	if len(args) != 1 {
+		return nil, fmt.Errorf("update: expected one task reference")
+	}
+	task, err := resolver.MustResolve(app, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	change := proposal.Change{Action: proposal.ActionUpdate, Task: task.Id, Set: map[string]any{}}
+	var fields []string
+
+	if flags.Changed("title") {
+		title, _ := flags.GetString("title")
+		if title == "" {
+			return nil, fmt.Errorf("title cannot be empty")
+		}
+		change.Set["title"] = title
+		fields = append(fields, "title")
+	}
+	if flags.Changed("description") {
+		description, _ := flags.GetString("description")
+		change.Set["description"] = description
+		fields = append(fields, "description")
+	}
+	if flags.Changed("type") {
+		taskType, _ := flags.GetString("type")
+		if !isValidType(taskType) {
+			return nil, fmt.Errorf("invalid type '%s', must be one of: %v", taskType, ValidTypes)
+		}
+		change.Set["type"] = taskType
+		fields = append(fields, "type")
+	}
+	if flags.Changed("priority") {
+		priority, _ := flags.GetString("priority")
+		if !isValidPriority(priority) {
+			return nil, fmt.Errorf("invalid priority '%s', must be one of: %v", priority, ValidPriorities)
+		}
+		change.Set["priority"] = priority
+		fields = append(fields, "priority")
+	}
+	if flags.Changed("estimate") {
+		estimateVal, _ := flags.GetFloat64("estimate")
+		if estimateVal < 0 {
+			return nil, fmt.Errorf("invalid estimate '%g', must be zero or positive", estimateVal)
+		}
+		change.Set["estimate"] = estimateVal
+		fields = append(fields, "estimate")
+	}
+
+	change.AddLabels, _ = flags.GetStringSlice("add-label")
+	change.RemoveLabels, _ = flags.GetStringSlice("remove-label")
+	if len(change.AddLabels) > 0 || len(change.RemoveLabels) > 0 {
+		fields = append(fields, "labels")
+	}
+
+	assign, _ := flags.GetStringSlice("assign")
+	unassign, _ := flags.GetStringSlice("unassign")
+	change.Assign = resolveAssigneeNames(assign)
+	change.Unassign = resolveAssigneeNames(unassign)
+	if len(change.Assign) > 0 || len(change.Unassign) > 0 {
+		fields = append(fields, "assignees")
+	}
+
+	blockedBy, _ := flags.GetStringSlice("blocked-by")
+	removeBlockedBy, _ := flags.GetStringSlice("remove-blocked-by")
+	for _, ref := range blockedBy {
+		blockingTask, err := resolver.MustResolve(app, ref)
+		if err != nil {
+			return nil, err
+		}
+		if blockingTask.Id == task.Id {
+			return nil, fmt.Errorf("task cannot block itself")
+		}
+		if hasCircularDependency(app, task.Id, blockingTask) {
+			return nil, fmt.Errorf("circular dependency detected: %s is already blocked by %s (directly or indirectly)",
+				shortID(blockingTask.Id), shortID(task.Id))
+		}
+		change.AddBlockedBy = append(change.AddBlockedBy, blockingTask.Id)
+	}
+	for _, ref := range removeBlockedBy {
+		blockingTask, err := resolver.MustResolve(app, ref)
+		if err != nil {
+			return nil, err
+		}
+		change.RemoveBlockedBy = append(change.RemoveBlockedBy, blockingTask.Id)
+	}
+	if len(change.AddBlockedBy) > 0 || len(change.RemoveBlockedBy) > 0 {
+		fields = append(fields, "blocked_by")
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("update: no changes specified")
+	}
+
+	return &proposedCommand{
+		change:  change,
+		boardID: task.GetString("board"),
+		summary: fmt.Sprintf("update %s (%s)", getTaskDisplayID(app, task), strings.Join(fields, ", ")),
+	}, nil
+}
+
+func parseProposedMove(app *pocketbase.PocketBase, cmd *cobra.Command, args []string) (*proposedCommand, error) {
+	flags := cmd.Flags()
+	if len(args) != 2 {
+		return nil, fmt.Errorf("move: expected a task reference and a column")
+	}
+	for _, unsupported := range []string{"position", "after", "before"} {
+		if flags.Changed(unsupported) {
+			return nil, fmt.Errorf("move: --%s cannot be proposed", unsupported)
+		}
+	}
+
+	task, err := resolver.MustResolve(app, args[0])
+	if err != nil {
+		return nil, err
+	}
+	column := args[1]
+	if !isValidColumn(column) {
+		return nil, fmt.Errorf("invalid column '%s', must be one of: %v", column, ValidColumns)
+	}
+	if column == task.GetString("column") {
+		return nil, fmt.Errorf("task is already in %s", column)
+	}
+
+	return &proposedCommand{
+		change: proposal.Change{
+			Action: proposal.ActionMove,
+			Task:   task.Id,
+			Column: column,
+		},
+		boardID: task.GetString("board"),
+		summary: fmt.Sprintf("move %s to %s", getTaskDisplayID(app, task), column),
+	}, nil
+}
+
+func parseProposedDelete(app *pocketbase.PocketBase, cmd *cobra.Command, args []string) (*proposedCommand, error) {
+	if cmd.Flags().Changed("stdin") {
+		return nil, fmt.Errorf("delete: --stdin cannot be proposed")
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("delete: propose one task at a time")
+	}
+
+	task, err := resolver.MustResolve(app, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &proposedCommand{
+		change: proposal.Change{
+			Action: proposal.ActionDelete,
+			Task:   task.Id,
+		},
+		boardID: task.GetString("board"),
+		summary: fmt.Sprintf("delete %s %q", getTaskDisplayID(app, task), task.GetString("title")),
+	}, nil
+}
+
+// proposalToMap converts a proposal record to a map for JSON output.
+func proposalToMap(record *core.Record) map[string]any {
+	var changes any
+	if change, err := proposal.DecodeChange(record); err == nil {
+		changes = change
+	}
+
+	m := map[string]any{
+		"id":          record.Id,
+		"board":       record.GetString("board"),
+		"task":        record.GetString("task"),
+		"action":      record.GetString("action"),
+		"summary":     record.GetString("summary"),
+		"command":     record.GetString("command"),
+		"rationale":   record.GetString("rationale"),
+		"status":      record.GetString("status"),
+		"proposed_by": record.GetString("proposed_by"),
+		"changes":     changes,
+		"created":     record.GetDateTime("created").Time().Format(time.RFC3339),
+	}
+	if record.GetString("status") != proposal.StatusPending {
+		m["reviewed_by"] = record.GetString("reviewed_by")
+		m["review_note"] = record.GetString("review_note")
+		m["reviewed_at"] = record.GetDateTime("reviewed_at").Time().Format(time.RFC3339)
+	}
+	return m
+}
@@ -0,0 +1,527 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// Remote mode runs the commands against a shared server (--server URL, or
+// server.mode "remote" in the config) instead of the local database, so a
+// team can use one server from several machines. Every read and write goes
+// through the HTTP API and there is never a fallback to a local database;
+// commands that need one are rejected.
+//
+// The helpers below take the app and use its database in the default
+// (hybrid) mode, and the API of the server in remote mode.
+
+var (
+	serverURL  string // --server
+	remoteMode bool   // set once the command line is parsed
+
+	// remoteAPIClient is the API client of remote mode, created on first use
+	remoteAPIClient *APIClient
+)
+
+// remoteCommands are the commands that work in remote mode. Subcommands
+// are allowed with their parent.
+var remoteCommands = map[string]bool{
+	"add": true, "list": true, "show": true, "mine": true, "move": true,
+	"update": true, "delete": true, "comment": true, "comments": true,
+	"block": true, "resume": true, "session": true, "suggest": true,
+	"context": true, "epic": true, "check": true, "link": true,
+	"unlink": true, "log": true, "time": true, "sprint": true, "view": true,
+	"archive": true, "unarchive": true, "trash": true, "undo": true, "redo": true,
+	"template": true, "proposals": true, "attach": true, "attachments": true,
+	"report": true, "export": true,
+}
+
+// localCommands never use the task database and run in either mode.
+var localCommands = map[string]bool{
+	"help": true, "version": true, "completion": true, "self-upgrade": true,
+	"config": true, "skill": true, "init": true, "prime": true,
+}
+
+// serverCommands run the server itself, always on the local database. The
+// server.mode of the config does not apply to them.
+var serverCommands = map[string]bool{"serve": true, "superuser": true, "migrate": true}
+
+// RemoteMode reports whether a command line runs in remote mode, in which
+// the app must not be bootstrapped: with --server, or with server.mode
+// "remote" in the config, for any command but those running the server.
+func RemoteMode(app *pocketbase.PocketBase, args []string) bool {
+	cmd, _, err := app.RootCmd.Find(args)
+	if err == nil && cmd != app.RootCmd {
+		if serverCommands[topCommand(cmd)] {
+			return false
+		}
+	} else {
+		// serve and superuser are only added by app.Start
+		for _, arg := range args {
+			if serverCommands[arg] {
+				return false
+			}
+		}
+	}
+
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--server" || strings.HasPrefix(arg, "--server=") {
+			return true
+		}
+	}
+	return configRemoteMode()
+}
+
+// configRemoteMode reports whether the config selects remote mode.
+func configRemoteMode() bool {
+	cfg, err := config.Load()
+	return err == nil && cfg.Server.Mode == config.ServerModeRemote
+}
+
+// setupRemoteMode decides the mode of a command before it runs. In remote
+// mode the command must support it, and the server must be reachable.
+func setupRemoteMode(cmd *cobra.Command) {
+	name := topCommand(cmd)
+	serverFlag := cmd.Flags().Lookup("server")
+	flagSet := serverFlag != nil && serverFlag.Changed
+
+	if serverCommands[name] {
+		if flagSet {
+			out := getFormatter()
+			out.Error(ExitInvalidArguments, fmt.Sprintf("--server cannot be used with '%s'", name), nil)
+		}
+		return
+	}
+
+	remoteMode = flagSet || configRemoteMode()
+	if !remoteMode || localCommands[name] {
+		return
+	}
+
+	out := getFormatter()
+	if flagSet && strings.TrimSpace(serverURL) == "" {
+		out.Error(ExitInvalidArguments, "--server requires the URL of the server", nil)
+		return
+	}
+	if isDirectMode() {
+		out.Error(ExitInvalidArguments, "--direct cannot be used in remote mode", nil)
+		return
+	}
+	if !remoteCommands[name] {
+		out.ErrorWithSuggestion(ExitValidation,
+			fmt.Sprintf("'%s' is not available in remote mode", strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")),
+			"Run it on the server machine, or without --server and server.mode \"remote\"", nil)
+		return
+	}
+
+	c := remoteClient()
+	if err := c.do("GET", "/api/health", nil, nil); err != nil {
+		out.Error(ExitGeneralError, fmt.Sprintf("cannot reach the server at %s: %v", c.baseURL, err), nil)
+		return
+	}
+	verboseLog("Remote mode, using the server at %s", c.baseURL)
+}
+
+// topCommand returns the name of the top-level command of cmd.
+func topCommand(cmd *cobra.Command) string {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd.Name()
+}
+
+// isRemoteMode reports whether commands run against a remote server.
+func isRemoteMode() bool {
+	return remoteMode
+}
+
+// remoteClient returns the API client of remote mode, for the --server URL
+// or the configured one.
+func remoteClient() *APIClient {
+	if remoteAPIClient == nil {
+		if url := strings.TrimSpace(serverURL); url != "" {
+			remoteAPIClient = NewAPIClientWithURL(strings.TrimRight(url, "/"))
+		} else {
+			remoteAPIClient = NewAPIClient()
+		}
+	}
+	return remoteAPIClient
+}
+
+// bootstrap opens the local database, except in remote mode.
+func bootstrap(app *pocketbase.PocketBase) error {
+	if isRemoteMode() {
+		return nil
+	}
+	return app.Bootstrap()
+}
+
+// resolveTask resolves a task reference like resolver.MustResolve, on the
+// server in remote mode.
+func resolveTask(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
+	if !isRemoteMode() {
+		return resolver.MustResolve(app, ref)
+	}
+
+	id, err := remoteClient().ResolveTask(ref)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.StatusCode {
+			case http.StatusConflict:
+				return nil, &resolver.AmbiguousError{Reference: ref, Matches: remoteMatches(apiErr)}
+			case http.StatusNotFound:
+				return nil, fmt.Errorf("no task found matching: %s", ref)
+			}
+		}
+		return nil, err
+	}
+	return remoteClient().GetRecord("tasks", id)
+}
+
+// remoteMatches returns the tasks of an ambiguous reference error.
+func remoteMatches(apiErr *APIError) []*core.Record {
+	matches, _ := apiErr.Data["matches"].([]any)
+	records := make([]*core.Record, 0, len(matches))
+	for _, m := range matches {
+		if data, ok := m.(map[string]any); ok {
+			records = append(records, remoteRecord("tasks", data))
+		}
+	}
+	return records
+}
+
+// newRecord returns a new record of a collection.
+func newRecord(app core.App, collection string) (*core.Record, error) {
+	if isRemoteMode() {
+		return core.NewRecord(core.NewBaseCollection(collection)).WithCustomData(true), nil
+	}
+	c, err := app.FindCollectionByNameOrId(collection)
+	if err != nil {
+		return nil, fmt.Errorf("%s collection not found: %w", collection, err)
+	}
+	return core.NewRecord(c), nil
+}
+
+// findRecordByID finds a record by ID.
+func findRecordByID(app core.App, collection, id string) (*core.Record, error) {
+	if isRemoteMode() {
+		return remoteClient().GetRecord(collection, id)
+	}
+	return app.FindRecordById(collection, id)
+}
+
+// findRecordsByIds finds the records with the given IDs.
+func findRecordsByIds(app core.App, collection string, ids []string) ([]*core.Record, error) {
+	if !isRemoteMode() {
+		return app.FindRecordsByIds(collection, ids)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	terms := make([]string, len(ids))
+	for i, id := range ids {
+		terms[i] = "id = " + filterLiteral(id)
+	}
+	return remoteClient().ListRecords(collection, strings.Join(terms, " || "), "", 0)
+}
+
+// findRecordsByFilter finds records with a PocketBase filter, which may
+// hold {:name} placeholders for params, like app.FindRecordsByFilter.
+// limit 0 returns all records.
+func findRecordsByFilter(app core.App, collection, filter, sort string, limit int, params ...dbx.Params) ([]*core.Record, error) {
+	if !isRemoteMode() {
+		return app.FindRecordsByFilter(collection, filter, sort, limit, 0, params...)
+	}
+	return remoteClient().ListRecords(collection, inlineFilterParams(filter, params...), sort, limit)
+}
+
+// findActiveRecords finds the records matching a filter that are not in
+// the trash.
+func findActiveRecords(app core.App, collection, filter, sort string, params ...dbx.Params) ([]*core.Record, error) {
+	records, err := findRecordsByFilter(app, collection, filter, sort, 0, params...)
+	if err != nil {
+		return nil, err
+	}
+	active := records[:0]
+	for _, r := range records {
+		if !trash.IsTrashed(r) {
+			active = append(active, r)
+		}
+	}
+	return active, nil
+}
+
+// saveRecord saves a record, through the API in remote mode: new records
+// are created, others updated with all their fields. Either way the record
+// is reloaded with the values stored by the server, which also logged the
+// task events queued on it.
+func saveRecord(app core.App, record *core.Record) error {
+	if !isRemoteMode() {
+		return app.Save(record)
+	}
+
+	data := record.CustomData()
+	files := make(map[string][]*filesystem.File)
+	for key, value := range data {
+		switch v := value.(type) {
+		case []*filesystem.File:
+			files[key] = v
+			delete(data, key)
+		case *filesystem.File:
+			files[key] = []*filesystem.File{v}
+			delete(data, key)
+		}
+	}
+	delete(data, "created")
+	delete(data, "updated")
+
+	collection := record.Collection().Name
+	var saved *core.Record
+	var err error
+	if record.IsNew() {
+		if record.Id != "" {
+			data["id"] = record.Id
+		}
+		saved, err = remoteClient().CreateRecord(collection, data, files)
+	} else {
+		saved, err = remoteClient().UpdateRecord(collection, record.Id, data, files)
+	}
+	if err != nil {
+		return err
+	}
+
+	record.Id = saved.Id
+	record.Load(saved.CustomData())
+	record.MarkAsNotNew()
+	taskevent.Clear(record)
+	return nil
+}
+
+// runInTransaction runs fn in a transaction. In remote mode the changes
+// are separate requests and fn gets app itself.
+func runInTransaction(app core.App, fn func(txApp core.App) error) error {
+	if isRemoteMode() {
+		return fn(app)
+	}
+	return app.RunInTransaction(fn)
+}
+
+// allBoards returns the boards not in the trash.
+func allBoards(app *pocketbase.PocketBase) ([]*core.Record, error) {
+	if isRemoteMode() {
+		return remoteClient().ListRecords("boards", `deleted_at = ""`, "", 0)
+	}
+	return board.GetAll(app)
+}
+
+// boardsByID returns the boards not in the trash by ID, to format display
+// IDs.
+func boardsByID(app *pocketbase.PocketBase) (map[string]*core.Record, error) {
+	boards, err := allBoards(app)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load boards: %w", err)
+	}
+	boardsMap := make(map[string]*core.Record, len(boards))
+	for _, b := range boards {
+		boardsMap[b.Id] = b
+	}
+	return boardsMap, nil
+}
+
+// findBoard finds a board by ID, name, or prefix like
+// board.GetByNameOrPrefix.
+func findBoard(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
+	if !isRemoteMode() {
+		return board.GetByNameOrPrefix(app, ref)
+	}
+	boards, err := allBoards(app)
+	if err != nil {
+		return nil, err
+	}
+	return board.Match(boards, ref)
+}
+
+// checkPolicy evaluates an action on a board like policy.Check. The server
+// enforces agent modes too, but only knows the agent of the API token.
+func checkPolicy(app core.App, actor policy.Actor, boardID string, action policy.Action) policy.Decision {
+	if !isRemoteMode() {
+		return policy.Check(app, actor, boardID, action)
+	}
+	var b *core.Record
+	if boardID != "" {
+		b, _ = remoteClient().GetRecord("boards", boardID)
+	}
+	return policy.CheckBoard(b, actor, action)
+}
+
+// checkTaskPolicy evaluates an action on a task's board.
+func checkTaskPolicy(app core.App, actor policy.Actor, task *core.Record, action policy.Action) policy.Decision {
+	return checkPolicy(app, actor, task.GetString("board"), action)
+}
+
+// requestAgent returns the agent a request to the server names as the
+// caller: the caller, if it is an agent. The agent of the API token takes
+// precedence on the server.
+func requestAgent(caller policy.Actor) string {
+	if caller.IsAgent() {
+		return caller.Name
+	}
+	return ""
+}
+
+// resultInt returns a number of a command result, which is a float64 in
+// results decoded from the JSON of the server.
+func resultInt(result map[string]any, key string) int {
+	switch v := result[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// resultString returns a string of a command result.
+func resultString(result map[string]any, key string) string {
+	s, _ := result[key].(string)
+	return s
+}
+
+// resultMap returns an object of a command result.
+func resultMap(result map[string]any, key string) map[string]any {
+	m, _ := result[key].(map[string]any)
+	return m
+}
+
+// resultValue decodes a value of a command result into v, whether the
+// result was built locally or decoded from the JSON of the server.
+func resultValue(result map[string]any, key string, v any) error {
+	data, err := json.Marshal(result[key])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// remoteFailed reports an error of a request to the server and exits with
+// the exit code of the matching command error: data.exit_code for the
+// routes of RegisterRoutes, or one derived from the HTTP status.
+func remoteFailed(out *output.Formatter, err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return commandFailed(out, err)
+	}
+
+	code := ExitGeneralError
+	if v, ok := apiErr.Data["exit_code"].(float64); ok {
+		code = int(v)
+	} else {
+		switch apiErr.StatusCode {
+		case http.StatusBadRequest:
+			code = ExitValidation
+		case http.StatusNotFound:
+			code = ExitNotFound
+		case http.StatusConflict:
+			code = ExitConflict
+		case http.StatusForbidden:
+			if apiErr.Data["policy"] != nil {
+				code = ExitPolicyDenied
+			}
+		}
+	}
+
+	if ref, ok := apiErr.Data["reference"].(string); ok && code == ExitAmbiguous {
+		return out.AmbiguousError(ref, remoteMatches(apiErr))
+	}
+
+	message := apiErr.Message
+	if apiErr.StatusCode == http.StatusUnauthorized || (apiErr.StatusCode == http.StatusForbidden && code != ExitPolicyDenied) {
+		message = fmt.Sprintf("%s (set $%s to an API token of the server)", message, config.TokenEnvVar)
+	}
+	// Pass on the data of the command's error, e.g. the policy violated
+	var data map[string]any
+	for k, v := range apiErr.Data {
+		if k == "exit_code" || k == "suggestion" {
+			continue
+		}
+		if data == nil {
+			data = map[string]any{}
+		}
+		data[k] = v
+	}
+	if suggestion, ok := apiErr.Data["suggestion"].(string); ok && suggestion != "" {
+		return out.ErrorWithSuggestion(code, message, suggestion, data)
+	}
+	return out.Error(code, message, data)
+}
+
+// inlineFilterParams replaces the {:name} placeholders of a PocketBase
+// filter with the quoted params, since the record API takes the filter as
+// a string.
+func inlineFilterParams(filter string, params ...dbx.Params) string {
+	merged := dbx.Params{}
+	for _, p := range params {
+		for k, v := range p {
+			merged[k] = v
+		}
+	}
+	// Longest names first, so {:a} does not replace part of {:ab}
+	names := make([]string, 0, len(merged))
+	for name := range merged {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	for _, name := range names {
+		filter = strings.ReplaceAll(filter, "{:"+name+"}", filterLiteral(merged[name]))
+	}
+	return filter
+}
+
+// filterLiteral formats a value as a PocketBase filter literal.
+func filterLiteral(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(val)
+	case int, int64, float64:
+		return fmt.Sprint(val)
+	case fmt.Stringer:
+		return quoteFilterString(val.String())
+	default:
+		return quoteFilterString(fmt.Sprint(val))
+	}
+}
+
+// quoteFilterString quotes a string for a PocketBase filter the way
+// PocketBase quotes filter placeholders, escaping backslashes before
+// quotes.
+func quoteFilterString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,657 @@
+package commands
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/proposal"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasktemplate"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+)
+
+// useRemoteServer runs the commands of a test in remote mode against a
+// server answering with handler.
+func useRemoteServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	remoteMode, serverURL, remoteAPIClient = true, server.URL, nil
+	t.Cleanup(func() {
+		server.Close()
+		remoteMode, serverURL, remoteAPIClient = false, "", nil
+	})
+}
+
+// useRouteServer runs the commands of a test in remote mode against
+// routes of RegisterRoutes on app, keyed by method and path pattern.
+func useRouteServer(t *testing.T, app *pocketbase.PocketBase, routes map[string]func(*core.RequestEvent) error) {
+	t.Helper()
+	mux := http.NewServeMux()
+	for pattern, route := range routes {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			re := &core.RequestEvent{App: app}
+			re.Request = r
+			re.Response = w
+			require.NoError(t, route(re))
+		})
+	}
+	useRemoteServer(t, mux.ServeHTTP)
+}
+
+func TestRemoteMode(t *testing.T) {
+	app := pocketbase.New()
+	app.RootCmd.AddCommand(newListCmd(app))
+
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"list"}, false},
+		{[]string{"--server", "http://host:8090", "list"}, true},
+		{[]string{"list", "--server=http://host:8090"}, true},
+		{[]string{"list", "--", "--server"}, false},
+		// The server commands always run on the local database
+		{[]string{"serve", "--server", "http://host:8090"}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RemoteMode(app, tt.args), "%v", tt.args)
+	}
+}
+
+func TestInlineFilterParams(t *testing.T) {
+	filter := inlineFilterParams("board = {:board} && title ~ {:b} && seq > {:seq} && done = {:done}",
+		dbx.Params{"board": "abc", "b": `say "hi"`},
+		dbx.Params{"seq": 3, "done": false})
+	assert.Equal(t, `board = "abc" && title ~ "say \"hi\"" && seq > 3 && done = false`, filter)
+
+	assert.Equal(t, "null", filterLiteral(nil))
+	assert.Equal(t, "1.5", filterLiteral(1.5))
+}
+
+func TestQuoteFilterString(t *testing.T) {
+	assert.Equal(t, `"C:\\tmp"`, quoteFilterString(`C:\tmp`))
+	assert.Equal(t, `"ends with \\"`, quoteFilterString(`ends with \`))
+	assert.Equal(t, `"a \\\" b"`, quoteFilterString(`a \" b`))
+
+	// Quoted like PocketBase quotes filter placeholders
+	for _, s := range []string{`C:\tmp`, `say "hi"`, `x\" || 1=1 || "`} {
+		assert.Equal(t, strconv.Quote(s), quoteFilterString(s))
+	}
+}
+
+func TestRemoteSaveRecord(t *testing.T) {
+	var requests []string
+	var bodies []map[string]any
+	useRemoteServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+
+		// The server assigns the sequence
+		body["id"] = "task12345678901"
+		body["seq"] = 7
+		json.NewEncoder(w).Encode(body)
+	})
+
+	record, err := newRecord(nil, "tasks")
+	require.NoError(t, err)
+	record.Set("title", "Remote task")
+	record.Set("labels", []string{"api"})
+	require.NoError(t, saveRecord(nil, record))
+
+	assert.Equal(t, "task12345678901", record.Id)
+	assert.Equal(t, 7, record.GetInt("seq"))
+	assert.False(t, record.IsNew())
+	assert.Equal(t, "Remote task", bodies[0]["title"])
+
+	record.Set("title", "Renamed")
+	require.NoError(t, saveRecord(nil, record))
+	assert.Equal(t, []string{
+		"POST /api/collections/tasks/records",
+		"PATCH /api/collections/tasks/records/task12345678901",
+	}, requests)
+	assert.Equal(t, "Renamed", bodies[1]["title"])
+	assert.Equal(t, []any{"api"}, bodies[1]["labels"])
+}
+
+func TestRemoteResolveTask(t *testing.T) {
+	useRemoteServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case APIPrefix + "/resolve/login":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"status": 409, "message": "ambiguous", "data": {"exit_code": 4, "reference": "login",
+				"matches": [{"id": "a", "title": "Fix login"}, {"id": "b", "title": "Login page"}]}}`))
+		case APIPrefix + "/resolve/WRK-1":
+			w.Write([]byte(`{"task": {"id": "task12345678901"}}`))
+		case "/api/collections/tasks/records/task12345678901":
+			w.Write([]byte(`{"id": "task12345678901", "title": "Fix login", "column": "todo"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status": 404, "message": "not found", "data": {"exit_code": 3}}`))
+		}
+	})
+
+	task, err := resolveTask(nil, "WRK-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Fix login", task.GetString("title"))
+
+	_, err = resolveTask(nil, "login")
+	var ambErr *resolver.AmbiguousError
+	require.ErrorAs(t, err, &ambErr)
+	require.Len(t, ambErr.Matches, 2)
+	assert.Equal(t, "Login page", ambErr.Matches[1].GetString("title"))
+
+	_, err = resolveTask(nil, "nothing")
+	assert.EqualError(t, err, "no task found matching: nothing")
+}
+
+func TestRemoteFindActiveRecords(t *testing.T) {
+	var filter string
+	useRemoteServer(t, func(w http.ResponseWriter, r *http.Request) {
+		filter = r.URL.Query().Get("filter")
+		w.Write([]byte(`{"items": [
+			{"id": "a", "title": "Open"},
+			{"id": "b", "title": "Trashed", "deleted_at": "2026-01-07 10:00:00.000Z"}
+		]}`))
+	})
+
+	records, err := findActiveRecords(nil, "epics", "board = {:board}", "", dbx.Params{"board": "wrk"})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "a", records[0].Id)
+	assert.Equal(t, `board = "wrk"`, filter)
+}
+
+func TestResolveCaller_RemoteAgentToken(t *testing.T) {
+	clearAgentEnv(t)
+	t.Setenv("EGENSKRIVEN_TOKEN", "agent-token")
+
+	var requests int
+	useRemoteServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/api/collections/users/auth-refresh" || r.Header.Get("Authorization") != "Bearer agent-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"status": 401, "message": "unauthorized"}`))
+			return
+		}
+		w.Write([]byte(`{"token": "agent-token", "record": {"id": "bot12345678901", "name": "bot", "agent": "claude"}}`))
+	})
+
+	// The server names the agent of the token; the local app is never
+	// bootstrapped in remote mode
+	app := pocketbase.New()
+	assert.Equal(t, policy.Agent("claude"), resolveCaller(app, ""))
+	assert.Equal(t, policy.Agent("claude"), resolveCaller(app, ""))
+	assert.Equal(t, 1, requests, "the account is fetched once")
+
+	assert.Equal(t, policy.Agent("opencode"), resolveCaller(app, "opencode"), "flag wins over the token")
+}
+
+func TestRemoteTrackTimeAndLog(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+	SetupTimeEntriesCollection(t, app)
+	task := CreateTestTask(t, app, "Parser", "in_progress")
+
+	useRouteServer(t, app, map[string]func(*core.RequestEvent) error{
+		"POST " + APIPrefix + "/tasks/{id}/time/{action}": apiTrackTime(app),
+		"GET " + APIPrefix + "/time/report":               apiTimeReport(app),
+		"GET " + APIPrefix + "/log":                       apiLog(app),
+	})
+	c := remoteClient()
+
+	endedAt := time.Date(2025, 1, 15, 17, 0, 0, 0, time.UTC)
+	result, err := c.TrackTime(task.Id, "log", timeRequest{Actor: "alice", Duration: "45m0s", EndedAt: endedAt})
+	require.NoError(t, err)
+	entry := result["entry"].(map[string]any)
+	assert.Equal(t, 2700, resultInt(entry, "duration_seconds"))
+
+	_, err = c.TrackTime(task.Id, "stop", timeRequest{})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, float64(ExitValidation), apiErr.Data["exit_code"])
+
+	rows, err := c.TimeReport("actor", endedAt.Add(-24*time.Hour), "")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, TimeReportRow{Key: "alice", Name: "alice", TotalSeconds: 2700, Hours: 0.75, Entries: 1}, rows[0])
+
+	rows, err = c.TimeReport("task", endedAt.Add(24*time.Hour), "")
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+
+	entries, err := c.Log(logQuery{Task: task.Id})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Equal(t, task.Id, entries[0].Task)
+	assert.Equal(t, "created", entries[0].Action)
+	assert.False(t, entries[0].Timestamp.IsZero())
+}
+
+func TestRemoteSprints(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	b, task := setupSprintBoard(t, app)
+
+	useRouteServer(t, app, map[string]func(*core.RequestEvent) error{
+		"GET " + APIPrefix + "/sprints":                 apiSprints(app),
+		"POST " + APIPrefix + "/sprints":                apiCreateSprint(app),
+		"GET " + APIPrefix + "/sprints/{ref}":           apiShowSprint(app),
+		"POST " + APIPrefix + "/sprints/{ref}/{action}": apiSprintAction(app),
+		"POST " + APIPrefix + "/tasks/{id}/sprint":      apiTaskSprint(app),
+	})
+	c := remoteClient()
+
+	_, err := remoteNextSprint(b.Id)
+	var ce *commandError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, ExitNotFound, ce.code)
+
+	created, err := c.CreateSprint(sprintRequest{Board: b.Id, Name: "Sprint 1", Start: "2025-01-06"})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-19", resultString(created, "end_date"))
+
+	ref, err := remoteNextSprint(b.Id)
+	require.NoError(t, err)
+	assert.Equal(t, resultString(created, "id"), ref)
+
+	_, err = c.SprintAction(ref, "start", sprintRequest{Board: b.Id})
+	require.NoError(t, err)
+	_, err = c.SetTaskSprint(task.Id, taskSprintRequest{Sprint: "current"})
+	require.NoError(t, err)
+
+	shown, err := c.ShowSprint(b.Id, "current")
+	require.NoError(t, err)
+	assert.Equal(t, "Sprint 1", resultString(resultMap(shown, "sprint"), "name"))
+	var tasks []map[string]any
+	require.NoError(t, resultValue(shown, "tasks", &tasks))
+	require.Len(t, tasks, 1)
+	assert.Equal(t, task.Id, resultString(tasks[0], "id"))
+
+	_, err = c.SprintAction("Sprint 1", "close", sprintRequest{Board: b.Id, Carry: true})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, float64(ExitValidation), apiErr.Data["exit_code"])
+	assert.Equal(t, "no planned sprint to carry tasks into", apiErr.Message)
+
+	listed, err := c.Sprints(b.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, resultInt(listed, "count"))
+}
+
+func TestRemoteViews(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	b := setupViewBoard(t, app)
+
+	useRouteServer(t, app, map[string]func(*core.RequestEvent) error{
+		"GET " + APIPrefix + "/views":                 apiViews(app),
+		"POST " + APIPrefix + "/views":                apiCreateView(app),
+		"GET " + APIPrefix + "/views/{ref}":           apiShowView(app),
+		"POST " + APIPrefix + "/views/{ref}/{action}": apiViewAction(app),
+	})
+	c := remoteClient()
+
+	flags := viewFilterFlags{Assignee: "alice", NotBlocked: true}
+	created, err := c.CreateView(viewRequest{Board: b.Id, Name: "Mine", Filters: &flags})
+	require.NoError(t, err)
+	assert.Len(t, created.Filters, 2)
+	assert.Equal(t, []string{"alice"}, created.Filters[1].Values())
+
+	shown, err := c.ShowView(b.Id, "mine")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, shown.ID)
+
+	updated, err := c.ViewAction(created.ID, "update", viewRequest{
+		Board:   b.Id,
+		Filters: &viewFilterFlags{Types: []string{"bug"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, updated.Filters, 1)
+	assert.Equal(t, "type", updated.Filters[0].Field)
+
+	_, err = c.ViewAction(created.ID, "favorite", viewRequest{Board: b.Id, Favorite: true})
+	require.NoError(t, err)
+	views, err := c.Views(b.Id)
+	require.NoError(t, err)
+	require.Len(t, views, 1)
+	assert.True(t, views[0].IsFavorite)
+
+	_, err = c.ViewAction(created.ID, "delete", viewRequest{Board: b.Id})
+	require.NoError(t, err)
+	_, err = c.ShowView(b.Id, "Mine")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, float64(ExitNotFound), apiErr.Data["exit_code"])
+}
+
+func TestRemoteArchive(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	b, task := setupArchiveBoard(t, app)
+
+	useRouteServer(t, app, map[string]func(*core.RequestEvent) error{
+		"POST " + APIPrefix + "/tasks/{id}/archive":   apiArchive(app, false),
+		"POST " + APIPrefix + "/tasks/{id}/unarchive": apiArchive(app, true),
+		"POST " + APIPrefix + "/archive/run":          apiRunArchive(app),
+	})
+	c := remoteClient()
+
+	result, err := c.ArchiveTask(task.Id, "archive", archiveRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, resultString(result, "skipped"))
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.True(t, archive.IsArchived(task))
+
+	result, err = c.ArchiveTask(task.Id, "archive", archiveRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, archive.ErrArchived.Error(), resultString(result, "skipped"))
+
+	_, err = c.ArchiveTask(task.Id, "unarchive", archiveRequest{})
+	require.NoError(t, err)
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.False(t, archive.IsArchived(task))
+
+	result, err = c.RunArchive(archiveRequest{Board: b.Id, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 0, resultInt(result, "count"))
+	assert.Equal(t, true, result["dry_run"])
+}
+
+func TestRemoteTrash(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	_, task := setupTrashBoard(t, app)
+	require.NoError(t, trash.Task(app, task, "cli", "alice"))
+
+	useRouteServer(t, app, map[string]func(*core.RequestEvent) error{
+		"GET " + APIPrefix + "/trash":          apiTrash(app),
+		"POST " + APIPrefix + "/trash/restore": apiRestoreTrash(app),
+		"POST " + APIPrefix + "/trash/purge":   apiPurgeTrash(app),
+	})
+	c := remoteClient()
+
+	listed, err := c.Trash("WRK")
+	require.NoError(t, err)
+	assert.Equal(t, 1, resultInt(listed, "count"))
+
+	restored, err := c.RestoreTrash(trashRequest{Ref: task.Id})
+	require.NoError(t, err)
+	assert.Equal(t, task.Id, resultString(restored, "id"))
+
+	_, err = c.RestoreTrash(trashRequest{Ref: task.Id})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, float64(ExitNotFound), apiErr.Data["exit_code"])
+
+	require.NoError(t, trash.Task(app, task, "cli", "alice"))
+	purged, err := c.PurgeTrash(trashRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged.Tasks)
+}
+
+func TestRemoteUndo(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	b, task := setupUndoBoard(t, app)
+
+	useRouteServer(t, app, map[string]func(*core.RequestEvent) error{
+		"POST " + APIPrefix + "/undo": apiUndo(app, false),
+		"POST " + APIPrefix + "/redo": apiUndo(app, true),
+	})
+	c := remoteClient()
+
+	// An agent raised the priority, then moved the task
+	change := func(label, field, value string) {
+		before := undo.Snapshot(task)
+		task.Set(field, value)
+		require.NoError(t, app.Save(task))
+		require.NoError(t, undo.Record(app, undo.NewBatch(label, "claude"),
+			undo.KindUpdate, task.Id, b.Id, before, undo.Snapshot(task)))
+	}
+	change("raise WRK-1", "priority", "high")
+	change("move WRK-1 review", "column", "review")
+
+	// Someone else lowered the priority since: the move is undone, the
+	// priority change conflicts
+	task, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	task.Set("priority", "low")
+	require.NoError(t, app.Save(task))
+
+	_, err = c.Undo("undo", undoRequest{Count: 2, Agent: "claude"})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, float64(ExitConflict), apiErr.Data["exit_code"])
+	undone := undoneBefore(err, "Undone")
+	require.Len(t, undone, 1)
+	assert.Equal(t, "move WRK-1 review", undone[0].Label)
+
+	result, err := c.Undo("redo", undoRequest{Agent: "claude"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resultInt(result, "count"))
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "review", task.GetString("column"))
+}
+
+func TestRemoteTemplates(t *testing.T) {
+	// The server stores board templates in the task_templates collection
+	stored := map[string]map[string]any{}
+	var requests []string
+	useRemoteServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		id := strings.TrimPrefix(r.URL.Path, "/api/collections/task_templates/records")
+		id = strings.TrimPrefix(id, "/")
+		switch r.Method {
+		case http.MethodGet:
+			filter := r.URL.Query().Get("filter")
+			items := []map[string]any{}
+			for _, item := range stored {
+				if strings.Contains(filter, "name =") && !strings.Contains(filter, `name = "`+item["name"].(string)+`"`) {
+					continue
+				}
+				if strings.Contains(filter, `board = "`+item["board"].(string)+`"`) {
+					items = append(items, item)
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]any{"items": items})
+		case http.MethodPost, http.MethodPatch:
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if id == "" {
+				id = "tmpl" + strconv.Itoa(len(stored)+1)
+			}
+			body["id"] = id
+			stored[id] = body
+			json.NewEncoder(w).Encode(body)
+		case http.MethodDelete:
+			delete(stored, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	bug := tasktemplate.Template{
+		Name:     "bug",
+		Task:     tasktemplate.Spec{Title: "Bug: {{title}}", Type: "bug"},
+		Subtasks: []tasktemplate.Spec{{Title: "Reproduce"}, {Title: "Fix"}},
+	}
+	_, err := saveTemplate(nil, "board1", bug, false)
+	require.NoError(t, err)
+	_, err = saveTemplate(nil, "board2", tasktemplate.Template{Name: "chore"}, false)
+	require.NoError(t, err)
+
+	_, err = saveTemplate(nil, "board1", bug, false)
+	assert.ErrorIs(t, err, tasktemplate.ErrExists)
+	bug.Summary = "Bug report"
+	_, err = saveTemplate(nil, "board1", bug, true)
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+
+	templates, err := listTemplates(nil, "board1")
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "Bug report", templates[0].Summary)
+	assert.Equal(t, "Bug: {{title}}", templates[0].Task.Title)
+	assert.Len(t, templates[0].Subtasks, 2)
+	assert.Equal(t, tasktemplate.SourceBoard, templates[0].Source)
+
+	found, err := findTemplate(nil, "board1", "bug")
+	require.NoError(t, err)
+	require.NoError(t, deleteTemplate(nil, found))
+	_, err = findTemplate(nil, "board1", "bug")
+	assert.ErrorIs(t, err, tasktemplate.ErrNotFound)
+	assert.Contains(t, requests, "DELETE /api/collections/task_templates/records/tmpl1")
+}
+
+func TestRemoteProposals(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	_, p := setupProposalBoard(t, app)
+
+	useRouteServer(t, app, map[string]func(*core.RequestEvent) error{
+		"GET " + APIPrefix + "/proposals":                 apiProposals(app),
+		"GET " + APIPrefix + "/proposals/{ref}":           apiShowProposal(app),
+		"POST " + APIPrefix + "/proposals/{ref}/{action}": apiReviewProposal(app),
+	})
+	c := remoteClient()
+
+	result, err := c.Proposals("WRK", "")
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), result["count"])
+
+	shown, err := c.ShowProposal(p.Id[:6])
+	require.NoError(t, err)
+	assert.Equal(t, p.Id, shown["id"])
+
+	rejected, err := runProposalReview(app, p.Id, "Not yet", false)
+	require.NoError(t, err)
+	assert.Equal(t, proposal.StatusRejected, rejected["status"])
+	assert.Equal(t, "Not yet", rejected["review_note"])
+
+	_, err = runProposalReview(app, p.Id, "", true)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, float64(ExitValidation), apiErr.Data["exit_code"])
+
+	result, err = c.Proposals("", proposal.StatusPending)
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), result["count"])
+}
+
+func TestRemoteAttachments(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	_, task := setupSprintBoard(t, app)
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	tasks.Fields.Add(&core.FileField{Name: attachment.Field, MaxSelect: 10, MaxSize: 1 << 20})
+	require.NoError(t, app.Save(tasks))
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	file, err := filesystem.NewFileFromBytes([]byte("panic: nil map"), "build.log")
+	require.NoError(t, err)
+	task.Set(attachment.Field, []*filesystem.File{file})
+	require.NoError(t, app.Save(task))
+	stored := attachment.Names(task)[0]
+
+	var patched map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+APIPrefix+"/tasks/{id}/attachments", func(w http.ResponseWriter, r *http.Request) {
+		re := &core.RequestEvent{App: app}
+		re.Request, re.Response = r, w
+		require.NoError(t, apiAttachments(app)(re))
+	})
+	mux.HandleFunc("GET /api/files/tasks/{id}/{name}", func(w http.ResponseWriter, r *http.Request) {
+		infos, err := attachment.ForRecord(app, task)
+		require.NoError(t, err)
+		reader, err := attachment.Open(app, infos[0])
+		require.NoError(t, err)
+		defer reader.Close()
+		io.Copy(w, reader)
+	})
+	mux.HandleFunc("PATCH /api/collections/tasks/records/{id}", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&patched))
+		json.NewEncoder(w).Encode(patched)
+	})
+	useRemoteServer(t, mux.ServeHTTP)
+
+	result, infos, err := resolveTaskAttachments(app, getFormatter(), task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "Parser", resultString(result, "title"))
+	require.Len(t, infos, 1)
+	assert.Equal(t, stored, infos[0].Name)
+
+	reader, err := openAttachment(app, task.Id, infos[0])
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "panic: nil map", string(content))
+
+	// The record API takes the files that remain
+	remote := remoteRecord("tasks", map[string]any{"id": task.Id, attachment.Field: []any{stored}})
+	removeAttachments(remote, attachment.Names(remote), []string{stored})
+	require.NoError(t, saveRecord(app, remote))
+	assert.Equal(t, []any{}, patched[attachment.Field])
+}
+
+func TestRemoteFlowReport(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	setupSprintBoard(t, app)
+
+	useRouteServer(t, app, map[string]func(*core.RequestEvent) error{
+		"GET " + APIPrefix + "/reports/flow": apiFlowReport(app),
+	})
+
+	since := time.Now().Add(-7 * 24 * time.Hour)
+	result, err := remoteClient().FlowReport("WRK", since)
+	require.NoError(t, err)
+	assert.Equal(t, "Work", result["board"])
+	var report flow.Report
+	require.NoError(t, resultValue(result, "report", &report))
+	assert.Equal(t, since.UTC().Format(time.RFC3339), report.Since)
+	assert.Equal(t, 0, report.Overall.Completed)
+
+	_, err = remoteClient().FlowReport("OPS", since)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, float64(ExitNotFound), apiErr.Data["exit_code"])
+}
+
+func TestRemoteExport(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	setupSprintBoard(t, app)
+
+	useRouteServer(t, app, map[string]func(*core.RequestEvent) error{
+		"GET " + APIPrefix + "/export": apiExport(app),
+	})
+
+	body, summary, err := remoteClient().Export(exportOptions{format: "csv", board: "WRK"})
+	require.NoError(t, err)
+	content, err := io.ReadAll(body)
+	body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "Exported 1 tasks", summary)
+	assert.Contains(t, string(content), "Parser")
+
+	_, _, err = remoteClient().Export(exportOptions{format: "csv", board: "OPS"})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, float64(ExitNotFound), apiErr.Data["exit_code"])
+}
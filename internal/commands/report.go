@@ -0,0 +1,325 @@
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// validFlowDatasets are the supported --data values for CSV flow output
+var validFlowDatasets = []string{"tasks", "throughput", "cfd"}
+
+func newReportCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Analyze board activity",
+		Long:  `Reports computed from the task event log.`,
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newReportFlowCmd(app))
+
+	return cmd
+}
+
+// ========== Report Flow ==========
+
+func newReportFlowCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef string
+		since    string
+		format   string
+		dataset  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "flow",
+		Short: "Flow metrics from the task event log",
+		Long: `Compute flow metrics from the column changes recorded in the task event log.
+
+Metrics (for tasks completed since --since):
+  - Lead time:  task created -> moved to done
+  - Cycle time: first moved to in_progress -> moved to done
+  - Time in each column, including time blocked in need_input (over the
+    tasks that were in need_input)
+  - Weekly throughput (weeks start on Monday)
+  - Cumulative flow: tasks per column at the end of each day
+
+Durations are reported in hours with p50/p85/p95 percentiles. Results are
+split by human vs agent: completed tasks are attributed to whoever moved
+them to done.
+
+CSV output writes one dataset, chosen with --data (tasks, throughput, cfd).`,
+		Example: `  egenskriven report flow --board WRK --since 30d
+  egenskriven report flow --since 2w --json
+  egenskriven report flow --format csv --data cfd > cfd.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			format = strings.ToLower(format)
+			if jsonOutput {
+				format = "json"
+			}
+			if format != "table" && format != "csv" && format != "json" {
+				return out.Error(ExitValidation,
+					fmt.Sprintf("unsupported format: %s (use 'table', 'csv' or 'json')", format), nil)
+			}
+			if !containsString(validFlowDatasets, dataset) {
+				return out.Error(ExitValidation,
+					fmt.Sprintf("invalid --data %q: must be one of %v", dataset, validFlowDatasets), nil)
+			}
+
+			sinceTime, err := parseSince(since)
+			if err != nil {
+				return out.Error(ExitValidation, err.Error(), nil)
+			}
+
+			// Board filter, falling back to the configured default board
+			if boardRef == "" {
+				cfg, _ := config.LoadProjectConfig()
+				if cfg != nil && cfg.DefaultBoard != "" {
+					boardRef = cfg.DefaultBoard
+				}
+			}
+			var result map[string]any
+			if isRemoteMode() {
+				if result, err = remoteClient().FlowReport(boardRef, sinceTime); err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				var boardRecord *core.Record
+				if boardRef != "" {
+					boardRecord, err = board.GetByNameOrPrefix(app, boardRef)
+					if err != nil {
+						return out.Error(ExitNotFound, fmt.Sprintf("board not found: %s", boardRef), nil)
+					}
+				}
+				if result, err = flowReportResult(app, boardRecord, sinceTime, time.Now()); err != nil {
+					return commandFailed(out, err)
+				}
+			}
+
+			var report flow.Report
+			if err := resultValue(result, "report", &report); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to read report: %v", err), nil)
+			}
+
+			switch format {
+			case "json":
+				out.WriteJSON(result)
+			case "csv":
+				if err := writeFlowCSV(report, dataset); err != nil {
+					return out.Error(ExitGeneralError, fmt.Sprintf("failed to write CSV: %v", err), nil)
+				}
+			default:
+				printFlowReport(report)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board to analyze (default: configured default board, else all)")
+	cmd.Flags().StringVar(&since, "since", "30d", "Start of the reporting window (e.g. 30d, 2w, 2025-01-15)")
+	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format: table, csv, json")
+	cmd.Flags().StringVar(&dataset, "data", "tasks", "Dataset for CSV output: tasks, throughput, cfd")
+
+	return cmd
+}
+
+// flowReportResult builds the flow report of a board, or of all boards
+// for a nil boardRecord, as printed by 'report flow --json'.
+func flowReportResult(app *pocketbase.PocketBase, boardRecord *core.Record, since, until time.Time) (map[string]any, error) {
+	report, err := buildFlowReport(app, boardRecord, since, until)
+	if err != nil {
+		return nil, newCommandError(ExitGeneralError, "failed to build report: %v", err)
+	}
+	result := map[string]any{
+		"report": report,
+	}
+	if boardRecord != nil {
+		result["board"] = boardRecord.GetString("name")
+	}
+	return result, nil
+}
+
+// buildFlowReport loads tasks (optionally limited to a board) and their
+// column transitions from the task event log, and computes flow metrics
+// for the window [since, until].
+func buildFlowReport(app *pocketbase.PocketBase, boardRecord *core.Record, since, until time.Time) (flow.Report, error) {
+	var exprs []dbx.Expression
+	columns := ValidColumns
+	prefix := ""
+	if boardRecord != nil {
+		exprs = append(exprs, dbx.NewExp("board = {:board}", dbx.Params{"board": boardRecord.Id}))
+		b := board.RecordToBoard(boardRecord)
+		columns = b.Columns
+		prefix = b.Prefix
+	}
+
+	exprs = append(exprs, trash.Exclude(app, "tasks"))
+	tasks, err := app.FindAllRecords("tasks", exprs...)
+	if err != nil {
+		return flow.Report{}, err
+	}
+
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.Id
+	}
+	transitions, err := taskevent.Transitions(app, ids)
+	if err != nil {
+		return flow.Report{}, err
+	}
+
+	timelines := make([]flow.Timeline, 0, len(tasks))
+	for _, task := range tasks {
+		displayID := shortID(task.Id)
+		if prefix != "" && task.GetInt("seq") > 0 {
+			displayID = board.FormatDisplayID(prefix, task.GetInt("seq"))
+		} else if boardRecord == nil {
+			displayID = getTaskDisplayID(app, task)
+		}
+
+		timelines = append(timelines, flow.NewTimeline(flow.TaskInput{
+			ID:          task.Id,
+			DisplayID:   displayID,
+			Title:       task.GetString("title"),
+			Column:      task.GetString("column"),
+			CreatedBy:   task.GetString("created_by"),
+			Created:     task.GetDateTime("created").Time(),
+			Transitions: transitions[task.Id],
+		}))
+	}
+
+	return flow.BuildReport(timelines, columns, since, until), nil
+}
+
+// printFlowReport renders a flow report as human-readable tables.
+func printFlowReport(report flow.Report) {
+	fmt.Printf("Flow report: %s to %s\n\n", report.Since[:10], report.Until[:10])
+
+	if report.Overall.Completed == 0 {
+		fmt.Println("No tasks completed in this period.")
+	} else {
+		fmt.Printf("%-22s %6s %8s %8s %8s %8s\n", "METRIC (hours)", "COUNT", "MEAN", "P50", "P85", "P95")
+		printStatsRow := func(label string, s flow.Stats) {
+			fmt.Printf("%-22s %6d %8.1f %8.1f %8.1f %8.1f\n", label, s.Count, s.Mean, s.P50, s.P85, s.P95)
+		}
+		printStatsRow("Lead time", report.Overall.LeadTime)
+		printStatsRow("Cycle time", report.Overall.CycleTime)
+		printStatsRow("Blocked (need_input)", report.Overall.Blocked)
+		for _, kind := range []string{flow.ActorHuman, flow.ActorAgent} {
+			group := report.ByActor[kind]
+			if group.Completed == 0 {
+				continue
+			}
+			printStatsRow("Lead time ("+kind+")", group.LeadTime)
+			printStatsRow("Cycle time ("+kind+")", group.CycleTime)
+		}
+
+		fmt.Println("\nTime in column (hours):")
+		for _, column := range report.Columns {
+			s, ok := report.TimeInColumns[column]
+			if !ok {
+				continue
+			}
+			printStatsRow("  "+column, s)
+		}
+	}
+
+	fmt.Println("\nWeekly throughput:")
+	fmt.Printf("  %-12s %6s %6s %6s\n", "WEEK", "DONE", "HUMAN", "AGENT")
+	for _, w := range report.Throughput {
+		fmt.Printf("  %-12s %6d %6d %6d\n", w.WeekStart, w.Completed,
+			w.ByActor[flow.ActorHuman], w.ByActor[flow.ActorAgent])
+	}
+
+	if len(report.CumulativeFlow) > 0 {
+		last := report.CumulativeFlow[len(report.CumulativeFlow)-1]
+		fmt.Printf("\nCurrent WIP (%s):\n", last.Date)
+		for _, column := range report.Columns {
+			fmt.Printf("  %-14s %d\n", column, last.Columns[column])
+		}
+		fmt.Println("\nUse --format csv --data cfd for daily cumulative-flow data.")
+	}
+}
+
+// writeFlowCSV writes one dataset of a flow report as CSV to stdout.
+func writeFlowCSV(report flow.Report, dataset string) error {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	var rows [][]string
+	switch dataset {
+	case "throughput":
+		rows = append(rows, []string{"week_start", "completed", "human", "agent"})
+		for _, t := range report.Throughput {
+			rows = append(rows, []string{
+				t.WeekStart,
+				fmt.Sprintf("%d", t.Completed),
+				fmt.Sprintf("%d", t.ByActor[flow.ActorHuman]),
+				fmt.Sprintf("%d", t.ByActor[flow.ActorAgent]),
+			})
+		}
+	case "cfd":
+		rows = append(rows, append([]string{"date"}, report.Columns...))
+		for _, p := range report.CumulativeFlow {
+			row := []string{p.Date}
+			for _, column := range report.Columns {
+				row = append(row, fmt.Sprintf("%d", p.Columns[column]))
+			}
+			rows = append(rows, row)
+		}
+	default:
+		header := []string{"task", "title", "actor_kind", "completed_at", "lead_hours", "cycle_hours", "blocked_hours"}
+		for _, column := range report.Columns {
+			header = append(header, column+"_hours")
+		}
+		rows = append(rows, header)
+		for _, t := range report.Tasks {
+			cycle := ""
+			if t.CycleHours != nil {
+				cycle = fmt.Sprintf("%.2f", *t.CycleHours)
+			}
+			row := []string{
+				t.DisplayID,
+				t.Title,
+				t.ActorKind,
+				t.CompletedAt,
+				fmt.Sprintf("%.2f", t.LeadHours),
+				cycle,
+				fmt.Sprintf("%.2f", t.BlockedHours),
+			}
+			for _, column := range report.Columns {
+				row = append(row, fmt.Sprintf("%.2f", t.HoursInColumns[column]))
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
 	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
 	"github.com/ramtinJ95/EgenSkriven/internal/resume"
 )
@@ -59,94 +60,50 @@ which is injected into the agent's session.`,
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
 			taskRef := args[0]
 
-			// Resolve task
-			task, err := resolver.MustResolve(app, taskRef)
-			if err != nil {
-				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
-					return out.AmbiguousError(taskRef, ambErr.Matches)
+			var task *core.Record
+			var info *resumeInfo
+			if isRemoteMode() {
+				// The server builds the command and, with --exec, marks the
+				// task and session resumed before it runs here
+				var err error
+				info, err = remoteResume(taskRef, resumeRequest{
+					Minimal: minimal,
+					Prompt:  customPrompt,
+					Start:   execFlag && !dryRun,
+				})
+				if err != nil {
+					return remoteFailed(out, err)
 				}
-				return out.Error(ExitNotFound, err.Error(), nil)
-			}
-
-			displayId := getTaskDisplayID(app, task)
-
-			// Validate task state
-			column := task.GetString("column")
-			if column != "need_input" {
-				return out.Error(ExitValidation,
-					fmt.Sprintf("task %s is not in need_input state (current: %s)", displayId, column), nil)
-			}
-
-			// Get session info
-			sessionData := task.Get("agent_session")
-			if sessionData == nil || sessionData == "" {
-				return out.Error(ExitValidation,
-					fmt.Sprintf("no agent session linked to task %s\n\nTo resume, first link a session:\n  egenskriven session link %s --tool <tool> --ref <session-id>",
-						displayId, displayId), nil)
-			}
-
-			session, err := output.ParseAgentSession(sessionData)
-			if err != nil {
-				return out.Error(ExitGeneralError, fmt.Sprintf("invalid session data: %v", err), nil)
-			}
-			if session == nil {
-				return out.Error(ExitValidation,
-					fmt.Sprintf("no agent session linked to task %s\n\nTo resume, first link a session:\n  egenskriven session link %s --tool <tool> --ref <session-id>",
-						displayId, displayId), nil)
-			}
-
-			tool, _ := session["tool"].(string)
-			sessionRef, _ := session["ref"].(string)
-			workingDir, _ := session["working_dir"].(string)
-
-			// Validate session ref
-			if err := resume.ValidateSessionRef(tool, sessionRef); err != nil {
-				return out.Error(ExitValidation, fmt.Sprintf("invalid session: %v", err), nil)
-			}
-
-			// Fetch comments
-			comments, err := fetchCommentsForResume(app, task.Id)
-			if err != nil {
-				return out.Error(ExitGeneralError, fmt.Sprintf("failed to fetch comments: %v", err), nil)
-			}
-
-			// Build context prompt
-			var prompt string
-			if customPrompt != "" {
-				prompt = customPrompt
-			} else if minimal {
-				prompt = resume.BuildMinimalPrompt(task, displayId, comments)
 			} else {
-				prompt = resume.BuildContextPrompt(task, displayId, comments)
-			}
+				// Resolve task
+				var err error
+				task, err = resolver.MustResolve(app, taskRef)
+				if err != nil {
+					if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+						return out.AmbiguousError(taskRef, ambErr.Matches)
+					}
+					return out.Error(ExitNotFound, err.Error(), nil)
+				}
 
-			// Build resume command
-			resumeCmd, err := resume.BuildResumeCommand(tool, sessionRef, workingDir, prompt)
-			if err != nil {
-				return out.Error(ExitGeneralError, fmt.Sprintf("failed to build resume command: %v", err), nil)
+				info, err = prepareResume(app, task, minimal, customPrompt)
+				if err != nil {
+					return commandFailed(out, err)
+				}
 			}
+			displayId, resumeCmd, prompt := info.DisplayID, info.Command, info.Prompt
+			tool, workingDir := info.Tool, info.WorkingDir
 
 			// JSON output
 			if jsonOutput {
-				result := map[string]any{
-					"task_id":       task.Id,
-					"display_id":    displayId,
-					"tool":          tool,
-					"session_ref":   sessionRef,
-					"working_dir":   workingDir,
-					"command":       resumeCmd.Command,
-					"prompt":        prompt,
-					"prompt_length": len(prompt),
-				}
 				encoder := json.NewEncoder(os.Stdout)
 				encoder.SetIndent("", "  ")
-				return encoder.Encode(result)
+				return encoder.Encode(info.result())
 			}
 
 			// Execute mode
@@ -158,14 +115,16 @@ which is injected into the agent's session.`,
 					return nil
 				}
 
-				// Update task status BEFORE executing
-				if err := updateTaskForResume(app, task); err != nil {
-					return out.Error(ExitGeneralError, fmt.Sprintf("failed to update task: %v", err), nil)
+				// Update task and session status BEFORE executing
+				if task != nil {
+					if err := checkTaskPolicy(app, resolveCaller(app, ""), task, policy.ActionMove).Err(); err != nil {
+						return policyDenied(out, err)
+					}
+					if err := startResume(app, task, info.SessionRef); err != nil {
+						return out.Error(ExitGeneralError, fmt.Sprintf("failed to update task: %v", err), nil)
+					}
 				}
 
-				// Update session status in history
-				updateSessionStatusInHistory(app, task.Id, sessionRef, "active")
-
 				fmt.Printf("Resuming session for %s...\n", displayId)
 				fmt.Printf("Tool: %s\n", tool)
 				fmt.Printf("Working directory: %s\n\n", workingDir)
@@ -193,6 +152,132 @@ which is injected into the agent's session.`,
 	return cmd
 }
 
+// resumeInfo is the command resuming the agent session of a blocked task.
+type resumeInfo struct {
+	TaskID     string
+	DisplayID  string
+	Tool       string
+	SessionRef string
+	WorkingDir string
+	Prompt     string
+	Command    *resume.ResumeCommand
+}
+
+// result is the JSON output of resume, also returned by the REST route.
+func (r *resumeInfo) result() map[string]any {
+	return map[string]any{
+		"task_id":       r.TaskID,
+		"display_id":    r.DisplayID,
+		"tool":          r.Tool,
+		"session_ref":   r.SessionRef,
+		"working_dir":   r.WorkingDir,
+		"command":       r.Command.Command,
+		"prompt":        r.Prompt,
+		"prompt_length": len(r.Prompt),
+	}
+}
+
+// prepareResume builds the command resuming the agent session linked to a
+// task in need_input, with the task and its comment thread as the prompt
+// unless customPrompt is given.
+func prepareResume(app *pocketbase.PocketBase, task *core.Record, minimal bool, customPrompt string) (*resumeInfo, error) {
+	displayId := getTaskDisplayID(app, task)
+
+	// Validate task state
+	column := task.GetString("column")
+	if column != "need_input" {
+		return nil, newCommandError(ExitValidation,
+			"task %s is not in need_input state (current: %s)", displayId, column)
+	}
+
+	// Get session info
+	noSession := newCommandError(ExitValidation,
+		"no agent session linked to task %s\n\nTo resume, first link a session:\n  egenskriven session link %s --tool <tool> --ref <session-id>",
+		displayId, displayId)
+	sessionData := task.Get("agent_session")
+	if sessionData == nil || sessionData == "" {
+		return nil, noSession
+	}
+
+	session, err := output.ParseAgentSession(sessionData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session data: %w", err)
+	}
+	if session == nil {
+		return nil, noSession
+	}
+
+	info := &resumeInfo{TaskID: task.Id, DisplayID: displayId}
+	info.Tool, _ = session["tool"].(string)
+	info.SessionRef, _ = session["ref"].(string)
+	info.WorkingDir, _ = session["working_dir"].(string)
+
+	// Validate session ref
+	if err := resume.ValidateSessionRef(info.Tool, info.SessionRef); err != nil {
+		return nil, newCommandError(ExitValidation, "invalid session: %v", err)
+	}
+
+	// Fetch comments
+	comments, err := fetchCommentsForResume(app, task.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comments: %w", err)
+	}
+
+	// Build context prompt
+	switch {
+	case customPrompt != "":
+		info.Prompt = customPrompt
+	case minimal:
+		info.Prompt = resume.BuildMinimalPrompt(task, displayId, comments)
+	default:
+		info.Prompt = resume.BuildContextPrompt(task, displayId, comments)
+	}
+
+	// Build resume command
+	info.Command, err = resume.BuildResumeCommand(info.Tool, info.SessionRef, info.WorkingDir, info.Prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build resume command: %w", err)
+	}
+
+	return info, nil
+}
+
+// remoteResume gets the command resuming a task's agent session from the
+// server, for remote mode.
+func remoteResume(ref string, req resumeRequest) (*resumeInfo, error) {
+	result, err := remoteClient().ResumeTask(ref, req)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &resumeInfo{}
+	info.TaskID, _ = result["task_id"].(string)
+	info.DisplayID, _ = result["display_id"].(string)
+	info.Tool, _ = result["tool"].(string)
+	info.SessionRef, _ = result["session_ref"].(string)
+	info.WorkingDir, _ = result["working_dir"].(string)
+	info.Prompt, _ = result["prompt"].(string)
+
+	// Build the command to run here from its parts
+	info.Command, err = resume.BuildResumeCommand(info.Tool, info.SessionRef, info.WorkingDir, info.Prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build resume command: %w", err)
+	}
+	return info, nil
+}
+
+// startResume marks a task and its session as resumed, right before the
+// resume command runs.
+func startResume(app *pocketbase.PocketBase, task *core.Record, sessionRef string) error {
+	if err := updateTaskForResume(app, task); err != nil {
+		return err
+	}
+
+	// Update session status in history
+	updateSessionStatusInHistory(app, task.Id, sessionRef, "active")
+	return nil
+}
+
 // fetchCommentsForResume gets comments formatted for the resume context.
 func fetchCommentsForResume(app *pocketbase.PocketBase, taskId string) ([]resume.Comment, error) {
 	records, err := app.FindRecordsByFilter(
@@ -220,12 +305,12 @@ func fetchCommentsForResume(app *pocketbase.PocketBase, taskId string) ([]resume
 	return comments, nil
 }
 
-// updateTaskForResume moves task to in_progress and adds history.
+// updateTaskForResume moves task to in_progress and records a task event.
 func updateTaskForResume(app *pocketbase.PocketBase, task *core.Record) error {
 	task.Set("column", "in_progress")
 
-	// Add history entry
-	addHistoryEntry(task, "resumed", "", map[string]any{
+	// Record the change in the event log
+	addTaskEvent(task, "resumed", "", map[string]any{
 		"column": map[string]any{
 			"from": "need_input",
 			"to":   "in_progress",
@@ -13,6 +13,7 @@ import (
 
 	"github.com/ramtinJ95/EgenSkriven/internal/output"
 	"github.com/ramtinJ95/EgenSkriven/internal/resume"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
 	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
 )
 
@@ -422,12 +423,10 @@ func TestResumeCommand_UpdatesTaskOnExec(t *testing.T) {
 	assert.Equal(t, "in_progress", task.GetString("column"),
 		"task should move to in_progress after resume")
 
-	// Verify history was updated
-	history := getHistoryFromTask(t, task)
-	require.Greater(t, len(history), 0, "history should have entries")
-
-	lastEntry := history[len(history)-1]
-	assert.Equal(t, "resumed", lastEntry["action"], "last action should be 'resumed'")
+	// Verify the event was logged
+	events := GetTaskEvents(t, app, task.Id)
+	require.NotEmpty(t, events, "events should be logged")
+	assert.Equal(t, "resumed", events[len(events)-1].Action, "last action should be 'resumed'")
 }
 
 // TestResumeCommand_InvalidSessionRefRejected verifies invalid session refs are rejected
@@ -577,17 +576,17 @@ func TestIntegration_FullWorkflowBlockCommentResume(t *testing.T) {
 	assert.Equal(t, "agent", comments[0].GetString("author_type"))
 	assert.Equal(t, agentName, comments[0].GetString("author_id"))
 
-	// Verify history was updated with 'blocked' action
-	history := getHistoryFromTask(t, task)
-	require.Greater(t, len(history), 0, "task should have history")
+	// Verify a 'blocked' event was logged
+	events := GetTaskEvents(t, app, task.Id)
+	require.NotEmpty(t, events, "task should have events")
 	foundBlocked := false
-	for _, entry := range history {
-		if entry["action"] == "blocked" {
+	for _, event := range events {
+		if event.Action == "blocked" {
 			foundBlocked = true
 			break
 		}
 	}
-	assert.True(t, foundBlocked, "history should contain 'blocked' action")
+	assert.True(t, foundBlocked, "events should contain 'blocked' action")
 
 	// Step 3: Add human comment response
 	humanResponse := "@agent Use JWT with refresh tokens. Access tokens expire in 15 minutes, refresh tokens in 7 days."
@@ -650,28 +649,26 @@ func TestIntegration_FullWorkflowBlockCommentResume(t *testing.T) {
 	assert.Equal(t, "in_progress", task.GetString("column"),
 		"task should move to in_progress after resume --exec")
 
-	// Task 8.3: Verify history is updated with "resumed" action
-	history = getHistoryFromTask(t, task)
-	foundResumed := false
-	var resumedEntry map[string]any
-	for _, entry := range history {
-		if entry["action"] == "resumed" {
-			foundResumed = true
-			resumedEntry = entry
+	// Task 8.3: Verify a "resumed" event is logged
+	events = GetTaskEvents(t, app, task.Id)
+	var resumedEvent *taskevent.Event
+	for i := range events {
+		if events[i].Action == "resumed" {
+			resumedEvent = &events[i]
 			break
 		}
 	}
-	assert.True(t, foundResumed, "history should contain 'resumed' action")
+	assert.NotNil(t, resumedEvent, "events should contain 'resumed' action")
 
-	if resumedEntry != nil {
+	if resumedEvent != nil {
 		// Verify timestamp is present
-		assert.NotEmpty(t, resumedEntry["timestamp"], "resumed entry should have timestamp")
+		assert.False(t, resumedEvent.Timestamp.IsZero(), "resumed event should have timestamp")
 
 		// Verify actor
-		assert.Equal(t, "cli", resumedEntry["actor"], "actor should be 'cli'")
+		assert.Equal(t, "cli", resumedEvent.Actor, "actor should be 'cli'")
 
 		// Verify changes contain column transition
-		changes, ok := resumedEntry["changes"].(map[string]any)
+		changes, ok := resumedEvent.Changes.(map[string]any)
 		if assert.True(t, ok, "changes should be a map") {
 			columnChange, ok := changes["column"].(map[string]any)
 			if assert.True(t, ok, "column change should be a map") {
@@ -6,8 +6,11 @@ import (
 	"strings"
 
 	"github.com/pocketbase/pocketbase"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
 )
 
 var (
@@ -33,6 +36,15 @@ func Register(app *pocketbase.PocketBase) {
 		"Skip HTTP API, use direct database access (faster offline, no real-time updates)")
 	app.RootCmd.PersistentFlags().BoolVarP(&verboseMode, "verbose", "v", false,
 		"Show detailed output including connection method")
+	app.RootCmd.PersistentFlags().StringVar(&serverURL, "server", "",
+		"Run against the server at this URL only, never the local database (remote mode)")
+
+	// Pick local or remote mode, then group the task changes of each
+	// command into one undoable batch
+	app.RootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		setupRemoteMode(cmd)
+		beginUndoBatch(app, cmd, args)
+	}
 
 	// Register all commands
 	app.RootCmd.AddCommand(newAddCmd(app))
@@ -41,6 +53,7 @@ func Register(app *pocketbase.PocketBase) {
 	app.RootCmd.AddCommand(newMoveCmd(app))
 	app.RootCmd.AddCommand(newUpdateCmd(app))
 	app.RootCmd.AddCommand(newDeleteCmd(app))
+	app.RootCmd.AddCommand(newMineCmd(app))
 
 	// Phase 1.5 commands
 	app.RootCmd.AddCommand(newInitCmd(app))
@@ -78,13 +91,96 @@ func Register(app *pocketbase.PocketBase) {
 	// AI workflow commands (Phase 3 - resume flow)
 	app.RootCmd.AddCommand(newResumeCmd(app))
 
+	// AI workflow commands (proposals for human approval)
+	app.RootCmd.AddCommand(newProposeCmd(app))
+	app.RootCmd.AddCommand(newProposalsCmd(app))
+
+	// Time tracking
+	app.RootCmd.AddCommand(newTimeCmd(app))
+
+	// Sprint planning
+	app.RootCmd.AddCommand(newSprintCmd(app))
+
+	// Reports
+	app.RootCmd.AddCommand(newReportCmd(app))
+
+	// Task event log
+	app.RootCmd.AddCommand(newLogCmd(app))
+
+	// Undo and redo
+	app.RootCmd.AddCommand(newUndoCmd(app))
+	app.RootCmd.AddCommand(newRedoCmd(app))
+
+	// Trash
+	app.RootCmd.AddCommand(newTrashCmd(app))
+
+	// Archive
+	app.RootCmd.AddCommand(newArchiveCmd(app))
+	app.RootCmd.AddCommand(newUnarchiveCmd(app))
+
+	// Task templates
+	app.RootCmd.AddCommand(newTemplateCmd(app))
+
+	// Checklists
+	app.RootCmd.AddCommand(newCheckCmd(app))
+
+	// Attachments
+	app.RootCmd.AddCommand(newAttachCmd(app))
+	app.RootCmd.AddCommand(newAttachmentsCmd(app))
+
+	// Task links
+	app.RootCmd.AddCommand(newLinkCmd(app))
+	app.RootCmd.AddCommand(newUnlinkCmd(app))
+	app.RootCmd.AddCommand(newDuplicatesCmd(app))
+
+	// Saved views
+	app.RootCmd.AddCommand(newViewCmd(app))
+
 	// Configuration management
 	app.RootCmd.AddCommand(newConfigCmd(app))
 
+	// API authentication
+	app.RootCmd.AddCommand(newAuthCmd(app))
+	app.RootCmd.AddCommand(newTokenCmd(app))
+
 	// TUI command
 	app.RootCmd.AddCommand(newTuiCmd(app))
 }
 
+// ownBatchCommands start their own undo batches (or none) instead of one
+// per command: the server and TUI make many independent changes.
+var ownBatchCommands = map[string]bool{"serve": true, "tui": true, "undo": true, "redo": true}
+
+// beginUndoBatch starts the undo batch of a command, labelled with the
+// command line and owned by the caller.
+func beginUndoBatch(app *pocketbase.PocketBase, cmd *cobra.Command, args []string) {
+	if ownBatchCommands[cmd.Name()] {
+		return
+	}
+	agentName, _ := cmd.Flags().GetString("agent")
+	label := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if len(args) > 0 {
+		label += " " + strings.Join(args, " ")
+	}
+	cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range slice.GetSlice() {
+				label += fmt.Sprintf(" --%s %s", f.Name, v)
+			}
+			return
+		}
+		if f.Value.Type() == "bool" {
+			label += " --" + f.Name
+			return
+		}
+		label += fmt.Sprintf(" --%s %s", f.Name, f.Value)
+	})
+	undo.Begin(undo.NewBatch(label, resolveCaller(app, agentName).Name))
+}
+
 // getFormatter creates a new output formatter with current flag values.
 // This should be called at the start of each command's RunE function.
 func getFormatter() *output.Formatter {
@@ -99,6 +195,8 @@ const (
 	ExitNotFound         = 3
 	ExitAmbiguous        = 4
 	ExitValidation       = 5
+	ExitPolicyDenied     = 6
+	ExitConflict         = 7
 )
 
 // ValidColumns is the list of valid column values
@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
 	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
 )
 
@@ -71,7 +72,7 @@ or a file path (for some tools).`,
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
@@ -103,7 +104,7 @@ or a file path (for some tools).`,
 			refType := determineRefType(ref)
 
 			// Resolve task
-			task, err := resolver.MustResolve(app, taskRef)
+			task, err := resolveTask(app, taskRef)
 			if err != nil {
 				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 					return out.AmbiguousError(taskRef, ambErr.Matches)
@@ -111,18 +112,21 @@ or a file path (for some tools).`,
 				return out.Error(ExitNotFound, err.Error(), nil)
 			}
 
+			if err := checkTaskPolicy(app, resolveCaller(app, ""), task, policy.ActionUpdate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
 			displayId := getTaskDisplayID(app, task)
 			now := time.Now()
 
 			// Get sessions collection
-			sessionsCollection, err := app.FindCollectionByNameOrId("sessions")
-			if err != nil {
-				return out.Error(ExitGeneralError, fmt.Sprintf("sessions collection not found: %v", err), nil)
+			if _, err := newRecord(app, "sessions"); err != nil {
+				return out.Error(ExitGeneralError, err.Error(), nil)
 			}
 
 			// Execute in transaction
 			var sessionId string
-			err = app.RunInTransaction(func(txApp core.App) error {
+			err = runInTransaction(app, func(txApp core.App) error {
 				// Check for existing session
 				existingSessionData := task.Get("agent_session")
 				if existingSessionData != nil && existingSessionData != "" {
@@ -148,18 +152,18 @@ or a file path (for some tools).`,
 				}
 				task.Set("agent_session", newSession)
 
-				// Add to history
-				addHistoryEntry(task, "session_linked", "agent", map[string]any{
+				// Record the change in the event log
+				addTaskEvent(task, "session_linked", "agent", map[string]any{
 					"tool":        tool,
 					"session_ref": ref,
 				})
 
-				if err := txApp.Save(task); err != nil {
+				if err := saveRecord(txApp, task); err != nil {
 					return fmt.Errorf("failed to update task: %w", err)
 				}
 
 				// Create session record in sessions table
-				sessionRecord := core.NewRecord(sessionsCollection)
+				sessionRecord, _ := newRecord(txApp, "sessions")
 				sessionRecord.Set("task", task.Id)
 				sessionRecord.Set("tool", tool)
 				sessionRecord.Set("external_ref", ref)
@@ -167,7 +171,7 @@ or a file path (for some tools).`,
 				sessionRecord.Set("working_dir", workingDir)
 				sessionRecord.Set("status", "active")
 
-				if err := txApp.Save(sessionRecord); err != nil {
+				if err := saveRecord(txApp, sessionRecord); err != nil {
 					return fmt.Errorf("failed to create session record: %w", err)
 				}
 
@@ -230,14 +234,14 @@ and when it was linked.`,
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
 			taskRef := args[0]
 
 			// Resolve task
-			task, err := resolver.MustResolve(app, taskRef)
+			task, err := resolveTask(app, taskRef)
 			if err != nil {
 				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 					return out.AmbiguousError(taskRef, ambErr.Matches)
@@ -341,14 +345,14 @@ This helps track which tools have worked on a task over time.`,
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
 			taskRef := args[0]
 
 			// Resolve task
-			task, err := resolver.MustResolve(app, taskRef)
+			task, err := resolveTask(app, taskRef)
 			if err != nil {
 				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 					return out.AmbiguousError(taskRef, ambErr.Matches)
@@ -359,9 +363,8 @@ This helps track which tools have worked on a task over time.`,
 			displayId := getTaskDisplayID(app, task)
 
 			// Fetch all sessions for this task
-			records, err := app.FindAllRecords("sessions",
-				dbx.NewExp("task = {:taskId}", dbx.Params{"taskId": task.Id}),
-			)
+			records, err := findRecordsByFilter(app, "sessions", "task = {:taskId}", "+created", 0,
+				dbx.Params{"taskId": task.Id})
 			if err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to fetch sessions: %v", err), nil)
 			}
@@ -456,7 +459,7 @@ Use this when you want to disconnect a session without linking a new one.`,
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
@@ -470,7 +473,7 @@ Use this when you want to disconnect a session without linking a new one.`,
 			}
 
 			// Resolve task
-			task, err := resolver.MustResolve(app, taskRef)
+			task, err := resolveTask(app, taskRef)
 			if err != nil {
 				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 					return out.AmbiguousError(taskRef, ambErr.Matches)
@@ -493,9 +496,13 @@ Use this when you want to disconnect a session without linking a new one.`,
 				return out.Error(ExitValidation, fmt.Sprintf("no session linked to %s", displayId), nil)
 			}
 
+			if err := checkTaskPolicy(app, resolveCaller(app, ""), task, policy.ActionUpdate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
 			now := time.Now()
 
-			err = app.RunInTransaction(func(txApp core.App) error {
+			err = runInTransaction(app, func(txApp core.App) error {
 				// Update session in history table
 				if ref, ok := session["ref"].(string); ok {
 					if err := markSessionStatus(txApp, task.Id, ref, status, now); err != nil {
@@ -507,14 +514,14 @@ Use this when you want to disconnect a session without linking a new one.`,
 				// Clear agent_session on task
 				task.Set("agent_session", nil)
 
-				// Add history entry
-				addHistoryEntry(task, "session_unlinked", "user", map[string]any{
+				// Record the change in the event log
+				addTaskEvent(task, "session_unlinked", "user", map[string]any{
 					"tool":         session["tool"],
 					"session_ref":  session["ref"],
 					"final_status": status,
 				})
 
-				return txApp.Save(task)
+				return saveRecord(txApp, task)
 			})
 
 			if err != nil {
@@ -559,12 +566,12 @@ func determineRefType(ref string) string {
 // Returns an error if the save fails, allowing callers to decide how to handle it.
 func markSessionStatus(app core.App, taskId, externalRef, status string, endTime time.Time) error {
 	// Find the session record
-	records, err := app.FindRecordsByFilter(
+	records, err := findRecordsByFilter(
+		app,
 		"sessions",
 		"task = {:taskId} && external_ref = {:ref} && status = 'active'",
 		"-created",
 		1,
-		0,
 		dbx.Params{"taskId": taskId, "ref": externalRef},
 	)
 	if err != nil || len(records) == 0 {
@@ -576,7 +583,7 @@ func markSessionStatus(app core.App, taskId, externalRef, status string, endTime
 	record.Set("status", status)
 	record.Set("ended_at", endTime)
 
-	if err := app.Save(record); err != nil {
+	if err := saveRecord(app, record); err != nil {
 		return fmt.Errorf("failed to update session status: %w", err)
 	}
 	return nil
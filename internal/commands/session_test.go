@@ -166,8 +166,8 @@ func SimulateSessionLink(t *testing.T, app *pocketbase.PocketBase, task *core.Re
 	}
 	task.Set("agent_session", session)
 
-	// Add to history
-	addHistoryEntry(task, "session_linked", "agent", map[string]any{
+	// Record the change in the event log
+	addTaskEvent(task, "session_linked", "agent", map[string]any{
 		"tool":        tool,
 		"session_ref": ref,
 	})
@@ -526,7 +526,7 @@ func TestSessionUnlink_ClearsAgentSession(t *testing.T) {
 
 	// Simulate unlink
 	task.Set("agent_session", nil)
-	addHistoryEntry(task, "session_unlinked", "user", map[string]any{
+	addTaskEvent(task, "session_unlinked", "user", map[string]any{
 		"tool":         "opencode",
 		"session_ref":  "session-to-unlink",
 		"final_status": "abandoned",
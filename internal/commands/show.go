@@ -8,7 +8,15 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/spf13/cobra"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
+	"github.com/ramtinJ95/EgenSkriven/internal/estimate"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
 	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/sprint"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasklink"
+	"github.com/ramtinJ95/EgenSkriven/internal/timetrack"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
 )
 
 func newShowCmd(app *pocketbase.PocketBase) *cobra.Command {
@@ -31,12 +39,21 @@ Examples:
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
 			ref := args[0]
 
+			if isRemoteMode() {
+				task, subtasks, extras, err := remoteClient().ShowTask(ref)
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+				out.TaskDetailWithExtras(task, subtasks, extras)
+				return nil
+			}
+
 			// Resolve the task
 			resolution, err := resolver.ResolveTask(app, ref)
 			if err != nil {
@@ -52,21 +69,51 @@ Examples:
 			}
 
 			task := resolution.Task
-
-			// Query for sub-tasks where parent = task.Id
-			var subtasks []*core.Record
-			collection, err := app.FindCollectionByNameOrId("tasks")
-			if err == nil {
-				query := app.RecordQuery(collection).
-					AndWhere(dbx.NewExp("parent = {:parent}", dbx.Params{"parent": task.Id})).
-					OrderBy("position ASC")
-				query.All(&subtasks)
-			}
-
-			out.TaskDetailWithSubtasks(task, subtasks)
+			subtasks, extras := taskDetail(app, task)
+			out.TaskDetailWithExtras(task, subtasks, extras)
 			return nil
 		},
 	}
 
 	return cmd
 }
+
+// taskDetail returns the sub-tasks of a task and the related data shown
+// with it, best-effort.
+func taskDetail(app *pocketbase.PocketBase, task *core.Record) ([]*core.Record, output.TaskExtras) {
+	// Query for sub-tasks where parent = task.Id
+	var subtasks []*core.Record
+	collection, err := app.FindCollectionByNameOrId("tasks")
+	if err == nil {
+		query := app.RecordQuery(collection).
+			AndWhere(dbx.NewExp("parent = {:parent}", dbx.Params{"parent": task.Id})).
+			AndWhere(trash.Exclude(app, "tasks")).
+			OrderBy("position ASC")
+		query.All(&subtasks)
+	}
+
+	// Logged time (best-effort, shown when entries exist)
+	var extras output.TaskExtras
+	if summary, err := timetrack.TaskSummary(app, task.Id); err == nil {
+		extras.TimeLogged = &summary
+	}
+	if sprintID := task.GetString("sprint"); sprintID != "" {
+		if sprintRecord, err := app.FindRecordById(sprint.CollectionName, sprintID); err == nil {
+			extras.SprintName = sprintRecord.GetString("name")
+		}
+	}
+	totals := estimate.ForTask(app, task)
+	extras.Estimate = &totals
+	extras.EstimateUnit = estimate.BoardUnit(app, task.GetString("board"))
+	if events, err := taskevent.ForTask(app, task.Id); err == nil {
+		extras.History = events
+	}
+	if files, err := attachment.ForTask(app, task); err == nil {
+		extras.Attachments = files
+	}
+	if links, err := tasklink.ForTask(app, task); err == nil {
+		extras.Links = links
+	}
+
+	return subtasks, extras
+}
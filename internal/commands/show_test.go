@@ -51,7 +51,6 @@ func setupTasksWithParentField(t *testing.T, app *pocketbase.PocketBase) *core.C
 		Values:   []string{"user", "agent", "cli"},
 	})
 	collection.Fields.Add(&core.TextField{Name: "created_by_agent"})
-	collection.Fields.Add(&core.JSONField{Name: "history"})
 	collection.Fields.Add(&core.DateField{Name: "due_date"})
 
 	if err := app.Save(collection); err != nil {
@@ -91,7 +90,6 @@ func createShowTestTask(t *testing.T, app *pocketbase.PocketBase, title string,
 	record.Set("labels", []string{})
 	record.Set("blocked_by", []string{})
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 
 	require.NoError(t, app.Save(record))
 	return record
@@ -113,7 +111,6 @@ func createShowTestSubTask(t *testing.T, app *pocketbase.PocketBase, title strin
 	record.Set("labels", []string{})
 	record.Set("blocked_by", []string{})
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 	record.Set("parent", parentID)
 
 	require.NoError(t, app.Save(record))
@@ -0,0 +1,1074 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/sprint"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+func newSprintCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sprint",
+		Short: "Plan and track sprints",
+		Long: `Manage sprints (time-boxed iterations) for a board.
+
+A sprint moves through three states: planned -> active -> closed.
+Each board has at most one active sprint, referred to as "current".
+Sprints can be referenced by ID, ID prefix, name, or "current".`,
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newSprintListCmd(app))
+	cmd.AddCommand(newSprintCreateCmd(app))
+	cmd.AddCommand(newSprintStartCmd(app))
+	cmd.AddCommand(newSprintCloseCmd(app))
+	cmd.AddCommand(newSprintShowCmd(app))
+	cmd.AddCommand(newSprintAddCmd(app))
+	cmd.AddCommand(newSprintRemoveCmd(app))
+
+	return cmd
+}
+
+// ========== Sprint List ==========
+
+func newSprintListCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var boardRef string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List sprints and velocity",
+		Long: `List sprints for a board with task counts.
+
+Velocity is the number of tasks completed in each closed sprint.`,
+		Example: `  egenskriven sprint list
+  egenskriven sprint list --board WRK --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+
+			result, err := sprintList(app, boardRecord)
+			if err != nil {
+				return sprintFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			fmt.Printf("SPRINTS (%s)\n", boardRecord.GetString("name"))
+			fmt.Println(strings.Repeat("-", 40))
+
+			var sprints []map[string]any
+			resultValue(result, "sprints", &sprints)
+			if len(sprints) == 0 {
+				fmt.Printf("No sprints found for board '%s'.\n", boardRecord.GetString("name"))
+				fmt.Printf("Create one with: egenskriven sprint create \"Sprint 1\"\n")
+				return nil
+			}
+
+			closed := 0
+			for _, s := range sprints {
+				taskCount := resultInt(s, "task_count")
+				fmt.Printf("  [%s] %-20s %-8s %s  %d/%d done\n",
+					shortID(resultString(s, "id")),
+					truncateString(resultString(s, "name"), 20),
+					resultString(s, "state"),
+					formatSprintRange(resultString(s, "start_date"), resultString(s, "end_date")),
+					taskCount-resultInt(s, "unfinished"), taskCount,
+				)
+				if resultString(s, "state") == sprint.StateClosed {
+					closed++
+				}
+			}
+
+			if closed > 0 {
+				var average float64
+				resultValue(result, "average_velocity", &average)
+				fmt.Printf("\nVelocity (last %d closed): %.1f tasks/sprint\n", min(closed, 3), average)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+
+	return cmd
+}
+
+// ========== Sprint Create ==========
+
+func newSprintCreateCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef string
+		start    string
+		end      string
+		goal     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a planned sprint",
+		Long: `Create a new sprint in the planned state.
+
+The start date defaults to today, or to the day after the board's last open
+sprint ends. The end date defaults to two weeks after the start.`,
+		Example: `  egenskriven sprint create "Sprint 12"
+  egenskriven sprint create "Sprint 13" --start 2025-02-03 --end 2025-02-14 --goal "Ship auth"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+
+			caller := resolveCaller(app, "")
+			if err := checkPolicy(app, caller, boardRecord.Id, policy.ActionCreate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
+			req := sprintRequest{Board: boardRecord.Id, Name: args[0], Goal: goal, Agent: requestAgent(caller)}
+			if start != "" {
+				if req.Start, err = parseDate(start); err != nil {
+					return out.Error(ExitValidation, fmt.Sprintf("invalid --start date: %v", err), nil)
+				}
+			}
+			if end != "" {
+				if req.End, err = parseDate(end); err != nil {
+					return out.Error(ExitValidation, fmt.Sprintf("invalid --end date: %v", err), nil)
+				}
+			}
+
+			var result map[string]any
+			if isRemoteMode() {
+				result, err = remoteClient().CreateSprint(req)
+			} else {
+				result, err = createSprint(app, boardRecord, req)
+			}
+			if err != nil {
+				return sprintFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			out.Success(fmt.Sprintf("Created sprint: %s [%s] %s (board: %s)",
+				args[0], shortID(resultString(result, "id")),
+				formatSprintRange(resultString(result, "start_date"), resultString(result, "end_date")),
+				boardRecord.GetString("name")))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+	cmd.Flags().StringVar(&start, "start", "", "Start date (default: today)")
+	cmd.Flags().StringVar(&end, "end", "", "End date, inclusive (default: two weeks after start)")
+	cmd.Flags().StringVarP(&goal, "goal", "g", "", "Sprint goal")
+
+	return cmd
+}
+
+// ========== Sprint Start ==========
+
+func newSprintStartCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var boardRef string
+
+	cmd := &cobra.Command{
+		Use:   "start [sprint]",
+		Short: "Start a sprint",
+		Long: `Make a planned sprint the board's active sprint.
+
+Without an argument, the earliest planned sprint is started.
+Fails if another sprint is already active.`,
+		Example: `  egenskriven sprint start
+  egenskriven sprint start "Sprint 12"`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+
+			caller := resolveCaller(app, "")
+			if err := checkPolicy(app, caller, boardRecord.Id, policy.ActionUpdate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
+			ref := ""
+			if len(args) == 1 {
+				ref = args[0]
+			}
+
+			var result map[string]any
+			if isRemoteMode() {
+				if ref == "" {
+					ref, err = remoteNextSprint(boardRecord.Id)
+				}
+				if err == nil {
+					result, err = remoteClient().SprintAction(ref, "start", sprintRequest{
+						Board: boardRecord.Id,
+						Agent: requestAgent(caller),
+					})
+				}
+			} else {
+				result, err = startSprint(app, boardRecord, ref)
+			}
+			if err != nil {
+				return sprintFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			out.Success(fmt.Sprintf("Started sprint: %s %s", resultString(result, "name"),
+				formatSprintRange(resultString(result, "start_date"), resultString(result, "end_date"))))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+
+	return cmd
+}
+
+// ========== Sprint Close ==========
+
+func newSprintCloseCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef string
+		carryTo  string
+		carry    bool
+		noCarry  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "close [sprint]",
+		Short: "Close a sprint",
+		Long: `Close a sprint (default: the current sprint).
+
+Unfinished tasks can be carried over into another sprint:
+  --carry           carry into the next planned sprint
+  --carry-to <ref>  carry into a specific sprint
+  --no-carry        leave them in the closed sprint
+
+Without these flags you are asked interactively. In JSON or quiet mode
+unfinished tasks are left in the closed sprint.`,
+		Example: `  egenskriven sprint close
+  egenskriven sprint close --carry
+  egenskriven sprint close "Sprint 12" --carry-to "Sprint 13"`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			if noCarry && (carry || carryTo != "") {
+				return out.Error(ExitValidation, "--no-carry cannot be combined with --carry or --carry-to", nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+
+			caller := resolveCaller(app, "")
+			if err := checkPolicy(app, caller, boardRecord.Id, policy.ActionUpdate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
+			ref := sprint.RefCurrent
+			if len(args) == 1 {
+				ref = args[0]
+			}
+
+			// Ask whether to carry unfinished tasks into the next sprint
+			if !noCarry && !carry && carryTo == "" && !out.JSON && !out.Quiet {
+				if carryTo, err = promptSprintCarry(app, boardRecord, ref); err != nil {
+					return sprintFailed(out, err)
+				}
+			}
+
+			var result map[string]any
+			if isRemoteMode() {
+				result, err = remoteClient().SprintAction(ref, "close", sprintRequest{
+					Board:   boardRecord.Id,
+					Carry:   carry,
+					CarryTo: carryTo,
+					Agent:   requestAgent(caller),
+				})
+			} else {
+				result, err = closeSprint(app, boardRecord, ref, carryTo, carry)
+			}
+			if err != nil {
+				return sprintFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			closedSprint := resultMap(result, "sprint")
+			completed, unfinished := resultInt(result, "completed"), resultInt(result, "unfinished")
+			var carried []string
+			resultValue(result, "carried", &carried)
+
+			out.Success(fmt.Sprintf("Closed sprint: %s (%d/%d tasks done)",
+				resultString(closedSprint, "name"), completed, completed+unfinished))
+			if len(carried) > 0 {
+				fmt.Printf("  Carried %d task(s) into '%s': %s\n",
+					len(carried), resultString(resultMap(result, "carried_to"), "name"), strings.Join(carried, ", "))
+			} else if unfinished > 0 {
+				fmt.Printf("  %d unfinished task(s) left in the closed sprint\n", unfinished)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+	cmd.Flags().BoolVar(&carry, "carry", false, "Carry unfinished tasks into the next planned sprint")
+	cmd.Flags().StringVar(&carryTo, "carry-to", "", "Carry unfinished tasks into this sprint")
+	cmd.Flags().BoolVar(&noCarry, "no-carry", false, "Leave unfinished tasks in the closed sprint")
+
+	return cmd
+}
+
+// promptSprintCarry asks whether to carry the unfinished tasks of the
+// sprint being closed into the next planned sprint, and returns the ID of
+// that sprint if so ("" otherwise).
+func promptSprintCarry(app *pocketbase.PocketBase, boardRecord *core.Record, ref string) (string, error) {
+	var shown, listed map[string]any
+	var err error
+	if isRemoteMode() {
+		if shown, err = remoteClient().ShowSprint(boardRecord.Id, ref); err == nil {
+			listed, err = remoteClient().Sprints(boardRecord.Id)
+		}
+	} else {
+		if shown, err = showSprint(app, boardRecord, ref); err == nil {
+			listed, err = sprintList(app, boardRecord)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	current := resultMap(shown, "sprint")
+	unfinished := resultInt(shown, "task_count") - resultInt(shown, "completed")
+	if unfinished == 0 || resultString(current, "state") == sprint.StateClosed {
+		return "", nil
+	}
+
+	// The next sprint is the earliest planned one, sprints being listed
+	// by start date
+	var sprints []map[string]any
+	resultValue(listed, "sprints", &sprints)
+	for _, next := range sprints {
+		if resultString(next, "id") == resultString(current, "id") || resultString(next, "state") != sprint.StatePlanned {
+			continue
+		}
+		fmt.Printf("%d unfinished task(s) in '%s'. Carry them into '%s'? [y/N]: ",
+			unfinished, resultString(current, "name"), resultString(next, "name"))
+		var response string
+		fmt.Scanln(&response)
+		if strings.EqualFold(response, "y") || strings.EqualFold(response, "yes") {
+			return resultString(next, "id"), nil
+		}
+		break
+	}
+	return "", nil
+}
+
+// ========== Sprint Show ==========
+
+func newSprintShowCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var boardRef string
+
+	cmd := &cobra.Command{
+		Use:   "show [sprint]",
+		Short: "Show sprint details and burndown",
+		Long: `Show a sprint (default: the current sprint) with its tasks and burndown.
+
+Burndown counts the tasks not yet done at the end of each sprint day,
+computed from the task event log.`,
+		Example: `  egenskriven sprint show
+  egenskriven sprint show "Sprint 12" --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+
+			ref := sprint.RefCurrent
+			if len(args) == 1 {
+				ref = args[0]
+			}
+
+			var result map[string]any
+			if isRemoteMode() {
+				result, err = remoteClient().ShowSprint(boardRecord.Id, ref)
+			} else {
+				result, err = showSprint(app, boardRecord, ref)
+			}
+			if err != nil {
+				return sprintFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			record := resultMap(result, "sprint")
+			taskCount := resultInt(result, "task_count")
+			fmt.Printf("Sprint: %s [%s]\n", resultString(record, "name"), shortID(resultString(record, "id")))
+			fmt.Printf("State:  %s\n", resultString(record, "state"))
+			fmt.Printf("Dates:  %s\n", formatSprintRange(resultString(record, "start_date"), resultString(record, "end_date")))
+			if goal := resultString(record, "goal"); goal != "" {
+				fmt.Printf("Goal:   %s\n", goal)
+			}
+			fmt.Printf("Done:   %d/%d tasks\n", resultInt(result, "completed"), taskCount)
+
+			var tasks []map[string]any
+			resultValue(result, "tasks", &tasks)
+			fmt.Printf("\nTasks (%d):\n", taskCount)
+			if len(tasks) == 0 {
+				fmt.Println("  (no tasks)")
+			}
+			for _, task := range tasks {
+				fmt.Printf("  [%s] %s (%s, %s)\n",
+					resultString(task, "display_id"),
+					resultString(task, "title"),
+					resultString(task, "column"),
+					resultString(task, "priority"),
+				)
+			}
+
+			var burndown []sprint.BurndownPoint
+			resultValue(result, "burndown", &burndown)
+			if len(burndown) > 0 && len(tasks) > 0 {
+				fmt.Println("\nBurndown:")
+				for _, p := range burndown {
+					fmt.Printf("  %s  %3d remaining  %s\n", p.Date, p.Remaining, strings.Repeat("#", p.Remaining))
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+
+	return cmd
+}
+
+// ========== Sprint Add ==========
+
+func newSprintAddCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <sprint> <task>...",
+		Short: "Add tasks to a sprint",
+		Long: `Add one or more tasks to a sprint.
+
+The sprint is looked up on each task's board, so "current" refers to the
+active sprint of that board. A task can be in only one sprint; adding it
+to another sprint moves it.`,
+		Example: `  egenskriven sprint add current WRK-1 WRK-2
+  egenskriven sprint add "Sprint 13" WRK-5`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			tasks, caller, err := resolveSprintTasks(app, out, args[1:], true)
+			if err != nil {
+				return err
+			}
+
+			added := make([]string, 0, len(tasks))
+			var record map[string]any
+			for _, task := range tasks {
+				result, err := moveTaskToSprint(app, caller, task, args[0])
+				if err != nil {
+					return sprintFailed(out, err)
+				}
+				added = append(added, resultString(result, "display_id"))
+				record = resultMap(result, "sprint")
+			}
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{
+					"success": true,
+					"sprint":  record,
+					"added":   added,
+				})
+				return nil
+			}
+
+			out.Success(fmt.Sprintf("Added %s to sprint '%s'", strings.Join(added, ", "), resultString(record, "name")))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// ========== Sprint Remove ==========
+
+func newSprintRemoveCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <task>...",
+		Short: "Remove tasks from their sprint",
+		Example: `  egenskriven sprint remove WRK-1
+  egenskriven sprint remove WRK-1 WRK-2`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			tasks, caller, err := resolveSprintTasks(app, out, args, false)
+			if err != nil {
+				return err
+			}
+
+			removed := make([]string, 0, len(tasks))
+			for _, task := range tasks {
+				if task.GetString("sprint") == "" {
+					continue
+				}
+				result, err := moveTaskToSprint(app, caller, task, "")
+				if err != nil {
+					return sprintFailed(out, err)
+				}
+				removed = append(removed, resultString(result, "display_id"))
+			}
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{
+					"success": true,
+					"removed": removed,
+				})
+				return nil
+			}
+
+			if len(removed) == 0 {
+				out.Success("No tasks were in a sprint")
+				return nil
+			}
+			out.Success(fmt.Sprintf("Removed %s from sprint", strings.Join(removed, ", ")))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// resolveSprintTasks resolves the task references of sprint add and
+// remove, checking that the caller may update them. Tasks outside a
+// sprint need no check to be removed from one.
+func resolveSprintTasks(app *pocketbase.PocketBase, out *output.Formatter, refs []string, adding bool) ([]*core.Record, policy.Actor, error) {
+	caller := resolveCaller(app, "")
+	tasks := make([]*core.Record, 0, len(refs))
+	for _, ref := range refs {
+		task, err := resolveTask(app, ref)
+		if err != nil {
+			if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+				return nil, caller, out.AmbiguousError(ref, ambErr.Matches)
+			}
+			return nil, caller, out.Error(ExitNotFound, err.Error(), nil)
+		}
+		if adding || task.GetString("sprint") != "" {
+			if err := checkTaskPolicy(app, caller, task, policy.ActionUpdate).Err(); err != nil {
+				return nil, caller, policyDenied(out, err)
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, caller, nil
+}
+
+// remoteNextSprint returns the ID of the earliest planned sprint of a
+// board on the server, which sprint start starts by default.
+func remoteNextSprint(boardID string) (string, error) {
+	listed, err := remoteClient().Sprints(boardID)
+	if err != nil {
+		return "", err
+	}
+	var sprints []map[string]any
+	resultValue(listed, "sprints", &sprints)
+	for _, s := range sprints {
+		if resultString(s, "state") == sprint.StatePlanned {
+			return resultString(s, "id"), nil
+		}
+	}
+	return "", &commandError{
+		code:       ExitNotFound,
+		message:    "no planned sprint to start",
+		suggestion: "Create one with: egenskriven sprint create \"Sprint 1\"",
+	}
+}
+
+// moveTaskToSprint moves a task into the sprint ref names on its board, or
+// out of its sprint with an empty ref, on the server in remote mode.
+func moveTaskToSprint(app *pocketbase.PocketBase, caller policy.Actor, task *core.Record, ref string) (map[string]any, error) {
+	if isRemoteMode() {
+		return remoteClient().SetTaskSprint(task.Id, taskSprintRequest{Sprint: ref, Agent: requestAgent(caller)})
+	}
+	return setSprint(app, task, ref)
+}
+
+// sprintFailed reports an error of a sprint operation, run locally or on
+// the server.
+func sprintFailed(out *output.Formatter, err error) error {
+	if isRemoteMode() {
+		return remoteFailed(out, err)
+	}
+	return commandFailed(out, err)
+}
+
+// ========== Sprint Operations ==========
+//
+// Shared by the sprint commands and the /sprints routes. Each returns what
+// the command prints with --json.
+
+// sprintList returns the sprints of a board with their task counts and the
+// board's velocity.
+func sprintList(app *pocketbase.PocketBase, boardRecord *core.Record) (map[string]any, error) {
+	records, err := sprint.ForBoard(app, boardRecord.Id)
+	if err != nil {
+		return nil, &commandError{
+			code:       ExitGeneralError,
+			message:    fmt.Sprintf("failed to list sprints: %v", err),
+			suggestion: "Run 'egenskriven serve' first to initialize the database",
+		}
+	}
+
+	velocity, err := sprint.Velocity(app, boardRecord.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute velocity: %w", err)
+	}
+
+	sprints := make([]map[string]any, 0, len(records))
+	for _, r := range records {
+		tasks, _ := sprint.Tasks(app, r.Id)
+		m := sprintToMap(r)
+		m["task_count"] = len(tasks)
+		m["unfinished"] = len(sprint.Unfinished(tasks))
+		sprints = append(sprints, m)
+	}
+	return map[string]any{
+		"sprints":          sprints,
+		"count":            len(sprints),
+		"board":            boardRecord.Id,
+		"board_name":       boardRecord.GetString("name"),
+		"velocity":         velocity,
+		"average_velocity": sprint.AverageVelocity(velocity, 3),
+	}, nil
+}
+
+// createSprint creates a planned sprint. req.Start and req.End are
+// YYYY-MM-DD dates; an empty start defaults to today or the day after the
+// board's last open sprint ends, an empty end to two weeks after the start.
+func createSprint(app *pocketbase.PocketBase, boardRecord *core.Record, req sprintRequest) (map[string]any, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, newCommandError(ExitValidation, "sprint name is required")
+	}
+
+	startDate := req.Start
+	if startDate == "" {
+		startDate = time.Now().UTC().Format("2006-01-02")
+		if existing, err := sprint.ForBoard(app, boardRecord.Id); err == nil {
+			for _, r := range existing {
+				if r.GetString("state") == sprint.StateClosed {
+					continue
+				}
+				_, lastEnd := sprint.Window(r)
+				if next := lastEnd.Add(time.Nanosecond).Format("2006-01-02"); next > startDate {
+					startDate = next
+				}
+			}
+		}
+	}
+	startTime, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, newCommandError(ExitValidation, "invalid start date: %s", startDate)
+	}
+
+	endDate := req.End
+	if endDate == "" {
+		endDate = startTime.Add(sprint.DefaultLength).AddDate(0, 0, -1).Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", endDate); err != nil {
+		return nil, newCommandError(ExitValidation, "invalid end date: %s", endDate)
+	}
+	if endDate < startDate {
+		return nil, newCommandError(ExitValidation, "end date must not be before start date")
+	}
+
+	collection, err := app.FindCollectionByNameOrId(sprint.CollectionName)
+	if err != nil {
+		return nil, &commandError{
+			code:       ExitGeneralError,
+			message:    "sprints collection not found",
+			suggestion: "Run 'egenskriven serve' first to initialize the database",
+		}
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("board", boardRecord.Id)
+	record.Set("name", req.Name)
+	record.Set("start_date", startDate)
+	record.Set("end_date", endDate)
+	record.Set("goal", req.Goal)
+	record.Set("state", sprint.StatePlanned)
+
+	if err := app.Save(record); err != nil {
+		return nil, fmt.Errorf("failed to create sprint: %w", err)
+	}
+	return sprintToMap(record), nil
+}
+
+// startSprint makes a sprint the board's active sprint; an empty ref
+// starts the earliest planned sprint.
+func startSprint(app *pocketbase.PocketBase, boardRecord *core.Record, ref string) (map[string]any, error) {
+	var record *core.Record
+	if ref != "" {
+		var err error
+		if record, err = sprint.Resolve(app, boardRecord.Id, ref); err != nil {
+			return nil, newCommandError(ExitNotFound, "%s", err.Error())
+		}
+	} else {
+		record = sprint.NextPlanned(app, boardRecord.Id)
+		if record == nil {
+			return nil, &commandError{
+				code:       ExitNotFound,
+				message:    "no planned sprint to start",
+				suggestion: "Create one with: egenskriven sprint create \"Sprint 1\"",
+			}
+		}
+	}
+
+	if err := sprint.Start(app, record); err != nil {
+		if errors.Is(err, sprint.ErrAlreadyActive) {
+			return nil, &commandError{
+				code:       ExitValidation,
+				message:    err.Error(),
+				suggestion: "Close it first with: egenskriven sprint close",
+			}
+		}
+		return nil, newCommandError(ExitValidation, "%s", err.Error())
+	}
+	return sprintToMap(record), nil
+}
+
+// closeSprint closes a sprint, carrying its unfinished tasks into the
+// sprint carryTo names, or with carry into the next planned sprint.
+func closeSprint(app *pocketbase.PocketBase, boardRecord *core.Record, ref, carryTo string, carry bool) (map[string]any, error) {
+	record, err := sprint.Resolve(app, boardRecord.Id, ref)
+	if err != nil {
+		return nil, newCommandError(ExitNotFound, "%s", err.Error())
+	}
+	if record.GetString("state") == sprint.StateClosed {
+		return nil, newCommandError(ExitValidation, "sprint '%s' is already closed", record.GetString("name"))
+	}
+
+	tasks, err := sprint.Tasks(app, record.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sprint tasks: %w", err)
+	}
+	unfinished := sprint.Unfinished(tasks)
+
+	// Determine the carry-over target
+	var target *core.Record
+	switch {
+	case carryTo != "":
+		target, err = sprint.Resolve(app, boardRecord.Id, carryTo)
+		if err != nil {
+			return nil, newCommandError(ExitNotFound, "%s", err.Error())
+		}
+	case carry:
+		target = nextSprintAfter(app, boardRecord.Id, record)
+		if target == nil && len(unfinished) > 0 {
+			return nil, &commandError{
+				code:       ExitValidation,
+				message:    "no planned sprint to carry tasks into",
+				suggestion: "Create one with: egenskriven sprint create \"Next sprint\"",
+			}
+		}
+	}
+	if target != nil && target.Id == record.Id {
+		return nil, newCommandError(ExitValidation, "cannot carry tasks into the sprint being closed")
+	}
+	if target != nil && target.GetString("state") == sprint.StateClosed {
+		return nil, newCommandError(ExitValidation, "cannot carry tasks into closed sprint '%s'", target.GetString("name"))
+	}
+
+	if err := sprint.Close(app, record, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to close sprint: %w", err)
+	}
+
+	carried := make([]string, 0)
+	if target != nil {
+		for _, task := range unfinished {
+			if err := setTaskSprint(app, task, target.Id); err != nil {
+				return nil, fmt.Errorf("failed to carry over task: %w", err)
+			}
+			carried = append(carried, getTaskDisplayID(app, task))
+		}
+	}
+
+	result := map[string]any{
+		"sprint":     sprintToMap(record),
+		"completed":  len(tasks) - len(unfinished),
+		"unfinished": len(unfinished),
+		"carried":    carried,
+		"carried_to": nil,
+	}
+	if target != nil {
+		result["carried_to"] = sprintToMap(target)
+	}
+	return result, nil
+}
+
+// showSprint returns a sprint with its tasks and burndown.
+func showSprint(app *pocketbase.PocketBase, boardRecord *core.Record, ref string) (map[string]any, error) {
+	record, err := sprint.Resolve(app, boardRecord.Id, ref)
+	if err != nil {
+		return nil, &commandError{
+			code:       ExitNotFound,
+			message:    err.Error(),
+			suggestion: "Use 'egenskriven sprint list' to see available sprints",
+		}
+	}
+
+	tasks, err := sprint.Tasks(app, record.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sprint tasks: %w", err)
+	}
+	sortTasksByPosition(tasks)
+
+	start, end := sprint.Window(record)
+	now := time.Now()
+	if closedAt := record.GetDateTime("closed_at").Time(); !closedAt.IsZero() && closedAt.Before(now) {
+		now = closedAt
+	}
+	timelines, err := sprint.Timelines(app, tasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task events: %w", err)
+	}
+
+	taskList := make([]map[string]any, 0, len(tasks))
+	for _, task := range tasks {
+		taskList = append(taskList, map[string]any{
+			"id":         task.Id,
+			"display_id": getTaskDisplayID(app, task),
+			"title":      task.GetString("title"),
+			"column":     task.GetString("column"),
+			"priority":   task.GetString("priority"),
+		})
+	}
+	return map[string]any{
+		"sprint":     sprintToMap(record),
+		"tasks":      taskList,
+		"task_count": len(tasks),
+		"completed":  len(tasks) - len(sprint.Unfinished(tasks)),
+		"burndown":   sprint.Burndown(timelines, start, end, now),
+	}, nil
+}
+
+// setSprint moves a task into the sprint ref names on the task's board, or
+// out of its sprint with an empty ref.
+func setSprint(app *pocketbase.PocketBase, task *core.Record, ref string) (map[string]any, error) {
+	result := map[string]any{
+		"id":         task.Id,
+		"display_id": getTaskDisplayID(app, task),
+		"sprint":     nil,
+	}
+	if ref == "" {
+		if err := setTaskSprint(app, task, ""); err != nil {
+			return nil, fmt.Errorf("failed to update task: %w", err)
+		}
+		return result, nil
+	}
+
+	boardID := task.GetString("board")
+	if boardID == "" {
+		return nil, newCommandError(ExitValidation,
+			"task %s has no board and cannot join a sprint", getTaskDisplayID(app, task))
+	}
+	record, err := sprint.Resolve(app, boardID, ref)
+	if err != nil {
+		return nil, newCommandError(ExitNotFound, "%s", err.Error())
+	}
+	if record.GetString("state") == sprint.StateClosed {
+		return nil, newCommandError(ExitValidation, "sprint '%s' is closed", record.GetString("name"))
+	}
+
+	if err := setTaskSprint(app, task, record.Id); err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+	result["sprint"] = sprintToMap(record)
+	return result, nil
+}
+
+// ========== Helper Functions ==========
+
+// setTaskSprint moves a task into a sprint ("" removes it) and records the
+// change in the task event log.
+func setTaskSprint(app *pocketbase.PocketBase, task *core.Record, sprintID string) error {
+	from := task.GetString("sprint")
+	if from == sprintID {
+		return nil
+	}
+	task.Set("sprint", sprintID)
+	addTaskEvent(task, "updated", "", map[string]any{
+		"sprint": map[string]any{"from": from, "to": sprintID},
+	})
+	return app.Save(task)
+}
+
+// nextSprintAfter returns the earliest planned sprint other than current.
+func nextSprintAfter(app *pocketbase.PocketBase, boardID string, current *core.Record) *core.Record {
+	records, err := sprint.ForBoard(app, boardID)
+	if err != nil {
+		return nil
+	}
+	for _, r := range records {
+		if r.Id != current.Id && r.GetString("state") == sprint.StatePlanned {
+			return r
+		}
+	}
+	return nil
+}
+
+// resolveSprintFilter resolves a --sprint value for task filtering.
+// Returns the sprint IDs to match; "none" returns an empty slice with
+// matchNone set. "current" matches the active sprint on boardID, or on
+// every board when boardID is empty.
+func resolveSprintFilter(app *pocketbase.PocketBase, boardID, ref string) (ids []string, matchNone bool, err error) {
+	if strings.EqualFold(ref, "none") {
+		return nil, true, nil
+	}
+
+	if boardID != "" {
+		record, err := sprint.Resolve(app, boardID, ref)
+		if err != nil {
+			return nil, false, err
+		}
+		return []string{record.Id}, false, nil
+	}
+
+	if strings.EqualFold(ref, sprint.RefCurrent) {
+		records, err := app.FindAllRecords(sprint.CollectionName)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, r := range records {
+			if r.GetString("state") == sprint.StateActive {
+				ids = append(ids, r.Id)
+			}
+		}
+		if len(ids) == 0 {
+			return nil, false, sprint.ErrNoActiveSprint
+		}
+		return ids, false, nil
+	}
+
+	// Search every board by ID or name
+	boards, err := app.FindAllRecords("boards", trash.Exclude(app, "boards"))
+	if err != nil {
+		return nil, false, err
+	}
+	for _, b := range boards {
+		if record, err := sprint.Resolve(app, b.Id, ref); err == nil {
+			ids = append(ids, record.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, false, fmt.Errorf("no sprint found matching: %s", ref)
+	}
+	return ids, false, nil
+}
+
+// sprintToMap converts a sprint record to a JSON-friendly map.
+func sprintToMap(record *core.Record) map[string]any {
+	m := map[string]any{
+		"id":         record.Id,
+		"name":       record.GetString("name"),
+		"board":      record.GetString("board"),
+		"state":      record.GetString("state"),
+		"goal":       record.GetString("goal"),
+		"start_date": formatSprintDate(record, "start_date"),
+		"end_date":   formatSprintDate(record, "end_date"),
+	}
+	if closedAt := record.GetDateTime("closed_at"); !closedAt.IsZero() {
+		m["closed_at"] = closedAt.Time().UTC().Format(time.RFC3339)
+	}
+	return m
+}
+
+// formatSprintDate returns a sprint date field as YYYY-MM-DD, or "".
+func formatSprintDate(record *core.Record, field string) string {
+	dt := record.GetDateTime(field)
+	if dt.IsZero() {
+		return ""
+	}
+	return dt.Time().UTC().Format("2006-01-02")
+}
+
+// formatSprintRange renders a range of YYYY-MM-DD sprint dates for display.
+func formatSprintRange(start, end string) string {
+	if start == "" && end == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s to %s", start, end)
+}
@@ -3,10 +3,13 @@ package commands
 import (
 	"fmt"
 	"sort"
+	"strconv"
 
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
 )
 
 // Suggestion represents a task suggestion with reasoning.
@@ -21,7 +24,10 @@ type SuggestResponse struct {
 }
 
 func newSuggestCmd(app *pocketbase.PocketBase) *cobra.Command {
-	var limit int
+	var (
+		limit   int
+		forName string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "suggest",
@@ -34,26 +40,52 @@ Suggestion priority:
 3. High priority unblocked tasks
 4. Tasks that unblock the most other tasks
 
+Within each group, tasks that unblock others come first, and smaller
+estimates are preferred so quick wins that unblock work surface early.
+
+With --for, only tasks assigned to that person or unassigned are
+suggested, and their own tasks come first.
+
 Examples:
   egenskriven suggest
   egenskriven suggest --json
-  egenskriven suggest --json --limit 3`,
+  egenskriven suggest --json --limit 3
+  egenskriven suggest --for me
+  egenskriven suggest --for claude --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
-			// Get all tasks
-			tasks, err := app.FindAllRecords("tasks")
-			if err != nil {
-				return out.Error(ExitGeneralError, fmt.Sprintf("failed to list tasks: %v", err), nil)
+			// Resolve who the suggestions are for ("me" expands to the current user)
+			if forName != "" {
+				forName = resolveAssigneeName(forName)
+				if forName == "" {
+					return out.ErrorWithSuggestion(ExitValidation, "cannot determine who 'me' is",
+						"Set defaults.author in ~/.config/egenskriven/config.json", nil)
+				}
 			}
 
-			// Build suggestions
-			suggestions := buildSuggestions(tasks, limit)
+			var suggestions []Suggestion
+			if isRemoteMode() {
+				resp, err := remoteClient().Suggest(limit, forName)
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+				suggestions = resp.Suggestions
+			} else {
+				// Get all tasks
+				tasks, err := app.FindAllRecords("tasks", trash.Exclude(app, "tasks"))
+				if err != nil {
+					return out.Error(ExitGeneralError, fmt.Sprintf("failed to list tasks: %v", err), nil)
+				}
+
+				// Build suggestions
+				suggestions = buildSuggestions(tasks, limit, forName)
+			}
 
 			if jsonOutput {
 				out.WriteJSON(SuggestResponse{Suggestions: suggestions})
@@ -66,11 +98,15 @@ Examples:
 	}
 
 	cmd.Flags().IntVarP(&limit, "limit", "l", 5, "Maximum number of suggestions")
+	cmd.Flags().StringVar(&forName, "for", "", "Suggest for an assignee (me or <name>)")
 
 	return cmd
 }
 
-func buildSuggestions(tasks []*core.Record, limit int) []Suggestion {
+// buildSuggestions ranks tasks to work on next. When forName is set, only
+// tasks assigned to forName or unassigned are suggested, with their own
+// tasks first within each group.
+func buildSuggestions(tasks []*core.Record, limit int, forName string) []Suggestion {
 	var suggestions []Suggestion
 
 	// Calculate how many tasks each task unblocks
@@ -87,6 +123,11 @@ func buildSuggestions(tasks []*core.Record, limit int) []Suggestion {
 
 	// Helper to add suggestion if not already added
 	addSuggestion := func(task *core.Record, reason string) {
+		if forName != "" {
+			if assignees := getTaskAssignees(task); len(assignees) > 0 && !containsFold(assignees, forName) {
+				return
+			}
+		}
 		if !addedIDs[task.Id] {
 			suggestions = append(suggestions, Suggestion{
 				Task:   taskToSuggestionMap(task),
@@ -96,6 +137,11 @@ func buildSuggestions(tasks []*core.Record, limit int) []Suggestion {
 		}
 	}
 
+	// Tasks assigned to forName come first within each group
+	if forName != "" {
+		tasks = preferAssignedTo(tasks, forName)
+	}
+
 	// 1. In-progress tasks (continue current work)
 	for _, t := range tasks {
 		if t.GetString("column") == "in_progress" {
@@ -103,23 +149,29 @@ func buildSuggestions(tasks []*core.Record, limit int) []Suggestion {
 		}
 	}
 
+	// Candidates for the priority tiers, small unblocking tasks first
+	candidates := preferSmallUnblocking(tasks, unblocksCount)
+	if forName != "" {
+		candidates = preferAssignedTo(candidates, forName)
+	}
+
 	// 2. Urgent unblocked tasks
-	for _, t := range tasks {
+	for _, t := range candidates {
 		col := t.GetString("column")
 		if col != "in_progress" && col != "done" && col != "review" &&
 			t.GetString("priority") == "urgent" &&
 			len(getTaskBlockedBy(t)) == 0 {
-			addSuggestion(t, "Urgent priority, unblocked")
+			addSuggestion(t, withEstimate("Urgent priority, unblocked", t))
 		}
 	}
 
 	// 3. High priority unblocked tasks
-	for _, t := range tasks {
+	for _, t := range candidates {
 		col := t.GetString("column")
 		if col != "in_progress" && col != "done" && col != "review" &&
 			t.GetString("priority") == "high" &&
 			len(getTaskBlockedBy(t)) == 0 {
-			addSuggestion(t, "High priority, unblocked")
+			addSuggestion(t, withEstimate("High priority, unblocked", t))
 		}
 	}
 
@@ -139,13 +191,22 @@ func buildSuggestions(tasks []*core.Record, limit int) []Suggestion {
 		}
 	}
 
-	// Sort by count descending
-	sort.Slice(unblocking, func(i, j int) bool {
-		return unblocking[i].count > unblocking[j].count
+	// Sort by count descending, smaller estimates first on ties
+	sort.SliceStable(unblocking, func(i, j int) bool {
+		if forName != "" {
+			ai, aj := hasAssignee(unblocking[i].task, forName), hasAssignee(unblocking[j].task, forName)
+			if ai != aj {
+				return ai
+			}
+		}
+		if unblocking[i].count != unblocking[j].count {
+			return unblocking[i].count > unblocking[j].count
+		}
+		return smallerEstimate(unblocking[i].task, unblocking[j].task)
 	})
 
 	for _, ut := range unblocking {
-		addSuggestion(ut.task, fmt.Sprintf("Unblocks %d other task(s)", ut.count))
+		addSuggestion(ut.task, withEstimate(fmt.Sprintf("Unblocks %d other task(s)", ut.count), ut.task))
 	}
 
 	// Limit results
@@ -156,14 +217,67 @@ func buildSuggestions(tasks []*core.Record, limit int) []Suggestion {
 	return suggestions
 }
 
+// preferSmallUnblocking orders tasks so that those unblocking others come
+// first, then smaller estimates. Unestimated tasks sort after estimated ones.
+func preferSmallUnblocking(tasks []*core.Record, unblocksCount map[string]int) []*core.Record {
+	sorted := append([]*core.Record{}, tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ui, uj := unblocksCount[sorted[i].Id] > 0, unblocksCount[sorted[j].Id] > 0
+		if ui != uj {
+			return ui
+		}
+		return smallerEstimate(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+// preferAssignedTo moves tasks assigned to name ahead of the rest,
+// keeping the existing order otherwise.
+func preferAssignedTo(tasks []*core.Record, name string) []*core.Record {
+	sorted := append([]*core.Record{}, tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return hasAssignee(sorted[i], name) && !hasAssignee(sorted[j], name)
+	})
+	return sorted
+}
+
+// smallerEstimate reports whether a has a smaller estimate than b.
+// Unestimated tasks are treated as larger than any estimate.
+func smallerEstimate(a, b *core.Record) bool {
+	ea, eb := a.GetFloat("estimate"), b.GetFloat("estimate")
+	switch {
+	case ea <= 0:
+		return false
+	case eb <= 0:
+		return true
+	default:
+		return ea < eb
+	}
+}
+
+// withEstimate appends a task's estimate to a suggestion reason.
+func withEstimate(reason string, task *core.Record) string {
+	if est := task.GetFloat("estimate"); est > 0 {
+		return fmt.Sprintf("%s, estimate %s", reason, strconv.FormatFloat(est, 'f', -1, 64))
+	}
+	return reason
+}
+
 func taskToSuggestionMap(task *core.Record) map[string]any {
-	return map[string]any{
+	result := map[string]any{
 		"id":       task.Id,
 		"title":    task.GetString("title"),
 		"type":     task.GetString("type"),
 		"priority": task.GetString("priority"),
 		"column":   task.GetString("column"),
 	}
+	if est := task.GetFloat("estimate"); est > 0 {
+		result["estimate"] = est
+	}
+	if assignees := getTaskAssignees(task); len(assignees) > 0 {
+		result["assignees"] = assignees
+	}
+	return result
 }
 
 func printSuggestions(suggestions []Suggestion) {
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+func TestBuildSuggestions_PrefersSmallUnblockingTasks(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+
+	large := CreateTestTask(t, app, "Large blocker", "todo")
+	large.Set("estimate", 8)
+	require.NoError(t, app.Save(large))
+
+	small := CreateTestTask(t, app, "Small blocker", "todo")
+	small.Set("estimate", 1)
+	require.NoError(t, app.Save(small))
+
+	unestimated := CreateTestTask(t, app, "Unestimated blocker", "todo")
+
+	for _, blocker := range []*core.Record{large, small, unestimated} {
+		blocked := CreateTestTask(t, app, "Blocked by "+blocker.GetString("title"), "todo")
+		blocked.Set("blocked_by", []string{blocker.Id})
+		require.NoError(t, app.Save(blocked))
+	}
+
+	tasks, err := app.FindAllRecords("tasks")
+	require.NoError(t, err)
+
+	suggestions := buildSuggestions(tasks, 0, "")
+	require.Len(t, suggestions, 3)
+
+	assert.Equal(t, small.Id, suggestions[0].Task["id"])
+	assert.Equal(t, "Unblocks 1 other task(s), estimate 1", suggestions[0].Reason)
+	assert.Equal(t, 1.0, suggestions[0].Task["estimate"])
+	assert.Equal(t, large.Id, suggestions[1].Task["id"])
+	assert.Equal(t, unestimated.Id, suggestions[2].Task["id"])
+}
+
+func TestBuildSuggestions_PriorityTierPrefersSmallTasks(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+
+	big := CreateTestTask(t, app, "Big urgent", "todo")
+	big.Set("priority", "urgent")
+	big.Set("estimate", 5)
+	require.NoError(t, app.Save(big))
+
+	quick := CreateTestTask(t, app, "Quick urgent", "todo")
+	quick.Set("priority", "urgent")
+	quick.Set("estimate", 2)
+	require.NoError(t, app.Save(quick))
+
+	tasks, err := app.FindAllRecords("tasks")
+	require.NoError(t, err)
+
+	suggestions := buildSuggestions(tasks, 0, "")
+	require.Len(t, suggestions, 2)
+
+	assert.Equal(t, quick.Id, suggestions[0].Task["id"])
+	assert.Equal(t, big.Id, suggestions[1].Task["id"])
+}
+
+func TestBuildContextSummary_RemainingByColumn(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+
+	parent := CreateTestTask(t, app, "Parent", "in_progress")
+	parent.Set("estimate", 10)
+	require.NoError(t, app.Save(parent))
+
+	// Estimated subtasks replace the parent's own estimate
+	sub := CreateTestTask(t, app, "Subtask", "todo")
+	sub.Set("parent", parent.Id)
+	sub.Set("estimate", 3)
+	require.NoError(t, app.Save(sub))
+
+	other := CreateTestTask(t, app, "Other", "todo")
+	other.Set("estimate", 2)
+	require.NoError(t, app.Save(other))
+
+	done := CreateTestTask(t, app, "Done", "done")
+	done.Set("estimate", 4)
+	require.NoError(t, app.Save(done))
+
+	tasks, err := app.FindAllRecords("tasks")
+	require.NoError(t, err)
+
+	summary := buildContextSummary(tasks)
+
+	assert.Equal(t, map[string]float64{"todo": 5}, summary.RemainingByColumn)
+}
+
+func TestBuildSuggestions_ForAssignee(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+
+	unassigned := CreateTestTask(t, app, "Unassigned urgent", "todo")
+	unassigned.Set("priority", "urgent")
+	require.NoError(t, app.Save(unassigned))
+
+	mine := CreateTestTask(t, app, "Alice urgent", "todo")
+	mine.Set("priority", "urgent")
+	mine.Set("assignees", []string{"alice"})
+	require.NoError(t, app.Save(mine))
+
+	theirs := CreateTestTask(t, app, "Bob urgent", "todo")
+	theirs.Set("priority", "urgent")
+	theirs.Set("assignees", []string{"bob"})
+	require.NoError(t, app.Save(theirs))
+
+	tasks, err := app.FindAllRecords("tasks")
+	require.NoError(t, err)
+
+	// Own tasks first, unassigned tasks next, tasks assigned to others skipped
+	suggestions := buildSuggestions(tasks, 0, "Alice")
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, mine.Id, suggestions[0].Task["id"])
+	assert.Equal(t, unassigned.Id, suggestions[1].Task["id"])
+
+	// Without --for every task is a candidate
+	assert.Len(t, buildSuggestions(tasks, 0, ""), 3)
+}
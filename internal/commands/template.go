@@ -0,0 +1,710 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasktemplate"
+)
+
+func newTemplateCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage task templates",
+		Long: `Manage templates for repeatable work.
+
+A template describes a parent task, its subtasks, and the blocked_by
+wiring between them. Text may contain {{placeholders}} that are filled in
+when the template is used:
+
+  egenskriven add --template bug "Crash on save" --var version=1.2
+
+Every template can use {{title}}, {{date}}, {{user}}, and {{board}}; other
+variables are passed with --var or take the template's defaults.
+
+Templates are stored per board (template add) or as files in
+.egenskriven/templates/: <name>.json with the same structure as
+'template show --json', or <name>.md with the task description and a
+front matter block:
+
+  ---
+  summary: Bug report
+  title: "Bug: {{title}}"
+  type: bug
+  labels: bug, triage
+  subtask: Reproduce on {{version}}
+  subtask: Fix and add a regression test
+  chain: true
+  var.version: latest
+  ---
+  ## Steps to reproduce
+
+A board template shadows a file template of the same name.`,
+	}
+
+	cmd.AddCommand(newTemplateListCmd(app))
+	cmd.AddCommand(newTemplateShowCmd(app))
+	cmd.AddCommand(newTemplateAddCmd(app))
+	cmd.AddCommand(newTemplateDeleteCmd(app))
+
+	return cmd
+}
+
+// ========== Template List ==========
+
+func newTemplateListCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var boardRef string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List task templates",
+		Example: `  egenskriven template list
+  egenskriven template list --board WRK --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+
+			templates, err := listTemplates(app, boardRecord.Id)
+			if err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to load templates: %v", err), nil)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{
+					"templates": templates,
+					"count":     len(templates),
+					"board":     boardRecord.GetString("name"),
+				})
+				return nil
+			}
+
+			if len(templates) == 0 {
+				fmt.Printf("No templates for board '%s'.\n", boardRecord.GetString("name"))
+				fmt.Printf("Add one with: egenskriven template add bug --title \"Bug: {{title}}\" --type bug\n")
+				return nil
+			}
+
+			fmt.Printf("TEMPLATES (%s)\n", boardRecord.GetString("name"))
+			fmt.Println(strings.Repeat("-", 40))
+			for _, t := range templates {
+				fmt.Printf("  %-16s %-30s %d subtask(s)  %s\n",
+					truncateString(t.Name, 16),
+					truncateString(t.Summary, 30),
+					len(t.Subtasks),
+					templateSource(t),
+				)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+
+	return cmd
+}
+
+// ========== Template Show ==========
+
+func newTemplateShowCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var boardRef string
+
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a task template",
+		Example: `  egenskriven template show bug
+  egenskriven template show release --json > .egenskriven/templates/release.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+
+			t, err := findTemplate(app, boardRecord.Id, args[0])
+			if err != nil {
+				return templateError(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(t)
+				return nil
+			}
+
+			fmt.Printf("\nTemplate: %s\n", t.Name)
+			if t.Summary != "" {
+				fmt.Printf("Summary:   %s\n", t.Summary)
+			}
+			fmt.Printf("Source:    %s\n", templateSource(t))
+			if vars := t.Placeholders(); len(vars) > 0 {
+				var described []string
+				for _, name := range vars {
+					if def := t.Vars[name]; def != "" {
+						name += "=" + def
+					}
+					described = append(described, name)
+				}
+				fmt.Printf("Variables: %s\n", strings.Join(described, ", "))
+			}
+
+			fmt.Printf("\nTask:      %s\n", templateTitle(t.Task, true))
+			printTemplateSpec(t.Task)
+
+			if len(t.Subtasks) > 0 {
+				fmt.Printf("\nSubtasks:\n")
+				for _, spec := range t.Subtasks {
+					fmt.Printf("  - %s\n", templateTitle(spec, false))
+				}
+			}
+			if t.Task.Description != "" {
+				fmt.Printf("\nDescription:\n%s\n", t.Task.Description)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+
+	return cmd
+}
+
+// printTemplateSpec prints the fields a template sets on its parent task.
+func printTemplateSpec(spec tasktemplate.Spec) {
+	if spec.Type != "" {
+		fmt.Printf("Type:      %s\n", spec.Type)
+	}
+	if spec.Priority != "" {
+		fmt.Printf("Priority:  %s\n", spec.Priority)
+	}
+	if spec.Column != "" {
+		fmt.Printf("Column:    %s\n", spec.Column)
+	}
+	if len(spec.Labels) > 0 {
+		fmt.Printf("Labels:    %s\n", strings.Join(spec.Labels, ", "))
+	}
+	if len(spec.Assignees) > 0 {
+		fmt.Printf("Assignees: %s\n", strings.Join(spec.Assignees, ", "))
+	}
+	if spec.Estimate > 0 {
+		fmt.Printf("Estimate:  %g\n", spec.Estimate)
+	}
+}
+
+// templateTitle formats a template task title with its key and blockers.
+func templateTitle(spec tasktemplate.Spec, parent bool) string {
+	title := spec.Title
+	if parent && strings.TrimSpace(title) == "" {
+		title = "{{title}}"
+	}
+	if spec.Key != "" {
+		title = fmt.Sprintf("[%s] %s", spec.Key, title)
+	}
+	if len(spec.BlockedBy) > 0 {
+		title += fmt.Sprintf(" (blocked by %s)", strings.Join(spec.BlockedBy, ", "))
+	}
+	return title
+}
+
+// templateSource describes where a template is stored.
+func templateSource(t tasktemplate.Template) string {
+	if t.Source == tasktemplate.SourceBoard {
+		return "board"
+	}
+	return t.Source
+}
+
+// listTemplates returns the templates of a board followed by the project's
+// file templates it does not shadow, like tasktemplate.List, loading the
+// board's from the server in remote mode.
+func listTemplates(app *pocketbase.PocketBase, boardID string) ([]tasktemplate.Template, error) {
+	if !isRemoteMode() {
+		return tasktemplate.List(app, boardID, ".")
+	}
+
+	records, err := findRecordsByFilter(app, tasktemplate.CollectionName,
+		"board = {:board}", "name", 0, dbx.Params{"board": boardID})
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]tasktemplate.Template, 0, len(records))
+	for _, record := range records {
+		t, err := tasktemplate.FromRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return tasktemplate.WithFiles(templates, ".")
+}
+
+// findTemplate returns the template with the given name, preferring the
+// board's.
+func findTemplate(app *pocketbase.PocketBase, boardID, name string) (tasktemplate.Template, error) {
+	templates, err := listTemplates(app, boardID)
+	if err != nil {
+		return tasktemplate.Template{}, err
+	}
+	return tasktemplate.Named(templates, name)
+}
+
+// saveTemplate stores a template on a board like tasktemplate.Save,
+// through the API in remote mode.
+func saveTemplate(app *pocketbase.PocketBase, boardID string, t tasktemplate.Template, replace bool) (*core.Record, error) {
+	if !isRemoteMode() {
+		return tasktemplate.Save(app, boardID, t, replace)
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := findRecordsByFilter(app, tasktemplate.CollectionName,
+		"board = {:board} && name = {:name}", "", 1,
+		dbx.Params{"board": boardID, "name": t.Name})
+	if err != nil {
+		return nil, err
+	}
+	var record *core.Record
+	if len(existing) > 0 {
+		if !replace {
+			return nil, fmt.Errorf("%w: %s", tasktemplate.ErrExists, t.Name)
+		}
+		record = existing[0]
+	} else if record, err = newRecord(app, tasktemplate.CollectionName); err != nil {
+		return nil, err
+	}
+
+	tasktemplate.ToRecord(t, boardID, record)
+	if err := saveRecord(app, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// deleteTemplate deletes a board template, through the API in remote mode.
+func deleteTemplate(app *pocketbase.PocketBase, t tasktemplate.Template) error {
+	if isRemoteMode() {
+		return remoteClient().DeleteRecord(tasktemplate.CollectionName, t.Record.Id)
+	}
+	return app.Delete(t.Record)
+}
+
+// templateError reports a failure to find or use a template.
+func templateError(out *output.Formatter, err error) error {
+	var missing *tasktemplate.MissingVarsError
+	switch {
+	case errors.Is(err, tasktemplate.ErrNotFound):
+		return out.ErrorWithSuggestion(ExitNotFound, err.Error(),
+			"List templates with 'egenskriven template list'", nil)
+	case errors.As(err, &missing):
+		return out.ErrorWithSuggestion(ExitValidation, err.Error(),
+			fmt.Sprintf("Pass values with --var %s=<value>", missing.Names[0]), nil)
+	default:
+		return out.Error(ExitValidation, err.Error(), nil)
+	}
+}
+
+// ========== Template Add ==========
+
+func newTemplateAddCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef    string
+		file        string
+		summary     string
+		title       string
+		description string
+		taskType    string
+		priority    string
+		column      string
+		labels      []string
+		subtasks    []string
+		chain       bool
+		vars        []string
+		force       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a task template to a board",
+		Long: `Store a task template on a board.
+
+Describe the template with flags, or load it from a JSON or markdown file
+with --file (see 'egenskriven template --help' for the file formats).
+Use --force to replace the board's template of the same name.`,
+		Example: `  egenskriven template add bug --title "Bug: {{title}}" --type bug --label bug \
+    --description "## Steps to reproduce\n\n## Version\n{{version}}" \
+    --subtask "Reproduce" --subtask "Fix" --subtask "Release notes" --chain
+  egenskriven template add release --file .egenskriven/templates/release.json
+  egenskriven template add bug --file bug.md --board WRK --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+
+			var t tasktemplate.Template
+			if file != "" {
+				t, err = tasktemplate.ParseFile(file)
+				if err != nil {
+					return out.Error(ExitValidation, err.Error(), nil)
+				}
+			} else {
+				t.Task = tasktemplate.Spec{
+					Title:       title,
+					Description: strings.ReplaceAll(description, `\n`, "\n"),
+					Type:        taskType,
+					Priority:    priority,
+					Column:      column,
+					Labels:      labels,
+				}
+				for i, s := range subtasks {
+					spec := tasktemplate.Spec{Title: s}
+					if chain {
+						spec.Key = fmt.Sprintf("step%d", i+1)
+						if i > 0 {
+							spec.BlockedBy = []string{fmt.Sprintf("step%d", i)}
+						}
+					}
+					t.Subtasks = append(t.Subtasks, spec)
+				}
+			}
+			t.Name = args[0]
+			if summary != "" {
+				t.Summary = summary
+			}
+			defaults, err := parseTemplateVars(vars)
+			if err != nil {
+				return out.Error(ExitInvalidArguments, err.Error(), nil)
+			}
+			for k, v := range defaults {
+				if t.Vars == nil {
+					t.Vars = make(map[string]string)
+				}
+				t.Vars[k] = v
+			}
+
+			if err := validateTemplateSpecs(t); err != nil {
+				return out.Error(ExitValidation, err.Error(), nil)
+			}
+
+			if _, err := saveTemplate(app, boardRecord.Id, t, force); err != nil {
+				if errors.Is(err, tasktemplate.ErrExists) {
+					return out.ErrorWithSuggestion(ExitConflict, err.Error(),
+						"Use --force to replace it", nil)
+				}
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to save template: %v", err), nil)
+			}
+
+			if jsonOutput {
+				t.Source = tasktemplate.SourceBoard
+				out.WriteJSON(t)
+				return nil
+			}
+			out.Success(fmt.Sprintf("Saved template '%s' on board %s (%d subtask(s))",
+				t.Name, boardRecord.GetString("name"), len(t.Subtasks)))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Load the template from a JSON or markdown file")
+	cmd.Flags().StringVar(&summary, "summary", "", "What the template is for")
+	cmd.Flags().StringVar(&title, "title", "", "Title of the parent task (default: {{title}})")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Description of the parent task")
+	cmd.Flags().StringVarP(&taskType, "type", "t", "", "Task type (bug, feature, chore)")
+	cmd.Flags().StringVarP(&priority, "priority", "p", "", "Priority (low, medium, high, urgent)")
+	cmd.Flags().StringVarP(&column, "column", "c", "", "Initial column")
+	cmd.Flags().StringSliceVarP(&labels, "label", "l", nil, "Labels (repeatable)")
+	cmd.Flags().StringArrayVar(&subtasks, "subtask", nil, "Subtask title (repeatable)")
+	cmd.Flags().BoolVar(&chain, "chain", false, "Block each subtask by the previous one")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Variable default as name=value (repeatable)")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing template of the same name")
+
+	return cmd
+}
+
+// validateTemplateSpecs checks the types, priorities, and columns of a
+// template's tasks. Empty values take the defaults when the template is used.
+func validateTemplateSpecs(t tasktemplate.Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	for _, spec := range append([]tasktemplate.Spec{t.Task}, t.Subtasks...) {
+		if spec.Type != "" && !isValidType(spec.Type) {
+			return fmt.Errorf("invalid type '%s', must be one of: %v", spec.Type, ValidTypes)
+		}
+		if spec.Priority != "" && !isValidPriority(spec.Priority) {
+			return fmt.Errorf("invalid priority '%s', must be one of: %v", spec.Priority, ValidPriorities)
+		}
+		if spec.Column != "" && !isValidColumn(spec.Column) {
+			return fmt.Errorf("invalid column '%s', must be one of: %v", spec.Column, ValidColumns)
+		}
+	}
+	return nil
+}
+
+// parseTemplateVars parses name=value pairs given with --var.
+func parseTemplateVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --var %q: use name=value", pair)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// ========== Template Delete ==========
+
+func newTemplateDeleteCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var boardRef string
+
+	cmd := &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a board's task template",
+		Long: `Delete a template stored on a board.
+
+Templates in .egenskriven/templates/ are removed by deleting their file.`,
+		Example: `  egenskriven template delete bug
+  egenskriven template delete release --board WRK`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+
+			t, err := findTemplate(app, boardRecord.Id, args[0])
+			if err != nil {
+				return templateError(out, err)
+			}
+			if t.Record == nil {
+				return out.ErrorWithSuggestion(ExitValidation,
+					fmt.Sprintf("template '%s' is a file template", t.Name),
+					fmt.Sprintf("Delete %s to remove it", t.Source), nil)
+			}
+
+			if err := deleteTemplate(app, t); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to delete template: %v", err), nil)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{
+					"deleted": t.Name,
+					"board":   boardRecord.GetString("name"),
+				})
+				return nil
+			}
+			out.Success(fmt.Sprintf("Deleted template '%s' from board %s", t.Name, boardRecord.GetString("name")))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+
+	return cmd
+}
+
+// ========== add --template ==========
+
+// templateAddOptions are the add flags applied to a template's tasks.
+type templateAddOptions struct {
+	Name      string
+	Title     string
+	Vars      []string
+	BoardRef  string
+	Agent     string
+	CreatedBy string
+	Epic      string
+	DueDate   string
+
+	// Overrides of the parent task, set only when the flag was given
+	Type     string
+	Priority string
+	Column   string
+	Labels   []string
+	Assign   []string
+	Estimate float64
+}
+
+// addFromTemplate creates a task and its subtasks from a template.
+func addFromTemplate(app *pocketbase.PocketBase, out *output.Formatter, opts templateAddOptions) error {
+	boardRecord, err := resolveBoard(app, opts.BoardRef)
+	if err != nil {
+		return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+	}
+
+	caller := resolveCaller(app, opts.Agent)
+	if err := policy.Check(app, caller, boardRecord.Id, policy.ActionCreate).Err(); err != nil {
+		return policyDenied(out, err)
+	}
+
+	t, err := findTemplate(app, boardRecord.Id, opts.Name)
+	if err != nil {
+		return templateError(out, err)
+	}
+
+	vars, err := parseTemplateVars(opts.Vars)
+	if err != nil {
+		return out.Error(ExitInvalidArguments, err.Error(), nil)
+	}
+	for k, v := range tasktemplate.BuiltinVars(opts.Title, boardRecord.GetString("prefix")) {
+		if _, ok := vars[k]; !ok {
+			vars[k] = v
+		}
+	}
+
+	rendered, err := t.Render(vars)
+	if err != nil {
+		var missing *tasktemplate.MissingVarsError
+		if errors.As(err, &missing) && len(missing.Names) == 1 && missing.Names[0] == tasktemplate.TitleVar {
+			return out.Error(ExitInvalidArguments,
+				fmt.Sprintf("template '%s' needs a title\n\nUsage: egenskriven add --template %s <title>", t.Name, t.Name), nil)
+		}
+		return templateError(out, err)
+	}
+
+	// Flags given with add override the template's parent task
+	if opts.Type != "" {
+		rendered.Task.Type = opts.Type
+	}
+	if opts.Priority != "" {
+		rendered.Task.Priority = opts.Priority
+	}
+	if opts.Column != "" {
+		rendered.Task.Column = opts.Column
+	}
+	if len(opts.Labels) > 0 {
+		rendered.Task.Labels = append(rendered.Task.Labels, opts.Labels...)
+	}
+	if len(opts.Assign) > 0 {
+		rendered.Task.Assignees = updateAssignees(rendered.Task.Assignees, resolveAssigneeNames(opts.Assign), nil)
+	}
+	if opts.Estimate > 0 {
+		rendered.Task.Estimate = opts.Estimate
+	}
+	if err := validateTemplateSpecs(rendered); err != nil {
+		return out.Error(ExitValidation, fmt.Sprintf("template '%s': %v", t.Name, err), nil)
+	}
+
+	// Agent modes apply to every task the template creates
+	rendered.Task.Column = defaultString(rendered.Task.Column, "backlog")
+	if rendered.Task.Column, err = policyColumn(app, caller, boardRecord.Id, "", rendered.Task.Column); err != nil {
+		return policyDenied(out, err)
+	}
+	for i := range rendered.Subtasks {
+		if rendered.Subtasks[i].Column == "" {
+			continue
+		}
+		if rendered.Subtasks[i].Column, err = policyColumn(app, caller, boardRecord.Id, "", rendered.Subtasks[i].Column); err != nil {
+			return policyDenied(out, err)
+		}
+	}
+
+	instantiate := tasktemplate.Options{
+		CreatedBy: opts.CreatedBy,
+		Agent:     opts.Agent,
+		DueDate:   opts.DueDate,
+	}
+	if opts.Epic != "" {
+		epicRecord, err := resolveEpic(app, opts.Epic)
+		if err != nil {
+			return out.Error(ExitValidation, fmt.Sprintf("invalid epic: %v", err), nil)
+		}
+		instantiate.Epic = epicRecord.Id
+	}
+	if opts.DueDate != "" {
+		if instantiate.DueDate, err = parseDate(opts.DueDate); err != nil {
+			return out.Error(ExitValidation, fmt.Sprintf("invalid due date: %v", err), nil)
+		}
+	}
+
+	records, err := tasktemplate.Instantiate(app, boardRecord, rendered, instantiate)
+	if err != nil {
+		return out.Error(ExitGeneralError, fmt.Sprintf("failed to create tasks from template: %v", err), nil)
+	}
+
+	prefix := boardRecord.GetString("prefix")
+	if jsonOutput {
+		tasks := make([]map[string]any, 0, len(records))
+		for _, record := range records {
+			tasks = append(tasks, templateTaskToMap(record, prefix))
+		}
+		out.WriteJSON(map[string]any{
+			"template": t.Name,
+			"created":  len(records),
+			"tasks":    tasks,
+		})
+		return nil
+	}
+
+	for i, record := range records {
+		indent := ""
+		if i > 0 {
+			indent = "  "
+		}
+		fmt.Printf("%sCreated: %s [%s]\n", indent, record.GetString("title"),
+			board.FormatDisplayID(prefix, record.GetInt("seq")))
+	}
+	return nil
+}
+
+// templateTaskToMap describes a task created from a template.
+func templateTaskToMap(record *core.Record, prefix string) map[string]any {
+	m := map[string]any{
+		"id":         record.Id,
+		"display_id": board.FormatDisplayID(prefix, record.GetInt("seq")),
+		"title":      record.GetString("title"),
+		"type":       record.GetString("type"),
+		"priority":   record.GetString("priority"),
+		"column":     record.GetString("column"),
+		"board":      record.GetString("board"),
+		"seq":        record.GetInt("seq"),
+	}
+	if parent := record.GetString("parent"); parent != "" {
+		m["parent"] = parent
+	}
+	if blockedBy := record.GetStringSlice("blocked_by"); len(blockedBy) > 0 {
+		m["blocked_by"] = blockedBy
+	}
+	return m
+}
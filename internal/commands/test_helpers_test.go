@@ -7,6 +7,9 @@ import (
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
 )
 
 // ========== Shared Test Setup Functions ==========
@@ -50,11 +53,17 @@ func SetupTasksCollection(t *testing.T, app *pocketbase.PocketBase) {
 		Values:   []string{"user", "agent", "cli"},
 	})
 	collection.Fields.Add(&core.TextField{Name: "created_by_agent"})
-	collection.Fields.Add(&core.JSONField{Name: "history"})
+	collection.Fields.Add(&core.TextField{Name: "parent"})
+	collection.Fields.Add(&core.NumberField{Name: "estimate"})
+	collection.Fields.Add(&core.JSONField{Name: "assignees"})
 
 	if err := app.Save(collection); err != nil {
 		t.Fatalf("failed to create tasks collection: %v", err)
 	}
+
+	// Log task changes to task_events, as the app does
+	testutil.CreateTaskEventsCollection(t, app)
+	taskevent.Bind(app)
 }
 
 // SetupCommentsCollection creates the comments collection without autodate fields.
@@ -113,6 +122,43 @@ func SetupCommentsCollectionWithAutodate(t *testing.T, app *pocketbase.PocketBas
 	}
 }
 
+// SetupTimeEntriesCollection creates the time_entries collection.
+// Requires the tasks collection to exist (see SetupTasksCollection).
+func SetupTimeEntriesCollection(t *testing.T, app *pocketbase.PocketBase) {
+	t.Helper()
+
+	_, err := app.FindCollectionByNameOrId("time_entries")
+	if err == nil {
+		return
+	}
+
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+
+	collection := core.NewBaseCollection("time_entries")
+	collection.Fields.Add(&core.RelationField{
+		Name:          "task",
+		CollectionId:  tasks.Id,
+		MaxSelect:     1,
+		Required:      true,
+		CascadeDelete: true,
+	})
+	collection.Fields.Add(&core.TextField{Name: "actor"})
+	collection.Fields.Add(&core.SelectField{
+		Name:     "source",
+		Required: true,
+		Values:   []string{"timer", "auto", "manual"},
+	})
+	collection.Fields.Add(&core.DateField{Name: "started_at", Required: true})
+	collection.Fields.Add(&core.DateField{Name: "ended_at"})
+	collection.Fields.Add(&core.NumberField{Name: "duration_seconds", OnlyInt: true})
+	collection.Fields.Add(&core.TextField{Name: "note"})
+
+	if err := app.Save(collection); err != nil {
+		t.Fatalf("failed to create time_entries collection: %v", err)
+	}
+}
+
 // CreateTestTask creates a task for testing with standard defaults.
 func CreateTestTask(t *testing.T, app *pocketbase.PocketBase, title string, column string) *core.Record {
 	t.Helper()
@@ -129,7 +175,6 @@ func CreateTestTask(t *testing.T, app *pocketbase.PocketBase, title string, colu
 	record.Set("labels", []string{})
 	record.Set("blocked_by", []string{})
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 
 	require.NoError(t, app.Save(record))
 	return record
@@ -153,6 +198,15 @@ func CreateTestComment(t *testing.T, app *pocketbase.PocketBase, taskId, content
 	return record
 }
 
+// GetTaskEvents returns the logged events of a task, oldest first.
+func GetTaskEvents(t *testing.T, app *pocketbase.PocketBase, taskId string) []taskevent.Event {
+	t.Helper()
+
+	events, err := taskevent.ForTask(app, taskId)
+	require.NoError(t, err)
+	return events
+}
+
 // GetCommentsForTask returns all comments for a given task ID.
 func GetCommentsForTask(t *testing.T, app *pocketbase.PocketBase, taskId string) []*core.Record {
 	t.Helper()
@@ -0,0 +1,733 @@
+package commands
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
+	"github.com/ramtinJ95/EgenSkriven/internal/timetrack"
+)
+
+// validTimeReportGroups are the supported --by values for time report
+var validTimeReportGroups = []string{"task", "epic", "label", "actor"}
+
+func newTimeCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "time",
+		Short: "Track time spent on tasks",
+		Long: `Commands for tracking and reporting time spent on tasks.
+
+Timers start automatically when a task enters in_progress and pause when it
+moves to need_input (or any other column). This can be configured in
+.egenskriven/config.json:
+
+  "time_tracking": {
+    "mode": "auto",              // "auto" or "manual"
+    "columns": ["in_progress"]   // columns in which the timer runs
+  }
+
+Timers can also be controlled explicitly with 'time start' and 'time stop',
+and past work can be recorded with 'time log'.`,
+	}
+
+	// Add subcommands
+	cmd.AddCommand(newTimeStartCmd(app))
+	cmd.AddCommand(newTimeStopCmd(app))
+	cmd.AddCommand(newTimeLogCmd(app))
+	cmd.AddCommand(newTimeListCmd(app))
+	cmd.AddCommand(newTimeReportCmd(app))
+
+	return cmd
+}
+
+// ========== Time Start ==========
+
+func newTimeStartCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var actor string
+
+	cmd := &cobra.Command{
+		Use:   "start <task>",
+		Short: "Start a timer on a task",
+		Example: `  egenskriven time start WRK-1
+  egenskriven time start WRK-1 --actor alice`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			taskRef := args[0]
+			caller := resolveCaller(app, "")
+
+			var result map[string]any
+			if isRemoteMode() {
+				var err error
+				result, err = remoteClient().TrackTime(taskRef, "start", timeRequest{
+					Actor: resolveTimeActor(actor),
+					Agent: requestAgent(caller),
+				})
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				task, err := resolveTimeTask(app, out, taskRef, caller)
+				if err != nil {
+					return err
+				}
+				entry, err := startTimer(app, task, resolveTimeActor(actor))
+				if err != nil {
+					return commandFailed(out, err)
+				}
+				result = timeResult(app, task, entry)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			out.Success(fmt.Sprintf("Started timer on %s", result["display_id"]))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&actor, "actor", "", "Who is working on the task (default: defaults.author or $USER)")
+
+	return cmd
+}
+
+// ========== Time Stop ==========
+
+func newTimeStopCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var note string
+
+	cmd := &cobra.Command{
+		Use:   "stop <task>",
+		Short: "Stop the running timer on a task",
+		Example: `  egenskriven time stop WRK-1
+  egenskriven time stop WRK-1 --note "Implemented parser"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			taskRef := args[0]
+			caller := resolveCaller(app, "")
+
+			var result map[string]any
+			if isRemoteMode() {
+				var err error
+				result, err = remoteClient().TrackTime(taskRef, "stop", timeRequest{
+					Note:  note,
+					Agent: requestAgent(caller),
+				})
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				task, err := resolveTimeTask(app, out, taskRef, caller)
+				if err != nil {
+					return err
+				}
+				entry, err := stopTimer(app, task, note)
+				if err != nil {
+					return commandFailed(out, err)
+				}
+				result = timeResult(app, task, entry)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			entry, _ := result["entry"].(map[string]any)
+			duration := time.Duration(resultInt(entry, "duration_seconds")) * time.Second
+			out.Success(fmt.Sprintf("Stopped timer on %s (%s)", result["display_id"], timetrack.FormatDuration(duration)))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&note, "note", "n", "", "Note describing the work")
+
+	return cmd
+}
+
+// ========== Time Log ==========
+
+func newTimeLogCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		actor string
+		note  string
+		date  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "log <task> <duration>",
+		Short: "Log time spent on a task",
+		Long: `Record time already spent on a task.
+
+Duration uses Go duration syntax: 30m, 1h, 1h30m, 1.5h.
+By default the entry ends now; use --date to log work on an earlier day.`,
+		Example: `  egenskriven time log WRK-1 1h30m
+  egenskriven time log WRK-1 45m --note "Code review"
+  egenskriven time log WRK-1 2h --date 2025-01-15
+  egenskriven time log WRK-1 2h --date yesterday`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			taskRef := args[0]
+
+			duration, err := time.ParseDuration(args[1])
+			if err != nil || duration <= 0 {
+				return out.Error(ExitValidation,
+					fmt.Sprintf("invalid duration '%s' (use e.g. 30m, 1h30m, 1.5h)", args[1]), nil)
+			}
+
+			endedAt := time.Now()
+			if date != "" {
+				day, err := parsePastDate(date)
+				if err != nil {
+					return out.Error(ExitValidation, fmt.Sprintf("invalid date: %v", err), nil)
+				}
+				// End at the same time of day on the given date
+				now := time.Now()
+				endedAt = time.Date(day.Year(), day.Month(), day.Day(),
+					now.Hour(), now.Minute(), now.Second(), 0, time.Local)
+			}
+
+			caller := resolveCaller(app, "")
+
+			var result map[string]any
+			if isRemoteMode() {
+				result, err = remoteClient().TrackTime(taskRef, "log", timeRequest{
+					Actor:    resolveTimeActor(actor),
+					Note:     note,
+					Duration: duration.String(),
+					EndedAt:  endedAt,
+					Agent:    requestAgent(caller),
+				})
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				task, err := resolveTimeTask(app, out, taskRef, caller)
+				if err != nil {
+					return err
+				}
+				entry, err := logTime(app, task, resolveTimeActor(actor), duration, endedAt, note)
+				if err != nil {
+					return commandFailed(out, err)
+				}
+				result = timeResult(app, task, entry)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			out.Success(fmt.Sprintf("Logged %s on %s", timetrack.FormatDuration(duration), result["display_id"]))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&actor, "actor", "", "Who did the work (default: defaults.author or $USER)")
+	cmd.Flags().StringVarP(&note, "note", "n", "", "Note describing the work")
+	cmd.Flags().StringVar(&date, "date", "", "Date the work was done: YYYY-MM-DD, yesterday, or e.g. jan 15 (default: today)")
+
+	return cmd
+}
+
+// ========== Time List ==========
+
+func newTimeListCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <task>",
+		Short: "List time entries for a task",
+		Example: `  egenskriven time list WRK-1
+  egenskriven time list WRK-1 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			taskRef := args[0]
+
+			// Resolve task
+			task, err := resolveTask(app, taskRef)
+			if err != nil {
+				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+					return out.AmbiguousError(taskRef, ambErr.Matches)
+				}
+				return out.Error(ExitNotFound, err.Error(), nil)
+			}
+
+			displayID := getTaskDisplayID(app, task)
+
+			entries, err := findRecordsByFilter(app, timetrack.CollectionName,
+				"task = {:task}", "+started_at", 0, dbx.Params{"task": task.Id})
+			if err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to fetch time entries: %v", err), nil)
+			}
+
+			now := time.Now()
+			var total time.Duration
+			running := false
+			for _, e := range entries {
+				total += timetrack.EntryDuration(e, now)
+				if timetrack.IsRunning(e) {
+					running = true
+				}
+			}
+
+			if jsonOutput {
+				entryMaps := make([]map[string]any, 0, len(entries))
+				for _, e := range entries {
+					entryMaps = append(entryMaps, timeEntryToMap(e, now))
+				}
+				out.WriteJSON(map[string]any{
+					"task_id":       task.Id,
+					"display_id":    displayID,
+					"total_seconds": int64(total.Seconds()),
+					"running":       running,
+					"count":         len(entries),
+					"entries":       entryMaps,
+				})
+				return nil
+			}
+
+			if len(entries) == 0 {
+				fmt.Printf("No time logged on %s\n", displayID)
+				return nil
+			}
+
+			fmt.Printf("Time entries for %s:\n\n", displayID)
+			for _, e := range entries {
+				duration := timetrack.FormatDuration(timetrack.EntryDuration(e, now))
+				if timetrack.IsRunning(e) {
+					duration += " (running)"
+				}
+				fmt.Printf("  %s  %-18s %-12s %-7s %s\n",
+					e.GetDateTime("started_at").Time().Local().Format("2006-01-02 15:04"),
+					duration,
+					truncateString(e.GetString("actor"), 12),
+					e.GetString("source"),
+					e.GetString("note"),
+				)
+			}
+			fmt.Printf("\nTotal: %s\n", timetrack.FormatDuration(total))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// ========== Time Report ==========
+
+// TimeReportRow is one group in a time report.
+type TimeReportRow struct {
+	Key          string  `json:"key"`
+	Name         string  `json:"name"`
+	TotalSeconds int64   `json:"total_seconds"`
+	Hours        float64 `json:"hours"`
+	Entries      int     `json:"entries"`
+}
+
+func newTimeReportCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		groupBy  string
+		since    string
+		boardRef string
+		format   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarize logged time",
+		Long: `Summarize logged time grouped by task, epic, label, or actor.
+
+Entries are included when they started on or after --since.
+Time on a task with several labels counts toward each label.`,
+		Example: `  egenskriven time report
+  egenskriven time report --by epic --since 30d
+  egenskriven time report --by actor --since 2w --format csv > time.csv
+  egenskriven time report --by label --board WRK --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			if !containsString(validTimeReportGroups, groupBy) {
+				return out.Error(ExitValidation,
+					fmt.Sprintf("invalid --by %q: must be one of %v", groupBy, validTimeReportGroups), nil)
+			}
+
+			format = strings.ToLower(format)
+			if jsonOutput {
+				format = "json"
+			}
+			if format != "table" && format != "csv" && format != "json" {
+				return out.Error(ExitValidation,
+					fmt.Sprintf("unsupported format: %s (use 'table', 'csv' or 'json')", format), nil)
+			}
+
+			var sinceTime time.Time
+			if since != "" {
+				var err error
+				sinceTime, err = parseSince(since)
+				if err != nil {
+					return out.Error(ExitValidation, err.Error(), nil)
+				}
+			}
+
+			var rows []TimeReportRow
+			if isRemoteMode() {
+				var err error
+				rows, err = remoteClient().TimeReport(groupBy, sinceTime, boardRef)
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				var boardID string
+				if boardRef != "" {
+					boardRecord, err := board.GetByNameOrPrefix(app, boardRef)
+					if err != nil {
+						return out.Error(ExitNotFound, fmt.Sprintf("board not found: %s", boardRef), nil)
+					}
+					boardID = boardRecord.Id
+				}
+
+				var err error
+				rows, err = buildTimeReport(app, groupBy, sinceTime, boardID)
+				if err != nil {
+					return out.Error(ExitGeneralError, fmt.Sprintf("failed to build report: %v", err), nil)
+				}
+			}
+
+			var total int64
+			for _, r := range rows {
+				total += r.TotalSeconds
+			}
+
+			switch format {
+			case "json":
+				out.WriteJSON(timeReportResult(groupBy, sinceTime, rows))
+			case "csv":
+				w := csv.NewWriter(os.Stdout)
+				defer w.Flush()
+				if err := w.Write([]string{groupBy, "name", "total_seconds", "hours", "entries"}); err != nil {
+					return out.Error(ExitGeneralError, fmt.Sprintf("failed to write CSV: %v", err), nil)
+				}
+				for _, r := range rows {
+					if err := w.Write([]string{
+						r.Key,
+						r.Name,
+						fmt.Sprintf("%d", r.TotalSeconds),
+						fmt.Sprintf("%.2f", r.Hours),
+						fmt.Sprintf("%d", r.Entries),
+					}); err != nil {
+						return out.Error(ExitGeneralError, fmt.Sprintf("failed to write CSV: %v", err), nil)
+					}
+				}
+			default:
+				if len(rows) == 0 {
+					fmt.Println("No time logged.")
+					return nil
+				}
+				fmt.Printf("%-12s %-40s %10s %8s\n", strings.ToUpper(groupBy), "NAME", "TIME", "ENTRIES")
+				for _, r := range rows {
+					fmt.Printf("%-12s %-40s %10s %8d\n",
+						truncateString(r.Key, 12),
+						truncateString(r.Name, 40),
+						timetrack.FormatDuration(time.Duration(r.TotalSeconds)*time.Second),
+						r.Entries,
+					)
+				}
+				fmt.Printf("\nTotal: %s\n", timetrack.FormatDuration(time.Duration(total)*time.Second))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&groupBy, "by", "task", "Group by: task, epic, label, actor")
+	cmd.Flags().StringVar(&since, "since", "", "Only include entries since (e.g. 7d, 2w, 2025-01-15)")
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Only include tasks on this board")
+	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format: table, csv, json")
+
+	return cmd
+}
+
+// buildTimeReport aggregates time entries into report rows, sorted by total
+// time descending. A zero since includes all entries; an empty boardID
+// includes all boards.
+func buildTimeReport(app *pocketbase.PocketBase, groupBy string, since time.Time, boardID string) ([]TimeReportRow, error) {
+	var exprs []dbx.Expression
+	if !since.IsZero() {
+		exprs = append(exprs, dbx.NewExp("started_at >= {:since}",
+			dbx.Params{"since": since.UTC().Format("2006-01-02 15:04:05.000Z")}))
+	}
+
+	entries, err := app.FindAllRecords(timetrack.CollectionName, exprs...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache lookups shared across entries
+	tasks := make(map[string]*core.Record)
+	epics := make(map[string]*core.Record)
+	prefixes := make(map[string]string)
+
+	findTask := func(id string) *core.Record {
+		if t, ok := tasks[id]; ok {
+			return t
+		}
+		t, _ := app.FindRecordById("tasks", id)
+		tasks[id] = t
+		return t
+	}
+
+	displayID := func(task *core.Record) string {
+		boardID := task.GetString("board")
+		if boardID == "" || task.GetInt("seq") == 0 {
+			return shortID(task.Id)
+		}
+		prefix, ok := prefixes[boardID]
+		if !ok {
+			if b, err := app.FindRecordById("boards", boardID); err == nil {
+				prefix = b.GetString("prefix")
+			}
+			prefixes[boardID] = prefix
+		}
+		if prefix == "" {
+			return shortID(task.Id)
+		}
+		return board.FormatDisplayID(prefix, task.GetInt("seq"))
+	}
+
+	rowsByKey := make(map[string]*TimeReportRow)
+	add := func(key, name string, d time.Duration) {
+		row, ok := rowsByKey[key]
+		if !ok {
+			row = &TimeReportRow{Key: key, Name: name}
+			rowsByKey[key] = row
+		}
+		row.TotalSeconds += int64(d.Seconds())
+		row.Entries++
+	}
+
+	now := time.Now()
+	for _, e := range entries {
+		task := findTask(e.GetString("task"))
+		if task == nil {
+			continue
+		}
+		if boardID != "" && task.GetString("board") != boardID {
+			continue
+		}
+
+		d := timetrack.EntryDuration(e, now)
+
+		switch groupBy {
+		case "task":
+			add(displayID(task), task.GetString("title"), d)
+		case "epic":
+			epicID := task.GetString("epic")
+			if epicID == "" {
+				add("-", "(no epic)", d)
+				continue
+			}
+			epic, ok := epics[epicID]
+			if !ok {
+				epic, _ = app.FindRecordById("epics", epicID)
+				epics[epicID] = epic
+			}
+			name := epicID
+			if epic != nil {
+				name = epic.GetString("title")
+			}
+			add(shortID(epicID), name, d)
+		case "label":
+			labels := task.GetStringSlice("labels")
+			if len(labels) == 0 {
+				add("-", "(no label)", d)
+				continue
+			}
+			for _, label := range labels {
+				add(label, label, d)
+			}
+		case "actor":
+			actor := e.GetString("actor")
+			if actor == "" {
+				actor = "(unknown)"
+			}
+			add(actor, actor, d)
+		}
+	}
+
+	rows := make([]TimeReportRow, 0, len(rowsByKey))
+	for _, row := range rowsByKey {
+		row.Hours = math.Round(float64(row.TotalSeconds)/36) / 100
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].TotalSeconds != rows[j].TotalSeconds {
+			return rows[i].TotalSeconds > rows[j].TotalSeconds
+		}
+		return rows[i].Key < rows[j].Key
+	})
+
+	return rows, nil
+}
+
+// resolveTimeTask resolves the task whose time is tracked and checks that
+// the caller may update it.
+func resolveTimeTask(app *pocketbase.PocketBase, out *output.Formatter, ref string, caller policy.Actor) (*core.Record, error) {
+	task, err := resolver.MustResolve(app, ref)
+	if err != nil {
+		if ambErr, ok := err.(*resolver.AmbiguousError); ok {
+			return nil, out.AmbiguousError(ref, ambErr.Matches)
+		}
+		return nil, out.Error(ExitNotFound, err.Error(), nil)
+	}
+	if err := checkTaskPolicy(app, caller, task, policy.ActionUpdate).Err(); err != nil {
+		return nil, policyDenied(out, err)
+	}
+	return task, nil
+}
+
+// startTimer starts a timer on a task.
+func startTimer(app *pocketbase.PocketBase, task *core.Record, actor string) (*core.Record, error) {
+	entry, err := timetrack.Start(app, task.Id, actor, timetrack.SourceTimer, time.Now())
+	if errors.Is(err, timetrack.ErrTimerRunning) {
+		return nil, newCommandError(ExitValidation, "timer already running on %s (started %s)",
+			getTaskDisplayID(app, task), formatRelativeTime(entry.GetDateTime("started_at").Time()))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+	return entry, nil
+}
+
+// stopTimer stops the running timer on a task, with a note on the work
+// when given.
+func stopTimer(app *pocketbase.PocketBase, task *core.Record, note string) (*core.Record, error) {
+	entry, err := timetrack.Stop(app, task.Id, time.Now())
+	if errors.Is(err, timetrack.ErrNoTimerRunning) {
+		return nil, newCommandError(ExitValidation, "no timer running on %s", getTaskDisplayID(app, task))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+
+	if note != "" {
+		entry.Set("note", note)
+		if err := app.Save(entry); err != nil {
+			return nil, fmt.Errorf("failed to save note: %w", err)
+		}
+	}
+	return entry, nil
+}
+
+// logTime records time already spent on a task, ending at endedAt.
+func logTime(app *pocketbase.PocketBase, task *core.Record, actor string, duration time.Duration, endedAt time.Time, note string) (*core.Record, error) {
+	entry, err := timetrack.Log(app, task.Id, actor, duration, endedAt, note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log time: %w", err)
+	}
+	return entry, nil
+}
+
+// timeResult returns the result printed by 'time start', 'time stop', and
+// 'time log' with --json.
+func timeResult(app *pocketbase.PocketBase, task, entry *core.Record) map[string]any {
+	return map[string]any{
+		"success":    true,
+		"task_id":    task.Id,
+		"display_id": getTaskDisplayID(app, task),
+		"entry":      timeEntryToMap(entry, time.Now()),
+	}
+}
+
+// timeReportResult returns the output of 'time report --json'.
+func timeReportResult(groupBy string, since time.Time, rows []TimeReportRow) map[string]any {
+	var total int64
+	for _, r := range rows {
+		total += r.TotalSeconds
+	}
+	result := map[string]any{
+		"by":            groupBy,
+		"rows":          rows,
+		"total_seconds": total,
+	}
+	if !since.IsZero() {
+		result["since"] = since.UTC().Format(time.RFC3339)
+	}
+	return result
+}
+
+// resolveTimeActor returns who a time entry is attributed to.
+// Falls back to the configured agent name in agent sessions and to "cli" otherwise.
+func resolveTimeActor(flagValue string) string {
+	if actor := resolveAuthor(flagValue); actor != "" {
+		return actor
+	}
+	if isAgentContext() {
+		return getDefaultAgentName()
+	}
+	return "cli"
+}
+
+// timeEntryToMap converts a time entry record to a map for JSON output.
+func timeEntryToMap(entry *core.Record, now time.Time) map[string]any {
+	result := map[string]any{
+		"id":               entry.Id,
+		"actor":            entry.GetString("actor"),
+		"source":           entry.GetString("source"),
+		"started_at":       entry.GetDateTime("started_at").Time().Format(time.RFC3339),
+		"duration_seconds": int64(timetrack.EntryDuration(entry, now).Seconds()),
+		"running":          timetrack.IsRunning(entry),
+		"note":             entry.GetString("note"),
+	}
+	if !timetrack.IsRunning(entry) {
+		result["ended_at"] = entry.GetDateTime("ended_at").Time().Format(time.RFC3339)
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+	"github.com/ramtinJ95/EgenSkriven/internal/timetrack"
+)
+
+func TestBuildTimeReport_ByTask(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+	SetupTimeEntriesCollection(t, app)
+
+	task1 := CreateTestTask(t, app, "Parser", "in_progress")
+	task2 := CreateTestTask(t, app, "Lexer", "done")
+
+	now := time.Now()
+	_, err := timetrack.Log(app, task1.Id, "alice", 30*time.Minute, now, "")
+	require.NoError(t, err)
+	_, err = timetrack.Log(app, task1.Id, "bob", time.Hour, now, "")
+	require.NoError(t, err)
+	_, err = timetrack.Log(app, task2.Id, "alice", 2*time.Hour, now, "")
+	require.NoError(t, err)
+
+	rows, err := buildTimeReport(app, "task", time.Time{}, "")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	// Sorted by total time descending
+	assert.Equal(t, "Lexer", rows[0].Name)
+	assert.Equal(t, int64(7200), rows[0].TotalSeconds)
+	assert.Equal(t, "Parser", rows[1].Name)
+	assert.Equal(t, int64(5400), rows[1].TotalSeconds)
+	assert.Equal(t, 2, rows[1].Entries)
+	assert.InDelta(t, 1.5, rows[1].Hours, 0.001)
+}
+
+func TestBuildTimeReport_ByActorSince(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+	SetupTimeEntriesCollection(t, app)
+
+	task := CreateTestTask(t, app, "Parser", "in_progress")
+
+	now := time.Now()
+	_, err := timetrack.Log(app, task.Id, "alice", time.Hour, now, "")
+	require.NoError(t, err)
+	// Logged ten days ago, excluded by --since 7d
+	_, err = timetrack.Log(app, task.Id, "bob", time.Hour, now.AddDate(0, 0, -10), "")
+	require.NoError(t, err)
+
+	rows, err := buildTimeReport(app, "actor", now.AddDate(0, 0, -7), "")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "alice", rows[0].Key)
+}
+
+func TestBuildTimeReport_ByLabel(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	SetupTasksCollection(t, app)
+	SetupTimeEntriesCollection(t, app)
+
+	labelled := CreateTestTask(t, app, "Parser", "in_progress")
+	labelled.Set("labels", []string{"backend", "api"})
+	require.NoError(t, app.Save(labelled))
+	unlabelled := CreateTestTask(t, app, "Docs", "todo")
+
+	now := time.Now()
+	_, err := timetrack.Log(app, labelled.Id, "alice", time.Hour, now, "")
+	require.NoError(t, err)
+	_, err = timetrack.Log(app, unlabelled.Id, "alice", 30*time.Minute, now, "")
+	require.NoError(t, err)
+
+	rows, err := buildTimeReport(app, "label", time.Time{}, "")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	byKey := make(map[string]TimeReportRow)
+	for _, r := range rows {
+		byKey[r.Key] = r
+	}
+	// Time counts toward each label on the task
+	assert.Equal(t, int64(3600), byKey["backend"].TotalSeconds)
+	assert.Equal(t, int64(3600), byKey["api"].TotalSeconds)
+	assert.Equal(t, "(no label)", byKey["-"].Name)
+}
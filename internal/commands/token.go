@@ -0,0 +1,271 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/auth"
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+)
+
+func newTokenCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage API tokens",
+		Long: `Create, list, and revoke API tokens.
+
+Tokens authenticate API requests when auth is enabled
+('egenskriven auth enable'). Each token has a scope:
+
+  read        List and view records
+  write       Full access
+  board:WRK   Full access to one board only (combine with read for
+              read-only access to that board: --scope read,board:WRK)
+
+Tokens created with --agent act as that agent, so the agent mode of each
+board (autonomous, collaborative, supervised) applies to their requests.
+
+The token value is shown once on creation. Store it in $EGENSKRIVEN_TOKEN
+or server.token in ~/.config/egenskriven/config.json.`,
+	}
+
+	cmd.AddCommand(newTokenCreateCmd(app))
+	cmd.AddCommand(newTokenListCmd(app))
+	cmd.AddCommand(newTokenRevokeCmd(app))
+
+	return cmd
+}
+
+// ========== Token Create ==========
+
+func newTokenCreateCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		name      string
+		scopes    []string
+		days      int
+		agentName string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an API token",
+		Example: `  egenskriven token create --scope write --name laptop
+  egenskriven token create --scope read --name dashboard
+  egenskriven token create --scope board:WRK --name ci --days 90
+  egenskriven token create --scope write --name bot --agent claude`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			scope, err := auth.ParseScope(scopes)
+			if err != nil {
+				return out.Error(ExitInvalidArguments, err.Error(), nil)
+			}
+			if days < 0 {
+				return out.Error(ExitInvalidArguments, "--days cannot be negative", nil)
+			}
+
+			if err := app.Bootstrap(); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			// Agents cannot grant themselves API access
+			if err := checkPolicy(app, resolveCaller(app, ""), "", policy.ActionSetMode).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
+			var boardRecord *core.Record
+			if scope.BoardRef != "" {
+				boardRecord, err = board.GetByNameOrPrefix(app, scope.BoardRef)
+				if err != nil {
+					return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+				}
+			}
+
+			boardID := ""
+			if boardRecord != nil {
+				boardID = boardRecord.Id
+			}
+
+			duration := time.Duration(days) * 24 * time.Hour
+			if days == 0 {
+				duration = auth.DefaultTokenDuration
+			}
+
+			record, token, err := auth.CreateToken(app, name, scope.Access, boardID, duration)
+			if err != nil {
+				return out.Error(ExitValidation, err.Error(), nil)
+			}
+
+			// Requests made with an agent token are subject to agent modes
+			if agentName != "" {
+				record.Set("agent", agentName)
+				if err := app.Save(record); err != nil {
+					return out.Error(ExitGeneralError, fmt.Sprintf("failed to set token agent: %v", err), nil)
+				}
+			}
+			expires := time.Now().Add(duration).UTC()
+
+			if jsonOutput {
+				m := tokenToMap(record, boardRecord)
+				m["token"] = token
+				m["expires"] = expires.Format(time.RFC3339)
+				out.WriteJSON(m)
+				return nil
+			}
+
+			out.Success(fmt.Sprintf("Created token %q (%s)", name, formatTokenScope(record, boardRecord)))
+			fmt.Printf("\n  %s\n\n", token)
+			fmt.Printf("Expires: %s\n", expires.Format("2006-01-02"))
+			fmt.Println("This token will not be shown again. Use it with:")
+			fmt.Printf("  export %s=<token>\n", config.TokenEnvVar)
+			if !auth.Enabled(app) {
+				fmt.Println("\nNote: auth is disabled. Enable it with: egenskriven auth enable")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Token name (required)")
+	cmd.Flags().StringSliceVar(&scopes, "scope", nil, "Scope: read, write, or board:<prefix> (required)")
+	cmd.Flags().IntVar(&days, "days", 0, "Days until the token expires (default 365)")
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent the token acts as (subject to agent modes)")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("scope")
+
+	return cmd
+}
+
+// ========== Token List ==========
+
+func newTokenListCmd(app *pocketbase.PocketBase) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List API tokens",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := app.Bootstrap(); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			records, err := auth.Tokens(app)
+			if err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to list tokens: %v", err), nil)
+			}
+
+			boardsMap := make(map[string]*core.Record)
+			allBoards, _ := board.GetAll(app)
+			for _, b := range allBoards {
+				boardsMap[b.Id] = b
+			}
+
+			if jsonOutput {
+				tokens := make([]map[string]any, 0, len(records))
+				for _, r := range records {
+					tokens = append(tokens, tokenToMap(r, boardsMap[r.GetString("board")]))
+				}
+				out.WriteJSON(map[string]any{
+					"tokens": tokens,
+					"count":  len(tokens),
+				})
+				return nil
+			}
+
+			fmt.Println("API TOKENS")
+			fmt.Println(strings.Repeat("-", 40))
+
+			if len(records) == 0 {
+				fmt.Println("No tokens found.")
+				fmt.Println("Create one with: egenskriven token create --scope write --name <name>")
+				return nil
+			}
+
+			for _, r := range records {
+				fmt.Printf("  %-20s %-20s created %s\n",
+					truncateString(r.GetString("name"), 20),
+					formatTokenScope(r, boardsMap[r.GetString("board")]),
+					r.GetDateTime("created").Time().Format("2006-01-02"),
+				)
+			}
+			return nil
+		},
+	}
+}
+
+// ========== Token Revoke ==========
+
+func newTokenRevokeCmd(app *pocketbase.PocketBase) *cobra.Command {
+	return &cobra.Command{
+		Use:     "revoke <name>",
+		Short:   "Revoke an API token",
+		Example: `  egenskriven token revoke ci`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := app.Bootstrap(); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			if err := checkPolicy(app, resolveCaller(app, ""), "", policy.ActionSetMode).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
+			if err := auth.RevokeToken(app, args[0]); err != nil {
+				if errors.Is(err, auth.ErrTokenNotFound) {
+					return out.ErrorWithSuggestion(ExitNotFound,
+						fmt.Sprintf("token not found: %s", args[0]),
+						"Run 'egenskriven token list' to see tokens", nil)
+				}
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to revoke token: %v", err), nil)
+			}
+
+			out.Success(fmt.Sprintf("Revoked token %q", args[0]))
+			return nil
+		},
+	}
+}
+
+// ========== Helper Functions ==========
+
+// tokenToMap converts a token account to a map for JSON output.
+// The token value itself is never stored and so never listed.
+func tokenToMap(record, boardRecord *core.Record) map[string]any {
+	m := map[string]any{
+		"id":      record.Id,
+		"name":    record.GetString("name"),
+		"scope":   record.GetString("scope"),
+		"created": record.GetDateTime("created").Time().Format(time.RFC3339),
+	}
+	if agent := record.GetString("agent"); agent != "" {
+		m["agent"] = agent
+	}
+	if boardRecord != nil {
+		m["board"] = boardRecord.Id
+		m["board_prefix"] = boardRecord.GetString("prefix")
+	}
+	return m
+}
+
+// formatTokenScope formats a token's scope for display (e.g. "read,board:WRK"),
+// noting the agent the token acts as.
+func formatTokenScope(record, boardRecord *core.Record) string {
+	scope := auth.Scope{Access: record.GetString("scope")}
+	if boardRecord != nil {
+		scope.BoardRef = boardRecord.GetString("prefix")
+	}
+	if agent := record.GetString("agent"); agent != "" {
+		return scope.String() + " @" + agent
+	}
+	return scope.String()
+}
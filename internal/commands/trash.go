@@ -0,0 +1,527 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+func newTrashCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List, restore, and purge deleted tasks, epics, and boards",
+		Long: `Deleted tasks, epics, and boards are moved to the trash instead of being
+removed. They keep their relations (parent, blockers, epic, comments) and
+are hidden from every list, search, and suggestion until restored.
+
+Deleting a board trashes its tasks and epics with it; restoring the board
+brings them back. Purging removes trashed records permanently.`,
+	}
+
+	cmd.AddCommand(newTrashListCmd(app))
+	cmd.AddCommand(newTrashRestoreCmd(app))
+	cmd.AddCommand(newTrashPurgeCmd(app))
+
+	return cmd
+}
+
+// ========== Trash List ==========
+
+func newTrashListCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var boardRef string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the trash",
+		Long: `List trashed tasks, epics, and boards, most recently deleted first.
+
+Tasks and epics deleted along with a board are counted on the board.`,
+		Example: `  egenskriven trash list
+  egenskriven trash list --board WRK --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			var result map[string]any
+			var err error
+			if isRemoteMode() {
+				result, err = remoteClient().Trash(boardRef)
+				if err != nil {
+					return remoteFailed(out, err)
+				}
+			} else {
+				var boardRecord *core.Record
+				if boardRef != "" {
+					if boardRecord, err = findBoardInAnyState(app, boardRef); err != nil {
+						return out.Error(ExitNotFound, fmt.Sprintf("board not found: %s", boardRef), nil)
+					}
+				}
+				result, err = trashList(app, boardRecord)
+				if err != nil {
+					return commandFailed(out, err)
+				}
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			var items []struct {
+				Kind      string `json:"kind"`
+				Ref       string `json:"ref"`
+				Name      string `json:"name"`
+				DeletedAt string `json:"deleted_at"`
+				DeletedBy string `json:"deleted_by"`
+				Tasks     int    `json:"tasks"`
+				Epics     int    `json:"epics"`
+			}
+			if err := resultValue(result, "items", &items); err != nil {
+				return out.Error(ExitGeneralError, err.Error(), nil)
+			}
+			if len(items) == 0 {
+				fmt.Println("The trash is empty.")
+				return nil
+			}
+
+			for _, item := range items {
+				name := item.Name
+				if item.Kind == "board" {
+					name += fmt.Sprintf(" (%d tasks, %d epics)", item.Tasks, item.Epics)
+				}
+				deletedAt := item.DeletedAt
+				if t, err := time.Parse(time.RFC3339, deletedAt); err == nil {
+					deletedAt = t.Local().Format("2006-01-02 15:04")
+				}
+				fmt.Printf("%s  %-5s %-9s %-12s %s\n",
+					deletedAt,
+					item.Kind,
+					item.Ref,
+					truncateString(item.DeletedBy, 12),
+					name,
+				)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Only list the trash of this board")
+
+	return cmd
+}
+
+// ========== Trash Restore ==========
+
+func newTrashRestoreCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <ref> [ref...]",
+		Short: "Restore trashed tasks, epics, or boards",
+		Long: `Restore records from the trash.
+
+A reference is a task ID, display ID (WRK-123), or title; an epic ID or
+title; or a board ID, name, or prefix. Only trashed records are matched.
+Restoring a board restores the tasks and epics deleted with it. A task or
+epic whose board is still in the trash cannot be restored on its own.`,
+		Example: `  egenskriven trash restore WRK-123
+  egenskriven trash restore "Q1 Launch"
+  egenskriven trash restore WRK-4 WRK-7`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			caller := resolveCaller(app, "")
+			var restored []map[string]any
+
+			for _, ref := range args {
+				var result map[string]any
+				var err error
+				if isRemoteMode() {
+					result, err = remoteClient().RestoreTrash(trashRequest{Ref: ref, Agent: requestAgent(caller)})
+					if err != nil {
+						return remoteFailed(out, err)
+					}
+				} else {
+					var record *core.Record
+					if record, err = findTrashed(app, ref); err != nil {
+						return commandFailed(out, err)
+					}
+					if err := checkPolicy(app, caller, trashBoardID(record), policy.ActionCreate).Err(); err != nil {
+						return policyDenied(out, err)
+					}
+					if result, err = restoreTrashed(app, record, ref, "cli", caller.Name); err != nil {
+						return commandFailed(out, err)
+					}
+				}
+
+				restored = append(restored, result)
+				if !jsonOutput && !quietMode {
+					fmt.Printf("Restored %s %s: %s\n", resultString(result, "kind"),
+						resultString(result, "ref"), resultString(result, "name"))
+				}
+			}
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{
+					"restored": restored,
+					"count":    len(restored),
+				})
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// ========== Trash Purge ==========
+
+func newTrashPurgeCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		olderThan string
+		force     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Permanently delete trashed records",
+		Long: `Permanently delete records in the trash.
+
+Without --older-than the whole trash is emptied. Tasks and epics deleted
+with a purged board are purged with it. Purged tasks are removed from the
+blockers of remaining tasks. Purging cannot be undone.`,
+		Example: `  egenskriven trash purge --older-than 30d
+  egenskriven trash purge --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			// Purging deletes on every board, so it needs delete rights on all
+			var boards []*core.Record
+			var err error
+			if isRemoteMode() {
+				boards, err = remoteClient().ListRecords("boards", "", "", 0)
+			} else {
+				boards, err = app.FindAllRecords("boards")
+			}
+			if err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to load boards: %v", err), nil)
+			}
+			caller := resolveCaller(app, "")
+			if err := checkPurgePolicy(app, caller, boards); err != nil {
+				return policyDenied(out, err)
+			}
+
+			var before time.Time
+			if olderThan != "" {
+				t, err := parseSince(olderThan)
+				if err != nil {
+					return out.Error(ExitValidation, fmt.Sprintf("invalid --older-than: %v", err), nil)
+				}
+				before = t
+			}
+
+			// Confirm purge
+			if !force && !out.Quiet && !out.JSON {
+				if before.IsZero() {
+					fmt.Print("Permanently delete everything in the trash?")
+				} else {
+					fmt.Printf("Permanently delete records trashed before %s?",
+						before.Local().Format("2006-01-02 15:04"))
+				}
+				fmt.Print("\nType 'yes' to confirm: ")
+
+				var response string
+				fmt.Scanln(&response)
+				if response != "yes" {
+					fmt.Println("Cancelled")
+					return nil
+				}
+			}
+
+			var result trash.PurgeResult
+			if isRemoteMode() {
+				req := trashRequest{Agent: requestAgent(caller)}
+				if !before.IsZero() {
+					req.Before = before.UTC().Format(time.RFC3339)
+				}
+				result, err = remoteClient().PurgeTrash(req)
+			} else {
+				result, err = purgeTrash(app, before)
+			}
+			if err != nil {
+				if isRemoteMode() {
+					return remoteFailed(out, err)
+				}
+				return commandFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{
+					"purged": result,
+				})
+				return nil
+			}
+
+			out.Success(fmt.Sprintf("Purged %d task(s), %d epic(s), and %d board(s)",
+				result.Tasks, result.Epics, result.Boards))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&olderThan, "older-than", "",
+		"Only purge records trashed longer ago than this (e.g. 30d, 2w, 2025-01-15)")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+// ========== Trash Operations ==========
+//
+// Shared by the trash commands and the /trash routes.
+
+// trashList lists the trash, or the trash of a board when boardRecord is
+// set.
+func trashList(app *pocketbase.PocketBase, boardRecord *core.Record) (map[string]any, error) {
+	var boardID string
+	if boardRecord != nil {
+		boardID = boardRecord.Id
+	}
+	items, err := trash.List(app, boardID)
+	if err != nil {
+		return nil, &commandError{
+			code:       ExitGeneralError,
+			message:    fmt.Sprintf("failed to list the trash: %v", err),
+			suggestion: "Run 'egenskriven serve' first to initialize the database",
+		}
+	}
+
+	result := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		result = append(result, trashItemToMap(app, item))
+	}
+	return map[string]any{
+		"items": result,
+		"count": len(result),
+	}, nil
+}
+
+// findTrashed finds a trashed task, epic, or board by reference.
+func findTrashed(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
+	record, err := resolveTrashed(app, ref)
+	if err != nil {
+		return nil, &commandError{
+			code:       ExitNotFound,
+			message:    err.Error(),
+			suggestion: "Use 'egenskriven trash list' to see trashed records",
+		}
+	}
+	return record, nil
+}
+
+// trashBoardID returns the board a trashed record belongs to, or is.
+func trashBoardID(record *core.Record) string {
+	if record.Collection().Name == "boards" {
+		return record.Id
+	}
+	return record.GetString("board")
+}
+
+// restoreTrashed restores a trashed record found by ref.
+func restoreTrashed(app *pocketbase.PocketBase, record *core.Record, ref, source, actor string) (map[string]any, error) {
+	if err := trash.Restore(app, record, source, actor); err != nil {
+		if errors.Is(err, trash.ErrBoardTrashed) {
+			return nil, &commandError{
+				code:       ExitValidation,
+				message:    fmt.Sprintf("cannot restore %s: %v", ref, err),
+				suggestion: "Restore the board with 'egenskriven trash restore <board>'",
+			}
+		}
+		return nil, fmt.Errorf("failed to restore %s: %w", ref, err)
+	}
+
+	kind := trashKind(record)
+	return map[string]any{
+		"id":   record.Id,
+		"kind": kind,
+		"ref":  trashItemRef(app, record),
+		"name": trashItemName(trash.Item{Record: record, Kind: kind}),
+	}, nil
+}
+
+// checkPurgePolicy checks that the caller may delete on every board, as
+// purging does.
+func checkPurgePolicy(app *pocketbase.PocketBase, caller policy.Actor, boards []*core.Record) error {
+	for _, b := range boards {
+		if err := checkPolicy(app, caller, b.Id, policy.ActionDelete).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeTrash permanently deletes the records trashed before a time, or
+// the whole trash for a zero time.
+func purgeTrash(app *pocketbase.PocketBase, before time.Time) (trash.PurgeResult, error) {
+	result, err := trash.Purge(app, before)
+	if err != nil {
+		return result, fmt.Errorf("failed to purge the trash: %w", err)
+	}
+	return result, nil
+}
+
+// ========== Helper Functions ==========
+
+// resolveTrashed finds a trashed task, epic, or board by reference.
+func resolveTrashed(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
+	// Exact ID match in any collection
+	for _, name := range trash.Collections {
+		if record, err := app.FindRecordById(name, ref); err == nil {
+			if !trash.IsTrashed(record) {
+				return nil, fmt.Errorf("%s %s is not in the trash", trashKind(record), ref)
+			}
+			return record, nil
+		}
+	}
+
+	// Task display ID; the board may be in the trash too
+	if prefix, seq, err := board.ParseDisplayID(ref); err == nil {
+		tasks, err := app.FindAllRecords("tasks",
+			dbx.NewExp("seq = {:seq} AND board IN (SELECT id FROM boards WHERE LOWER(prefix) = {:prefix})",
+				dbx.Params{"seq": seq, "prefix": strings.ToLower(prefix)}),
+		)
+		if err == nil && len(tasks) == 1 {
+			if !trash.IsTrashed(tasks[0]) {
+				return nil, fmt.Errorf("task %s is not in the trash", ref)
+			}
+			return tasks[0], nil
+		}
+	}
+
+	// ID prefix, then board prefix or name, then title or name substring;
+	// the first group with matches wins
+	pattern := escapeLikePattern(strings.ToLower(ref))
+	idExpr := dbx.NewExp("id LIKE {:id} ESCAPE '\\'", dbx.Params{"id": pattern + "%"})
+	titleExpr := dbx.NewExp("LOWER(title) LIKE {:title} ESCAPE '\\'", dbx.Params{"title": "%" + pattern + "%"})
+	groups := []map[string]dbx.Expression{
+		{"tasks": idExpr, "epics": idExpr, "boards": idExpr},
+		{"boards": dbx.NewExp("LOWER(prefix) = {:ref} OR LOWER(name) = {:ref}",
+			dbx.Params{"ref": strings.ToLower(ref)})},
+		{"tasks": titleExpr, "epics": titleExpr, "boards": dbx.NewExp("LOWER(name) LIKE {:name} ESCAPE '\\'",
+			dbx.Params{"name": "%" + pattern + "%"})},
+	}
+
+	var matches []*core.Record
+	for _, group := range groups {
+		for _, name := range trash.Collections {
+			expr, ok := group[name]
+			if !ok {
+				continue
+			}
+			records, err := app.FindAllRecords(name, expr, dbx.NewExp("deleted_at != ''"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to search the trash: %w", err)
+			}
+			matches = append(matches, records...)
+		}
+		if len(matches) > 0 {
+			break
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("nothing in the trash matches: %s", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		var lines []string
+		for _, m := range matches {
+			lines = append(lines, fmt.Sprintf("%s %s %s", trashKind(m), trashItemRef(app, m),
+				trashItemName(trash.Item{Record: m, Kind: trashKind(m)})))
+		}
+		return nil, fmt.Errorf("ambiguous reference '%s' matches multiple trashed records:\n  %s",
+			ref, strings.Join(lines, "\n  "))
+	}
+}
+
+// findBoardInAnyState finds a board by name or prefix, including trashed
+// boards.
+func findBoardInAnyState(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
+	if record, err := board.GetByNameOrPrefix(app, ref); err == nil {
+		return record, nil
+	}
+	record, err := resolveTrashed(app, ref)
+	if err != nil || record.Collection().Name != "boards" {
+		return nil, fmt.Errorf("board not found: %s", ref)
+	}
+	return record, nil
+}
+
+// trashKind returns "task", "epic", or "board" for a record.
+func trashKind(record *core.Record) string {
+	return strings.TrimSuffix(record.Collection().Name, "s")
+}
+
+// trashItemRef returns the short reference shown for a trashed record.
+func trashItemRef(app *pocketbase.PocketBase, record *core.Record) string {
+	switch record.Collection().Name {
+	case "tasks":
+		return getTaskDisplayID(app, record)
+	case "boards":
+		return record.GetString("prefix")
+	default:
+		return shortID(record.Id)
+	}
+}
+
+// trashItemName returns the title or name of a trashed record.
+func trashItemName(item trash.Item) string {
+	if item.Kind == "board" {
+		return item.Record.GetString("name")
+	}
+	return item.Record.GetString("title")
+}
+
+// trashItemToMap converts a trash item to a map for JSON output.
+func trashItemToMap(app *pocketbase.PocketBase, item trash.Item) map[string]any {
+	m := map[string]any{
+		"id":         item.Record.Id,
+		"kind":       item.Kind,
+		"ref":        trashItemRef(app, item.Record),
+		"name":       trashItemName(item),
+		"deleted_at": item.DeletedAt.UTC().Format(time.RFC3339),
+		"deleted_by": item.DeletedBy,
+	}
+	if item.Kind == "board" {
+		m["tasks"] = item.Tasks
+		m["epics"] = item.Epics
+	} else {
+		m["board"] = item.Record.GetString("board")
+	}
+	return m
+}
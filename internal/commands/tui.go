@@ -6,13 +6,19 @@ import (
 	"github.com/pocketbase/pocketbase"
 	"github.com/spf13/cobra"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
 	"github.com/ramtinJ95/EgenSkriven/internal/config"
 	"github.com/ramtinJ95/EgenSkriven/internal/tui"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
 )
 
 // newTuiCmd creates the 'tui' command for launching the terminal UI.
 func newTuiCmd(app *pocketbase.PocketBase) *cobra.Command {
-	var boardRef string
+	var (
+		boardRef        string
+		includeArchived bool
+		archivedOnly    bool
+	)
 
 	cmd := &cobra.Command{
 		Use:     "tui",
@@ -30,6 +36,7 @@ Examples:
   egenskriven tui
   egenskriven tui --board work
   egenskriven tui -b WRK
+  egenskriven tui --archived        # Browse archived tasks
 
 Navigation:
   h/l       Move between columns
@@ -50,14 +57,21 @@ Navigation:
 				}
 			}
 
+			// Each TUI action is its own undo batch on the caller's stack
+			undo.Begin(undo.Batch{Actor: resolveCaller(app, "").Name})
+
 			// Run the TUI
-			return tui.Run(app, boardRef)
+			return tui.Run(app, boardRef, archive.ModeFor(includeArchived, archivedOnly))
 		},
 	}
 
 	// Add flags
 	cmd.Flags().StringVarP(&boardRef, "board", "b", "",
 		"Board to open (name or prefix)")
+	cmd.Flags().BoolVar(&includeArchived, "include-archived", false,
+		"Show archived tasks alongside active ones")
+	cmd.Flags().BoolVar(&archivedOnly, "archived", false,
+		"Show only archived tasks")
 
 	return cmd
 }
@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+)
+
+func newUndoCmd(app *pocketbase.PocketBase) *cobra.Command {
+	return newUndoRedoCmd(app, false)
+}
+
+func newRedoCmd(app *pocketbase.PocketBase) *cobra.Command {
+	return newUndoRedoCmd(app, true)
+}
+
+// newUndoRedoCmd creates the 'undo' or 'redo' command. Both walk the
+// caller's own stack of batches, one batch per command or TUI action.
+func newUndoRedoCmd(app *pocketbase.PocketBase, redo bool) *cobra.Command {
+	var (
+		count     int
+		agentName string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Undo your last task changes",
+		Long: `Undo the task changes made by your most recent commands.
+
+Every command that creates, updates, moves, or deletes tasks is recorded as
+one batch, including 'add --stdin' and multi-task deletes; TUI actions and
+bulk operations are recorded the same way. Each user and agent has their
+own undo history.
+
+A batch is only undone if its tasks are unchanged since. If someone else
+edited them in the meantime, the conflicts are reported and nothing is
+reverted.`,
+		Example: `  egenskriven undo
+  egenskriven undo --n 3
+  egenskriven undo --agent claude`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			// Bootstrap the app
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			if count < 1 {
+				return out.Error(ExitInvalidArguments, "--n must be at least 1", nil)
+			}
+
+			caller := resolveCaller(app, agentName)
+			action, done := "undo", "Undone"
+			if redo {
+				action, done = "redo", "Redone"
+			}
+
+			var result map[string]any
+			var err error
+			if isRemoteMode() {
+				result, err = remoteClient().Undo(action, undoRequest{Count: count, Agent: requestAgent(caller)})
+			} else {
+				result, err = undoBatches(app, undoActor(app, caller, "cli"), count, redo)
+			}
+			if err != nil {
+				printUndone(undoneBefore(err, done), done)
+				return undoFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(result)
+				return nil
+			}
+
+			var summaries []*undo.Summary
+			if err := resultValue(result, strings.ToLower(done), &summaries); err != nil {
+				return out.Error(ExitGeneralError, err.Error(), nil)
+			}
+			printUndone(summaries, done)
+			return nil
+		},
+	}
+
+	if redo {
+		cmd.Use = "redo"
+		cmd.Short = "Redo your last undone task changes"
+		cmd.Long = `Re-apply the task changes most recently undone with 'egenskriven undo'.
+
+Making any new change discards what is left to redo. As with undo, tasks
+edited by someone else since are reported as conflicts and left unchanged.`
+		cmd.Example = `  egenskriven redo
+  egenskriven redo --n 2`
+	}
+
+	cmd.Flags().IntVarP(&count, "n", "n", 1, "Number of commands to "+cmd.Use)
+	cmd.Flags().StringVar(&agentName, "agent", "",
+		"Agent whose changes to "+cmd.Use+" (default: your own)")
+
+	return cmd
+}
+
+// ========== Helper Functions ==========
+
+// printUndone prints the batches that were undone or redone.
+func printUndone(summaries []*undo.Summary, done string) {
+	if jsonOutput || quietMode {
+		return
+	}
+	for _, s := range summaries {
+		label := s.Label
+		if label == "" {
+			label = "task changes"
+		}
+		fmt.Printf("%s: %s (%d change(s) to %d task(s))\n", done, label, s.Operations, len(s.Tasks))
+	}
+}
+
+// undoActor is whose stack undo and redo walk. Undo and redo change tasks
+// like any command, so the board policy applies to every change they make.
+func undoActor(app *pocketbase.PocketBase, caller policy.Actor, source string) undo.Actor {
+	return undo.Actor{
+		Name:   caller.Name,
+		Source: source,
+		Check: func(boardID, kind, fromColumn, toColumn string) error {
+			return checkPolicy(app, caller, boardID, undo.PolicyAction(kind, fromColumn, toColumn)).Err()
+		},
+	}
+}
+
+// undoBatches undoes, or with redo redoes, the last count batches of an
+// actor. When a batch fails after others were done, the error's data lists
+// the batches done.
+func undoBatches(app *pocketbase.PocketBase, by undo.Actor, count int, redo bool) (map[string]any, error) {
+	if count < 1 {
+		return nil, newCommandError(ExitInvalidArguments, "--n must be at least 1")
+	}
+	verb, done := "undo", "undone"
+	if redo {
+		verb, done = "redo", "redone"
+	}
+
+	summaries := []*undo.Summary{}
+	for range count {
+		var summary *undo.Summary
+		var err error
+		if redo {
+			summary, err = undo.Redo(app, by)
+		} else {
+			summary, err = undo.Undo(app, by)
+		}
+		if errors.Is(err, undo.ErrNothingToUndo) || errors.Is(err, undo.ErrNothingToRedo) {
+			if len(summaries) > 0 {
+				break
+			}
+			return nil, newCommandError(ExitNotFound, "%s", err.Error())
+		}
+		if err != nil {
+			err = undoError(app, verb, err)
+			var ce *commandError
+			if len(summaries) > 0 && errors.As(err, &ce) {
+				if ce.data == nil {
+					ce.data = map[string]any{}
+				}
+				ce.data[done] = summaries
+			}
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return map[string]any{
+		done:    summaries,
+		"count": len(summaries),
+	}, nil
+}
+
+// undoneBefore returns the batches undone or redone before an undo or
+// redo failed, from the data of its error.
+func undoneBefore(err error, done string) []*undo.Summary {
+	var data map[string]any
+	var ce *commandError
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &ce):
+		data = ce.data
+	case errors.As(err, &apiErr):
+		data = apiErr.Data
+	}
+	var summaries []*undo.Summary
+	_ = resultValue(data, strings.ToLower(done), &summaries)
+	return summaries
+}
+
+// undoFailed reports a failed undo or redo.
+func undoFailed(out *output.Formatter, err error) error {
+	if isRemoteMode() {
+		return remoteFailed(out, err)
+	}
+	var v *policy.Violation
+	if errors.As(err, &v) {
+		return policyDenied(out, v)
+	}
+	return commandFailed(out, err)
+}
+
+// undoError describes a failed undo or redo, listing conflicting tasks.
+func undoError(app *pocketbase.PocketBase, verb string, err error) error {
+	var v *policy.Violation
+	if errors.As(err, &v) {
+		return err
+	}
+
+	var conflictErr *undo.ConflictError
+	if !errors.As(err, &conflictErr) {
+		return fmt.Errorf("failed to %s: %w", verb, err)
+	}
+
+	lines := []string{fmt.Sprintf("cannot %s %q: tasks were changed since", verb, conflictErr.Label)}
+	conflicts := make([]map[string]any, 0, len(conflictErr.Conflicts))
+	for _, c := range conflictErr.Conflicts {
+		displayID := shortID(c.Task)
+		if task, err := app.FindRecordById("tasks", c.Task); err == nil {
+			displayID = getTaskDisplayID(app, task)
+		}
+		lines = append(lines, fmt.Sprintf("  %s %q: %s", displayID, c.Title, c.Reason))
+		conflicts = append(conflicts, map[string]any{
+			"task":       c.Task,
+			"display_id": displayID,
+			"title":      c.Title,
+			"field":      c.Field,
+			"reason":     c.Reason,
+		})
+	}
+
+	return &commandError{
+		code:       ExitConflict,
+		message:    strings.Join(lines, "\n"),
+		suggestion: "Review the tasks and revert the remaining changes manually",
+		data:       map[string]any{"conflicts": conflicts},
+	}
+}
@@ -3,10 +3,12 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/pocketbase/pocketbase"
 	"github.com/spf13/cobra"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
 	"github.com/ramtinJ95/EgenSkriven/internal/resolver"
 )
 
@@ -20,6 +22,10 @@ func newUpdateCmd(app *pocketbase.PocketBase) *cobra.Command {
 		removeLabels    []string
 		blockedBy       []string
 		removeBlockedBy []string
+		estimateVal     float64
+		assign          []string
+		unassign        []string
+		agentName       string
 	)
 
 	cmd := &cobra.Command{
@@ -35,20 +41,23 @@ Examples:
   egenskriven update abc123 --add-label critical --remove-label backlog
   egenskriven update abc123 --blocked-by def456
   egenskriven update abc123 --remove-blocked-by def456
+  egenskriven update abc123 --estimate 5
+  egenskriven update abc123 --estimate 0            # clears estimate
+  egenskriven update abc123 --assign me --unassign claude
   egenskriven update abc123 --description ""  # clears description`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := getFormatter()
 
 			// Bootstrap the app
-			if err := app.Bootstrap(); err != nil {
+			if err := bootstrap(app); err != nil {
 				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
 			}
 
 			taskRef := args[0]
 
 			// Resolve the task
-			task, err := resolver.MustResolve(app, taskRef)
+			task, err := resolveTask(app, taskRef)
 			if err != nil {
 				if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 					return out.AmbiguousError(taskRef, ambErr.Matches)
@@ -56,6 +65,12 @@ Examples:
 				return out.Error(ExitNotFound, err.Error(), nil)
 			}
 
+			// Enforce the agent mode of the board
+			caller := resolveCaller(app, agentName)
+			if err := checkTaskPolicy(app, caller, task, policy.ActionUpdate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
 			// Track changes
 			changes := make(map[string]any)
 
@@ -106,6 +121,33 @@ Examples:
 				task.Set("priority", priority)
 			}
 
+			// Update estimate (0 clears it)
+			if cmd.Flags().Changed("estimate") {
+				if estimateVal < 0 {
+					return out.Error(ExitValidation,
+						fmt.Sprintf("invalid estimate '%g', must be zero or positive", estimateVal), nil)
+				}
+				changes["estimate"] = map[string]any{
+					"from": task.GetFloat("estimate"),
+					"to":   estimateVal,
+				}
+				task.Set("estimate", estimateVal)
+			}
+
+			// Update assignees ("me" expands to the current user)
+			if len(assign) > 0 || len(unassign) > 0 {
+				oldAssignees := getTaskAssignees(task)
+				newAssignees := updateAssignees(oldAssignees,
+					resolveAssigneeNames(assign), resolveAssigneeNames(unassign))
+				if !slices.Equal(oldAssignees, newAssignees) {
+					changes["assignees"] = map[string]any{
+						"from": oldAssignees,
+						"to":   newAssignees,
+					}
+					task.Set("assignees", newAssignees)
+				}
+			}
+
 			// Update labels
 			if len(addLabels) > 0 || len(removeLabels) > 0 {
 				oldLabels := task.GetStringSlice("labels")
@@ -124,7 +166,7 @@ Examples:
 				// Resolve all blocking task references to full IDs (supports display IDs like TST-4)
 				resolvedBlockedBy := make([]string, 0, len(blockedBy))
 				for _, ref := range blockedBy {
-					blockingTask, err := resolver.MustResolve(app, ref)
+					blockingTask, err := resolveTask(app, ref)
 					if err != nil {
 						if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 							return out.AmbiguousError(ref, ambErr.Matches)
@@ -142,7 +184,7 @@ Examples:
 				// Resolve removeBlockedBy references too
 				resolvedRemoveBlockedBy := make([]string, 0, len(removeBlockedBy))
 				for _, ref := range removeBlockedBy {
-					blockingTask, err := resolver.MustResolve(app, ref)
+					blockingTask, err := resolveTask(app, ref)
 					if err != nil {
 						if ambErr, ok := err.(*resolver.AmbiguousError); ok {
 							return out.AmbiguousError(ref, ambErr.Matches)
@@ -160,7 +202,7 @@ Examples:
 					if blockingID == task.Id {
 						return out.Error(ExitValidation, "task cannot block itself", nil)
 					}
-					blockingTask, err := app.FindRecordById("tasks", blockingID)
+					blockingTask, err := findRecordByID(app, "tasks", blockingID)
 					if err != nil {
 						return out.Error(ExitNotFound,
 							fmt.Sprintf("blocking task not found: %s", blockingID), nil)
@@ -186,8 +228,8 @@ Examples:
 				return out.Error(ExitValidation, "no changes specified", nil)
 			}
 
-			// Add to history
-			addHistoryEntry(task, "updated", "", changes)
+			// Record the change in the event log
+			addTaskEvent(task, "updated", agentName, changes)
 
 			// Save the task using hybrid approach (API first, then fallback to direct)
 			if err := updateRecordHybrid(app, task, out); err != nil {
@@ -208,6 +250,10 @@ Examples:
 	cmd.Flags().StringSliceVar(&removeLabels, "remove-label", nil, "Remove label (repeatable)")
 	cmd.Flags().StringSliceVar(&blockedBy, "blocked-by", nil, "Add blocking task ID (repeatable)")
 	cmd.Flags().StringSliceVar(&removeBlockedBy, "remove-blocked-by", nil, "Remove blocking task ID (repeatable)")
+	cmd.Flags().Float64Var(&estimateVal, "estimate", 0, "Estimate in the board's unit (0 clears)")
+	cmd.Flags().StringSliceVar(&assign, "assign", nil, "Assign to a person or agent (repeatable, 'me' for yourself)")
+	cmd.Flags().StringSliceVar(&unassign, "unassign", nil, "Remove assignee (repeatable)")
+	cmd.Flags().StringVar(&agentName, "agent", "", "Agent identifier (subject to the board's agent mode)")
 
 	return cmd
 }
@@ -241,7 +287,17 @@ func updateLabels(current, add, remove []string) []string {
 
 // getTaskBlockedBy extracts blocked_by IDs from a task record.
 func getTaskBlockedBy(task interface{ Get(string) any }) []string {
-	raw := task.Get("blocked_by")
+	return getTaskStringSlice(task, "blocked_by")
+}
+
+// getTaskAssignees extracts assignee names from a task record.
+func getTaskAssignees(task interface{ Get(string) any }) []string {
+	return getTaskStringSlice(task, "assignees")
+}
+
+// getTaskStringSlice extracts a JSON array of strings from a task field.
+func getTaskStringSlice(task interface{ Get(string) any }, field string) []string {
+	raw := task.Get(field)
 	if raw == nil {
 		return []string{}
 	}
@@ -307,7 +363,7 @@ func hasCircularDependency(app *pocketbase.PocketBase, targetID string, blocking
 		}
 
 		// Get the blocked_by list of the current task
-		currentTask, err := app.FindRecordById("tasks", currentID)
+		currentTask, err := findRecordByID(app, "tasks", currentID)
 		if err != nil {
 			continue // Task not found, skip
 		}
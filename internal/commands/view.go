@@ -0,0 +1,895 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/output"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskquery"
+	"github.com/ramtinJ95/EgenSkriven/internal/view"
+)
+
+func newViewCmd(app *pocketbase.PocketBase) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "view",
+		Short: "Manage saved views",
+		Long: `Manage saved filter views.
+
+Views are stored per board and shared with the web UI and the TUI: a view
+saved in the browser can be used with 'list --view', and a view created
+here shows up in the web UI's view list.
+
+'view create' and 'view update' take the filter flags of 'list':
+
+  egenskriven view create "Urgent bugs" --type bug --priority urgent,high
+  egenskriven list --view "Urgent bugs"`,
+	}
+
+	cmd.AddCommand(newViewListCmd(app))
+	cmd.AddCommand(newViewShowCmd(app))
+	cmd.AddCommand(newViewCreateCmd(app))
+	cmd.AddCommand(newViewUpdateCmd(app))
+	cmd.AddCommand(newViewDeleteCmd(app))
+	cmd.AddCommand(newViewFavoriteCmd(app))
+
+	return cmd
+}
+
+// ========== View List ==========
+
+func newViewListCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var boardRef string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List saved views",
+		Example: `  egenskriven view list
+  egenskriven view list --board WRK --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+
+			var views []view.View
+			if isRemoteMode() {
+				views, err = remoteClient().Views(boardRecord.Id)
+			} else {
+				views, err = view.List(app, boardRecord.Id)
+			}
+			if err != nil {
+				if isRemoteMode() {
+					return remoteFailed(out, err)
+				}
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to load views: %v", err), nil)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(viewListResult(boardRecord, views))
+				return nil
+			}
+
+			if len(views) == 0 {
+				fmt.Printf("No views for board '%s'.\n", boardRecord.GetString("name"))
+				fmt.Printf("Save one with: egenskriven view create \"My bugs\" --type bug --assignee me\n")
+				return nil
+			}
+
+			fmt.Printf("VIEWS (%s)\n", boardRecord.GetString("name"))
+			fmt.Println(strings.Repeat("-", 40))
+			for _, v := range views {
+				star := " "
+				if v.IsFavorite {
+					star = "*"
+				}
+				fmt.Printf("%s %-24s %d filter(s), match %s\n",
+					star, truncateString(v.Name, 24), len(v.Filters), v.MatchMode)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+
+	return cmd
+}
+
+// ========== View Show ==========
+
+func newViewShowCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var boardRef string
+
+	cmd := &cobra.Command{
+		Use:     "show <name>",
+		Short:   "Show a saved view's filters",
+		Example: `  egenskriven view show "Urgent bugs"`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			_, v, err := resolveViewArg(app, out, boardRef, args[0])
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				out.WriteJSON(v)
+				return nil
+			}
+
+			fmt.Printf("View:     %s\n", v.Name)
+			fmt.Printf("Favorite: %t\n", v.IsFavorite)
+			fmt.Printf("Match:    %s\n", v.MatchMode)
+			if len(v.Filters) == 0 {
+				fmt.Println("Filters:  none")
+				return nil
+			}
+			fmt.Println("Filters:")
+			for _, f := range v.Filters {
+				fmt.Printf("  %s\n", describeViewFilter(app, f))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+
+	return cmd
+}
+
+// ========== View Create ==========
+
+func newViewCreateCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef  string
+		matchMode string
+		favorite  bool
+		flags     viewFilterFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Save list filters as a view",
+		Long: `Save the given list filter flags as a view on a board.
+
+Filters are combined with AND, or with OR when --match any is given.`,
+		Example: `  egenskriven view create "Urgent bugs" --type bug --priority urgent,high
+  egenskriven view create "Mine" --assignee me --not-blocked --favorite
+  egenskriven view create "Due soon" --query "due:<7d is:open -label:wontfix"
+  egenskriven view create "Triage" --column backlog --label triage --match any`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				return out.Error(ExitValidation, "view name cannot be empty", nil)
+			}
+			if matchMode != view.MatchAll && matchMode != view.MatchAny {
+				return out.Error(ExitValidation,
+					fmt.Sprintf("invalid match mode '%s', must be one of: all, any", matchMode), nil)
+			}
+			if err := flags.resolveMe(); err != nil {
+				return out.Error(ExitValidation, err.Error(), nil)
+			}
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			boardRecord, err := resolveBoardForEpic(app, boardRef)
+			if err != nil {
+				return out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+			}
+			caller := resolveCaller(app, "")
+			if err := checkPolicy(app, caller, boardRecord.Id, policy.ActionCreate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
+			req := viewRequest{
+				Board:    boardRecord.Id,
+				Name:     name,
+				Match:    matchMode,
+				Favorite: favorite,
+				Filters:  &flags,
+				Agent:    requestAgent(caller),
+			}
+			var v view.View
+			if isRemoteMode() {
+				v, err = remoteClient().CreateView(req)
+			} else {
+				v, err = createView(app, boardRecord, req)
+			}
+			if err != nil {
+				return viewFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(v)
+				return nil
+			}
+			out.Success(fmt.Sprintf("Created view '%s' with %d filter(s) on board %s",
+				name, len(v.Filters), boardRecord.GetString("name")))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+	cmd.Flags().StringVar(&matchMode, "match", view.MatchAll, "Combine filters with all (AND) or any (OR)")
+	cmd.Flags().BoolVar(&favorite, "favorite", false, "Mark the view as a favorite")
+	flags.register(cmd)
+
+	return cmd
+}
+
+// ========== View Update ==========
+
+func newViewUpdateCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef  string
+		newName   string
+		matchMode string
+		flags     viewFilterFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update <name>",
+		Short: "Rename a view or replace its filters",
+		Long: `Update a saved view. When any filter flag is given, the view's filters are
+replaced by the given ones; otherwise they are kept.`,
+		Example: `  egenskriven view update "Urgent bugs" --priority urgent
+  egenskriven view update "Urgent bugs" --name "P0 bugs"
+  egenskriven view update "Triage" --match all`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if matchMode != "" && matchMode != view.MatchAll && matchMode != view.MatchAny {
+				return out.Error(ExitValidation,
+					fmt.Sprintf("invalid match mode '%s', must be one of: all, any", matchMode), nil)
+			}
+			if err := flags.resolveMe(); err != nil {
+				return out.Error(ExitValidation, err.Error(), nil)
+			}
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			record, v, err := resolveViewArg(app, out, boardRef, args[0])
+			if err != nil {
+				return err
+			}
+			caller := resolveCaller(app, "")
+			if err := checkPolicy(app, caller, v.Board, policy.ActionUpdate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+
+			req := viewRequest{
+				Board: v.Board,
+				Name:  newName,
+				Match: matchMode,
+				Agent: requestAgent(caller),
+			}
+			if flags.changed(cmd) {
+				req.Filters = &flags
+			}
+			if isRemoteMode() {
+				v, err = remoteClient().ViewAction(v.ID, "update", req)
+			} else {
+				v, err = updateView(app, record, v, req)
+			}
+			if err != nil {
+				return viewFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(v)
+				return nil
+			}
+			out.Success(fmt.Sprintf("Updated view '%s' (%d filter(s), match %s)", v.Name, len(v.Filters), v.MatchMode))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+	cmd.Flags().StringVar(&newName, "name", "", "New name for the view")
+	cmd.Flags().StringVar(&matchMode, "match", "", "Combine filters with all (AND) or any (OR)")
+	flags.register(cmd)
+
+	return cmd
+}
+
+// ========== View Delete ==========
+
+func newViewDeleteCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var boardRef string
+
+	cmd := &cobra.Command{
+		Use:     "delete <name>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a saved view",
+		Example: `  egenskriven view delete "Urgent bugs"`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			record, v, err := resolveViewArg(app, out, boardRef, args[0])
+			if err != nil {
+				return err
+			}
+			// Views are board settings, so deleting one updates the board
+			caller := resolveCaller(app, "")
+			if err := checkPolicy(app, caller, v.Board, policy.ActionUpdate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+			if isRemoteMode() {
+				_, err = remoteClient().ViewAction(v.ID, "delete", viewRequest{Board: v.Board, Agent: requestAgent(caller)})
+			} else {
+				err = deleteView(app, record)
+			}
+			if err != nil {
+				return viewFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(map[string]any{"deleted": v.Name, "id": v.ID})
+				return nil
+			}
+			out.Success(fmt.Sprintf("Deleted view '%s'", v.Name))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+
+	return cmd
+}
+
+// ========== View Favorite ==========
+
+func newViewFavoriteCmd(app *pocketbase.PocketBase) *cobra.Command {
+	var (
+		boardRef string
+		off      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "favorite <name>",
+		Short: "Mark a view as a favorite",
+		Long:  `Mark a view as a favorite. Favorites are listed first in the CLI, TUI and web UI.`,
+		Example: `  egenskriven view favorite "Urgent bugs"
+  egenskriven view favorite "Urgent bugs" --off`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := getFormatter()
+
+			if err := bootstrap(app); err != nil {
+				return out.Error(ExitGeneralError, fmt.Sprintf("failed to bootstrap: %v", err), nil)
+			}
+
+			record, v, err := resolveViewArg(app, out, boardRef, args[0])
+			if err != nil {
+				return err
+			}
+			caller := resolveCaller(app, "")
+			if err := checkPolicy(app, caller, v.Board, policy.ActionUpdate).Err(); err != nil {
+				return policyDenied(out, err)
+			}
+			if isRemoteMode() {
+				v, err = remoteClient().ViewAction(v.ID, "favorite", viewRequest{
+					Board:    v.Board,
+					Favorite: !off,
+					Agent:    requestAgent(caller),
+				})
+			} else {
+				v, err = favoriteView(app, record, v, !off)
+			}
+			if err != nil {
+				return viewFailed(out, err)
+			}
+
+			if jsonOutput {
+				out.WriteJSON(v)
+				return nil
+			}
+			if v.IsFavorite {
+				out.Success(fmt.Sprintf("Marked view '%s' as a favorite", v.Name))
+			} else {
+				out.Success(fmt.Sprintf("View '%s' is no longer a favorite", v.Name))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&boardRef, "board", "b", "", "Board name or prefix (uses default if not specified)")
+	cmd.Flags().BoolVar(&off, "off", false, "Remove the favorite mark")
+
+	return cmd
+}
+
+// ========== Helpers ==========
+
+// resolveViewArg finds a view of a board by name or ID, on the server in
+// remote mode, and outputs the error when there is none. The record is
+// nil in remote mode.
+func resolveViewArg(app *pocketbase.PocketBase, out *output.Formatter, boardRef, ref string) (*core.Record, view.View, error) {
+	boardRecord, err := resolveBoardForEpic(app, boardRef)
+	if err != nil {
+		return nil, view.View{}, out.Error(ExitValidation, fmt.Sprintf("invalid board: %v", err), nil)
+	}
+	if isRemoteMode() {
+		v, err := remoteClient().ShowView(boardRecord.Id, ref)
+		if err != nil {
+			return nil, view.View{}, remoteFailed(out, err)
+		}
+		return nil, v, nil
+	}
+	record, v, err := findView(app, boardRecord, ref)
+	if err != nil {
+		return nil, view.View{}, commandFailed(out, err)
+	}
+	return record, v, nil
+}
+
+// viewFailed reports an error of a view operation, run locally or on the
+// server.
+func viewFailed(out *output.Formatter, err error) error {
+	if isRemoteMode() {
+		return remoteFailed(out, err)
+	}
+	return commandFailed(out, err)
+}
+
+// viewListResult is what 'view list --json' prints.
+func viewListResult(boardRecord *core.Record, views []view.View) map[string]any {
+	return map[string]any{
+		"views": views,
+		"count": len(views),
+		"board": boardRecord.GetString("name"),
+	}
+}
+
+// ========== View Operations ==========
+//
+// Shared by the view commands and the /views routes.
+
+// findView finds a view of a board by name or ID.
+func findView(app *pocketbase.PocketBase, boardRecord *core.Record, ref string) (*core.Record, view.View, error) {
+	record, err := view.Find(app, boardRecord.Id, ref)
+	if err != nil {
+		if errors.Is(err, view.ErrNotFound) {
+			return nil, view.View{}, &commandError{
+				code:       ExitNotFound,
+				message:    fmt.Sprintf("view '%s' not found on board %s", ref, boardRecord.GetString("name")),
+				suggestion: "List views with 'egenskriven view list'",
+			}
+		}
+		return nil, view.View{}, fmt.Errorf("failed to find view: %w", err)
+	}
+	v, err := view.FromRecord(record)
+	if err != nil {
+		return nil, view.View{}, err
+	}
+	return record, v, nil
+}
+
+// createView saves list filters as a view on a board.
+func createView(app *pocketbase.PocketBase, boardRecord *core.Record, req viewRequest) (view.View, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return view.View{}, newCommandError(ExitValidation, "view name cannot be empty")
+	}
+	matchMode := req.Match
+	if matchMode == "" {
+		matchMode = view.MatchAll
+	}
+	if matchMode != view.MatchAll && matchMode != view.MatchAny {
+		return view.View{}, newCommandError(ExitValidation, "invalid match mode '%s', must be one of: all, any", matchMode)
+	}
+	if _, err := view.Find(app, boardRecord.Id, name); err == nil {
+		return view.View{}, &commandError{
+			code:       ExitConflict,
+			message:    fmt.Sprintf("view '%s' already exists on board %s", name, boardRecord.GetString("name")),
+			suggestion: fmt.Sprintf("Change it with 'egenskriven view update \"%s\"'", name),
+		}
+	}
+
+	filters := []view.Filter{}
+	if req.Filters != nil {
+		var err error
+		if filters, err = req.Filters.filters(app, boardRecord.Id); err != nil {
+			return view.View{}, newCommandError(ExitValidation, "%s", err.Error())
+		}
+	}
+
+	collection, err := app.FindCollectionByNameOrId(view.CollectionName)
+	if err != nil {
+		return view.View{}, errors.New("views collection not found - run migrations first")
+	}
+	v := view.View{
+		Name:       name,
+		Board:      boardRecord.Id,
+		Filters:    filters,
+		MatchMode:  matchMode,
+		Display:    view.DefaultDisplay(),
+		IsFavorite: req.Favorite,
+	}
+	record := core.NewRecord(collection)
+	view.ToRecord(v, record)
+	if err := app.Save(record); err != nil {
+		return view.View{}, fmt.Errorf("failed to save view: %w", err)
+	}
+	v.ID = record.Id
+	return v, nil
+}
+
+// updateView renames a view, changes its match mode, or replaces its
+// filters when req.Filters is set.
+func updateView(app *pocketbase.PocketBase, record *core.Record, v view.View, req viewRequest) (view.View, error) {
+	changed := false
+	if newName := strings.TrimSpace(req.Name); newName != "" && newName != v.Name {
+		if existing, err := view.Find(app, v.Board, newName); err == nil && existing.Id != v.ID {
+			return v, newCommandError(ExitConflict, "view '%s' already exists", newName)
+		}
+		v.Name = newName
+		changed = true
+	}
+	if req.Match != "" && req.Match != v.MatchMode {
+		if req.Match != view.MatchAll && req.Match != view.MatchAny {
+			return v, newCommandError(ExitValidation, "invalid match mode '%s', must be one of: all, any", req.Match)
+		}
+		v.MatchMode = req.Match
+		changed = true
+	}
+	if req.Filters != nil {
+		filters, err := req.Filters.filters(app, v.Board)
+		if err != nil {
+			return v, newCommandError(ExitValidation, "%s", err.Error())
+		}
+		v.Filters = filters
+		changed = true
+	}
+	if !changed {
+		return v, newCommandError(ExitInvalidArguments, "nothing to update: give --name, --match, or filter flags")
+	}
+
+	view.ToRecord(v, record)
+	if err := app.Save(record); err != nil {
+		return v, fmt.Errorf("failed to save view: %w", err)
+	}
+	return v, nil
+}
+
+// deleteView deletes a view.
+func deleteView(app *pocketbase.PocketBase, record *core.Record) error {
+	if err := app.Delete(record); err != nil {
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+	return nil
+}
+
+// favoriteView marks a view as a favorite, or removes the mark.
+func favoriteView(app *pocketbase.PocketBase, record *core.Record, v view.View, favorite bool) (view.View, error) {
+	v.IsFavorite = favorite
+	record.Set("is_favorite", v.IsFavorite)
+	if err := app.Save(record); err != nil {
+		return v, fmt.Errorf("failed to save view: %w", err)
+	}
+	return v, nil
+}
+
+// findListView finds the view for 'list --view'. Without a board, the
+// view is looked up by name on every board.
+func findListView(app *pocketbase.PocketBase, boardID, ref string) (view.View, error) {
+	if boardID != "" {
+		record, err := view.Find(app, boardID, ref)
+		if err != nil {
+			return view.View{}, err
+		}
+		return bindView(app, record)
+	}
+
+	records, err := app.FindAllRecords(view.CollectionName,
+		dbx.Or(
+			dbx.HashExp{"id": ref},
+			dbx.NewExp("LOWER(name) = {:name}", dbx.Params{"name": strings.ToLower(ref)}),
+		),
+	)
+	if err != nil {
+		return view.View{}, err
+	}
+	switch len(records) {
+	case 0:
+		return view.View{}, fmt.Errorf("%w: %s", view.ErrNotFound, ref)
+	case 1:
+		return bindView(app, records[0])
+	default:
+		return view.View{}, fmt.Errorf("view '%s' exists on several boards, use --board", ref)
+	}
+}
+
+// bindView reads a view record and compiles its query filters.
+func bindView(app *pocketbase.PocketBase, record *core.Record) (view.View, error) {
+	v, err := view.FromRecord(record)
+	if err != nil {
+		return v, err
+	}
+	return v, v.Bind(app)
+}
+
+// describeViewFilter describes a filter, naming epics and sprints instead
+// of showing their IDs.
+func describeViewFilter(app *pocketbase.PocketBase, f view.Filter) string {
+	collection := map[string]string{"epic": "epics", "sprint": "sprints"}[f.Field]
+	if collection == "" || f.Value == nil {
+		return f.String()
+	}
+	values := f.Values()
+	for i, id := range values {
+		if record, err := findRecordByID(app, collection, id); err == nil {
+			name := record.GetString("title")
+			if name == "" {
+				name = record.GetString("name")
+			}
+			values[i] = name
+		}
+	}
+	return view.Filter{Field: f.Field, Operator: f.Operator, Value: values}.String()
+}
+
+// viewFilterFlags are the list filter flags that can be saved in a view.
+// In remote mode they are sent to the server, which converts them.
+type viewFilterFlags struct {
+	Columns    []string `json:"columns,omitempty"`
+	Types      []string `json:"types,omitempty"`
+	Priorities []string `json:"priorities,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	Search     string   `json:"search,omitempty"`
+	Epic       string   `json:"epic,omitempty"`
+	CreatedBy  string   `json:"created_by,omitempty"`
+	Agent      string   `json:"agent,omitempty"`
+	DueBefore  string   `json:"due_before,omitempty"`
+	DueAfter   string   `json:"due_after,omitempty"`
+	HasDue     bool     `json:"has_due,omitempty"`
+	NoDue      bool     `json:"no_due,omitempty"`
+	HasParent  bool     `json:"has_parent,omitempty"`
+	NoParent   bool     `json:"no_parent,omitempty"`
+	IsBlocked  bool     `json:"is_blocked,omitempty"`
+	NotBlocked bool     `json:"not_blocked,omitempty"`
+	Ready      bool     `json:"ready,omitempty"`
+	NeedInput  bool     `json:"need_input,omitempty"`
+	Sprint     string   `json:"sprint,omitempty"`
+	Assignee   string   `json:"assignee,omitempty"`
+	Query      string   `json:"query,omitempty"`
+}
+
+// viewFilterFlagNames lists the flags of viewFilterFlags.
+var viewFilterFlagNames = []string{
+	"column", "type", "priority", "label", "search", "epic", "created-by", "agent",
+	"due-before", "due-after", "has-due", "no-due", "has-parent", "no-parent",
+	"is-blocked", "not-blocked", "ready", "need-input", "sprint", "assignee", "query",
+}
+
+// register adds the filter flags with the names and meaning of 'list'.
+func (f *viewFilterFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVarP(&f.Columns, "column", "c", nil, "Filter by column (repeatable)")
+	cmd.Flags().StringSliceVarP(&f.Types, "type", "t", nil, "Filter by type (repeatable)")
+	cmd.Flags().StringSliceVarP(&f.Priorities, "priority", "p", nil, "Filter by priority (repeatable)")
+	cmd.Flags().StringSliceVarP(&f.Labels, "label", "l", nil, "Filter by label (repeatable, all must match)")
+	cmd.Flags().StringVarP(&f.Search, "search", "s", "", "Search title (case-insensitive)")
+	cmd.Flags().StringVarP(&f.Epic, "epic", "e", "", "Filter by epic (ID or title)")
+	cmd.Flags().StringVar(&f.CreatedBy, "created-by", "", "Filter by creator (user, agent, cli)")
+	cmd.Flags().StringVar(&f.Agent, "agent", "", "Filter by agent name")
+	cmd.Flags().StringVar(&f.DueBefore, "due-before", "", "Tasks due before date (inclusive)")
+	cmd.Flags().StringVar(&f.DueAfter, "due-after", "", "Tasks due after date (inclusive)")
+	cmd.Flags().BoolVar(&f.HasDue, "has-due", false, "Only tasks with due date set")
+	cmd.Flags().BoolVar(&f.NoDue, "no-due", false, "Only tasks without due date")
+	cmd.Flags().BoolVar(&f.HasParent, "has-parent", false, "Only sub-tasks")
+	cmd.Flags().BoolVar(&f.NoParent, "no-parent", false, "Only top-level tasks")
+	cmd.Flags().BoolVar(&f.IsBlocked, "is-blocked", false, "Only tasks blocked by others")
+	cmd.Flags().BoolVar(&f.NotBlocked, "not-blocked", false, "Only tasks not blocked by others")
+	cmd.Flags().BoolVar(&f.Ready, "ready", false, "Unblocked tasks in todo/backlog")
+	cmd.Flags().BoolVar(&f.NeedInput, "need-input", false, "Only tasks awaiting human input")
+	cmd.Flags().StringVar(&f.Sprint, "sprint", "", "Filter by sprint (name, ID, 'current', or 'none')")
+	cmd.Flags().StringVar(&f.Assignee, "assignee", "", "Filter by assignee (me, <name>, or none)")
+	cmd.Flags().StringVar(&f.Query, "query", "", "Filter with a task query (see 'list --help')")
+}
+
+// changed reports whether any filter flag was given.
+func (f *viewFilterFlags) changed(cmd *cobra.Command) bool {
+	for _, name := range viewFilterFlagNames {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveMe replaces an assignee of "me" with the current user, who is
+// only known on this machine.
+func (f *viewFilterFlags) resolveMe() error {
+	if f.Assignee == "" || strings.EqualFold(f.Assignee, "none") {
+		return nil
+	}
+	name := resolveAssigneeName(f.Assignee)
+	if name == "" {
+		return errors.New("cannot determine who 'me' is; set defaults.author in ~/.config/egenskriven/config.json")
+	}
+	f.Assignee = name
+	return nil
+}
+
+// filters converts the flags to view filters. Epics and sprints are stored
+// by ID; "current" is resolved to the board's active sprint when saving.
+func (f *viewFilterFlags) filters(app *pocketbase.PocketBase, boardID string) ([]view.Filter, error) {
+	if f.HasDue && f.NoDue {
+		return nil, errors.New("--has-due and --no-due are mutually exclusive")
+	}
+	if f.HasParent && f.NoParent {
+		return nil, errors.New("--has-parent and --no-parent are mutually exclusive")
+	}
+	if f.IsBlocked && (f.NotBlocked || f.Ready) {
+		return nil, errors.New("--is-blocked and --not-blocked are mutually exclusive")
+	}
+
+	filters := []view.Filter{}
+	selectFilter := func(field string, values []string) {
+		if len(values) == 1 {
+			filters = append(filters, view.NewFilter(field, view.OpIs, values[0]))
+		} else {
+			filters = append(filters, view.NewFilter(field, view.OpIsAnyOf, values))
+		}
+	}
+
+	columns := f.Columns
+	if f.Ready {
+		columns = []string{"todo", "backlog"}
+	}
+	if f.NeedInput {
+		columns = []string{"need_input"}
+	}
+	for _, col := range columns {
+		if !isValidColumn(col) {
+			return nil, fmt.Errorf("invalid column '%s', must be one of: %v", col, ValidColumns)
+		}
+	}
+	if len(columns) > 0 {
+		selectFilter("column", columns)
+	}
+	for _, t := range f.Types {
+		if !isValidType(t) {
+			return nil, fmt.Errorf("invalid type '%s', must be one of: %v", t, ValidTypes)
+		}
+	}
+	if len(f.Types) > 0 {
+		selectFilter("type", f.Types)
+	}
+	for _, p := range f.Priorities {
+		if !isValidPriority(p) {
+			return nil, fmt.Errorf("invalid priority '%s', must be one of: %v", p, ValidPriorities)
+		}
+	}
+	if len(f.Priorities) > 0 {
+		selectFilter("priority", f.Priorities)
+	}
+	if len(f.Labels) > 0 {
+		filters = append(filters, view.NewFilter("labels", view.OpIncludesAll, f.Labels))
+	}
+	if f.Search != "" {
+		filters = append(filters, view.NewFilter("title", view.OpContains, f.Search))
+	}
+	if f.Epic != "" {
+		epicRecord, err := resolveEpic(app, f.Epic)
+		if err != nil {
+			return nil, fmt.Errorf("invalid epic filter: %v", err)
+		}
+		filters = append(filters, view.NewFilter("epic", view.OpIs, epicRecord.Id))
+	}
+	if f.CreatedBy != "" {
+		filters = append(filters, view.NewFilter("created_by", view.OpIs, f.CreatedBy))
+	}
+	if f.Agent != "" {
+		filters = append(filters, view.NewFilter("created_by_agent", view.OpIs, f.Agent))
+	}
+
+	// The web UI compares due dates strictly; list's flags are inclusive
+	if f.DueBefore != "" {
+		date, err := parseDate(f.DueBefore)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --due-before date: %v", err)
+		}
+		filters = append(filters, view.NewFilter("due_date", view.OpBefore, shiftDate(date, 1)))
+	}
+	if f.DueAfter != "" {
+		date, err := parseDate(f.DueAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --due-after date: %v", err)
+		}
+		filters = append(filters, view.NewFilter("due_date", view.OpAfter, shiftDate(date, -1)))
+	}
+	if f.HasDue {
+		filters = append(filters, view.NewFilter("due_date", view.OpIsSet, nil))
+	}
+	if f.NoDue {
+		filters = append(filters, view.NewFilter("due_date", view.OpIsNotSet, nil))
+	}
+	if f.HasParent {
+		filters = append(filters, view.NewFilter("parent", view.OpIsSet, nil))
+	}
+	if f.NoParent {
+		filters = append(filters, view.NewFilter("parent", view.OpIsNotSet, nil))
+	}
+	if f.IsBlocked {
+		filters = append(filters, view.NewFilter("blocked_by", view.OpIsSet, nil))
+	}
+	if f.NotBlocked || f.Ready {
+		filters = append(filters, view.NewFilter("blocked_by", view.OpIsNotSet, nil))
+	}
+
+	if f.Sprint != "" {
+		ids, matchNone, err := resolveSprintFilter(app, boardID, f.Sprint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sprint filter: %v", err)
+		}
+		if matchNone {
+			filters = append(filters, view.NewFilter("sprint", view.OpIsNotSet, nil))
+		} else {
+			selectFilter("sprint", ids)
+		}
+	}
+	if f.Assignee != "" {
+		if strings.EqualFold(f.Assignee, "none") {
+			filters = append(filters, view.NewFilter("assignees", view.OpIsNotSet, nil))
+		} else {
+			name := resolveAssigneeName(f.Assignee)
+			if name == "" {
+				return nil, errors.New("cannot determine who 'me' is; set defaults.author in ~/.config/egenskriven/config.json")
+			}
+			filters = append(filters, view.NewFilter("assignees", view.OpIncludesAny, []string{name}))
+		}
+	}
+	if strings.TrimSpace(f.Query) != "" {
+		compiled, err := taskquery.ParseAndCompile(app, f.Query, taskquery.Options{BoardID: boardID})
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, view.NewQueryFilter(compiled.String()))
+	}
+
+	return filters, nil
+}
+
+// shiftDate moves a parsed date (as returned by parseDate) by days and
+// returns it as YYYY-MM-DD.
+func shiftDate(date string, days int) string {
+	t, err := time.Parse("2006-01-02", date[:min(10, len(date))])
+	if err != nil {
+		return date
+	}
+	return t.AddDate(0, 0, days).Format("2006-01-02")
+}
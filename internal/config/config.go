@@ -25,10 +25,106 @@ var ValidModes = []string{"autonomous", "collaborative", "supervised"}
 // ValidResumeModes is the list of valid resume mode values.
 var ValidResumeModes = []string{"manual", "command", "auto"}
 
+// ValidTimeTrackingModes is the list of valid time tracking mode values.
+var ValidTimeTrackingModes = []string{"auto", "manual"}
+
+// Server modes: how the CLI reaches the tasks.
+const (
+	// ServerModeHybrid uses the server when it is running and the local
+	// database otherwise.
+	ServerModeHybrid = "hybrid"
+	// ServerModeRemote sends every command to the server and never opens
+	// a local database.
+	ServerModeRemote = "remote"
+)
+
+// ValidServerModes is the list of valid server.mode values.
+var ValidServerModes = []string{ServerModeHybrid, ServerModeRemote}
+
 // ServerConfig defines server connection settings for CLI hybrid mode.
 type ServerConfig struct {
 	// URL is the PocketBase server URL (default: http://localhost:8090)
 	URL string `json:"url,omitempty"`
+
+	// Mode is "hybrid" (default) or "remote". In remote mode every command
+	// goes through the server at URL, so several machines can share one
+	// server; there is no fallback to a local database.
+	Mode string `json:"mode,omitempty"`
+
+	// Token is the API token sent to servers with auth enabled.
+	// Only read from the global config so it never ends up in a repository;
+	// the EGENSKRIVEN_TOKEN environment variable takes precedence.
+	Token string `json:"token,omitempty"`
+
+	// CalendarToken protects the calendar feeds the server publishes at
+	// /api/egenskriven/calendar/<board>.ics; subscribers pass it as
+	// ?token=. Only read from the global config, like Token; the
+	// EGENSKRIVEN_CALENDAR_TOKEN environment variable takes precedence.
+	CalendarToken string `json:"calendar_token,omitempty"`
+}
+
+// TokenEnvVar is the environment variable holding the API token.
+const TokenEnvVar = "EGENSKRIVEN_TOKEN"
+
+// CalendarTokenEnvVar is the environment variable holding the calendar
+// feed token.
+const CalendarTokenEnvVar = "EGENSKRIVEN_CALENDAR_TOKEN"
+
+// TimeTrackingConfig defines how automatic task timers behave.
+type TimeTrackingConfig struct {
+	// Mode controls automatic timers: "auto" or "manual"
+	// - auto: Timers start when a task enters a tracked column (default)
+	// - manual: Only `time start`/`time stop`/`time log` record time
+	Mode string `json:"mode,omitempty"`
+
+	// Columns are the columns in which an automatic timer runs.
+	// Leaving these columns (e.g. into need_input) pauses the timer;
+	// returning resumes it with a new entry. Default: ["in_progress"]
+	Columns []string `json:"columns,omitempty"`
+}
+
+// AutoTimerEnabled reports whether automatic timers are enabled.
+func (t TimeTrackingConfig) AutoTimerEnabled() bool {
+	return t.Mode != "manual"
+}
+
+// TrackedColumns returns the columns in which automatic timers run.
+func (t TimeTrackingConfig) TrackedColumns() []string {
+	if len(t.Columns) == 0 {
+		return []string{"in_progress"}
+	}
+	return t.Columns
+}
+
+// AttachmentsConfig limits the files attached to tasks and comments.
+type AttachmentsConfig struct {
+	// MaxFileMB is the size limit of a single attached file in megabytes.
+	// Default: 10
+	MaxFileMB float64 `json:"max_file_mb,omitempty"`
+
+	// MaxTotalMB is the size limit of all files attached to one task or
+	// comment in megabytes. Default: 50
+	MaxTotalMB float64 `json:"max_total_mb,omitempty"`
+}
+
+// MaxFileSize returns the size limit of a single attached file in bytes.
+func (a AttachmentsConfig) MaxFileSize() int64 {
+	return megabytes(a.MaxFileMB, 10)
+}
+
+// MaxTotalSize returns the size limit of the files attached to one task or
+// comment in bytes.
+func (a AttachmentsConfig) MaxTotalSize() int64 {
+	return megabytes(a.MaxTotalMB, 50)
+}
+
+// megabytes converts a size in megabytes to bytes, using fallback when the
+// size is unset or not positive.
+func megabytes(mb, fallback float64) int64 {
+	if mb <= 0 {
+		mb = fallback
+	}
+	return int64(mb * 1024 * 1024)
 }
 
 // AgentConfig defines agent-specific behavior settings.
@@ -64,9 +160,13 @@ type AgentConfig struct {
 // Config represents the project configuration.
 // Location: .egenskriven/config.json
 type Config struct {
-	Agent        AgentConfig  `json:"agent"`
-	Server       ServerConfig `json:"server,omitempty"`
-	DefaultBoard string       `json:"default_board,omitempty"` // Default board prefix for CLI commands
+	Agent        AgentConfig        `json:"agent"`
+	Server       ServerConfig       `json:"server,omitempty"`
+	DefaultBoard string             `json:"default_board,omitempty"` // Default board prefix for CLI commands
+	TimeTracking TimeTrackingConfig `json:"time_tracking,omitempty"`
+	Attachments  AttachmentsConfig  `json:"attachments,omitempty"`
+	// ListFormats are named Go templates for 'list --format <name>'
+	ListFormats map[string]string `json:"list_formats,omitempty"`
 }
 
 // DefaultsConfig contains default values for commands.
@@ -89,6 +189,8 @@ type GlobalConfig struct {
 	Agent AgentConfig `json:"agent,omitempty"`
 	// Server contains server connection settings
 	Server ServerConfig `json:"server,omitempty"`
+	// ListFormats are named Go templates for 'list --format <name>'
+	ListFormats map[string]string `json:"list_formats,omitempty"`
 }
 
 // MergedConfig represents the effective configuration after merging
@@ -99,11 +201,14 @@ type MergedConfig struct {
 	Defaults DefaultsConfig
 
 	// Merged (project overrides global)
-	Agent  AgentConfig
-	Server ServerConfig
+	Agent       AgentConfig
+	Server      ServerConfig
+	ListFormats map[string]string // project formats replace global ones of the same name
 
 	// From project only
 	DefaultBoard string
+	TimeTracking TimeTrackingConfig
+	Attachments  AttachmentsConfig
 }
 
 // DefaultConfig returns project configuration with default values.
@@ -158,6 +263,40 @@ func LoadGlobalConfig() (*GlobalConfig, error) {
 	return globalConfigCache, globalConfigError
 }
 
+// CurrentUser returns the name identifying the person running the CLI:
+// defaults.author from the global config, falling back to $USER.
+func CurrentUser() string {
+	if cfg, err := LoadGlobalConfig(); err == nil && cfg.Defaults.Author != "" {
+		return cfg.Defaults.Author
+	}
+	return os.Getenv("USER")
+}
+
+// APIToken returns the token to send with API requests: $EGENSKRIVEN_TOKEN,
+// falling back to server.token from the global config.
+func APIToken() string {
+	if token := os.Getenv(TokenEnvVar); token != "" {
+		return token
+	}
+	if cfg, err := LoadGlobalConfig(); err == nil {
+		return cfg.Server.Token
+	}
+	return ""
+}
+
+// CalendarToken returns the token protecting the calendar feeds:
+// $EGENSKRIVEN_CALENDAR_TOKEN, falling back to server.calendar_token from
+// the global config. Empty means the feeds need no calendar token.
+func CalendarToken() string {
+	if token := os.Getenv(CalendarTokenEnvVar); token != "" {
+		return token
+	}
+	if cfg, err := LoadGlobalConfig(); err == nil {
+		return cfg.Server.CalendarToken
+	}
+	return ""
+}
+
 // loadGlobalConfigFromDisk reads the global config from disk.
 // This is the internal implementation; use LoadGlobalConfig for cached access.
 func loadGlobalConfigFromDisk() (*GlobalConfig, error) {
@@ -201,6 +340,11 @@ func loadGlobalConfigFromDisk() (*GlobalConfig, error) {
 			cfg.Agent.ResumeMode = "command"
 		}
 	}
+	if cfg.Server.Mode != "" {
+		if err := ValidateServerMode(cfg.Server.Mode); err != nil {
+			cfg.Server.Mode = ServerModeHybrid
+		}
+	}
 
 	return cfg, nil
 }
@@ -299,6 +443,28 @@ func ValidateResumeMode(mode string) error {
 	return fmt.Errorf("invalid resume_mode '%s', must be one of: %v", mode, ValidResumeModes)
 }
 
+// ValidateTimeTrackingMode checks if a time tracking mode value is valid.
+// Returns an error if invalid.
+func ValidateTimeTrackingMode(mode string) error {
+	for _, valid := range ValidTimeTrackingModes {
+		if mode == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid time_tracking.mode '%s', must be one of: %v", mode, ValidTimeTrackingModes)
+}
+
+// ValidateServerMode checks if a server mode value is valid.
+// Returns an error if invalid.
+func ValidateServerMode(mode string) error {
+	for _, valid := range ValidServerModes {
+		if mode == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid server.mode '%s', must be one of: %v", mode, ValidServerModes)
+}
+
 // validateConfig ensures config values are valid, normalizing invalid values to defaults.
 func validateConfig(cfg *Config) error {
 	if err := ValidateWorkflow(cfg.Agent.Workflow); err != nil {
@@ -309,6 +475,18 @@ func validateConfig(cfg *Config) error {
 		cfg.Agent.Mode = "autonomous" // Default to autonomous if invalid
 	}
 
+	if cfg.TimeTracking.Mode != "" {
+		if err := ValidateTimeTrackingMode(cfg.TimeTracking.Mode); err != nil {
+			cfg.TimeTracking.Mode = "auto" // Default to auto if invalid
+		}
+	}
+
+	if cfg.Server.Mode != "" {
+		if err := ValidateServerMode(cfg.Server.Mode); err != nil {
+			cfg.Server.Mode = ServerModeHybrid // Default to hybrid if invalid
+		}
+	}
+
 	return nil
 }
 
@@ -362,6 +540,8 @@ func merge(global *GlobalConfig, project *Config) *MergedConfig {
 
 		// Project-only values
 		DefaultBoard: project.DefaultBoard,
+		TimeTracking: project.TimeTracking,
+		Attachments:  project.Attachments,
 	}
 
 	// Override agent settings from project if set
@@ -379,10 +559,23 @@ func merge(global *GlobalConfig, project *Config) *MergedConfig {
 	merged.Agent.RequireSummary = project.Agent.RequireSummary
 	merged.Agent.StructuredSections = project.Agent.StructuredSections
 
-	// Override server URL from project if set
+	// Override server URL and mode from project if set
 	if project.Server.URL != "" {
 		merged.Server.URL = project.Server.URL
 	}
+	if project.Server.Mode != "" {
+		merged.Server.Mode = project.Server.Mode
+	}
+
+	if len(global.ListFormats) > 0 || len(project.ListFormats) > 0 {
+		merged.ListFormats = make(map[string]string)
+		for name, tmpl := range global.ListFormats {
+			merged.ListFormats[name] = tmpl
+		}
+		for name, tmpl := range project.ListFormats {
+			merged.ListFormats[name] = tmpl
+		}
+	}
 
 	return merged
 }
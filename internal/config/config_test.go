@@ -231,6 +231,56 @@ func TestValidateResumeMode(t *testing.T) {
 	}
 }
 
+func TestValidateServerMode(t *testing.T) {
+	assert.NoError(t, ValidateServerMode("hybrid"))
+	assert.NoError(t, ValidateServerMode("remote"))
+	assert.Error(t, ValidateServerMode("local"))
+	assert.Error(t, ValidateServerMode(""))
+}
+
+func TestLoadProjectConfig_TimeTracking(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "egenskriven-config-test-*")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	configDir := filepath.Join(tmpDir, ".egenskriven")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+
+	configContent := `{"time_tracking": {"mode": "bogus", "columns": ["in_progress", "need_input"]}}`
+	require.NoError(t, os.WriteFile(
+		filepath.Join(configDir, "config.json"),
+		[]byte(configContent),
+		0644,
+	))
+
+	cfg, err := LoadProjectConfigFrom(tmpDir)
+
+	require.NoError(t, err)
+	assert.Equal(t, "auto", cfg.TimeTracking.Mode) // Should default to auto
+	assert.True(t, cfg.TimeTracking.AutoTimerEnabled())
+	assert.Equal(t, []string{"in_progress", "need_input"}, cfg.TimeTracking.TrackedColumns())
+}
+
+func TestTimeTrackingConfig_Defaults(t *testing.T) {
+	var tt TimeTrackingConfig
+
+	assert.True(t, tt.AutoTimerEnabled())
+	assert.Equal(t, []string{"in_progress"}, tt.TrackedColumns())
+
+	tt.Mode = "manual"
+	assert.False(t, tt.AutoTimerEnabled())
+}
+
+func TestAttachmentsConfig_Limits(t *testing.T) {
+	var a AttachmentsConfig
+	assert.Equal(t, int64(10<<20), a.MaxFileSize())
+	assert.Equal(t, int64(50<<20), a.MaxTotalSize())
+
+	a = AttachmentsConfig{MaxFileMB: 0.5, MaxTotalMB: -1}
+	assert.Equal(t, int64(512<<10), a.MaxFileSize())
+	assert.Equal(t, int64(50<<20), a.MaxTotalSize(), "invalid limits use the default")
+}
+
 func TestMerge_GlobalOnly(t *testing.T) {
 	global := &GlobalConfig{
 		DataDir: "/data",
@@ -290,7 +340,8 @@ func TestMerge_ProjectOverrides(t *testing.T) {
 			OverrideTodoWrite: true,
 		},
 		Server: ServerConfig{
-			URL: "http://localhost:9999",
+			URL:  "http://localhost:9999",
+			Mode: ServerModeRemote,
 		},
 		DefaultBoard: "WRK",
 	}
@@ -308,6 +359,7 @@ func TestMerge_ProjectOverrides(t *testing.T) {
 	assert.Equal(t, "auto", merged.Agent.ResumeMode)
 	assert.True(t, merged.Agent.OverrideTodoWrite)
 	assert.Equal(t, "http://localhost:9999", merged.Server.URL)
+	assert.Equal(t, ServerModeRemote, merged.Server.Mode)
 	assert.Equal(t, "WRK", merged.DefaultBoard)
 }
 
@@ -341,6 +393,24 @@ func TestMerge_PartialProjectOverride(t *testing.T) {
 	assert.Equal(t, "http://localhost:8090", merged.Server.URL)
 }
 
+func TestMerge_ListFormats(t *testing.T) {
+	global := &GlobalConfig{ListFormats: map[string]string{
+		"short": "{{.display_id}}",
+		"line":  "{{.title}}",
+	}}
+	project := &Config{ListFormats: map[string]string{
+		"line": "{{.display_id}} {{.title}}",
+	}}
+
+	merged := merge(global, project)
+
+	assert.Equal(t, map[string]string{
+		"short": "{{.display_id}}",
+		"line":  "{{.display_id}} {{.title}}",
+	}, merged.ListFormats)
+	assert.Nil(t, merge(&GlobalConfig{}, &Config{}).ListFormats)
+}
+
 // Tests for LoadGlobalConfig with file I/O
 
 func TestTildeExpansion(t *testing.T) {
@@ -0,0 +1,332 @@
+// Package dedupe finds tasks that are likely duplicates of each other.
+//
+// Texts are compared by the trigrams of their words (after lowercasing,
+// dropping stopwords, and stripping plural and tense endings), so
+// different wordings of the same thing such as "Crash when saving" and
+// "App crashes on save" still score high, but lower than the same words.
+// The score of two tasks is the Dice coefficient of their title trigrams,
+// blended with that of their descriptions when both have one.
+//
+// Tasks that belong together are never reported as duplicates of each
+// other: a parent and its subtasks, and subtasks created together from
+// the same template.
+package dedupe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// DefaultThreshold is the score from which tasks count as likely duplicates.
+const DefaultThreshold = 0.6
+
+// MaxMatches is the number of likely duplicates reported for a new task.
+const MaxMatches = 5
+
+// descriptionWeight is the share of the description in the score when both
+// tasks have one.
+const descriptionWeight = 0.3
+
+// Modes of handling likely duplicates when adding tasks.
+const (
+	ModeWarn = "warn" // create the task and report the duplicates
+	ModeSkip = "skip" // do not create the task, report the duplicates
+	ModeFail = "fail" // fail with the duplicates
+	ModeOff  = "off"  // do not check
+)
+
+// Modes lists the valid modes.
+var Modes = []string{ModeWarn, ModeSkip, ModeFail, ModeOff}
+
+// ValidMode reports whether mode is one of Modes.
+func ValidMode(mode string) bool {
+	for _, m := range Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// stopwords are left out of comparisons; they make unrelated titles look
+// alike. Besides common English words they include the verbs that start
+// many task titles ("Add", "Fix"). Labels like "Bug:" are kept, as they
+// tell tasks apart.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "into": true,
+	"is": true, "it": true, "of": true, "on": true, "or": true, "the": true,
+	"to": true, "when": true, "with": true, "should": true, "we": true,
+	"add": true, "fix": true, "implement": true, "update": true, "create": true,
+	"make": true,
+}
+
+// Profile holds the trigrams of a task's title and description.
+type Profile struct {
+	title       map[string]struct{}
+	description map[string]struct{}
+}
+
+// NewProfile builds the profile of a title and description.
+func NewProfile(title, description string) Profile {
+	return Profile{title: trigrams(title), description: trigrams(description)}
+}
+
+// ProfileOf builds the profile of a task.
+func ProfileOf(task *core.Record) Profile {
+	return NewProfile(task.GetString("title"), task.GetString("description"))
+}
+
+// Score returns the similarity of two profiles between 0 and 1.
+func Score(a, b Profile) float64 {
+	score := dice(a.title, b.title)
+	if len(a.description) > 0 && len(b.description) > 0 {
+		score = (1-descriptionWeight)*score + descriptionWeight*dice(a.description, b.description)
+	}
+	return score
+}
+
+// Match is a likely duplicate of a task.
+type Match struct {
+	TaskID    string       `json:"id"`
+	DisplayID string       `json:"display_id"`
+	Title     string       `json:"title"`
+	Column    string       `json:"column"`
+	Score     float64      `json:"score"`
+	Task      *core.Record `json:"-"`
+}
+
+// Percent formats the score of a match as a percentage.
+func (m Match) Percent() string {
+	return Percent(m.Score)
+}
+
+// Percent formats a score as a percentage.
+func Percent(score float64) string {
+	return fmt.Sprintf("%.0f%%", score*100)
+}
+
+// Candidates returns the open tasks of a board: not done, archived or in
+// the trash. An empty boardID selects the tasks without a board.
+func Candidates(app core.App, boardID string) ([]*core.Record, error) {
+	return app.FindAllRecords("tasks",
+		dbx.HashExp{"board": boardID},
+		dbx.Not(dbx.HashExp{"column": flow.ColumnDone}),
+		archive.Filter(app, archive.Active),
+		trash.Exclude(app, "tasks"),
+	)
+}
+
+// Index holds the profiles of candidate tasks.
+type Index struct {
+	displayID func(*core.Record) string
+	tasks     []*core.Record
+	profiles  []Profile
+	templates map[string]string // Template each task was created from, by ID
+}
+
+// NewIndex profiles candidate tasks.
+func NewIndex(app core.App, tasks []*core.Record) *Index {
+	idx := NewIndexFunc(func(task *core.Record) string {
+		return board.TaskDisplayID(app, task)
+	}, tasks)
+
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.Id
+	}
+	// Without the event log, subtasks from templates are compared like
+	// any others
+	idx.templates, _ = taskevent.Templates(app, ids)
+	return idx
+}
+
+// NewIndexFunc profiles candidate tasks whose display IDs are formatted by
+// displayID, for callers without a database. Such an index does not know
+// which tasks were created from templates.
+func NewIndexFunc(displayID func(*core.Record) string, tasks []*core.Record) *Index {
+	idx := &Index{displayID: displayID}
+	for _, task := range tasks {
+		idx.Add(task)
+	}
+	return idx
+}
+
+// Add adds a task to the index, e.g. one created earlier in a batch.
+func (idx *Index) Add(task *core.Record) {
+	idx.tasks = append(idx.tasks, task)
+	idx.profiles = append(idx.profiles, ProfileOf(task))
+}
+
+// Similar returns the indexed tasks scoring at least threshold against a
+// title and description, best first, at most MaxMatches. parent is the ID
+// of the new task's parent, which is never reported.
+func (idx *Index) Similar(title, description, parent string, threshold float64) []Match {
+	p := NewProfile(title, description)
+	var matches []Match
+	for i, task := range idx.tasks {
+		if task.Id == parent {
+			continue
+		}
+		if score := Score(p, idx.profiles[i]); score >= threshold {
+			matches = append(matches, idx.match(task, score))
+		}
+	}
+	sortMatches(matches)
+	if len(matches) > MaxMatches {
+		matches = matches[:MaxMatches]
+	}
+	return matches
+}
+
+// Pair is two indexed tasks that are likely duplicates of each other.
+type Pair struct {
+	A     Match   `json:"a"`
+	B     Match   `json:"b"`
+	Score float64 `json:"score"`
+}
+
+// Pairs returns the pairs of indexed tasks scoring at least threshold,
+// best first, leaving out tasks that belong together. In each pair A is
+// the older task.
+func (idx *Index) Pairs(threshold float64) []Pair {
+	var pairs []Pair
+	for i := range idx.tasks {
+		for j := i + 1; j < len(idx.tasks); j++ {
+			a, b := idx.tasks[i], idx.tasks[j]
+			if idx.related(a, b) {
+				continue
+			}
+			score := Score(idx.profiles[i], idx.profiles[j])
+			if score < threshold {
+				continue
+			}
+			if older(b, a) {
+				a, b = b, a
+			}
+			pairs = append(pairs, Pair{A: idx.match(a, score), B: idx.match(b, score), Score: round(score)})
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		return pairs[i].A.DisplayID < pairs[j].A.DisplayID
+	})
+	return pairs
+}
+
+// related reports whether two tasks belong together: a parent and its
+// subtask, or subtasks created from the same template, whose titles often
+// differ in a word only.
+func (idx *Index) related(a, b *core.Record) bool {
+	if a.GetString("parent") == b.Id || b.GetString("parent") == a.Id {
+		return true
+	}
+	parent := a.GetString("parent")
+	if parent == "" || parent != b.GetString("parent") {
+		return false
+	}
+	template := idx.templates[a.Id]
+	return template != "" && template == idx.templates[b.Id]
+}
+
+func (idx *Index) match(task *core.Record, score float64) Match {
+	return Match{
+		TaskID:    task.Id,
+		DisplayID: idx.displayID(task),
+		Title:     task.GetString("title"),
+		Column:    task.GetString("column"),
+		Score:     round(score),
+		Task:      task,
+	}
+}
+
+// older reports whether task a was created before task b, by board
+// sequence when both have one.
+func older(a, b *core.Record) bool {
+	if sa, sb := a.GetInt("seq"), b.GetInt("seq"); sa > 0 && sb > 0 && a.GetString("board") == b.GetString("board") {
+		return sa < sb
+	}
+	return a.GetDateTime("created").Time().Before(b.GetDateTime("created").Time())
+}
+
+func sortMatches(matches []Match) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+}
+
+// trigrams returns the trigrams of the words of a text. Each word is
+// padded with spaces so short words and word starts carry weight.
+func trigrams(text string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, word := range words(text) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// words returns the lowercased, stemmed words of a text without
+// stopwords.
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, w := range fields {
+		if !stopwords[w] {
+			out = append(out, stem(w))
+		}
+	}
+	return out
+}
+
+// suffixes are stripped from words so that "crashes", "crashed" and
+// "crash" compare equal. Words that differ in more, like "save" and
+// "saving", only share trigrams.
+var suffixes = []string{"ing", "es", "ed", "s"}
+
+// stem strips one plural or tense ending from a word, keeping at least
+// four letters so that short words like "uses" or "need" stay intact.
+func stem(word string) string {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 4 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// dice returns the Dice coefficient of two sets.
+func dice(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for g := range a {
+		if _, ok := b[g]; ok {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// round rounds a score to two decimals for output.
+func round(score float64) float64 {
+	return float64(int(score*100+0.5)) / 100
+}
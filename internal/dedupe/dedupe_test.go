@@ -0,0 +1,205 @@
+package dedupe
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+func TestScore(t *testing.T) {
+	similar := [][2]string{
+		{"Crash when saving", "App crashes on save"},
+		{"Fix login crash", "Login crashes on Safari"},
+		{"Add dark mode", "Implement dark mode"},
+		{"Bug: Crash on save", "Crash on save"},
+	}
+	for _, p := range similar {
+		score := Score(NewProfile(p[0], ""), NewProfile(p[1], ""))
+		assert.GreaterOrEqual(t, score, DefaultThreshold, "%q vs %q", p[0], p[1])
+	}
+
+	different := [][2]string{
+		{"Add dark mode", "Fix login crash"},
+		{"Update README", "Update CHANGELOG"},
+		{"Release 2.0", "Tag 2.0"},
+	}
+	for _, p := range different {
+		score := Score(NewProfile(p[0], ""), NewProfile(p[1], ""))
+		assert.Less(t, score, DefaultThreshold, "%q vs %q", p[0], p[1])
+	}
+
+	// Descriptions count when both tasks have one
+	a := NewProfile("Slow page", "The dashboard takes ten seconds to load")
+	b := NewProfile("Slow page", "Export to CSV fails with a timeout")
+	c := NewProfile("Slow page", "")
+	assert.Less(t, Score(a, b), 1.0)
+	assert.Equal(t, 1.0, Score(a, c))
+	assert.Equal(t, 0.0, Score(NewProfile("", ""), c))
+}
+
+func TestScore_NearMisses(t *testing.T) {
+	// Different wordings score high, but not like the same words
+	score := Score(NewProfile("Crash when saving", ""), NewProfile("Bug: Crash on save", ""))
+	assert.GreaterOrEqual(t, score, DefaultThreshold)
+	assert.Less(t, score, 0.8)
+
+	// Titles sharing all but their subject or kind are not duplicates
+	different := [][2]string{
+		{"Crash on save", "Crash on load"},
+		{"Saving drafts", "Save settings"},
+		{"Bug: login fails", "Feature: login with SSO"},
+		{"Users page", "Use paging"},
+	}
+	for _, p := range different {
+		score := Score(NewProfile(p[0], ""), NewProfile(p[1], ""))
+		assert.Less(t, score, DefaultThreshold, "%q vs %q", p[0], p[1])
+	}
+
+	// Only plural and tense endings are stripped, from longer words
+	for word, want := range map[string]string{
+		"crashes": "crash", "crashed": "crash", "tokens": "token",
+		"saving": "saving", "save": "save", "note": "note", "uses": "uses", "need": "need",
+	} {
+		assert.Equal(t, want, stem(word), word)
+	}
+}
+
+func TestValidMode(t *testing.T) {
+	for _, m := range Modes {
+		assert.True(t, ValidMode(m))
+	}
+	assert.False(t, ValidMode("ignore"))
+}
+
+func setupDedupeTasks(t *testing.T) (*pocketbase.PocketBase, *core.Collection) {
+	t.Helper()
+	app := testutil.NewTestApp(t)
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "description"},
+		&core.TextField{Name: "column"},
+		&core.TextField{Name: "board"},
+		&core.TextField{Name: "parent"},
+		&core.NumberField{Name: "seq"},
+		&core.DateField{Name: "archived_at"},
+		&core.DateField{Name: "deleted_at"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+	return app, tasks
+}
+
+func createDedupeTask(t *testing.T, app *pocketbase.PocketBase, tasks *core.Collection, board, title, column string) *core.Record {
+	t.Helper()
+	task := core.NewRecord(tasks)
+	task.Set("title", title)
+	task.Set("board", board)
+	task.Set("column", column)
+	seq, err := app.CountRecords("tasks")
+	require.NoError(t, err)
+	task.Set("seq", seq+1)
+	require.NoError(t, app.Save(task))
+	return task
+}
+
+func TestCandidatesAndSimilar(t *testing.T) {
+	app, tasks := setupDedupeTasks(t)
+	open := createDedupeTask(t, app, tasks, "b1", "Crash when saving", "todo")
+	createDedupeTask(t, app, tasks, "b1", "Crash on save", "done")
+	createDedupeTask(t, app, tasks, "b2", "Crash when saving files", "todo")
+	archived := createDedupeTask(t, app, tasks, "b1", "Saving crashes the app", "backlog")
+	archived.Set("archived_at", "2026-01-01 00:00:00.000Z")
+	require.NoError(t, app.Save(archived))
+	createDedupeTask(t, app, tasks, "b1", "Dark mode", "backlog")
+
+	candidates, err := Candidates(app, "b1")
+	require.NoError(t, err)
+	assert.Len(t, candidates, 2, "done, archived and other boards' tasks are left out")
+
+	idx := NewIndex(app, candidates)
+	matches := idx.Similar("App crashes on save", "", "", DefaultThreshold)
+	require.Len(t, matches, 1)
+	assert.Equal(t, open.Id, matches[0].TaskID)
+	assert.Equal(t, "Crash when saving", matches[0].Title)
+	assert.Equal(t, "64%", matches[0].Percent())
+
+	assert.Empty(t, idx.Similar("Write docs", "", "", DefaultThreshold))
+}
+
+func TestPairs(t *testing.T) {
+	app, tasks := setupDedupeTasks(t)
+	first := createDedupeTask(t, app, tasks, "b1", "Crash when saving", "todo")
+	second := createDedupeTask(t, app, tasks, "b1", "App crashes on save", "backlog")
+	darkMode := createDedupeTask(t, app, tasks, "b1", "Dark mode", "backlog")
+
+	idx := NewIndex(app, []*core.Record{second, first})
+	pairs := idx.Pairs(DefaultThreshold)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, first.Id, pairs[0].A.TaskID, "the older task comes first")
+	assert.Equal(t, second.Id, pairs[0].B.TaskID)
+
+	idx.Add(darkMode)
+	idx.Add(createDedupeTask(t, app, tasks, "b1", "Implement dark mode", "todo"))
+	pairs = idx.Pairs(DefaultThreshold)
+	require.Len(t, pairs, 2, "tasks added to the index are compared too")
+	assert.Equal(t, 1.0, pairs[0].Score, "best pair first")
+	assert.Equal(t, darkMode.Id, pairs[0].A.TaskID)
+}
+
+func TestPairs_Related(t *testing.T) {
+	app, tasks := setupDedupeTasks(t)
+	testutil.CreateTaskEventsCollection(t, app)
+	fromTemplate := func(title, parent string) *core.Record {
+		task := createDedupeTask(t, app, tasks, "b1", title, "todo")
+		task.Set("parent", parent)
+		require.NoError(t, app.Save(task))
+		require.NoError(t, taskevent.Save(app, taskevent.Event{
+			Task: task.Id, Action: taskevent.ActionCreated, Actor: "cli",
+			Metadata: map[string]any{"template": "release"},
+		}))
+		return task
+	}
+
+	root := fromTemplate("Release 2.0 checklist", "")
+	tag := fromTemplate("Release 2.0: tag build", root.Id)
+	notes := fromTemplate("Release 2.0: tag notes", root.Id)
+	child := createDedupeTask(t, app, tasks, "b1", "Release 2.0 checklists", "todo")
+	child.Set("parent", root.Id)
+	require.NoError(t, app.Save(child))
+	other := createDedupeTask(t, app, tasks, "b1", "Release 2.0: tag builds", "todo")
+
+	idx := NewIndex(app, []*core.Record{root, tag, notes, child, other})
+	for _, p := range idx.Pairs(DefaultThreshold) {
+		ids := []string{p.A.TaskID, p.B.TaskID}
+		assert.False(t, slices.Contains(ids, root.Id), "a parent is no duplicate of its subtasks")
+		assert.False(t, slices.Contains(ids, tag.Id) && slices.Contains(ids, notes.Id),
+			"subtasks from one template are no duplicates of each other")
+	}
+	pairs := idx.Pairs(DefaultThreshold)
+	require.NotEmpty(t, pairs)
+	assert.Equal(t, tag.Id, pairs[0].A.TaskID, "other tasks are still compared")
+	assert.Equal(t, other.Id, pairs[0].B.TaskID)
+
+	// A new subtask is no duplicate of its parent
+	assert.NotEmpty(t, idx.Similar("Release 2.0 checklist", "", "", DefaultThreshold))
+	for _, m := range idx.Similar("Release 2.0 checklist", "", root.Id, DefaultThreshold) {
+		assert.NotEqual(t, root.Id, m.TaskID)
+	}
+}
+
+func TestNewIndexFunc(t *testing.T) {
+	task := core.NewRecord(core.NewBaseCollection("tasks")).WithCustomData(true)
+	task.Id = "abc123def456ghi"
+	task.Set("title", "Crash when saving")
+
+	idx := NewIndexFunc(func(*core.Record) string { return "WRK-7" }, []*core.Record{task})
+	matches := idx.Similar("Crash when saving", "", "", DefaultThreshold)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "WRK-7", matches[0].DisplayID)
+}
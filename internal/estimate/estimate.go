@@ -0,0 +1,321 @@
+// Package estimate rolls task estimates up through the task hierarchy.
+//
+// Estimates are optional and measured in a per-board unit (points or
+// hours). A parent task's size is the sum of its subtasks when any of
+// them are estimated, otherwise its own estimate. Remaining work excludes
+// tasks that are done.
+package estimate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// Estimate units configurable per board.
+const (
+	UnitPoints = "points"
+	UnitHours  = "hours"
+)
+
+// ValidUnits lists the accepted board estimate units.
+var ValidUnits = []string{UnitPoints, UnitHours}
+
+// Item is the minimal view of a task needed for a rollup.
+type Item struct {
+	ID       string
+	ParentID string
+	Estimate float64
+	Done     bool
+}
+
+// Totals is the rolled-up size of a task or group of tasks.
+type Totals struct {
+	Total       float64 `json:"total"`
+	Remaining   float64 `json:"remaining"`
+	Estimated   int     `json:"estimated"`
+	Unestimated int     `json:"unestimated"`
+}
+
+// Add returns the sum of two totals.
+func (t Totals) Add(o Totals) Totals {
+	return Totals{
+		Total:       t.Total + o.Total,
+		Remaining:   t.Remaining + o.Remaining,
+		Estimated:   t.Estimated + o.Estimated,
+		Unestimated: t.Unestimated + o.Unestimated,
+	}
+}
+
+// Done returns the completed portion of the total.
+func (t Totals) Done() float64 {
+	return t.Total - t.Remaining
+}
+
+// Rollup computes totals for every item, including the work of its
+// subtasks. Subtasks whose parent is not among items are treated as roots.
+func Rollup(items []Item) map[string]Totals {
+	byID := make(map[string]Item, len(items))
+	children := make(map[string][]string)
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+	for _, it := range items {
+		if it.ParentID != "" {
+			if _, ok := byID[it.ParentID]; ok {
+				children[it.ParentID] = append(children[it.ParentID], it.ID)
+			}
+		}
+	}
+
+	result := make(map[string]Totals, len(items))
+	visiting := make(map[string]bool)
+
+	var visit func(id string) Totals
+	visit = func(id string) Totals {
+		if t, ok := result[id]; ok {
+			return t
+		}
+		// Guard against parent cycles
+		if visiting[id] {
+			return Totals{}
+		}
+		visiting[id] = true
+
+		it := byID[id]
+		var sub Totals
+		for _, childID := range children[id] {
+			sub = sub.Add(visit(childID))
+		}
+
+		var t Totals
+		if sub.Estimated > 0 {
+			t = sub
+			if it.Done {
+				// A finished parent closes out its subtasks
+				t.Remaining = 0
+			}
+		} else {
+			t = own(it)
+		}
+
+		visiting[id] = false
+		result[id] = t
+		return t
+	}
+
+	for _, it := range items {
+		visit(it.ID)
+	}
+	return result
+}
+
+// Sum totals the selected items using a rollup. Items whose parent is also
+// selected are skipped so subtasks are not counted twice.
+func Sum(selected []Item, rollup map[string]Totals) Totals {
+	ids := make(map[string]bool, len(selected))
+	for _, it := range selected {
+		ids[it.ID] = true
+	}
+
+	var t Totals
+	for _, it := range selected {
+		if it.ParentID != "" && ids[it.ParentID] {
+			continue
+		}
+		if r, ok := rollup[it.ID]; ok {
+			t = t.Add(r)
+		} else {
+			t = t.Add(own(it))
+		}
+	}
+	return t
+}
+
+// Effective returns the items that carry work themselves: tasks whose
+// size is not replaced by estimated subtasks. Summing their estimates
+// counts every unit of work exactly once.
+func Effective(items []Item) []Item {
+	parentOf := make(map[string]string, len(items))
+	for _, it := range items {
+		parentOf[it.ID] = it.ParentID
+	}
+
+	// Mark every ancestor of an estimated item
+	replaced := make(map[string]bool)
+	for _, it := range items {
+		if it.Estimate <= 0 {
+			continue
+		}
+		seen := map[string]bool{it.ID: true}
+		for p := it.ParentID; p != "" && !seen[p]; p = parentOf[p] {
+			seen[p] = true
+			replaced[p] = true
+		}
+	}
+
+	var result []Item
+	for _, it := range items {
+		if !replaced[it.ID] {
+			result = append(result, it)
+		}
+	}
+	return result
+}
+
+// own returns an item's totals without considering subtasks.
+func own(it Item) Totals {
+	if it.Estimate <= 0 {
+		return Totals{Unestimated: 1}
+	}
+	t := Totals{Total: it.Estimate, Remaining: it.Estimate, Estimated: 1}
+	if it.Done {
+		t.Remaining = 0
+	}
+	return t
+}
+
+// Items converts task records into rollup items.
+func Items(tasks []*core.Record) []Item {
+	items := make([]Item, 0, len(tasks))
+	for _, t := range tasks {
+		items = append(items, ItemOf(t))
+	}
+	return items
+}
+
+// ItemOf converts a task record into a rollup item.
+func ItemOf(task *core.Record) Item {
+	return Item{
+		ID:       task.Id,
+		ParentID: task.GetString("parent"),
+		Estimate: task.GetFloat("estimate"),
+		Done:     task.GetString("column") == "done",
+	}
+}
+
+// WithDescendants returns the given tasks followed by all of their
+// sub-tasks, at any depth.
+func WithDescendants(app core.App, tasks []*core.Record) []*core.Record {
+	all := append([]*core.Record{}, tasks...)
+	seen := make(map[string]bool, len(tasks))
+	frontier := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		seen[t.Id] = true
+		frontier = append(frontier, t.Id)
+	}
+
+	for len(frontier) > 0 {
+		placeholders := make([]string, len(frontier))
+		params := dbx.Params{}
+		for i, id := range frontier {
+			key := fmt.Sprintf("parent%d", i)
+			placeholders[i] = "{:" + key + "}"
+			params[key] = id
+		}
+		children, err := app.FindAllRecords("tasks",
+			dbx.NewExp("parent IN ("+strings.Join(placeholders, ", ")+")", params),
+			trash.Exclude(app, "tasks"),
+		)
+		if err != nil {
+			break
+		}
+
+		frontier = frontier[:0]
+		for _, c := range children {
+			if seen[c.Id] {
+				continue
+			}
+			seen[c.Id] = true
+			all = append(all, c)
+			frontier = append(frontier, c.Id)
+		}
+	}
+	return all
+}
+
+// ForTask returns a task's estimate rolled up from its sub-tasks.
+func ForTask(app core.App, task *core.Record) Totals {
+	tree := WithDescendants(app, []*core.Record{task})
+	return Rollup(Items(tree))[task.Id]
+}
+
+// ForTasks returns the combined estimate of a set of tasks, such as the
+// tasks linked to an epic, including their sub-tasks.
+func ForTasks(app core.App, tasks []*core.Record) Totals {
+	if len(tasks) == 0 {
+		return Totals{}
+	}
+	rollup := Rollup(Items(WithDescendants(app, tasks)))
+	return Sum(Items(tasks), rollup)
+}
+
+// BoardUnit returns the estimate unit configured for a board, defaulting
+// to points.
+func BoardUnit(app core.App, boardID string) string {
+	if boardID == "" {
+		return UnitPoints
+	}
+	board, err := app.FindRecordById("boards", boardID)
+	if err != nil {
+		return UnitPoints
+	}
+	return NormalizeUnit(board.GetString("estimate_unit"))
+}
+
+// NormalizeUnit returns the board unit, defaulting to points.
+func NormalizeUnit(unit string) string {
+	if unit == UnitHours {
+		return UnitHours
+	}
+	return UnitPoints
+}
+
+// IsValidUnit reports whether unit is an accepted estimate unit.
+func IsValidUnit(unit string) bool {
+	for _, u := range ValidUnits {
+		if u == unit {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders a value with its unit, e.g. "3 pts" or "1.5h".
+func Format(value float64, unit string) string {
+	n := strconv.FormatFloat(value, 'f', -1, 64)
+	if NormalizeUnit(unit) == UnitHours {
+		return n + "h"
+	}
+	if value == 1 {
+		return n + " pt"
+	}
+	return n + " pts"
+}
+
+// Describe renders the estimate of a task for show, list, and the TUI: its
+// own estimate, e.g. "3 pts", or remaining/total when estimated subtasks
+// roll up into something else, e.g. "5/8 pts remaining". rollup is the
+// task's totals, or nil when they are not known. Unestimated tasks give "".
+func Describe(task Item, rollup *Totals, unit string) string {
+	if rollup != nil && rollup.Estimated > 0 && *rollup != own(task) {
+		return FormatProgress(*rollup, unit)
+	}
+	if task.Estimate > 0 {
+		return Format(task.Estimate, unit)
+	}
+	return ""
+}
+
+// FormatProgress renders remaining/total, e.g. "5/8 pts remaining".
+func FormatProgress(t Totals, unit string) string {
+	if t.Estimated == 0 {
+		return "unestimated"
+	}
+	return strconv.FormatFloat(t.Remaining, 'f', -1, 64) + "/" + Format(t.Total, unit) + " remaining"
+}
@@ -0,0 +1,175 @@
+package estimate
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+func TestRollup_LeafTasks(t *testing.T) {
+	items := []Item{
+		{ID: "a", Estimate: 3},
+		{ID: "b", Estimate: 2, Done: true},
+		{ID: "c"},
+	}
+
+	r := Rollup(items)
+
+	assert.Equal(t, Totals{Total: 3, Remaining: 3, Estimated: 1}, r["a"])
+	assert.Equal(t, Totals{Total: 2, Remaining: 0, Estimated: 1}, r["b"])
+	assert.Equal(t, Totals{Unestimated: 1}, r["c"])
+}
+
+func TestRollup_SubtasksOverrideParent(t *testing.T) {
+	items := []Item{
+		{ID: "parent", Estimate: 10},
+		{ID: "s1", ParentID: "parent", Estimate: 3, Done: true},
+		{ID: "s2", ParentID: "parent", Estimate: 2},
+		{ID: "s3", ParentID: "parent"},
+	}
+
+	r := Rollup(items)
+
+	assert.Equal(t, Totals{Total: 5, Remaining: 2, Estimated: 2, Unestimated: 1}, r["parent"])
+}
+
+func TestRollup_UnestimatedSubtasksKeepParentEstimate(t *testing.T) {
+	items := []Item{
+		{ID: "parent", Estimate: 8},
+		{ID: "s1", ParentID: "parent"},
+	}
+
+	r := Rollup(items)
+
+	assert.Equal(t, Totals{Total: 8, Remaining: 8, Estimated: 1}, r["parent"])
+}
+
+func TestRollup_DoneParentHasNoRemaining(t *testing.T) {
+	items := []Item{
+		{ID: "parent", Done: true},
+		{ID: "s1", ParentID: "parent", Estimate: 3},
+	}
+
+	r := Rollup(items)
+
+	assert.Equal(t, 3.0, r["parent"].Total)
+	assert.Equal(t, 0.0, r["parent"].Remaining)
+}
+
+func TestRollup_NestedAndCycle(t *testing.T) {
+	items := []Item{
+		{ID: "root"},
+		{ID: "mid", ParentID: "root"},
+		{ID: "leaf", ParentID: "mid", Estimate: 1.5},
+		{ID: "x", ParentID: "y", Estimate: 1},
+		{ID: "y", ParentID: "x", Estimate: 2},
+	}
+
+	r := Rollup(items)
+
+	assert.Equal(t, 1.5, r["root"].Total)
+	// Cycles terminate without panicking
+	assert.Contains(t, r, "x")
+	assert.Contains(t, r, "y")
+}
+
+func TestSum_SkipsSelectedSubtasks(t *testing.T) {
+	items := []Item{
+		{ID: "p"},
+		{ID: "s1", ParentID: "p", Estimate: 2},
+		{ID: "s2", ParentID: "p", Estimate: 3, Done: true},
+		{ID: "q", Estimate: 5},
+	}
+	r := Rollup(items)
+
+	total := Sum(items, r)
+
+	assert.Equal(t, Totals{Total: 10, Remaining: 7, Estimated: 3}, total)
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "1 pt", Format(1, ""))
+	assert.Equal(t, "2.5 pts", Format(2.5, UnitPoints))
+	assert.Equal(t, "4h", Format(4, UnitHours))
+	assert.Equal(t, "2/5 pts remaining", FormatProgress(Totals{Total: 5, Remaining: 2, Estimated: 2}, UnitPoints))
+	assert.Equal(t, "unestimated", FormatProgress(Totals{Unestimated: 2}, UnitPoints))
+}
+
+func TestDescribe(t *testing.T) {
+	task := Item{ID: "t", Estimate: 2}
+	rollup := Rollup([]Item{task})["t"]
+
+	// A task without estimated subtasks shows its own estimate, whether
+	// or not the rollup is known
+	assert.Equal(t, "2 pts", Describe(task, nil, UnitPoints))
+	assert.Equal(t, "2 pts", Describe(task, &rollup, UnitPoints))
+	done := Item{ID: "t", Estimate: 2, Done: true}
+	doneRollup := Rollup([]Item{done})["t"]
+	assert.Equal(t, "2 pts", Describe(done, &doneRollup, UnitPoints))
+
+	// Estimated subtasks replace it with their progress
+	items := []Item{task, {ID: "s1", ParentID: "t", Estimate: 3, Done: true}, {ID: "s2", ParentID: "t", Estimate: 5}}
+	parent := Rollup(items)["t"]
+	assert.Equal(t, "5/8 pts remaining", Describe(task, &parent, UnitPoints))
+
+	assert.Equal(t, "", Describe(Item{ID: "u"}, nil, UnitPoints))
+	assert.Equal(t, "", Describe(Item{ID: "u"}, &Totals{Unestimated: 1}, UnitPoints))
+}
+
+func TestUnits(t *testing.T) {
+	assert.Equal(t, UnitPoints, NormalizeUnit(""))
+	assert.Equal(t, UnitHours, NormalizeUnit(UnitHours))
+	assert.True(t, IsValidUnit(UnitHours))
+	assert.False(t, IsValidUnit("days"))
+}
+
+func TestEffective_CountsWorkOnce(t *testing.T) {
+	items := []Item{
+		{ID: "root", Estimate: 20},
+		{ID: "mid", ParentID: "root"},
+		{ID: "leaf", ParentID: "mid", Estimate: 2},
+		{ID: "solo", Estimate: 1},
+		{ID: "plain"},
+	}
+
+	var ids []string
+	for _, it := range Effective(items) {
+		ids = append(ids, it.ID)
+	}
+
+	assert.Equal(t, []string{"leaf", "solo", "plain"}, ids)
+}
+
+func TestForTask_LoadsNestedSubtasks(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "column"},
+		&core.TextField{Name: "parent"},
+		&core.NumberField{Name: "estimate"},
+	)
+
+	create := func(title, column, parent string, est float64) *core.Record {
+		r := core.NewRecord(tasks)
+		r.Set("title", title)
+		r.Set("column", column)
+		r.Set("parent", parent)
+		r.Set("estimate", est)
+		require.NoError(t, app.Save(r))
+		return r
+	}
+
+	root := create("root", "todo", "", 0)
+	mid := create("mid", "in_progress", root.Id, 0)
+	create("leaf-a", "done", mid.Id, 2)
+	create("leaf-b", "todo", mid.Id, 3)
+	other := create("other", "todo", "", 5)
+
+	assert.Equal(t, Totals{Total: 5, Remaining: 3, Estimated: 2}, ForTask(app, root))
+	assert.Equal(t, Totals{Total: 10, Remaining: 8, Estimated: 3}, ForTasks(app, []*core.Record{root, other}))
+	assert.Equal(t, UnitPoints, BoardUnit(app, ""))
+}
@@ -0,0 +1,478 @@
+// Package flow computes kanban flow metrics from task column changes.
+//
+// The task event log records every column {from, to} change of a task.
+// Replaying these transitions gives each task's timeline, from which the
+// metrics are derived:
+//   - Lead time: task created -> last moved to done
+//   - Cycle time: first moved to in_progress -> last moved to done
+//   - Time in column, including time blocked in need_input
+//   - Weekly throughput and daily cumulative-flow snapshots
+package flow
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Column names with special meaning for the metrics.
+const (
+	ColumnDone       = "done"
+	ColumnInProgress = "in_progress"
+	ColumnNeedInput  = "need_input"
+)
+
+// Actor kinds used to split results.
+const (
+	ActorHuman = "human"
+	ActorAgent = "agent"
+)
+
+// Transition is a single column change of a task.
+type Transition struct {
+	At          time.Time
+	From        string
+	To          string
+	Actor       string
+	ActorDetail string
+}
+
+// Timeline is the column history of a single task.
+type Timeline struct {
+	TaskID        string
+	DisplayID     string
+	Title         string
+	Created       time.Time
+	CreatedBy     string
+	InitialColumn string
+	CurrentColumn string
+	Transitions   []Transition
+}
+
+// TaskInput is the raw task data needed to build a timeline.
+type TaskInput struct {
+	ID          string
+	DisplayID   string
+	Title       string
+	Column      string
+	CreatedBy   string
+	Created     time.Time
+	Transitions []Transition
+}
+
+// NewTimeline builds a timeline from a task and its recorded column
+// changes, in any order.
+func NewTimeline(in TaskInput) Timeline {
+	tl := Timeline{
+		TaskID:        in.ID,
+		DisplayID:     in.DisplayID,
+		Title:         in.Title,
+		Created:       in.Created,
+		CreatedBy:     in.CreatedBy,
+		CurrentColumn: in.Column,
+	}
+
+	for _, t := range in.Transitions {
+		if t.To == "" || t.From == t.To {
+			continue
+		}
+		tl.Transitions = append(tl.Transitions, t)
+	}
+
+	sort.SliceStable(tl.Transitions, func(i, j int) bool {
+		return tl.Transitions[i].At.Before(tl.Transitions[j].At)
+	})
+
+	if len(tl.Transitions) > 0 {
+		tl.InitialColumn = tl.Transitions[0].From
+	} else {
+		tl.InitialColumn = in.Column
+	}
+	if tl.Created.IsZero() && len(tl.Transitions) > 0 {
+		tl.Created = tl.Transitions[0].At
+	}
+
+	return tl
+}
+
+// DoneAt returns when the task last moved to done, if it is currently done.
+func (tl Timeline) DoneAt() (time.Time, bool) {
+	if tl.CurrentColumn != ColumnDone {
+		return time.Time{}, false
+	}
+	for i := len(tl.Transitions) - 1; i >= 0; i-- {
+		if tl.Transitions[i].To == ColumnDone {
+			return tl.Transitions[i].At, true
+		}
+	}
+	// Created directly in done: no meaningful completion time
+	return time.Time{}, false
+}
+
+// StartedAt returns when the task first moved to in_progress.
+func (tl Timeline) StartedAt() (time.Time, bool) {
+	for _, t := range tl.Transitions {
+		if t.To == ColumnInProgress {
+			return t.At, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// LeadTime returns the time from creation to completion.
+func (tl Timeline) LeadTime() (time.Duration, bool) {
+	done, ok := tl.DoneAt()
+	if !ok || tl.Created.IsZero() {
+		return 0, false
+	}
+	return nonNegative(done.Sub(tl.Created)), true
+}
+
+// CycleTime returns the time from first in_progress to completion.
+func (tl Timeline) CycleTime() (time.Duration, bool) {
+	done, ok := tl.DoneAt()
+	if !ok {
+		return 0, false
+	}
+	started, ok := tl.StartedAt()
+	if !ok || started.After(done) {
+		return 0, false
+	}
+	return done.Sub(started), true
+}
+
+// TimeInColumns returns how long the task spent in each column up to until.
+// Time after the task reaches done is not counted, nor time the log does
+// not account for: when a change is missing from the log, the next
+// transition leaves another column than the task last entered, and the
+// time in between is left out rather than guessed.
+func (tl Timeline) TimeInColumns(until time.Time) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	if tl.Created.IsZero() {
+		return result
+	}
+
+	column := tl.InitialColumn
+	since := tl.Created
+	for _, t := range tl.Transitions {
+		if column != ColumnDone && column == t.From {
+			result[column] += nonNegative(t.At.Sub(since))
+		}
+		column = t.To
+		since = t.At
+	}
+	if column != ColumnDone && until.After(since) {
+		result[column] += until.Sub(since)
+	}
+	return result
+}
+
+// ColumnAt returns the column the task was in at the given time,
+// or "" if the task did not exist yet.
+func (tl Timeline) ColumnAt(at time.Time) string {
+	if tl.Created.IsZero() || at.Before(tl.Created) {
+		return ""
+	}
+	column := tl.InitialColumn
+	for _, t := range tl.Transitions {
+		if t.At.After(at) {
+			break
+		}
+		column = t.To
+	}
+	return column
+}
+
+// ActorKind classifies the task as human or agent work. Completed tasks are
+// attributed to the actor that moved them to done; other tasks to their
+// creator.
+func (tl Timeline) ActorKind() string {
+	if _, ok := tl.DoneAt(); ok {
+		for i := len(tl.Transitions) - 1; i >= 0; i-- {
+			if tl.Transitions[i].To == ColumnDone {
+				return ClassifyActor(tl.Transitions[i].Actor, tl.Transitions[i].ActorDetail)
+			}
+		}
+	}
+	return ClassifyActor(tl.CreatedBy, "")
+}
+
+// ClassifyActor maps an event actor to ActorHuman or ActorAgent.
+// Agent entries either use the "agent" actor or carry an agent name
+// in actor_detail.
+func ClassifyActor(actor, detail string) string {
+	if actor == ActorAgent {
+		return ActorAgent
+	}
+	if detail != "" && detail != "user" && actor != "system" {
+		return ActorAgent
+	}
+	return ActorHuman
+}
+
+// Stats summarizes a set of durations.
+type Stats struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean_hours"`
+	P50   float64 `json:"p50_hours"`
+	P85   float64 `json:"p85_hours"`
+	P95   float64 `json:"p95_hours"`
+	Max   float64 `json:"max_hours"`
+}
+
+// NewStats computes summary statistics in hours.
+func NewStats(durations []time.Duration) Stats {
+	if len(durations) == 0 {
+		return Stats{}
+	}
+
+	hours := make([]float64, len(durations))
+	var sum float64
+	for i, d := range durations {
+		hours[i] = d.Hours()
+		sum += hours[i]
+	}
+	sort.Float64s(hours)
+
+	return Stats{
+		Count: len(hours),
+		Mean:  round2(sum / float64(len(hours))),
+		P50:   round2(Percentile(hours, 50)),
+		P85:   round2(Percentile(hours, 85)),
+		P95:   round2(Percentile(hours, 95)),
+		Max:   round2(hours[len(hours)-1]),
+	}
+}
+
+// Percentile returns the p-th percentile of sorted values using linear
+// interpolation between closest ranks.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	frac := rank - float64(lower)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*frac
+}
+
+// TaskMetrics are the per-task results of a flow report.
+type TaskMetrics struct {
+	TaskID         string             `json:"task_id"`
+	DisplayID      string             `json:"display_id"`
+	Title          string             `json:"title"`
+	ActorKind      string             `json:"actor_kind"`
+	CompletedAt    string             `json:"completed_at"`
+	LeadHours      float64            `json:"lead_hours"`
+	CycleHours     *float64           `json:"cycle_hours,omitempty"`
+	BlockedHours   float64            `json:"blocked_hours"`
+	HoursInColumns map[string]float64 `json:"hours_in_columns"`
+}
+
+// GroupMetrics are aggregate lead, cycle, and blocked statistics.
+type GroupMetrics struct {
+	Completed int   `json:"completed"`
+	LeadTime  Stats `json:"lead_time"`
+	CycleTime Stats `json:"cycle_time"`
+	// Blocked is the time in need_input of the tasks that were there
+	Blocked Stats `json:"blocked_time"`
+}
+
+// WeekThroughput is the number of tasks completed in a week.
+type WeekThroughput struct {
+	WeekStart string         `json:"week_start"`
+	Completed int            `json:"completed"`
+	ByActor   map[string]int `json:"by_actor"`
+}
+
+// CFDPoint is the number of tasks in each column at the end of a day.
+type CFDPoint struct {
+	Date    string         `json:"date"`
+	Columns map[string]int `json:"columns"`
+}
+
+// Report is the full flow report.
+type Report struct {
+	Since          string                  `json:"since"`
+	Until          string                  `json:"until"`
+	Columns        []string                `json:"columns"`
+	Overall        GroupMetrics            `json:"overall"`
+	ByActor        map[string]GroupMetrics `json:"by_actor"`
+	TimeInColumns  map[string]Stats        `json:"time_in_columns"`
+	Throughput     []WeekThroughput        `json:"throughput"`
+	CumulativeFlow []CFDPoint              `json:"cumulative_flow"`
+	Tasks          []TaskMetrics           `json:"tasks"`
+}
+
+// BuildReport computes flow metrics for tasks completed in [since, until]
+// and cumulative-flow snapshots for each day in that range. Columns
+// controls the column order and which columns appear in the output.
+func BuildReport(timelines []Timeline, columns []string, since, until time.Time) Report {
+	report := Report{
+		Since:         since.UTC().Format(time.RFC3339),
+		Until:         until.UTC().Format(time.RFC3339),
+		Columns:       columns,
+		ByActor:       make(map[string]GroupMetrics),
+		TimeInColumns: make(map[string]Stats),
+		Tasks:         []TaskMetrics{},
+	}
+
+	type bucket struct {
+		lead, cycle, blocked []time.Duration
+	}
+	overall := &bucket{}
+	byActor := map[string]*bucket{ActorHuman: {}, ActorAgent: {}}
+	columnDurations := make(map[string][]time.Duration)
+
+	weekly := make(map[string]*WeekThroughput)
+	for week := startOfWeek(since); !week.After(until); week = week.AddDate(0, 0, 7) {
+		key := week.Format("2006-01-02")
+		weekly[key] = &WeekThroughput{WeekStart: key, ByActor: map[string]int{ActorHuman: 0, ActorAgent: 0}}
+	}
+
+	for _, tl := range timelines {
+		done, ok := tl.DoneAt()
+		if !ok || done.Before(since) || done.After(until) {
+			continue
+		}
+		lead, ok := tl.LeadTime()
+		if !ok {
+			continue
+		}
+
+		kind := tl.ActorKind()
+		inColumns := tl.TimeInColumns(done)
+		blocked, wasBlocked := inColumns[ColumnNeedInput]
+
+		metrics := TaskMetrics{
+			TaskID:         tl.TaskID,
+			DisplayID:      tl.DisplayID,
+			Title:          tl.Title,
+			ActorKind:      kind,
+			CompletedAt:    done.UTC().Format(time.RFC3339),
+			LeadHours:      round2(lead.Hours()),
+			BlockedHours:   round2(blocked.Hours()),
+			HoursInColumns: make(map[string]float64),
+		}
+
+		buckets := []*bucket{overall, byActor[kind]}
+		for _, b := range buckets {
+			b.lead = append(b.lead, lead)
+			// Tasks never in need_input would dilute the blocked time
+			if wasBlocked {
+				b.blocked = append(b.blocked, blocked)
+			}
+		}
+		if cycle, ok := tl.CycleTime(); ok {
+			hours := round2(cycle.Hours())
+			metrics.CycleHours = &hours
+			for _, b := range buckets {
+				b.cycle = append(b.cycle, cycle)
+			}
+		}
+
+		for column, d := range inColumns {
+			metrics.HoursInColumns[column] = round2(d.Hours())
+			columnDurations[column] = append(columnDurations[column], d)
+		}
+
+		weekKey := startOfWeek(done).Format("2006-01-02")
+		if w, ok := weekly[weekKey]; ok {
+			w.Completed++
+			w.ByActor[kind]++
+		}
+
+		report.Tasks = append(report.Tasks, metrics)
+	}
+
+	toGroup := func(b *bucket) GroupMetrics {
+		return GroupMetrics{
+			Completed: len(b.lead),
+			LeadTime:  NewStats(b.lead),
+			CycleTime: NewStats(b.cycle),
+			Blocked:   NewStats(b.blocked),
+		}
+	}
+	report.Overall = toGroup(overall)
+	for kind, b := range byActor {
+		report.ByActor[kind] = toGroup(b)
+	}
+	for column, durations := range columnDurations {
+		report.TimeInColumns[column] = NewStats(durations)
+	}
+
+	// Stable ordering for output
+	sort.Slice(report.Tasks, func(i, j int) bool {
+		return report.Tasks[i].CompletedAt < report.Tasks[j].CompletedAt
+	})
+
+	weekKeys := make([]string, 0, len(weekly))
+	for key := range weekly {
+		weekKeys = append(weekKeys, key)
+	}
+	sort.Strings(weekKeys)
+	for _, key := range weekKeys {
+		report.Throughput = append(report.Throughput, *weekly[key])
+	}
+
+	report.CumulativeFlow = CumulativeFlow(timelines, columns, since, until)
+
+	return report
+}
+
+// CumulativeFlow returns the number of tasks per column at the end of each
+// day from since to until (inclusive).
+func CumulativeFlow(timelines []Timeline, columns []string, since, until time.Time) []CFDPoint {
+	var points []CFDPoint
+
+	day := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, since.Location())
+	for !day.After(until) {
+		endOfDay := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		if endOfDay.After(until) {
+			endOfDay = until
+		}
+
+		counts := make(map[string]int, len(columns))
+		for _, column := range columns {
+			counts[column] = 0
+		}
+		for _, tl := range timelines {
+			if column := tl.ColumnAt(endOfDay); column != "" {
+				counts[column]++
+			}
+		}
+
+		points = append(points, CFDPoint{
+			Date:    day.Format("2006-01-02"),
+			Columns: counts,
+		})
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return points
+}
+
+// startOfWeek returns midnight on the Monday of t's week.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Monday = 0
+	day := t.AddDate(0, 0, -offset)
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,242 @@
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC) // Monday
+
+func move(at time.Time, from, to, actor, detail string) Transition {
+	return Transition{At: at, From: from, To: to, Actor: actor, ActorDetail: detail}
+}
+
+// sampleTimeline: created in todo, in_progress after 2h, blocked 3h,
+// back in progress, done 10h after creation.
+func sampleTimeline() Timeline {
+	return NewTimeline(TaskInput{
+		ID:        "task1",
+		DisplayID: "WRK-1",
+		Column:    "done",
+		CreatedBy: "user",
+		Created:   base,
+		Transitions: []Transition{
+			move(base.Add(2*time.Hour), "todo", "in_progress", "cli", ""),
+			move(base.Add(4*time.Hour), "in_progress", "need_input", "cli", "claude"),
+			move(base.Add(7*time.Hour), "need_input", "in_progress", "cli", ""),
+			move(base.Add(10*time.Hour), "in_progress", "done", "agent", ""),
+		},
+	})
+}
+
+func TestNewTimeline(t *testing.T) {
+	tl := sampleTimeline()
+
+	assert.Equal(t, "todo", tl.InitialColumn)
+	assert.Len(t, tl.Transitions, 4)
+}
+
+func TestNewTimeline_SortsAndSkipsNoOps(t *testing.T) {
+	tl := NewTimeline(TaskInput{
+		Column:  "review",
+		Created: base,
+		Transitions: []Transition{
+			move(base.Add(3*time.Hour), "in_progress", "review", "cli", ""),
+			move(base.Add(2*time.Hour), "in_progress", "in_progress", "cli", ""),
+			move(base.Add(time.Hour), "todo", "in_progress", "cli", ""),
+		},
+	})
+
+	require.Len(t, tl.Transitions, 2)
+	assert.Equal(t, "in_progress", tl.Transitions[0].To)
+	assert.Equal(t, "review", tl.Transitions[1].To)
+	assert.Equal(t, "todo", tl.InitialColumn)
+}
+
+func TestNewTimeline_NoTransitions(t *testing.T) {
+	tl := NewTimeline(TaskInput{Column: "backlog", Created: base})
+
+	assert.Equal(t, "backlog", tl.InitialColumn)
+	assert.Equal(t, "backlog", tl.ColumnAt(base.Add(time.Hour)))
+	assert.Equal(t, "", tl.ColumnAt(base.Add(-time.Hour)))
+}
+
+func TestTimeline_LeadAndCycleTime(t *testing.T) {
+	tl := sampleTimeline()
+
+	lead, ok := tl.LeadTime()
+	require.True(t, ok)
+	assert.Equal(t, 10*time.Hour, lead)
+
+	cycle, ok := tl.CycleTime()
+	require.True(t, ok)
+	assert.Equal(t, 8*time.Hour, cycle)
+}
+
+func TestTimeline_NotDone(t *testing.T) {
+	tl := NewTimeline(TaskInput{
+		Column:      "in_progress",
+		Created:     base,
+		Transitions: []Transition{move(base.Add(time.Hour), "todo", "in_progress", "cli", "")},
+	})
+
+	_, ok := tl.LeadTime()
+	assert.False(t, ok)
+	_, ok = tl.CycleTime()
+	assert.False(t, ok)
+}
+
+func TestTimeline_TimeInColumns(t *testing.T) {
+	tl := sampleTimeline()
+	done, _ := tl.DoneAt()
+
+	inColumns := tl.TimeInColumns(done)
+	assert.Equal(t, 2*time.Hour, inColumns["todo"])
+	assert.Equal(t, 5*time.Hour, inColumns["in_progress"])
+	assert.Equal(t, 3*time.Hour, inColumns["need_input"])
+	assert.NotContains(t, inColumns, "done")
+}
+
+func TestTimeline_TimeInColumns_MissingChange(t *testing.T) {
+	// The move from need_input back to in_progress is missing from the log
+	tl := NewTimeline(TaskInput{
+		Column:  "done",
+		Created: base,
+		Transitions: []Transition{
+			move(base.Add(time.Hour), "todo", "need_input", "cli", ""),
+			move(base.Add(2*time.Hour), "in_progress", "done", "cli", ""),
+			move(base.Add(3*time.Hour), "done", "review", "cli", ""),
+			move(base.Add(5*time.Hour), "review", "done", "cli", ""),
+		},
+	})
+	done, _ := tl.DoneAt()
+
+	inColumns := tl.TimeInColumns(done)
+	assert.Equal(t, time.Hour, inColumns["todo"])
+	assert.NotContains(t, inColumns, "need_input", "the task may have left need_input any time")
+	assert.NotContains(t, inColumns, "in_progress")
+	assert.Equal(t, 2*time.Hour, inColumns["review"])
+}
+
+func TestTimeline_ColumnAt(t *testing.T) {
+	tl := sampleTimeline()
+
+	assert.Equal(t, "todo", tl.ColumnAt(base.Add(time.Hour)))
+	assert.Equal(t, "need_input", tl.ColumnAt(base.Add(5*time.Hour)))
+	assert.Equal(t, "done", tl.ColumnAt(base.Add(24*time.Hour)))
+}
+
+func TestClassifyActor(t *testing.T) {
+	assert.Equal(t, ActorAgent, ClassifyActor("agent", ""))
+	assert.Equal(t, ActorAgent, ClassifyActor("cli", "claude"))
+	assert.Equal(t, ActorHuman, ClassifyActor("cli", ""))
+	assert.Equal(t, ActorHuman, ClassifyActor("user", "user"))
+	assert.Equal(t, ActorHuman, ClassifyActor("tui", ""))
+	assert.Equal(t, ActorHuman, ClassifyActor("system", "auto-resume"))
+}
+
+func TestTimeline_ActorKind(t *testing.T) {
+	// Completed by an agent
+	assert.Equal(t, ActorAgent, sampleTimeline().ActorKind())
+
+	// Not done: attributed to creator
+	tl := NewTimeline(TaskInput{Column: "todo", CreatedBy: "agent", Created: base})
+	assert.Equal(t, ActorAgent, tl.ActorKind())
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5}
+
+	assert.Equal(t, 3.0, Percentile(values, 50))
+	assert.Equal(t, 1.0, Percentile(values, 0))
+	assert.Equal(t, 5.0, Percentile(values, 100))
+	assert.InDelta(t, 4.4, Percentile(values, 85), 0.001)
+	assert.Equal(t, 0.0, Percentile(nil, 50))
+	assert.Equal(t, 7.0, Percentile([]float64{7}, 95))
+}
+
+func TestNewStats(t *testing.T) {
+	stats := NewStats([]time.Duration{time.Hour, 3 * time.Hour, 2 * time.Hour})
+
+	assert.Equal(t, 3, stats.Count)
+	assert.Equal(t, 2.0, stats.Mean)
+	assert.Equal(t, 2.0, stats.P50)
+	assert.Equal(t, 3.0, stats.Max)
+
+	assert.Equal(t, Stats{}, NewStats(nil))
+}
+
+func TestBuildReport(t *testing.T) {
+	columns := []string{"backlog", "todo", "in_progress", "need_input", "review", "done"}
+	open := NewTimeline(TaskInput{
+		ID:          "task2",
+		Column:      "in_progress",
+		Created:     base.Add(24 * time.Hour),
+		Transitions: []Transition{move(base.Add(26*time.Hour), "todo", "in_progress", "tui", "")},
+	})
+	// Completed before the window: excluded from metrics
+	old := NewTimeline(TaskInput{
+		ID:          "task3",
+		Column:      "done",
+		Created:     base.Add(-30 * 24 * time.Hour),
+		Transitions: []Transition{move(base.Add(-29*24*time.Hour), "todo", "done", "cli", "")},
+	})
+
+	since := base.Add(-time.Hour)
+	until := base.Add(3 * 24 * time.Hour)
+	report := BuildReport([]Timeline{sampleTimeline(), open, old}, columns, since, until)
+
+	assert.Equal(t, 1, report.Overall.Completed)
+	assert.Equal(t, 10.0, report.Overall.LeadTime.P50)
+	assert.Equal(t, 8.0, report.Overall.CycleTime.P50)
+	assert.Equal(t, 3.0, report.Overall.Blocked.P50)
+	assert.Equal(t, 1, report.Overall.Blocked.Count)
+	assert.Equal(t, 1, report.ByActor[ActorAgent].Completed)
+	assert.Equal(t, 0, report.ByActor[ActorHuman].Completed)
+	assert.Equal(t, 5.0, report.TimeInColumns["in_progress"].P50)
+
+	require.Len(t, report.Tasks, 1)
+	assert.Equal(t, "WRK-1", report.Tasks[0].DisplayID)
+	require.NotNil(t, report.Tasks[0].CycleHours)
+
+	require.Len(t, report.Throughput, 1)
+	assert.Equal(t, "2025-03-03", report.Throughput[0].WeekStart)
+	assert.Equal(t, 1, report.Throughput[0].ByActor[ActorAgent])
+
+	// One point per day: Mar 3..6
+	require.Len(t, report.CumulativeFlow, 4)
+	first := report.CumulativeFlow[0]
+	assert.Equal(t, "2025-03-03", first.Date)
+	assert.Equal(t, 2, first.Columns["done"])
+	assert.Equal(t, 0, first.Columns["in_progress"])
+	second := report.CumulativeFlow[1]
+	assert.Equal(t, 1, second.Columns["in_progress"])
+	assert.Equal(t, 2, second.Columns["done"])
+}
+
+func TestBuildReport_BlockedOnlyCountsBlockedTasks(t *testing.T) {
+	unblocked := NewTimeline(TaskInput{
+		ID:      "task2",
+		Column:  "done",
+		Created: base,
+		Transitions: []Transition{
+			move(base.Add(time.Hour), "todo", "in_progress", "cli", ""),
+			move(base.Add(2*time.Hour), "in_progress", "done", "cli", ""),
+		},
+	})
+
+	report := BuildReport([]Timeline{sampleTimeline(), unblocked}, nil, base, base.Add(24*time.Hour))
+	assert.Equal(t, 2, report.Overall.Completed)
+	assert.Equal(t, 1, report.Overall.Blocked.Count)
+	assert.Equal(t, 3.0, report.Overall.Blocked.Mean, "tasks never in need_input do not dilute it")
+	assert.Equal(t, 0, report.ByActor[ActorHuman].Blocked.Count)
+}
+
+func TestStartOfWeek(t *testing.T) {
+	sunday := time.Date(2025, 3, 9, 18, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2025-03-03", startOfWeek(sunday).Format("2006-01-02"))
+	assert.Equal(t, "2025-03-03", startOfWeek(base).Format("2006-01-02"))
+}
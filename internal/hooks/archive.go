@@ -0,0 +1,36 @@
+package hooks
+
+import (
+	"github.com/pocketbase/pocketbase"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+)
+
+// RegisterArchiveSchedule runs the boards' auto-archive policies hourly
+// while the server is running. Each run is one undo batch of the
+// auto-archive actor.
+func RegisterArchiveSchedule(app *pocketbase.PocketBase) {
+	app.Cron().MustAdd("egenskrivenAutoArchive", archive.Schedule, func() {
+		// Databases without the archive fields have nothing to archive
+		if c, err := app.FindCachedCollectionByNameOrId("boards"); err != nil ||
+			c.Fields.GetByName("auto_archive_days") == nil {
+			return
+		}
+
+		results, err := archive.Run(app, archive.RunOptions{
+			Source: "server",
+			Batch:  undo.NewBatch("auto-archive", archive.AutoActor),
+		})
+		if err != nil {
+			app.Logger().Error("auto-archive failed", "error", err)
+		}
+		for _, r := range results {
+			app.Logger().Info("auto-archived done tasks",
+				"board", r.Board.GetString("prefix"),
+				"days", r.Days,
+				"tasks", len(r.Tasks),
+			)
+		}
+	})
+}
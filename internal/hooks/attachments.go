@@ -0,0 +1,30 @@
+package hooks
+
+import (
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+)
+
+// RegisterAttachmentHooks rejects saves of tasks and comments whose new
+// files exceed the size limits of the attachments section of the project
+// config. Uploads through the API and the CLI are checked alike; API
+// clients get a 400 error naming the limit.
+func RegisterAttachmentHooks(app *pocketbase.PocketBase) {
+	check := func(e *core.RecordEvent) error {
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = &config.MergedConfig{}
+		}
+		if err := attachment.CheckLimits(e.App, e.Record,
+			cfg.Attachments.MaxFileSize(), cfg.Attachments.MaxTotalSize()); err != nil {
+			return router.NewBadRequestError(err.Error(), err)
+		}
+		return e.Next()
+	}
+	app.OnRecordCreate("tasks", "comments").BindFunc(check)
+	app.OnRecordUpdate("tasks", "comments").BindFunc(check)
+}
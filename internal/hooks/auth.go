@@ -0,0 +1,20 @@
+package hooks
+
+import (
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/auth"
+)
+
+// RegisterAuthHooks keeps API rules in sync with the auth mode.
+// When auth is enabled, collections added by newer migrations start out
+// public, so the rules are re-applied every time the server starts.
+func RegisterAuthHooks(app *pocketbase.PocketBase) {
+	app.OnServe().BindFunc(func(e *core.ServeEvent) error {
+		if err := auth.SyncRules(e.App); err != nil {
+			app.Logger().Error("failed to apply auth rules", "error", err)
+		}
+		return e.Next()
+	})
+}
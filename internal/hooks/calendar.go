@@ -0,0 +1,98 @@
+package hooks
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/auth"
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/calendar"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+)
+
+// RegisterCalendarRoutes publishes the due dates of each board as an
+// iCalendar feed at /api/egenskriven/calendar/<board>.ics, where <board>
+// is a board name or prefix. ?entry=todo publishes to-dos instead of
+// all-day events.
+//
+// Calendar clients cannot send headers, so feeds take their token as
+// ?token=. With a calendar token configured (server.calendar_token), that
+// token opens every feed. With auth enabled, an API token allowed on the
+// board does too, passed as ?token= or in the Authorization header.
+// Without either, feeds are public like the rest of the API.
+//
+// The token is checked before the board is looked up, and a board that
+// does not exist answers like one the token may not read, so feeds do not
+// reveal which boards exist.
+func RegisterCalendarRoutes(app *pocketbase.PocketBase) {
+	app.OnServe().BindFunc(func(e *core.ServeEvent) error {
+		e.Router.GET("/api/egenskriven/calendar/{board}", func(re *core.RequestEvent) error {
+			restriction, ok := calendarCaller(re)
+			if !ok {
+				return router.NewUnauthorizedError("a valid calendar or API token is required", nil)
+			}
+
+			ref := strings.TrimSuffix(re.Request.PathValue("board"), ".ics")
+			boardRecord, err := board.GetByNameOrPrefix(app, ref)
+			if err != nil || (restriction != "" && restriction != boardRecord.Id) {
+				return router.NewNotFoundError("board not found", nil)
+			}
+
+			kind := re.Request.URL.Query().Get("entry")
+			if kind != "" {
+				if err := calendar.ValidateKind(kind); err != nil {
+					return router.NewBadRequestError(err.Error(), nil)
+				}
+			}
+
+			cal, err := calendar.Build(re.App, calendar.Options{Board: boardRecord, Kind: kind})
+			if err != nil {
+				return err
+			}
+
+			re.Response.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+			re.Response.Header().Set("Content-Disposition", `inline; filename="`+boardRecord.GetString("prefix")+`.ics"`)
+			re.Response.WriteHeader(http.StatusOK)
+			return calendar.Write(re.Response, cal)
+		})
+		return e.Next()
+	})
+}
+
+// calendarCaller reports whether a request may read calendars, and the
+// board its API token is restricted to ("" for all boards).
+func calendarCaller(re *core.RequestEvent) (string, bool) {
+	secret := config.CalendarToken()
+	enabled := auth.Enabled(re.App)
+	if secret == "" && !enabled {
+		return "", true
+	}
+
+	token := re.Request.URL.Query().Get("token")
+	if secret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1 {
+		return "", true
+	}
+	if !enabled {
+		return "", false
+	}
+
+	record := re.Auth
+	if token != "" {
+		record, _ = re.App.FindAuthRecordByToken(token, core.TokenTypeAuth)
+	}
+	if record == nil {
+		return "", false
+	}
+	if record.IsSuperuser() {
+		return "", true
+	}
+	if record.Collection().Name != auth.UsersCollection {
+		return "", false
+	}
+	return record.GetString("board"), true
+}
@@ -0,0 +1,40 @@
+package hooks
+
+import (
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+)
+
+// RegisterChecklistHooks keeps a task's checklist field and the markdown
+// checkboxes of its description in sync on every save, so checking a box
+// in an editor ticks the item and the other way around. Must be registered
+// before the task event and undo hooks, which record the synced fields.
+func RegisterChecklistHooks(app *pocketbase.PocketBase) {
+	sync := func(e *core.RecordEvent) error {
+		checklist.Sync(e.Record, checklistActor(e.Record), time.Now())
+		return e.Next()
+	}
+	app.OnRecordCreate("tasks").BindFunc(sync)
+	app.OnRecordUpdate("tasks").BindFunc(sync)
+}
+
+// checklistActor names who checked boxes in an edited description: the
+// actor of the last event queued for the save, the API caller, or the
+// local user.
+func checklistActor(task *core.Record) string {
+	if events := taskevent.Pending(task); len(events) > 0 {
+		if detail := events[len(events)-1].ActorDetail; detail != "" {
+			return detail
+		}
+	}
+	if _, detail := taskevent.ActorOf(task); detail != "" {
+		return detail
+	}
+	return config.CurrentUser()
+}
@@ -0,0 +1,244 @@
+package hooks
+
+import (
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/auth"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+)
+
+// RegisterPolicyHooks enforces agent modes on API requests.
+// The caller is identified by the API token (token accounts issued to an
+// agent) or, for records created without one, by the agent recorded on
+// the record itself. Direct database writes are checked by the CLI.
+func RegisterPolicyHooks(app *pocketbase.PocketBase) {
+	app.OnRecordCreateRequest("tasks").BindFunc(func(e *core.RecordRequestEvent) error {
+		actor := requestActor(e, e.Record.GetString("created_by"), e.Record.GetString("created_by_agent"))
+
+		action := policy.ActionCreate
+		if e.Record.GetString("column") == policy.DoneColumn {
+			action = policy.ActionComplete
+		}
+
+		d := policy.CheckTask(e.App, actor, e.Record, action)
+		if d.Downgraded() {
+			e.Record.Set("column", d.DowngradeTo)
+			return e.Next()
+		}
+		if err := d.Err(); err != nil {
+			return policyError(err)
+		}
+		return e.Next()
+	})
+
+	app.OnRecordUpdateRequest("tasks").BindFunc(func(e *core.RecordRequestEvent) error {
+		actor := requestActor(e, "", "")
+		action := policy.MoveAction(e.Record.Original().GetString("column"), e.Record.GetString("column"))
+
+		d := policy.CheckTask(e.App, actor, e.Record, action)
+		if d.Downgraded() {
+			e.Record.Set("column", d.DowngradeTo)
+			return e.Next()
+		}
+		if err := d.Err(); err != nil {
+			return policyError(err)
+		}
+		return e.Next()
+	})
+
+	// Setting or clearing the trash fields moves a record in or out of the
+	// trash, which is checked as a delete whatever else the request changes
+	app.OnRecordUpdateRequest(trash.Collections...).BindFunc(func(e *core.RecordRequestEvent) error {
+		if !trashChanged(e.Record) {
+			return e.Next()
+		}
+		boardID, err := auth.BoardID(e.App, e.Record)
+		if err != nil {
+			return e.Next()
+		}
+		if err := policy.Check(e.App, requestActor(e, "", ""), boardID, policy.ActionDelete).Err(); err != nil {
+			return policyError(err)
+		}
+		return e.Next()
+	})
+
+	app.OnRecordDeleteRequest("tasks").BindFunc(func(e *core.RecordRequestEvent) error {
+		actor := requestActor(e, "", "")
+		if err := policy.CheckTask(e.App, actor, e.Record, policy.ActionDelete).Err(); err != nil {
+			return policyError(err)
+		}
+		return e.Next()
+	})
+
+	app.OnRecordCreateRequest("comments").BindFunc(func(e *core.RecordRequestEvent) error {
+		actor := requestActor(e, e.Record.GetString("author_type"), e.Record.GetString("author_id"))
+		task, err := e.App.FindRecordById("tasks", e.Record.GetString("task"))
+		if err != nil {
+			return e.Next() // Let record validation report the missing task
+		}
+		if err := policy.CheckTask(e.App, actor, task, policy.ActionComment).Err(); err != nil {
+			return policyError(err)
+		}
+		return e.Next()
+	})
+
+	// Agents cannot create, change, or delete boards their mode forbids,
+	// and never change a board's agent mode
+	app.OnRecordCreateRequest("boards").BindFunc(func(e *core.RecordRequestEvent) error {
+		actor := requestActor(e, "", "")
+		action := policy.ActionCreate
+		if e.Record.GetString("agent_mode") != "" {
+			action = policy.ActionSetMode
+		}
+		if err := policy.Check(e.App, actor, "", action).Err(); err != nil {
+			return policyError(err)
+		}
+		return e.Next()
+	})
+
+	app.OnRecordUpdateRequest("boards").BindFunc(func(e *core.RecordRequestEvent) error {
+		actor := requestActor(e, "", "")
+		action := policy.ActionUpdate
+		if e.Record.GetString("agent_mode") != e.Record.Original().GetString("agent_mode") {
+			action = policy.ActionSetMode
+		}
+		if err := policy.Check(e.App, actor, e.Record.Id, action).Err(); err != nil {
+			return policyError(err)
+		}
+		return e.Next()
+	})
+
+	app.OnRecordDeleteRequest("boards").BindFunc(func(e *core.RecordRequestEvent) error {
+		actor := requestActor(e, "", "")
+		if err := policy.Check(e.App, actor, e.Record.Id, policy.ActionDelete).Err(); err != nil {
+			return policyError(err)
+		}
+		return e.Next()
+	})
+
+	// Proposals are how agents suggest changes, so anyone may create one,
+	// but only humans may approve, reject, or discard them
+	app.OnRecordUpdateRequest("proposals").BindFunc(checkRecord(policy.ActionReview))
+	app.OnRecordDeleteRequest("proposals").BindFunc(checkRecord(policy.ActionReview))
+
+	// Every other collection that belongs to a board. Records that belong
+	// to a task (comments, sessions, time entries, links) change that task,
+	// as they do in the CLI; the rest are created, changed, and deleted on
+	// their board.
+	for _, name := range auth.Collections() {
+		if !customCreate[name] {
+			action := policy.ActionCreate
+			if taskRecords[name] {
+				action = policy.ActionUpdate
+			}
+			app.OnRecordCreateRequest(name).BindFunc(checkRecord(action))
+		}
+		if !customUpdate[name] {
+			app.OnRecordUpdateRequest(name).BindFunc(checkRecord(policy.ActionUpdate))
+		}
+		if !customDelete[name] {
+			action := policy.ActionDelete
+			if taskRecords[name] {
+				action = policy.ActionUpdate
+			}
+			app.OnRecordDeleteRequest(name).BindFunc(checkRecord(action))
+		}
+	}
+}
+
+// Collections with a dedicated policy hook for an action.
+var (
+	customCreate = map[string]bool{"tasks": true, "boards": true, "comments": true, "proposals": true}
+	customUpdate = map[string]bool{"tasks": true, "boards": true, "proposals": true}
+	customDelete = map[string]bool{"tasks": true, "boards": true, "proposals": true}
+)
+
+// taskRecords are the collections whose records belong to a task.
+var taskRecords = map[string]bool{
+	"comments":     true,
+	"sessions":     true,
+	"time_entries": true,
+	"task_links":   true,
+}
+
+// trashChanged reports whether an update changes the trash fields of a
+// record.
+func trashChanged(record *core.Record) bool {
+	for _, field := range []string{"deleted_at", "deleted_by", "deleted_with"} {
+		if record.Collection().Fields.GetByName(field) == nil {
+			continue
+		}
+		if record.GetString(field) != record.Original().GetString(field) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRecord returns a request hook that checks action on the board the
+// record belongs to.
+func checkRecord(action policy.Action) func(e *core.RecordRequestEvent) error {
+	return func(e *core.RecordRequestEvent) error {
+		boardID, err := auth.BoardID(e.App, e.Record)
+		if err != nil {
+			return e.Next() // Let record validation report the missing task
+		}
+		if err := policy.Check(e.App, requestActor(e, "", ""), boardID, action).Err(); err != nil {
+			return policyError(err)
+		}
+		return e.Next()
+	}
+}
+
+// requestActor identifies the caller of an API request. Token accounts
+// issued to an agent act as that agent. Otherwise a record that declares
+// an agent author (createdBy "agent" with a name) is attributed to it.
+func requestActor(e *core.RecordRequestEvent, createdBy, agentName string) policy.Actor {
+	if e.HasSuperuserAuth() {
+		return policy.User("")
+	}
+	actor := policy.ActorFromAuth(e.Auth)
+	if !actor.IsAgent() && createdBy == policy.ActorAgent && agentName != "" {
+		return policy.Agent(agentName)
+	}
+	return actor
+}
+
+// attributedActor names the caller of an API request in the records it
+// changes. An authenticated caller is named by its account; the client's
+// X-Egenskriven-Actor header only names callers without one (auth
+// disabled), since any client can set it.
+func attributedActor(e *core.RecordRequestEvent) policy.Actor {
+	actor := requestActor(e, "", "")
+	if e.Auth == nil && !actor.IsAgent() {
+		if name := e.Request.Header.Get(undo.HeaderActor); name != "" {
+			return policy.User(name)
+		}
+	}
+	return actor
+}
+
+// policyError converts a policy violation into a 403 API error whose data
+// carries the violation code, e.g. {"policy": {"code": "policy_read_only"}}.
+func policyError(err error) error {
+	return router.NewForbiddenError(err.Error(), map[string]error{
+		"policy": safePolicyError{err},
+	})
+}
+
+// safePolicyError exposes the violation code to API clients.
+type safePolicyError struct {
+	error
+}
+
+// Code implements router.SafeErrorItem.
+func (e safePolicyError) Code() string {
+	if v, ok := e.error.(*policy.Violation); ok {
+		return v.Code
+	}
+	return "policy_denied"
+}
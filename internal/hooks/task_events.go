@@ -0,0 +1,45 @@
+package hooks
+
+import (
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+)
+
+// RegisterTaskEventHooks registers hooks that write a task_events record for
+// every task create, update, and delete, whichever path made the change.
+// API requests are attributed to the authenticated caller.
+func RegisterTaskEventHooks(app *pocketbase.PocketBase) {
+	taskevent.Bind(app)
+
+	// Queue the events an API client sent along with the task, and
+	// attribute the change to the caller. Events sent with an API token
+	// are always attributed to the token's account.
+	attributeRequest := func(e *core.RecordRequestEvent) error {
+		actor := requestActor(e, "", "")
+		source := "api"
+		if actor.IsAgent() {
+			source = actor.Type
+		}
+
+		if info, err := e.RequestInfo(); err == nil {
+			if raw, ok := info.Body[taskevent.PendingKey]; ok {
+				e.Record.Set(taskevent.PendingKey, raw)
+				if e.Auth != nil && !e.HasSuperuserAuth() {
+					events := taskevent.Pending(e.Record)
+					for i := range events {
+						events[i].Actor, events[i].ActorDetail = source, actor.Name
+					}
+					e.Record.Set(taskevent.PendingKey, events)
+				}
+			}
+		}
+
+		taskevent.SetActor(e.Record, source, actor.Name)
+		return e.Next()
+	}
+	app.OnRecordCreateRequest("tasks").BindFunc(attributeRequest)
+	app.OnRecordUpdateRequest("tasks").BindFunc(attributeRequest)
+	app.OnRecordDeleteRequest("tasks").BindFunc(attributeRequest)
+}
@@ -0,0 +1,53 @@
+package hooks
+
+import (
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/timetrack"
+)
+
+// RegisterTimeTrackingHooks registers hooks that start and pause automatic
+// task timers when tasks move between columns.
+// Behavior is controlled by the time_tracking section of the project config.
+func RegisterTimeTrackingHooks(app *pocketbase.PocketBase) {
+	// Task created directly in a tracked column
+	app.OnRecordAfterCreateSuccess("tasks").BindFunc(func(e *core.RecordEvent) error {
+		handleTimerColumnChange(e.App, e.Record, "", e.Record.GetString("column"))
+		return e.Next()
+	})
+
+	// Task moved between columns
+	app.OnRecordUpdate("tasks").BindFunc(func(e *core.RecordEvent) error {
+		// Capture the column before the update is persisted
+		fromColumn := e.Record.Original().GetString("column")
+
+		if err := e.Next(); err != nil {
+			return err
+		}
+
+		handleTimerColumnChange(e.App, e.Record, fromColumn, e.Record.GetString("column"))
+		return nil
+	})
+}
+
+// handleTimerColumnChange applies the configured timer behavior.
+// Errors are logged but never fail the task save.
+func handleTimerColumnChange(app core.App, task *core.Record, fromColumn, toColumn string) {
+	if fromColumn == toColumn {
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.MergedConfig{}
+	}
+
+	if err := timetrack.HandleColumnChange(app, task, fromColumn, toColumn, cfg.TimeTracking); err != nil {
+		app.Logger().Error("time tracking update failed",
+			"task", task.Id,
+			"error", err,
+		)
+	}
+}
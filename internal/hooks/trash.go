@@ -0,0 +1,47 @@
+package hooks
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// RegisterTrashHooks turns API deletes of tasks, epics, and boards into
+// soft deletes, so the web UI and CLI commands sent through the server move
+// records to the trash. Must be registered after the policy hooks, which
+// check the delete first.
+func RegisterTrashHooks(app *pocketbase.PocketBase) {
+	app.OnRecordDeleteRequest(trash.Collections...).BindFunc(func(e *core.RecordRequestEvent) error {
+		// Databases without the trash fields delete as before
+		if e.Record.Collection().Fields.GetByName("deleted_at") == nil {
+			return e.Next()
+		}
+
+		actor := attributedActor(e)
+		source, name := "api", actor.Name
+		if actor.IsAgent() {
+			source = actor.Type
+		}
+		if name == "" {
+			name = config.CurrentUser()
+		}
+
+		var err error
+		switch e.Record.Collection().Name {
+		case "tasks":
+			err = trash.Task(e.App, e.Record, source, name)
+		case "epics":
+			err = trash.Epic(e.App, e.Record, name)
+		default:
+			_, err = trash.Board(e.App, e.Record, source, name)
+		}
+		if err != nil {
+			return e.BadRequestError("Failed to move the record to the trash.", err)
+		}
+		return e.NoContent(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,85 @@
+package hooks
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+)
+
+// RegisterUndoHooks registers hooks that record every task create, update,
+// and delete as a reversible operation for `egenskriven undo`. Operations
+// are grouped into the batch of the CLI command or TUI action that made
+// them; API requests carry the batch in X-Egenskriven-* headers.
+func RegisterUndoHooks(app *pocketbase.PocketBase) {
+	app.OnRecordCreate("tasks").BindFunc(func(e *core.RecordEvent) error {
+		if err := e.Next(); err != nil {
+			return err
+		}
+		recordOperation(e.App, e.Record, undo.KindCreate, nil, undo.Snapshot(e.Record))
+		return nil
+	})
+
+	app.OnRecordUpdate("tasks").BindFunc(func(e *core.RecordEvent) error {
+		before := undo.Snapshot(e.Record.Original())
+
+		if err := e.Next(); err != nil {
+			return err
+		}
+		after := undo.Snapshot(e.Record)
+		if undo.Changed(before, after) {
+			recordOperation(e.App, e.Record, undo.KindUpdate, before, after)
+		}
+		return nil
+	})
+
+	app.OnRecordDelete("tasks").BindFunc(func(e *core.RecordEvent) error {
+		before := undo.Snapshot(e.Record)
+
+		if err := e.Next(); err != nil {
+			return err
+		}
+		recordOperation(e.App, e.Record, undo.KindDelete, before, nil)
+		return nil
+	})
+
+	// Group API changes into the batch of the calling command
+	tagRequest := func(e *core.RecordRequestEvent) error {
+		b := undo.Batch{
+			ID:    e.Request.Header.Get(undo.HeaderBatch),
+			Label: e.Request.Header.Get(undo.HeaderLabel),
+			Actor: attributedActor(e).Name,
+		}
+		if b.ID != "" || b.Actor != "" {
+			undo.Tag(e.Record, b)
+		}
+		return e.Next()
+	}
+	app.OnRecordCreateRequest("tasks").BindFunc(tagRequest)
+	app.OnRecordUpdateRequest("tasks").BindFunc(tagRequest)
+	app.OnRecordDeleteRequest("tasks").BindFunc(tagRequest)
+}
+
+// recordOperation stores an operation. Errors are logged but never fail
+// the task change; databases without the operations collection are skipped.
+func recordOperation(app core.App, task *core.Record, kind string, before, after map[string]any) {
+	if _, err := app.FindCachedCollectionByNameOrId(undo.CollectionName); err != nil {
+		return
+	}
+
+	b, ok := undo.BatchFor(task, fmt.Sprintf("%s %q", kind, task.GetString("title")), config.CurrentUser())
+	if !ok {
+		return
+	}
+
+	if err := undo.Record(app, b, kind, task.Id, task.GetString("board"), before, after); err != nil {
+		app.Logger().Error("undo operation write failed",
+			"task", task.Id,
+			"kind", kind,
+			"error", err,
+		)
+	}
+}
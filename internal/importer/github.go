@@ -0,0 +1,143 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// githubIssue is an issue as listed by
+// `gh issue list --json number,title,body,state,labels,milestone,assignees,comments,createdAt`
+// or by the REST API.
+type githubIssue struct {
+	Number         int              `json:"number"`
+	Title          string           `json:"title"`
+	Body           string           `json:"body"`
+	State          string           `json:"state"`
+	Labels         []githubName     `json:"labels"`
+	Milestone      *githubMilestone `json:"milestone"`
+	Assignees      []githubUser     `json:"assignees"`
+	Comments       json.RawMessage  `json:"comments"` // a list from gh, a count from the REST API
+	CreatedAt      string           `json:"createdAt"`
+	CreatedAtREST  string           `json:"created_at"`
+	URL            string           `json:"url"`
+	PullRequest    json.RawMessage  `json:"pull_request"`
+	ParentIssueURL string           `json:"parent_issue_url"`
+}
+
+type githubMilestone struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubComment struct {
+	Author        githubUser `json:"author"` // gh
+	User          githubUser `json:"user"`   // REST API
+	Body          string     `json:"body"`
+	CreatedAt     string     `json:"createdAt"`
+	CreatedAtREST string     `json:"created_at"`
+}
+
+// githubName is a label, listed as an object by gh and the REST API and
+// as a string by some tools.
+type githubName string
+
+func (n *githubName) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*n = githubName(s)
+		return nil
+	}
+	var label struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &label); err != nil {
+		return err
+	}
+	*n = githubName(label.Name)
+	return nil
+}
+
+// parseGitHub reads a JSON list of GitHub issues. Milestones become epics,
+// sub-issues (parent_issue_url) sub-tasks, and open and closed issues go to
+// the todo and done columns unless mapped otherwise. Pull requests are
+// skipped.
+func parseGitHub(content []byte) (*source, error) {
+	var issues []githubIssue
+	if err := json.Unmarshal(content, &issues); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub issues (expected a JSON list, as written by 'gh issue list --json'): %w", err)
+	}
+
+	src := &source{}
+	epics := make(map[string]Epic)
+	for _, issue := range issues {
+		if len(issue.PullRequest) > 0 && string(issue.PullRequest) != "null" {
+			continue
+		}
+		if src.board == "" {
+			src.board = githubRepository(issue.URL)
+		}
+
+		t := Task{
+			Key:         "#" + strconv.Itoa(issue.Number),
+			Title:       issue.Title,
+			Description: issue.Body,
+			Column:      strings.ToLower(issue.State),
+			Created:     timestamp(firstNonEmpty(issue.CreatedAt, issue.CreatedAtREST)),
+		}
+		for _, label := range issue.Labels {
+			t.Labels = append(t.Labels, string(label))
+		}
+		for _, a := range issue.Assignees {
+			t.Assignees = append(t.Assignees, a.Login)
+		}
+		if m := issue.Milestone; m != nil && m.Title != "" {
+			t.Epic = m.Title
+			epics[m.Title] = Epic{Key: m.Title, Title: m.Title, Description: m.Description}
+		}
+		if issue.ParentIssueURL != "" {
+			t.Parent = "#" + issue.ParentIssueURL[strings.LastIndex(issue.ParentIssueURL, "/")+1:]
+		}
+
+		var comments []githubComment
+		if json.Unmarshal(issue.Comments, &comments) == nil {
+			for _, c := range comments {
+				t.Comments = append(t.Comments, Comment{
+					Author:  firstNonEmpty(c.Author.Login, c.User.Login),
+					Content: c.Body,
+					Created: timestamp(firstNonEmpty(c.CreatedAt, c.CreatedAtREST)),
+				})
+			}
+		}
+		src.tasks = append(src.tasks, t)
+	}
+	src.epics = sortedEpics(epics)
+	return src, nil
+}
+
+// githubRepository returns the repository name of an issue URL, such as
+// "https://github.com/owner/repo/issues/12", or "".
+func githubRepository(url string) string {
+	parts := strings.Split(url, "/")
+	for i := len(parts) - 1; i > 0; i-- {
+		if parts[i] == "issues" {
+			return parts[i-1]
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
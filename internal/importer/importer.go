@@ -0,0 +1,378 @@
+// Package importer converts the exports of other trackers (GitHub Issues,
+// Jira CSV, Trello, and Linear) to the boards, epics, and tasks of
+// EgenSkriven. The files are read offline; nothing is fetched.
+//
+// Each source is parsed to tasks with their original statuses, priorities,
+// types, and labels, which are then mapped onto columns, priorities, and
+// types by built-in tables and a Mapping.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+)
+
+// Sources are the supported export formats.
+var Sources = []string{"github", "jira-csv", "trello", "linear"}
+
+// Data is the content of an export, mapped onto EgenSkriven.
+type Data struct {
+	// Board is the name the source gives the board, such as the repository,
+	// Jira project, Trello board, or Linear team; empty if unknown.
+	Board string
+	Epics []Epic
+	// Tasks are listed with parents before their sub-tasks.
+	Tasks []Task
+	// Warnings report values that could not be mapped, and the defaults
+	// used instead.
+	Warnings []string
+}
+
+// Epic is an epic, milestone, or project of the source.
+type Epic struct {
+	Key         string // unique within the export
+	Title       string
+	Description string
+}
+
+// Task is an issue or card of the source.
+type Task struct {
+	Key         string // ID in the source, such as "#12" or "ENG-4"
+	Title       string
+	Description string
+	Type        string
+	Priority    string
+	Column      string
+	Labels      []string
+	Assignees   []string
+	Epic        string // epic key
+	Parent      string // task key
+	DueDate     string // YYYY-MM-DD
+	Created     string // RFC 3339
+	Archived    string // RFC 3339; set when archived in the source
+	Checklist   []checklist.Item
+	Comments    []Comment
+}
+
+// Comment is a comment on a task.
+type Comment struct {
+	Author  string
+	Content string
+	Created string // RFC 3339
+}
+
+// source is a parsed export before mapping. Tasks carry the source's
+// values: Column holds the status and Priority and Type the source's names.
+type source struct {
+	board string
+	epics []Epic
+	tasks []Task
+}
+
+// Parse reads an export of a source. A nil mapping uses the built-in
+// mappings only.
+func Parse(from string, content []byte, m *Mapping) (*Data, error) {
+	if m == nil {
+		m = &Mapping{}
+	}
+
+	var (
+		src *source
+		err error
+	)
+	switch from {
+	case "github":
+		src, err = parseGitHub(content)
+	case "jira-csv":
+		src, err = parseJiraCSV(content)
+	case "trello":
+		src, err = parseTrello(content)
+	case "linear":
+		src, err = parseLinear(content)
+	default:
+		return nil, fmt.Errorf("unknown source %q: use %s", from, strings.Join(Sources, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return src.apply(m), nil
+}
+
+// apply maps the values of the source's tasks and orders them parents
+// first. Epics and parents missing from the export are dropped with a
+// warning.
+func (src *source) apply(m *Mapping) *Data {
+	data := &Data{Board: src.board, Epics: src.epics}
+	warned := make(map[string]bool)
+	warn := func(format string, args ...any) {
+		msg := fmt.Sprintf(format, args...)
+		if !warned[msg] {
+			warned[msg] = true
+			data.Warnings = append(data.Warnings, msg)
+		}
+	}
+
+	epics := make(map[string]bool, len(src.epics))
+	for _, e := range src.epics {
+		epics[e.Key] = true
+	}
+	keys := make(map[string]bool, len(src.tasks))
+	for _, t := range src.tasks {
+		keys[t.Key] = true
+	}
+
+	for _, t := range src.tasks {
+		status := t.Column
+		if column, ok := lookup(m.Columns, builtinColumns, status); ok {
+			t.Column = column
+		} else {
+			t.Column = m.defaultColumn()
+			if status != "" {
+				warn("status %q is not mapped; using %s", status, t.Column)
+			}
+		}
+
+		priority := t.Priority
+		t.Priority = ""
+		if priority != "" {
+			if p, ok := lookup(m.Priorities, builtinPriorities, priority); ok {
+				t.Priority = p
+			} else {
+				warn("priority %q is not mapped; using %s", priority, m.defaultPriority())
+			}
+		}
+
+		issueType := t.Type
+		t.Type = ""
+		if issueType != "" {
+			if typ, ok := lookup(m.Types, builtinTypes, issueType); ok {
+				t.Type = typ
+			} else {
+				warn("type %q is not mapped; using %s", issueType, m.defaultType())
+			}
+		}
+
+		// Labels such as "P1" or "bug" set the priority or type in place
+		// of being kept
+		var labels []string
+		for _, label := range t.Labels {
+			if p, ok := m.labelPriority(label); ok {
+				if t.Priority == "" {
+					t.Priority = p
+				}
+				continue
+			}
+			if typ, ok := m.labelType(label); ok {
+				if t.Type == "" {
+					t.Type = typ
+				}
+				continue
+			}
+			if name, ok := m.label(label); ok && !containsString(labels, name) {
+				labels = append(labels, name)
+			}
+		}
+		t.Labels = labels
+		if t.Priority == "" {
+			t.Priority = m.defaultPriority()
+		}
+		if t.Type == "" {
+			t.Type = m.defaultType()
+		}
+
+		for i, a := range t.Assignees {
+			t.Assignees[i] = m.user(a)
+		}
+		for i := range t.Comments {
+			t.Comments[i].Author = m.user(t.Comments[i].Author)
+		}
+
+		if t.Epic != "" && !epics[t.Epic] {
+			warn("epic %q of %s is not in the export; leaving it out", t.Epic, t.Key)
+			t.Epic = ""
+		}
+		if t.Parent != "" && !keys[t.Parent] {
+			warn("parent %s of %s is not in the export; leaving it out", t.Parent, t.Key)
+			t.Parent = ""
+		}
+		data.Tasks = append(data.Tasks, t)
+	}
+
+	data.Tasks = parentsFirst(data.Tasks)
+	return data
+}
+
+// parentsFirst orders tasks so each parent comes before its sub-tasks,
+// keeping the order of the export otherwise. A parent cycle is broken by
+// dropping the link that closes it.
+func parentsFirst(tasks []Task) []Task {
+	index := make(map[string]int, len(tasks))
+	for i, t := range tasks {
+		index[t.Key] = i
+	}
+	result := make([]Task, 0, len(tasks))
+	state := make([]int, len(tasks)) // 0 new, 1 visiting, 2 done
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != 0 {
+			return
+		}
+		state[i] = 1
+		if p, ok := index[tasks[i].Parent]; ok && tasks[i].Parent != "" {
+			if state[p] == 1 {
+				tasks[i].Parent = ""
+			} else {
+				visit(p)
+			}
+		}
+		state[i] = 2
+		result = append(result, tasks[i])
+	}
+	for i := range tasks {
+		visit(i)
+	}
+	return result
+}
+
+// dateLayouts are the date formats of the supported exports.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06 15:04",
+	"02/Jan/06",
+	"1/2/2006 15:04",
+	"1/2/2006",
+}
+
+// parseTime parses a date of an export, returning the zero time for an
+// empty or unknown value.
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// timestamp formats a date of an export as RFC 3339 in UTC, or "".
+func timestamp(s string) string {
+	t := parseTime(s)
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// date formats a date of an export as YYYY-MM-DD, or "".
+func date(s string) string {
+	t := parseTime(s)
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// csvTable is a CSV export with a header row. Columns are found by name,
+// case-insensitively; exports such as Jira's repeat a column for each
+// value of a multi-valued field.
+type csvTable struct {
+	columns map[string][]int
+	rows    [][]string
+}
+
+func readCSV(content []byte) (*csvTable, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the CSV file is empty")
+	}
+	table := &csvTable{columns: make(map[string][]int), rows: records[1:]}
+	for i, name := range records[0] {
+		key := strings.ToLower(strings.TrimSpace(name))
+		table.columns[key] = append(table.columns[key], i)
+	}
+	return table, nil
+}
+
+// has reports whether the table has one of the columns.
+func (t *csvTable) has(names ...string) bool {
+	for _, name := range names {
+		if len(t.columns[strings.ToLower(name)]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// get returns the first non-empty value of the first of the columns a row
+// has.
+func (t *csvTable) get(row []string, names ...string) string {
+	for _, name := range names {
+		for _, i := range t.columns[strings.ToLower(name)] {
+			if i < len(row) {
+				if v := strings.TrimSpace(row[i]); v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// all returns the non-empty values of a repeated column.
+func (t *csvTable) all(row []string, name string) []string {
+	var values []string
+	for _, i := range t.columns[strings.ToLower(name)] {
+		if i < len(row) {
+			if v := strings.TrimSpace(row[i]); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// sortedEpics returns epics sorted by title, for a stable import order.
+func sortedEpics(epics map[string]Epic) []Epic {
+	result := make([]Epic, 0, len(epics))
+	for _, e := range epics {
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Title != result[j].Title {
+			return result[i].Title < result[j].Title
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
@@ -0,0 +1,180 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+)
+
+const githubIssues = `[
+  {"number": 12, "title": "Crash on save", "body": "Steps", "state": "OPEN",
+   "labels": [{"name": "bug"}, {"name": "P1"}, {"name": "ui"}],
+   "milestone": {"title": "v2.0", "description": "Next release"},
+   "assignees": [{"login": "octocat"}], "createdAt": "2026-09-01T10:00:00Z",
+   "url": "https://github.com/acme/widgets/issues/12",
+   "comments": [{"author": {"login": "hubot"}, "body": "Reproduced", "createdAt": "2026-09-02T11:00:00Z"}]},
+  {"number": 13, "title": "Add dark mode", "state": "closed", "labels": ["enhancement"],
+   "comments": 0, "parent_issue_url": "https://api.github.com/repos/acme/widgets/issues/12"},
+  {"number": 14, "title": "Fix typo", "state": "open", "pull_request": {"url": "x"}}
+]`
+
+func TestParse_GitHub(t *testing.T) {
+	data, err := Parse("github", []byte(githubIssues), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "widgets", data.Board)
+	assert.Equal(t, []Epic{{Key: "v2.0", Title: "v2.0", Description: "Next release"}}, data.Epics)
+	require.Len(t, data.Tasks, 2, "pull requests are skipped")
+
+	crash := data.Tasks[0]
+	assert.Equal(t, "#12", crash.Key)
+	assert.Equal(t, "todo", crash.Column)
+	assert.Equal(t, "bug", crash.Type)
+	assert.Equal(t, "high", crash.Priority)
+	assert.Equal(t, []string{"ui"}, crash.Labels, "priority and type labels are consumed")
+	assert.Equal(t, []string{"octocat"}, crash.Assignees)
+	assert.Equal(t, "v2.0", crash.Epic)
+	assert.Equal(t, []Comment{{Author: "hubot", Content: "Reproduced", Created: "2026-09-02T11:00:00Z"}}, crash.Comments)
+
+	darkMode := data.Tasks[1]
+	assert.Equal(t, "done", darkMode.Column)
+	assert.Equal(t, "feature", darkMode.Type)
+	assert.Equal(t, "medium", darkMode.Priority)
+	assert.Equal(t, "#12", darkMode.Parent)
+}
+
+func TestParse_JiraCSV(t *testing.T) {
+	csv := "Summary,Issue key,Issue id,Issue Type,Status,Priority,Assignee,Labels,Labels,Parent id,Due date,Created,Comment,Comment,Project name\n" +
+		"Checkout,SHOP-1,100,Epic,In Progress,Medium,,,,,,,,,Shop\n" +
+		"Pay with card,SHOP-2,101,Story,In QA,Highest,jane,payments,,100,21/Oct/26,01/Sep/26 9:30 AM,\"02/Sep/26 10:15 AM;jane;Needs 3DS\",Plain note,Shop\n" +
+		"Add Stripe keys,SHOP-3,102,Sub-task,To Do,Low,,,,101,,,,,Shop\n"
+
+	m := &Mapping{Columns: map[string]string{"in qa": "review"}}
+	data, err := Parse("jira-csv", []byte(csv), m)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Shop", data.Board)
+	assert.Equal(t, []Epic{{Key: "SHOP-1", Title: "Checkout"}}, data.Epics)
+	require.Len(t, data.Tasks, 2, "epics are not tasks")
+
+	card := data.Tasks[0]
+	assert.Equal(t, "review", card.Column)
+	assert.Equal(t, "urgent", card.Priority)
+	assert.Equal(t, "feature", card.Type)
+	assert.Equal(t, "SHOP-1", card.Epic)
+	assert.Equal(t, "2026-10-21", card.DueDate)
+	assert.Equal(t, "2026-09-01T09:30:00Z", card.Created)
+	assert.Equal(t, []Comment{
+		{Author: "jane", Content: "Needs 3DS", Created: "2026-09-02T10:15:00Z"},
+		{Content: "Plain note"},
+	}, card.Comments)
+
+	keys := data.Tasks[1]
+	assert.Equal(t, "SHOP-2", keys.Parent)
+	assert.Equal(t, "todo", keys.Column)
+	assert.Equal(t, "feature", keys.Type, "generic issue types use the default type")
+	assert.Empty(t, data.Warnings)
+
+	_, err = Parse("jira-csv", []byte("Title,Status\nA,Open\n"), nil)
+	assert.ErrorContains(t, err, "not a Jira CSV export")
+}
+
+func TestParse_Trello(t *testing.T) {
+	board := `{
+	  "name": "Roadmap",
+	  "lists": [{"id": "l2", "name": "Doing", "pos": 2}, {"id": "l1", "name": "Ideas", "pos": 1},
+	            {"id": "l3", "name": "Old", "pos": 3, "closed": true}],
+	  "cards": [
+	    {"id": "c1", "idShort": 1, "name": "Sync", "idList": "l2", "pos": 1, "due": "2026-11-01T12:00:00.000Z",
+	     "labels": [{"name": "Urgent"}, {"name": "", "color": "green"}], "idMembers": ["m1"]},
+	    {"id": "c2", "idShort": 2, "name": "Offline", "idList": "l1", "pos": 5},
+	    {"id": "c3", "idShort": 3, "name": "Legacy", "idList": "l3", "pos": 1, "dateLastActivity": "2026-01-05T08:00:00.000Z"}
+	  ],
+	  "checklists": [{"idCard": "c1", "checkItems": [
+	    {"name": "Second", "state": "incomplete", "pos": 2}, {"name": "First", "state": "complete", "pos": 1}]}],
+	  "members": [{"id": "m1", "username": "ramtin"}],
+	  "actions": [
+	    {"type": "commentCard", "date": "2026-09-03T10:00:00.000Z", "data": {"text": "Later", "card": {"id": "c1"}}, "memberCreator": {"username": "ramtin"}},
+	    {"type": "commentCard", "date": "2026-09-01T10:00:00.000Z", "data": {"text": "Earlier", "card": {"id": "c1"}}, "memberCreator": {"username": "ramtin"}},
+	    {"type": "updateCard", "date": "2026-09-02T10:00:00.000Z", "data": {"card": {"id": "c1"}}}
+	  ]
+	}`
+	data, err := Parse("trello", []byte(board), &Mapping{Columns: map[string]string{"Ideas": "backlog"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Roadmap", data.Board)
+	require.Len(t, data.Tasks, 3)
+	assert.Equal(t, "#2", data.Tasks[0].Key, "cards are in list order")
+
+	sync := data.Tasks[1]
+	assert.Equal(t, "in_progress", sync.Column)
+	assert.Equal(t, "medium", sync.Priority, "plain words are labels, not priorities")
+	assert.Equal(t, []string{"Urgent", "green"}, sync.Labels)
+	assert.Equal(t, []string{"ramtin"}, sync.Assignees)
+	assert.Equal(t, "2026-11-01", sync.DueDate)
+	assert.Equal(t, []checklist.Item{{Text: "First", Done: true}, {Text: "Second"}}, sync.Checklist)
+	require.Len(t, sync.Comments, 2)
+	assert.Equal(t, "Earlier", sync.Comments[0].Content, "comments are oldest first")
+
+	legacy := data.Tasks[2]
+	assert.Equal(t, "2026-01-05T08:00:00Z", legacy.Archived, "cards of archived lists are archived")
+	assert.Equal(t, "todo", legacy.Column)
+	assert.Equal(t, []string{`status "Old" is not mapped; using todo`}, data.Warnings)
+}
+
+func TestParse_Linear(t *testing.T) {
+	csv := "ID,Team,Title,Description,Status,Priority,Project,Assignee,Labels,Created,Archived,Due Date,Parent issue\n" +
+		"ENG-2,Engineering,Login fails,,In Review,2,Auth,ana@example.com,\"Bug, backend\",2026-09-01T10:00:00.000Z,,2026-10-30,ENG-1\n" +
+		"ENG-1,Engineering,Auth rewrite,,Todo,No priority,Auth,,,2026-08-01T10:00:00.000Z,,,\n" +
+		"ENG-3,Engineering,Old spike,,Canceled,Low,,,,,2026-09-10T00:00:00.000Z,,ENG-9\n"
+
+	data, err := Parse("linear", []byte(csv), &Mapping{Users: map[string]string{"ana@example.com": "ana"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Engineering", data.Board)
+	assert.Equal(t, []Epic{{Key: "Auth", Title: "Auth"}}, data.Epics)
+	require.Len(t, data.Tasks, 3)
+	assert.Equal(t, "ENG-1", data.Tasks[0].Key, "parents come before their sub-tasks")
+
+	login := data.Tasks[1]
+	assert.Equal(t, "review", login.Column)
+	assert.Equal(t, "high", login.Priority)
+	assert.Equal(t, "bug", login.Type)
+	assert.Equal(t, []string{"backend"}, login.Labels)
+	assert.Equal(t, []string{"ana"}, login.Assignees)
+	assert.Equal(t, "ENG-1", login.Parent)
+	assert.Equal(t, "Auth", login.Epic)
+
+	assert.Equal(t, "medium", data.Tasks[0].Priority, "no priority uses the default")
+
+	spike := data.Tasks[2]
+	assert.Equal(t, "done", spike.Column)
+	assert.Equal(t, "2026-09-10T00:00:00Z", spike.Archived)
+	assert.Empty(t, spike.Parent)
+	assert.Equal(t, []string{"parent ENG-9 of ENG-3 is not in the export; leaving it out"}, data.Warnings)
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse("asana", nil, nil)
+	assert.ErrorContains(t, err, "unknown source")
+
+	_, err = Parse("github", []byte(`{"issues": []}`), nil)
+	assert.Error(t, err)
+
+	_, err = Parse("trello", []byte(`{"name": "x"}`), nil)
+	assert.ErrorContains(t, err, "not a Trello board export")
+}
+
+func TestParentsFirst_Cycle(t *testing.T) {
+	tasks := parentsFirst([]Task{
+		{Key: "A", Parent: "B"},
+		{Key: "B", Parent: "A"},
+	})
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "B", tasks[0].Key)
+	assert.Empty(t, tasks[0].Parent, "the link closing the cycle is dropped")
+	assert.Equal(t, "B", tasks[1].Parent)
+}
@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseJiraCSV reads a Jira issue export ("Export Excel CSV (all fields)").
+// Issues of type Epic become epics; other issues link to them through
+// their parent or the Epic Link field, and sub-tasks to their parent
+// issue. Comments are read from the repeated Comment columns, written by
+// Jira as "date;author;text".
+func parseJiraCSV(content []byte) (*source, error) {
+	table, err := readCSV(content)
+	if err != nil {
+		return nil, err
+	}
+	if !table.has("Summary") || !table.has("Issue key") {
+		return nil, fmt.Errorf("not a Jira CSV export: the Summary and Issue key columns are missing")
+	}
+
+	src := &source{}
+	epics := make(map[string]Epic)
+	keysByID := make(map[string]string) // issue id -> issue key
+	for _, row := range table.rows {
+		key := table.get(row, "Issue key")
+		if id := table.get(row, "Issue id"); id != "" {
+			keysByID[id] = key
+		}
+		if src.board == "" {
+			src.board = table.get(row, "Project name")
+		}
+		if strings.EqualFold(table.get(row, "Issue Type"), "Epic") {
+			title := table.get(row, "Custom field (Epic Name)", "Summary")
+			epics[key] = Epic{Key: key, Title: title, Description: table.get(row, "Description")}
+		}
+	}
+
+	for _, row := range table.rows {
+		issueType := table.get(row, "Issue Type")
+		if strings.EqualFold(issueType, "Epic") {
+			continue
+		}
+		t := Task{
+			Key:         table.get(row, "Issue key"),
+			Title:       table.get(row, "Summary"),
+			Description: table.get(row, "Description"),
+			Type:        issueType,
+			Priority:    table.get(row, "Priority"),
+			Column:      table.get(row, "Status"),
+			Labels:      table.all(row, "Labels"),
+			DueDate:     date(table.get(row, "Due date", "Due Date")),
+			Created:     timestamp(table.get(row, "Created")),
+		}
+		if assignee := table.get(row, "Assignee"); assignee != "" {
+			t.Assignees = []string{assignee}
+		}
+
+		// Parent is the parent's issue id (or key in some exports), which
+		// is an epic or, for sub-tasks, an issue
+		parent := table.get(row, "Parent id", "Parent", "Parent key")
+		if key, ok := keysByID[parent]; ok {
+			parent = key
+		}
+		if _, ok := epics[parent]; ok {
+			t.Epic = parent
+		} else {
+			t.Parent = parent
+		}
+		if link := table.get(row, "Custom field (Epic Link)"); link != "" && t.Epic == "" {
+			t.Epic = link
+		}
+
+		for _, c := range table.all(row, "Comment") {
+			t.Comments = append(t.Comments, jiraComment(c))
+		}
+		src.tasks = append(src.tasks, t)
+	}
+	src.epics = sortedEpics(epics)
+	return src, nil
+}
+
+// jiraComment parses a comment column: "21/Jan/25 10:15 AM;author;text",
+// or the text alone.
+func jiraComment(value string) Comment {
+	parts := strings.SplitN(value, ";", 3)
+	if len(parts) == 3 && !parseTime(parts[0]).IsZero() {
+		return Comment{Created: timestamp(parts[0]), Author: parts[1], Content: strings.TrimSpace(parts[2])}
+	}
+	return Comment{Content: value}
+}
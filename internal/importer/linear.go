@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"fmt"
+)
+
+// linearPriorities are Linear's numeric priorities, which some exports use
+// in place of the names.
+var linearPriorities = map[string]string{
+	"0": "No priority", "1": "Urgent", "2": "High", "3": "Medium", "4": "Low",
+}
+
+// parseLinear reads a Linear CSV export (Settings, Import / Export, Export
+// CSV). Projects become epics and parent issues parents; Linear has no
+// issue types, so labels such as "Bug" set the type. The export has no
+// comments.
+func parseLinear(content []byte) (*source, error) {
+	table, err := readCSV(content)
+	if err != nil {
+		return nil, err
+	}
+	if !table.has("ID") || !table.has("Title") || !table.has("Status") {
+		return nil, fmt.Errorf("not a Linear CSV export: the ID, Title, and Status columns are missing")
+	}
+
+	src := &source{}
+	epics := make(map[string]Epic)
+	for _, row := range table.rows {
+		if src.board == "" {
+			src.board = table.get(row, "Team")
+		}
+		priority := table.get(row, "Priority")
+		if name, ok := linearPriorities[priority]; ok {
+			priority = name
+		}
+		t := Task{
+			Key:         table.get(row, "ID"),
+			Title:       table.get(row, "Title"),
+			Description: table.get(row, "Description"),
+			Priority:    priority,
+			Column:      table.get(row, "Status"),
+			Labels:      splitList(table.get(row, "Labels")),
+			Parent:      table.get(row, "Parent issue"),
+			DueDate:     date(table.get(row, "Due Date")),
+			Created:     timestamp(table.get(row, "Created")),
+			Archived:    timestamp(table.get(row, "Archived")),
+		}
+		if assignee := table.get(row, "Assignee"); assignee != "" {
+			t.Assignees = []string{assignee}
+		}
+		if project := table.get(row, "Project"); project != "" {
+			t.Epic = project
+			epics[project] = Epic{Key: project, Title: project}
+		}
+		src.tasks = append(src.tasks, t)
+	}
+	src.epics = sortedEpics(epics)
+	return src, nil
+}
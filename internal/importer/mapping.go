@@ -0,0 +1,275 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+)
+
+// Mapping overrides how the values of an export map onto EgenSkriven. It is
+// read from a YAML or JSON file:
+//
+//	board: Work
+//	prefix: WRK
+//	columns:
+//	  "In QA": review
+//	priorities:
+//	  P0: urgent
+//	types:
+//	  Spike: chore
+//	labels:
+//	  "good first issue": ""   # drop the label
+//	users:
+//	  octocat: ramtin
+//	defaults:
+//	  column: backlog
+//
+// Keys are matched case-insensitively, ignoring punctuation, so "In QA",
+// "in-qa", and "IN_QA" are the same status.
+type Mapping struct {
+	// Board is the name of the board to import into, created if missing.
+	Board string `yaml:"board"`
+	// Prefix is the prefix of a created board.
+	Prefix string `yaml:"prefix"`
+	// Columns maps statuses, states, and list names to columns.
+	Columns map[string]string `yaml:"columns"`
+	// Priorities maps priorities, and labels such as "P1", to priorities.
+	Priorities map[string]string `yaml:"priorities"`
+	// Types maps issue types, and labels such as "bug", to task types.
+	Types map[string]string `yaml:"types"`
+	// Labels renames labels; an empty name drops the label.
+	Labels map[string]string `yaml:"labels"`
+	// Users maps user names, logins, and account IDs to assignee and
+	// comment author names.
+	Users map[string]string `yaml:"users"`
+	// Defaults apply to values that map to nothing.
+	Defaults Defaults `yaml:"defaults"`
+}
+
+// Defaults are the values of tasks whose source value is missing or not
+// mapped.
+type Defaults struct {
+	Column   string `yaml:"column"`
+	Priority string `yaml:"priority"`
+	Type     string `yaml:"type"`
+}
+
+// Valid task values
+var (
+	validPriorities = []string{"low", "medium", "high", "urgent"}
+	validTypes      = []string{"bug", "feature", "chore"}
+)
+
+// builtinColumns maps common statuses of other trackers to columns.
+var builtinColumns = map[string]string{
+	"backlog": "backlog", "triage": "backlog", "icebox": "backlog",
+
+	"todo": "todo", "to_do": "todo", "open": "todo", "new": "todo", "ready": "todo",
+	"unstarted": "todo", "planned": "todo", "selected_for_development": "todo",
+
+	"in_progress": "in_progress", "doing": "in_progress", "started": "in_progress",
+	"in_development": "in_progress", "active": "in_progress",
+
+	"need_input": "need_input", "blocked": "need_input", "waiting": "need_input",
+	"on_hold": "need_input",
+
+	"review": "review", "in_review": "review", "code_review": "review", "qa": "review",
+	"in_qa": "review", "testing": "review",
+
+	"done": "done", "closed": "done", "resolved": "done", "completed": "done",
+	"complete": "done", "canceled": "done", "cancelled": "done", "duplicate": "done",
+	"won_t_do": "done", "wont_do": "done", "not_planned": "done", "shipped": "done",
+	"released": "done",
+}
+
+// builtinPriorities maps common priority names to priorities. "none" maps
+// to the default priority.
+var builtinPriorities = map[string]string{
+	"urgent": "urgent", "highest": "urgent", "critical": "urgent", "blocker": "urgent", "p0": "urgent",
+	"high": "high", "major": "high", "p1": "high",
+	"medium": "medium", "normal": "medium", "p2": "medium",
+	"low": "low", "lowest": "low", "minor": "low", "trivial": "low", "p3": "low", "p4": "low",
+	"no_priority": "", "none": "",
+}
+
+// builtinTypes maps common issue types to task types. Generic types map to
+// the default type.
+var builtinTypes = map[string]string{
+	"bug": "bug", "defect": "bug",
+	"feature": "feature", "story": "feature", "user_story": "feature", "enhancement": "feature",
+	"improvement": "feature", "new_feature": "feature",
+	"chore": "chore", "maintenance": "chore", "tech_debt": "chore", "refactor": "chore",
+	"task": "", "sub_task": "", "subtask": "", "issue": "",
+}
+
+// LoadMapping reads a mapping file. Unknown keys are errors, so a typo
+// does not silently leave values unmapped.
+func LoadMapping(path string) (*Mapping, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping file: %w", err)
+	}
+	var m Mapping
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid mapping file %s: %w", path, err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid mapping file %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// validate checks that the mapping only maps to valid values.
+func (m *Mapping) validate() error {
+	sections := []struct {
+		name   string
+		values map[string]string
+		valid  []string
+		empty  bool // an empty value is allowed
+	}{
+		{"columns", m.Columns, board.DefaultColumns, false},
+		{"priorities", m.Priorities, validPriorities, true},
+		{"types", m.Types, validTypes, true},
+		{"defaults", map[string]string{"column": m.Defaults.Column}, board.DefaultColumns, true},
+		{"defaults", map[string]string{"priority": m.Defaults.Priority}, validPriorities, true},
+		{"defaults", map[string]string{"type": m.Defaults.Type}, validTypes, true},
+	}
+	for _, section := range sections {
+		keys := make([]string, 0, len(section.values))
+		for k := range section.values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := section.values[k]
+			if (v == "" && section.empty) || containsString(section.valid, v) {
+				continue
+			}
+			return fmt.Errorf("%s: %q maps to %q, which is not one of %s",
+				section.name, k, v, strings.Join(section.valid, ", "))
+		}
+	}
+	return nil
+}
+
+// lookup finds a value in a mapping section, then in the built-in map.
+func lookup(overrides, builtin map[string]string, value string) (string, bool) {
+	key := normalizeKey(value)
+	for k, v := range overrides {
+		if normalizeKey(k) == key {
+			return v, true
+		}
+	}
+	v, ok := builtin[key]
+	return v, ok
+}
+
+func (m *Mapping) defaultColumn() string {
+	if m.Defaults.Column != "" {
+		return m.Defaults.Column
+	}
+	return "todo"
+}
+
+func (m *Mapping) defaultPriority() string {
+	if m.Defaults.Priority != "" {
+		return m.Defaults.Priority
+	}
+	return "medium"
+}
+
+func (m *Mapping) defaultType() string {
+	if m.Defaults.Type != "" {
+		return m.Defaults.Type
+	}
+	return "feature"
+}
+
+// user maps a user of the source.
+func (m *Mapping) user(name string) string {
+	for k, v := range m.Users {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return name
+}
+
+// label renames a label; ok is false when the label is dropped.
+func (m *Mapping) label(name string) (string, bool) {
+	for k, v := range m.Labels {
+		if normalizeKey(k) == normalizeKey(name) {
+			return v, v != ""
+		}
+	}
+	return name, true
+}
+
+// labelPriority returns the priority a label sets: a label of the mapping's
+// priorities, or one such as "priority: high" or "P1".
+func (m *Mapping) labelPriority(label string) (string, bool) {
+	key := normalizeKey(label)
+	for k, v := range m.Priorities {
+		if normalizeKey(k) == key {
+			return v, true
+		}
+	}
+	if rest, ok := strings.CutPrefix(key, "priority_"); ok {
+		key = rest
+	} else if len(key) != 2 || key[0] != 'p' {
+		return "", false
+	}
+	v, ok := builtinPriorities[key]
+	return v, ok
+}
+
+// labelType returns the task type a label sets: a label of the mapping's
+// types, a type name such as "bug", or one such as "type: bug".
+func (m *Mapping) labelType(label string) (string, bool) {
+	key := normalizeKey(label)
+	for k, v := range m.Types {
+		if normalizeKey(k) == key {
+			return v, v != ""
+		}
+	}
+	key = strings.TrimPrefix(key, "type_")
+	v, ok := builtinTypes[key]
+	return v, ok && v != ""
+}
+
+// normalizeKey lowercases a value and replaces runs of other characters
+// than letters and digits with "_", so "In Review" becomes "in_review".
+func normalizeKey(s string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
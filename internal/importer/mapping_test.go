@@ -0,0 +1,84 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMapping(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadMapping(t *testing.T) {
+	m, err := LoadMapping(writeMapping(t, "mapping.yaml", `
+board: Work
+prefix: WRK
+columns:
+  In QA: review
+priorities:
+  P0: urgent
+labels:
+  wontfix: ""
+defaults:
+  column: backlog
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "Work", m.Board)
+	assert.Equal(t, "backlog", m.defaultColumn())
+
+	column, ok := lookup(m.Columns, builtinColumns, "in-qa")
+	assert.True(t, ok)
+	assert.Equal(t, "review", column)
+
+	_, kept := m.label("WontFix")
+	assert.False(t, kept)
+
+	// JSON is read too
+	m, err = LoadMapping(writeMapping(t, "mapping.json", `{"types": {"Spike": "chore"}}`))
+	require.NoError(t, err)
+	typ, ok := m.labelType("spike")
+	assert.True(t, ok)
+	assert.Equal(t, "chore", typ)
+
+	// An empty file is an empty mapping
+	_, err = LoadMapping(writeMapping(t, "empty.yaml", ""))
+	assert.NoError(t, err)
+}
+
+func TestLoadMapping_Invalid(t *testing.T) {
+	_, err := LoadMapping(writeMapping(t, "typo.yaml", "colums:\n  Open: todo\n"))
+	assert.ErrorContains(t, err, "colums")
+
+	_, err = LoadMapping(writeMapping(t, "column.yaml", "columns:\n  Open: doing\n"))
+	assert.ErrorContains(t, err, `columns: "Open" maps to "doing"`)
+
+	_, err = LoadMapping(writeMapping(t, "default.yaml", "defaults:\n  priority: asap\n"))
+	assert.ErrorContains(t, err, "defaults")
+
+	_, err = LoadMapping(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestNormalizeKey(t *testing.T) {
+	assert.Equal(t, "in_review", normalizeKey(" In Review "))
+	assert.Equal(t, "won_t_do", normalizeKey("Won't Do"))
+	assert.Equal(t, "priority_high", normalizeKey("priority: high"))
+}
+
+func TestLabelPriority(t *testing.T) {
+	m := &Mapping{}
+	for label, want := range map[string]string{"P1": "high", "priority: urgent": "urgent", "priority/low": "low"} {
+		got, ok := m.labelPriority(label)
+		assert.True(t, ok, label)
+		assert.Equal(t, want, got, label)
+	}
+	_, ok := m.labelPriority("high")
+	assert.False(t, ok, "plain words are labels, not priorities")
+}
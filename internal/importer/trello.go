@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+)
+
+// trelloBoard is a board exported from Trello ("Print, export, and share"
+// then "Export as JSON").
+type trelloBoard struct {
+	Name  string `json:"name"`
+	Lists []struct {
+		ID     string  `json:"id"`
+		Name   string  `json:"name"`
+		Closed bool    `json:"closed"`
+		Pos    float64 `json:"pos"`
+	} `json:"lists"`
+	Cards []struct {
+		ID               string   `json:"id"`
+		IDShort          int      `json:"idShort"`
+		Name             string   `json:"name"`
+		Desc             string   `json:"desc"`
+		IDList           string   `json:"idList"`
+		Closed           bool     `json:"closed"`
+		Pos              float64  `json:"pos"`
+		Due              string   `json:"due"`
+		IDMembers        []string `json:"idMembers"`
+		DateLastActivity string   `json:"dateLastActivity"`
+		Labels           []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Checklists []struct {
+		IDCard     string  `json:"idCard"`
+		Pos        float64 `json:"pos"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"members"`
+	Actions []struct {
+		Type string `json:"type"`
+		Date string `json:"date"`
+		Data struct {
+			Text string `json:"text"`
+			Card struct {
+				ID string `json:"id"`
+			} `json:"card"`
+		} `json:"data"`
+		MemberCreator struct {
+			Username string `json:"username"`
+		} `json:"memberCreator"`
+	} `json:"actions"`
+}
+
+// parseTrello reads a Trello board export. Lists map to columns by name,
+// checklists become task checklists, and archived cards, or cards of
+// archived lists, are imported archived. Trello has no epics.
+func parseTrello(content []byte) (*source, error) {
+	var b trelloBoard
+	if err := json.Unmarshal(content, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse Trello board: %w", err)
+	}
+	if b.Lists == nil && b.Cards == nil {
+		return nil, fmt.Errorf("not a Trello board export: the lists and cards are missing")
+	}
+
+	type list struct {
+		name   string
+		closed bool
+		pos    float64
+	}
+	lists := make(map[string]list, len(b.Lists))
+	for _, l := range b.Lists {
+		lists[l.ID] = list{l.Name, l.Closed, l.Pos}
+	}
+	members := make(map[string]string, len(b.Members))
+	for _, m := range b.Members {
+		members[m.ID] = m.Username
+	}
+
+	checklists := b.Checklists
+	sort.SliceStable(checklists, func(i, j int) bool { return checklists[i].Pos < checklists[j].Pos })
+
+	// Actions are listed newest first
+	actions := b.Actions
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].Date < actions[j].Date })
+
+	cards := b.Cards
+	sort.SliceStable(cards, func(i, j int) bool {
+		li, lj := lists[cards[i].IDList], lists[cards[j].IDList]
+		if li.pos != lj.pos {
+			return li.pos < lj.pos
+		}
+		return cards[i].Pos < cards[j].Pos
+	})
+
+	src := &source{board: b.Name}
+	for _, card := range cards {
+		l := lists[card.IDList]
+		t := Task{
+			Key:         "#" + strconv.Itoa(card.IDShort),
+			Title:       card.Name,
+			Description: card.Desc,
+			Column:      l.name,
+			DueDate:     date(card.Due),
+		}
+		if card.IDShort == 0 {
+			t.Key = card.ID
+		}
+		if card.Closed || l.closed {
+			t.Archived = timestamp(card.DateLastActivity)
+		}
+		for _, label := range card.Labels {
+			t.Labels = append(t.Labels, firstNonEmpty(label.Name, label.Color))
+		}
+		for _, id := range card.IDMembers {
+			if name := members[id]; name != "" {
+				t.Assignees = append(t.Assignees, name)
+			}
+		}
+
+		for _, c := range checklists {
+			if c.IDCard != card.ID {
+				continue
+			}
+			items := c.CheckItems
+			sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+			for _, item := range items {
+				t.Checklist = append(t.Checklist, checklist.Item{Text: item.Name, Done: item.State == "complete"})
+			}
+		}
+
+		for _, a := range actions {
+			if a.Type == "commentCard" && a.Data.Card.ID == card.ID {
+				t.Comments = append(t.Comments, Comment{
+					Author:  a.MemberCreator.Username,
+					Content: a.Data.Text,
+					Created: timestamp(a.Date),
+				})
+			}
+		}
+		src.tasks = append(src.tasks, t)
+	}
+	return src, nil
+}
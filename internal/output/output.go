@@ -3,11 +3,19 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+	"github.com/ramtinJ95/EgenSkriven/internal/estimate"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasklink"
+	"github.com/ramtinJ95/EgenSkriven/internal/timetrack"
 )
 
 // exitFunc is the function called to exit the program.
@@ -47,6 +55,21 @@ func (f *Formatter) Task(task *core.Record, action string) {
 	fmt.Printf("%s task: %s [%s]\n", action, task.GetString("title"), ShortID(task.Id))
 }
 
+// TaskWithExtra outputs a single task like Task, adding extra fields to
+// the JSON object.
+func (f *Formatter) TaskWithExtra(task *core.Record, action string, extra map[string]any) {
+	if !f.JSON {
+		f.Task(task, action)
+		return
+	}
+
+	result := taskToMap(task)
+	for k, v := range extra {
+		result[k] = v
+	}
+	f.writeJSON(result)
+}
+
 // Tasks outputs a list of tasks.
 // Human mode: Grouped by column
 // JSON mode: Array with count
@@ -90,15 +113,7 @@ func (f *Formatter) TasksWithFields(tasks []*core.Record, fields []string) {
 	// Build filtered task list
 	result := make([]map[string]any, len(tasks))
 	for i, task := range tasks {
-		fullMap := taskToMap(task)
-		filtered := make(map[string]any)
-		for _, field := range fields {
-			field = strings.TrimSpace(field)
-			if val, ok := fullMap[field]; ok {
-				filtered[field] = val
-			}
-		}
-		result[i] = filtered
+		result[i] = selectFields(taskToMap(task), fields)
 	}
 
 	f.writeJSON(map[string]any{
@@ -150,15 +165,7 @@ func (f *Formatter) TasksWithFieldsAndBoards(tasks []*core.Record, fields []stri
 	// Build filtered task list
 	result := make([]map[string]any, len(tasks))
 	for i, task := range tasks {
-		fullMap := taskToMapWithBoard(task, boardsMap)
-		filtered := make(map[string]any)
-		for _, field := range fields {
-			field = strings.TrimSpace(field)
-			if val, ok := fullMap[field]; ok {
-				filtered[field] = val
-			}
-		}
-		result[i] = filtered
+		result[i] = selectFields(taskToMapWithBoard(task, boardsMap), fields)
 	}
 
 	f.writeJSON(map[string]any{
@@ -181,6 +188,11 @@ func (f *Formatter) TaskDetail(task *core.Record) {
 	fmt.Printf("Column:      %s\n", task.GetString("column"))
 	fmt.Printf("Position:    %.0f\n", task.GetFloat("position"))
 
+	// Archive state
+	if archivedAt := task.GetDateTime("archived_at"); !archivedAt.IsZero() {
+		fmt.Printf("Archived:    %s by %s\n", archivedAt.Time().Format("2006-01-02"), task.GetString("archived_by"))
+	}
+
 	// Labels
 	labels := getLabels(task)
 	if len(labels) > 0 {
@@ -195,6 +207,11 @@ func (f *Formatter) TaskDetail(task *core.Record) {
 		fmt.Printf("Blocked by:  %s\n", strings.Join(blockedBy, ", "))
 	}
 
+	// Checklist progress (the items are shown in the description)
+	if progress := checklist.FormatProgress(checklist.Get(task)); progress != "" {
+		fmt.Printf("Checklist:   %s done\n", progress)
+	}
+
 	// Created by
 	createdBy := task.GetString("created_by")
 	if agent := task.GetString("created_by_agent"); agent != "" {
@@ -215,14 +232,63 @@ func (f *Formatter) TaskDetail(task *core.Record) {
 	fmt.Println()
 }
 
+// TaskExtras holds related data shown alongside a task in detail views.
+// Nil fields are omitted from the output.
+type TaskExtras struct {
+	// TimeLogged is the total time tracked on the task
+	TimeLogged *timetrack.Summary `json:"time_logged,omitempty"`
+	// SprintName is the name of the sprint the task belongs to
+	SprintName string `json:"sprint_name,omitempty"`
+	// Estimate is the task's size including its sub-tasks
+	Estimate *estimate.Totals `json:"estimate,omitempty"`
+	// EstimateUnit is the board's estimate unit (points or hours)
+	EstimateUnit string `json:"estimate_unit,omitempty"`
+	// History is the task's event log, oldest first
+	History []taskevent.Event `json:"history"`
+	// Attachments are the files attached to the task and its comments
+	Attachments []attachment.Info `json:"attachments"`
+	// Links are the typed relations to other tasks
+	Links []tasklink.Link `json:"links"`
+}
+
 // TaskDetailWithSubtasks outputs detailed information about a task including its sub-tasks.
 func (f *Formatter) TaskDetailWithSubtasks(task *core.Record, subtasks []*core.Record) {
+	f.TaskDetailWithExtras(task, subtasks, TaskExtras{})
+}
+
+// TaskDetailWithExtras outputs detailed information about a task including
+// its sub-tasks and related data such as logged time.
+func (f *Formatter) TaskDetailWithExtras(task *core.Record, subtasks []*core.Record, extras TaskExtras) {
 	if f.JSON {
 		result := taskToMap(task)
 		result["subtasks"] = tasksToMaps(subtasks)
 		result["subtask_count"] = len(subtasks)
 		// Include agent_session in JSON output
 		result["agent_session"] = task.Get("agent_session")
+		if extras.TimeLogged != nil {
+			result["time_logged"] = map[string]any{
+				"total_seconds": int64(extras.TimeLogged.Total.Seconds()),
+				"entries":       extras.TimeLogged.Entries,
+				"running":       extras.TimeLogged.Running,
+			}
+		}
+		if sprintID := task.GetString("sprint"); sprintID != "" {
+			result["sprint"] = sprintID
+			result["sprint_name"] = extras.SprintName
+		}
+		if extras.Estimate != nil && extras.Estimate.Estimated > 0 {
+			result["estimate_rollup"] = extras.Estimate
+			result["estimate_unit"] = estimate.NormalizeUnit(extras.EstimateUnit)
+		}
+		if extras.History != nil {
+			result["history"] = extras.History
+		}
+		if extras.Attachments != nil {
+			result["attachments"] = extras.Attachments
+		}
+		if extras.Links != nil {
+			result["links"] = extras.Links
+		}
 		f.writeJSON(result)
 		return
 	}
@@ -234,6 +300,11 @@ func (f *Formatter) TaskDetailWithSubtasks(task *core.Record, subtasks []*core.R
 	fmt.Printf("Column:      %s\n", task.GetString("column"))
 	fmt.Printf("Position:    %.0f\n", task.GetFloat("position"))
 
+	// Archive state
+	if archivedAt := task.GetDateTime("archived_at"); !archivedAt.IsZero() {
+		fmt.Printf("Archived:    %s by %s\n", archivedAt.Time().Format("2006-01-02"), task.GetString("archived_by"))
+	}
+
 	// Due date
 	if dueDate := task.GetDateTime("due_date"); !dueDate.IsZero() {
 		fmt.Printf("Due:         %s\n", dueDate.Time().Format("2006-01-02"))
@@ -244,6 +315,16 @@ func (f *Formatter) TaskDetailWithSubtasks(task *core.Record, subtasks []*core.R
 		fmt.Printf("Parent:      %s\n", ShortID(parent))
 	}
 
+	// Sprint
+	if extras.SprintName != "" {
+		fmt.Printf("Sprint:      %s\n", extras.SprintName)
+	}
+
+	// Estimate (rolled up from sub-tasks when they are estimated)
+	if text := estimate.Describe(estimate.ItemOf(task), extras.Estimate, extras.EstimateUnit); text != "" {
+		fmt.Printf("Estimate:    %s\n", text)
+	}
+
 	// Labels
 	labels := getLabels(task)
 	if len(labels) > 0 {
@@ -252,12 +333,22 @@ func (f *Formatter) TaskDetailWithSubtasks(task *core.Record, subtasks []*core.R
 		fmt.Printf("Labels:      -\n")
 	}
 
+	// Assignees
+	if assignees := getAssignees(task); len(assignees) > 0 {
+		fmt.Printf("Assignees:   %s\n", strings.Join(assignees, ", "))
+	}
+
 	// Blocked by
 	blockedBy := getBlockedBy(task)
 	if len(blockedBy) > 0 {
 		fmt.Printf("Blocked by:  %s\n", strings.Join(blockedBy, ", "))
 	}
 
+	// Checklist progress (the items are shown in the description)
+	if progress := checklist.FormatProgress(checklist.Get(task)); progress != "" {
+		fmt.Printf("Checklist:   %s done\n", progress)
+	}
+
 	// Created by
 	createdBy := task.GetString("created_by")
 	if agent := task.GetString("created_by_agent"); agent != "" {
@@ -270,6 +361,18 @@ func (f *Formatter) TaskDetailWithSubtasks(task *core.Record, subtasks []*core.R
 	fmt.Printf("Created:     %s\n", formatTime(task.GetDateTime("created").Time()))
 	fmt.Printf("Updated:     %s\n", formatTime(task.GetDateTime("updated").Time()))
 
+	// Logged time
+	if tl := extras.TimeLogged; tl != nil && tl.Entries > 0 {
+		detail := fmt.Sprintf("%d entries", tl.Entries)
+		if tl.Entries == 1 {
+			detail = "1 entry"
+		}
+		if tl.Running {
+			detail += ", timer running"
+		}
+		fmt.Printf("Time logged: %s (%s)\n", timetrack.FormatDuration(tl.Total), detail)
+	}
+
 	// Description
 	if desc := task.GetString("description"); desc != "" {
 		fmt.Printf("\nDescription:\n  %s\n", strings.ReplaceAll(desc, "\n", "\n  "))
@@ -316,9 +419,51 @@ func (f *Formatter) TaskDetailWithSubtasks(task *core.Record, subtasks []*core.R
 		}
 	}
 
+	// Links
+	if len(extras.Links) > 0 {
+		fmt.Printf("\nLinks (%d):\n", len(extras.Links))
+		for _, l := range extras.Links {
+			line := fmt.Sprintf("  %-15s %s %s (%s)", l.Label, l.DisplayID, l.Title, l.Column)
+			if l.OneWay {
+				line += " [one-way]"
+			}
+			fmt.Println(line)
+		}
+	}
+
+	// Attachments
+	if len(extras.Attachments) > 0 {
+		fmt.Printf("\nAttachments (%d):\n", len(extras.Attachments))
+		for i, a := range extras.Attachments {
+			line := fmt.Sprintf("  %2d. %s (%s)", i+1, a.DisplayName, attachment.FormatSize(a.Size))
+			if a.Comment != "" {
+				line += " on comment " + ShortID(a.Comment)
+			}
+			fmt.Println(line)
+		}
+	}
+
+	// History (most recent events; 'egenskriven log <task>' shows all)
+	if len(extras.History) > 0 {
+		events := extras.History
+		if len(events) > maxHistoryShown {
+			fmt.Printf("\nHistory (last %d of %d):\n", maxHistoryShown, len(events))
+			events = events[len(events)-maxHistoryShown:]
+		} else {
+			fmt.Printf("\nHistory:\n")
+		}
+		for _, e := range events {
+			fmt.Printf("  %s  %-14s %-12s %s\n",
+				e.Timestamp.Local().Format("2006-01-02 15:04"), e.Action, e.ActorName(), e.Describe())
+		}
+	}
+
 	fmt.Println()
 }
 
+// maxHistoryShown is the number of history events shown in task details.
+const maxHistoryShown = 10
+
 // TruncateMiddle truncates a string in the middle if too long.
 // It preserves the beginning and end of the string, replacing the middle with "...".
 func TruncateMiddle(s string, maxLen int) string {
@@ -520,7 +665,7 @@ func (f *Formatter) printTaskLine(task *core.Record) {
 		priorityIndicator = "!"
 	}
 
-	fmt.Printf("  [%s] %s (%s%s)\n",
+	fmt.Printf("  [%s] %s (%s%s%s)\n",
 		ShortID(task.Id),
 		task.GetString("title"),
 		task.GetString("type"),
@@ -530,10 +675,15 @@ func (f *Formatter) printTaskLine(task *core.Record) {
 			}
 			return ""
 		}(),
+		checklistText(task),
 	)
 }
 
 func (f *Formatter) printTaskLineWithBoard(task *core.Record, boardsMap map[string]*core.Record) {
+	fprintTaskLineWithBoard(os.Stdout, task, boardsMap)
+}
+
+func fprintTaskLineWithBoard(w io.Writer, task *core.Record, boardsMap map[string]*core.Record) {
 	priority := task.GetString("priority")
 	priorityIndicator := ""
 	switch priority {
@@ -547,7 +697,21 @@ func (f *Formatter) printTaskLineWithBoard(task *core.Record, boardsMap map[stri
 
 	displayID := getDisplayID(task, boardsMap)
 
-	fmt.Printf("  [%s] %s (%s%s)\n",
+	estimateText := ""
+	unit := ""
+	if boardRecord, ok := boardsMap[task.GetString("board")]; ok {
+		unit = boardRecord.GetString("estimate_unit")
+	}
+	if text := estimate.Describe(estimate.ItemOf(task), nil, unit); text != "" {
+		estimateText = ", " + text
+	}
+
+	assigneeText := ""
+	if assignees := getAssignees(task); len(assignees) > 0 {
+		assigneeText = " @" + strings.Join(assignees, " @")
+	}
+
+	fmt.Fprintf(w, "  [%s] %s (%s%s%s)%s\n",
 		displayID,
 		task.GetString("title"),
 		task.GetString("type"),
@@ -557,11 +721,22 @@ func (f *Formatter) printTaskLineWithBoard(task *core.Record, boardsMap map[stri
 			}
 			return ""
 		}(),
+		estimateText+checklistText(task),
+		assigneeText,
 	)
 }
 
+// checklistText formats checklist progress for task lines, or "" when the
+// task has no checklist.
+func checklistText(task *core.Record) string {
+	if progress := checklist.FormatProgress(checklist.Get(task)); progress != "" {
+		return ", checklist " + progress
+	}
+	return ""
+}
+
 func taskToMap(task *core.Record) map[string]any {
-	return map[string]any{
+	result := map[string]any{
 		"id":               task.Id,
 		"title":            task.GetString("title"),
 		"description":      task.GetString("description"),
@@ -576,6 +751,40 @@ func taskToMap(task *core.Record) map[string]any {
 		"created":          task.GetDateTime("created").Time().Format(time.RFC3339),
 		"updated":          task.GetDateTime("updated").Time().Format(time.RFC3339),
 	}
+	if est := task.GetFloat("estimate"); est > 0 {
+		result["estimate"] = est
+	}
+	if dueDate := task.GetDateTime("due_date"); !dueDate.IsZero() {
+		result["due_date"] = dueDate.Time().Format("2006-01-02")
+	}
+	if assignees := getAssignees(task); len(assignees) > 0 {
+		result["assignees"] = assignees
+	}
+	if archivedAt := task.GetDateTime("archived_at"); !archivedAt.IsZero() {
+		result["archived_at"] = archivedAt.Time().Format(time.RFC3339)
+		result["archived_by"] = task.GetString("archived_by")
+	}
+	if items := checklist.Get(task); len(items) > 0 {
+		result["checklist"] = items
+		result["checklist_progress"] = checklist.FormatProgress(items)
+	}
+	return result
+}
+
+// selectFields returns the given fields of a task map, or the whole map
+// when fields is empty.
+func selectFields(task map[string]any, fields []string) map[string]any {
+	if len(fields) == 0 {
+		return task
+	}
+	filtered := make(map[string]any)
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if val, ok := task[field]; ok {
+			filtered[field] = val
+		}
+	}
+	return filtered
 }
 
 func tasksToMaps(tasks []*core.Record) []map[string]any {
@@ -594,6 +803,17 @@ func tasksToMapsWithBoards(tasks []*core.Record, boardsMap map[string]*core.Reco
 	return result
 }
 
+// TaskMaps returns the JSON objects of tasks as in error data.
+func TaskMaps(tasks []*core.Record) []map[string]any {
+	return tasksToMaps(tasks)
+}
+
+// TaskMap returns the JSON object of a task as listed by list --json,
+// with the display ID taken from the board in boardsMap.
+func TaskMap(task *core.Record, boardsMap map[string]*core.Record) map[string]any {
+	return taskToMapWithBoard(task, boardsMap)
+}
+
 func taskToMapWithBoard(task *core.Record, boardsMap map[string]*core.Record) map[string]any {
 	result := taskToMap(task)
 	result["display_id"] = getDisplayID(task, boardsMap)
@@ -604,6 +824,13 @@ func taskToMapWithBoard(task *core.Record, boardsMap map[string]*core.Record) ma
 		result["seq"] = task.GetInt("seq")
 	}
 
+	if epicID := task.GetString("epic"); epicID != "" {
+		result["epic"] = epicID
+	}
+	if sprintID := task.GetString("sprint"); sprintID != "" {
+		result["sprint"] = sprintID
+	}
+
 	return result
 }
 
@@ -637,6 +864,11 @@ func getLabels(task *core.Record) []string {
 	return task.GetStringSlice("labels")
 }
 
+func getAssignees(task *core.Record) []string {
+	// Use GetStringSlice which properly handles types.JSONRaw
+	return task.GetStringSlice("assignees")
+}
+
 func getBlockedBy(task *core.Record) []string {
 	// Use GetStringSlice which properly handles types.JSONRaw
 	return task.GetStringSlice("blocked_by")
@@ -0,0 +1,357 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pocketbase/pocketbase/core"
+	"gopkg.in/yaml.v3"
+)
+
+// Task list formats accepted by Formatter.TaskList.
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatNDJSON   = "ndjson"
+	FormatYAML     = "yaml"
+	FormatMarkdown = "markdown"
+)
+
+// ListFormats returns the names of the task list formats.
+func ListFormats() []string {
+	return []string{FormatText, FormatJSON, FormatNDJSON, FormatYAML, FormatMarkdown}
+}
+
+// NormalizeListFormat returns the task list format called name, accepting
+// the aliases "yml" and "md", or "" when name is not a list format.
+func NormalizeListFormat(name string) string {
+	switch strings.ToLower(name) {
+	case FormatText:
+		return FormatText
+	case FormatJSON:
+		return FormatJSON
+	case FormatNDJSON:
+		return FormatNDJSON
+	case FormatYAML, "yml":
+		return FormatYAML
+	case FormatMarkdown, "md":
+		return FormatMarkdown
+	}
+	return ""
+}
+
+// TaskGroup is a group of tasks in list output, such as the tasks of one
+// epic.
+type TaskGroup struct {
+	// Key identifies the group, e.g. the epic ID. It is empty for the group
+	// of tasks without a value.
+	Key   string
+	Name  string
+	Tasks []*core.Record
+}
+
+// TaskList is a list of tasks to print with Formatter.TaskList.
+type TaskList struct {
+	Tasks  []*core.Record
+	Boards map[string]*core.Record
+	// Fields limits the task fields of structured output and markdown
+	// tables. Empty includes every field.
+	Fields []string
+	// GroupBy names what Groups are grouped by. Empty lists Tasks without
+	// groups.
+	GroupBy string
+	Groups  []TaskGroup
+}
+
+// templateDefaults are the optional task fields, so that templates can use
+// them on every task.
+var templateDefaults = map[string]any{
+	"assignees":          []string{},
+	"board":              "",
+	"seq":                0,
+	"epic":               "",
+	"sprint":             "",
+	"due_date":           "",
+	"estimate":           0.0,
+	"archived_at":        "",
+	"archived_by":        "",
+	"checklist_progress": "",
+}
+
+// ParseTaskTemplate parses a Go text/template for task lists. It is executed
+// once per task with the task's JSON fields, e.g. "{{.display_id}} {{.title}}",
+// and may use the functions join, upper, lower, and json.
+func ParseTaskTemplate(text string) (*template.Template, error) {
+	return template.New("format").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"join":  func(v any, sep string) string { return joinValues(v, sep) },
+			"upper": strings.ToUpper,
+			"lower": strings.ToLower,
+			"json": func(v any) (string, error) {
+				data, err := json.Marshal(v)
+				return string(data), err
+			},
+		}).
+		Parse(text)
+}
+
+// TaskList outputs tasks in one of the list formats. Text output without
+// groups is the same as TasksWithBoards.
+func (f *Formatter) TaskList(list TaskList, format string) error {
+	if format == FormatText && list.GroupBy == "" {
+		f.TasksWithBoards(list.Tasks, list.Boards)
+		return nil
+	}
+	return writeTaskList(os.Stdout, list, format)
+}
+
+// TaskListTemplate outputs tasks by executing tmpl for each task, under a
+// heading per group when the list is grouped.
+func (f *Formatter) TaskListTemplate(list TaskList, tmpl *template.Template) error {
+	return writeTaskTemplate(os.Stdout, list, tmpl)
+}
+
+func writeTaskList(w io.Writer, list TaskList, format string) error {
+	switch format {
+	case FormatText:
+		for _, g := range list.Groups {
+			fmt.Fprintf(w, "\n%s (%d)\n", g.Name, len(g.Tasks))
+			if len(g.Tasks) == 0 {
+				fmt.Fprintln(w, "  (empty)")
+			}
+			for _, task := range g.Tasks {
+				fprintTaskLineWithBoard(w, task, list.Boards)
+			}
+		}
+		fmt.Fprintln(w)
+		return nil
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(listDocument(list))
+	case FormatNDJSON:
+		return writeNDJSON(w, list)
+	case FormatYAML:
+		// Round-trip through JSON so the keys match the JSON output
+		data, err := json.Marshal(listDocument(list))
+		if err != nil {
+			return err
+		}
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		return enc.Close()
+	case FormatMarkdown:
+		return writeMarkdown(w, list)
+	}
+	return fmt.Errorf("unknown list format: %s", format)
+}
+
+// listDocument returns the JSON/YAML document of a task list: the tasks and
+// their count, or the groups with their counts.
+func listDocument(list TaskList) map[string]any {
+	if list.GroupBy == "" {
+		return map[string]any{
+			"tasks": taskMaps(list.Tasks, list),
+			"count": len(list.Tasks),
+		}
+	}
+
+	groups := make([]map[string]any, len(list.Groups))
+	for i, g := range list.Groups {
+		groups[i] = map[string]any{
+			"key":   g.Key,
+			"name":  g.Name,
+			"count": len(g.Tasks),
+			"tasks": taskMaps(g.Tasks, list),
+		}
+	}
+	return map[string]any{
+		"group_by": list.GroupBy,
+		"groups":   groups,
+		"count":    len(list.Tasks),
+	}
+}
+
+// writeNDJSON writes one task per line. In a grouped list each task carries
+// its group, and tasks in several groups (labels, assignees) appear once per
+// group.
+func writeNDJSON(w io.Writer, list TaskList) error {
+	enc := json.NewEncoder(w)
+	if list.GroupBy == "" {
+		for _, m := range taskMaps(list.Tasks, list) {
+			if err := enc.Encode(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, g := range list.Groups {
+		for _, m := range taskMaps(g.Tasks, list) {
+			m["group"] = map[string]any{"by": list.GroupBy, "key": g.Key, "name": g.Name}
+			if err := enc.Encode(m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// markdownColumns are the fields and headings of markdown tables when no
+// fields are selected.
+var markdownColumns = [][2]string{
+	{"display_id", "ID"},
+	{"title", "Title"},
+	{"column", "Column"},
+	{"type", "Type"},
+	{"priority", "Priority"},
+	{"labels", "Labels"},
+	{"assignees", "Assignees"},
+	{"due_date", "Due"},
+}
+
+func writeMarkdown(w io.Writer, list TaskList) error {
+	columns := markdownColumns
+	if len(list.Fields) > 0 {
+		columns = nil
+		for _, field := range list.Fields {
+			field = strings.TrimSpace(field)
+			columns = append(columns, [2]string{field, field})
+		}
+	}
+
+	table := func(tasks []*core.Record) {
+		if len(tasks) == 0 {
+			fmt.Fprintln(w, "_No tasks._")
+			return
+		}
+		headings := make([]string, len(columns))
+		rules := make([]string, len(columns))
+		for i, c := range columns {
+			headings[i] = c[1]
+			rules[i] = "---"
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(headings, " | "))
+		fmt.Fprintf(w, "| %s |\n", strings.Join(rules, " | "))
+		for _, task := range tasks {
+			m := taskToMapWithBoard(task, list.Boards)
+			cells := make([]string, len(columns))
+			for i, c := range columns {
+				cells[i] = markdownCell(m[c[0]])
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+
+	if list.GroupBy == "" {
+		table(list.Tasks)
+		return nil
+	}
+	for i, g := range list.Groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "## %s (%d)\n\n", markdownEscape(g.Name), len(g.Tasks))
+		table(g.Tasks)
+	}
+	return nil
+}
+
+func writeTaskTemplate(w io.Writer, list TaskList, tmpl *template.Template) error {
+	execute := func(tasks []*core.Record) error {
+		for _, task := range tasks {
+			data := taskToMapWithBoard(task, list.Boards)
+			for k, v := range templateDefaults {
+				if _, ok := data[k]; !ok {
+					data[k] = v
+				}
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, data); err != nil {
+				return err
+			}
+			if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+				buf.WriteByte('\n')
+			}
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if list.GroupBy == "" {
+		return execute(list.Tasks)
+	}
+	for i, g := range list.Groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d)\n", g.Name, len(g.Tasks))
+		if err := execute(g.Tasks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskMaps returns the JSON maps of tasks with the list's field selection.
+func taskMaps(tasks []*core.Record, list TaskList) []map[string]any {
+	result := make([]map[string]any, len(tasks))
+	for i, task := range tasks {
+		result[i] = selectFields(taskToMapWithBoard(task, list.Boards), list.Fields)
+	}
+	return result
+}
+
+// markdownCell formats a task field for a markdown table cell.
+func markdownCell(v any) string {
+	return markdownEscape(joinValues(v, ", "))
+}
+
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r", "")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// joinValues formats a task field as text, joining lists with sep.
+func joinValues(v any, sep string) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []string:
+		return strings.Join(val, sep)
+	case []any:
+		parts := make([]string, len(val))
+		for i, item := range val {
+			parts[i] = joinValues(item, sep)
+		}
+		return strings.Join(parts, sep)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case int, bool:
+		return fmt.Sprint(val)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,144 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// listTasks returns two tasks on a board with prefix WRK.
+func listTasks(t *testing.T) ([]*core.Record, map[string]*core.Record) {
+	t.Helper()
+	boards := core.NewBaseCollection("boards")
+	boards.Fields.Add(&core.TextField{Name: "prefix"})
+	b := core.NewRecord(boards)
+	b.Id = "board1"
+	b.Set("prefix", "WRK")
+
+	tasks := core.NewBaseCollection("tasks")
+	tasks.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "type"},
+		&core.TextField{Name: "priority"},
+		&core.TextField{Name: "column"},
+		&core.TextField{Name: "board"},
+		&core.NumberField{Name: "seq"},
+		&core.JSONField{Name: "labels"},
+		&core.DateField{Name: "due_date"},
+	)
+	newTask := func(id, title string, seq int, labels []string, due string) *core.Record {
+		r := core.NewRecord(tasks)
+		r.Id = id
+		r.Set("title", title)
+		r.Set("type", "bug")
+		r.Set("priority", "high")
+		r.Set("column", "todo")
+		r.Set("board", b.Id)
+		r.Set("seq", seq)
+		r.Set("labels", labels)
+		r.Set("due_date", due)
+		return r
+	}
+	return []*core.Record{
+		newTask("task1", "Crash | on save", 1, []string{"api", "ui"}, "2026-03-10"),
+		newTask("task2", "Login", 2, nil, ""),
+	}, map[string]*core.Record{b.Id: b}
+}
+
+func TestWriteTaskList_Formats(t *testing.T) {
+	tasks, boards := listTasks(t)
+	list := TaskList{Tasks: tasks, Boards: boards, Fields: []string{"display_id", "labels"}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTaskList(&buf, list, FormatNDJSON))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"display_id":"WRK-1","labels":["api","ui"]}`, lines[0])
+
+	buf.Reset()
+	require.NoError(t, writeTaskList(&buf, list, FormatYAML))
+	var doc struct {
+		Count int              `yaml:"count"`
+		Tasks []map[string]any `yaml:"tasks"`
+	}
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, 2, doc.Count)
+	assert.Equal(t, "WRK-2", doc.Tasks[1]["display_id"])
+
+	buf.Reset()
+	list.Fields = nil
+	require.NoError(t, writeTaskList(&buf, list, FormatMarkdown))
+	assert.Equal(t, `| ID | Title | Column | Type | Priority | Labels | Assignees | Due |
+| --- | --- | --- | --- | --- | --- | --- | --- |
+| WRK-1 | Crash \| on save | todo | bug | high | api, ui |  | 2026-03-10 |
+| WRK-2 | Login | todo | bug | high |  |  |  |
+`, buf.String())
+
+	assert.Error(t, writeTaskList(&buf, list, "xml"))
+}
+
+func TestWriteTaskList_Groups(t *testing.T) {
+	tasks, boards := listTasks(t)
+	list := TaskList{
+		Tasks:   tasks,
+		Boards:  boards,
+		Fields:  []string{"display_id"},
+		GroupBy: "label",
+		Groups: []TaskGroup{
+			{Key: "api", Name: "api", Tasks: tasks[:1]},
+			{Key: "", Name: "(no label)", Tasks: tasks[1:]},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTaskList(&buf, list, FormatJSON))
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "label", doc["group_by"])
+	assert.Equal(t, float64(2), doc["count"])
+	groups := doc["groups"].([]any)
+	require.Len(t, groups, 2)
+	first := groups[0].(map[string]any)
+	assert.Equal(t, "api", first["name"])
+	assert.Equal(t, float64(1), first["count"])
+
+	buf.Reset()
+	require.NoError(t, writeTaskList(&buf, list, FormatNDJSON))
+	assert.Contains(t, buf.String(), `"group":{"by":"label","key":"","name":"(no label)"}`)
+
+	buf.Reset()
+	require.NoError(t, writeTaskList(&buf, list, FormatText))
+	assert.Contains(t, buf.String(), "\napi (1)\n  [WRK-1] Crash | on save (bug, !!high)\n")
+}
+
+func TestTaskTemplate(t *testing.T) {
+	tasks, boards := listTasks(t)
+	list := TaskList{Tasks: tasks, Boards: boards}
+
+	tmpl, err := ParseTaskTemplate(`{{.display_id}} {{upper .priority}} [{{join .labels ","}}] {{.due_date}}`)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, writeTaskTemplate(&buf, list, tmpl))
+	assert.Equal(t, "WRK-1 HIGH [api,ui] 2026-03-10\nWRK-2 HIGH [] \n", buf.String())
+
+	// Unknown fields are errors rather than "<no value>"
+	tmpl, err = ParseTaskTemplate(`{{.titel}}`)
+	require.NoError(t, err)
+	assert.Error(t, writeTaskTemplate(&buf, list, tmpl))
+
+	_, err = ParseTaskTemplate(`{{.title`)
+	assert.Error(t, err)
+}
+
+func TestNormalizeListFormat(t *testing.T) {
+	assert.Equal(t, FormatMarkdown, NormalizeListFormat("md"))
+	assert.Equal(t, FormatYAML, NormalizeListFormat("YML"))
+	assert.Equal(t, FormatNDJSON, NormalizeListFormat("ndjson"))
+	assert.Equal(t, "", NormalizeListFormat("{{.title}}"))
+}
@@ -0,0 +1,297 @@
+// Package policy enforces agent modes on task mutations.
+//
+// The agent mode (autonomous, collaborative, supervised) is set per board,
+// falling back to agent.mode in the config. Humans are never restricted.
+// Agents are restricted by mode:
+//
+//   - autonomous: everything is allowed
+//   - collaborative: deleting and completing tasks is denied; completing
+//     is downgraded to a move to review when the board has that column
+//   - supervised: read-only, every mutation is denied
+//
+// Agents restricted by their mode can file proposals instead, which only
+// humans may approve or reject. Whatever the mode, agents cannot change
+// agent modes or API access.
+//
+// The same rules are evaluated in the CLI command paths and in PocketBase
+// request hooks, so they apply to the CLI, the TUI, and the HTTP API.
+package policy
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+)
+
+// Agent modes.
+const (
+	ModeAutonomous    = "autonomous"
+	ModeCollaborative = "collaborative"
+	ModeSupervised    = "supervised"
+)
+
+// Action is a mutation subject to policy.
+type Action string
+
+// Actions evaluated by the policy.
+const (
+	ActionCreate   Action = "create"
+	ActionUpdate   Action = "update"
+	ActionMove     Action = "move"
+	ActionComplete Action = "complete"
+	ActionDelete   Action = "delete"
+	ActionComment  Action = "comment"
+	ActionReview   Action = "review"   // Approve or reject a proposal
+	ActionSetMode  Action = "set_mode" // Change agent modes or API access
+)
+
+// Error codes returned with policy violations.
+const (
+	CodeReadOnly       = "policy_read_only"
+	CodeDeleteDenied   = "policy_delete_denied"
+	CodeCompleteDenied = "policy_complete_denied"
+	CodeReviewDenied   = "policy_review_denied"
+	CodeSetModeDenied  = "policy_set_mode_denied"
+)
+
+// Columns involved in completion.
+const (
+	DoneColumn   = "done"
+	ReviewColumn = "review"
+)
+
+// Actor types.
+const (
+	ActorUser  = "user"
+	ActorAgent = "agent"
+)
+
+// Actor identifies who performs a mutation.
+type Actor struct {
+	Type string // ActorUser or ActorAgent
+	Name string
+}
+
+// IsAgent reports whether the actor is an agent.
+func (a Actor) IsAgent() bool {
+	return a.Type == ActorAgent
+}
+
+// Agent returns an agent actor with the given name.
+func Agent(name string) Actor {
+	return Actor{Type: ActorAgent, Name: name}
+}
+
+// User returns a human actor with the given name.
+func User(name string) Actor {
+	return Actor{Type: ActorUser, Name: name}
+}
+
+// Decision is the outcome of evaluating an action.
+type Decision struct {
+	Allowed bool
+	Code    string // Error code when not allowed
+	Mode    string
+	Action  Action
+	Actor   Actor
+
+	// DowngradeTo is the column a denied completion may move to instead.
+	DowngradeTo string
+}
+
+// Downgraded reports whether the action may proceed in a reduced form.
+func (d Decision) Downgraded() bool {
+	return !d.Allowed && d.DowngradeTo != ""
+}
+
+// Err returns the violation for a denied decision, or nil when allowed.
+func (d Decision) Err() error {
+	if d.Allowed {
+		return nil
+	}
+	return &Violation{Decision: d}
+}
+
+// Violation is returned when policy denies an action.
+type Violation struct {
+	Decision
+}
+
+func (v *Violation) Error() string {
+	name := v.Actor.Name
+	if name == "" {
+		name = "agent"
+	}
+	switch v.Code {
+	case CodeReadOnly:
+		return fmt.Sprintf("%s is in supervised mode and cannot %s (read-only)", name, v.Action)
+	case CodeDeleteDenied:
+		return fmt.Sprintf("%s is in %s mode and cannot delete tasks", name, v.Mode)
+	case CodeCompleteDenied:
+		return fmt.Sprintf("%s is in %s mode and cannot complete tasks", name, v.Mode)
+	case CodeReviewDenied:
+		return fmt.Sprintf("%s is an agent and cannot review proposals", name)
+	case CodeSetModeDenied:
+		return fmt.Sprintf("%s is an agent and cannot change agent modes or API access", name)
+	default:
+		return fmt.Sprintf("%s cannot %s in %s mode", name, v.Action, v.Mode)
+	}
+}
+
+// Data returns the violation details for error output.
+func (v *Violation) Data() map[string]any {
+	return map[string]any{
+		"code":   v.Code,
+		"mode":   v.Mode,
+		"action": string(v.Action),
+		"agent":  v.Actor.Name,
+	}
+}
+
+// Evaluate decides whether actor may perform action in the given mode.
+func Evaluate(mode string, actor Actor, action Action) Decision {
+	mode = NormalizeMode(mode)
+	d := Decision{Allowed: true, Mode: mode, Action: action, Actor: actor}
+	if !actor.IsAgent() {
+		return d
+	}
+
+	// Proposals exist so that a human decides, whatever the mode
+	if action == ActionReview {
+		d.Allowed = false
+		d.Code = CodeReviewDenied
+		return d
+	}
+
+	// Agents cannot lift the restrictions that apply to them
+	if action == ActionSetMode {
+		d.Allowed = false
+		d.Code = CodeSetModeDenied
+		return d
+	}
+
+	switch mode {
+	case ModeSupervised:
+		d.Allowed = false
+		d.Code = CodeReadOnly
+	case ModeCollaborative:
+		switch action {
+		case ActionDelete:
+			d.Allowed = false
+			d.Code = CodeDeleteDenied
+		case ActionComplete:
+			d.Allowed = false
+			d.Code = CodeCompleteDenied
+			d.DowngradeTo = ReviewColumn
+		}
+	}
+	return d
+}
+
+// MoveAction classifies a column change as a completion or a plain move.
+func MoveAction(fromColumn, toColumn string) Action {
+	if toColumn == DoneColumn && fromColumn != DoneColumn {
+		return ActionComplete
+	}
+	if fromColumn != toColumn {
+		return ActionMove
+	}
+	return ActionUpdate
+}
+
+// NormalizeMode returns mode, or autonomous when it is empty or unknown.
+func NormalizeMode(mode string) string {
+	if slices.Contains(config.ValidModes, mode) {
+		return mode
+	}
+	return ModeAutonomous
+}
+
+// EffectiveMode returns the agent mode for a board: the board's agent_mode,
+// falling back to agent.mode from the merged config.
+func EffectiveMode(app core.App, boardID string) string {
+	if boardID != "" {
+		if record, err := app.FindRecordById("boards", boardID); err == nil {
+			if mode := record.GetString("agent_mode"); mode != "" {
+				return NormalizeMode(mode)
+			}
+		}
+	}
+	if cfg, err := config.Load(); err == nil {
+		return NormalizeMode(cfg.Agent.Mode)
+	}
+	return ModeAutonomous
+}
+
+// BoardHasColumn reports whether a board has the given column.
+// Boards without configured columns use the defaults, which include
+// both review and done.
+func BoardHasColumn(app core.App, boardID, column string) bool {
+	record, err := app.FindRecordById("boards", boardID)
+	if err != nil {
+		return false
+	}
+	columns := record.GetStringSlice("columns")
+	if len(columns) == 0 {
+		return true
+	}
+	return slices.Contains(columns, column)
+}
+
+// Check evaluates an action on a board, resolving the board's mode.
+// For a denied completion that can be downgraded, the returned decision
+// has DowngradeTo set only if the board has that column.
+func Check(app core.App, actor Actor, boardID string, action Action) Decision {
+	d := Evaluate(EffectiveMode(app, boardID), actor, action)
+	if d.DowngradeTo != "" && !BoardHasColumn(app, boardID, d.DowngradeTo) {
+		d.DowngradeTo = ""
+	}
+	return d
+}
+
+// CheckBoard evaluates an action on an already loaded board record, for
+// clients without a database. It decides like Check; a nil board uses
+// agent.mode from the merged config and never downgrades.
+func CheckBoard(board *core.Record, actor Actor, action Action) Decision {
+	mode := ""
+	if board != nil {
+		mode = board.GetString("agent_mode")
+	}
+	if mode == "" {
+		mode = ModeAutonomous
+		if cfg, err := config.Load(); err == nil {
+			mode = cfg.Agent.Mode
+		}
+	}
+
+	d := Evaluate(mode, actor, action)
+	if d.DowngradeTo != "" {
+		if board == nil {
+			d.DowngradeTo = ""
+		} else if columns := board.GetStringSlice("columns"); len(columns) > 0 && !slices.Contains(columns, d.DowngradeTo) {
+			d.DowngradeTo = ""
+		}
+	}
+	return d
+}
+
+// CheckTask evaluates an action on a task's board.
+func CheckTask(app core.App, actor Actor, task *core.Record, action Action) Decision {
+	return Check(app, actor, task.GetString("board"), action)
+}
+
+// ActorFromAuth returns the actor for an authenticated record.
+// Token accounts issued to an agent (users.agent) act as that agent;
+// everything else, including unauthenticated requests, is a user.
+func ActorFromAuth(record *core.Record) Actor {
+	if record == nil {
+		return User("")
+	}
+	if agent := record.GetString("agent"); agent != "" {
+		return Agent(agent)
+	}
+	return User(record.GetString("name"))
+}
@@ -0,0 +1,167 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+// setupBoards creates a boards collection with an agent_mode field.
+func setupBoards(t *testing.T, app *pocketbase.PocketBase) *core.Collection {
+	t.Helper()
+
+	return testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.JSONField{Name: "columns"},
+		&core.TextField{Name: "agent_mode"},
+	)
+}
+
+func createBoard(t *testing.T, app *pocketbase.PocketBase, boards *core.Collection, mode string, columns []string) *core.Record {
+	t.Helper()
+
+	record := core.NewRecord(boards)
+	record.Set("name", "Work")
+	record.Set("agent_mode", mode)
+	if columns != nil {
+		record.Set("columns", columns)
+	}
+	require.NoError(t, app.Save(record))
+	return record
+}
+
+func TestEvaluate(t *testing.T) {
+	agent := Agent("claude")
+	human := User("ramtin")
+
+	tests := []struct {
+		name      string
+		mode      string
+		actor     Actor
+		action    Action
+		allowed   bool
+		code      string
+		downgrade string
+	}{
+		{"human in supervised", ModeSupervised, human, ActionDelete, true, "", ""},
+		{"autonomous delete", ModeAutonomous, agent, ActionDelete, true, "", ""},
+		{"autonomous complete", ModeAutonomous, agent, ActionComplete, true, "", ""},
+		{"collaborative create", ModeCollaborative, agent, ActionCreate, true, "", ""},
+		{"collaborative move", ModeCollaborative, agent, ActionMove, true, "", ""},
+		{"collaborative comment", ModeCollaborative, agent, ActionComment, true, "", ""},
+		{"collaborative delete", ModeCollaborative, agent, ActionDelete, false, CodeDeleteDenied, ""},
+		{"collaborative complete", ModeCollaborative, agent, ActionComplete, false, CodeCompleteDenied, ReviewColumn},
+		{"supervised create", ModeSupervised, agent, ActionCreate, false, CodeReadOnly, ""},
+		{"supervised comment", ModeSupervised, agent, ActionComment, false, CodeReadOnly, ""},
+		{"unknown mode is autonomous", "yolo", agent, ActionDelete, true, "", ""},
+		{"autonomous review", ModeAutonomous, agent, ActionReview, false, CodeReviewDenied, ""},
+		{"human review", ModeSupervised, human, ActionReview, true, "", ""},
+		{"autonomous set mode", ModeAutonomous, agent, ActionSetMode, false, CodeSetModeDenied, ""},
+		{"human set mode", ModeSupervised, human, ActionSetMode, true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Evaluate(tt.mode, tt.actor, tt.action)
+			assert.Equal(t, tt.allowed, d.Allowed)
+			assert.Equal(t, tt.code, d.Code)
+			assert.Equal(t, tt.downgrade, d.DowngradeTo)
+		})
+	}
+}
+
+func TestMoveAction(t *testing.T) {
+	assert.Equal(t, ActionComplete, MoveAction("review", "done"))
+	assert.Equal(t, ActionComplete, MoveAction("", "done"))
+	assert.Equal(t, ActionMove, MoveAction("todo", "in_progress"))
+	assert.Equal(t, ActionMove, MoveAction("done", "todo"))
+	assert.Equal(t, ActionUpdate, MoveAction("done", "done"))
+}
+
+func TestViolation(t *testing.T) {
+	err := Evaluate(ModeCollaborative, Agent("claude"), ActionDelete).Err()
+	require.Error(t, err)
+
+	var v *Violation
+	require.True(t, errors.As(err, &v))
+	assert.Equal(t, "claude is in collaborative mode and cannot delete tasks", v.Error())
+	assert.Equal(t, map[string]any{
+		"code":   CodeDeleteDenied,
+		"mode":   ModeCollaborative,
+		"action": "delete",
+		"agent":  "claude",
+	}, v.Data())
+
+	assert.NoError(t, Evaluate(ModeAutonomous, Agent("claude"), ActionDelete).Err())
+}
+
+func TestCheck_UsesBoardMode(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	boards := setupBoards(t, app)
+	supervised := createBoard(t, app, boards, ModeSupervised, nil)
+	collaborative := createBoard(t, app, boards, ModeCollaborative, nil)
+
+	assert.Equal(t, ModeSupervised, EffectiveMode(app, supervised.Id))
+	assert.Equal(t, ModeCollaborative, EffectiveMode(app, collaborative.Id))
+
+	d := Check(app, Agent("claude"), supervised.Id, ActionMove)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, CodeReadOnly, d.Code)
+
+	d = Check(app, Agent("claude"), collaborative.Id, ActionComplete)
+	assert.True(t, d.Downgraded())
+	assert.Equal(t, ReviewColumn, d.DowngradeTo)
+}
+
+func TestCheck_NoDowngradeWithoutReviewColumn(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	boards := setupBoards(t, app)
+	board := createBoard(t, app, boards, ModeCollaborative, []string{"todo", "done"})
+
+	d := Check(app, Agent("claude"), board.Id, ActionComplete)
+	assert.False(t, d.Allowed)
+	assert.False(t, d.Downgraded())
+	assert.Equal(t, CodeCompleteDenied, d.Code)
+}
+
+func TestCheckBoard(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	boards := setupBoards(t, app)
+	collaborative := createBoard(t, app, boards, ModeCollaborative, nil)
+	noReview := createBoard(t, app, boards, ModeCollaborative, []string{"todo", "done"})
+	supervised := createBoard(t, app, boards, ModeSupervised, nil)
+
+	// Same decisions as Check, without a database
+	for _, board := range []*core.Record{collaborative, noReview, supervised} {
+		for _, action := range []Action{ActionMove, ActionComplete, ActionDelete} {
+			assert.Equal(t, Check(app, Agent("claude"), board.Id, action),
+				CheckBoard(board, Agent("claude"), action))
+		}
+	}
+
+	assert.True(t, CheckBoard(supervised, User("ramtin"), ActionDelete).Allowed)
+	assert.False(t, CheckBoard(nil, Agent("claude"), ActionComplete).Downgraded())
+}
+
+func TestActorFromAuth(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	users, err := app.FindCollectionByNameOrId("users")
+	require.NoError(t, err)
+	users.Fields.Add(&core.TextField{Name: "agent"})
+	require.NoError(t, app.Save(users))
+
+	record := core.NewRecord(users)
+	record.Set("name", "ci")
+	assert.Equal(t, User("ci"), ActorFromAuth(record))
+
+	record.Set("agent", "claude")
+	assert.Equal(t, Agent("claude"), ActorFromAuth(record))
+
+	assert.False(t, ActorFromAuth(nil).IsAgent())
+}
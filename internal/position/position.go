@@ -19,23 +19,11 @@ const (
 // If the column is empty, returns DefaultGap.
 // Otherwise, returns the last position + DefaultGap.
 func GetNext(app *pocketbase.PocketBase, column string) float64 {
-	tasks, err := app.FindAllRecords("tasks",
-		dbx.NewExp("column = {:col}", dbx.Params{"col": column}),
-	)
-	if err != nil || len(tasks) == 0 {
+	tasks, err := columnTasks(app, column)
+	if err != nil {
 		return DefaultGap
 	}
-
-	// Find the maximum position
-	var maxPos float64
-	for _, task := range tasks {
-		pos := task.GetFloat("position")
-		if pos > maxPos {
-			maxPos = pos
-		}
-	}
-
-	return maxPos + DefaultGap
+	return NextIn(tasks)
 }
 
 // GetBetween calculates a position between two existing positions.
@@ -48,30 +36,11 @@ func GetBetween(before, after float64) float64 {
 // index 0 = top of column
 // index -1 = bottom of column (same as GetNext)
 func GetAtIndex(app *pocketbase.PocketBase, column string, index int) float64 {
-	tasks, err := app.FindAllRecords("tasks",
-		dbx.NewExp("column = {:col}", dbx.Params{"col": column}),
-	)
-	if err != nil || len(tasks) == 0 {
+	tasks, err := columnTasks(app, column)
+	if err != nil {
 		return DefaultGap
 	}
-
-	// Sort tasks by position
-	SortByPosition(tasks)
-
-	// Handle bottom of column
-	if index < 0 || index >= len(tasks) {
-		return tasks[len(tasks)-1].GetFloat("position") + DefaultGap
-	}
-
-	// Handle top of column
-	if index == 0 {
-		return tasks[0].GetFloat("position") / 2.0
-	}
-
-	// Insert between two tasks
-	before := tasks[index-1].GetFloat("position")
-	after := tasks[index].GetFloat("position")
-	return GetBetween(before, after)
+	return AtIndexIn(tasks, index)
 }
 
 // GetAfter returns a position after a specific task.
@@ -80,68 +49,114 @@ func GetAfter(app *pocketbase.PocketBase, taskID string) (float64, error) {
 	if err != nil {
 		return 0, err
 	}
+	tasks, err := columnTasks(app, task.GetString("column"))
+	if err != nil {
+		return 0, err
+	}
+	return AfterIn(tasks, task), nil
+}
 
-	column := task.GetString("column")
-	targetPos := task.GetFloat("position")
-
-	// Find the next task in the column
-	tasks, err := app.FindAllRecords("tasks",
-		dbx.NewExp("column = {:col} AND position > {:pos}",
-			dbx.Params{"col": column, "pos": targetPos}),
-	)
+// GetBefore returns a position before a specific task.
+func GetBefore(app *pocketbase.PocketBase, taskID string) (float64, error) {
+	task, err := app.FindRecordById("tasks", taskID)
+	if err != nil {
+		return 0, err
+	}
+	tasks, err := columnTasks(app, task.GetString("column"))
 	if err != nil {
 		return 0, err
 	}
+	return BeforeIn(tasks, task), nil
+}
+
+// The *In functions compute positions from the tasks of a column that
+// were already loaded, for clients without a database.
 
+// NextIn returns the position after the last of the tasks of a column.
+func NextIn(tasks []*core.Record) float64 {
 	if len(tasks) == 0 {
-		// No task after, append to end
-		return targetPos + DefaultGap, nil
+		return DefaultGap
 	}
 
-	// Find minimum position among tasks after
-	minPos := tasks[0].GetFloat("position")
-	for _, t := range tasks {
-		if pos := t.GetFloat("position"); pos < minPos {
-			minPos = pos
+	// Find the maximum position
+	var maxPos float64
+	for _, task := range tasks {
+		pos := task.GetFloat("position")
+		if pos > maxPos {
+			maxPos = pos
 		}
 	}
 
-	return GetBetween(targetPos, minPos), nil
+	return maxPos + DefaultGap
 }
 
-// GetBefore returns a position before a specific task.
-func GetBefore(app *pocketbase.PocketBase, taskID string) (float64, error) {
-	task, err := app.FindRecordById("tasks", taskID)
-	if err != nil {
-		return 0, err
+// AtIndexIn returns the position for a task at an index among the tasks
+// of a column, like GetAtIndex.
+func AtIndexIn(tasks []*core.Record, index int) float64 {
+	if len(tasks) == 0 {
+		return DefaultGap
 	}
 
-	column := task.GetString("column")
-	targetPos := task.GetFloat("position")
+	// Sort tasks by position
+	sorted := append([]*core.Record(nil), tasks...)
+	SortByPosition(sorted)
 
-	// Find the previous task in the column
-	tasks, err := app.FindAllRecords("tasks",
-		dbx.NewExp("column = {:col} AND position < {:pos}",
-			dbx.Params{"col": column, "pos": targetPos}),
-	)
-	if err != nil {
-		return 0, err
+	// Handle bottom of column
+	if index < 0 || index >= len(sorted) {
+		return sorted[len(sorted)-1].GetFloat("position") + DefaultGap
 	}
 
-	if len(tasks) == 0 {
-		// No task before, put at top
-		return targetPos / 2.0, nil
+	// Handle top of column
+	if index == 0 {
+		return sorted[0].GetFloat("position") / 2.0
 	}
 
-	// Find maximum position among tasks before
-	maxPos := tasks[0].GetFloat("position")
+	// Insert between two tasks
+	before := sorted[index-1].GetFloat("position")
+	after := sorted[index].GetFloat("position")
+	return GetBetween(before, after)
+}
+
+// AfterIn returns a position right after target among the tasks of its
+// column.
+func AfterIn(tasks []*core.Record, target *core.Record) float64 {
+	targetPos := target.GetFloat("position")
+
+	// Find the next task in the column
+	found := false
+	var minPos float64
 	for _, t := range tasks {
-		if pos := t.GetFloat("position"); pos > maxPos {
-			maxPos = pos
+		if pos := t.GetFloat("position"); pos > targetPos && (!found || pos < minPos) {
+			minPos, found = pos, true
 		}
 	}
+	if !found {
+		// No task after, append to end
+		return targetPos + DefaultGap
+	}
 
-	return GetBetween(maxPos, targetPos), nil
+	return GetBetween(targetPos, minPos)
+}
+
+// BeforeIn returns a position right before target among the tasks of its
+// column.
+func BeforeIn(tasks []*core.Record, target *core.Record) float64 {
+	targetPos := target.GetFloat("position")
+
+	// Find the previous task in the column
+	found := false
+	var maxPos float64
+	for _, t := range tasks {
+		if pos := t.GetFloat("position"); pos < targetPos && (!found || pos > maxPos) {
+			maxPos, found = pos, true
+		}
+	}
+	if !found {
+		// No task before, put at top
+		return targetPos / 2.0
+	}
+
+	return GetBetween(maxPos, targetPos)
 }
 
 // SortByPosition sorts tasks by their position field in ascending order.
@@ -150,3 +165,10 @@ func SortByPosition(tasks []*core.Record) {
 		return tasks[i].GetFloat("position") < tasks[j].GetFloat("position")
 	})
 }
+
+// columnTasks returns the tasks in a column.
+func columnTasks(app *pocketbase.PocketBase, column string) ([]*core.Record, error) {
+	return app.FindAllRecords("tasks",
+		dbx.NewExp("column = {:col}", dbx.Params{"col": column}),
+	)
+}
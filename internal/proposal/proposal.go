@@ -0,0 +1,377 @@
+// Package proposal stores changes agents ask humans to approve.
+//
+// In collaborative and supervised mode an agent files a proposal instead
+// of mutating a task directly. A proposal holds the intended change (a new
+// task, an update, a move, or a delete), a rationale, and the proposing
+// agent. Approving a proposal applies the change and marks the proposal in
+// a single transaction; the task event log records both the agent and the
+// reviewer.
+package proposal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/position"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// CollectionName is the PocketBase collection holding proposals.
+const CollectionName = "proposals"
+
+// Proposal states.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Proposed actions.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionMove   = "move"
+	ActionDelete = "delete"
+)
+
+// ValidStatuses lists the accepted proposal states.
+var ValidStatuses = []string{StatusPending, StatusApproved, StatusRejected}
+
+var (
+	// ErrNotFound is returned when no proposal matches a reference.
+	ErrNotFound = errors.New("proposal not found")
+	// ErrAmbiguous is returned when a reference matches several proposals.
+	ErrAmbiguous = errors.New("ambiguous proposal reference")
+	// ErrNotPending is returned when reviewing a proposal twice.
+	ErrNotPending = errors.New("proposal is not pending")
+	// ErrTaskGone is returned when the target task no longer exists.
+	ErrTaskGone = errors.New("task no longer exists")
+)
+
+// Change is the mutation a proposal applies.
+//
+// Set holds field values to assign (title, description, type, priority,
+// estimate, due_date, epic, parent, labels, assignees). List fields on an
+// existing task are changed with the Add/Remove lists instead, so that
+// approval merges with edits made in the meantime.
+type Change struct {
+	Action string         `json:"action"`
+	Task   string         `json:"task,omitempty"`   // Target task ID (update, move, delete)
+	Column string         `json:"column,omitempty"` // Target column (create, move)
+	Set    map[string]any `json:"set,omitempty"`
+
+	AddLabels       []string `json:"add_labels,omitempty"`
+	RemoveLabels    []string `json:"remove_labels,omitempty"`
+	Assign          []string `json:"assign,omitempty"`
+	Unassign        []string `json:"unassign,omitempty"`
+	AddBlockedBy    []string `json:"add_blocked_by,omitempty"`
+	RemoveBlockedBy []string `json:"remove_blocked_by,omitempty"`
+}
+
+// Input describes a new proposal.
+type Input struct {
+	BoardID    string
+	Change     Change
+	Command    string // Command line as typed by the agent
+	Summary    string // Human-readable description of the change
+	Rationale  string
+	ProposedBy string
+}
+
+// Create stores a pending proposal.
+func Create(app core.App, in Input) (*core.Record, error) {
+	collection, err := app.FindCollectionByNameOrId(CollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("proposals collection not found: %w", err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("board", in.BoardID)
+	record.Set("task", in.Change.Task)
+	record.Set("action", in.Change.Action)
+	record.Set("changes", in.Change)
+	record.Set("command", in.Command)
+	record.Set("summary", in.Summary)
+	record.Set("rationale", in.Rationale)
+	record.Set("status", StatusPending)
+	record.Set("proposed_by", in.ProposedBy)
+
+	if err := app.Save(record); err != nil {
+		return nil, fmt.Errorf("failed to save proposal: %w", err)
+	}
+	return record, nil
+}
+
+// List returns proposals, newest first. Empty boardID or status match all.
+func List(app core.App, boardID, status string) ([]*core.Record, error) {
+	var conditions []string
+	params := dbx.Params{}
+	if boardID != "" {
+		conditions = append(conditions, "board = {:board}")
+		params["board"] = boardID
+	}
+	if status != "" {
+		conditions = append(conditions, "status = {:status}")
+		params["status"] = status
+	}
+
+	var records []*core.Record
+	var err error
+	if len(conditions) > 0 {
+		records, err = app.FindAllRecords(CollectionName,
+			dbx.NewExp(strings.Join(conditions, " AND "), params))
+	} else {
+		records, err = app.FindAllRecords(CollectionName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].GetDateTime("created").Time().After(records[j].GetDateTime("created").Time())
+	})
+	return records, nil
+}
+
+// Find returns the proposal with the given ID or unique ID prefix.
+func Find(app core.App, ref string) (*core.Record, error) {
+	if record, err := app.FindRecordById(CollectionName, ref); err == nil {
+		return record, nil
+	}
+
+	records, err := app.FindAllRecords(CollectionName,
+		dbx.NewExp("id LIKE {:prefix}", dbx.Params{"prefix": ref + "%"}),
+	)
+	if err != nil || len(records) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+	if len(records) > 1 {
+		return nil, fmt.Errorf("%w: %s matches %d proposals", ErrAmbiguous, ref, len(records))
+	}
+	return records[0], nil
+}
+
+// DecodeChange returns the change stored on a proposal.
+func DecodeChange(record *core.Record) (Change, error) {
+	var change Change
+	if err := record.UnmarshalJSONField("changes", &change); err != nil {
+		return Change{}, fmt.Errorf("invalid proposal changes: %w", err)
+	}
+	return change, nil
+}
+
+// Approve applies a pending proposal and marks it approved in one
+// transaction. It returns the affected task (nil for deletions).
+func Approve(app *pocketbase.PocketBase, record *core.Record, reviewer, note string) (*core.Record, error) {
+	if status := record.GetString("status"); status != StatusPending {
+		return nil, fmt.Errorf("%w (%s)", ErrNotPending, status)
+	}
+
+	change, err := DecodeChange(record)
+	if err != nil {
+		return nil, err
+	}
+
+	attribution := map[string]any{
+		"id":          record.Id,
+		"proposed_by": record.GetString("proposed_by"),
+		"approved_by": reviewer,
+	}
+
+	var task *core.Record
+	switch change.Action {
+	case ActionCreate:
+		task, err = buildNewTask(app, record.GetString("board"), change, reviewer, attribution)
+	case ActionUpdate, ActionMove:
+		task, err = buildUpdatedTask(app, change, reviewer, attribution)
+	case ActionDelete:
+		task, err = app.FindRecordById("tasks", change.Task)
+		if err != nil || trash.IsTrashed(task) {
+			err = ErrTaskGone
+		}
+	default:
+		err = fmt.Errorf("unknown proposal action %q", change.Action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = app.RunInTransaction(func(txApp core.App) error {
+		if err := checkPending(txApp, record.Id); err != nil {
+			return err
+		}
+		markReviewed(record, StatusApproved, reviewer, note)
+
+		if change.Action == ActionDelete {
+			if err := txApp.Save(record); err != nil {
+				return err
+			}
+			return trash.TaskWithMetadata(txApp, task, "user", reviewer, map[string]any{"proposal": attribution})
+		}
+
+		if err := txApp.Save(task); err != nil {
+			return err
+		}
+		record.Set("task", task.Id)
+		return txApp.Save(record)
+	})
+	if errors.Is(err, ErrNotPending) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply proposal: %w", err)
+	}
+
+	if change.Action == ActionDelete {
+		return nil, nil
+	}
+	return task, nil
+}
+
+// Reject marks a pending proposal as rejected without applying it.
+func Reject(app core.App, record *core.Record, reviewer, note string) error {
+	if status := record.GetString("status"); status != StatusPending {
+		return fmt.Errorf("%w (%s)", ErrNotPending, status)
+	}
+	return app.RunInTransaction(func(txApp core.App) error {
+		if err := checkPending(txApp, record.Id); err != nil {
+			return err
+		}
+		markReviewed(record, StatusRejected, reviewer, note)
+		return txApp.Save(record)
+	})
+}
+
+// checkPending re-reads a proposal and fails unless it is still pending,
+// so that concurrent reviews cannot both apply it.
+func checkPending(app core.App, id string) error {
+	current, err := app.FindRecordById(CollectionName, id)
+	if err != nil {
+		return err
+	}
+	if status := current.GetString("status"); status != StatusPending {
+		return fmt.Errorf("%w (%s)", ErrNotPending, status)
+	}
+	return nil
+}
+
+// markReviewed records the review outcome on a proposal.
+func markReviewed(record *core.Record, status, reviewer, note string) {
+	record.Set("status", status)
+	record.Set("reviewed_by", reviewer)
+	record.Set("review_note", note)
+	record.Set("reviewed_at", time.Now().UTC())
+}
+
+// buildNewTask prepares the task a create proposal adds.
+func buildNewTask(app *pocketbase.PocketBase, boardID string, change Change, reviewer string, attribution map[string]any) (*core.Record, error) {
+	collection, err := app.FindCollectionByNameOrId("tasks")
+	if err != nil {
+		return nil, fmt.Errorf("tasks collection not found: %w", err)
+	}
+
+	column := change.Column
+	if column == "" {
+		column = "backlog"
+	}
+
+	task := core.NewRecord(collection)
+	task.Set("type", "feature")
+	task.Set("priority", "medium")
+	task.Set("labels", []string{})
+	task.Set("blocked_by", []string{})
+	for field, value := range change.Set {
+		task.Set(field, value)
+	}
+	task.Set("column", column)
+	task.Set("position", position.GetNext(app, column))
+	task.Set("board", boardID)
+	task.Set("created_by", "agent")
+	task.Set("created_by_agent", attribution["proposed_by"])
+
+	seq, err := board.GetNextSequence(app, boardID)
+	if err != nil {
+		return nil, err
+	}
+	task.Set("seq", seq)
+
+	addEvent(task, "created", reviewer, nil, attribution)
+	return task, nil
+}
+
+// buildUpdatedTask applies an update or move proposal to its task.
+func buildUpdatedTask(app *pocketbase.PocketBase, change Change, reviewer string, attribution map[string]any) (*core.Record, error) {
+	task, err := app.FindRecordById("tasks", change.Task)
+	if err != nil {
+		return nil, ErrTaskGone
+	}
+
+	changes := make(map[string]any)
+	for field, value := range change.Set {
+		changes[field] = map[string]any{"from": task.Get(field), "to": value}
+		task.Set(field, value)
+	}
+
+	mergeField(task, changes, "labels", change.AddLabels, change.RemoveLabels, false)
+	mergeField(task, changes, "assignees", change.Assign, change.Unassign, true)
+	mergeField(task, changes, "blocked_by", change.AddBlockedBy, change.RemoveBlockedBy, false)
+
+	action := "updated"
+	if change.Action == ActionMove {
+		action = "moved"
+		changes["column"] = map[string]any{"from": task.GetString("column"), "to": change.Column}
+		task.Set("column", change.Column)
+		task.Set("position", position.GetNext(app, change.Column))
+	}
+
+	addEvent(task, action, reviewer, changes, attribution)
+	return task, nil
+}
+
+// mergeField adds and removes values of a list field, recording the change.
+func mergeField(task *core.Record, changes map[string]any, field string, add, remove []string, fold bool) {
+	if len(add) == 0 && len(remove) == 0 {
+		return
+	}
+
+	contains := func(list []string, s string) bool {
+		return slices.ContainsFunc(list, func(item string) bool {
+			if fold {
+				return strings.EqualFold(item, s)
+			}
+			return item == s
+		})
+	}
+
+	current := task.GetStringSlice(field)
+	result := make([]string, 0, len(current)+len(add))
+	for _, v := range current {
+		if !contains(remove, v) && !contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	for _, v := range add {
+		if !contains(result, v) {
+			result = append(result, v)
+		}
+	}
+
+	changes[field] = map[string]any{"from": current, "to": result}
+	task.Set(field, result)
+}
+
+// addEvent queues an event attributed to the reviewer, with the proposal
+// and proposing agent recorded alongside.
+func addEvent(task *core.Record, action, reviewer string, changes any, attribution map[string]any) {
+	taskevent.Add(task, action, "user", reviewer, changes, map[string]any{"proposal": attribution})
+}
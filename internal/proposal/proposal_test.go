@@ -0,0 +1,264 @@
+package proposal
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// setup creates boards, tasks, proposals, and task_events collections and
+// one board.
+func setup(t *testing.T) (*pocketbase.PocketBase, *core.Record) {
+	t.Helper()
+
+	app := testutil.NewTestApp(t)
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "prefix"},
+		&core.NumberField{Name: "next_seq"},
+	)
+	testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "type"},
+		&core.TextField{Name: "priority"},
+		&core.TextField{Name: "column"},
+		&core.NumberField{Name: "position"},
+		&core.TextField{Name: "board"},
+		&core.NumberField{Name: "seq"},
+		&core.JSONField{Name: "labels"},
+		&core.JSONField{Name: "assignees"},
+		&core.JSONField{Name: "blocked_by"},
+		&core.TextField{Name: "created_by"},
+		&core.TextField{Name: "created_by_agent"},
+		&core.DateField{Name: "deleted_at"},
+		&core.TextField{Name: "deleted_by"},
+		&core.TextField{Name: "deleted_with"},
+	)
+	testutil.CreateTestCollection(t, app, CollectionName,
+		&core.TextField{Name: "board"},
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "action"},
+		&core.JSONField{Name: "changes"},
+		&core.TextField{Name: "command"},
+		&core.TextField{Name: "summary"},
+		&core.TextField{Name: "rationale"},
+		&core.TextField{Name: "status"},
+		&core.TextField{Name: "proposed_by"},
+		&core.TextField{Name: "reviewed_by"},
+		&core.TextField{Name: "review_note"},
+		&core.DateField{Name: "reviewed_at"},
+	)
+	testutil.CreateTaskEventsCollection(t, app)
+	taskevent.Bind(app)
+
+	board := core.NewRecord(boards)
+	board.Set("name", "Work")
+	board.Set("prefix", "WRK")
+	require.NoError(t, app.Save(board))
+	return app, board
+}
+
+func createTask(t *testing.T, app *pocketbase.PocketBase, boardID, title, column string) *core.Record {
+	t.Helper()
+
+	collection, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	task := core.NewRecord(collection)
+	task.Set("title", title)
+	task.Set("column", column)
+	task.Set("board", boardID)
+	task.Set("labels", []string{"backend"})
+	require.NoError(t, app.Save(task))
+	return task
+}
+
+func propose(t *testing.T, app *pocketbase.PocketBase, boardID string, change Change) *core.Record {
+	t.Helper()
+
+	record, err := Create(app, Input{
+		BoardID:    boardID,
+		Change:     change,
+		Summary:    "test change",
+		Rationale:  "because",
+		ProposedBy: "claude",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, record.GetString("status"))
+	return record
+}
+
+func lastEvent(t *testing.T, app *pocketbase.PocketBase, task *core.Record) taskevent.Event {
+	t.Helper()
+
+	events, err := taskevent.ForTask(app, task.Id)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	return events[len(events)-1]
+}
+
+func TestApprove_Create(t *testing.T) {
+	app, board := setup(t)
+	record := propose(t, app, board.Id, Change{
+		Action: ActionCreate,
+		Column: "todo",
+		Set:    map[string]any{"title": "New task", "priority": "high"},
+	})
+
+	task, err := Approve(app, record, "ramtin", "ok")
+	require.NoError(t, err)
+
+	saved, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "New task", saved.GetString("title"))
+	assert.Equal(t, "high", saved.GetString("priority"))
+	assert.Equal(t, "todo", saved.GetString("column"))
+	assert.Equal(t, "claude", saved.GetString("created_by_agent"))
+	assert.Equal(t, 1, saved.GetInt("seq"))
+
+	event := lastEvent(t, app, saved)
+	assert.Equal(t, "created", event.Action)
+	assert.Equal(t, "ramtin", event.ActorDetail)
+	assert.Equal(t, map[string]any{
+		"id":          record.Id,
+		"proposed_by": "claude",
+		"approved_by": "ramtin",
+	}, event.Metadata["proposal"])
+
+	reviewed, err := app.FindRecordById(CollectionName, record.Id)
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, reviewed.GetString("status"))
+	assert.Equal(t, task.Id, reviewed.GetString("task"))
+	assert.Equal(t, "ramtin", reviewed.GetString("reviewed_by"))
+	assert.Equal(t, "ok", reviewed.GetString("review_note"))
+}
+
+func TestApprove_MoveAndUpdate(t *testing.T) {
+	app, board := setup(t)
+	task := createTask(t, app, board.Id, "Fix bug", "review")
+
+	move := propose(t, app, board.Id, Change{Action: ActionMove, Task: task.Id, Column: "done"})
+	moved, err := Approve(app, move, "ramtin", "")
+	require.NoError(t, err)
+	assert.Equal(t, "done", moved.GetString("column"))
+	assert.Equal(t, "moved", lastEvent(t, app, moved).Action)
+
+	update := propose(t, app, board.Id, Change{
+		Action:       ActionUpdate,
+		Task:         task.Id,
+		Set:          map[string]any{"priority": "urgent"},
+		AddLabels:    []string{"ui"},
+		RemoveLabels: []string{"backend"},
+	})
+	updated, err := Approve(app, update, "ramtin", "")
+	require.NoError(t, err)
+
+	saved, err := app.FindRecordById("tasks", updated.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "urgent", saved.GetString("priority"))
+	assert.Equal(t, []string{"ui"}, saved.GetStringSlice("labels"))
+	assert.Equal(t, "done", saved.GetString("column"))
+}
+
+func TestApprove_Delete(t *testing.T) {
+	app, board := setup(t)
+	task := createTask(t, app, board.Id, "Obsolete", "todo")
+	record := propose(t, app, board.Id, Change{Action: ActionDelete, Task: task.Id})
+
+	result, err := Approve(app, record, "ramtin", "")
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	trashed, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.True(t, trash.IsTrashed(trashed), "deleted tasks go to the trash")
+	assert.Equal(t, "ramtin", trashed.GetString("deleted_by"))
+
+	event := lastEvent(t, app, trashed)
+	assert.Equal(t, "deleted", event.Action)
+	assert.Equal(t, "ramtin", event.ActorDetail)
+	assert.Equal(t, "Obsolete", event.Metadata["title"])
+	assert.Equal(t, map[string]any{
+		"id":          record.Id,
+		"proposed_by": "claude",
+		"approved_by": "ramtin",
+	}, event.Metadata["proposal"])
+
+	reviewed, err := app.FindRecordById(CollectionName, record.Id)
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, reviewed.GetString("status"))
+}
+
+func TestApprove_Errors(t *testing.T) {
+	app, board := setup(t)
+	task := createTask(t, app, board.Id, "Gone soon", "todo")
+	record := propose(t, app, board.Id, Change{Action: ActionMove, Task: task.Id, Column: "done"})
+
+	require.NoError(t, app.Delete(task))
+	_, err := Approve(app, record, "ramtin", "")
+	assert.ErrorIs(t, err, ErrTaskGone)
+
+	require.NoError(t, Reject(app, record, "ramtin", "task was removed"))
+	assert.Equal(t, StatusRejected, record.GetString("status"))
+
+	_, err = Approve(app, record, "ramtin", "")
+	assert.ErrorIs(t, err, ErrNotPending)
+	assert.ErrorIs(t, Reject(app, record, "ramtin", ""), ErrNotPending)
+}
+
+func TestApprove_StaleRecord(t *testing.T) {
+	app, board := setup(t)
+	record := propose(t, app, board.Id, Change{
+		Action: ActionCreate,
+		Set:    map[string]any{"title": "Once"},
+	})
+
+	// A second reviewer loaded the proposal before the first approved it
+	stale, err := app.FindRecordById(CollectionName, record.Id)
+	require.NoError(t, err)
+
+	_, err = Approve(app, record, "ramtin", "")
+	require.NoError(t, err)
+
+	_, err = Approve(app, stale, "alice", "")
+	assert.ErrorIs(t, err, ErrNotPending)
+	assert.ErrorIs(t, Reject(app, stale, "alice", ""), ErrNotPending)
+
+	tasks, err := app.FindAllRecords("tasks")
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1, "the proposal is applied once")
+}
+
+func TestListAndFind(t *testing.T) {
+	app, board := setup(t)
+	first := propose(t, app, board.Id, Change{Action: ActionCreate, Set: map[string]any{"title": "A"}})
+	second := propose(t, app, board.Id, Change{Action: ActionCreate, Set: map[string]any{"title": "B"}})
+	require.NoError(t, Reject(app, second, "ramtin", ""))
+
+	pending, err := List(app, board.Id, StatusPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, first.Id, pending[0].Id)
+
+	all, err := List(app, "", "")
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	found, err := Find(app, first.Id[:6])
+	require.NoError(t, err)
+	assert.Equal(t, first.Id, found.Id)
+
+	_, err = Find(app, "nonexistent")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	change, err := DecodeChange(found)
+	require.NoError(t, err)
+	assert.Equal(t, ActionCreate, change.Action)
+	assert.Equal(t, "A", change.Set["title"])
+}
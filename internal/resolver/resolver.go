@@ -9,6 +9,7 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 
 	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
 )
 
 // escapeLikePattern escapes SQL LIKE special characters (% and _)
@@ -44,16 +45,30 @@ func (r *Resolution) IsNotFound() bool {
 // 2. Exact ID match
 // 3. ID prefix match (must be unique)
 // 4. Title substring match (case-insensitive, must be unique)
+//
+// Tasks in the trash are never matched.
 func ResolveTask(app *pocketbase.PocketBase, ref string) (*Resolution, error) {
+	return ResolveTaskInBoard(app, ref, "")
+}
+
+// ResolveTaskInBoard resolves a task reference like ResolveTask, matching
+// only the tasks of one board when boardID is set.
+func ResolveTaskInBoard(app *pocketbase.PocketBase, ref, boardID string) (*Resolution, error) {
+	scope := []dbx.Expression{trash.Exclude(app, "tasks")}
+	if boardID != "" {
+		scope = append(scope, dbx.HashExp{"board": boardID})
+	}
+
 	// 1. Try display ID format (PREFIX-NUMBER)
 	if prefix, seq, err := board.ParseDisplayID(ref); err == nil {
 		// Find board by prefix
 		boardRecord, err := board.GetByNameOrPrefix(app, prefix)
-		if err == nil {
+		if err == nil && (boardID == "" || boardRecord.Id == boardID) {
 			// Find task by board + sequence
 			tasks, err := app.FindAllRecords("tasks",
 				dbx.NewExp("board = {:board} AND seq = {:seq}",
 					dbx.Params{"board": boardRecord.Id, "seq": seq}),
+				trash.Exclude(app, "tasks"),
 			)
 			if err == nil && len(tasks) == 1 {
 				return &Resolution{Task: tasks[0]}, nil
@@ -63,14 +78,14 @@ func ResolveTask(app *pocketbase.PocketBase, ref string) (*Resolution, error) {
 
 	// 2. Try exact ID match
 	task, err := app.FindRecordById("tasks", ref)
-	if err == nil {
+	if err == nil && !trash.IsTrashed(task) && (boardID == "" || task.GetString("board") == boardID) {
 		return &Resolution{Task: task}, nil
 	}
 
 	// 3. Try ID prefix match
-	tasks, err := app.FindAllRecords("tasks",
+	tasks, err := app.FindAllRecords("tasks", append([]dbx.Expression{
 		dbx.NewExp("id LIKE {:prefix} ESCAPE '\\'", dbx.Params{"prefix": escapeLikePattern(ref) + "%"}),
-	)
+	}, scope...)...)
 	if err != nil {
 		return nil, fmt.Errorf("database error: %w", err)
 	}
@@ -84,10 +99,10 @@ func ResolveTask(app *pocketbase.PocketBase, ref string) (*Resolution, error) {
 	}
 
 	// 4. Try title match (case-insensitive substring)
-	tasks, err = app.FindAllRecords("tasks",
+	tasks, err = app.FindAllRecords("tasks", append([]dbx.Expression{
 		dbx.NewExp("LOWER(title) LIKE {:title} ESCAPE '\\'",
 			dbx.Params{"title": "%" + escapeLikePattern(strings.ToLower(ref)) + "%"}),
-	)
+	}, scope...)...)
 	if err != nil {
 		return nil, fmt.Errorf("database error: %w", err)
 	}
@@ -105,7 +120,13 @@ func ResolveTask(app *pocketbase.PocketBase, ref string) (*Resolution, error) {
 // MustResolve resolves a task and returns an error if not found or ambiguous.
 // This is a convenience wrapper for commands that need exactly one task.
 func MustResolve(app *pocketbase.PocketBase, ref string) (*core.Record, error) {
-	resolution, err := ResolveTask(app, ref)
+	return MustResolveInBoard(app, ref, "")
+}
+
+// MustResolveInBoard is MustResolve among the tasks of one board when
+// boardID is set.
+func MustResolveInBoard(app *pocketbase.PocketBase, ref, boardID string) (*core.Record, error) {
+	resolution, err := ResolveTaskInBoard(app, ref, boardID)
 	if err != nil {
 		return nil, err
 	}
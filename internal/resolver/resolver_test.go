@@ -119,6 +119,33 @@ func TestMustResolve_Ambiguous(t *testing.T) {
 	assert.True(t, ok, "expected AmbiguousError")
 }
 
+func TestResolveTaskInBoard(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	setupTasksCollection(t, app)
+
+	taskA := createTestTask(t, app, "Task A", "feature", "medium", "backlog")
+	taskA.Set("board", "board1")
+	require.NoError(t, app.Save(taskA))
+	taskB := createTestTask(t, app, "Task B", "feature", "medium", "backlog")
+	taskB.Set("board", "board2")
+	require.NoError(t, app.Save(taskB))
+
+	// Tasks of other boards do not make the reference ambiguous
+	resolution, err := ResolveTaskInBoard(app, "Task", "board1")
+	require.NoError(t, err)
+	require.NotNil(t, resolution.Task)
+	assert.Equal(t, taskA.Id, resolution.Task.Id)
+
+	resolution, err = ResolveTaskInBoard(app, taskB.Id, "board1")
+	require.NoError(t, err)
+	assert.True(t, resolution.IsNotFound())
+
+	_, err = MustResolveInBoard(app, "Task", "")
+	var ambErr *AmbiguousError
+	require.ErrorAs(t, err, &ambErr)
+	assert.Len(t, ambErr.Matches, 2)
+}
+
 // Helper functions
 
 func setupTasksCollection(t *testing.T, app *pocketbase.PocketBase) {
@@ -159,7 +186,7 @@ func setupTasksCollection(t *testing.T, app *pocketbase.PocketBase) {
 		Values:   []string{"user", "agent", "cli"},
 	})
 	collection.Fields.Add(&core.TextField{Name: "created_by_agent"})
-	collection.Fields.Add(&core.JSONField{Name: "history"})
+	collection.Fields.Add(&core.TextField{Name: "board"})
 
 	if err := app.Save(collection); err != nil {
 		t.Fatalf("failed to create tasks collection: %v", err)
@@ -183,7 +210,6 @@ func createTestTask(t *testing.T, app *pocketbase.PocketBase, title, taskType, p
 	record.Set("labels", []string{})
 	record.Set("blocked_by", []string{})
 	record.Set("created_by", "cli")
-	record.Set("history", []map[string]any{})
 
 	if err := app.Save(record); err != nil {
 		t.Fatalf("failed to create test task: %v", err)
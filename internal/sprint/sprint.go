@@ -0,0 +1,349 @@
+// Package sprint manages time-boxed iterations scoped to a board.
+//
+// A board has at most one active sprint at a time. Tasks join a sprint
+// through their sprint relation; burndown and velocity are derived from
+// the column changes in the task event log using the flow package.
+package sprint
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// CollectionName is the PocketBase collection holding sprints.
+const CollectionName = "sprints"
+
+// Sprint lifecycle states.
+const (
+	StatePlanned = "planned"
+	StateActive  = "active"
+	StateClosed  = "closed"
+)
+
+// RefCurrent resolves to the board's active sprint.
+const RefCurrent = "current"
+
+// DefaultLength is the length of a sprint when no end date is given.
+const DefaultLength = 14 * 24 * time.Hour
+
+var (
+	// ErrNoActiveSprint is returned when a board has no active sprint.
+	ErrNoActiveSprint = errors.New("no active sprint")
+	// ErrAlreadyActive is returned when starting a sprint while another is active.
+	ErrAlreadyActive = errors.New("another sprint is already active")
+)
+
+// ForBoard returns all sprints for a board ordered by start date.
+func ForBoard(app core.App, boardID string) ([]*core.Record, error) {
+	records, err := app.FindAllRecords(CollectionName,
+		dbx.NewExp("board = {:board}", dbx.Params{"board": boardID}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		si := records[i].GetDateTime("start_date").Time()
+		sj := records[j].GetDateTime("start_date").Time()
+		if !si.Equal(sj) {
+			return si.Before(sj)
+		}
+		return records[i].GetDateTime("created").Time().Before(records[j].GetDateTime("created").Time())
+	})
+	return records, nil
+}
+
+// FindActive returns the active sprint for a board.
+func FindActive(app core.App, boardID string) (*core.Record, error) {
+	records, err := app.FindAllRecords(CollectionName,
+		dbx.NewExp("board = {:board} AND state = {:state}",
+			dbx.Params{"board": boardID, "state": StateActive}),
+	)
+	if err != nil || len(records) == 0 {
+		return nil, ErrNoActiveSprint
+	}
+	return records[0], nil
+}
+
+// NextPlanned returns the earliest planned sprint for a board, or nil.
+func NextPlanned(app core.App, boardID string) *core.Record {
+	records, err := ForBoard(app, boardID)
+	if err != nil {
+		return nil
+	}
+	for _, r := range records {
+		if r.GetString("state") == StatePlanned {
+			return r
+		}
+	}
+	return nil
+}
+
+// Resolve finds a sprint on a board by "current", ID, ID prefix, or name
+// (case-insensitive, exact match preferred over substring).
+func Resolve(app core.App, boardID, ref string) (*core.Record, error) {
+	if strings.EqualFold(ref, RefCurrent) {
+		return FindActive(app, boardID)
+	}
+
+	records, err := ForBoard(app, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sprints: %w", err)
+	}
+
+	var prefixMatches, exactMatches, partialMatches []*core.Record
+	lowerRef := strings.ToLower(ref)
+	for _, r := range records {
+		name := strings.ToLower(r.GetString("name"))
+		switch {
+		case r.Id == ref:
+			return r, nil
+		case strings.HasPrefix(r.Id, ref):
+			prefixMatches = append(prefixMatches, r)
+		}
+		if name == lowerRef {
+			exactMatches = append(exactMatches, r)
+		} else if strings.Contains(name, lowerRef) {
+			partialMatches = append(partialMatches, r)
+		}
+	}
+
+	for _, matches := range [][]*core.Record{prefixMatches, exactMatches, partialMatches} {
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		default:
+			names := make([]string, len(matches))
+			for i, r := range matches {
+				names[i] = r.GetString("name")
+			}
+			return nil, fmt.Errorf("ambiguous sprint reference '%s' matches: %s", ref, strings.Join(names, ", "))
+		}
+	}
+
+	return nil, fmt.Errorf("no sprint found matching: %s", ref)
+}
+
+// Tasks returns all tasks in a sprint.
+func Tasks(app core.App, sprintID string) ([]*core.Record, error) {
+	return app.FindAllRecords("tasks",
+		dbx.NewExp("sprint = {:sprint}", dbx.Params{"sprint": sprintID}),
+		trash.Exclude(app, "tasks"),
+	)
+}
+
+// Start activates a planned sprint. Only one sprint per board may be active.
+func Start(app core.App, record *core.Record) error {
+	switch record.GetString("state") {
+	case StateActive:
+		return nil
+	case StateClosed:
+		return fmt.Errorf("sprint '%s' is already closed", record.GetString("name"))
+	}
+
+	if active, err := FindActive(app, record.GetString("board")); err == nil && active.Id != record.Id {
+		return fmt.Errorf("%w: %s", ErrAlreadyActive, active.GetString("name"))
+	}
+
+	record.Set("state", StateActive)
+	if record.GetDateTime("start_date").IsZero() {
+		record.Set("start_date", startOfDay(time.Now()))
+	}
+	return app.Save(record)
+}
+
+// Close marks a sprint as closed at the given time.
+func Close(app core.App, record *core.Record, at time.Time) error {
+	if record.GetString("state") == StateClosed {
+		return fmt.Errorf("sprint '%s' is already closed", record.GetString("name"))
+	}
+	record.Set("state", StateClosed)
+	record.Set("closed_at", at.UTC())
+	return app.Save(record)
+}
+
+// Unfinished returns the tasks in a sprint that are not done.
+func Unfinished(tasks []*core.Record) []*core.Record {
+	var result []*core.Record
+	for _, t := range tasks {
+		if t.GetString("column") != flow.ColumnDone {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// Window returns the sprint's start and end (end of the end date).
+// A missing end date defaults to DefaultLength after the start.
+func Window(record *core.Record) (time.Time, time.Time) {
+	start := record.GetDateTime("start_date").Time()
+	if start.IsZero() {
+		start = startOfDay(record.GetDateTime("created").Time())
+	}
+	end := record.GetDateTime("end_date").Time()
+	if end.IsZero() {
+		end = start.Add(DefaultLength)
+	} else {
+		end = startOfDay(end).AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return start, end
+}
+
+// BurndownPoint is the remaining work at the end of a sprint day.
+type BurndownPoint struct {
+	Date      string  `json:"date"`
+	Remaining int     `json:"remaining"`
+	Completed int     `json:"completed"`
+	Ideal     float64 `json:"ideal"`
+}
+
+// Burndown computes remaining tasks for each day of [start, end], stopping
+// at now. The ideal line falls linearly from the total to zero.
+func Burndown(timelines []flow.Timeline, start, end, now time.Time) []BurndownPoint {
+	total := len(timelines)
+	days := int(startOfDay(end).Sub(startOfDay(start)).Hours()/24) + 1
+	if days < 1 {
+		days = 1
+	}
+
+	var points []BurndownPoint
+	for i := 0; i < days; i++ {
+		day := startOfDay(start).AddDate(0, 0, i)
+		if day.After(now) {
+			break
+		}
+		endOfDay := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		if endOfDay.After(now) {
+			endOfDay = now
+		}
+
+		completed := 0
+		for _, tl := range timelines {
+			if tl.ColumnAt(endOfDay) == flow.ColumnDone {
+				completed++
+			}
+		}
+
+		ideal := float64(total)
+		if days > 1 {
+			ideal = float64(total) * (1 - float64(i)/float64(days-1))
+		}
+
+		points = append(points, BurndownPoint{
+			Date:      day.Format("2006-01-02"),
+			Remaining: total - completed,
+			Completed: completed,
+			Ideal:     float64(int(ideal*100+0.5)) / 100,
+		})
+	}
+	return points
+}
+
+// VelocityPoint is the work completed in a closed sprint.
+type VelocityPoint struct {
+	SprintID  string `json:"sprint_id"`
+	Name      string `json:"name"`
+	Completed int    `json:"completed"`
+	Remaining int    `json:"remaining"`
+}
+
+// Velocity returns completed task counts for each closed sprint on a board,
+// oldest first. A task counts as completed when it reached done before the
+// sprint closed.
+func Velocity(app core.App, boardID string) ([]VelocityPoint, error) {
+	records, err := ForBoard(app, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []VelocityPoint
+	for _, r := range records {
+		if r.GetString("state") != StateClosed {
+			continue
+		}
+		tasks, err := Tasks(app, r.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		closedAt := r.GetDateTime("closed_at").Time()
+		if closedAt.IsZero() {
+			_, closedAt = Window(r)
+		}
+
+		timelines, err := Timelines(app, tasks)
+		if err != nil {
+			return nil, err
+		}
+
+		point := VelocityPoint{SprintID: r.Id, Name: r.GetString("name")}
+		for _, tl := range timelines {
+			if tl.ColumnAt(closedAt) == flow.ColumnDone {
+				point.Completed++
+			} else {
+				point.Remaining++
+			}
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// AverageVelocity returns the mean completed count over the last n points
+// (all points when n <= 0).
+func AverageVelocity(points []VelocityPoint, n int) float64 {
+	if n > 0 && len(points) > n {
+		points = points[len(points)-n:]
+	}
+	if len(points) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, p := range points {
+		sum += p.Completed
+	}
+	return float64(int(float64(sum)/float64(len(points))*100+0.5)) / 100
+}
+
+// Timelines builds flow timelines for task records from their recorded
+// column changes.
+func Timelines(app core.App, tasks []*core.Record) ([]flow.Timeline, error) {
+	ids := make([]string, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.Id
+	}
+	transitions, err := taskevent.Transitions(app, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	timelines := make([]flow.Timeline, 0, len(tasks))
+	for _, t := range tasks {
+		timelines = append(timelines, flow.NewTimeline(flow.TaskInput{
+			ID:          t.Id,
+			Title:       t.GetString("title"),
+			Column:      t.GetString("column"),
+			CreatedBy:   t.GetString("created_by"),
+			Created:     t.GetDateTime("created").Time(),
+			Transitions: transitions[t.Id],
+		}))
+	}
+	return timelines, nil
+}
+
+// startOfDay returns midnight UTC on t's date.
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,209 @@
+package sprint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+// setupCollections creates minimal boards, sprints, and tasks collections.
+func setupCollections(t *testing.T, app *pocketbase.PocketBase) (boards, sprints, tasks *core.Collection) {
+	t.Helper()
+
+	boards = testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name", Required: true},
+	)
+
+	sprints = core.NewBaseCollection(CollectionName)
+	sprints.Fields.Add(&core.RelationField{Name: "board", CollectionId: boards.Id, MaxSelect: 1, Required: true})
+	sprints.Fields.Add(&core.TextField{Name: "name", Required: true})
+	sprints.Fields.Add(&core.DateField{Name: "start_date"})
+	sprints.Fields.Add(&core.DateField{Name: "end_date"})
+	sprints.Fields.Add(&core.TextField{Name: "goal"})
+	sprints.Fields.Add(&core.SelectField{Name: "state", Required: true, Values: []string{StatePlanned, StateActive, StateClosed}})
+	sprints.Fields.Add(&core.DateField{Name: "closed_at"})
+	sprints.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
+	require.NoError(t, app.Save(sprints))
+
+	tasks = testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "column"},
+		&core.TextField{Name: "board"},
+		&core.TextField{Name: "sprint"},
+		&core.TextField{Name: "created_by"},
+	)
+	testutil.CreateTaskEventsCollection(t, app)
+
+	return boards, sprints, tasks
+}
+
+func createSprint(t *testing.T, app *pocketbase.PocketBase, collection *core.Collection, boardID, name, state, start string) *core.Record {
+	t.Helper()
+
+	record := core.NewRecord(collection)
+	record.Set("board", boardID)
+	record.Set("name", name)
+	record.Set("state", state)
+	record.Set("start_date", start)
+	require.NoError(t, app.Save(record))
+	return record
+}
+
+func TestResolve(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	boards, sprints, _ := setupCollections(t, app)
+
+	b := core.NewRecord(boards)
+	b.Set("name", "Work")
+	require.NoError(t, app.Save(b))
+
+	s12 := createSprint(t, app, sprints, b.Id, "Sprint 12", StateActive, "2025-01-06")
+	s13 := createSprint(t, app, sprints, b.Id, "Sprint 13", StatePlanned, "2025-01-20")
+
+	found, err := Resolve(app, b.Id, "current")
+	require.NoError(t, err)
+	assert.Equal(t, s12.Id, found.Id)
+
+	found, err = Resolve(app, b.Id, "sprint 13")
+	require.NoError(t, err)
+	assert.Equal(t, s13.Id, found.Id)
+
+	found, err = Resolve(app, b.Id, s13.Id[:6])
+	require.NoError(t, err)
+	assert.Equal(t, s13.Id, found.Id)
+
+	_, err = Resolve(app, b.Id, "Sprint")
+	assert.ErrorContains(t, err, "ambiguous")
+
+	_, err = Resolve(app, b.Id, "Sprint 99")
+	assert.Error(t, err)
+}
+
+func TestStartAndClose(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	boards, sprints, _ := setupCollections(t, app)
+
+	b := core.NewRecord(boards)
+	b.Set("name", "Work")
+	require.NoError(t, app.Save(b))
+
+	s1 := createSprint(t, app, sprints, b.Id, "Sprint 1", StatePlanned, "2025-01-06")
+	s2 := createSprint(t, app, sprints, b.Id, "Sprint 2", StatePlanned, "2025-01-20")
+
+	assert.Equal(t, s1.Id, NextPlanned(app, b.Id).Id)
+
+	require.NoError(t, Start(app, s1))
+	assert.Equal(t, StateActive, s1.GetString("state"))
+
+	// Only one active sprint per board
+	assert.ErrorIs(t, Start(app, s2), ErrAlreadyActive)
+
+	require.NoError(t, Close(app, s1, time.Now()))
+	assert.Equal(t, StateClosed, s1.GetString("state"))
+	assert.False(t, s1.GetDateTime("closed_at").IsZero())
+	assert.Error(t, Close(app, s1, time.Now()))
+
+	_, err := FindActive(app, b.Id)
+	assert.ErrorIs(t, err, ErrNoActiveSprint)
+
+	require.NoError(t, Start(app, s2))
+}
+
+func TestVelocity(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	boards, sprints, tasks := setupCollections(t, app)
+
+	b := core.NewRecord(boards)
+	b.Set("name", "Work")
+	require.NoError(t, app.Save(b))
+
+	s1 := createSprint(t, app, sprints, b.Id, "Sprint 1", StateActive, "2025-01-06")
+
+	doneAt := time.Now().Add(-time.Hour).UTC()
+	for i, column := range []string{"done", "done", "todo"} {
+		task := core.NewRecord(tasks)
+		task.Set("title", "Task")
+		task.Set("column", column)
+		task.Set("board", b.Id)
+		task.Set("sprint", s1.Id)
+		require.NoError(t, app.Save(task))
+		if column == "done" {
+			require.NoError(t, taskevent.Save(app, taskevent.Event{
+				Task:      task.Id,
+				Board:     b.Id,
+				Action:    taskevent.ActionMoved,
+				Actor:     "cli",
+				Changes:   map[string]any{"column": map[string]any{"from": "todo", "to": "done"}},
+				Timestamp: doneAt.Add(time.Duration(i) * time.Minute),
+			}))
+		}
+	}
+
+	require.NoError(t, Close(app, s1, time.Now()))
+
+	points, err := Velocity(app, b.Id)
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	assert.Equal(t, 2, points[0].Completed)
+	assert.Equal(t, 1, points[0].Remaining)
+}
+
+func TestAverageVelocity(t *testing.T) {
+	points := []VelocityPoint{{Completed: 2}, {Completed: 4}, {Completed: 6}, {Completed: 8}}
+
+	assert.Equal(t, 5.0, AverageVelocity(points, 0))
+	assert.Equal(t, 6.0, AverageVelocity(points, 3))
+	assert.Equal(t, 0.0, AverageVelocity(nil, 3))
+}
+
+func TestBurndown(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 8, 23, 59, 59, 0, time.UTC)
+
+	done := func(at time.Time) flow.Timeline {
+		return flow.NewTimeline(flow.TaskInput{
+			Column:      "done",
+			Created:     start.Add(-time.Hour),
+			Transitions: []flow.Transition{{At: at, From: "todo", To: "done"}},
+		})
+	}
+	open := flow.NewTimeline(flow.TaskInput{Column: "todo", Created: start.Add(-time.Hour)})
+
+	timelines := []flow.Timeline{
+		done(start.Add(10 * time.Hour)), // day 1
+		done(start.Add(34 * time.Hour)), // day 2
+		done(start.Add(35 * time.Hour)), // day 2
+		open,
+	}
+
+	points := Burndown(timelines, start, end, end)
+	require.Len(t, points, 3)
+	assert.Equal(t, 3, points[0].Remaining)
+	assert.Equal(t, 1, points[1].Remaining)
+	assert.Equal(t, 1, points[2].Remaining)
+	assert.Equal(t, 4.0, points[0].Ideal)
+	assert.Equal(t, 2.0, points[1].Ideal)
+	assert.Equal(t, 0.0, points[2].Ideal)
+
+	// Stops at now
+	points = Burndown(timelines, start, end, start.Add(30*time.Hour))
+	assert.Len(t, points, 2)
+}
+
+func TestWindow(t *testing.T) {
+	record := core.NewRecord(core.NewBaseCollection(CollectionName))
+	record.Set("start_date", "2025-01-06")
+	record.Set("end_date", "2025-01-17")
+
+	start, end := Window(record)
+	assert.Equal(t, "2025-01-06", start.Format("2006-01-02"))
+	assert.Equal(t, "2025-01-17 23:59", end.Format("2006-01-02 15:04"))
+}
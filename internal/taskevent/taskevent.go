@@ -0,0 +1,572 @@
+// Package taskevent records task changes in the task_events collection.
+//
+// Every create, update, and delete of a task produces one or more events,
+// written by record hooks (see Bind) so that all mutation paths are
+// covered: the CLI, the TUI, the API, and the web UI. Code changing a task
+// queues events naming the action and actor with Add; the hooks write them
+// when the task is saved. Saves that change tracked fields without a queued
+// event are recorded from a field diff.
+package taskevent
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+)
+
+// CollectionName is the name of the task events collection.
+const CollectionName = "task_events"
+
+// Event actions written when no queued event describes the change.
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionMoved   = "moved"
+	ActionDeleted = "deleted"
+)
+
+// actorKey and actorDetailKey are custom (non-persisted) record keys set by
+// SetActor to attribute changes that carry no queued event.
+const (
+	actorKey       = "@event_actor"
+	actorDetailKey = "@event_actor_detail"
+)
+
+// PendingKey is the custom (non-persisted) record key holding the events
+// queued with Add. API clients send queued events under the same key of
+// the request body.
+const PendingKey = "@events"
+
+// DefaultActor is used when neither a queued event nor SetActor names one.
+const DefaultActor = "user"
+
+// TrackedFields lists the task fields diffed for events.
+// Position and bookkeeping fields are not tracked.
+var TrackedFields = []string{
+	"title", "description", "type", "priority", "column", "board",
+	"epic", "parent", "sprint", "labels", "blocked_by", "assignees",
+	"due_date", "estimate",
+}
+
+// Event is a single recorded change to a task.
+type Event struct {
+	ID          string         `json:"id,omitempty"`
+	Task        string         `json:"task"`
+	Board       string         `json:"board,omitempty"`
+	Action      string         `json:"action"`
+	Actor       string         `json:"actor"`
+	ActorDetail string         `json:"actor_detail,omitempty"`
+	Changes     any            `json:"changes,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Timestamp   time.Time      `json:"timestamp"`
+}
+
+// Save stores an event.
+func Save(app core.App, e Event) error {
+	collection, err := app.FindCollectionByNameOrId(CollectionName)
+	if err != nil {
+		return fmt.Errorf("task_events collection not found: %w", err)
+	}
+
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("task", e.Task)
+	record.Set("board", e.Board)
+	record.Set("action", e.Action)
+	record.Set("actor", e.Actor)
+	record.Set("actor_detail", e.ActorDetail)
+	record.Set("changes", e.Changes)
+	record.Set("metadata", e.Metadata)
+	record.Set("timestamp", e.Timestamp.UTC())
+
+	return app.Save(record)
+}
+
+// FromRecord converts a task_events record to an Event.
+func FromRecord(record *core.Record) Event {
+	e := Event{
+		ID:          record.Id,
+		Task:        record.GetString("task"),
+		Board:       record.GetString("board"),
+		Action:      record.GetString("action"),
+		Actor:       record.GetString("actor"),
+		ActorDetail: record.GetString("actor_detail"),
+		Timestamp:   record.GetDateTime("timestamp").Time(),
+	}
+
+	var changes any
+	if err := record.UnmarshalJSONField("changes", &changes); err == nil {
+		e.Changes = changes
+	}
+	var metadata map[string]any
+	if err := record.UnmarshalJSONField("metadata", &metadata); err == nil && len(metadata) > 0 {
+		e.Metadata = metadata
+	}
+	return e
+}
+
+// Add queues an event describing a change to a task, written when the task
+// is next saved. Changes are field diffs as {"field": {"from": old, "to":
+// new}}; events without changes take the field diff of the save.
+func Add(task *core.Record, action, actor, detail string, changes any, metadata map[string]any) {
+	events := append(Pending(task), Event{
+		Action:      action,
+		Actor:       actor,
+		ActorDetail: detail,
+		Changes:     changes,
+		Metadata:    metadata,
+		Timestamp:   time.Now(),
+	})
+	task.Set(PendingKey, events)
+}
+
+// Pending returns the events queued on a task, including those an API
+// client sent along with the record.
+func Pending(task *core.Record) []Event {
+	switch raw := task.GetRaw(PendingKey).(type) {
+	case nil:
+		return nil
+	case []Event:
+		return raw
+	default:
+		// Events decoded from a request body
+		var events []Event
+		if data, err := json.Marshal(raw); err == nil {
+			json.Unmarshal(data, &events)
+		}
+		return events
+	}
+}
+
+// Clear drops the events queued on a task once they are written. Later
+// saves of the same record stay attributed to the actor of the last one.
+func Clear(task *core.Record) {
+	events := Pending(task)
+	if len(events) == 0 {
+		return
+	}
+	last := events[len(events)-1]
+	SetActor(task, last.Actor, last.ActorDetail)
+	task.Set(PendingKey, nil)
+}
+
+// SetActor attributes the next save or delete of a task to an actor when
+// the change carries no queued event. The values are not persisted.
+func SetActor(task *core.Record, actor, detail string) {
+	task.Set(actorKey, actor)
+	task.Set(actorDetailKey, detail)
+}
+
+// ActorOf returns the actor set with SetActor, or DefaultActor.
+func ActorOf(task *core.Record) (string, string) {
+	actor, _ := task.GetRaw(actorKey).(string)
+	detail, _ := task.GetRaw(actorDetailKey).(string)
+	if actor == "" {
+		actor = DefaultActor
+	}
+	return actor, detail
+}
+
+// Diff returns the tracked fields that differ between two versions of a
+// task as {"field": {"from": old, "to": new}}. A nil original diffs
+// against empty values.
+func Diff(original, updated *core.Record) map[string]any {
+	changes := make(map[string]any)
+	for _, field := range TrackedFields {
+		if updated.Collection().Fields.GetByName(field) == nil {
+			continue
+		}
+		to := normalize(updated.Get(field))
+		var from any
+		if original != nil {
+			from = normalize(original.Get(field))
+		}
+		if isEmpty(from) && isEmpty(to) {
+			continue
+		}
+		if !reflect.DeepEqual(from, to) {
+			changes[field] = map[string]any{"from": from, "to": to}
+		}
+	}
+	return changes
+}
+
+// ForSave returns the events describing a save of a task: the events
+// queued with Add, or otherwise a single event built from the field diff.
+// It returns nil for saves that change no tracked field.
+func ForSave(original, updated *core.Record) []Event {
+	diff := Diff(original, updated)
+
+	if pending := Pending(updated); len(pending) > 0 {
+		events := make([]Event, len(pending))
+		for i, e := range pending {
+			e.Task = updated.Id
+			e.Board = updated.GetString("board")
+			if e.Action == "" {
+				e.Action = ActionUpdated
+			}
+			if e.Actor == "" {
+				e.Actor = DefaultActor
+			}
+			if isEmpty(normalize(e.Changes)) && original != nil && len(diff) > 0 {
+				e.Changes = diff
+			}
+			if e.Timestamp.IsZero() {
+				e.Timestamp = time.Now()
+			}
+			events[i] = e
+		}
+		return events
+	}
+
+	action := ActionUpdated
+	switch {
+	case original == nil:
+		action = ActionCreated
+		diff = nil
+	case len(diff) == 0:
+		return nil
+	case diff["column"] != nil:
+		action = ActionMoved
+	}
+
+	actor, detail := ActorOf(updated)
+	return []Event{{
+		Task:        updated.Id,
+		Board:       updated.GetString("board"),
+		Action:      action,
+		Actor:       actor,
+		ActorDetail: detail,
+		Changes:     changesOrNil(diff),
+		Timestamp:   time.Now(),
+	}}
+}
+
+// ForDelete returns the event describing the deletion of a task.
+func ForDelete(task *core.Record) Event {
+	actor, detail := ActorOf(task)
+	return Event{
+		Task:        task.Id,
+		Board:       task.GetString("board"),
+		Action:      ActionDeleted,
+		Actor:       actor,
+		ActorDetail: detail,
+		Metadata:    map[string]any{"title": task.GetString("title")},
+		Timestamp:   time.Now(),
+	}
+}
+
+// Bind registers the record hooks writing the events of every task create,
+// update, and delete. Write errors are logged but never fail the task
+// change; databases without the task_events collection are skipped.
+func Bind(app core.App) {
+	app.OnRecordCreate("tasks").BindFunc(func(e *core.RecordEvent) error {
+		if err := e.Next(); err != nil {
+			return err
+		}
+		write(e.App, ForSave(nil, e.Record))
+		Clear(e.Record)
+		return nil
+	})
+
+	app.OnRecordUpdate("tasks").BindFunc(func(e *core.RecordEvent) error {
+		// Compute the events before the original is replaced by the save
+		events := ForSave(e.Record.Original(), e.Record)
+
+		if err := e.Next(); err != nil {
+			return err
+		}
+		write(e.App, events)
+		Clear(e.Record)
+		return nil
+	})
+
+	app.OnRecordDelete("tasks").BindFunc(func(e *core.RecordEvent) error {
+		event := ForDelete(e.Record)
+
+		if err := e.Next(); err != nil {
+			return err
+		}
+		write(e.App, []Event{event})
+		return nil
+	})
+}
+
+// write stores events, logging failures.
+func write(app core.App, events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	if _, err := app.FindCachedCollectionByNameOrId(CollectionName); err != nil {
+		return
+	}
+
+	for _, event := range events {
+		if err := Save(app, event); err != nil {
+			app.Logger().Error("task event write failed",
+				"task", event.Task,
+				"action", event.Action,
+				"error", err,
+			)
+		}
+	}
+}
+
+// Filter selects events in Query. Zero values match everything.
+type Filter struct {
+	Task  string
+	Board string
+	Since time.Time
+	Actor string // Matches actor or actor_detail, case-insensitively
+	Limit int    // Most recent events to return; 0 returns all
+}
+
+// Query returns matching events, oldest first.
+func Query(app core.App, f Filter) ([]Event, error) {
+	collection, err := app.FindCollectionByNameOrId(CollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("task_events collection not found: %w", err)
+	}
+
+	query := app.RecordQuery(collection)
+	if f.Task != "" {
+		query = query.AndWhere(dbx.HashExp{"task": f.Task})
+	}
+	if f.Board != "" {
+		query = query.AndWhere(dbx.HashExp{"board": f.Board})
+	}
+	if !f.Since.IsZero() {
+		query = query.AndWhere(dbx.NewExp("timestamp >= {:since}",
+			dbx.Params{"since": f.Since.UTC().Format("2006-01-02 15:04:05.000Z")}))
+	}
+	if f.Actor != "" {
+		query = query.AndWhere(dbx.NewExp("(LOWER(actor) = {:actor} OR LOWER(actor_detail) = {:actor})",
+			dbx.Params{"actor": strings.ToLower(f.Actor)}))
+	}
+
+	// Newest first so the limit keeps the most recent events
+	query = query.OrderBy("timestamp DESC", "rowid DESC")
+	if f.Limit > 0 {
+		query = query.Limit(int64(f.Limit))
+	}
+
+	var records []*core.Record
+	if err := query.All(&records); err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, len(records))
+	for i, r := range records {
+		events[len(records)-1-i] = FromRecord(r)
+	}
+	return events, nil
+}
+
+// ForTask returns all events of a task, oldest first.
+func ForTask(app core.App, taskID string) ([]Event, error) {
+	return Query(app, Filter{Task: taskID})
+}
+
+// transitionBatch caps the task IDs per query of Transitions and
+// Templates.
+const transitionBatch = 500
+
+// Transitions returns the column changes recorded for tasks, keyed by task
+// ID, oldest first.
+func Transitions(app core.App, taskIDs []string) (map[string][]flow.Transition, error) {
+	collection, err := app.FindCollectionByNameOrId(CollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("task_events collection not found: %w", err)
+	}
+
+	result := make(map[string][]flow.Transition)
+	for start := 0; start < len(taskIDs); start += transitionBatch {
+		end := min(start+transitionBatch, len(taskIDs))
+		ids := make([]any, 0, end-start)
+		for _, id := range taskIDs[start:end] {
+			ids = append(ids, id)
+		}
+
+		var records []*core.Record
+		err := app.RecordQuery(collection).
+			AndWhere(dbx.In("task", ids...)).
+			AndWhere(dbx.NewExp("json_extract(changes, '$.column') IS NOT NULL")).
+			OrderBy("timestamp ASC", "rowid ASC").
+			All(&records)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range records {
+			e := FromRecord(r)
+			changes, _ := e.Changes.(map[string]any)
+			column, _ := changes["column"].(map[string]any)
+			from, _ := column["from"].(string)
+			to, _ := column["to"].(string)
+			result[e.Task] = append(result[e.Task], flow.Transition{
+				At:          e.Timestamp,
+				From:        from,
+				To:          to,
+				Actor:       e.Actor,
+				ActorDetail: e.ActorDetail,
+			})
+		}
+	}
+	return result, nil
+}
+
+// Templates returns the template each task was created from, keyed by
+// task ID. Tasks not created from a template are left out.
+func Templates(app core.App, taskIDs []string) (map[string]string, error) {
+	collection, err := app.FindCollectionByNameOrId(CollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("task_events collection not found: %w", err)
+	}
+
+	result := make(map[string]string)
+	for start := 0; start < len(taskIDs); start += transitionBatch {
+		end := min(start+transitionBatch, len(taskIDs))
+		ids := make([]any, 0, end-start)
+		for _, id := range taskIDs[start:end] {
+			ids = append(ids, id)
+		}
+
+		var records []*core.Record
+		err := app.RecordQuery(collection).
+			AndWhere(dbx.In("task", ids...)).
+			AndWhere(dbx.HashExp{"action": ActionCreated}).
+			AndWhere(dbx.NewExp("json_extract(metadata, '$.template') IS NOT NULL")).
+			All(&records)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range records {
+			e := FromRecord(r)
+			if name, _ := e.Metadata["template"].(string); name != "" {
+				result[e.Task] = name
+			}
+		}
+	}
+	return result, nil
+}
+
+// ActorName returns the most specific actor name of an event.
+func (e Event) ActorName() string {
+	if e.ActorDetail != "" {
+		return e.ActorDetail
+	}
+	return e.Actor
+}
+
+// Describe summarizes an event's changes (e.g. "column: todo → done"),
+// noting the deleted task's title and the proposal a change came from.
+func (e Event) Describe() string {
+	var parts []string
+	if title, ok := e.Metadata["title"].(string); ok && e.Action == ActionDeleted {
+		parts = append(parts, fmt.Sprintf("%q", title))
+	}
+	if changes, ok := e.Changes.(map[string]any); ok {
+		parts = append(parts, describeChanges(changes)...)
+	}
+	if p, ok := e.Metadata["proposal"].(map[string]any); ok {
+		if by, ok := p["proposed_by"].(string); ok && by != "" {
+			parts = append(parts, "(proposed by "+by+")")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeChanges formats field diffs in field order.
+func describeChanges(changes map[string]any) []string {
+	keys := make([]string, 0, len(changes))
+	for k := range changes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if m, ok := changes[k].(map[string]any); ok {
+			if _, hasFrom := m["from"]; hasFrom {
+				parts = append(parts, fmt.Sprintf("%s: %s → %s", k, formatValue(m["from"]), formatValue(m["to"])))
+				continue
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", k, formatValue(changes[k])))
+	}
+	return parts
+}
+
+// normalize converts field values to JSON-comparable forms.
+func normalize(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func isEmpty(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case string:
+		return x == ""
+	case float64:
+		return x == 0
+	case []any:
+		return len(x) == 0
+	case map[string]any:
+		return len(x) == 0
+	}
+	return false
+}
+
+func changesOrNil(changes map[string]any) any {
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		if x == "" {
+			return "-"
+		}
+		return x
+	case []any:
+		items := make([]string, len(x))
+		for i, item := range x {
+			items[i] = formatValue(item)
+		}
+		if len(items) == 0 {
+			return "-"
+		}
+		return strings.Join(items, ",")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
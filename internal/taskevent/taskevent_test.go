@@ -0,0 +1,280 @@
+package taskevent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+// setup creates minimal tasks and task_events collections.
+func setup(t *testing.T) (*pocketbase.PocketBase, *core.Collection) {
+	t.Helper()
+
+	app := testutil.NewTestApp(t)
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "column"},
+		&core.TextField{Name: "priority"},
+		&core.TextField{Name: "board"},
+		&core.NumberField{Name: "position"},
+		&core.JSONField{Name: "labels"},
+	)
+	testutil.CreateTaskEventsCollection(t, app)
+	return app, tasks
+}
+
+func newTask(t *testing.T, app *pocketbase.PocketBase, tasks *core.Collection, title string) *core.Record {
+	t.Helper()
+
+	task := core.NewRecord(tasks)
+	task.Set("title", title)
+	task.Set("column", "todo")
+	task.Set("priority", "medium")
+	task.Set("board", "board1")
+	require.NoError(t, app.Save(task))
+
+	saved, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	return saved
+}
+
+func TestForSave_UsesQueuedEvents(t *testing.T) {
+	app, tasks := setup(t)
+	original := newTask(t, app, tasks, "Fix bug")
+
+	updated := original.Fresh()
+	updated.Set("column", "done")
+	Add(updated, ActionMoved, "user", "ramtin",
+		map[string]any{"column": map[string]any{"from": "todo", "to": "done"}},
+		map[string]any{"proposal": map[string]any{"proposed_by": "claude"}},
+	)
+
+	events := ForSave(original, updated)
+	require.Len(t, events, 1)
+	assert.Equal(t, original.Id, events[0].Task)
+	assert.Equal(t, ActionMoved, events[0].Action)
+	assert.Equal(t, "user", events[0].Actor)
+	assert.Equal(t, "ramtin", events[0].ActorName())
+	assert.Equal(t, "board1", events[0].Board)
+	assert.False(t, events[0].Timestamp.IsZero())
+	assert.Equal(t, "column: todo → done, (proposed by claude)", events[0].Describe())
+
+	// Queued events without changes take the field diff
+	updated = original.Fresh()
+	updated.Set("priority", "high")
+	Add(updated, "", "", "", nil, map[string]any{"template": "bug"})
+
+	events = ForSave(original, updated)
+	require.Len(t, events, 1)
+	assert.Equal(t, ActionUpdated, events[0].Action)
+	assert.Equal(t, DefaultActor, events[0].Actor)
+	assert.Equal(t, "priority: medium → high", events[0].Describe())
+}
+
+func TestForSave_DiffWithoutQueuedEvents(t *testing.T) {
+	app, tasks := setup(t)
+	original := newTask(t, app, tasks, "Fix bug")
+
+	// Position-only changes are not logged
+	updated := original.Fresh()
+	updated.Set("position", 5000)
+	assert.Nil(t, ForSave(original, updated))
+
+	updated.Set("column", "in_progress")
+	updated.Set("labels", []string{"ui"})
+	SetActor(updated, "api", "ci")
+
+	events := ForSave(original, updated)
+	require.Len(t, events, 1)
+	assert.Equal(t, ActionMoved, events[0].Action)
+	assert.Equal(t, "api", events[0].Actor)
+	assert.Equal(t, "ci", events[0].ActorDetail)
+	assert.Equal(t, "column: todo → in_progress, labels: - → ui", events[0].Describe())
+
+	created := ForSave(nil, original)
+	require.Len(t, created, 1)
+	assert.Equal(t, ActionCreated, created[0].Action)
+	assert.Equal(t, DefaultActor, created[0].Actor)
+	assert.Nil(t, created[0].Changes)
+}
+
+func TestForDelete(t *testing.T) {
+	app, tasks := setup(t)
+	task := newTask(t, app, tasks, "Obsolete")
+	SetActor(task, "cli", "ramtin")
+
+	e := ForDelete(task)
+	assert.Equal(t, ActionDeleted, e.Action)
+	assert.Equal(t, "ramtin", e.ActorName())
+	assert.Equal(t, `"Obsolete"`, e.Describe())
+}
+
+func TestPending_DecodesRequestBody(t *testing.T) {
+	app, tasks := setup(t)
+	task := newTask(t, app, tasks, "Fix bug")
+	assert.Empty(t, Pending(task))
+
+	// Events sent by an API client arrive as decoded JSON
+	task.Set(PendingKey, []any{
+		map[string]any{
+			"action":       "blocked",
+			"actor":        "cli",
+			"actor_detail": "claude",
+			"timestamp":    "2025-01-15T10:00:00Z",
+		},
+	})
+	events := Pending(task)
+	require.Len(t, events, 1)
+	assert.Equal(t, "blocked", events[0].Action)
+	assert.Equal(t, "claude", events[0].ActorName())
+	assert.Equal(t, time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), events[0].Timestamp.UTC())
+
+	Clear(task)
+	assert.Empty(t, Pending(task))
+	actor, detail := ActorOf(task)
+	assert.Equal(t, "cli", actor, "the last event's actor is kept for later hooks")
+	assert.Equal(t, "claude", detail)
+}
+
+func TestBind_WritesQueuedEventsOnce(t *testing.T) {
+	app, tasks := setup(t)
+	Bind(app)
+
+	task := core.NewRecord(tasks)
+	task.Set("title", "Fix bug")
+	task.Set("column", "todo")
+	task.Set("board", "board1")
+	Add(task, ActionCreated, "cli", "claude", nil, map[string]any{"template": "bug"})
+	require.NoError(t, app.Save(task))
+	assert.Empty(t, Pending(task), "written events are cleared")
+
+	task.Set("column", "in_progress")
+	Add(task, "blocked", "cli", "claude", nil, nil)
+	Add(task, ActionMoved, "tui", "", nil, nil)
+	require.NoError(t, app.Save(task))
+
+	events, err := ForTask(app, task.Id)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, ActionCreated, events[0].Action)
+	assert.Equal(t, "bug", events[0].Metadata["template"])
+	assert.Equal(t, "blocked", events[1].Action)
+	assert.Equal(t, ActionMoved, events[2].Action)
+	assert.Equal(t, "tui", events[2].Actor)
+
+	require.NoError(t, app.Delete(task))
+	events, err = ForTask(app, task.Id)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	assert.Equal(t, ActionDeleted, events[3].Action)
+}
+
+func TestTransitions(t *testing.T) {
+	app, _ := setup(t)
+	base := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	save := func(task string, changes any, hours int) {
+		require.NoError(t, Save(app, Event{
+			Task:      task,
+			Action:    ActionUpdated,
+			Actor:     "cli",
+			Changes:   changes,
+			Timestamp: base.Add(time.Duration(hours) * time.Hour),
+		}))
+	}
+	move := func(from, to string) map[string]any {
+		return map[string]any{"column": map[string]any{"from": from, "to": to}}
+	}
+	save("t1", move("in_progress", "done"), 5)
+	save("t1", move("todo", "in_progress"), 1)
+	save("t1", map[string]any{"priority": map[string]any{"from": "low", "to": "high"}}, 2)
+	save("t1", nil, 3)
+	save("t2", move("todo", "review"), 4)
+	save("t3", move("todo", "done"), 4)
+
+	transitions, err := Transitions(app, []string{"t1", "t2"})
+	require.NoError(t, err)
+	require.Len(t, transitions, 2)
+	require.Len(t, transitions["t1"], 2, "only column changes are transitions")
+	assert.Equal(t, "in_progress", transitions["t1"][0].To, "transitions are oldest first")
+	assert.Equal(t, base.Add(time.Hour), transitions["t1"][0].At.UTC())
+	assert.Equal(t, "done", transitions["t1"][1].To)
+	assert.Equal(t, "review", transitions["t2"][0].To)
+
+	none, err := Transitions(app, nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestTemplates(t *testing.T) {
+	app, _ := setup(t)
+	save := func(task, action string, metadata map[string]any) {
+		require.NoError(t, Save(app, Event{Task: task, Action: action, Actor: "cli", Metadata: metadata}))
+	}
+	save("t1", ActionCreated, map[string]any{"template": "bug"})
+	save("t2", ActionCreated, map[string]any{"template": "release"})
+	save("t3", ActionCreated, nil)
+	save("t4", ActionUpdated, map[string]any{"template": "bug"})
+
+	templates, err := Templates(app, []string{"t1", "t3", "t4"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"t1": "bug"}, templates,
+		"only the created events of the given tasks count")
+}
+
+func TestQuery_Filters(t *testing.T) {
+	app, _ := setup(t)
+	base := time.Now().Add(-10 * 24 * time.Hour)
+
+	save := func(task, board, actor, detail string, daysAgo int) {
+		require.NoError(t, Save(app, Event{
+			Task:        task,
+			Board:       board,
+			Action:      ActionUpdated,
+			Actor:       actor,
+			ActorDetail: detail,
+			Timestamp:   base.Add(time.Duration(10-daysAgo) * 24 * time.Hour),
+		}))
+	}
+	save("t1", "b1", "cli", "", 9)
+	save("t1", "b1", "agent", "Claude", 5)
+	save("t2", "b1", "tui", "", 3)
+	save("t3", "b2", "cli", "claude", 1)
+
+	all, err := Query(app, Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	assert.True(t, all[0].Timestamp.Before(all[3].Timestamp), "events are oldest first")
+
+	byTask, err := ForTask(app, "t1")
+	require.NoError(t, err)
+	assert.Len(t, byTask, 2)
+
+	byBoard, err := Query(app, Filter{Board: "b1"})
+	require.NoError(t, err)
+	assert.Len(t, byBoard, 3)
+
+	byActor, err := Query(app, Filter{Actor: "claude"})
+	require.NoError(t, err)
+	assert.Len(t, byActor, 2)
+
+	byKind, err := Query(app, Filter{Actor: "cli"})
+	require.NoError(t, err)
+	assert.Len(t, byKind, 2)
+
+	recent, err := Query(app, Filter{Since: time.Now().Add(-4 * 24 * time.Hour)})
+	require.NoError(t, err)
+	assert.Len(t, recent, 2)
+
+	limited, err := Query(app, Filter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, limited, 2)
+	assert.Equal(t, "t2", limited[0].Task)
+	assert.Equal(t, "t3", limited[1].Task)
+}
@@ -0,0 +1,225 @@
+// Package tasklink manages typed relations between tasks in the task_links
+// collection.
+//
+// A link reads "<source> <type> <target>", e.g. "WRK-1 duplicates WRK-9".
+// Links are shown on both tasks, with the inverse label on the target
+// ("duplicated by"), unless they are one-way. Blocking relations are not
+// links; they stay in the blocked_by field of tasks.
+package tasklink
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// CollectionName is the name of the task links collection.
+const CollectionName = "task_links"
+
+// Link types.
+const (
+	TypeRelates    = "relates"
+	TypeDuplicates = "duplicates"
+	TypeCausedBy   = "caused_by"
+	TypeFollowUpOf = "follow_up_of"
+)
+
+// Types lists the link types in display order.
+var Types = []string{TypeRelates, TypeDuplicates, TypeCausedBy, TypeFollowUpOf}
+
+// Directions of a link as seen from a task.
+const (
+	Outgoing = "outgoing"
+	Incoming = "incoming"
+)
+
+var (
+	// ErrSelfLink is returned when linking a task to itself.
+	ErrSelfLink = errors.New("a task cannot be linked to itself")
+	// ErrExists is returned when the same link already exists.
+	ErrExists = errors.New("link already exists")
+)
+
+// labels holds the outgoing and incoming label of each type.
+var labels = map[string][2]string{
+	TypeRelates:    {"relates to", "relates to"},
+	TypeDuplicates: {"duplicates", "duplicated by"},
+	TypeCausedBy:   {"caused by", "causes"},
+	TypeFollowUpOf: {"follow-up of", "followed up by"},
+}
+
+// aliases maps accepted spellings to link types. Input is lowercased and
+// dashes and spaces become underscores before the lookup.
+var aliases = map[string]string{
+	"relates":      TypeRelates,
+	"relates_to":   TypeRelates,
+	"related":      TypeRelates,
+	"related_to":   TypeRelates,
+	"duplicates":   TypeDuplicates,
+	"duplicate":    TypeDuplicates,
+	"duplicate_of": TypeDuplicates,
+	"dup":          TypeDuplicates,
+	"caused_by":    TypeCausedBy,
+	"follow_up_of": TypeFollowUpOf,
+	"follow_up":    TypeFollowUpOf,
+	"followup":     TypeFollowUpOf,
+	"followup_of":  TypeFollowUpOf,
+}
+
+// ParseType returns the link type for a name such as "duplicates",
+// "caused-by" or "follow-up-of".
+func ParseType(name string) (string, error) {
+	key := strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+	if t, ok := aliases[key]; ok {
+		return t, nil
+	}
+	return "", fmt.Errorf("invalid link type '%s', must be one of: %s", name, strings.Join(Types, ", "))
+}
+
+// Label returns how a link of the given type reads from its source
+// (outgoing) or its target (incoming).
+func Label(linkType, direction string) string {
+	l, ok := labels[linkType]
+	if !ok {
+		return linkType
+	}
+	if direction == Incoming {
+		return l[1]
+	}
+	return l[0]
+}
+
+// Link is a link as seen from one of its tasks.
+type Link struct {
+	ID        string       `json:"id"`
+	Type      string       `json:"type"`
+	Label     string       `json:"label"`
+	Direction string       `json:"direction"`
+	OneWay    bool         `json:"one_way"`
+	TaskID    string       `json:"task_id"`
+	DisplayID string       `json:"display_id"`
+	Title     string       `json:"title"`
+	Column    string       `json:"column"`
+	CreatedBy string       `json:"created_by,omitempty"`
+	Task      *core.Record `json:"-"`
+}
+
+// Create links source to target. "relates" is symmetric, so it also
+// counts as existing when the target already relates to the source.
+func Create(app core.App, source, target *core.Record, linkType string, oneWay bool, actor string) (*core.Record, error) {
+	if source.Id == target.Id {
+		return nil, ErrSelfLink
+	}
+	if _, ok := labels[linkType]; !ok {
+		return nil, fmt.Errorf("invalid link type '%s'", linkType)
+	}
+
+	existing, err := Find(app, source.Id, target.Id, linkType)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range existing {
+		if link.GetString("source") == source.Id || linkType == TypeRelates {
+			return nil, ErrExists
+		}
+	}
+
+	collection, err := app.FindCollectionByNameOrId(CollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("task_links collection not found: %w", err)
+	}
+	link := core.NewRecord(collection)
+	link.Set("source", source.Id)
+	link.Set("target", target.Id)
+	link.Set("type", linkType)
+	link.Set("one_way", oneWay)
+	link.Set("created_by", actor)
+	if err := app.Save(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// Find returns the links between two tasks in either direction. An empty
+// linkType matches all types.
+func Find(app core.App, a, b, linkType string) ([]*core.Record, error) {
+	filter := "((source = {:a} && target = {:b}) || (source = {:b} && target = {:a}))"
+	params := dbx.Params{"a": a, "b": b}
+	if linkType != "" {
+		filter += " && type = {:type}"
+		params["type"] = linkType
+	}
+	return app.FindRecordsByFilter(CollectionName, filter, "created", 0, 0, params)
+}
+
+// ForTask returns the links shown on a task: all links it is the source
+// of, and the two-way links it is the target of. Links to trashed tasks
+// are left out.
+func ForTask(app core.App, task *core.Record) ([]Link, error) {
+	records, err := app.FindRecordsByFilter(
+		CollectionName,
+		"source = {:id} || (target = {:id} && one_way = false)",
+		"created",
+		0,
+		0,
+		dbx.Params{"id": task.Id},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]Link, 0, len(records))
+	for _, record := range records {
+		direction, otherID := Outgoing, record.GetString("target")
+		if record.GetString("source") != task.Id {
+			direction, otherID = Incoming, record.GetString("source")
+		}
+		other, err := app.FindRecordById("tasks", otherID)
+		if err != nil || trash.IsTrashed(other) {
+			continue
+		}
+		links = append(links, Link{
+			ID:        record.Id,
+			Type:      record.GetString("type"),
+			Label:     Label(record.GetString("type"), direction),
+			Direction: direction,
+			OneWay:    record.GetBool("one_way"),
+			TaskID:    otherID,
+			DisplayID: board.TaskDisplayID(app, other),
+			Title:     other.GetString("title"),
+			Column:    other.GetString("column"),
+			CreatedBy: record.GetString("created_by"),
+			Task:      other,
+		})
+	}
+	return links, nil
+}
+
+// MoveComments moves the comments of one task to another, keeping their
+// authors and timestamps. It returns the number of comments moved.
+func MoveComments(app core.App, fromID, toID string) (int, error) {
+	comments, err := app.FindRecordsByFilter(
+		"comments",
+		"task = {:task}",
+		"+created",
+		0,
+		0,
+		dbx.Params{"task": fromID},
+	)
+	if err != nil {
+		return 0, err
+	}
+	for _, comment := range comments {
+		comment.Set("task", toID)
+		if err := app.Save(comment); err != nil {
+			return 0, fmt.Errorf("failed to move comment %s: %w", comment.Id, err)
+		}
+	}
+	return len(comments), nil
+}
@@ -0,0 +1,166 @@
+package tasklink
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+func setupLinkCollections(t *testing.T) (*pocketbase.PocketBase, *core.Collection) {
+	t.Helper()
+	app := testutil.NewTestApp(t)
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title"},
+		&core.DateField{Name: "deleted_at"},
+	)
+	testutil.CreateTestCollection(t, app, CollectionName,
+		&core.TextField{Name: "source"},
+		&core.TextField{Name: "target"},
+		&core.TextField{Name: "type"},
+		&core.BoolField{Name: "one_way"},
+		&core.TextField{Name: "created_by"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+	testutil.CreateTestCollection(t, app, "comments",
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "content"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+	return app, tasks
+}
+
+func createLinkTestTask(t *testing.T, app *pocketbase.PocketBase, tasks *core.Collection, title string) *core.Record {
+	t.Helper()
+	task := core.NewRecord(tasks)
+	task.Set("title", title)
+	require.NoError(t, app.Save(task))
+	return task
+}
+
+func TestParseType(t *testing.T) {
+	for input, want := range map[string]string{
+		"duplicates":   TypeDuplicates,
+		"duplicate-of": TypeDuplicates,
+		"Relates to":   TypeRelates,
+		"caused-by":    TypeCausedBy,
+		"follow-up-of": TypeFollowUpOf,
+		"followup":     TypeFollowUpOf,
+	} {
+		got, err := ParseType(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseType("blocks")
+	assert.ErrorContains(t, err, "must be one of: relates, duplicates, caused_by, follow_up_of")
+}
+
+func TestLabel(t *testing.T) {
+	assert.Equal(t, "duplicates", Label(TypeDuplicates, Outgoing))
+	assert.Equal(t, "duplicated by", Label(TypeDuplicates, Incoming))
+	assert.Equal(t, "causes", Label(TypeCausedBy, Incoming))
+	assert.Equal(t, "followed up by", Label(TypeFollowUpOf, Incoming))
+	assert.Equal(t, "relates to", Label(TypeRelates, Incoming))
+}
+
+func TestCreateAndForTask(t *testing.T) {
+	app, tasks := setupLinkCollections(t)
+	dup := createLinkTestTask(t, app, tasks, "Login fails")
+	canonical := createLinkTestTask(t, app, tasks, "Auth broken")
+	other := createLinkTestTask(t, app, tasks, "Refactor auth")
+
+	_, err := Create(app, dup, canonical, TypeDuplicates, false, "ramtin")
+	require.NoError(t, err)
+	_, err = Create(app, dup, other, TypeFollowUpOf, true, "ramtin")
+	require.NoError(t, err)
+
+	links, err := ForTask(app, dup)
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, "duplicates", links[0].Label)
+	assert.Equal(t, canonical.Id, links[0].TaskID)
+	assert.Equal(t, Outgoing, links[0].Direction)
+	assert.True(t, links[1].OneWay)
+
+	// The target sees the inverse label
+	links, err = ForTask(app, canonical)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "duplicated by", links[0].Label)
+	assert.Equal(t, Incoming, links[0].Direction)
+	assert.Equal(t, dup.Id, links[0].TaskID)
+
+	// One-way links are not shown on the target
+	links, err = ForTask(app, other)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	// Links to trashed tasks are hidden
+	canonical.Set("deleted_at", "2026-01-01 00:00:00.000Z")
+	require.NoError(t, app.Save(canonical))
+	links, err = ForTask(app, dup)
+	require.NoError(t, err)
+	assert.Len(t, links, 1)
+}
+
+func TestCreate_Validation(t *testing.T) {
+	app, tasks := setupLinkCollections(t)
+	a := createLinkTestTask(t, app, tasks, "A")
+	b := createLinkTestTask(t, app, tasks, "B")
+
+	_, err := Create(app, a, a, TypeRelates, false, "")
+	assert.True(t, errors.Is(err, ErrSelfLink))
+
+	_, err = Create(app, a, b, TypeRelates, false, "")
+	require.NoError(t, err)
+	_, err = Create(app, a, b, TypeRelates, false, "")
+	assert.True(t, errors.Is(err, ErrExists))
+	// relates is symmetric
+	_, err = Create(app, b, a, TypeRelates, false, "")
+	assert.True(t, errors.Is(err, ErrExists))
+
+	// Directed types may exist both ways
+	_, err = Create(app, a, b, TypeCausedBy, false, "")
+	require.NoError(t, err)
+	_, err = Create(app, b, a, TypeCausedBy, false, "")
+	require.NoError(t, err)
+
+	links, err := Find(app, b.Id, a.Id, "")
+	require.NoError(t, err)
+	assert.Len(t, links, 3)
+	links, err = Find(app, a.Id, b.Id, TypeCausedBy)
+	require.NoError(t, err)
+	assert.Len(t, links, 2)
+}
+
+func TestMoveComments(t *testing.T) {
+	app, tasks := setupLinkCollections(t)
+	dup := createLinkTestTask(t, app, tasks, "Dup")
+	canonical := createLinkTestTask(t, app, tasks, "Canonical")
+
+	comments, err := app.FindCollectionByNameOrId("comments")
+	require.NoError(t, err)
+	for _, content := range []string{"first", "second"} {
+		comment := core.NewRecord(comments)
+		comment.Set("task", dup.Id)
+		comment.Set("content", content)
+		require.NoError(t, app.Save(comment))
+	}
+
+	moved, err := MoveComments(app, dup.Id, canonical.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 2, moved)
+
+	remaining, err := app.FindRecordsByFilter("comments", "task = {:task}", "", 0, 0, map[string]any{"task": dup.Id})
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+	onCanonical, err := app.FindRecordsByFilter("comments", "task = {:task}", "", 0, 0, map[string]any{"task": canonical.Id})
+	require.NoError(t, err)
+	assert.Len(t, onCanonical, 2)
+}
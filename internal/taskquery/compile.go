@@ -0,0 +1,493 @@
+package taskquery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/flow"
+	"github.com/ramtinJ95/EgenSkriven/internal/sprint"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+)
+
+// Values accepted by the priority, type and by fields.
+var (
+	Priorities = []string{"low", "medium", "high", "urgent"}
+	Types      = []string{"bug", "feature", "chore"}
+	Creators   = []string{"user", "agent", "cli"}
+)
+
+// readyColumns are the columns of is:ready tasks.
+var readyColumns = []string{"todo", "backlog"}
+
+// timeNow is replaced in tests.
+var timeNow = time.Now
+
+// Task holds the fields a query filters on.
+type Task struct {
+	Column         string
+	Priority       string
+	Type           string
+	CreatedBy      string
+	CreatedByAgent string
+	Title          string
+	Description    string
+	Epic           string
+	Sprint         string
+	Parent         string
+	DueDate        string // YYYY-MM-DD, empty when unset
+	Created        string // YYYY-MM-DD
+	Updated        string // YYYY-MM-DD
+	Estimate       float64
+	Labels         []string
+	Assignees      []string
+	BlockedBy      []string
+}
+
+// TaskFromRecord returns the filter fields of a task record.
+func TaskFromRecord(record *core.Record) Task {
+	return Task{
+		Column:         record.GetString("column"),
+		Priority:       record.GetString("priority"),
+		Type:           record.GetString("type"),
+		CreatedBy:      record.GetString("created_by"),
+		CreatedByAgent: record.GetString("created_by_agent"),
+		Title:          record.GetString("title"),
+		Description:    record.GetString("description"),
+		Epic:           record.GetString("epic"),
+		Sprint:         record.GetString("sprint"),
+		Parent:         record.GetString("parent"),
+		DueDate:        day(record.GetDateTime("due_date").Time()),
+		Created:        day(record.GetDateTime("created").Time()),
+		Updated:        day(record.GetDateTime("updated").Time()),
+		Estimate:       record.GetFloat("estimate"),
+		Labels:         record.GetStringSlice("labels"),
+		Assignees:      record.GetStringSlice("assignees"),
+		BlockedBy:      record.GetStringSlice("blocked_by"),
+	}
+}
+
+func day(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// Filter is a compiled query.
+type Filter struct {
+	query Node
+	root  cond
+}
+
+// String returns the query in canonical form.
+func (f *Filter) String() string {
+	return f.query.String()
+}
+
+// Expression returns the query as a dbx expression on the tasks table.
+func (f *Filter) Expression() dbx.Expression {
+	return f.root.expr(&params{})
+}
+
+// Match reports whether a task matches the query.
+func (f *Filter) Match(t Task) bool {
+	return f.root.match(t)
+}
+
+// Options control how names in a query are resolved.
+type Options struct {
+	// BoardID scopes epic and sprint names to a board. When empty, names
+	// are looked up on every board.
+	BoardID string
+}
+
+// Compile resolves the epic, sprint, parent and "me" references of a
+// parsed query.
+func Compile(app core.App, query Node, opts Options) (*Filter, error) {
+	c := compiler{app: app, opts: opts, today: timeNow()}
+	root, err := c.node(query)
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{query: query, root: root}, nil
+}
+
+// ParseAndCompile parses and compiles a query.
+func ParseAndCompile(app core.App, input string, opts Options) (*Filter, error) {
+	query, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return Compile(app, query, opts)
+}
+
+type compiler struct {
+	app   core.App
+	opts  Options
+	today time.Time
+}
+
+func (c compiler) node(node Node) (cond, error) {
+	switch n := node.(type) {
+	case And:
+		conds, err := c.nodes(n.Nodes)
+		return andCond(conds), err
+	case Or:
+		conds, err := c.nodes(n.Nodes)
+		return orCond(conds), err
+	case Not:
+		inner, err := c.node(n.Node)
+		return notCond{inner}, err
+	case Term:
+		return c.term(n)
+	default:
+		return nil, fmt.Errorf("unknown query node %T", node)
+	}
+}
+
+func (c compiler) nodes(nodes []Node) ([]cond, error) {
+	conds := make([]cond, 0, len(nodes))
+	for _, node := range nodes {
+		cond, err := c.node(node)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+func (c compiler) term(t Term) (cond, error) {
+	switch t.Field {
+	case "column":
+		return selectTerm("column", func(t Task) string { return t.Column }, t.Values, true), nil
+	case "priority":
+		return selectTerm("priority", func(t Task) string { return t.Priority }, t.Values, true), nil
+	case "type":
+		return selectTerm("type", func(t Task) string { return t.Type }, t.Values, true), nil
+	case "by":
+		return selectTerm("created_by", func(t Task) string { return t.CreatedBy }, t.Values, true), nil
+	case "agent":
+		return selectTerm("created_by_agent", func(t Task) string { return t.CreatedByAgent }, t.Values, true), nil
+	case "epic":
+		ids, err := c.resolveEach(t.Values, c.epicIDs)
+		if err != nil {
+			return nil, err
+		}
+		return selectTerm("epic", func(t Task) string { return t.Epic }, ids, false), nil
+	case "sprint":
+		ids, err := c.resolveEach(t.Values, c.sprintIDs)
+		if err != nil {
+			return nil, err
+		}
+		return selectTerm("sprint", func(t Task) string { return t.Sprint }, ids, false), nil
+	case "parent":
+		ids, err := c.resolveEach(t.Values, c.taskIDs)
+		if err != nil {
+			return nil, err
+		}
+		return selectTerm("parent", func(t Task) string { return t.Parent }, ids, false), nil
+	case "label":
+		return listTerm("labels", func(t Task) []string { return t.Labels }, t.Values), nil
+	case "assignee":
+		names := make([]string, len(t.Values))
+		for i, v := range t.Values {
+			names[i] = v
+			if strings.EqualFold(v, "me") {
+				names[i] = config.CurrentUser()
+				if names[i] == "" {
+					return nil, errors.New("cannot determine who 'me' is; set defaults.author in ~/.config/egenskriven/config.json")
+				}
+			}
+		}
+		return listTerm("assignees", func(t Task) []string { return t.Assignees }, names), nil
+	case "due":
+		return c.dateTerm("due_date", func(t Task) string { return t.DueDate }, t)
+	case "created":
+		return c.dateTerm("created", func(t Task) string { return t.Created }, t)
+	case "updated":
+		return c.dateTerm("updated", func(t Task) string { return t.Updated }, t)
+	case "estimate":
+		return numberTerm(t)
+	case "text":
+		return textCond{columns: []string{"title", "description"}, value: strings.ToLower(t.Values[0]),
+			get: func(t Task) []string { return []string{t.Title, t.Description} }}, nil
+	case "title":
+		return textCond{columns: []string{"title"}, value: strings.ToLower(t.Values[0]),
+			get: func(t Task) []string { return []string{t.Title} }}, nil
+	case "is":
+		conds := make([]cond, 0, len(t.Values))
+		for _, v := range t.Values {
+			conds = append(conds, c.state(strings.ToLower(v)))
+		}
+		return orCond(conds), nil
+	case "has":
+		conds := make([]cond, 0, len(t.Values))
+		for _, v := range t.Values {
+			inner, err := c.term(Term{Field: canonicalHas(v), Op: OpEq, Values: []string{"none"}})
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, notCond{inner})
+		}
+		return orCond(conds), nil
+	default:
+		return nil, fmt.Errorf("unknown field '%s'", t.Field)
+	}
+}
+
+// state returns the condition of an is: value.
+func (c compiler) state(value string) cond {
+	blocked := listCond{column: "blocked_by", any: true, get: func(t Task) []string { return t.BlockedBy }}
+	done := selectTerm("column", func(t Task) string { return t.Column }, []string{flow.ColumnDone}, true)
+	parent := selectTerm("parent", func(t Task) string { return t.Parent }, []string{"none"}, false)
+	assigned := listCond{column: "assignees", any: true, get: func(t Task) []string { return t.Assignees }}
+
+	switch value {
+	case "blocked":
+		return blocked
+	case "unblocked":
+		return notCond{blocked}
+	case "ready":
+		return andCond{selectTerm("column", func(t Task) string { return t.Column }, readyColumns, true), notCond{blocked}}
+	case "open":
+		return notCond{done}
+	case "done":
+		return done
+	case "overdue":
+		due, _ := c.dateTerm("due_date", func(t Task) string { return t.DueDate }, Term{Op: OpLt, Values: []string{"today"}})
+		return andCond{due, notCond{done}}
+	case "subtask":
+		return notCond{parent}
+	case "toplevel":
+		return parent
+	case "assigned":
+		return assigned
+	default: // unassigned
+		return notCond{assigned}
+	}
+}
+
+// resolveEach resolves the values of a term, keeping "none" and "any".
+func (c compiler) resolveEach(values []string, resolve func(string) ([]string, error)) ([]string, error) {
+	var ids []string
+	for _, v := range values {
+		if isNoneOrAny(v) {
+			ids = append(ids, strings.ToLower(v))
+			continue
+		}
+		resolved, err := resolve(v)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, resolved...)
+	}
+	return ids, nil
+}
+
+// epicIDs finds epics by ID or title (case-insensitive).
+func (c compiler) epicIDs(ref string) ([]string, error) {
+	if record, err := c.app.FindRecordById("epics", ref); err == nil && !trash.IsTrashed(record) {
+		return []string{record.Id}, nil
+	}
+	exps := []dbx.Expression{
+		dbx.NewExp("LOWER(title) = {:title}", dbx.Params{"title": strings.ToLower(ref)}),
+		trash.Exclude(c.app, "epics"),
+	}
+	if c.opts.BoardID != "" {
+		exps = append(exps, dbx.HashExp{"board": c.opts.BoardID})
+	}
+	records, err := c.app.FindAllRecords("epics", exps...)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no epic found matching: %s", ref)
+	}
+	ids := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r.Id
+	}
+	return ids, nil
+}
+
+// sprintIDs finds sprints by ID, name, or "current".
+func (c compiler) sprintIDs(ref string) ([]string, error) {
+	if c.opts.BoardID != "" {
+		record, err := sprint.Resolve(c.app, c.opts.BoardID, ref)
+		if err != nil {
+			return nil, err
+		}
+		return []string{record.Id}, nil
+	}
+
+	boards, err := c.app.FindAllRecords("boards", trash.Exclude(c.app, "boards"))
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, b := range boards {
+		if record, err := sprint.Resolve(c.app, b.Id, ref); err == nil {
+			ids = append(ids, record.Id)
+		}
+	}
+	if len(ids) == 0 {
+		if strings.EqualFold(ref, sprint.RefCurrent) {
+			return nil, sprint.ErrNoActiveSprint
+		}
+		return nil, fmt.Errorf("no sprint found matching: %s", ref)
+	}
+	return ids, nil
+}
+
+// taskIDs finds a task by ID or display ID (e.g. WRK-12).
+func (c compiler) taskIDs(ref string) ([]string, error) {
+	if record, err := c.app.FindRecordById("tasks", ref); err == nil {
+		return []string{record.Id}, nil
+	}
+	if prefix, seq, err := board.ParseDisplayID(ref); err == nil {
+		boards, err := c.app.FindAllRecords("boards", dbx.NewExp("UPPER(prefix) = {:prefix}", dbx.Params{"prefix": prefix}))
+		if err == nil && len(boards) > 0 {
+			record, err := c.app.FindFirstRecordByFilter("tasks", "board = {:board} && seq = {:seq}",
+				dbx.Params{"board": boards[0].Id, "seq": seq})
+			if err == nil {
+				return []string{record.Id}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no task found matching: %s", ref)
+}
+
+// dateTerm compiles a date term. Dates compare by day: due:<7d is due
+// before the day a week from today, and due:2025-03-01 is that whole day.
+func (c compiler) dateTerm(column string, get func(Task) string, t Term) (cond, error) {
+	conds := make([]cond, 0, len(t.Values))
+	for _, v := range t.Values {
+		switch {
+		case strings.EqualFold(v, "none"):
+			conds = append(conds, dateCond{column: column, get: get, none: true})
+			continue
+		case strings.EqualFold(v, "any"):
+			conds = append(conds, notCond{dateCond{column: column, get: get, none: true}})
+			continue
+		}
+		d, err := parseDay(v, c.today)
+		if err != nil {
+			return nil, err
+		}
+		next := d.AddDate(0, 0, 1).Format("2006-01-02")
+		day := d.Format("2006-01-02")
+		switch t.Op {
+		case OpLt:
+			conds = append(conds, dateCond{column: column, get: get, before: day})
+		case OpLe:
+			conds = append(conds, dateCond{column: column, get: get, before: next})
+		case OpGt:
+			conds = append(conds, dateCond{column: column, get: get, from: next})
+		case OpGe:
+			conds = append(conds, dateCond{column: column, get: get, from: day})
+		default:
+			conds = append(conds, dateCond{column: column, get: get, from: day, before: next})
+		}
+	}
+	return orCond(conds), nil
+}
+
+// parseDay parses today, tomorrow, yesterday, an offset from today such as
+// 7d, -2w or 3m, or a YYYY-MM-DD date.
+func parseDay(v string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch strings.ToLower(v) {
+	case "today", "now":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, nil
+	}
+	if len(v) >= 2 {
+		n, err := strconv.Atoi(strings.TrimPrefix(v[:len(v)-1], "+"))
+		if err == nil {
+			switch v[len(v)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n), nil
+			case 'm':
+				return today.AddDate(0, n, 0), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date '%s' (use YYYY-MM-DD, today, or an offset like 7d, -2w, 1m)", v)
+}
+
+func parseNumber(v string) (float64, error) {
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number '%s'", v)
+	}
+	return n, nil
+}
+
+func numberTerm(t Term) (cond, error) {
+	conds := make([]cond, 0, len(t.Values))
+	for _, v := range t.Values {
+		switch {
+		case strings.EqualFold(v, "none"):
+			conds = append(conds, numberCond{column: "estimate", op: OpEq, value: 0})
+			continue
+		case strings.EqualFold(v, "any"):
+			conds = append(conds, numberCond{column: "estimate", op: OpGt, value: 0})
+			continue
+		}
+		n, err := parseNumber(v)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, numberCond{column: "estimate", op: t.Op, value: n})
+	}
+	return orCond(conds), nil
+}
+
+func selectTerm(column string, get func(Task) string, values []string, fold bool) cond {
+	s := selectCond{column: column, get: get, fold: fold}
+	for _, v := range values {
+		switch {
+		case strings.EqualFold(v, "none"):
+			s.none = true
+		case strings.EqualFold(v, "any"):
+			s.any = true
+		case fold:
+			s.values = append(s.values, strings.ToLower(v))
+		default:
+			s.values = append(s.values, v)
+		}
+	}
+	return s
+}
+
+func listTerm(column string, get func(Task) []string, values []string) cond {
+	l := listCond{column: column, get: get}
+	for _, v := range values {
+		switch {
+		case strings.EqualFold(v, "none"):
+			l.none = true
+		case strings.EqualFold(v, "any"):
+			l.any = true
+		default:
+			l.values = append(l.values, strings.ToLower(v))
+		}
+	}
+	return l
+}
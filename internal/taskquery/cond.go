@@ -0,0 +1,307 @@
+package taskquery
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+)
+
+// cond is a compiled query node. expr and match must select the same
+// tasks.
+type cond interface {
+	expr(p *params) dbx.Expression
+	match(t Task) bool
+}
+
+// params names the placeholders of an expression. The prefix keeps them
+// apart from the parameters of other filters in the same query.
+type params struct {
+	n int
+}
+
+func (p *params) next() string {
+	p.n++
+	return fmt.Sprintf("tq%d", p.n)
+}
+
+// in returns "{:tq1}, {:tq2}" and the parameters for values.
+func (p *params) in(values []string) (string, dbx.Params) {
+	names := make([]string, len(values))
+	bound := dbx.Params{}
+	for i, v := range values {
+		name := p.next()
+		names[i] = "{:" + name + "}"
+		bound[name] = v
+	}
+	return strings.Join(names, ", "), bound
+}
+
+type andCond []cond
+
+func (c andCond) expr(p *params) dbx.Expression {
+	if len(c) == 0 {
+		return dbx.NewExp("1=1")
+	}
+	exps := make([]dbx.Expression, len(c))
+	for i, child := range c {
+		exps[i] = child.expr(p)
+	}
+	return dbx.And(exps...)
+}
+
+func (c andCond) match(t Task) bool {
+	for _, child := range c {
+		if !child.match(t) {
+			return false
+		}
+	}
+	return true
+}
+
+type orCond []cond
+
+func (c orCond) expr(p *params) dbx.Expression {
+	if len(c) == 1 {
+		return c[0].expr(p)
+	}
+	exps := make([]dbx.Expression, len(c))
+	for i, child := range c {
+		exps[i] = child.expr(p)
+	}
+	return dbx.Or(exps...)
+}
+
+func (c orCond) match(t Task) bool {
+	for _, child := range c {
+		if child.match(t) {
+			return true
+		}
+	}
+	return false
+}
+
+type notCond struct {
+	inner cond
+}
+
+func (c notCond) expr(p *params) dbx.Expression {
+	return notExp{c.inner.expr(p)}
+}
+
+func (c notCond) match(t Task) bool {
+	return !c.inner.match(t)
+}
+
+// notExp negates an expression, treating NULL as false so that negated
+// terms also match tasks with empty fields.
+type notExp struct {
+	e dbx.Expression
+}
+
+func (n notExp) Build(db *dbx.DB, params dbx.Params) string {
+	sql := n.e.Build(db, params)
+	if sql == "" {
+		return ""
+	}
+	return "NOT COALESCE((" + sql + "), 0)"
+}
+
+// selectCond matches a single-value field.
+type selectCond struct {
+	column string
+	get    func(Task) string
+	values []string
+	fold   bool // compare case-insensitively
+	none   bool // also match an empty field
+	any    bool // also match any non-empty value
+}
+
+func (c selectCond) expr(p *params) dbx.Expression {
+	var exps []dbx.Expression
+	if len(c.values) > 0 {
+		column := "[[" + c.column + "]]"
+		if c.fold {
+			column = "LOWER(" + column + ")"
+		}
+		list, bound := p.in(c.values)
+		exps = append(exps, dbx.NewExp(column+" IN ("+list+")", bound))
+	}
+	if c.none {
+		exps = append(exps, dbx.NewExp("([["+c.column+"]] = '' OR [["+c.column+"]] IS NULL)"))
+	}
+	if c.any {
+		exps = append(exps, dbx.NewExp("([["+c.column+"]] != '' AND [["+c.column+"]] IS NOT NULL)"))
+	}
+	return dbx.Or(exps...)
+}
+
+func (c selectCond) match(t Task) bool {
+	value := c.get(t)
+	if value == "" {
+		return c.none
+	}
+	if c.any {
+		return true
+	}
+	for _, v := range c.values {
+		if value == v || c.fold && strings.EqualFold(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// listCond matches a JSON list field when it contains any of the values
+// (case-insensitive).
+type listCond struct {
+	column string
+	get    func(Task) []string
+	values []string // lowercase
+	none   bool     // also match an empty list
+	any    bool     // also match any non-empty list
+}
+
+func (c listCond) expr(p *params) dbx.Expression {
+	column := "[[" + c.column + "]]"
+	var exps []dbx.Expression
+	if len(c.values) > 0 {
+		list, bound := p.in(c.values)
+		exps = append(exps, dbx.NewExp(
+			"(json_valid("+column+") AND EXISTS (SELECT 1 FROM json_each("+column+") WHERE LOWER(json_each.value) IN ("+list+")))",
+			bound,
+		))
+	}
+	if c.none {
+		exps = append(exps, dbx.NewExp("("+column+" IS NULL OR "+column+" IN ('', 'null', '[]'))"))
+	}
+	if c.any {
+		exps = append(exps, dbx.NewExp("(json_valid("+column+") AND json_array_length("+column+") > 0)"))
+	}
+	return dbx.Or(exps...)
+}
+
+func (c listCond) match(t Task) bool {
+	items := c.get(t)
+	if len(items) == 0 {
+		return c.none
+	}
+	if c.any {
+		return true
+	}
+	for _, item := range items {
+		for _, v := range c.values {
+			if strings.EqualFold(item, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dateCond matches a date field on or after from and before before
+// (YYYY-MM-DD, either may be empty), or an empty field when none is set.
+type dateCond struct {
+	column string
+	get    func(Task) string
+	from   string
+	before string
+	none   bool
+}
+
+func (c dateCond) expr(p *params) dbx.Expression {
+	column := "[[" + c.column + "]]"
+	if c.none {
+		return dbx.NewExp("(" + column + " = '' OR " + column + " IS NULL)")
+	}
+	sql := column + " != ''"
+	bound := dbx.Params{}
+	if c.from != "" {
+		name := p.next()
+		sql += " AND " + column + " >= {:" + name + "}"
+		bound[name] = c.from
+	}
+	if c.before != "" {
+		name := p.next()
+		sql += " AND " + column + " < {:" + name + "}"
+		bound[name] = c.before
+	}
+	return dbx.NewExp("("+sql+")", bound)
+}
+
+func (c dateCond) match(t Task) bool {
+	value := c.get(t)
+	if c.none || value == "" {
+		return c.none && value == ""
+	}
+	if len(value) > 10 {
+		value = value[:10]
+	}
+	return (c.from == "" || value >= c.from) && (c.before == "" || value < c.before)
+}
+
+// numberCond compares a number field; unset counts as 0.
+type numberCond struct {
+	column string
+	op     Op
+	value  float64
+}
+
+func (c numberCond) expr(p *params) dbx.Expression {
+	op := string(c.op)
+	if c.op == OpEq {
+		op = "="
+	}
+	name := p.next()
+	return dbx.NewExp("COALESCE([["+c.column+"]], 0) "+op+" {:"+name+"}", dbx.Params{name: c.value})
+}
+
+func (c numberCond) match(t Task) bool {
+	v := t.Estimate
+	switch c.op {
+	case OpLt:
+		return v < c.value
+	case OpLe:
+		return v <= c.value
+	case OpGt:
+		return v > c.value
+	case OpGe:
+		return v >= c.value
+	default:
+		return v == c.value
+	}
+}
+
+// textCond matches when any of the columns contains value
+// (case-insensitive).
+type textCond struct {
+	columns []string
+	get     func(Task) []string
+	value   string // lowercase
+}
+
+func (c textCond) expr(p *params) dbx.Expression {
+	name := p.next()
+	parts := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		parts[i] = "LOWER([[" + column + "]]) LIKE {:" + name + "} ESCAPE '\\'"
+	}
+	return dbx.NewExp("("+strings.Join(parts, " OR ")+")",
+		dbx.Params{name: "%" + escapeLike(c.value) + "%"})
+}
+
+func (c textCond) match(t Task) bool {
+	for _, text := range c.get(t) {
+		if strings.Contains(strings.ToLower(text), c.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeLike escapes the LIKE wildcards of s.
+func escapeLike(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "%", `\%`)
+	return strings.ReplaceAll(s, "_", `\_`)
+}
@@ -0,0 +1,596 @@
+// Package taskquery implements the task query language shared by
+// 'list --query', the TUI filter bar, and saved views.
+//
+// A query is a list of terms that must all match:
+//
+//	priority:high,urgent label:backend -label:wontfix due:<7d is:blocked epic:"Auth" text:"token"
+//
+// A term is field:value; comma-separated values match any of them, a
+// leading '-' negates a term or group, OR combines terms, and parentheses
+// group them. Words without a field search the title and description.
+// Parse turns a query into an AST, and Compile resolves it against the
+// database into a parameterized dbx expression and an in-memory predicate
+// that select the same tasks.
+package taskquery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Op is the comparison of a term.
+type Op string
+
+// Comparison operators. Only date and number fields accept the ordering
+// operators.
+const (
+	OpEq Op = ":"
+	OpLt Op = "<"
+	OpLe Op = "<="
+	OpGt Op = ">"
+	OpGe Op = ">="
+)
+
+// Field kinds.
+const (
+	kindSelect = iota // single value, e.g. priority
+	kindList          // list of values, e.g. labels
+	kindDate          // date with ordering operators
+	kindNumber        // number with ordering operators
+	kindText          // substring search
+	kindIs            // is:<state>
+	kindHas           // has:<field>
+)
+
+// fields maps the canonical field names to their kind.
+var fields = map[string]int{
+	"column":   kindSelect,
+	"priority": kindSelect,
+	"type":     kindSelect,
+	"by":       kindSelect,
+	"agent":    kindSelect,
+	"epic":     kindSelect,
+	"sprint":   kindSelect,
+	"parent":   kindSelect,
+	"label":    kindList,
+	"assignee": kindList,
+	"due":      kindDate,
+	"created":  kindDate,
+	"updated":  kindDate,
+	"estimate": kindNumber,
+	"text":     kindText,
+	"title":    kindText,
+	"is":       kindIs,
+	"has":      kindHas,
+}
+
+// aliases maps alternative field names to canonical ones.
+var aliases = map[string]string{
+	"col":        "column",
+	"status":     "column",
+	"in":         "column",
+	"p":          "priority",
+	"prio":       "priority",
+	"t":          "type",
+	"created_by": "by",
+	"created-by": "by",
+	"l":          "label",
+	"labels":     "label",
+	"assignees":  "assignee",
+	"assigned":   "assignee",
+	"due_date":   "due",
+	"est":        "estimate",
+	"q":          "text",
+}
+
+// isValues and hasValues list the accepted values of is: and has:.
+var (
+	isValues  = []string{"blocked", "unblocked", "ready", "open", "done", "overdue", "subtask", "toplevel", "assigned", "unassigned"}
+	hasValues = []string{"due", "epic", "sprint", "parent", "estimate", "label", "assignee"}
+)
+
+// Node is a node of a parsed query.
+type Node interface {
+	String() string
+}
+
+// And matches when all of its nodes match. An empty And matches every
+// task.
+type And struct {
+	Nodes []Node
+}
+
+// Or matches when any of its nodes matches.
+type Or struct {
+	Nodes []Node
+}
+
+// Not negates a node.
+type Not struct {
+	Node Node
+}
+
+// Term compares a field with one or more values.
+type Term struct {
+	Field  string
+	Op     Op
+	Values []string
+}
+
+func (n And) String() string {
+	parts := make([]string, len(n.Nodes))
+	for i, node := range n.Nodes {
+		if _, ok := node.(Or); ok && len(n.Nodes) > 1 {
+			parts[i] = "(" + node.String() + ")"
+		} else {
+			parts[i] = node.String()
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (n Or) String() string {
+	parts := make([]string, len(n.Nodes))
+	for i, node := range n.Nodes {
+		parts[i] = node.String()
+	}
+	return strings.Join(parts, " OR ")
+}
+
+func (n Not) String() string {
+	switch n.Node.(type) {
+	case Term:
+		return "-" + n.Node.String()
+	default:
+		return "-(" + n.Node.String() + ")"
+	}
+}
+
+func (n Term) String() string {
+	values := make([]string, len(n.Values))
+	for i, v := range n.Values {
+		values[i] = quote(v)
+	}
+	op := string(n.Op)
+	if n.Op != OpEq {
+		op = ":" + op
+	}
+	return n.Field + op + strings.Join(values, ",")
+}
+
+// quote quotes a value when it would not be read back as one word.
+func quote(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t,()\":") {
+		return `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
+	}
+	return v
+}
+
+// SyntaxError is returned for queries that cannot be parsed.
+type SyntaxError struct {
+	Pos int // byte offset in the query
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos+1, e.Msg)
+}
+
+// Parse parses a query. An empty query parses to an empty And.
+func Parse(input string) (Node, error) {
+	p := &parser{input: input}
+	if err := p.lex(); err != nil {
+		return nil, err
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		tok := p.tokens[p.pos]
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected '%s'", tok.text)}
+	}
+	if _, ok := node.(And); !ok {
+		node = And{Nodes: []Node{node}}
+	}
+	return node, nil
+}
+
+// IsQuery reports whether input uses the query syntax, that is whether it
+// has a field:value term, rather than being plain search text.
+func IsQuery(input string) bool {
+	node, err := Parse(input)
+	if err != nil {
+		// Field-like words that do not parse are still meant as a query
+		return looksLikeQuery(input)
+	}
+	return hasFieldTerm(node)
+}
+
+func hasFieldTerm(node Node) bool {
+	switch n := node.(type) {
+	case And:
+		for _, c := range n.Nodes {
+			if hasFieldTerm(c) {
+				return true
+			}
+		}
+	case Or:
+		return true
+	case Not:
+		return true
+	case Term:
+		return n.Field != "text"
+	}
+	return false
+}
+
+func looksLikeQuery(input string) bool {
+	for _, word := range strings.Fields(input) {
+		name, _, ok := strings.Cut(strings.TrimPrefix(word, "-"), ":")
+		if ok && canonicalField(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalField returns the canonical name of a field, or "" when the
+// field is unknown.
+func canonicalField(name string) string {
+	name = strings.ToLower(name)
+	if alias, ok := aliases[name]; ok {
+		return alias
+	}
+	if _, ok := fields[name]; ok {
+		return name
+	}
+	return ""
+}
+
+// Fields returns the canonical field names, sorted.
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ========== Lexer ==========
+
+type tokenKind int
+
+const (
+	tokTerm tokenKind = iota
+	tokNot
+	tokOr
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+	text string
+	term Term
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) lex() error {
+	s := p.input
+	i := 0
+	for i < len(s) {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, token{kind: tokLParen, pos: i, text: "("})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{kind: tokRParen, pos: i, text: ")"})
+			i++
+		case c == '-' && i+1 < len(s) && !unicode.IsSpace(rune(s[i+1])) && s[i+1] != ')':
+			p.tokens = append(p.tokens, token{kind: tokNot, pos: i, text: "-"})
+			i++
+		default:
+			start := i
+			tok, next, err := lexTerm(s, i)
+			if err != nil {
+				return err
+			}
+			if tok.kind == tokOr {
+				tok.pos = start
+			}
+			p.tokens = append(p.tokens, tok)
+			i = next
+		}
+	}
+	return nil
+}
+
+// lexTerm reads a field:value term, a quoted phrase, a bare word, or OR.
+func lexTerm(s string, start int) (token, int, error) {
+	if s[start] == '"' {
+		value, next, err := lexQuoted(s, start)
+		if err != nil {
+			return token{}, 0, err
+		}
+		return token{kind: tokTerm, pos: start, text: s[start:next],
+			term: Term{Field: "text", Op: OpEq, Values: []string{value}}}, next, nil
+	}
+
+	i := start
+	for i < len(s) && isWordChar(s[i]) {
+		i++
+	}
+	word := s[start:i]
+
+	if i < len(s) && s[i] == ':' && word != "" {
+		field := canonicalField(word)
+		if field == "" {
+			return token{}, 0, &SyntaxError{Pos: start,
+				Msg: fmt.Sprintf("unknown field '%s' (fields: %s)", word, strings.Join(Fields(), ", "))}
+		}
+		term, next, err := lexValues(s, i+1, field)
+		if err != nil {
+			return token{}, 0, err
+		}
+		return token{kind: tokTerm, pos: start, text: s[start:next], term: term}, next, nil
+	}
+
+	// A bare word runs to the next space or parenthesis
+	for i < len(s) && !unicode.IsSpace(rune(s[i])) && s[i] != '(' && s[i] != ')' {
+		i++
+	}
+	word = s[start:i]
+	if word == "OR" || word == "|" {
+		return token{kind: tokOr, text: word}, i, nil
+	}
+	return token{kind: tokTerm, pos: start, text: word,
+		term: Term{Field: "text", Op: OpEq, Values: []string{word}}}, i, nil
+}
+
+// lexValues reads the comparison and the comma-separated values of a term.
+func lexValues(s string, i int, field string) (Term, int, error) {
+	term := Term{Field: field, Op: OpEq}
+	for _, op := range []Op{OpLe, OpGe, OpLt, OpGt} {
+		if strings.HasPrefix(s[i:], string(op)) {
+			term.Op = op
+			i += len(op)
+			break
+		}
+	}
+	if term.Op != OpEq && fields[field] != kindDate && fields[field] != kindNumber {
+		return term, 0, &SyntaxError{Pos: i - len(term.Op),
+			Msg: fmt.Sprintf("'%s' cannot be compared with %s", field, term.Op)}
+	}
+
+	for {
+		var value string
+		if i < len(s) && s[i] == '"' {
+			v, next, err := lexQuoted(s, i)
+			if err != nil {
+				return term, 0, err
+			}
+			value, i = v, next
+		} else {
+			start := i
+			for i < len(s) && !unicode.IsSpace(rune(s[i])) && s[i] != ',' && s[i] != '(' && s[i] != ')' {
+				i++
+			}
+			value = s[start:i]
+		}
+		if value == "" {
+			return term, 0, &SyntaxError{Pos: i, Msg: fmt.Sprintf("missing value for '%s'", field)}
+		}
+		term.Values = append(term.Values, value)
+		if i < len(s) && s[i] == ',' {
+			i++
+			continue
+		}
+		break
+	}
+
+	if term.Op != OpEq && len(term.Values) > 1 {
+		return term, 0, &SyntaxError{Pos: i, Msg: fmt.Sprintf("'%s%s' takes a single value", field, term.Op)}
+	}
+	if err := validateTerm(term); err != nil {
+		return term, 0, &SyntaxError{Pos: i, Msg: err.Error()}
+	}
+	return term, i, nil
+}
+
+// lexQuoted reads a double-quoted string; \" escapes a quote.
+func lexQuoted(s string, start int) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '"':
+			b.WriteByte('"')
+			i++
+		case s[i] == '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, &SyntaxError{Pos: start, Msg: "unterminated quote"}
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// validateTerm checks values that do not need the database.
+func validateTerm(term Term) error {
+	switch fields[term.Field] {
+	case kindIs:
+		for _, v := range term.Values {
+			if !containsFold(isValues, v) {
+				return fmt.Errorf("unknown state 'is:%s' (states: %s)", v, strings.Join(isValues, ", "))
+			}
+		}
+	case kindHas:
+		for _, v := range term.Values {
+			if !containsFold(hasValues, canonicalHas(v)) {
+				return fmt.Errorf("unknown field 'has:%s' (fields: %s)", v, strings.Join(hasValues, ", "))
+			}
+		}
+	case kindDate:
+		for _, v := range term.Values {
+			if isNoneOrAny(v) {
+				if term.Op != OpEq {
+					return fmt.Errorf("'%s' cannot be compared with %s", v, term.Op)
+				}
+				continue
+			}
+			if _, err := parseDay(v, timeNow()); err != nil {
+				return err
+			}
+		}
+	case kindNumber:
+		for _, v := range term.Values {
+			if isNoneOrAny(v) && term.Op == OpEq {
+				continue
+			}
+			if _, err := parseNumber(v); err != nil {
+				return err
+			}
+		}
+	}
+	if term.Field == "priority" || term.Field == "type" || term.Field == "by" {
+		valid := map[string][]string{
+			"priority": Priorities,
+			"type":     Types,
+			"by":       Creators,
+		}[term.Field]
+		for _, v := range term.Values {
+			if !containsFold(valid, v) {
+				return fmt.Errorf("invalid %s '%s', must be one of: %s", term.Field, v, strings.Join(valid, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// canonicalHas maps has: values through the field aliases.
+func canonicalHas(v string) string {
+	if field := canonicalField(v); field != "" {
+		return field
+	}
+	return strings.ToLower(v)
+}
+
+// ========== Parser ==========
+
+func (p *parser) peek() *token {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{first}
+	for tok := p.peek(); tok != nil && tok.kind == tokOr; tok = p.peek() {
+		p.pos++
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if len(next.(And).Nodes) == 0 {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "OR needs a term on both sides"}
+		}
+		nodes = append(nodes, next)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	if len(first.(And).Nodes) == 0 {
+		return nil, &SyntaxError{Pos: 0, Msg: "OR needs a term on both sides"}
+	}
+	for i, n := range nodes {
+		nodes[i] = simplify(n)
+	}
+	return Or{Nodes: nodes}, nil
+}
+
+// parseAnd parses terms up to OR, ')' or the end. It always returns an And.
+func (p *parser) parseAnd() (Node, error) {
+	and := And{}
+	for tok := p.peek(); tok != nil && tok.kind != tokOr && tok.kind != tokRParen; tok = p.peek() {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		and.Nodes = append(and.Nodes, node)
+	}
+	return and, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokNot:
+		p.pos++
+		if p.peek() == nil {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "'-' must be followed by a term"}
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Node: node}, nil
+	case tokLParen:
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != tokRParen {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "unclosed '('"}
+		}
+		p.pos++
+		return simplify(node), nil
+	case tokTerm:
+		p.pos++
+		return tok.term, nil
+	default:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected '%s'", tok.text)}
+	}
+}
+
+// simplify unwraps single-node Ands.
+func simplify(node Node) Node {
+	if and, ok := node.(And); ok && len(and.Nodes) == 1 {
+		return and.Nodes[0]
+	}
+	return node
+}
+
+func containsFold(items []string, value string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNoneOrAny(v string) bool {
+	return strings.EqualFold(v, "none") || strings.EqualFold(v, "any")
+}
@@ -0,0 +1,291 @@
+package taskquery
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"priority:high,urgent", "priority:high,urgent"},
+		{"p:high -l:wontfix", "priority:high -label:wontfix"},
+		{`epic:"Auth flow" text:"token"`, `epic:"Auth flow" text:token`},
+		{"due:<7d estimate:>=3", "due:<7d estimate:>=3"},
+		{"crash save", "text:crash text:save"},
+		{`"crash on save"`, `text:"crash on save"`},
+		{"type:bug OR label:urgent", "type:bug OR label:urgent"},
+		{"is:open (type:bug OR p:urgent)", "is:open (type:bug OR priority:urgent)"},
+		{"-(label:a label:b)", "-(label:a label:b)"},
+		{`title:"say \"hi\""`, `title:"say \"hi\""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, node.String())
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, input := range []string{
+		"colour:red",
+		"priority:",
+		"priority:huge",
+		"label:<3",
+		"due:<next-year",
+		"is:sleeping",
+		"has:color",
+		"(type:bug",
+		"type:bug)",
+		`text:"open`,
+		"OR type:bug",
+		"type:bug OR",
+		"due:<none",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := Parse(input)
+			var syntaxErr *SyntaxError
+			assert.True(t, errors.As(err, &syntaxErr), "expected a syntax error, got %v", err)
+		})
+	}
+}
+
+func TestIsQuery(t *testing.T) {
+	assert.True(t, IsQuery("priority:high"))
+	assert.True(t, IsQuery("crash -label:wontfix"))
+	assert.True(t, IsQuery("priority:"))
+	assert.False(t, IsQuery("crash on save"))
+	assert.False(t, IsQuery(`"fix: login"`))
+	assert.False(t, IsQuery("fix: login"))
+}
+
+func TestParseDay(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 0, 0, 0, time.Local)
+	for input, want := range map[string]string{
+		"today":      "2025-03-10",
+		"tomorrow":   "2025-03-11",
+		"yesterday":  "2025-03-09",
+		"7d":         "2025-03-17",
+		"-2w":        "2025-02-24",
+		"+1m":        "2025-04-10",
+		"2025-01-31": "2025-01-31",
+	} {
+		got, err := parseDay(input, now)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got.Format("2006-01-02"), input)
+	}
+}
+
+// setupQueryTasks creates a board with an epic, a sprint, and tasks covering
+// each field.
+func setupQueryTasks(t *testing.T) *pocketbase.PocketBase {
+	t.Helper()
+	app := testutil.NewTestApp(t)
+
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "prefix"},
+	)
+	epics := testutil.CreateTestCollection(t, app, "epics",
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "board"},
+	)
+	sprints := testutil.CreateTestCollection(t, app, "sprints",
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "board"},
+		&core.TextField{Name: "state"},
+		&core.DateField{Name: "start_date"},
+	)
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "description"},
+		&core.TextField{Name: "column"},
+		&core.TextField{Name: "priority"},
+		&core.TextField{Name: "type"},
+		&core.TextField{Name: "created_by"},
+		&core.TextField{Name: "created_by_agent"},
+		&core.TextField{Name: "board"},
+		&core.NumberField{Name: "seq"},
+		&core.TextField{Name: "epic"},
+		&core.TextField{Name: "sprint"},
+		&core.TextField{Name: "parent"},
+		&core.DateField{Name: "due_date"},
+		&core.NumberField{Name: "estimate"},
+		&core.JSONField{Name: "labels"},
+		&core.JSONField{Name: "assignees"},
+		&core.JSONField{Name: "blocked_by"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+		&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true},
+	)
+
+	b := core.NewRecord(boards)
+	b.Set("name", "Work")
+	b.Set("prefix", "WRK")
+	require.NoError(t, app.Save(b))
+
+	epic := core.NewRecord(epics)
+	epic.Set("id", "epicauth0000001")
+	epic.Set("title", "Auth")
+	epic.Set("board", b.Id)
+	require.NoError(t, app.Save(epic))
+
+	sp := core.NewRecord(sprints)
+	sp.Set("id", "sprintcur000001")
+	sp.Set("name", "Sprint 1")
+	sp.Set("board", b.Id)
+	sp.Set("state", "active")
+	require.NoError(t, app.Save(sp))
+
+	save := func(id, title string, fields map[string]any) {
+		task := core.NewRecord(tasks)
+		task.Set("id", id)
+		task.Set("title", title)
+		task.Set("board", b.Id)
+		task.Set("column", "todo")
+		task.Set("priority", "medium")
+		task.Set("type", "feature")
+		task.Set("created_by", "user")
+		for k, v := range fields {
+			task.Set(k, v)
+		}
+		require.NoError(t, app.Save(task))
+	}
+	today := time.Now().Format("2006-01-02")
+	inFiveDays := time.Now().AddDate(0, 0, 5).Format("2006-01-02")
+	lastWeek := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+
+	save("task00000000001", "Token refresh fails", map[string]any{
+		"priority": "urgent", "type": "bug", "labels": []string{"backend", "auth"},
+		"epic": epic.Id, "sprint": sp.Id, "due_date": inFiveDays, "estimate": 3,
+		"assignees": []string{"Alice"}, "seq": 1,
+	})
+	save("task00000000002", "Login page", map[string]any{
+		"priority": "high", "labels": []string{"frontend", "wontfix"}, "epic": epic.Id,
+		"blocked_by": []string{"task00000000001"}, "due_date": lastWeek, "seq": 2,
+		"description": "Uses the refresh TOKEN",
+	})
+	save("task00000000003", "Write docs", map[string]any{
+		"priority": "low", "type": "chore", "column": "done", "due_date": lastWeek,
+		"created_by": "agent", "created_by_agent": "claude", "seq": 3,
+	})
+	save("task00000000004", "Subtask of token", map[string]any{
+		"parent": "task00000000001", "column": "backlog", "due_date": today, "estimate": 1, "seq": 4,
+		"labels": []string{"Backend"},
+	})
+	save("task00000000005", "100% done_rate", map[string]any{"column": "in_progress", "seq": 5})
+
+	return app
+}
+
+// queryIDs runs a query both as SQL and in memory and returns the matching
+// task IDs of each.
+func queryIDs(t *testing.T, app *pocketbase.PocketBase, input string) ([]string, []string) {
+	t.Helper()
+	filter, err := ParseAndCompile(app, input, Options{})
+	require.NoError(t, err, input)
+
+	sqlRecords, err := app.FindAllRecords("tasks", filter.Expression())
+	require.NoError(t, err, input)
+	var sqlIDs []string
+	for _, r := range sqlRecords {
+		sqlIDs = append(sqlIDs, r.Id)
+	}
+
+	all, err := app.FindAllRecords("tasks")
+	require.NoError(t, err)
+	var memIDs []string
+	for _, r := range all {
+		if filter.Match(TaskFromRecord(r)) {
+			memIDs = append(memIDs, r.Id)
+		}
+	}
+	sort.Strings(sqlIDs)
+	sort.Strings(memIDs)
+	return sqlIDs, memIDs
+}
+
+func TestCompile_SQLAndMatchAgree(t *testing.T) {
+	app := setupQueryTasks(t)
+
+	tests := []struct {
+		query string
+		want  []string // task ID suffixes
+	}{
+		{"", []string{"1", "2", "3", "4", "5"}},
+		{"priority:high,urgent", []string{"1", "2"}},
+		{"label:backend", []string{"1", "4"}},
+		{"label:backend -label:wontfix", []string{"1", "4"}},
+		{"-label:wontfix", []string{"1", "3", "4", "5"}},
+		{"label:none", []string{"3", "5"}},
+		{"is:blocked", []string{"2"}},
+		{"is:unblocked is:open", []string{"1", "4", "5"}},
+		{"is:ready", []string{"1", "4"}},
+		{"is:overdue", []string{"2"}},
+		{"is:subtask", []string{"4"}},
+		{"is:assigned", []string{"1"}},
+		{"assignee:alice", []string{"1"}},
+		{"assignee:none is:open", []string{"2", "4", "5"}},
+		{`epic:"auth"`, []string{"1", "2"}},
+		{"epic:none", []string{"3", "4", "5"}},
+		{"sprint:current", []string{"1"}},
+		{"parent:WRK-1", []string{"4"}},
+		{"due:<7d", []string{"1", "2", "3", "4"}},
+		{"due:<=today", []string{"2", "3", "4"}},
+		{"due:today", []string{"4"}},
+		{"due:>today", []string{"1"}},
+		{"due:none", []string{"5"}},
+		{"has:due -is:done", []string{"1", "2", "4"}},
+		{"created:>=today", []string{"1", "2", "3", "4", "5"}},
+		{"estimate:>=2", []string{"1"}},
+		{"estimate:none", []string{"2", "3", "5"}},
+		{`text:"token"`, []string{"1", "2", "4"}},
+		{"title:token", []string{"1", "4"}},
+		{"token -is:subtask", []string{"1", "2"}},
+		{"100%", []string{"5"}},
+		{"done_", []string{"5"}},
+		{"agent:claude", []string{"3"}},
+		{"by:agent", []string{"3"}},
+		{"type:bug OR column:done", []string{"1", "3"}},
+		{"is:open (type:bug OR p:high)", []string{"1", "2"}},
+		{"-(is:blocked OR is:done)", []string{"1", "4", "5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var want []string
+			for _, suffix := range tt.want {
+				want = append(want, "task0000000000"+suffix)
+			}
+			sqlIDs, memIDs := queryIDs(t, app, tt.query)
+			assert.Equal(t, want, sqlIDs, "SQL")
+			assert.Equal(t, want, memIDs, "in memory")
+		})
+	}
+}
+
+func TestCompile_ResolutionErrors(t *testing.T) {
+	app := setupQueryTasks(t)
+
+	for _, input := range []string{
+		"epic:Billing",
+		"sprint:nope",
+		"parent:WRK-99",
+	} {
+		_, err := ParseAndCompile(app, input, Options{})
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,650 @@
+// Package tasktemplate creates tasks from reusable templates.
+//
+// A template describes a parent task and its subtasks, including the
+// blocked_by wiring between them, with {{placeholders}} filled in from
+// variables when it is used. Templates are stored per board in the
+// task_templates collection or as files in .egenskriven/templates/
+// (JSON, or markdown with a front matter block). A board template shadows
+// a file template of the same name.
+package tasktemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/position"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+)
+
+// CollectionName is the PocketBase collection holding board templates.
+const CollectionName = "task_templates"
+
+// SourceBoard is the source of templates stored on a board.
+const SourceBoard = "board"
+
+// TitleVar is the variable holding the title given when the template is used.
+const TitleVar = "title"
+
+// ErrNotFound is returned when no template has the requested name.
+var ErrNotFound = errors.New("template not found")
+
+// ErrExists is returned when saving a template whose name is taken.
+var ErrExists = errors.New("template already exists on this board")
+
+// placeholderPattern matches {{name}} placeholders.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Spec describes one task a template creates.
+type Spec struct {
+	Key         string   `json:"key,omitempty"` // Referenced by blocked_by of other specs
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+	Column      string   `json:"column,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Estimate    float64  `json:"estimate,omitempty"`
+	Assignees   []string `json:"assignees,omitempty"`
+	BlockedBy   []string `json:"blocked_by,omitempty"` // Keys of specs in the same template
+}
+
+// Template is a parent task with subtasks and variable defaults.
+type Template struct {
+	Name     string            `json:"name"`
+	Summary  string            `json:"summary,omitempty"`
+	Vars     map[string]string `json:"vars,omitempty"` // Variable defaults
+	Task     Spec              `json:"task"`
+	Subtasks []Spec            `json:"subtasks,omitempty"`
+
+	// Source is SourceBoard or the path of the template file.
+	Source string `json:"source,omitempty"`
+	// Record is the board template record (nil for file templates).
+	Record *core.Record `json:"-"`
+}
+
+// definition is what a board template stores in its definition field.
+type definition struct {
+	Vars     map[string]string `json:"vars,omitempty"`
+	Task     Spec              `json:"task"`
+	Subtasks []Spec            `json:"subtasks,omitempty"`
+}
+
+// MissingVarsError is returned when placeholders have no value.
+type MissingVarsError struct {
+	Names []string
+}
+
+func (e *MissingVarsError) Error() string {
+	return fmt.Sprintf("missing template variable(s): %s", strings.Join(e.Names, ", "))
+}
+
+// Dir returns the template directory of the project in dir.
+func Dir(dir string) string {
+	return filepath.Join(dir, ".egenskriven", "templates")
+}
+
+// List returns the templates of a board followed by the file templates of
+// the project in dir that are not shadowed by a board template, each group
+// sorted by name.
+func List(app core.App, boardID, dir string) ([]Template, error) {
+	templates, err := boardTemplates(app, boardID)
+	if err != nil {
+		return nil, err
+	}
+	return WithFiles(templates, dir)
+}
+
+// WithFiles appends to a board's templates the file templates of the
+// project in dir that they do not shadow.
+func WithFiles(templates []Template, dir string) ([]Template, error) {
+	files, err := fileTemplates(dir)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(templates))
+	for _, t := range templates {
+		seen[t.Name] = true
+	}
+	for _, t := range files {
+		if !seen[t.Name] {
+			templates = append(templates, t)
+		}
+	}
+	return templates, nil
+}
+
+// Find returns the template with the given name, preferring the board's.
+func Find(app core.App, boardID, dir, name string) (Template, error) {
+	templates, err := List(app, boardID, dir)
+	if err != nil {
+		return Template{}, err
+	}
+	return Named(templates, name)
+}
+
+// Named returns the first template with the given name.
+func Named(templates []Template, name string) (Template, error) {
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return Template{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+// boardTemplates loads the templates stored on a board.
+func boardTemplates(app core.App, boardID string) ([]Template, error) {
+	if boardID == "" {
+		return nil, nil
+	}
+	if _, err := app.FindCachedCollectionByNameOrId(CollectionName); err != nil {
+		return nil, nil // Not migrated yet
+	}
+
+	records, err := app.FindAllRecords(CollectionName,
+		dbx.HashExp{"board": boardID},
+	)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].GetString("name") < records[j].GetString("name")
+	})
+
+	templates := make([]Template, 0, len(records))
+	for _, record := range records {
+		t, err := FromRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// FromRecord reads a board template record.
+func FromRecord(record *core.Record) (Template, error) {
+	var def definition
+	// Records of the API client hold the definition decoded, not as raw JSON
+	raw, err := json.Marshal(record.Get("definition"))
+	if err == nil {
+		err = json.Unmarshal(raw, &def)
+	}
+	if err != nil {
+		return Template{}, fmt.Errorf("template %s: invalid definition: %w", record.GetString("name"), err)
+	}
+	return Template{
+		Name:     record.GetString("name"),
+		Summary:  record.GetString("summary"),
+		Vars:     def.Vars,
+		Task:     def.Task,
+		Subtasks: def.Subtasks,
+		Source:   SourceBoard,
+		Record:   record,
+	}, nil
+}
+
+// ToRecord sets the fields of a board template record.
+func ToRecord(t Template, boardID string, record *core.Record) {
+	record.Set("board", boardID)
+	record.Set("name", t.Name)
+	record.Set("summary", t.Summary)
+	record.Set("definition", definition{Vars: t.Vars, Task: t.Task, Subtasks: t.Subtasks})
+}
+
+// warnings receives the warnings about template files that are skipped.
+var warnings io.Writer = os.Stderr
+
+// fileTemplates loads the *.json and *.md templates of a project. Files
+// that cannot be parsed are skipped with a warning, so one broken file
+// does not hide the other templates.
+func fileTemplates(dir string) ([]Template, error) {
+	entries, err := os.ReadDir(Dir(dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []Template
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".md") {
+			continue
+		}
+		path := filepath.Join(Dir(dir), entry.Name())
+		t, err := ParseFile(path)
+		if err != nil {
+			fmt.Fprintf(warnings, "Warning: skipping template %s: %v\n", path, err)
+			continue
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// ParseFile reads a JSON or markdown template file. The template is named
+// after the file unless a JSON template sets a name.
+func ParseFile(path string) (Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	var t Template
+	if filepath.Ext(path) == ".md" {
+		t, err = ParseMarkdown(name, string(data))
+	} else {
+		err = json.Unmarshal(data, &t)
+		if t.Name == "" {
+			t.Name = name
+		}
+	}
+	if err != nil {
+		return Template{}, fmt.Errorf("template %s: %w", path, err)
+	}
+	if err := t.Validate(); err != nil {
+		return Template{}, fmt.Errorf("template %s: %w", path, err)
+	}
+	t.Source = path
+	return t, nil
+}
+
+// ParseMarkdown parses a markdown template: an optional front matter block
+// of "key: value" lines between "---" lines, followed by the task
+// description. Front matter keys are summary, title, type, priority,
+// column, labels (comma-separated), subtask (repeatable, one subtask per
+// line, each blocked by the previous one when "chain: true" is set), and
+// var.<name> for variable defaults.
+func ParseMarkdown(name, content string) (Template, error) {
+	t := Template{Name: name}
+	body := content
+
+	lines := strings.Split(content, "\n")
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		end := -1
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return t, errors.New("front matter is not closed with ---")
+		}
+
+		chain := false
+		for i, line := range lines[1:end] {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return t, fmt.Errorf("front matter line %d: expected 'key: value'", i+2)
+			}
+			key, value = strings.TrimSpace(key), unquote(strings.TrimSpace(value))
+			switch {
+			case key == "summary":
+				t.Summary = value
+			case key == "title":
+				t.Task.Title = value
+			case key == "type":
+				t.Task.Type = value
+			case key == "priority":
+				t.Task.Priority = value
+			case key == "column":
+				t.Task.Column = value
+			case key == "labels":
+				t.Task.Labels = splitList(value)
+			case key == "subtask":
+				t.Subtasks = append(t.Subtasks, Spec{Title: value})
+			case key == "chain":
+				chain = value == "true"
+			case strings.HasPrefix(key, "var."):
+				if t.Vars == nil {
+					t.Vars = make(map[string]string)
+				}
+				t.Vars[strings.TrimPrefix(key, "var.")] = value
+			default:
+				return t, fmt.Errorf("front matter line %d: unknown key %q", i+2, key)
+			}
+		}
+		if chain {
+			for i := range t.Subtasks {
+				t.Subtasks[i].Key = fmt.Sprintf("step%d", i+1)
+				if i > 0 {
+					t.Subtasks[i].BlockedBy = []string{t.Subtasks[i-1].Key}
+				}
+			}
+		}
+		body = strings.Join(lines[end+1:], "\n")
+	}
+
+	t.Task.Description = strings.TrimSpace(body)
+	return t, nil
+}
+
+// unquote removes matching quotes around a front matter value.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Validate checks that keys are unique and blocked_by references keys of
+// the same template.
+func (t Template) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("template name is required")
+	}
+
+	keys := make(map[string]bool)
+	for _, spec := range t.specs() {
+		if spec.Key == "" {
+			continue
+		}
+		if keys[spec.Key] {
+			return fmt.Errorf("duplicate key %q", spec.Key)
+		}
+		keys[spec.Key] = true
+	}
+	for i, spec := range t.specs() {
+		if i > 0 && strings.TrimSpace(spec.Title) == "" {
+			return fmt.Errorf("subtask %d has no title", i)
+		}
+		for _, ref := range spec.BlockedBy {
+			if !keys[ref] {
+				return fmt.Errorf("blocked_by references unknown key %q", ref)
+			}
+			if ref == spec.Key {
+				return fmt.Errorf("task %q cannot block itself", ref)
+			}
+		}
+	}
+	return nil
+}
+
+// specs returns the parent task followed by the subtasks.
+func (t Template) specs() []Spec {
+	return append([]Spec{t.Task}, t.Subtasks...)
+}
+
+// Placeholders returns the names of the variables a template uses, sorted.
+func (t Template) Placeholders() []string {
+	seen := make(map[string]bool)
+	var names []string
+	visit := func(s string) {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+	for _, spec := range t.specs() {
+		visit(parentTitle(spec.Title))
+		visit(spec.Description)
+		for _, label := range spec.Labels {
+			visit(label)
+		}
+		for _, assignee := range spec.Assignees {
+			visit(assignee)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parentTitle returns the title pattern of a task; untitled parents take
+// the title the template is used with.
+func parentTitle(title string) string {
+	if strings.TrimSpace(title) == "" {
+		return "{{" + TitleVar + "}}"
+	}
+	return title
+}
+
+// Render fills in the placeholders of a template. Values in vars override
+// the template's defaults; placeholders without a value are reported in a
+// MissingVarsError.
+func (t Template) Render(vars map[string]string) (Template, error) {
+	values := make(map[string]string, len(t.Vars)+len(vars))
+	for k, v := range t.Vars {
+		values[k] = v
+	}
+	for k, v := range vars {
+		if v != "" || values[k] == "" {
+			values[k] = v
+		}
+	}
+
+	var missing []string
+	for _, name := range t.Placeholders() {
+		if values[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return t, &MissingVarsError{Names: missing}
+	}
+
+	fill := func(s string) string {
+		return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+			return values[placeholderPattern.FindStringSubmatch(match)[1]]
+		})
+	}
+	fillAll := func(items []string) []string {
+		if items == nil {
+			return nil
+		}
+		filled := make([]string, len(items))
+		for i, item := range items {
+			filled[i] = fill(item)
+		}
+		return filled
+	}
+	renderSpec := func(spec Spec) Spec {
+		spec.Title = fill(spec.Title)
+		spec.Description = fill(spec.Description)
+		spec.Labels = fillAll(spec.Labels)
+		spec.Assignees = fillAll(spec.Assignees)
+		return spec
+	}
+
+	rendered := t
+	rendered.Task = renderSpec(t.Task)
+	rendered.Task.Title = fill(parentTitle(t.Task.Title))
+	rendered.Subtasks = make([]Spec, len(t.Subtasks))
+	for i, spec := range t.Subtasks {
+		rendered.Subtasks[i] = renderSpec(spec)
+	}
+	return rendered, nil
+}
+
+// BuiltinVars returns the variables every template can use: the title it
+// is used with, today's date, the current user, and the board prefix.
+func BuiltinVars(title, boardPrefix string) map[string]string {
+	return map[string]string{
+		TitleVar: title,
+		"date":   time.Now().Format("2006-01-02"),
+		"user":   config.CurrentUser(),
+		"board":  boardPrefix,
+	}
+}
+
+// Save stores a template on a board, replacing the board's template of the
+// same name when replace is set.
+func Save(app core.App, boardID string, t Template, replace bool) (*core.Record, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	record, err := app.FindFirstRecordByFilter(CollectionName,
+		"board = {:board} && name = {:name}",
+		dbx.Params{"board": boardID, "name": t.Name},
+	)
+	if err == nil && !replace {
+		return nil, fmt.Errorf("%w: %s", ErrExists, t.Name)
+	}
+	if err != nil {
+		collection, err := app.FindCollectionByNameOrId(CollectionName)
+		if err != nil {
+			return nil, fmt.Errorf("%s collection not found: %w", CollectionName, err)
+		}
+		record = core.NewRecord(collection)
+	}
+
+	ToRecord(t, boardID, record)
+	if err := app.Save(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Options configures the tasks a template creates.
+type Options struct {
+	CreatedBy string // "user", "agent", "cli", or "tui"
+	Agent     string // Agent name when CreatedBy is "agent"
+	Epic      string // Epic ID set on every created task
+	DueDate   string // Due date of the parent task
+}
+
+// Instantiate creates the parent task and subtasks of a rendered template
+// on a board in one transaction and returns them in template order.
+// Subtasks without a type or column take the parent's.
+func Instantiate(app *pocketbase.PocketBase, boardRecord *core.Record, t Template, opts Options) ([]*core.Record, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	collection, err := app.FindCollectionByNameOrId("tasks")
+	if err != nil {
+		return nil, fmt.Errorf("tasks collection not found: %w", err)
+	}
+
+	specs := t.specs()
+	specs[0].Column = defaultString(specs[0].Column, "backlog")
+	specs[0].Type = defaultString(specs[0].Type, "feature")
+
+	// IDs are assigned up front so blocked_by can reference any task
+	records := make([]*core.Record, len(specs))
+	ids := make(map[string]string)
+	for i, spec := range specs {
+		records[i] = core.NewRecord(collection)
+		records[i].Id = security.RandomStringWithAlphabet(core.DefaultIdLength, core.DefaultIdAlphabet)
+		if spec.Key != "" {
+			ids[spec.Key] = records[i].Id
+		}
+	}
+
+	positions := make(map[string]float64)
+	for i, spec := range specs {
+		record := records[i]
+		column := spec.Column
+		if column == "" {
+			column = specs[0].Column // Subtasks start where their parent does
+		}
+
+		// Tasks added to the same column are placed one after another
+		pos, ok := positions[column]
+		if ok {
+			pos += position.DefaultGap
+		} else {
+			pos = position.GetNext(app, column)
+		}
+		positions[column] = pos
+
+		seq, err := board.GetNextSequence(app, boardRecord.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		blockedBy := make([]string, 0, len(spec.BlockedBy))
+		for _, key := range spec.BlockedBy {
+			blockedBy = append(blockedBy, ids[key])
+		}
+
+		record.Set("title", spec.Title)
+		record.Set("description", spec.Description)
+		record.Set("type", defaultString(spec.Type, specs[0].Type))
+		record.Set("priority", defaultString(spec.Priority, "medium"))
+		record.Set("column", column)
+		record.Set("position", pos)
+		record.Set("labels", spec.Labels)
+		record.Set("blocked_by", blockedBy)
+		record.Set("board", boardRecord.Id)
+		record.Set("seq", seq)
+		record.Set("created_by", opts.CreatedBy)
+		if opts.Agent != "" {
+			record.Set("created_by_agent", opts.Agent)
+		}
+		if spec.Estimate > 0 {
+			record.Set("estimate", spec.Estimate)
+		}
+		if len(spec.Assignees) > 0 {
+			record.Set("assignees", spec.Assignees)
+		}
+		if opts.Epic != "" {
+			record.Set("epic", opts.Epic)
+		}
+		if i == 0 {
+			if opts.DueDate != "" {
+				record.Set("due_date", opts.DueDate)
+			}
+		} else {
+			record.Set("parent", records[0].Id)
+		}
+		taskevent.Add(record, "created", opts.CreatedBy, opts.Agent, nil, map[string]any{"template": t.Name})
+	}
+
+	err = app.RunInTransaction(func(txApp core.App) error {
+		for _, record := range records {
+			if err := txApp.Save(record); err != nil {
+				return fmt.Errorf("failed to create task %q: %w", record.GetString("title"), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// defaultString returns value, or def if value is empty.
+func defaultString(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
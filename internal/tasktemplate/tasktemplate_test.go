@@ -0,0 +1,285 @@
+package tasktemplate
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+// setup creates boards, tasks, and task_templates collections and one
+// board.
+func setup(t *testing.T) (*pocketbase.PocketBase, *core.Record) {
+	t.Helper()
+
+	app := testutil.NewTestApp(t)
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "prefix"},
+		&core.NumberField{Name: "next_seq"},
+	)
+	testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "description"},
+		&core.TextField{Name: "type"},
+		&core.TextField{Name: "priority"},
+		&core.TextField{Name: "column"},
+		&core.NumberField{Name: "position"},
+		&core.TextField{Name: "board"},
+		&core.NumberField{Name: "seq"},
+		&core.JSONField{Name: "labels"},
+		&core.JSONField{Name: "assignees"},
+		&core.JSONField{Name: "blocked_by"},
+		&core.TextField{Name: "parent"},
+		&core.TextField{Name: "epic"},
+		&core.TextField{Name: "due_date"},
+		&core.NumberField{Name: "estimate"},
+		&core.TextField{Name: "created_by"},
+		&core.TextField{Name: "created_by_agent"},
+	)
+	testutil.CreateTaskEventsCollection(t, app)
+	taskevent.Bind(app)
+	testutil.CreateTestCollection(t, app, CollectionName,
+		&core.TextField{Name: "board"},
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "summary"},
+		&core.JSONField{Name: "definition"},
+	)
+
+	board := core.NewRecord(boards)
+	board.Set("name", "Work")
+	board.Set("prefix", "WRK")
+	require.NoError(t, app.Save(board))
+	return app, board
+}
+
+const bugMarkdown = `---
+summary: Bug report
+title: "Bug: {{title}}"
+type: bug
+labels: bug, triage
+subtask: Reproduce on {{version}}
+subtask: Fix
+subtask: Release notes
+chain: true
+var.version: latest
+---
+Found in {{ version }}.
+`
+
+func TestParseMarkdown(t *testing.T) {
+	tmpl, err := ParseMarkdown("bug", bugMarkdown)
+	require.NoError(t, err)
+
+	assert.Equal(t, "bug", tmpl.Name)
+	assert.Equal(t, "Bug report", tmpl.Summary)
+	assert.Equal(t, "Bug: {{title}}", tmpl.Task.Title, "quotes are removed")
+	assert.Equal(t, "bug", tmpl.Task.Type)
+	assert.Equal(t, []string{"bug", "triage"}, tmpl.Task.Labels)
+	assert.Equal(t, "Found in {{ version }}.", tmpl.Task.Description)
+	assert.Equal(t, map[string]string{"version": "latest"}, tmpl.Vars)
+
+	require.Len(t, tmpl.Subtasks, 3)
+	assert.Equal(t, "step1", tmpl.Subtasks[0].Key)
+	assert.Empty(t, tmpl.Subtasks[0].BlockedBy)
+	assert.Equal(t, []string{"step2"}, tmpl.Subtasks[2].BlockedBy)
+	assert.Equal(t, []string{"title", "version"}, tmpl.Placeholders())
+
+	_, err = ParseMarkdown("bad", "---\ncolour: red\n---\n")
+	assert.ErrorContains(t, err, "unknown key")
+	_, err = ParseMarkdown("bad", "---\ntitle: x\n")
+	assert.ErrorContains(t, err, "not closed")
+}
+
+func TestRender(t *testing.T) {
+	tmpl, err := ParseMarkdown("bug", bugMarkdown)
+	require.NoError(t, err)
+
+	rendered, err := tmpl.Render(map[string]string{"title": "Crash on save", "version": "1.2"})
+	require.NoError(t, err)
+	assert.Equal(t, "Bug: Crash on save", rendered.Task.Title)
+	assert.Equal(t, "Found in 1.2.", rendered.Task.Description)
+	assert.Equal(t, "Reproduce on 1.2", rendered.Subtasks[0].Title)
+	assert.Equal(t, "Bug: {{title}}", tmpl.Task.Title, "the template is not modified")
+
+	// Defaults fill variables that are not given
+	rendered, err = tmpl.Render(map[string]string{"title": "Crash"})
+	require.NoError(t, err)
+	assert.Equal(t, "Reproduce on latest", rendered.Subtasks[0].Title)
+
+	_, err = tmpl.Render(nil)
+	var missing *MissingVarsError
+	require.True(t, errors.As(err, &missing))
+	assert.Equal(t, []string{"title"}, missing.Names)
+
+	// An empty parent title is the title variable
+	plain := Template{Name: "plain"}
+	rendered, err = plain.Render(map[string]string{"title": "Just this"})
+	require.NoError(t, err)
+	assert.Equal(t, "Just this", rendered.Task.Title)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    Template
+		wantErr string
+	}{
+		{"no name", Template{}, "name"},
+		{"subtask without title", Template{Name: "x", Subtasks: []Spec{{}}}, "no title"},
+		{"duplicate key", Template{Name: "x", Subtasks: []Spec{{Key: "a", Title: "A"}, {Key: "a", Title: "B"}}}, "duplicate key"},
+		{"unknown blocker", Template{Name: "x", Subtasks: []Spec{{Title: "A", BlockedBy: []string{"b"}}}}, "unknown key"},
+		{"blocks itself", Template{Name: "x", Subtasks: []Spec{{Key: "a", Title: "A", BlockedBy: []string{"a"}}}}, "itself"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.tmpl.Validate(), tt.wantErr)
+		})
+	}
+
+	valid := Template{Name: "x", Subtasks: []Spec{{Key: "a", Title: "A"}, {Title: "B", BlockedBy: []string{"a"}}}}
+	assert.NoError(t, valid.Validate())
+}
+
+func TestSaveListAndFind(t *testing.T) {
+	app, board := setup(t)
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(Dir(dir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(Dir(dir), "bug.md"), []byte(bugMarkdown), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(Dir(dir), "release.json"),
+		[]byte(`{"summary":"Release","task":{"title":"Release {{version}}"}}`), 0644))
+
+	templates, err := List(app, board.Id, dir)
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Equal(t, "bug", templates[0].Name)
+	assert.Equal(t, "release", templates[1].Name, "a JSON template is named after its file")
+	assert.Equal(t, filepath.Join(Dir(dir), "bug.md"), templates[0].Source)
+
+	// A board template shadows the file template of the same name
+	_, err = Save(app, board.Id, Template{Name: "bug", Summary: "Board bug"}, false)
+	require.NoError(t, err)
+	_, err = Save(app, board.Id, Template{Name: "bug"}, false)
+	assert.ErrorIs(t, err, ErrExists)
+
+	found, err := Find(app, board.Id, dir, "bug")
+	require.NoError(t, err)
+	assert.Equal(t, SourceBoard, found.Source)
+	assert.Equal(t, "Board bug", found.Summary)
+	assert.NotNil(t, found.Record)
+
+	_, err = Save(app, board.Id, Template{Name: "bug", Summary: "Replaced"}, true)
+	require.NoError(t, err)
+	templates, err = List(app, board.Id, dir)
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Equal(t, "Replaced", templates[0].Summary)
+
+	_, err = Find(app, board.Id, dir, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestList_SkipsMalformedFiles(t *testing.T) {
+	app, board := setup(t)
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(Dir(dir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(Dir(dir), "bug.md"), []byte(bugMarkdown), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(Dir(dir), "broken.json"), []byte(`{"task":`), 0644))
+
+	var warned bytes.Buffer
+	warnings = &warned
+	t.Cleanup(func() { warnings = os.Stderr })
+
+	templates, err := List(app, board.Id, dir)
+	require.NoError(t, err)
+	require.Len(t, templates, 1, "the other templates are still listed")
+	assert.Equal(t, "bug", templates[0].Name)
+	assert.Contains(t, warned.String(), "skipping template "+filepath.Join(Dir(dir), "broken.json"))
+
+	_, err = Find(app, board.Id, dir, "broken")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestInstantiate(t *testing.T) {
+	app, board := setup(t)
+
+	tmpl := Template{
+		Name: "feature",
+		Task: Spec{Title: "{{title}}", Type: "feature", Column: "todo", Labels: []string{"{{area}}"}},
+		Subtasks: []Spec{
+			{Key: "design", Title: "Design {{title}}"},
+			{Key: "build", Title: "Build", Type: "chore", BlockedBy: []string{"design"}},
+			{Title: "Ship", Column: "backlog", BlockedBy: []string{"design", "build"}},
+		},
+	}
+	rendered, err := tmpl.Render(map[string]string{"title": "Search", "area": "web"})
+	require.NoError(t, err)
+
+	records, err := Instantiate(app, board, rendered, Options{CreatedBy: "cli", DueDate: "2026-05-01"})
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+
+	parent, design, build, ship := records[0], records[1], records[2], records[3]
+	assert.Equal(t, "Search", parent.GetString("title"))
+	assert.Equal(t, []string{"web"}, parent.GetStringSlice("labels"))
+	assert.Equal(t, "2026-05-01", parent.GetString("due_date"))
+	assert.Empty(t, parent.GetString("parent"))
+
+	for i, record := range records {
+		assert.Equal(t, i+1, record.GetInt("seq"))
+		assert.Equal(t, board.Id, record.GetString("board"))
+		if i > 0 {
+			assert.Equal(t, parent.Id, record.GetString("parent"))
+			assert.Empty(t, record.GetString("due_date"))
+		}
+	}
+
+	assert.Equal(t, "Design Search", design.GetString("title"))
+	assert.Equal(t, "todo", design.GetString("column"), "subtasks start in the parent's column")
+	assert.Equal(t, "feature", design.GetString("type"), "subtasks take the parent's type")
+	assert.Equal(t, "chore", build.GetString("type"))
+	assert.Equal(t, "backlog", ship.GetString("column"))
+	assert.Equal(t, []string{design.Id}, build.GetStringSlice("blocked_by"))
+	assert.Equal(t, []string{design.Id, build.Id}, ship.GetStringSlice("blocked_by"))
+	assert.Greater(t, design.GetFloat("position"), parent.GetFloat("position"))
+
+	events, err := taskevent.ForTask(app, parent.Id)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "created", events[0].Action)
+	assert.Equal(t, "feature", events[0].Metadata["template"])
+
+	saved, err := app.FindAllRecords("tasks")
+	require.NoError(t, err)
+	assert.Len(t, saved, 4)
+}
+
+func TestInstantiate_RollsBackOnError(t *testing.T) {
+	app, board := setup(t)
+
+	// Make the tasks collection reject long descriptions
+	tasks, err := app.FindCollectionByNameOrId("tasks")
+	require.NoError(t, err)
+	tasks.Fields.GetByName("description").(*core.TextField).Max = 10
+	require.NoError(t, app.Save(tasks))
+
+	_, err = Instantiate(app, board, Template{
+		Name:     "broken",
+		Task:     Spec{Title: "Parent"},
+		Subtasks: []Spec{{Title: "Fine"}, {Title: "Too long", Description: "more than ten characters"}},
+	}, Options{CreatedBy: "cli"})
+	assert.ErrorContains(t, err, "Too long")
+
+	saved, err := app.FindAllRecords("tasks")
+	require.NoError(t, err)
+	assert.Empty(t, saved, "no task is created when one fails")
+}
@@ -80,3 +80,21 @@ func CreateTestCollection(t *testing.T, app *pocketbase.PocketBase, name string,
 
 	return collection
 }
+
+// CreateTaskEventsCollection creates the task_events collection with the
+// fields the taskevent package reads and writes. Tests that check logged
+// events also call taskevent.Bind to write them on task saves.
+func CreateTaskEventsCollection(t *testing.T, app *pocketbase.PocketBase) *core.Collection {
+	t.Helper()
+
+	return CreateTestCollection(t, app, "task_events",
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "board"},
+		&core.TextField{Name: "action"},
+		&core.TextField{Name: "actor"},
+		&core.TextField{Name: "actor_detail"},
+		&core.JSONField{Name: "changes"},
+		&core.JSONField{Name: "metadata"},
+		&core.DateField{Name: "timestamp"},
+	)
+}
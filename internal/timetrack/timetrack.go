@@ -0,0 +1,268 @@
+// Package timetrack records time spent on tasks in the time_entries collection.
+//
+// An entry is "running" while its ended_at is empty. At most one entry per
+// task runs at a time. Entries are created in three ways:
+//   - timer: explicit `time start` / `time stop`
+//   - auto: started and paused by column changes (see HandleColumnChange)
+//   - manual: logged after the fact with `time log`
+package timetrack
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+)
+
+// CollectionName is the name of the time entries collection.
+const CollectionName = "time_entries"
+
+// Entry sources.
+const (
+	SourceTimer  = "timer"
+	SourceAuto   = "auto"
+	SourceManual = "manual"
+)
+
+var (
+	// ErrTimerRunning is returned when starting a timer on a task that already has one.
+	ErrTimerRunning = errors.New("timer already running")
+	// ErrNoTimerRunning is returned when stopping a timer on a task without one.
+	ErrNoTimerRunning = errors.New("no timer running")
+)
+
+// FindRunning returns the running entry for a task, or nil if there is none.
+func FindRunning(app core.App, taskID string) (*core.Record, error) {
+	records, err := app.FindRecordsByFilter(
+		CollectionName,
+		"task = {:task} && ended_at = ''",
+		"-started_at",
+		1,
+		0,
+		dbx.Params{"task": taskID},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return records[0], nil
+}
+
+// Start opens a new running entry for a task.
+// Returns ErrTimerRunning if the task already has a running entry.
+func Start(app core.App, taskID, actor, source string, at time.Time) (*core.Record, error) {
+	running, err := FindRunning(app, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		return running, ErrTimerRunning
+	}
+
+	collection, err := app.FindCollectionByNameOrId(CollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("time_entries collection not found: %w", err)
+	}
+
+	entry := core.NewRecord(collection)
+	entry.Set("task", taskID)
+	entry.Set("actor", actor)
+	entry.Set("source", source)
+	entry.Set("started_at", at.UTC())
+
+	if err := app.Save(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Stop closes the running entry for a task and records its duration.
+// Returns ErrNoTimerRunning if the task has no running entry.
+func Stop(app core.App, taskID string, at time.Time) (*core.Record, error) {
+	running, err := FindRunning(app, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if running == nil {
+		return nil, ErrNoTimerRunning
+	}
+
+	started := running.GetDateTime("started_at").Time()
+	duration := at.Sub(started)
+	if duration < 0 {
+		duration = 0
+	}
+
+	running.Set("ended_at", at.UTC())
+	running.Set("duration_seconds", int(duration.Seconds()))
+
+	if err := app.Save(running); err != nil {
+		return nil, err
+	}
+	return running, nil
+}
+
+// Log records a completed entry of the given duration ending at endedAt.
+func Log(app core.App, taskID, actor string, duration time.Duration, endedAt time.Time, note string) (*core.Record, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+
+	collection, err := app.FindCollectionByNameOrId(CollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("time_entries collection not found: %w", err)
+	}
+
+	entry := core.NewRecord(collection)
+	entry.Set("task", taskID)
+	entry.Set("actor", actor)
+	entry.Set("source", SourceManual)
+	entry.Set("started_at", endedAt.Add(-duration).UTC())
+	entry.Set("ended_at", endedAt.UTC())
+	entry.Set("duration_seconds", int(duration.Seconds()))
+	entry.Set("note", note)
+
+	if err := app.Save(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Entries returns all entries for a task, oldest first.
+func Entries(app core.App, taskID string) ([]*core.Record, error) {
+	return app.FindRecordsByFilter(
+		CollectionName,
+		"task = {:task}",
+		"+started_at",
+		0,
+		0,
+		dbx.Params{"task": taskID},
+	)
+}
+
+// IsRunning reports whether an entry is still running.
+func IsRunning(entry *core.Record) bool {
+	return entry.GetDateTime("ended_at").IsZero()
+}
+
+// EntryDuration returns the duration of an entry.
+// Running entries are measured up to now.
+func EntryDuration(entry *core.Record, now time.Time) time.Duration {
+	if IsRunning(entry) {
+		d := now.Sub(entry.GetDateTime("started_at").Time())
+		if d < 0 {
+			return 0
+		}
+		return d
+	}
+	return time.Duration(entry.GetInt("duration_seconds")) * time.Second
+}
+
+// Summary is the total time logged on a task.
+type Summary struct {
+	Total   time.Duration
+	Entries int
+	Running bool
+}
+
+// TaskSummary sums all entries for a task, including any running entry.
+// Returns an empty summary if the time_entries collection does not exist.
+func TaskSummary(app core.App, taskID string) (Summary, error) {
+	if _, err := app.FindCollectionByNameOrId(CollectionName); err != nil {
+		return Summary{}, nil
+	}
+
+	entries, err := Entries(app, taskID)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	now := time.Now()
+	summary := Summary{Entries: len(entries)}
+	for _, entry := range entries {
+		summary.Total += EntryDuration(entry, now)
+		if IsRunning(entry) {
+			summary.Running = true
+		}
+	}
+	return summary, nil
+}
+
+// HandleColumnChange starts or pauses the automatic timer for a task that
+// moved from fromColumn to toColumn. Entering a tracked column starts a timer
+// (unless one is already running); leaving all tracked columns stops it.
+// Does nothing when automatic timers are disabled.
+func HandleColumnChange(app core.App, task *core.Record, fromColumn, toColumn string, cfg config.TimeTrackingConfig) error {
+	if !cfg.AutoTimerEnabled() || fromColumn == toColumn {
+		return nil
+	}
+
+	tracked := cfg.TrackedColumns()
+	wasTracked := contains(tracked, fromColumn)
+	isTracked := contains(tracked, toColumn)
+
+	now := time.Now()
+	switch {
+	case isTracked && !wasTracked:
+		_, err := Start(app, task.Id, lastActor(task), SourceAuto, now)
+		if errors.Is(err, ErrTimerRunning) {
+			return nil
+		}
+		return err
+	case wasTracked && !isTracked:
+		_, err := Stop(app, task.Id, now)
+		if errors.Is(err, ErrNoTimerRunning) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// FormatDuration formats a duration compactly, e.g. "2h 15m", "45m", "<1m".
+func FormatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "<1m"
+	}
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+// lastActor returns the actor of the task change being saved, preferring
+// the actor detail (e.g. agent name) when present, or the task's creator.
+func lastActor(task *core.Record) string {
+	if events := taskevent.Pending(task); len(events) > 0 {
+		return events[len(events)-1].ActorName()
+	}
+	actor, detail := taskevent.ActorOf(task)
+	switch {
+	case detail != "":
+		return detail
+	case actor != taskevent.DefaultActor:
+		return actor
+	}
+	return task.GetString("created_by")
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package timetrack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+// setupCollections creates minimal tasks and time_entries collections.
+func setupCollections(t *testing.T, app *pocketbase.PocketBase) *core.Collection {
+	t.Helper()
+
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "column"},
+		&core.TextField{Name: "created_by"},
+	)
+
+	entries := core.NewBaseCollection(CollectionName)
+	entries.Fields.Add(&core.RelationField{Name: "task", CollectionId: tasks.Id, MaxSelect: 1, Required: true})
+	entries.Fields.Add(&core.TextField{Name: "actor"})
+	entries.Fields.Add(&core.SelectField{Name: "source", Required: true, Values: []string{SourceTimer, SourceAuto, SourceManual}})
+	entries.Fields.Add(&core.DateField{Name: "started_at", Required: true})
+	entries.Fields.Add(&core.DateField{Name: "ended_at"})
+	entries.Fields.Add(&core.NumberField{Name: "duration_seconds", OnlyInt: true})
+	entries.Fields.Add(&core.TextField{Name: "note"})
+	require.NoError(t, app.Save(entries))
+
+	return tasks
+}
+
+func createTask(t *testing.T, app *pocketbase.PocketBase, collection *core.Collection, column string) *core.Record {
+	t.Helper()
+
+	task := core.NewRecord(collection)
+	task.Set("title", "Test task")
+	task.Set("column", column)
+	task.Set("created_by", "cli")
+	taskevent.Add(task, "moved", "cli", "claude", nil, nil)
+	require.NoError(t, app.Save(task))
+	return task
+}
+
+func TestStartStop(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	tasks := setupCollections(t, app)
+	task := createTask(t, app, tasks, "todo")
+
+	start := time.Now().Add(-90 * time.Minute)
+	entry, err := Start(app, task.Id, "alice", SourceTimer, start)
+	require.NoError(t, err)
+	assert.True(t, IsRunning(entry))
+
+	// Starting again fails while the first timer runs
+	_, err = Start(app, task.Id, "alice", SourceTimer, time.Now())
+	assert.ErrorIs(t, err, ErrTimerRunning)
+
+	stopped, err := Stop(app, task.Id, start.Add(90*time.Minute))
+	require.NoError(t, err)
+	assert.False(t, IsRunning(stopped))
+	assert.Equal(t, 5400, stopped.GetInt("duration_seconds"))
+
+	// Stopping again fails
+	_, err = Stop(app, task.Id, time.Now())
+	assert.ErrorIs(t, err, ErrNoTimerRunning)
+}
+
+func TestLogAndSummary(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	tasks := setupCollections(t, app)
+	task := createTask(t, app, tasks, "todo")
+
+	_, err := Log(app, task.Id, "alice", 45*time.Minute, time.Now(), "review")
+	require.NoError(t, err)
+	_, err = Log(app, task.Id, "alice", 0, time.Now(), "")
+	assert.Error(t, err)
+
+	_, err = Start(app, task.Id, "alice", SourceTimer, time.Now().Add(-15*time.Minute))
+	require.NoError(t, err)
+
+	summary, err := TaskSummary(app, task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Entries)
+	assert.True(t, summary.Running)
+	assert.GreaterOrEqual(t, summary.Total, 60*time.Minute)
+}
+
+func TestHandleColumnChange(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	tasks := setupCollections(t, app)
+	task := createTask(t, app, tasks, "todo")
+	cfg := config.TimeTrackingConfig{}
+
+	// Entering in_progress starts an auto timer attributed to the last actor
+	require.NoError(t, HandleColumnChange(app, task, "todo", "in_progress", cfg))
+	running, err := FindRunning(app, task.Id)
+	require.NoError(t, err)
+	require.NotNil(t, running)
+	assert.Equal(t, SourceAuto, running.GetString("source"))
+	assert.Equal(t, "claude", running.GetString("actor"))
+
+	// Moving to need_input pauses it
+	require.NoError(t, HandleColumnChange(app, task, "in_progress", "need_input", cfg))
+	running, err = FindRunning(app, task.Id)
+	require.NoError(t, err)
+	assert.Nil(t, running)
+
+	// Returning resumes with a new entry
+	require.NoError(t, HandleColumnChange(app, task, "need_input", "in_progress", cfg))
+	entries, err := Entries(app, task.Id)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestHandleColumnChange_Configurable(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	tasks := setupCollections(t, app)
+	task := createTask(t, app, tasks, "in_progress")
+
+	// need_input as a tracked column keeps the timer running
+	cfg := config.TimeTrackingConfig{Columns: []string{"in_progress", "need_input"}}
+	require.NoError(t, HandleColumnChange(app, task, "todo", "in_progress", cfg))
+	require.NoError(t, HandleColumnChange(app, task, "in_progress", "need_input", cfg))
+	running, err := FindRunning(app, task.Id)
+	require.NoError(t, err)
+	assert.NotNil(t, running)
+
+	// Manual mode never touches timers
+	manual := config.TimeTrackingConfig{Mode: "manual"}
+	require.NoError(t, HandleColumnChange(app, task, "need_input", "done", manual))
+	running, err = FindRunning(app, task.Id)
+	require.NoError(t, err)
+	assert.NotNil(t, running)
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "<1m"},
+		{45 * time.Minute, "45m"},
+		{2 * time.Hour, "2h"},
+		{2*time.Hour + 15*time.Minute, "2h 15m"},
+		{26 * time.Hour, "26h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatDuration(tt.d))
+		})
+	}
+}
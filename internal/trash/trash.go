@@ -0,0 +1,405 @@
+// Package trash implements soft delete for tasks, epics, and boards.
+//
+// Deleting one of them sets its deleted_at and deleted_by fields instead of
+// removing it, so relations to it (parent, blocked_by, epic, comments) are
+// kept and Restore brings it back unchanged. Trashing a board trashes its
+// tasks and epics along with it, marked with deleted_with so that restoring
+// the board restores them too. Purge removes trashed records for good.
+//
+// Every query that lists records for display must exclude the trash with
+// Exclude.
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+)
+
+// Collections whose records go to the trash instead of being deleted.
+var Collections = []string{"tasks", "epics", "boards"}
+
+// ErrBoardTrashed is returned when restoring a task or epic whose board is
+// still in the trash.
+var ErrBoardTrashed = errors.New("board is in the trash; restore the board first")
+
+// Exclude returns the expression matching the records of a collection that
+// are not in the trash. Collections without the trash fields (databases not
+// yet migrated) match everything.
+func Exclude(app core.App, collection string) dbx.Expression {
+	c, err := app.FindCachedCollectionByNameOrId(collection)
+	if err != nil || !hasTrash(c) {
+		return dbx.NewExp("1=1")
+	}
+	return active
+}
+
+var (
+	active  = dbx.NewExp("deleted_at = ''")
+	trashed = dbx.NewExp("deleted_at != ''")
+)
+
+// IsTrashed reports whether a record is in the trash.
+func IsTrashed(record *core.Record) bool {
+	return !record.GetDateTime("deleted_at").IsZero()
+}
+
+// Task moves a task to the trash. source ("cli", "tui", "api", ...) and
+// actor are recorded in the task event log. Without the trash fields, the
+// task is deleted.
+func Task(app core.App, task *core.Record, source, actor string) error {
+	return TaskWithMetadata(app, task, source, actor, nil)
+}
+
+// TaskWithMetadata moves a task to the trash like Task, recording metadata
+// on the event, such as the proposal that asked for the delete.
+func TaskWithMetadata(app core.App, task *core.Record, source, actor string, metadata map[string]any) error {
+	if !hasTrash(task.Collection()) {
+		return app.Delete(task)
+	}
+	markTrashed(task, actor, "", types.NowDateTime())
+	trashEvent(task, "deleted", source, actor, metadata)
+	return app.Save(task)
+}
+
+// Epic moves an epic to the trash. Its tasks keep their epic.
+func Epic(app core.App, epic *core.Record, actor string) error {
+	if !hasTrash(epic.Collection()) {
+		return app.Delete(epic)
+	}
+	markTrashed(epic, actor, "", types.NowDateTime())
+	return app.Save(epic)
+}
+
+// Board moves a board and all of its tasks and epics to the trash. It
+// returns the number of tasks trashed with the board. Without the trash
+// fields, the board and its tasks and epics are deleted.
+func Board(app core.App, board *core.Record, source, actor string) (int, error) {
+	now := types.NowDateTime()
+	var count int
+
+	err := app.RunInTransaction(func(txApp core.App) error {
+		if !hasTrash(board.Collection()) {
+			for _, name := range []string{"tasks", "epics"} {
+				records, err := txApp.FindAllRecords(name, dbx.HashExp{"board": board.Id})
+				if err != nil {
+					continue
+				}
+				for _, r := range records {
+					if err := txApp.Delete(r); err != nil {
+						return fmt.Errorf("failed to delete %s %s: %w", kindOf(name), r.Id, err)
+					}
+				}
+				if name == "tasks" {
+					count = len(records)
+				}
+			}
+			return txApp.Delete(board)
+		}
+
+		tasks, err := txApp.FindAllRecords("tasks",
+			dbx.HashExp{"board": board.Id}, active)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			markTrashed(task, actor, board.Id, now)
+			trashEvent(task, "deleted", source, actor, nil)
+			if err := txApp.Save(task); err != nil {
+				return fmt.Errorf("failed to trash task %s: %w", task.Id, err)
+			}
+		}
+		count = len(tasks)
+
+		epics, err := txApp.FindAllRecords("epics",
+			dbx.HashExp{"board": board.Id}, active)
+		if err != nil {
+			return err
+		}
+		for _, epic := range epics {
+			markTrashed(epic, actor, board.Id, now)
+			if err := txApp.Save(epic); err != nil {
+				return fmt.Errorf("failed to trash epic %s: %w", epic.Id, err)
+			}
+		}
+
+		markTrashed(board, actor, "", now)
+		return txApp.Save(board)
+	})
+	return count, err
+}
+
+// Restore takes a task, epic, or board out of the trash. Restoring a board
+// restores the tasks and epics trashed with it.
+func Restore(app core.App, record *core.Record, source, actor string) error {
+	if !IsTrashed(record) {
+		return fmt.Errorf("%s is not in the trash", record.Id)
+	}
+
+	if record.Collection().Name != "boards" {
+		if board, err := app.FindRecordById("boards", record.GetString("board")); err == nil && IsTrashed(board) {
+			return ErrBoardTrashed
+		}
+		restore(record, source, actor)
+		return app.Save(record)
+	}
+
+	return app.RunInTransaction(func(txApp core.App) error {
+		for _, name := range []string{"tasks", "epics"} {
+			records, err := txApp.FindAllRecords(name, dbx.HashExp{"deleted_with": record.Id})
+			if err != nil {
+				return err
+			}
+			for _, r := range records {
+				restore(r, source, actor)
+				if err := txApp.Save(r); err != nil {
+					return err
+				}
+			}
+		}
+		restore(record, source, actor)
+		return txApp.Save(record)
+	})
+}
+
+// Item is an entry in the trash listing.
+type Item struct {
+	Record    *core.Record
+	Kind      string    // "task", "epic", or "board"
+	DeletedAt time.Time // When it was trashed
+	DeletedBy string    // Who trashed it
+	Tasks     int       // For boards: number of tasks trashed with it
+	Epics     int       // For boards: number of epics trashed with it
+}
+
+// List returns the trashed records, most recently trashed first. Tasks and
+// epics trashed along with a board are counted on the board's item. With
+// a boardID, only that board's trash is listed.
+func List(app core.App, boardID string) ([]Item, error) {
+	var items []Item
+	withBoard := make(map[string]*Item)
+
+	for _, name := range Collections {
+		exprs := []dbx.Expression{trashed}
+		if boardID != "" {
+			field := "board"
+			if name == "boards" {
+				field = "id"
+			}
+			exprs = append(exprs, dbx.HashExp{field: boardID})
+		}
+
+		records, err := app.FindAllRecords(name, exprs...)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			if r.GetString("deleted_with") != "" {
+				continue
+			}
+			items = append(items, Item{
+				Record:    r,
+				Kind:      kindOf(name),
+				DeletedAt: r.GetDateTime("deleted_at").Time(),
+				DeletedBy: r.GetString("deleted_by"),
+			})
+		}
+	}
+
+	for i := range items {
+		if items[i].Kind == "board" {
+			withBoard[items[i].Record.Id] = &items[i]
+		}
+	}
+	for id, item := range withBoard {
+		tasks, _ := app.CountRecords("tasks", dbx.HashExp{"deleted_with": id})
+		epics, _ := app.CountRecords("epics", dbx.HashExp{"deleted_with": id})
+		item.Tasks, item.Epics = int(tasks), int(epics)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+// PurgeResult counts the records removed by Purge.
+type PurgeResult struct {
+	Tasks  int `json:"tasks"`
+	Epics  int `json:"epics"`
+	Boards int `json:"boards"`
+}
+
+// Purge permanently deletes records trashed before the given time (all
+// trashed records if before is zero), including the tasks and epics trashed
+// with a purged board. References to purged tasks are removed from the
+// blocked_by lists of remaining tasks.
+func Purge(app core.App, before time.Time) (PurgeResult, error) {
+	var result PurgeResult
+
+	err := app.RunInTransaction(func(txApp core.App) error {
+		expired := func(name string) ([]*core.Record, error) {
+			exprs := []dbx.Expression{trashed}
+			if !before.IsZero() {
+				exprs = append(exprs, dbx.NewExp("deleted_at < {:before}",
+					dbx.Params{"before": before.UTC().Format(types.DefaultDateLayout)}))
+			}
+			return txApp.FindAllRecords(name, exprs...)
+		}
+
+		boards, err := expired("boards")
+		if err != nil {
+			return err
+		}
+		var boardIDs []string
+		for _, board := range boards {
+			boardIDs = append(boardIDs, board.Id)
+		}
+
+		// Tasks and epics go first; a board's go with it whatever their age
+		var purgedTasks []string
+		for _, name := range []string{"tasks", "epics"} {
+			records, err := expired(name)
+			if err != nil {
+				return err
+			}
+			if len(boardIDs) > 0 {
+				withBoards, err := txApp.FindAllRecords(name,
+					dbx.In("board", toAny(boardIDs)...), trashed)
+				if err != nil {
+					return err
+				}
+				records = appendUnique(records, withBoards)
+			}
+
+			for _, r := range records {
+				// Purging is permanent, not an undoable deletion
+				undo.Skip(r)
+				if err := txApp.Delete(r); err != nil {
+					return fmt.Errorf("failed to purge %s %s: %w", kindOf(name), r.Id, err)
+				}
+			}
+			if name == "tasks" {
+				result.Tasks = len(records)
+				for _, r := range records {
+					purgedTasks = append(purgedTasks, r.Id)
+				}
+			} else {
+				result.Epics = len(records)
+			}
+		}
+
+		for _, board := range boards {
+			if err := txApp.Delete(board); err != nil {
+				return fmt.Errorf("failed to purge board %s: %w", board.Id, err)
+			}
+		}
+		result.Boards = len(boards)
+
+		return removeBlockers(txApp, purgedTasks)
+	})
+	return result, err
+}
+
+// removeBlockers drops purged task IDs from the blocked_by lists of the
+// remaining tasks, including trashed ones.
+func removeBlockers(app core.App, purged []string) error {
+	if len(purged) == 0 {
+		return nil
+	}
+
+	tasks, err := app.FindAllRecords("tasks",
+		dbx.NewExp("blocked_by IS NOT NULL AND blocked_by != '' AND blocked_by != '[]' AND blocked_by != 'null'"))
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		var blockedBy []string
+		if err := task.UnmarshalJSONField("blocked_by", &blockedBy); err != nil {
+			continue
+		}
+		kept := slices.DeleteFunc(slices.Clone(blockedBy), func(id string) bool {
+			return slices.Contains(purged, id)
+		})
+		if len(kept) == len(blockedBy) {
+			continue
+		}
+		task.Set("blocked_by", kept)
+		if err := app.Save(task); err != nil {
+			return fmt.Errorf("failed to update blockers of task %s: %w", task.Id, err)
+		}
+	}
+	return nil
+}
+
+// ========== Helpers ==========
+
+func hasTrash(c *core.Collection) bool {
+	return c.Fields.GetByName("deleted_at") != nil
+}
+
+func markTrashed(record *core.Record, actor, with string, at types.DateTime) {
+	record.Set("deleted_at", at)
+	record.Set("deleted_by", actor)
+	if record.Collection().Name != "boards" {
+		record.Set("deleted_with", with)
+	}
+}
+
+func restore(record *core.Record, source, actor string) {
+	record.Set("deleted_at", "")
+	record.Set("deleted_by", "")
+	if record.Collection().Name != "boards" {
+		record.Set("deleted_with", "")
+	}
+	if record.Collection().Name == "tasks" {
+		trashEvent(record, "restored", source, actor, nil)
+	}
+}
+
+// trashEvent queues a trash event on a task, naming the task so that the
+// log stays readable once the task is purged.
+func trashEvent(task *core.Record, action, source, actor string, metadata map[string]any) {
+	merged := map[string]any{"title": task.GetString("title")}
+	for key, value := range metadata {
+		merged[key] = value
+	}
+	taskevent.Add(task, action, source, actor, nil, merged)
+}
+
+func kindOf(collection string) string {
+	switch collection {
+	case "tasks":
+		return "task"
+	case "epics":
+		return "epic"
+	default:
+		return "board"
+	}
+}
+
+func toAny(ids []string) []any {
+	out := make([]any, len(ids))
+	for i, id := range ids {
+		out[i] = id
+	}
+	return out
+}
+
+func appendUnique(records, more []*core.Record) []*core.Record {
+	for _, r := range more {
+		if !slices.ContainsFunc(records, func(existing *core.Record) bool { return existing.Id == r.Id }) {
+			records = append(records, r)
+		}
+	}
+	return records
+}
@@ -0,0 +1,200 @@
+package trash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+// setup creates minimal boards, epics, and tasks collections with the
+// trash fields.
+func setup(t *testing.T) *pocketbase.PocketBase {
+	t.Helper()
+
+	app := testutil.NewTestApp(t)
+	trashFields := func(withBoard bool) []core.Field {
+		fields := []core.Field{
+			&core.DateField{Name: "deleted_at"},
+			&core.TextField{Name: "deleted_by"},
+		}
+		if withBoard {
+			fields = append(fields, &core.TextField{Name: "deleted_with"})
+		}
+		return fields
+	}
+
+	testutil.CreateTestCollection(t, app, "boards", append([]core.Field{
+		&core.TextField{Name: "name", Required: true},
+		&core.TextField{Name: "prefix"},
+	}, trashFields(false)...)...)
+	testutil.CreateTestCollection(t, app, "epics", append([]core.Field{
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "board"},
+	}, trashFields(true)...)...)
+	testutil.CreateTestCollection(t, app, "tasks", append([]core.Field{
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "board"},
+		&core.TextField{Name: "epic"},
+		&core.JSONField{Name: "blocked_by"},
+	}, trashFields(true)...)...)
+
+	return app
+}
+
+func newRecord(t *testing.T, app *pocketbase.PocketBase, collection string, fields map[string]any) *core.Record {
+	t.Helper()
+
+	c, err := app.FindCollectionByNameOrId(collection)
+	require.NoError(t, err)
+	record := core.NewRecord(c)
+	for k, v := range fields {
+		record.Set(k, v)
+	}
+	require.NoError(t, app.Save(record))
+	return record
+}
+
+func reload(t *testing.T, app *pocketbase.PocketBase, record *core.Record) *core.Record {
+	t.Helper()
+
+	fresh, err := app.FindRecordById(record.Collection().Name, record.Id)
+	require.NoError(t, err)
+	return fresh
+}
+
+func activeTitles(t *testing.T, app *pocketbase.PocketBase) []string {
+	t.Helper()
+
+	tasks, err := app.FindAllRecords("tasks", Exclude(app, "tasks"))
+	require.NoError(t, err)
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.GetString("title"))
+	}
+	return titles
+}
+
+func TestTaskAndRestore(t *testing.T) {
+	app := setup(t)
+	testutil.CreateTaskEventsCollection(t, app)
+	taskevent.Bind(app)
+	board := newRecord(t, app, "boards", map[string]any{"name": "Work", "prefix": "WRK"})
+	a := newRecord(t, app, "tasks", map[string]any{"title": "A", "board": board.Id})
+	b := newRecord(t, app, "tasks", map[string]any{"title": "B", "board": board.Id, "blocked_by": []string{a.Id}})
+
+	require.NoError(t, Task(app, a, "cli", "ramtin"))
+
+	a = reload(t, app, a)
+	assert.True(t, IsTrashed(a))
+	assert.Equal(t, "ramtin", a.GetString("deleted_by"))
+	assert.Equal(t, []string{"B"}, activeTitles(t, app))
+	assert.Equal(t, []string{a.Id}, reload(t, app, b).GetStringSlice("blocked_by"),
+		"relations to trashed tasks are kept")
+
+	require.NoError(t, Restore(app, a, "cli", "ramtin"))
+	a = reload(t, app, a)
+	assert.False(t, IsTrashed(a))
+	assert.ElementsMatch(t, []string{"A", "B"}, activeTitles(t, app))
+
+	events, err := taskevent.ForTask(app, a.Id)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "deleted", events[1].Action)
+	assert.Equal(t, "restored", events[2].Action)
+	assert.Equal(t, "A", events[1].Metadata["title"])
+
+	assert.Error(t, Restore(app, a, "cli", "ramtin"), "not in the trash")
+}
+
+func TestBoardTrashesItsTasksAndEpics(t *testing.T) {
+	app := setup(t)
+	board := newRecord(t, app, "boards", map[string]any{"name": "Work", "prefix": "WRK"})
+	epic := newRecord(t, app, "epics", map[string]any{"title": "Launch", "board": board.Id})
+	a := newRecord(t, app, "tasks", map[string]any{"title": "A", "board": board.Id, "epic": epic.Id})
+	b := newRecord(t, app, "tasks", map[string]any{"title": "B", "board": board.Id})
+
+	// A task trashed on its own stays trashed when the board is restored
+	require.NoError(t, Task(app, b, "cli", "ramtin"))
+
+	count, err := Board(app, board, "cli", "ramtin")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Empty(t, activeTitles(t, app))
+	assert.Equal(t, board.Id, reload(t, app, epic).GetString("deleted_with"))
+
+	items, err := List(app, "")
+	require.NoError(t, err)
+	require.Len(t, items, 2, "the board and the task trashed on its own")
+	for _, item := range items {
+		switch item.Kind {
+		case "board":
+			assert.Equal(t, 1, item.Tasks)
+			assert.Equal(t, 1, item.Epics)
+		default:
+			assert.Equal(t, b.Id, item.Record.Id)
+		}
+	}
+
+	assert.ErrorIs(t, Restore(app, reload(t, app, b), "cli", "ramtin"), ErrBoardTrashed)
+
+	require.NoError(t, Restore(app, reload(t, app, board), "cli", "ramtin"))
+	assert.Equal(t, []string{"A"}, activeTitles(t, app))
+	assert.False(t, IsTrashed(reload(t, app, epic)))
+	assert.Equal(t, epic.Id, reload(t, app, a).GetString("epic"))
+}
+
+func TestPurge(t *testing.T) {
+	app := setup(t)
+	board := newRecord(t, app, "boards", map[string]any{"name": "Work", "prefix": "WRK"})
+	old := newRecord(t, app, "tasks", map[string]any{"title": "Old", "board": board.Id})
+	recent := newRecord(t, app, "tasks", map[string]any{"title": "Recent", "board": board.Id})
+	blocked := newRecord(t, app, "tasks", map[string]any{
+		"title": "Blocked", "board": board.Id, "blocked_by": []string{old.Id, recent.Id},
+	})
+
+	require.NoError(t, Task(app, old, "cli", "ramtin"))
+	require.NoError(t, Task(app, recent, "cli", "ramtin"))
+
+	// Backdate the first deletion
+	_, err := app.DB().NewQuery("UPDATE tasks SET deleted_at = {:at} WHERE id = {:id}").
+		Bind(dbx.Params{"at": "2020-01-01 00:00:00.000Z", "id": old.Id}).Execute()
+	require.NoError(t, err)
+
+	result, err := Purge(app, time.Now().AddDate(0, 0, -30))
+	require.NoError(t, err)
+	assert.Equal(t, PurgeResult{Tasks: 1}, result)
+
+	_, err = app.FindRecordById("tasks", old.Id)
+	assert.Error(t, err, "old trash is purged")
+	assert.True(t, IsTrashed(reload(t, app, recent)), "recent trash is kept")
+	assert.Equal(t, []string{recent.Id}, reload(t, app, blocked).GetStringSlice("blocked_by"))
+
+	// Without a cutoff everything goes, including a trashed board's tasks
+	_, err = Board(app, board, "cli", "ramtin")
+	require.NoError(t, err)
+	result, err = Purge(app, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, PurgeResult{Tasks: 2, Boards: 1}, result)
+	count, err := app.CountRecords("tasks")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestExclude_UnmigratedCollection(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+	)
+
+	tasks, err := app.FindAllRecords("tasks", Exclude(app, "tasks"))
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
@@ -13,7 +13,11 @@ import (
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
 	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskquery"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasktemplate"
 )
 
 // ViewState represents which view is currently active
@@ -25,6 +29,7 @@ const (
 	ViewTaskForm
 	ViewConfirm
 	ViewBoardSelector
+	ViewProposals
 )
 
 // App is the main TUI application model.
@@ -60,6 +65,7 @@ type App struct {
 	taskForm      *TaskForm
 	confirmDialog *ConfirmDialog
 	boardSelector *BoardSelector
+	proposalPanel *ProposalPanel
 
 	// Header component
 	header *Header
@@ -81,6 +87,9 @@ type App struct {
 	// Initial board reference (from CLI flag)
 	initialBoardRef string
 
+	// Which tasks to show with respect to the archive (from CLI flags)
+	archived archive.Mode
+
 	// Error state
 	err error
 
@@ -94,8 +103,10 @@ type App struct {
 	pendingFilterKey bool // True after 'f' is pressed
 
 	// Cached filter data
-	availableLabels []string
-	availableEpics  []EpicOption
+	availableLabels    []string
+	availableEpics     []EpicOption
+	availableSprints   []SprintOption
+	availableAssignees []string
 
 	// Help overlay
 	helpOverlay *HelpOverlay
@@ -113,7 +124,8 @@ type App struct {
 
 // NewApp creates a new TUI application.
 // boardRef is optional - if empty, uses the first available board.
-func NewApp(pb *pocketbase.PocketBase, boardRef string) *App {
+// archived selects whether archived tasks are shown.
+func NewApp(pb *pocketbase.PocketBase, boardRef string, archived archive.Mode) *App {
 	h := help.New()
 	h.ShowAll = false
 
@@ -130,6 +142,7 @@ func NewApp(pb *pocketbase.PocketBase, boardRef string) *App {
 		header:              NewHeader(),
 		focusedCol:          0,
 		initialBoardRef:     boardRef,
+		archived:            archived,
 		columnOrder:         []string{"backlog", "todo", "in_progress", "need_input", "review", "done"},
 		view:                ViewBoard,
 		lastPollTime:        time.Now(),
@@ -163,7 +176,7 @@ func (a *App) Init() tea.Cmd {
 	// Load session state to restore filters from previous run.
 	return tea.Batch(
 		loadBoards(a.pb),
-		loadBoardAndTasks(a.pb, a.initialBoardRef),
+		loadBoardAndTasks(a.pb, a.initialBoardRef, a.archived),
 		CheckServerStatus(a.serverURL),
 		loadSession,
 	)
@@ -204,6 +217,9 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if a.boardSelector != nil {
 			a.boardSelector.SetSize(min(60, a.width-4), min(20, a.height-4))
 		}
+		if a.proposalPanel != nil {
+			a.proposalPanel.SetSize(min(72, a.width-4), min(24, a.height-4))
+		}
 		// Update filter component sizes
 		a.filterBar.SetWidth(msg.Width)
 		a.searchOverlay.SetSize(msg.Width, msg.Height)
@@ -219,10 +235,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.initializeColumns(msg.tasks)
 		a.updateHeaderInfo()
 		a.ready = true
-		// Load epics, labels, and subtask counts for filtering and badge display
+		// Load epics, labels, assignees, and subtask counts for filtering and badge display
 		return a, tea.Batch(
 			CmdLoadEpics(a.pb, msg.board.Id),
+			CmdLoadSprints(a.pb, msg.board.Id),
 			CmdLoadLabels(a.pb, msg.board.Id),
+			CmdLoadAssignees(a.pb, msg.board.Id),
 			CmdLoadSubtaskCounts(a.pb, msg.board.Id),
 		)
 
@@ -259,7 +277,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Load the new board's data
-		return a, switchBoard(a.pb, msg.boardID)
+		return a, switchBoard(a.pb, msg.boardID, a.archived)
 
 	case boardTasksLoadedMsg:
 		if a.currentBoard != nil {
@@ -290,7 +308,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// Reload tasks and show success message
 		cmds = append(cmds,
-			loadTasks(a.pb, a.currentBoard.Id),
+			loadTasks(a.pb, a.currentBoard.Id, a.archived),
 			showStatus("Created: "+msg.task.GetString("title")+" ["+msg.displayID+"]", false, 3*time.Second),
 		)
 		// Close form
@@ -304,7 +322,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// Reload tasks and show success message
 		cmds = append(cmds,
-			loadTasks(a.pb, a.currentBoard.Id),
+			loadTasks(a.pb, a.currentBoard.Id, a.archived),
 			showStatus("Updated: "+msg.task.GetString("title"), false, 3*time.Second),
 		)
 		// Close form and update detail if open
@@ -322,7 +340,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// Reload tasks and show success message
 		cmds = append(cmds,
-			loadTasks(a.pb, a.currentBoard.Id),
+			loadTasks(a.pb, a.currentBoard.Id, a.archived),
 			showStatus("Deleted: "+msg.title, false, 3*time.Second),
 		)
 		// Close any overlays
@@ -338,7 +356,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		resultMsg := FormatBulkResult(msg.Result)
 		isError := len(msg.Result.FailedIDs) > 0
 		cmds = append(cmds,
-			loadTasks(a.pb, a.currentBoard.Id),
+			loadTasks(a.pb, a.currentBoard.Id, a.archived),
 			showStatus(resultMsg, isError, 3*time.Second),
 		)
 		// Close any overlays
@@ -352,7 +370,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 		// Reload tasks and show status
-		cmds = append(cmds, loadTasks(a.pb, a.currentBoard.Id))
+		cmds = append(cmds, loadTasks(a.pb, a.currentBoard.Id, a.archived))
 		if msg.fromColumn != msg.toColumn {
 			cmds = append(cmds, showStatus("Moved to "+msg.toColumn, false, 2*time.Second))
 		}
@@ -361,6 +379,52 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.updateColumnsWithTasks(msg.tasks)
 		return a, tea.Batch(cmds...)
 
+	// =================================================================
+	// Proposal Messages
+	// =================================================================
+
+	case ProposalsLoadedMsg:
+		if a.proposalPanel == nil {
+			return a, nil
+		}
+		if msg.Err != nil {
+			a.proposalPanel.SetProposals(nil)
+			return a, showStatus("Failed to load proposals: "+msg.Err.Error(), true, 3*time.Second)
+		}
+		a.proposalPanel.SetProposals(msg.Proposals)
+		return a, nil
+
+	case ProposalReviewedMsg:
+		if a.currentBoard == nil {
+			return a, nil
+		}
+		if msg.Err != nil {
+			cmds = append(cmds, showStatus("Review failed: "+msg.Err.Error(), true, 3*time.Second))
+		} else if msg.Approved {
+			cmds = append(cmds, showStatus("Approved: "+msg.Summary, false, 3*time.Second))
+		} else {
+			cmds = append(cmds, showStatus("Rejected: "+msg.Summary, false, 3*time.Second))
+		}
+		// Reload both the board and the panel; an approval changes tasks
+		cmds = append(cmds,
+			loadTasks(a.pb, a.currentBoard.Id, a.archived),
+			CmdLoadProposals(a.pb, a.currentBoard.Id),
+		)
+
+	case UndoneMsg:
+		action, done := "undo", "Undone"
+		if msg.Redo {
+			action, done = "redo", "Redone"
+		}
+		if msg.Err != nil {
+			cmds = append(cmds, showStatus("Cannot "+action+": "+undoErrorText(msg.Err), true, 5*time.Second))
+		} else {
+			cmds = append(cmds, showStatus(done+": "+msg.Label, false, 3*time.Second))
+			if a.currentBoard != nil {
+				cmds = append(cmds, loadTasks(a.pb, a.currentBoard.Id, a.archived))
+			}
+		}
+
 	// =================================================================
 	// View State Messages
 	// =================================================================
@@ -368,6 +432,38 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case openTaskDetailMsg:
 		a.taskDetail = NewTaskDetail(msg.task, a.width/2, a.height-4)
 		a.view = ViewTaskDetail
+		cmds = append(cmds, CmdLoadTimeLogged(a.pb, msg.task.ID))
+		cmds = append(cmds, CmdLoadEstimate(a.pb, msg.task.ID))
+		cmds = append(cmds, CmdLoadAttachments(a.pb, msg.task.ID))
+		cmds = append(cmds, CmdLoadLinks(a.pb, msg.task.ID))
+
+	case TimeLoggedMsg:
+		// Ignore stale results if the panel was closed or switched
+		if msg.Err == nil && a.taskDetail != nil && a.taskDetail.Task().ID == msg.TaskID {
+			a.taskDetail.SetTimeLogged(msg.Summary)
+		}
+		return a, nil
+
+	case EstimateLoadedMsg:
+		// Ignore stale results if the panel was closed or switched
+		if msg.Err == nil && a.taskDetail != nil && a.taskDetail.Task().ID == msg.TaskID {
+			a.taskDetail.SetEstimate(msg.Totals, msg.Unit)
+		}
+		return a, nil
+
+	case AttachmentsLoadedMsg:
+		// Ignore stale results if the panel was closed or switched
+		if msg.Err == nil && a.taskDetail != nil && a.taskDetail.Task().ID == msg.TaskID {
+			a.taskDetail.SetAttachments(msg.Attachments)
+		}
+		return a, nil
+
+	case LinksLoadedMsg:
+		// Ignore stale results if the panel was closed or switched
+		if msg.Err == nil && a.taskDetail != nil && a.taskDetail.Task().ID == msg.TaskID {
+			a.taskDetail.SetLinks(msg.Links)
+		}
+		return a, nil
 
 	case closeTaskDetailMsg:
 		a.taskDetail = nil
@@ -378,6 +474,13 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.taskForm = NewTaskFormWithData(msg.task, a.width/2, a.height-10)
 		} else {
 			a.taskForm = NewTaskForm(FormModeAdd, a.width/2, a.height-10)
+			if a.currentBoard != nil {
+				templates, err := tasktemplate.List(a.pb, a.currentBoard.Id, ".")
+				if err != nil {
+					debugLog("loading templates: %v", err)
+				}
+				a.taskForm.SetTemplates(templates)
+			}
 		}
 		a.view = ViewTaskForm
 
@@ -415,7 +518,9 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.view = ViewBoard
 
 	case submitTaskFormMsg:
-		if msg.mode == FormModeAdd {
+		if msg.mode == FormModeAdd && msg.data.Template != "" {
+			cmds = append(cmds, createTasksFromTemplate(a.pb, a.currentBoard, msg.data))
+		} else if msg.mode == FormModeAdd {
 			cmds = append(cmds, createTask(a.pb, a.currentBoard, msg.data))
 		} else {
 			cmds = append(cmds, updateTask(a.pb, msg.taskID, msg.data))
@@ -520,7 +625,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case pollTickMsg:
 		// Time to poll for changes
 		if a.usePolling && a.currentBoard != nil {
-			cmds = append(cmds, PollForChanges(a.pb, a.currentBoard.Id, a.lastPollTime))
+			cmds = append(cmds, PollForChanges(a.pb, a.currentBoard.Id, a.archived, a.lastPollTime))
 		}
 
 	case pollResultMsg:
@@ -553,6 +658,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, nil
 
 	case SearchAppliedMsg:
+		// Text with field:value terms is a query rather than a search
+		if taskquery.IsQuery(msg.Query) {
+			return a, a.applyQuery(msg.Query)
+		}
 		a.refreshFilteredColumns()
 		return a, nil
 
@@ -567,6 +676,23 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.availableLabels = msg.Labels
 		return a, nil
 
+	case SprintsLoadedMsg:
+		a.availableSprints = msg.Sprints
+		return a, nil
+
+	case AssigneesLoadedMsg:
+		a.availableAssignees = msg.Assignees
+		return a, nil
+
+	case ViewsLoadedMsg:
+		if msg.Err != nil {
+			return a, showStatus(fmt.Sprintf("Failed to load views: %v", msg.Err), true, 3*time.Second)
+		}
+		if len(msg.Views) == 0 {
+			return a, showStatus("No saved views for this board", true, 2*time.Second)
+		}
+		return a, a.filterSelector.ShowViews(msg.Views)
+
 	case EpicsLoadedMsg:
 		a.availableEpics = msg.Epics
 		// Refresh columns to show epic badges on tasks
@@ -605,6 +731,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Restore filter state from session
 			if msg.Session.FilterState != nil {
 				a.filterState = msg.Session.FilterState
+				a.filterState.Bind(a.pb, a.currentBoardID())
 				a.filterBar = NewFilterBar(a.filterState)
 				a.searchOverlay = NewSearchOverlay(a.filterState)
 			}
@@ -667,6 +794,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a.handleConfirmKeys(msg)
 		case ViewBoardSelector:
 			return a.handleBoardSelectorKeys(msg)
+		case ViewProposals:
+			return a.handleProposalsKeys(msg)
 		}
 	}
 
@@ -769,6 +898,28 @@ func (a *App) handleBoardKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.openBoardSelector()
 		return a, nil
 
+	case "p":
+		// Open proposal review panel
+		return a, a.openProposalPanel()
+
+	case "u":
+		// Undo the last change
+		return a, undoLastChange(a.pb, false)
+
+	case "ctrl+r":
+		// Redo the last undone change
+		return a, undoLastChange(a.pb, true)
+
+	case "r":
+		// Reload tasks
+		if a.currentBoard == nil {
+			return a, nil
+		}
+		return a, tea.Batch(
+			loadTasks(a.pb, a.currentBoard.Id, a.archived),
+			showStatus("Refreshing...", false, 2*time.Second),
+		)
+
 	case "n":
 		// New task
 		return a, func() tea.Msg {
@@ -868,7 +1019,7 @@ func (a *App) handleBoardKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
-// handleFilterKey handles the second key in a filter sequence (fp, ft, fl, fe, fc)
+// handleFilterKey handles the second key in a filter sequence (fp, ft, fl, fe, fs, fa, fv, fc)
 func (a *App) handleFilterKey(key string) (tea.Model, tea.Cmd) {
 	switch key {
 	case "p":
@@ -897,11 +1048,25 @@ func (a *App) handleFilterKey(key string) (tea.Model, tea.Cmd) {
 		cmd := a.filterSelector.ShowEpic(a.availableEpics)
 		return a, cmd
 
+	case "s":
+		// Filter by sprint
+		cmd := a.filterSelector.ShowSprint(a.availableSprints)
+		return a, cmd
+
+	case "a":
+		// Filter by assignee
+		cmd := a.filterSelector.ShowAssignee(a.availableAssignees, config.CurrentUser())
+		return a, cmd
+
 	case "b":
 		// Filter by blocked status
 		cmd := a.filterSelector.ShowBlocked()
 		return a, cmd
 
+	case "v":
+		// Apply a saved view (loaded fresh so views saved elsewhere show up)
+		return a, a.loadViews()
+
 	case "c":
 		// Clear all filters
 		a.filterState.Clear()
@@ -1004,6 +1169,75 @@ func (a *App) handleBoardSelectorKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, cmd
 }
 
+// handleProposalsKeys processes keyboard input when in proposal panel view.
+func (a *App) handleProposalsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.proposalPanel == nil {
+		a.view = ViewBoard
+		return a, nil
+	}
+
+	switch msg.String() {
+	case "esc", "p", "q":
+		a.view = ViewBoard
+		a.proposalPanel = nil
+		return a, nil
+	case "j", "down":
+		a.proposalPanel.MoveDown()
+	case "k", "up":
+		a.proposalPanel.MoveUp()
+	case "a", "x":
+		if record := a.proposalPanel.Selected(); record != nil {
+			return a, CmdReviewProposal(a.pb, record, msg.String() == "a")
+		}
+	}
+	return a, nil
+}
+
+// openProposalPanel opens the proposal review overlay for the current board.
+func (a *App) openProposalPanel() tea.Cmd {
+	if a.currentBoard == nil {
+		return nil
+	}
+	a.proposalPanel = NewProposalPanel(min(72, a.width-4), min(24, a.height-4))
+	a.view = ViewProposals
+	return CmdLoadProposals(a.pb, a.currentBoard.Id)
+}
+
+// applyQuery replaces the search with a query filter.
+func (a *App) applyQuery(text string) tea.Cmd {
+	compiled, err := taskquery.ParseAndCompile(a.pb, text, taskquery.Options{BoardID: a.currentBoardID()})
+	if err != nil {
+		return showStatus(err.Error(), true, 4*time.Second)
+	}
+	a.filterState.SetSearchQuery("")
+	a.filterState.AddFilter(Filter{
+		Field:    "query",
+		Operator: "matches",
+		Value:    compiled.String(),
+		Display:  "Query: " + compiled.String(),
+		Query:    compiled,
+	})
+	a.refreshFilteredColumns()
+	return nil
+}
+
+// currentBoardID returns the ID of the displayed board, or "" before one
+// is loaded.
+func (a *App) currentBoardID() string {
+	if a.currentBoard == nil {
+		return ""
+	}
+	return a.currentBoard.Id
+}
+
+// loadViews loads the current board's saved views for the view picker.
+func (a *App) loadViews() tea.Cmd {
+	if a.currentBoard == nil {
+		return nil
+	}
+	return CmdLoadViews(a.pb, a.currentBoard.Id)
+}
+
 // openBoardSelector opens the board selector overlay.
 func (a *App) openBoardSelector() {
 	if len(a.boards) == 0 {
@@ -1194,6 +1428,15 @@ func (a *App) createCommandActions() *CommandActions {
 			}
 			return a.filterSelector.ShowEpic(a.availableEpics)
 		},
+		FilterBySprint: func() tea.Cmd {
+			return a.filterSelector.ShowSprint(a.availableSprints)
+		},
+		FilterByAssignee: func() tea.Cmd {
+			return a.filterSelector.ShowAssignee(a.availableAssignees, config.CurrentUser())
+		},
+		FilterByView: func() tea.Cmd {
+			return a.loadViews()
+		},
 		FilterByLabel: func() tea.Cmd {
 			if len(a.availableLabels) == 0 {
 				return showStatus("No labels available", true, 2*time.Second)
@@ -1209,10 +1452,13 @@ func (a *App) createCommandActions() *CommandActions {
 			a.openBoardSelector()
 			return nil
 		},
+		ReviewProposals: func() tea.Cmd {
+			return a.openProposalPanel()
+		},
 		Refresh: func() tea.Cmd {
 			if a.currentBoard != nil {
 				return tea.Batch(
-					loadTasks(a.pb, a.currentBoard.Id),
+					loadTasks(a.pb, a.currentBoard.Id, a.archived),
 					showStatus("Refreshing...", false, 2*time.Second),
 				)
 			}
@@ -1441,6 +1687,15 @@ func (a *App) View() string {
 			sections = append(sections, boardView)
 		}
 
+	case ViewProposals:
+		// Board with proposal panel overlay
+		boardView := a.renderColumns()
+		if a.proposalPanel != nil {
+			sections = append(sections, a.overlayCenter(boardView, a.proposalPanel.View()))
+		} else {
+			sections = append(sections, boardView)
+		}
+
 	default:
 		// Normal board view
 		sections = append(sections, a.renderColumns())
@@ -1659,6 +1914,7 @@ func (a *App) renderStatusBar() string {
 					"n: new",
 					"e: edit",
 					"d: delete",
+					"u: undo",
 					"b: boards",
 					"ctrl+k: cmds",
 					"q: quit",
@@ -1688,6 +1944,13 @@ func (a *App) renderStatusBar() string {
 				"enter: select",
 				"esc/b: cancel",
 			}
+		case ViewProposals:
+			hints = []string{
+				"j/k: navigate",
+				"a: approve",
+				"x: reject",
+				"esc/p: close",
+			}
 		}
 		left = statusBarStyle.Render(strings.Join(hints, " | "))
 	}
@@ -2001,6 +2264,14 @@ func (a *App) handleRealtimeEvent(event RealtimeEvent) tea.Cmd {
 	case "create":
 		return a.handleTaskCreated(event.Record)
 	case "update":
+		// Moving a task to the trash is an update
+		if deletedAt, _ := event.Record["deleted_at"].(string); deletedAt != "" {
+			return a.handleTaskDeleted(event.Record)
+		}
+		// So is archiving or unarchiving it
+		if archivedAt, _ := event.Record["archived_at"].(string); !a.archived.Includes(archivedAt != "") {
+			return a.handleTaskDeleted(event.Record)
+		}
 		return a.handleTaskUpdated(event.Record)
 	case "delete":
 		return a.handleTaskDeleted(event.Record)
@@ -2129,8 +2400,8 @@ func matchKey(msg tea.KeyMsg, binding key.Binding) bool {
 
 // Run starts the TUI application.
 // This is the main entry point called from the CLI command.
-func Run(pb *pocketbase.PocketBase, boardRef string) error {
-	app := NewApp(pb, boardRef)
+func Run(pb *pocketbase.PocketBase, boardRef string, archived archive.Mode) error {
+	app := NewApp(pb, boardRef, archived)
 
 	// Create program with alt screen (full terminal takeover)
 	p := tea.NewProgram(app, tea.WithAltScreen())
@@ -6,6 +6,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
 )
 
 // BulkOperationType represents the type of bulk operation.
@@ -57,6 +60,7 @@ func BulkMove(app *pocketbase.PocketBase, taskIDs []string, targetColumn, boardI
 		result := BulkResult{
 			Operation: BulkOpMove,
 		}
+		batch := undo.NewBatch(fmt.Sprintf("move %d tasks to %s", len(taskIDs), targetColumn), undo.Current().Actor)
 
 		for _, id := range taskIDs {
 			record, err := app.FindRecordById("tasks", id)
@@ -71,6 +75,7 @@ func BulkMove(app *pocketbase.PocketBase, taskIDs []string, targetColumn, boardI
 
 			record.Set("column", targetColumn)
 			record.Set("position", position)
+			undo.Tag(record, batch)
 
 			if err := app.Save(record); err != nil {
 				result.FailedIDs = append(result.FailedIDs, id)
@@ -85,12 +90,13 @@ func BulkMove(app *pocketbase.PocketBase, taskIDs []string, targetColumn, boardI
 	}
 }
 
-// BulkDelete deletes multiple tasks.
+// BulkDelete moves multiple tasks to the trash.
 func BulkDelete(app *pocketbase.PocketBase, taskIDs []string) tea.Cmd {
 	return func() tea.Msg {
 		result := BulkResult{
 			Operation: BulkOpDelete,
 		}
+		batch := undo.NewBatch(fmt.Sprintf("delete %d tasks", len(taskIDs)), undo.Current().Actor)
 
 		for _, id := range taskIDs {
 			record, err := app.FindRecordById("tasks", id)
@@ -100,7 +106,8 @@ func BulkDelete(app *pocketbase.PocketBase, taskIDs []string) tea.Cmd {
 				continue
 			}
 
-			if err := app.Delete(record); err != nil {
+			undo.Tag(record, batch)
+			if err := trash.Task(app, record, "tui", batch.Actor); err != nil {
 				result.FailedIDs = append(result.FailedIDs, id)
 				result.Errors = append(result.Errors, fmt.Errorf("task %s: %w", id, err))
 				continue
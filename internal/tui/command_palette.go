@@ -63,8 +63,12 @@ type CommandActions struct {
 	FilterByType     func() tea.Cmd
 	FilterByEpic     func() tea.Cmd
 	FilterByLabel    func() tea.Cmd
+	FilterBySprint   func() tea.Cmd
+	FilterByAssignee func() tea.Cmd
+	FilterByView     func() tea.Cmd
 	ClearFilters     func() tea.Cmd
 	SwitchBoard      func() tea.Cmd
+	ReviewProposals  func() tea.Cmd
 	Refresh          func() tea.Cmd
 	ToggleHelp       func() tea.Cmd
 	SelectAll        func() tea.Cmd
@@ -92,16 +96,20 @@ func DefaultCommands(actions *CommandActions) []Command {
 		{ID: "move-done", Name: "Move to Done", Description: "Mark task as done", Shortcut: "6", Category: "Movement", Action: actions.MoveToColumn("done")},
 
 		// Filter Commands
-		{ID: "search", Name: "Search", Description: "Search tasks", Shortcut: "/", Category: "Filter", Action: actions.Search},
+		{ID: "search", Name: "Search", Description: "Search tasks or filter with a query", Shortcut: "/", Category: "Filter", Action: actions.Search},
 		{ID: "filter-priority", Name: "Filter by Priority", Description: "Filter tasks by priority", Shortcut: "fp", Category: "Filter", Action: actions.FilterByPriority},
 		{ID: "filter-type", Name: "Filter by Type", Description: "Filter tasks by type", Shortcut: "ft", Category: "Filter", Action: actions.FilterByType},
 		{ID: "filter-epic", Name: "Filter by Epic", Description: "Filter tasks by epic", Shortcut: "fe", Category: "Filter", Action: actions.FilterByEpic},
 		{ID: "filter-label", Name: "Filter by Label", Description: "Filter tasks by label", Shortcut: "fl", Category: "Filter", Action: actions.FilterByLabel},
+		{ID: "filter-sprint", Name: "Filter by Sprint", Description: "Filter tasks by sprint", Shortcut: "fs", Category: "Filter", Action: actions.FilterBySprint},
+		{ID: "filter-assignee", Name: "Filter by Assignee", Description: "Filter tasks by assignee", Shortcut: "fa", Category: "Filter", Action: actions.FilterByAssignee},
+		{ID: "filter-view", Name: "Apply Saved View", Description: "Filter tasks with a view saved in the CLI or web UI", Shortcut: "fv", Category: "Filter", Action: actions.FilterByView},
 		{ID: "clear-filters", Name: "Clear Filters", Description: "Remove all active filters", Shortcut: "fc", Category: "Filter", Action: actions.ClearFilters},
 
 		// View Commands
 		{ID: "switch-board", Name: "Switch Board", Description: "Change to different board", Shortcut: "b", Category: "View", Action: actions.SwitchBoard},
-		{ID: "refresh", Name: "Refresh", Description: "Reload all data", Shortcut: "Ctrl+R", Category: "View", Action: actions.Refresh},
+		{ID: "review-proposals", Name: "Review Proposals", Description: "Approve or reject agent proposals", Shortcut: "p", Category: "View", Action: actions.ReviewProposals},
+		{ID: "refresh", Name: "Refresh", Description: "Reload all data", Shortcut: "r", Category: "View", Action: actions.Refresh},
 		{ID: "toggle-help", Name: "Toggle Help", Description: "Show/hide keyboard shortcuts", Shortcut: "?", Category: "View", Action: actions.ToggleHelp},
 
 		// Bulk Commands
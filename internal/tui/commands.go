@@ -3,12 +3,15 @@ package tui
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,9 +19,21 @@ import (
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
 
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
 	"github.com/ramtinJ95/EgenSkriven/internal/board"
 	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/estimate"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
 	"github.com/ramtinJ95/EgenSkriven/internal/position"
+	"github.com/ramtinJ95/EgenSkriven/internal/sprint"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasklink"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasktemplate"
+	"github.com/ramtinJ95/EgenSkriven/internal/timetrack"
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+	"github.com/ramtinJ95/EgenSkriven/internal/view"
 )
 
 // debugLog logs a message only when EGENSKRIVEN_DEBUG is set
@@ -55,11 +70,13 @@ func loadBoards(app *pocketbase.PocketBase) tea.Cmd {
 
 // loadTasks creates a command that loads all tasks for a specific board.
 // Tasks are loaded and will be grouped by column in the Update handler.
-func loadTasks(app *pocketbase.PocketBase, boardID string) tea.Cmd {
+func loadTasks(app *pocketbase.PocketBase, boardID string, archived archive.Mode) tea.Cmd {
 	return func() tea.Msg {
 		// Build query for tasks in this board
 		records, err := app.FindAllRecords("tasks",
 			dbx.NewExp("board = {:board}", dbx.Params{"board": boardID}),
+			trash.Exclude(app, "tasks"),
+			archive.Filter(app, archived),
 		)
 		if err != nil {
 			return errMsg{err: fmt.Errorf("failed to load tasks: %w", err), context: "loading tasks"}
@@ -98,7 +115,7 @@ func getColumnOrder(column string) int {
 
 // loadBoardAndTasks creates a command that loads a specific board and its tasks.
 // This is a convenience function for initial load.
-func loadBoardAndTasks(app *pocketbase.PocketBase, boardRef string) tea.Cmd {
+func loadBoardAndTasks(app *pocketbase.PocketBase, boardRef string, archived archive.Mode) tea.Cmd {
 	return func() tea.Msg {
 		// First, find the board
 		var boardRecord *core.Record
@@ -125,6 +142,8 @@ func loadBoardAndTasks(app *pocketbase.PocketBase, boardRef string) tea.Cmd {
 		// Load tasks for this board
 		records, err := app.FindAllRecords("tasks",
 			dbx.NewExp("board = {:board}", dbx.Params{"board": boardRecord.Id}),
+			trash.Exclude(app, "tasks"),
+			archive.Filter(app, archived),
 		)
 		if err != nil {
 			return errMsg{err: fmt.Errorf("failed to load tasks: %w", err), context: "loading tasks"}
@@ -150,10 +169,12 @@ func loadBoardAndTasks(app *pocketbase.PocketBase, boardRef string) tea.Cmd {
 
 // loadBoardTasks returns a command that loads tasks for a specific board.
 // Returns boardTasksLoadedMsg (used during board switching).
-func loadBoardTasks(app *pocketbase.PocketBase, boardID string) tea.Cmd {
+func loadBoardTasks(app *pocketbase.PocketBase, boardID string, archived archive.Mode) tea.Cmd {
 	return func() tea.Msg {
 		records, err := app.FindAllRecords("tasks",
 			dbx.NewExp("board = {:board}", dbx.Params{"board": boardID}),
+			trash.Exclude(app, "tasks"),
+			archive.Filter(app, archived),
 		)
 		if err != nil {
 			return errMsg{err: fmt.Errorf("failed to load tasks: %w", err), context: "loading tasks"}
@@ -194,6 +215,7 @@ func getTaskCountsForBoards(app *pocketbase.PocketBase, boardIDs []string) map[s
 	for _, boardID := range boardIDs {
 		records, err := app.FindAllRecords("tasks",
 			dbx.NewExp("board = {:board}", dbx.Params{"board": boardID}),
+			trash.Exclude(app, "tasks"),
 		)
 		if err == nil {
 			counts[boardID] = len(records)
@@ -236,14 +258,16 @@ func loadDefaultBoard() tea.Cmd {
 
 // switchBoard returns a command sequence for switching to a new board.
 // It loads the board's tasks and columns, then saves it as the last-used board.
-// Also loads epics and labels for filtering and badge display.
-func switchBoard(app *pocketbase.PocketBase, boardID string) tea.Cmd {
+// Also loads epics, labels, and assignees for filtering and badge display.
+func switchBoard(app *pocketbase.PocketBase, boardID string, archived archive.Mode) tea.Cmd {
 	return tea.Batch(
-		loadBoardTasks(app, boardID),
+		loadBoardTasks(app, boardID, archived),
 		loadBoardColumns(app, boardID),
 		saveLastBoard(boardID),
 		CmdLoadEpics(app, boardID),
+		CmdLoadSprints(app, boardID),
 		CmdLoadLabels(app, boardID),
+		CmdLoadAssignees(app, boardID),
 		CmdLoadSubtaskCounts(app, boardID),
 	)
 }
@@ -355,17 +379,7 @@ func createTask(app *pocketbase.PocketBase, boardRecord *core.Record, data TaskF
 			record.Set("epic", data.EpicID)
 		}
 
-		// Initialize history
-		history := []map[string]any{
-			{
-				"timestamp":    time.Now().UTC().Format(time.RFC3339),
-				"action":       "created",
-				"actor":        "tui",
-				"actor_detail": "",
-				"changes":      nil,
-			},
-		}
-		record.Set("history", history)
+		taskevent.Add(record, "created", "tui", "", nil, nil)
 
 		// Save using hybrid pattern
 		if err := saveRecordHybrid(app, record); err != nil {
@@ -379,6 +393,49 @@ func createTask(app *pocketbase.PocketBase, boardRecord *core.Record, data TaskF
 	}
 }
 
+// createTasksFromTemplate creates a task and its subtasks from a template,
+// with the form's values for the parent task.
+func createTasksFromTemplate(app *pocketbase.PocketBase, boardRecord *core.Record, data TaskFormData) tea.Cmd {
+	return func() tea.Msg {
+		if boardRecord == nil {
+			return errMsg{err: errors.New("no board selected"), context: "using template"}
+		}
+
+		t, err := tasktemplate.Find(app, boardRecord.Id, ".", data.Template)
+		if err != nil {
+			return errMsg{err: err, context: "using template"}
+		}
+		t.Task.Description = data.Description
+		t.Task.Type = data.Type
+		t.Task.Priority = data.Priority
+		t.Task.Column = data.Column
+		t.Task.Labels = data.Labels
+
+		vars := tasktemplate.BuiltinVars(data.Title, boardRecord.GetString("prefix"))
+		for k, v := range data.Vars {
+			vars[k] = v
+		}
+		rendered, err := t.Render(vars)
+		if err != nil {
+			return errMsg{err: err, context: "using template " + t.Name}
+		}
+
+		records, err := tasktemplate.Instantiate(app, boardRecord, rendered, tasktemplate.Options{
+			CreatedBy: "tui",
+			Epic:      data.EpicID,
+			DueDate:   data.DueDate,
+		})
+		if err != nil {
+			return errMsg{err: err, context: "creating tasks from template"}
+		}
+
+		return taskCreatedMsg{
+			task:      records[0],
+			displayID: board.FormatDisplayID(boardRecord.GetString("prefix"), records[0].GetInt("seq")),
+		}
+	}
+}
+
 // =============================================================================
 // Update Task Command
 // =============================================================================
@@ -391,7 +448,7 @@ func updateTask(app *pocketbase.PocketBase, taskID string, data TaskFormData) te
 			return errMsg{err: err, context: "finding task"}
 		}
 
-		// Track changes for history
+		// Track changes for the event log
 		changes := make(map[string]any)
 
 		// Update fields that changed
@@ -449,17 +506,9 @@ func updateTask(app *pocketbase.PocketBase, taskID string, data TaskFormData) te
 			record.Set("epic", "")
 		}
 
-		// Add history entry if there were changes
+		// Record the changes in the event log
 		if len(changes) > 0 {
-			history := getRecordHistory(record)
-			history = append(history, map[string]any{
-				"timestamp":    time.Now().UTC().Format(time.RFC3339),
-				"action":       "updated",
-				"actor":        "tui",
-				"actor_detail": "",
-				"changes":      changes,
-			})
-			record.Set("history", history)
+			taskevent.Add(record, "updated", "tui", "", changes, nil)
 		}
 
 		// Save using hybrid pattern
@@ -475,7 +524,7 @@ func updateTask(app *pocketbase.PocketBase, taskID string, data TaskFormData) te
 // Delete Task Command
 // =============================================================================
 
-// deleteTask deletes a task by ID
+// deleteTask moves a task to the trash by ID
 func deleteTask(app *pocketbase.PocketBase, taskID string) tea.Cmd {
 	return func() tea.Msg {
 		record, err := app.FindRecordById("tasks", taskID)
@@ -521,23 +570,16 @@ func moveTaskToColumn(app *pocketbase.PocketBase, taskID, targetColumn string) t
 		// Get position at end of target column
 		position := position.GetNext(app, targetColumn)
 
-		// Track change in history
-		history := getRecordHistory(record)
-		history = append(history, map[string]any{
-			"timestamp": time.Now().UTC().Format(time.RFC3339),
-			"action":    "moved",
-			"actor":     "tui",
-			"changes": map[string]any{
-				"column": map[string]string{
-					"from": fromColumn,
-					"to":   targetColumn,
-				},
+		// Record the change in the event log
+		taskevent.Add(record, "moved", "tui", "", map[string]any{
+			"column": map[string]any{
+				"from": fromColumn,
+				"to":   targetColumn,
 			},
-		})
+		}, nil)
 
 		record.Set("column", targetColumn)
 		record.Set("position", position)
-		record.Set("history", history)
 
 		if err := updateRecordHybrid(app, record); err != nil {
 			return errMsg{err: err, context: "moving task"}
@@ -586,6 +628,63 @@ func reorderTaskInColumn(app *pocketbase.PocketBase, taskID string, moveUp bool)
 	}
 }
 
+// =============================================================================
+// Undo Commands
+// =============================================================================
+
+// agentEnvVar names the agent running the TUI, as it does for the CLI.
+const agentEnvVar = "EGENSKRIVEN_AGENT"
+
+// undoLastChange undoes (or with redo, redoes) the user's most recent batch
+// of task changes.
+func undoLastChange(app *pocketbase.PocketBase, redo bool) tea.Cmd {
+	return func() tea.Msg {
+		by := undo.Actor{Name: undo.Current().Actor, Source: "tui"}
+		if by.Name == "" {
+			by.Name = config.CurrentUser()
+		}
+
+		// Undo and redo change tasks like any edit, so the board policy
+		// applies to every change they make
+		caller := policy.User(by.Name)
+		if name := os.Getenv(agentEnvVar); name != "" {
+			caller = policy.Agent(name)
+		}
+		by.Check = func(boardID, kind, fromColumn, toColumn string) error {
+			return policy.Check(app, caller, boardID, undo.PolicyAction(kind, fromColumn, toColumn)).Err()
+		}
+
+		var summary *undo.Summary
+		var err error
+		if redo {
+			summary, err = undo.Redo(app, by)
+		} else {
+			summary, err = undo.Undo(app, by)
+		}
+
+		msg := UndoneMsg{Redo: redo, Err: err}
+		if summary != nil {
+			msg.Label = summary.Label
+		}
+		return msg
+	}
+}
+
+// undoErrorText describes an undo failure, naming conflicting tasks.
+func undoErrorText(err error) string {
+	var conflictErr *undo.ConflictError
+	if !errors.As(err, &conflictErr) {
+		return err.Error()
+	}
+	titles := make([]string, 0, len(conflictErr.Conflicts))
+	for _, c := range conflictErr.Conflicts {
+		if !slices.Contains(titles, c.Title) {
+			titles = append(titles, c.Title)
+		}
+	}
+	return fmt.Sprintf("%s changed since: %s", conflictErr.Label, strings.Join(titles, ", "))
+}
+
 // =============================================================================
 // Status Message Commands
 // =============================================================================
@@ -640,6 +739,7 @@ func saveRecordHybrid(app *pocketbase.PocketBase, record *core.Record) error {
 		taskData := recordToAPIData(record)
 		if err := createTaskViaAPI(taskData); err == nil {
 			debugLog("created task via API")
+			taskevent.Clear(record)
 			return nil
 		} else {
 			debugLog("API create failed, falling back to direct DB: %v", err)
@@ -656,6 +756,7 @@ func updateRecordHybrid(app *pocketbase.PocketBase, record *core.Record) error {
 		taskData := recordToAPIData(record)
 		if err := updateTaskViaAPI(record.Id, taskData); err == nil {
 			debugLog("updated task via API: %s", record.Id)
+			taskevent.Clear(record)
 			return nil
 		} else {
 			debugLog("API update failed, falling back to direct DB: %v", err)
@@ -666,7 +767,8 @@ func updateRecordHybrid(app *pocketbase.PocketBase, record *core.Record) error {
 	return app.Save(record)
 }
 
-// deleteRecordHybrid attempts to delete via API first, falls back to direct DB
+// deleteRecordHybrid moves a task to the trash via API first, falls back to
+// direct DB
 func deleteRecordHybrid(app *pocketbase.PocketBase, record *core.Record) error {
 	if isServerRunning() {
 		if err := deleteTaskViaAPI(record.Id); err == nil {
@@ -678,7 +780,7 @@ func deleteRecordHybrid(app *pocketbase.PocketBase, record *core.Record) error {
 	} else {
 		debugLog("server not running, using direct DB delete")
 	}
-	return app.Delete(record)
+	return trash.Task(app, record, "tui", undo.Current().Actor)
 }
 
 // isServerRunning checks if the PocketBase server is accessible
@@ -698,21 +800,30 @@ func isServerRunning() bool {
 
 // APITaskData represents task data for API requests
 type APITaskData struct {
-	ID          string   `json:"id,omitempty"`
-	Title       string   `json:"title,omitempty"`
-	Description string   `json:"description,omitempty"`
-	Type        string   `json:"type,omitempty"`
-	Priority    string   `json:"priority,omitempty"`
-	Column      string   `json:"column,omitempty"`
-	Position    float64  `json:"position,omitempty"`
-	Labels      []string `json:"labels,omitempty"`
-	BlockedBy   []string `json:"blocked_by,omitempty"`
-	CreatedBy   string   `json:"created_by,omitempty"`
-	Epic        string   `json:"epic,omitempty"`
-	Board       string   `json:"board,omitempty"`
-	Seq         int      `json:"seq,omitempty"`
-	DueDate     string   `json:"due_date,omitempty"`
-	History     []any    `json:"history,omitempty"`
+	ID          string            `json:"id,omitempty"`
+	Title       string            `json:"title,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Priority    string            `json:"priority,omitempty"`
+	Column      string            `json:"column,omitempty"`
+	Position    float64           `json:"position,omitempty"`
+	Labels      []string          `json:"labels,omitempty"`
+	BlockedBy   []string          `json:"blocked_by,omitempty"`
+	CreatedBy   string            `json:"created_by,omitempty"`
+	Epic        string            `json:"epic,omitempty"`
+	Board       string            `json:"board,omitempty"`
+	Seq         int               `json:"seq,omitempty"`
+	DueDate     string            `json:"due_date,omitempty"`
+	Events      []taskevent.Event `json:"@events,omitempty"`
+}
+
+// setAuthHeader attaches the configured API token, if any, and the undo
+// batch to a request.
+func setAuthHeader(req *http.Request) {
+	if token := config.APIToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	undo.SetHeaders(req)
 }
 
 func recordToAPIData(record *core.Record) APITaskData {
@@ -729,18 +840,6 @@ func recordToAPIData(record *core.Record) APITaskData {
 		}
 	}
 
-	var history []any
-	if h := record.Get("history"); h != nil {
-		if hSlice, ok := h.([]map[string]any); ok {
-			for _, entry := range hSlice {
-				history = append(history, entry)
-			}
-		} else if hAny, ok := h.([]any); ok {
-			// Handle []any type (consistent with getRecordHistory)
-			history = hAny
-		}
-	}
-
 	return APITaskData{
 		ID:          record.Id,
 		Title:       record.GetString("title"),
@@ -755,7 +854,7 @@ func recordToAPIData(record *core.Record) APITaskData {
 		Board:       record.GetString("board"),
 		Seq:         record.GetInt("seq"),
 		DueDate:     record.GetString("due_date"),
-		History:     history,
+		Events:      taskevent.Pending(record),
 	}
 }
 
@@ -765,15 +864,22 @@ func createTaskViaAPI(data APITaskData) error {
 		return err
 	}
 
-	client := &http.Client{Timeout: APIRequestTimeout}
-	resp, err := client.Post(
+	req, err := http.NewRequest(
+		"POST",
 		DefaultServerURL+"/api/collections/tasks/records",
-		"application/json",
 		bytes.NewBuffer(jsonData),
 	)
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", "application/json")
+	setAuthHeader(req)
+
+	client := &http.Client{Timeout: APIRequestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
@@ -798,6 +904,7 @@ func updateTaskViaAPI(id string, data APITaskData) error {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	setAuthHeader(req)
 
 	client := &http.Client{Timeout: APIRequestTimeout}
 	resp, err := client.Do(req)
@@ -822,6 +929,7 @@ func deleteTaskViaAPI(id string) error {
 	if err != nil {
 		return err
 	}
+	setAuthHeader(req)
 
 	client := &http.Client{Timeout: APIRequestTimeout}
 	resp, err := client.Do(req)
@@ -837,27 +945,6 @@ func deleteTaskViaAPI(id string) error {
 	return nil
 }
 
-// =============================================================================
-// Helper Functions
-// =============================================================================
-
-// getRecordHistory extracts history from a record as a slice of maps
-func getRecordHistory(record *core.Record) []map[string]any {
-	var history []map[string]any
-	if h := record.Get("history"); h != nil {
-		if hSlice, ok := h.([]map[string]any); ok {
-			history = hSlice
-		} else if hAny, ok := h.([]any); ok {
-			for _, item := range hAny {
-				if m, ok := item.(map[string]any); ok {
-					history = append(history, m)
-				}
-			}
-		}
-	}
-	return history
-}
-
 // =============================================================================
 // Polling Fallback Commands
 // =============================================================================
@@ -877,7 +964,7 @@ func StartPolling(config PollConfig) tea.Cmd {
 }
 
 // PollForChanges checks for records updated since the last check.
-func PollForChanges(app *pocketbase.PocketBase, boardID string, lastCheck time.Time) tea.Cmd {
+func PollForChanges(app *pocketbase.PocketBase, boardID string, archived archive.Mode, lastCheck time.Time) tea.Cmd {
 	return func() tea.Msg {
 		// Format timestamp for PocketBase query
 		// PocketBase uses ISO 8601 format: 2006-01-02 15:04:05.000Z
@@ -890,6 +977,8 @@ func PollForChanges(app *pocketbase.PocketBase, boardID string, lastCheck time.T
 					"board": boardID,
 					"time":  timestamp,
 				}),
+			trash.Exclude(app, "tasks"),
+			archive.Filter(app, archived),
 		)
 		if err != nil {
 			return pollResultMsg{
@@ -992,6 +1081,7 @@ func CmdLoadLabels(app *pocketbase.PocketBase, boardID string) tea.Cmd {
 
 		records, err := app.FindAllRecords("tasks",
 			dbx.NewExp("board = {:board}", dbx.Params{"board": boardID}),
+			trash.Exclude(app, "tasks"),
 		)
 		if err != nil {
 			return LabelsLoadedMsg{Labels: []string{}}
@@ -1026,11 +1116,78 @@ func CmdLoadLabels(app *pocketbase.PocketBase, boardID string) tea.Cmd {
 	}
 }
 
+// CmdLoadAssignees loads all unique assignees from a board's tasks
+func CmdLoadAssignees(app *pocketbase.PocketBase, boardID string) tea.Cmd {
+	return func() tea.Msg {
+		records, err := app.FindAllRecords("tasks",
+			dbx.NewExp("board = {:board}", dbx.Params{"board": boardID}),
+			trash.Exclude(app, "tasks"),
+		)
+		if err != nil {
+			return AssigneesLoadedMsg{Assignees: []string{}}
+		}
+
+		seen := make(map[string]bool)
+		result := make([]string, 0)
+		for _, record := range records {
+			for _, a := range record.GetStringSlice("assignees") {
+				key := strings.ToLower(a)
+				if a != "" && !seen[key] {
+					seen[key] = true
+					result = append(result, a)
+				}
+			}
+		}
+		sort.Strings(result)
+
+		return AssigneesLoadedMsg{Assignees: result}
+	}
+}
+
+// CmdLoadSprints loads open sprints for a board, active first
+func CmdLoadSprints(app *pocketbase.PocketBase, boardID string) tea.Cmd {
+	return func() tea.Msg {
+		records, err := sprint.ForBoard(app, boardID)
+		if err != nil {
+			return SprintsLoadedMsg{Sprints: []SprintOption{}}
+		}
+
+		sprints := make([]SprintOption, 0, len(records))
+		for _, record := range records {
+			state := record.GetString("state")
+			if state == sprint.StateClosed {
+				continue
+			}
+			option := SprintOption{
+				ID:    record.Id,
+				Name:  record.GetString("name"),
+				State: state,
+			}
+			if state == sprint.StateActive {
+				sprints = append([]SprintOption{option}, sprints...)
+			} else {
+				sprints = append(sprints, option)
+			}
+		}
+
+		return SprintsLoadedMsg{Sprints: sprints}
+	}
+}
+
+// CmdLoadViews loads the saved views of a board, favorites first
+func CmdLoadViews(app *pocketbase.PocketBase, boardID string) tea.Cmd {
+	return func() tea.Msg {
+		views, err := view.List(app, boardID)
+		return ViewsLoadedMsg{Views: views, Err: err}
+	}
+}
+
 // CmdLoadEpics loads all epics for a board
 func CmdLoadEpics(app *pocketbase.PocketBase, boardID string) tea.Cmd {
 	return func() tea.Msg {
 		records, err := app.FindAllRecords("epics",
 			dbx.NewExp("board = {:board}", dbx.Params{"board": boardID}),
+			trash.Exclude(app, "epics"),
 		)
 		if err != nil {
 			return EpicsLoadedMsg{Epics: []EpicOption{}}
@@ -1076,3 +1233,54 @@ func CmdLoadSubtasks(app *pocketbase.PocketBase, parentID, boardPrefix string) t
 		}
 	}
 }
+
+// CmdLoadEstimate loads a task's estimate rolled up from its sub-tasks
+func CmdLoadEstimate(app *pocketbase.PocketBase, taskID string) tea.Cmd {
+	return func() tea.Msg {
+		record, err := app.FindRecordById("tasks", taskID)
+		if err != nil {
+			return EstimateLoadedMsg{TaskID: taskID, Err: err}
+		}
+		return EstimateLoadedMsg{
+			TaskID: taskID,
+			Totals: estimate.ForTask(app, record),
+			Unit:   estimate.BoardUnit(app, record.GetString("board")),
+		}
+	}
+}
+
+// CmdLoadAttachments loads the files attached to a task and its comments
+func CmdLoadAttachments(app *pocketbase.PocketBase, taskID string) tea.Cmd {
+	return func() tea.Msg {
+		record, err := app.FindRecordById("tasks", taskID)
+		if err != nil {
+			return AttachmentsLoadedMsg{TaskID: taskID, Err: err}
+		}
+		infos, err := attachment.ForTask(app, record)
+		return AttachmentsLoadedMsg{TaskID: taskID, Attachments: infos, Err: err}
+	}
+}
+
+// CmdLoadLinks loads the typed links of a task
+func CmdLoadLinks(app *pocketbase.PocketBase, taskID string) tea.Cmd {
+	return func() tea.Msg {
+		record, err := app.FindRecordById("tasks", taskID)
+		if err != nil {
+			return LinksLoadedMsg{TaskID: taskID, Err: err}
+		}
+		links, err := tasklink.ForTask(app, record)
+		return LinksLoadedMsg{TaskID: taskID, Links: links, Err: err}
+	}
+}
+
+// CmdLoadTimeLogged loads the total logged time for a task
+func CmdLoadTimeLogged(app *pocketbase.PocketBase, taskID string) tea.Cmd {
+	return func() tea.Msg {
+		summary, err := timetrack.TaskSummary(app, taskID)
+		return TimeLoggedMsg{
+			TaskID:  taskID,
+			Summary: summary,
+			Err:     err,
+		}
+	}
+}
@@ -10,7 +10,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ramtinJ95/EgenSkriven/internal/board"
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasktemplate"
 	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
 )
 
 // createTasksCollection creates the tasks collection with all required fields
@@ -30,7 +35,6 @@ func createTasksCollection(t *testing.T, app *pocketbase.PocketBase) *core.Colle
 		&core.NumberField{Name: "seq"},
 		&core.TextField{Name: "due_date"},
 		&core.TextField{Name: "epic"},
-		&core.JSONField{Name: "history"},
 	)
 }
 
@@ -172,15 +176,15 @@ func TestMoveTaskToColumnCommand(t *testing.T) {
 		&core.TextField{Name: "title", Required: true},
 		&core.TextField{Name: "column"},
 		&core.NumberField{Name: "position"},
-		&core.JSONField{Name: "history"},
 	)
+	testutil.CreateTaskEventsCollection(t, app)
+	taskevent.Bind(app)
 
 	// Create a task in backlog
 	record := core.NewRecord(collection)
 	record.Set("title", "Task to Move")
 	record.Set("column", "backlog")
 	record.Set("position", 1000.0)
-	record.Set("history", []map[string]any{})
 	require.NoError(t, app.Save(record))
 
 	// Move to todo
@@ -198,6 +202,16 @@ func TestMoveTaskToColumnCommand(t *testing.T) {
 	updated, err := app.FindRecordById("tasks", record.Id)
 	require.NoError(t, err)
 	assert.Equal(t, "todo", updated.GetString("column"))
+
+	// Verify the move is logged
+	events, err := taskevent.ForTask(app, record.Id)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "moved", events[1].Action)
+	assert.Equal(t, "tui", events[1].Actor)
+	assert.Equal(t, map[string]any{
+		"column": map[string]any{"from": "backlog", "to": "todo"},
+	}, events[1].Changes)
 }
 
 // TestReorderTaskInColumnCommand verifies task reordering
@@ -243,7 +257,6 @@ func TestMoveTaskToSameColumn(t *testing.T) {
 		&core.TextField{Name: "title", Required: true},
 		&core.TextField{Name: "column"},
 		&core.NumberField{Name: "position"},
-		&core.JSONField{Name: "history"},
 	)
 
 	// Create a task in backlog
@@ -251,7 +264,6 @@ func TestMoveTaskToSameColumn(t *testing.T) {
 	record.Set("title", "Task in Backlog")
 	record.Set("column", "backlog")
 	record.Set("position", 1000.0)
-	record.Set("history", []map[string]any{})
 	require.NoError(t, app.Save(record))
 
 	originalPosition := record.GetFloat("position")
@@ -332,6 +344,46 @@ func TestTaskFormLabelsEmpty(t *testing.T) {
 	assert.Empty(t, submit.data.Labels)
 }
 
+// TestTaskFormTemplate verifies choosing a template fills the form and
+// submits its variables
+func TestTaskFormTemplate(t *testing.T) {
+	form := NewTaskForm(FormModeAdd, 80, 40)
+	assert.False(t, form.fieldVisible(FieldTemplate), "no templates, no template field")
+
+	form.SetTemplates([]tasktemplate.Template{{
+		Name: "bug",
+		Vars: map[string]string{"version": "latest"},
+		Task: tasktemplate.Spec{Title: "Bug: {{title}}", Description: "Seen in {{version}}", Type: "bug", Priority: "high", Labels: []string{"bug"}},
+	}})
+
+	// Tab from the title moves to the template field, skipping variables
+	form.nextField()
+	assert.Equal(t, FieldTemplate, FormField(form.focusIndex))
+	assert.False(t, form.fieldVisible(FieldVars))
+
+	form.selectNext()
+	assert.Equal(t, "bug", form.types[form.typeSelect])
+	assert.Equal(t, "high", form.priorities[form.prioritySelect])
+	assert.Equal(t, "bug", form.labelsInput.Value())
+	assert.Contains(t, form.varsInput.Placeholder, "version=latest")
+	assert.True(t, form.fieldVisible(FieldVars))
+
+	form.titleInput.SetValue("Crash on save")
+	form.varsInput.SetValue("version=1.2, os = linux")
+	msg := form.submit()()
+
+	submit, ok := msg.(submitTaskFormMsg)
+	require.True(t, ok)
+	assert.Equal(t, "bug", submit.data.Template)
+	assert.Equal(t, map[string]string{"version": "1.2", "os": "linux"}, submit.data.Vars)
+
+	// Back to no template
+	form.selectPrev()
+	submit, ok = form.submit()().(submitTaskFormMsg)
+	require.True(t, ok)
+	assert.Empty(t, submit.data.Template)
+}
+
 // TestConfirmDialogDefault verifies confirm dialog defaults to No
 func TestConfirmDialogDefault(t *testing.T) {
 	dialog := NewDeleteConfirmDialog("Test Task")
@@ -429,3 +481,63 @@ func TestGetColumnOrder(t *testing.T) {
 		})
 	}
 }
+
+// TestUndoLastChange_ChecksPolicy verifies that undo in the TUI applies the
+// board policy to the changes it makes
+func TestUndoLastChange_ChecksPolicy(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	tasks := createTasksCollection(t, app)
+	boards := testutil.CreateTestCollection(t, app, "boards",
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "agent_mode"},
+	)
+	testutil.CreateTestCollection(t, app, undo.CollectionName,
+		&core.TextField{Name: "batch"},
+		&core.TextField{Name: "label"},
+		&core.TextField{Name: "actor"},
+		&core.TextField{Name: "kind"},
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "board"},
+		&core.JSONField{Name: "before"},
+		&core.JSONField{Name: "after"},
+		&core.TextField{Name: "status"},
+		&core.NumberField{Name: "seq"},
+		&core.DateField{Name: "undone_at"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+
+	b := core.NewRecord(boards)
+	b.Set("name", "Work")
+	b.Set("agent_mode", policy.ModeSupervised)
+	require.NoError(t, app.Save(b))
+
+	task := core.NewRecord(tasks)
+	task.Set("title", "Fix bug")
+	task.Set("column", "in_progress")
+	task.Set("board", b.Id)
+	require.NoError(t, app.Save(task))
+
+	// The task was moved from todo by the TUI's user
+	before := undo.Snapshot(task)
+	before["column"] = "todo"
+	require.NoError(t, undo.Record(app, undo.NewBatch("move", config.CurrentUser()),
+		undo.KindUpdate, task.Id, b.Id, before, undo.Snapshot(task)))
+
+	// A supervised agent may not move it back
+	t.Setenv(agentEnvVar, "claude")
+	msg, ok := undoLastChange(app, false)().(UndoneMsg)
+	require.True(t, ok)
+	var v *policy.Violation
+	require.ErrorAs(t, msg.Err, &v)
+	task, err := app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "in_progress", task.GetString("column"))
+
+	t.Setenv(agentEnvVar, "")
+	msg = undoLastChange(app, false)().(UndoneMsg)
+	require.NoError(t, msg.Err)
+	assert.Equal(t, "move", msg.Label)
+	task, err = app.FindRecordById("tasks", task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "todo", task.GetString("column"))
+}
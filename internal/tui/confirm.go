@@ -69,7 +69,7 @@ func NewConfirmDialog(title, message string) *ConfirmDialog {
 func NewDeleteConfirmDialog(taskTitle string) *ConfirmDialog {
 	return &ConfirmDialog{
 		title:    "Delete Task?",
-		message:  "Delete \"" + Truncate(taskTitle, 30) + "\"?\nThis action cannot be undone.",
+		message:  "Delete \"" + Truncate(taskTitle, 30) + "\"?\nIt can be restored from the trash.",
 		yesLabel: "Delete",
 		noLabel:  "Cancel",
 		focused:  false, // Default to Cancel for safety
@@ -82,9 +82,9 @@ func NewDeleteConfirmDialog(taskTitle string) *ConfirmDialog {
 func NewBulkDeleteConfirmDialog(count int) *ConfirmDialog {
 	var message string
 	if count == 1 {
-		message = "Delete 1 task?\nThis action cannot be undone."
+		message = "Delete 1 task?\nIt can be restored from the trash."
 	} else {
-		message = fmt.Sprintf("Delete %d tasks?\nThis action cannot be undone.", count)
+		message = fmt.Sprintf("Delete %d tasks?\nIt can be restored from the trash.", count)
 	}
 
 	return &ConfirmDialog{
@@ -3,14 +3,27 @@ package tui
 import (
 	"encoding/json"
 	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskquery"
+	"github.com/ramtinJ95/EgenSkriven/internal/view"
 )
 
 // Filter represents a single filter condition
 type Filter struct {
-	Field    string // "priority", "type", "label", "epic", "blocked"
+	Field    string // "priority", "type", "label", "epic", "sprint", "assignee", "blocked"
 	Operator string // "is", "is_not", "includes"
 	Value    string // The filter value
 	Display  string // Human-readable display (e.g., "Priority: High")
+
+	// View holds the saved view for "view" filters. Its filters use the
+	// web UI's definitions and are matched with view.Matches.
+	View *view.View `json:",omitempty"`
+
+	// Query holds the compiled query of "query" filters, whose Value is
+	// the query text.
+	Query *taskquery.Filter `json:"-"`
 }
 
 // String returns a display-friendly representation of the filter
@@ -66,6 +79,22 @@ func (f *FilterState) RemoveFilterByField(field string) {
 	f.filters = newFilters
 }
 
+// Bind compiles the query and view filters restored from a session,
+// which hold only their text. Filters that no longer compile match nothing.
+func (f *FilterState) Bind(app core.App, boardID string) {
+	for i := range f.filters {
+		switch f.filters[i].Field {
+		case "query":
+			f.filters[i].Query, _ = taskquery.ParseAndCompile(app, f.filters[i].Value,
+				taskquery.Options{BoardID: boardID})
+		case "view":
+			if v := f.filters[i].View; v != nil {
+				_ = v.Bind(app)
+			}
+		}
+	}
+}
+
 // Clear removes all filters and search query
 func (f *FilterState) Clear() {
 	f.filters = make([]Filter, 0)
@@ -144,8 +173,16 @@ func (f *FilterState) matchesFilter(task TaskItem, filter Filter) bool {
 		return f.matchLabel(task, filter)
 	case "epic":
 		return f.matchEpic(task, filter)
+	case "sprint":
+		return f.matchSprint(task, filter)
+	case "assignee":
+		return f.matchAssignee(task, filter)
 	case "blocked":
 		return f.matchBlocked(task, filter)
+	case "view":
+		return filter.View == nil || filter.View.Matches(task.queryTask())
+	case "query":
+		return filter.Query != nil && filter.Query.Match(task.queryTask())
 	default:
 		return true // Unknown filter field - pass through
 	}
@@ -199,6 +236,19 @@ func (f *FilterState) matchEpic(task TaskItem, filter Filter) bool {
 	}
 }
 
+func (f *FilterState) matchSprint(task TaskItem, filter Filter) bool {
+	// "none" matches tasks without a sprint
+	matched := task.SprintID == filter.Value
+	if filter.Value == "none" {
+		matched = task.SprintID == ""
+	}
+
+	if filter.Operator == "is_not" {
+		return !matched
+	}
+	return matched
+}
+
 func (f *FilterState) matchBlocked(task TaskItem, filter Filter) bool {
 	// "blocked" filter: value can be "yes", "no", or "true", "false"
 	wantBlocked := strings.EqualFold(filter.Value, "yes") ||
@@ -261,3 +311,22 @@ func (f *FilterState) MarshalJSON() ([]byte, error) {
 func (f *FilterState) UnmarshalJSON(data []byte) error {
 	return f.FromJSON(data)
 }
+
+func (f *FilterState) matchAssignee(task TaskItem, filter Filter) bool {
+	// "none" matches tasks without assignees
+	matched := len(task.Assignees) == 0
+	if filter.Value != "none" {
+		matched = false
+		for _, a := range task.Assignees {
+			if strings.EqualFold(a, filter.Value) {
+				matched = true
+				break
+			}
+		}
+	}
+
+	if filter.Operator == "is_not" {
+		return !matched
+	}
+	return matched
+}
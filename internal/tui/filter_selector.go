@@ -2,10 +2,13 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/view"
 )
 
 // FilterSelectorType identifies which filter is being selected
@@ -18,6 +21,9 @@ const (
 	FilterSelectorLabel
 	FilterSelectorEpic
 	FilterSelectorBlocked
+	FilterSelectorSprint
+	FilterSelectorAssignee
+	FilterSelectorView
 )
 
 // FilterOption represents a selectable filter value
@@ -39,6 +45,7 @@ type FilterSelector struct {
 	width        int
 	height       int
 	active       bool
+	views        []view.View // saved views offered by ShowViews
 }
 
 // NewFilterSelector creates a filter selector
@@ -92,6 +99,51 @@ func (s *FilterSelector) ShowEpic(epics []EpicOption) tea.Cmd {
 	return s.show(FilterSelectorEpic, "Filter by Epic", options)
 }
 
+// ShowSprint opens selector for sprint filter with open sprints
+func (s *FilterSelector) ShowSprint(sprints []SprintOption) tea.Cmd {
+	options := make([]list.Item, 0, len(sprints)+1)
+	for _, sp := range sprints {
+		display := sp.Name
+		if sp.State == "active" {
+			display += " (current)"
+		}
+		options = append(options, FilterOption{Value: sp.ID, Display: display, Color: "39"})
+	}
+	options = append(options, FilterOption{Value: "none", Display: "No sprint", Color: "240"})
+	return s.show(FilterSelectorSprint, "Filter by Sprint", options)
+}
+
+// ShowAssignee opens selector for assignee filter. The current user (me)
+// is listed first when known.
+func (s *FilterSelector) ShowAssignee(assignees []string, me string) tea.Cmd {
+	options := make([]list.Item, 0, len(assignees)+2)
+	if me != "" {
+		options = append(options, FilterOption{Value: me, Display: "Me (" + me + ")", Color: "75"})
+	}
+	for _, a := range assignees {
+		if strings.EqualFold(a, me) {
+			continue
+		}
+		options = append(options, FilterOption{Value: a, Display: a, Color: "75"})
+	}
+	options = append(options, FilterOption{Value: "none", Display: "Unassigned", Color: "240"})
+	return s.show(FilterSelectorAssignee, "Filter by Assignee", options)
+}
+
+// ShowViews opens selector for saved views
+func (s *FilterSelector) ShowViews(views []view.View) tea.Cmd {
+	s.views = views
+	options := make([]list.Item, len(views))
+	for i, v := range views {
+		display := v.Name
+		if v.IsFavorite {
+			display = "* " + display
+		}
+		options[i] = FilterOption{Value: v.ID, Display: display, Color: "141"}
+	}
+	return s.show(FilterSelectorView, "Apply Saved View", options)
+}
+
 // ShowBlocked opens selector for blocked status filter
 func (s *FilterSelector) ShowBlocked() tea.Cmd {
 	options := []list.Item{
@@ -164,11 +216,15 @@ func (s FilterSelector) Update(msg tea.Msg) (FilterSelector, tea.Cmd) {
 			// Get selected option
 			if selected, ok := s.list.SelectedItem().(FilterOption); ok {
 				currentType := s.selectorType
+				filter := createFilterFromOption(currentType, selected)
+				if currentType == FilterSelectorView {
+					filter = s.viewFilter(selected.Value)
+				}
 				s.Hide()
 				return s, func() tea.Msg {
 					return FilterSelectedMsg{
 						Type:   currentType,
-						Filter: createFilterFromOption(currentType, selected),
+						Filter: filter,
 					}
 				}
 			}
@@ -206,6 +262,10 @@ func createFilterFromOption(sType FilterSelectorType, option FilterOption) Filte
 		field = "epic"
 	case FilterSelectorBlocked:
 		field = "blocked"
+	case FilterSelectorSprint:
+		field = "sprint"
+	case FilterSelectorAssignee:
+		field = "assignee"
 	}
 
 	return Filter{
@@ -216,6 +276,23 @@ func createFilterFromOption(sType FilterSelectorType, option FilterOption) Filte
 	}
 }
 
+// viewFilter returns the filter applying the saved view with the given ID
+func (s FilterSelector) viewFilter(id string) Filter {
+	for i := range s.views {
+		if s.views[i].ID == id {
+			v := s.views[i]
+			return Filter{
+				Field:    "view",
+				Operator: "is",
+				Value:    v.ID,
+				Display:  "View: " + v.Name,
+				View:     &v,
+			}
+		}
+	}
+	return Filter{Field: "view", Operator: "is", Value: id}
+}
+
 // View renders the selector
 func (s FilterSelector) View() string {
 	if !s.active {
@@ -267,6 +344,13 @@ type EpicOption struct {
 	Color string
 }
 
+// SprintOption for sprint filter selection
+type SprintOption struct {
+	ID    string
+	Name  string
+	State string
+}
+
 // capitalizeFirst capitalizes the first letter of a string
 func capitalizeFirst(s string) string {
 	if len(s) == 0 {
@@ -5,6 +5,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskquery"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+	"github.com/ramtinJ95/EgenSkriven/internal/view"
 )
 
 func TestFilterState_SearchQuery(t *testing.T) {
@@ -219,6 +223,51 @@ func TestFilterState_Apply_EpicFilter_ByTitle(t *testing.T) {
 	assert.Len(t, result, 1)
 }
 
+func TestFilterState_Apply_SprintFilter(t *testing.T) {
+	tasks := []TaskItem{
+		{ID: "1", SprintID: "sprint-1"},
+		{ID: "2", SprintID: "sprint-2"},
+		{ID: "3", SprintID: ""},
+	}
+
+	fs := NewFilterState()
+	fs.AddFilter(Filter{Field: "sprint", Operator: "is", Value: "sprint-1"})
+	result := fs.Apply(tasks)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "1", result[0].ID)
+
+	// "none" matches tasks without a sprint
+	fs.AddFilter(Filter{Field: "sprint", Operator: "is", Value: "none"})
+	result = fs.Apply(tasks)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "3", result[0].ID)
+}
+
+func TestFilterState_Apply_AssigneeFilter(t *testing.T) {
+	tasks := []TaskItem{
+		{ID: "1", Assignees: []string{"alice"}},
+		{ID: "2", Assignees: []string{"Bob", "alice"}},
+		{ID: "3"},
+	}
+
+	fs := NewFilterState()
+	fs.AddFilter(Filter{Field: "assignee", Operator: "is", Value: "bob"})
+	result := fs.Apply(tasks)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "2", result[0].ID)
+
+	// "none" matches unassigned tasks
+	fs.AddFilter(Filter{Field: "assignee", Operator: "is", Value: "none"})
+	result = fs.Apply(tasks)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "3", result[0].ID)
+
+	fs.AddFilter(Filter{Field: "assignee", Operator: "is_not", Value: "alice"})
+	result = fs.Apply(tasks)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "3", result[0].ID)
+}
+
 func TestFilterState_Apply_BlockedFilter(t *testing.T) {
 	fs := NewFilterState()
 	fs.AddFilter(Filter{Field: "blocked", Operator: "is", Value: "yes"})
@@ -357,3 +406,65 @@ func TestFilterState_RemoveFilterByField(t *testing.T) {
 		assert.NotEqual(t, "label", f.Field)
 	}
 }
+
+func TestFilterState_Apply_ViewFilter(t *testing.T) {
+	fs := NewFilterState()
+	tasks := []TaskItem{
+		{ID: "1", Type: "bug", Priority: "urgent", Assignees: []string{"alice"}},
+		{ID: "2", Type: "bug", Priority: "low", Assignees: []string{"alice"}},
+		{ID: "3", Type: "feature", Priority: "urgent", BlockedBy: []string{"1"}, IsBlocked: true},
+	}
+
+	saved := &view.View{
+		Name: "Urgent bugs",
+		Filters: []view.Filter{
+			{Field: "type", Operator: view.OpIs, Value: "bug"},
+			{Field: "priority", Operator: view.OpIsAnyOf, Value: []any{"urgent", "high"}},
+		},
+		MatchMode: view.MatchAll,
+	}
+	fs.AddFilter(Filter{Field: "view", Operator: "is", Value: "v1", Display: "View: Urgent bugs", View: saved})
+
+	result := fs.Apply(tasks)
+	require.Len(t, result, 1)
+	assert.Equal(t, "1", result[0].ID)
+
+	// The view survives session persistence
+	data, err := fs.ToJSON()
+	require.NoError(t, err)
+	restored := NewFilterState()
+	require.NoError(t, restored.FromJSON(data))
+	assert.Len(t, restored.Apply(tasks), 1)
+
+	// Match any
+	saved.MatchMode = view.MatchAny
+	assert.Len(t, fs.Apply(tasks), 3)
+}
+
+func TestFilterState_Apply_QueryFilter(t *testing.T) {
+	app := testutil.NewTestApp(t)
+	tasks := []TaskItem{
+		{ID: "1", Type: "bug", Priority: "urgent", Labels: []string{"backend"}},
+		{ID: "2", Type: "bug", Priority: "low", Labels: []string{"wontfix"}},
+		{ID: "3", Type: "feature", Priority: "high", BlockedBy: []string{"1"}, IsBlocked: true},
+	}
+
+	text := "priority:high,urgent -label:wontfix is:unblocked"
+	compiled, err := taskquery.ParseAndCompile(app, text, taskquery.Options{})
+	require.NoError(t, err)
+
+	fs := NewFilterState()
+	fs.AddFilter(Filter{Field: "query", Operator: "matches", Value: text, Query: compiled})
+	result := fs.Apply(tasks)
+	require.Len(t, result, 1)
+	assert.Equal(t, "1", result[0].ID)
+
+	// Restored sessions hold only the query text until bound
+	data, err := fs.ToJSON()
+	require.NoError(t, err)
+	restored := NewFilterState()
+	require.NoError(t, restored.FromJSON(data))
+	assert.Empty(t, restored.Apply(tasks))
+	restored.Bind(app, "")
+	assert.Len(t, restored.Apply(tasks), 1)
+}
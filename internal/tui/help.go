@@ -94,12 +94,15 @@ func GetHelpSections() []HelpSection {
 		{
 			Title: "Filtering",
 			Bindings: []HelpBinding{
-				{Key: "/", Description: "Search tasks"},
+				{Key: "/", Description: "Search tasks or filter with a query (is:blocked p:high)"},
 				{Key: "fp", Description: "Filter by priority"},
 				{Key: "ft", Description: "Filter by type"},
 				{Key: "fl", Description: "Filter by label"},
 				{Key: "fe", Description: "Filter by epic"},
+				{Key: "fs", Description: "Filter by sprint"},
+				{Key: "fa", Description: "Filter by assignee"},
 				{Key: "fb", Description: "Filter by blocked"},
+				{Key: "fv", Description: "Apply a saved view"},
 				{Key: "fc", Description: "Clear filters"},
 			},
 		},
@@ -108,6 +111,9 @@ func GetHelpSections() []HelpSection {
 			Bindings: []HelpBinding{
 				{Key: "?", Description: "Toggle help"},
 				{Key: "b", Description: "Switch board"},
+				{Key: "p", Description: "Review proposals"},
+				{Key: "u", Description: "Undo last change"},
+				{Key: "Ctrl+R", Description: "Redo"},
 				{Key: "r", Description: "Refresh"},
 				{Key: "q", Description: "Quit"},
 				{Key: "Esc", Description: "Cancel/close"},
@@ -20,12 +20,15 @@ type keyMap struct {
 	Board key.Binding
 
 	// Filtering - search and filter operations
-	// Note: fp, ft, fl, fe, fc are two-key sequences handled by pendingFilterKey
+	// Note: fp, ft, fl, fe, fs, fa, fv, fc are two-key sequences handled by pendingFilterKey
 	Search         key.Binding
 	FilterPriority key.Binding
 	FilterType     key.Binding
 	FilterLabel    key.Binding
 	FilterEpic     key.Binding
+	FilterSprint   key.Binding
+	FilterAssignee key.Binding
+	FilterView     key.Binding
 	ClearFilters   key.Binding
 
 	// Global - application-level controls
@@ -93,6 +96,21 @@ func defaultKeyMap() keyMap {
 			key.WithKeys("f"),
 			key.WithHelp("fe", "filter epic"),
 		),
+		// FilterSprint opens sprint filter (two-key: f then s)
+		FilterSprint: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("fs", "filter sprint"),
+		),
+		// FilterAssignee opens assignee filter (two-key: f then a)
+		FilterAssignee: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("fa", "filter assignee"),
+		),
+		// FilterView applies a saved view (two-key: f then v)
+		FilterView: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("fv", "saved view"),
+		),
 		// ClearFilters clears all active filters (two-key: f then c)
 		ClearFilters: key.NewBinding(
 			key.WithKeys("f"),
@@ -142,6 +160,9 @@ func (k keyMap) FilterHelp() []key.Binding {
 		k.FilterType,
 		k.FilterLabel,
 		k.FilterEpic,
+		k.FilterSprint,
+		k.FilterAssignee,
+		k.FilterView,
 		k.ClearFilters,
 	}
 }
@@ -4,6 +4,12 @@ import (
 	"time"
 
 	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
+	"github.com/ramtinJ95/EgenSkriven/internal/estimate"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasklink"
+	"github.com/ramtinJ95/EgenSkriven/internal/timetrack"
+	"github.com/ramtinJ95/EgenSkriven/internal/view"
 )
 
 // =============================================================================
@@ -99,6 +105,38 @@ type openTaskDetailMsg struct {
 // closeTaskDetailMsg requests closing the task detail panel
 type closeTaskDetailMsg struct{}
 
+// TimeLoggedMsg contains the logged time for the task shown in the detail panel
+type TimeLoggedMsg struct {
+	TaskID  string
+	Summary timetrack.Summary
+	Err     error
+}
+
+// EstimateLoadedMsg contains the rolled-up estimate for the task shown in
+// the detail panel
+type EstimateLoadedMsg struct {
+	TaskID string
+	Totals estimate.Totals
+	Unit   string
+	Err    error
+}
+
+// AttachmentsLoadedMsg contains the files attached to the task shown in the
+// detail panel and its comments
+type AttachmentsLoadedMsg struct {
+	TaskID      string
+	Attachments []attachment.Info
+	Err         error
+}
+
+// LinksLoadedMsg contains the typed links of the task shown in the detail
+// panel
+type LinksLoadedMsg struct {
+	TaskID string
+	Links  []tasklink.Link
+	Err    error
+}
+
 // openTaskFormMsg requests opening the task form for add/edit
 type openTaskFormMsg struct {
 	mode   FormMode
@@ -164,6 +202,8 @@ type TaskFormData struct {
 	Labels      []string
 	DueDate     string
 	EpicID      string
+	Template    string            // Template to create the task from (add only)
+	Vars        map[string]string // Template variables
 }
 
 // submitTaskFormMsg is sent when the task form is submitted
@@ -347,6 +387,22 @@ type EpicsLoadedMsg struct {
 	Epics []EpicOption
 }
 
+// AssigneesLoadedMsg contains the assignees used on a board
+type AssigneesLoadedMsg struct {
+	Assignees []string
+}
+
+// ViewsLoadedMsg contains the saved views of a board
+type ViewsLoadedMsg struct {
+	Views []view.View
+	Err   error
+}
+
+// SprintsLoadedMsg contains open (planned or active) sprints
+type SprintsLoadedMsg struct {
+	Sprints []SprintOption
+}
+
 // =============================================================================
 // Subtask Messages
 // =============================================================================
@@ -367,3 +423,27 @@ type SubtasksExpandedMsg struct {
 	Subtasks []TaskItem
 	Err      error
 }
+
+// =============================================================================
+// Proposal Messages
+// =============================================================================
+
+// ProposalsLoadedMsg contains the pending proposals for the current board
+type ProposalsLoadedMsg struct {
+	Proposals []*core.Record
+	Err       error
+}
+
+// ProposalReviewedMsg reports the outcome of approving or rejecting a proposal
+type ProposalReviewedMsg struct {
+	Summary  string
+	Approved bool
+	Err      error
+}
+
+// UndoneMsg reports the outcome of an undo or redo
+type UndoneMsg struct {
+	Label string
+	Redo  bool
+	Err   error
+}
@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
+	"github.com/ramtinJ95/EgenSkriven/internal/proposal"
+)
+
+// ProposalPanel is the modal listing pending agent proposals for review
+type ProposalPanel struct {
+	proposals []*core.Record
+	cursor    int
+	loading   bool
+	width     int
+	height    int
+}
+
+// NewProposalPanel creates an empty proposal panel in the loading state
+func NewProposalPanel(width, height int) *ProposalPanel {
+	return &ProposalPanel{
+		loading: true,
+		width:   width,
+		height:  height,
+	}
+}
+
+// SetProposals replaces the listed proposals, keeping the cursor in range
+func (p *ProposalPanel) SetProposals(records []*core.Record) {
+	p.proposals = records
+	p.loading = false
+	if p.cursor >= len(records) {
+		p.cursor = max(0, len(records)-1)
+	}
+}
+
+// SetSize updates the dimensions of the panel
+func (p *ProposalPanel) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// Selected returns the highlighted proposal, or nil if the list is empty
+func (p *ProposalPanel) Selected() *core.Record {
+	if p.cursor < 0 || p.cursor >= len(p.proposals) {
+		return nil
+	}
+	return p.proposals[p.cursor]
+}
+
+// MoveUp moves the cursor to the previous proposal
+func (p *ProposalPanel) MoveUp() {
+	if p.cursor > 0 {
+		p.cursor--
+	}
+}
+
+// MoveDown moves the cursor to the next proposal
+func (p *ProposalPanel) MoveDown() {
+	if p.cursor < len(p.proposals)-1 {
+		p.cursor++
+	}
+}
+
+// View renders the proposal panel
+func (p *ProposalPanel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		MarginBottom(1)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("62")).Bold(true)
+
+	innerWidth := max(10, p.width-6)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Proposals (%d pending)", len(p.proposals))))
+	b.WriteString("\n")
+
+	switch {
+	case p.loading:
+		b.WriteString(dimStyle.Render("Loading..."))
+	case len(p.proposals) == 0:
+		b.WriteString(dimStyle.Render("No pending proposals."))
+	default:
+		for i, r := range p.proposals {
+			summary := Truncate(r.GetString("summary"), innerWidth-2)
+			meta := fmt.Sprintf("  by %s, %s", r.GetString("proposed_by"),
+				formatTimeAgo(r.GetDateTime("created").Time()))
+
+			if i == p.cursor {
+				b.WriteString(selectedStyle.Render("> " + summary))
+				b.WriteString("\n")
+				b.WriteString(dimStyle.Render(meta))
+				b.WriteString("\n")
+				if rationale := r.GetString("rationale"); rationale != "" {
+					wrapped := lipgloss.NewStyle().Width(innerWidth - 2).Render(rationale)
+					for _, line := range strings.Split(wrapped, "\n") {
+						b.WriteString("  " + line + "\n")
+					}
+				}
+			} else {
+				b.WriteString("  " + summary + "\n")
+				b.WriteString(dimStyle.Render(meta))
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("a: approve  x: reject  esc: close"))
+
+	modalStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("205")).
+		Padding(1, 2).
+		Width(p.width)
+
+	return modalStyle.Render(b.String())
+}
+
+// formatTimeAgo formats a time relative to now (e.g., "5m ago")
+func formatTimeAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// CmdLoadProposals loads the pending proposals for a board
+func CmdLoadProposals(app *pocketbase.PocketBase, boardID string) tea.Cmd {
+	return func() tea.Msg {
+		records, err := proposal.List(app, boardID, proposal.StatusPending)
+		return ProposalsLoadedMsg{Proposals: records, Err: err}
+	}
+}
+
+// CmdReviewProposal approves or rejects a proposal as the current user
+func CmdReviewProposal(app *pocketbase.PocketBase, record *core.Record, approve bool) tea.Cmd {
+	return func() tea.Msg {
+		reviewer := config.CurrentUser()
+		var err error
+		if approve {
+			_, err = proposal.Approve(app, record, reviewer, "")
+		} else {
+			err = proposal.Reject(app, record, reviewer, "")
+		}
+		return ProposalReviewedMsg{
+			Summary:  record.GetString("summary"),
+			Approved: approve,
+			Err:      err,
+		}
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/config"
 )
 
 const (
@@ -34,6 +36,7 @@ const (
 type RealtimeClient struct {
 	serverURL string
 	clientID  string
+	token     string // API token for servers with auth enabled
 
 	// Connection management
 	ctx       context.Context
@@ -52,9 +55,11 @@ type RealtimeClient struct {
 }
 
 // NewRealtimeClient creates a new realtime client for the given server.
+// The API token is read from $EGENSKRIVEN_TOKEN or the global config.
 func NewRealtimeClient(serverURL string) *RealtimeClient {
 	return &RealtimeClient{
 		serverURL: strings.TrimSuffix(serverURL, "/"),
+		token:     config.APIToken(),
 		events:    make(chan RealtimeEvent, 100),
 		httpClient: &http.Client{
 			Timeout: 0, // No timeout for SSE (long-lived connection)
@@ -198,6 +203,11 @@ func (c *RealtimeClient) sendSubscription() error {
 		return fmt.Errorf("creating subscription request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	// PocketBase binds the auth state to the client on subscribe, so
+	// events are filtered by the collection rules for this token
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -18,8 +18,8 @@ type SearchOverlay struct {
 // NewSearchOverlay creates a search overlay
 func NewSearchOverlay(filterState *FilterState) SearchOverlay {
 	ti := textinput.New()
-	ti.Placeholder = "Type to search..."
-	ti.CharLimit = 100
+	ti.Placeholder = "Type to search, or a query like priority:high is:blocked"
+	ti.CharLimit = 200
 	ti.Width = 50
 	ti.Prompt = "/ "
 	ti.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
@@ -129,7 +129,7 @@ func (s SearchOverlay) View() string {
 	help := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240")).
 		MarginTop(1).
-		Render("Enter to search | Esc to cancel | Ctrl+U to clear")
+		Render("Enter to search or filter | Esc to cancel | Ctrl+U to clear")
 
 	// Combine
 	content := lipgloss.JoinVertical(lipgloss.Left, title, input, help)
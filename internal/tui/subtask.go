@@ -8,6 +8,8 @@ import (
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/trash"
 )
 
 // SubtaskIndicator renders the expandable subtask indicator.
@@ -42,6 +44,7 @@ func GetSubtaskCounts(app *pocketbase.PocketBase, boardID string) (map[string]in
 	records, err := app.FindAllRecords("tasks",
 		dbx.NewExp("board = {:board} AND parent != '' AND parent IS NOT NULL",
 			dbx.Params{"board": boardID}),
+		trash.Exclude(app, "tasks"),
 	)
 	if err != nil {
 		return nil, err
@@ -61,6 +64,7 @@ func GetSubtaskCounts(app *pocketbase.PocketBase, boardID string) (map[string]in
 func LoadSubtasks(app *pocketbase.PocketBase, parentID, boardPrefix string) ([]TaskItem, error) {
 	records, err := app.FindAllRecords("tasks",
 		dbx.NewExp("parent = {:parent}", dbx.Params{"parent": parentID}),
+		trash.Exclude(app, "tasks"),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load subtasks: %w", err)
@@ -9,16 +9,26 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/attachment"
+	"github.com/ramtinJ95/EgenSkriven/internal/estimate"
+	"github.com/ramtinJ95/EgenSkriven/internal/tasklink"
+	"github.com/ramtinJ95/EgenSkriven/internal/timetrack"
 )
 
 // TaskDetail displays full task information in a side panel
 type TaskDetail struct {
-	task     TaskItem
-	viewport viewport.Model
-	width    int
-	height   int
-	ready    bool
-	keys     taskDetailKeyMap
+	task         TaskItem
+	timeLogged   *timetrack.Summary
+	estimate     *estimate.Totals
+	estimateUnit string
+	attachments  []attachment.Info
+	links        []tasklink.Link
+	viewport     viewport.Model
+	width        int
+	height       int
+	ready        bool
+	keys         taskDetailKeyMap
 }
 
 type taskDetailKeyMap struct {
@@ -170,6 +180,10 @@ func (td *TaskDetail) updateContent() {
 		sections = append(sections, labels)
 	}
 
+	if len(td.task.Assignees) > 0 {
+		sections = append(sections, "Assignees: "+strings.Join(td.task.Assignees, ", "))
+	}
+
 	if td.task.DueDate != "" {
 		dueStyle := lipgloss.NewStyle()
 		// Could add overdue styling here
@@ -187,6 +201,43 @@ func (td *TaskDetail) updateContent() {
 		sections = append(sections, blockedStyle.Render("Blocked by: "+strings.Join(td.task.BlockedBy, ", ")))
 	}
 
+	item := estimate.Item{ID: td.task.ID, Estimate: td.task.Estimate, Done: td.task.Column == "done"}
+	if text := estimate.Describe(item, td.estimate, td.estimateUnit); text != "" {
+		sections = append(sections, "Estimate: "+text)
+	}
+
+	if td.timeLogged != nil && td.timeLogged.Entries > 0 {
+		timeText := "Time logged: " + timetrack.FormatDuration(td.timeLogged.Total)
+		if td.timeLogged.Running {
+			timeText += " (timer running)"
+		}
+		sections = append(sections, timeText)
+	}
+
+	if len(td.links) > 0 {
+		sections = append(sections, "", fmt.Sprintf("Links (%d):", len(td.links)))
+		mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
+		for _, l := range td.links {
+			line := "  " + mutedStyle.Render(l.Label) + " " + l.DisplayID + " " + l.Title
+			if l.Column == "done" {
+				line += mutedStyle.Render(" (done)")
+			}
+			sections = append(sections, line)
+		}
+	}
+
+	if len(td.attachments) > 0 {
+		sections = append(sections, "", fmt.Sprintf("Attachments (%d):", len(td.attachments)))
+		mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
+		for _, a := range td.attachments {
+			line := "  " + a.DisplayName + " " + mutedStyle.Render(attachment.FormatSize(a.Size))
+			if a.Comment != "" {
+				line += mutedStyle.Render(" (comment)")
+			}
+			sections = append(sections, line)
+		}
+	}
+
 	td.viewport.SetContent(strings.Join(sections, "\n"))
 }
 
@@ -298,6 +349,31 @@ func (td *TaskDetail) Task() TaskItem {
 	return td.task
 }
 
+// SetTimeLogged updates the logged time shown for the task
+func (td *TaskDetail) SetTimeLogged(summary timetrack.Summary) {
+	td.timeLogged = &summary
+	td.updateContent()
+}
+
+// SetEstimate updates the rolled-up estimate shown for the task
+func (td *TaskDetail) SetEstimate(totals estimate.Totals, unit string) {
+	td.estimate = &totals
+	td.estimateUnit = unit
+	td.updateContent()
+}
+
+// SetAttachments updates the files shown for the task and its comments
+func (td *TaskDetail) SetAttachments(infos []attachment.Info) {
+	td.attachments = infos
+	td.updateContent()
+}
+
+// SetLinks updates the typed links shown for the task
+func (td *TaskDetail) SetLinks(links []tasklink.Link) {
+	td.links = links
+	td.updateContent()
+}
+
 // UpdateTask updates the displayed task
 func (td *TaskDetail) UpdateTask(task TaskItem) {
 	td.task = task
@@ -9,6 +9,8 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/tasktemplate"
 )
 
 // FormField represents which field is currently focused
@@ -16,6 +18,8 @@ type FormField int
 
 const (
 	FieldTitle FormField = iota
+	FieldTemplate
+	FieldVars
 	FieldDescription
 	FieldType
 	FieldPriority
@@ -26,7 +30,7 @@ const (
 	FieldCancel
 )
 
-const numFields = 9 // Total number of focusable fields
+const numFields = 11 // Total number of focusable fields
 
 // TaskForm handles task creation and editing
 type TaskForm struct {
@@ -38,16 +42,19 @@ type TaskForm struct {
 	descInput    textarea.Model
 	labelsInput  textinput.Model
 	dueDateInput textinput.Model
+	varsInput    textinput.Model
 
 	// Select fields (index into options)
 	typeSelect     int
 	prioritySelect int
 	columnSelect   int
+	templateSelect int // 0 is no template, i is templates[i-1]
 
 	// Options for select fields
 	types      []string
 	priorities []string
 	columns    []string
+	templates  []tasktemplate.Template // Only set in add mode
 
 	// Form state
 	focusIndex int
@@ -122,12 +129,19 @@ func NewTaskForm(mode FormMode, width, height int) *TaskForm {
 	di.CharLimit = 10
 	di.Width = 15
 
+	// Template variables input
+	vi := textinput.New()
+	vi.Placeholder = "name=value, ..."
+	vi.CharLimit = 500
+	vi.Width = width - 20
+
 	return &TaskForm{
 		mode:           mode,
 		titleInput:     ti,
 		descInput:      ta,
 		labelsInput:    li,
 		dueDateInput:   di,
+		varsInput:      vi,
 		types:          []string{"feature", "bug", "chore"},
 		priorities:     []string{"low", "medium", "high", "urgent"},
 		columns:        []string{"backlog", "todo", "in_progress", "need_input", "review", "done"},
@@ -175,6 +189,61 @@ func NewTaskFormWithData(task *TaskItem, width, height int) *TaskForm {
 	return f
 }
 
+// SetTemplates offers the board's task templates in an add form.
+func (f *TaskForm) SetTemplates(templates []tasktemplate.Template) {
+	if f.mode == FormModeAdd {
+		f.templates = templates
+	}
+}
+
+// selectedTemplate returns the chosen template, or nil for none.
+func (f *TaskForm) selectedTemplate() *tasktemplate.Template {
+	if f.templateSelect == 0 || f.templateSelect > len(f.templates) {
+		return nil
+	}
+	return &f.templates[f.templateSelect-1]
+}
+
+// applyTemplate fills the form with the chosen template's parent task.
+func (f *TaskForm) applyTemplate() {
+	t := f.selectedTemplate()
+	if t == nil {
+		f.varsInput.Placeholder = "name=value, ..."
+		return
+	}
+
+	f.descInput.SetValue(t.Task.Description)
+	f.labelsInput.SetValue(strings.Join(t.Task.Labels, ", "))
+	f.typeSelect = indexOf(f.types, t.Task.Type, 0)
+	f.prioritySelect = indexOf(f.priorities, t.Task.Priority, 1)
+	f.columnSelect = indexOf(f.columns, t.Task.Column, 0)
+
+	// List the variables the user is expected to fill in
+	var names []string
+	for _, name := range t.Placeholders() {
+		if _, builtin := tasktemplate.BuiltinVars("", "")[name]; builtin {
+			continue
+		}
+		if def := t.Vars[name]; def != "" {
+			name += "=" + def
+		} else {
+			name += "="
+		}
+		names = append(names, name)
+	}
+	f.varsInput.Placeholder = strings.Join(names, ", ")
+}
+
+// indexOf returns the index of value in options, or def if it is missing.
+func indexOf(options []string, value string, def int) int {
+	for i, opt := range options {
+		if opt == value {
+			return i
+		}
+	}
+	return def
+}
+
 // Init initializes the form
 func (f *TaskForm) Init() tea.Cmd {
 	return textinput.Blink
@@ -255,6 +324,16 @@ func (f *TaskForm) View() string {
 	var fields []string
 
 	fields = append(fields, f.renderField("Title", f.titleInput.View(), FieldTitle))
+	if f.fieldVisible(FieldTemplate) {
+		names := []string{"none"}
+		for _, t := range f.templates {
+			names = append(names, t.Name)
+		}
+		fields = append(fields, f.renderSelect("Template", names, f.templateSelect, FieldTemplate))
+	}
+	if f.fieldVisible(FieldVars) {
+		fields = append(fields, f.renderField("Variables", f.varsInput.View(), FieldVars))
+	}
 	fields = append(fields, f.renderField("Description", f.descInput.View(), FieldDescription))
 	fields = append(fields, f.renderSelect("Type", f.types, f.typeSelect, FieldType))
 	fields = append(fields, f.renderSelect("Priority", f.priorities, f.prioritySelect, FieldPriority))
@@ -292,6 +371,7 @@ func (f *TaskForm) SetSize(width, height int) {
 	f.titleInput.Width = width - 20
 	f.descInput.SetWidth(width - 20)
 	f.labelsInput.Width = width - 20
+	f.varsInput.Width = width - 20
 }
 
 func (f *TaskForm) renderField(label, input string, field FormField) string {
@@ -355,15 +435,33 @@ func (f *TaskForm) renderButtons() string {
 func (f *TaskForm) nextField() {
 	f.blurCurrent()
 	f.focusIndex = (f.focusIndex + 1) % numFields
+	for !f.fieldVisible(FormField(f.focusIndex)) {
+		f.focusIndex = (f.focusIndex + 1) % numFields
+	}
 	f.focusCurrent()
 }
 
 func (f *TaskForm) prevField() {
 	f.blurCurrent()
 	f.focusIndex = (f.focusIndex - 1 + numFields) % numFields
+	for !f.fieldVisible(FormField(f.focusIndex)) {
+		f.focusIndex = (f.focusIndex - 1 + numFields) % numFields
+	}
 	f.focusCurrent()
 }
 
+// fieldVisible reports whether a field is shown: the template fields only
+// appear when the board has templates.
+func (f *TaskForm) fieldVisible(field FormField) bool {
+	switch field {
+	case FieldTemplate:
+		return len(f.templates) > 0
+	case FieldVars:
+		return f.selectedTemplate() != nil
+	}
+	return true
+}
+
 func (f *TaskForm) blurCurrent() {
 	switch FormField(f.focusIndex) {
 	case FieldTitle:
@@ -374,6 +472,8 @@ func (f *TaskForm) blurCurrent() {
 		f.labelsInput.Blur()
 	case FieldDueDate:
 		f.dueDateInput.Blur()
+	case FieldVars:
+		f.varsInput.Blur()
 	}
 }
 
@@ -387,12 +487,14 @@ func (f *TaskForm) focusCurrent() {
 		f.labelsInput.Focus()
 	case FieldDueDate:
 		f.dueDateInput.Focus()
+	case FieldVars:
+		f.varsInput.Focus()
 	}
 }
 
 func (f *TaskForm) isSelectField() bool {
 	field := FormField(f.focusIndex)
-	return field == FieldType || field == FieldPriority || field == FieldColumn
+	return field == FieldType || field == FieldPriority || field == FieldColumn || field == FieldTemplate
 }
 
 func (f *TaskForm) selectNext() {
@@ -403,6 +505,9 @@ func (f *TaskForm) selectNext() {
 		f.prioritySelect = (f.prioritySelect + 1) % len(f.priorities)
 	case FieldColumn:
 		f.columnSelect = (f.columnSelect + 1) % len(f.columns)
+	case FieldTemplate:
+		f.templateSelect = (f.templateSelect + 1) % (len(f.templates) + 1)
+		f.applyTemplate()
 	}
 }
 
@@ -414,6 +519,9 @@ func (f *TaskForm) selectPrev() {
 		f.prioritySelect = (f.prioritySelect - 1 + len(f.priorities)) % len(f.priorities)
 	case FieldColumn:
 		f.columnSelect = (f.columnSelect - 1 + len(f.columns)) % len(f.columns)
+	case FieldTemplate:
+		f.templateSelect = (f.templateSelect + len(f.templates)) % (len(f.templates) + 1)
+		f.applyTemplate()
 	}
 }
 
@@ -429,6 +537,8 @@ func (f *TaskForm) updateFocusedInput(msg tea.Msg) tea.Cmd {
 		f.labelsInput, cmd = f.labelsInput.Update(msg)
 	case FieldDueDate:
 		f.dueDateInput, cmd = f.dueDateInput.Update(msg)
+	case FieldVars:
+		f.varsInput, cmd = f.varsInput.Update(msg)
 	}
 
 	return cmd
@@ -437,10 +547,27 @@ func (f *TaskForm) updateFocusedInput(msg tea.Msg) tea.Cmd {
 func (f *TaskForm) submit() tea.Cmd {
 	// Validate
 	title := strings.TrimSpace(f.titleInput.Value())
-	if title == "" {
+	t := f.selectedTemplate()
+	if title == "" && (t == nil || usesTitle(*t)) {
 		return showStatus("Title is required", true, 3*time.Second)
 	}
 
+	// Parse template variables
+	var vars map[string]string
+	if t != nil {
+		vars = make(map[string]string)
+		for _, pair := range strings.Split(f.varsInput.Value(), ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			name, value, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				return showStatus("Variables must be name=value", true, 3*time.Second)
+			}
+			vars[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+
 	// Parse labels
 	var labels []string
 	labelsStr := strings.TrimSpace(f.labelsInput.Value())
@@ -461,6 +588,10 @@ func (f *TaskForm) submit() tea.Cmd {
 		Column:      f.columns[f.columnSelect],
 		Labels:      labels,
 		DueDate:     strings.TrimSpace(f.dueDateInput.Value()),
+		Vars:        vars,
+	}
+	if t != nil {
+		data.Template = t.Name
 	}
 
 	return func() tea.Msg {
@@ -472,6 +603,16 @@ func (f *TaskForm) submit() tea.Cmd {
 	}
 }
 
+// usesTitle reports whether a template's text uses the {{title}} variable.
+func usesTitle(t tasktemplate.Template) bool {
+	for _, name := range t.Placeholders() {
+		if name == tasktemplate.TitleVar {
+			return true
+		}
+	}
+	return false
+}
+
 // Mode returns the form mode
 func (f *TaskForm) Mode() FormMode {
 	return f.mode
@@ -2,10 +2,15 @@ package tui
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/archive"
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskquery"
 )
 
 // TaskItem represents a task in the kanban board.
@@ -20,9 +25,18 @@ type TaskItem struct {
 	Column          string // backlog, todo, in_progress, need_input, review, done
 	Labels          []string
 	Position        float64
-	DueDate         string // due date in YYYY-MM-DD format
-	EpicID          string // ID of parent epic
-	EpicTitle       string // title of parent epic (for display)
+	DueDate         string  // due date in YYYY-MM-DD format
+	EpicID          string  // ID of parent epic
+	EpicTitle       string  // title of parent epic (for display)
+	SprintID        string  // ID of the sprint the task belongs to
+	Estimate        float64 // size in the board's estimate unit (0 = unestimated)
+	Assignees       []string
+	CreatedBy       string // user, agent, or cli
+	CreatedByAgent  string // agent name when created by an agent
+	Created         string // creation time as stored by PocketBase
+	Updated         string // last update time as stored by PocketBase
+	ChecklistDone   int    // checklist items ticked off
+	ChecklistTotal  int    // checklist items (0 = no checklist)
 
 	// Display fields
 	DisplayID string // e.g., "WRK-123"
@@ -30,6 +44,7 @@ type TaskItem struct {
 	// Computed fields
 	IsBlocked bool
 	BlockedBy []string
+	Archived  bool // Shown when the board includes archived tasks
 
 	// Resolved epic information (for badge display)
 	Epic EpicOption
@@ -65,7 +80,7 @@ func (t TaskItem) Description() string {
 }
 
 // renderTitle creates the formatted title line for display.
-// Format: [x] [PRIORITY] DISPLAY_ID [+N] Title [TYPE] [BLOCKED] [DUE DATE]
+// Format: [x] [PRIORITY] DISPLAY_ID [+N] Title [TYPE] [BLOCKED] [ARCHIVED] [DUE DATE]
 func (t TaskItem) renderTitle() string {
 	var parts []string
 
@@ -106,6 +121,12 @@ func (t TaskItem) renderTitle() string {
 		parts = append(parts, blocked)
 	}
 
+	// Archived indicator
+	if t.Archived {
+		archived := lipgloss.NewStyle().Foreground(mutedColor).Render("[ARCHIVED]")
+		parts = append(parts, archived)
+	}
+
 	// Due date with urgency highlighting
 	if t.DueDate != "" {
 		dueStr := RenderDueDate(t.DueDate)
@@ -118,7 +139,7 @@ func (t TaskItem) renderTitle() string {
 }
 
 // renderDescription creates the secondary info line.
-// Shows epic badge, labels, blocked info, and other metadata.
+// Shows epic badge, labels, assignees, estimate, blocked info, and other metadata.
 func (t TaskItem) renderDescription() string {
 	var parts []string
 
@@ -143,6 +164,27 @@ func (t TaskItem) renderDescription() string {
 		}
 	}
 
+	// Assignees
+	if len(t.Assignees) > 0 {
+		assigneeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("75"))
+		parts = append(parts, assigneeStyle.Render("@"+strings.Join(t.Assignees, " @")))
+	}
+
+	// Estimate
+	if t.Estimate > 0 {
+		estimateStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+		parts = append(parts, estimateStyle.Render("est "+strconv.FormatFloat(t.Estimate, 'f', -1, 64)))
+	}
+
+	// Checklist progress
+	if t.ChecklistTotal > 0 {
+		checklistStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+		if t.ChecklistDone == t.ChecklistTotal {
+			checklistStyle = checklistStyle.Foreground(lipgloss.Color("42"))
+		}
+		parts = append(parts, checklistStyle.Render(fmt.Sprintf("✓ %d/%d", t.ChecklistDone, t.ChecklistTotal)))
+	}
+
 	// Blocked by info (show count of blocking tasks)
 	if t.IsBlocked && len(t.BlockedBy) > 0 {
 		blockedStyle := lipgloss.NewStyle().
@@ -176,6 +218,8 @@ func NewTaskItemFromRecord(record *core.Record, displayID string) TaskItem {
 	blockedBy := record.GetStringSlice("blocked_by")
 	isBlocked := len(blockedBy) > 0
 
+	checklistDone, checklistTotal := checklist.Progress(checklist.Get(record))
+
 	return TaskItem{
 		ID:              record.Id,
 		TaskTitle:       record.GetString("title"),
@@ -187,9 +231,19 @@ func NewTaskItemFromRecord(record *core.Record, displayID string) TaskItem {
 		Position:        record.GetFloat("position"),
 		DueDate:         record.GetString("due_date"),
 		EpicID:          record.GetString("epic"),
+		SprintID:        record.GetString("sprint"),
+		Estimate:        record.GetFloat("estimate"),
+		Assignees:       record.GetStringSlice("assignees"),
+		CreatedBy:       record.GetString("created_by"),
+		CreatedByAgent:  record.GetString("created_by_agent"),
+		Created:         record.GetString("created"),
+		Updated:         record.GetString("updated"),
+		ChecklistDone:   checklistDone,
+		ChecklistTotal:  checklistTotal,
 		DisplayID:       displayID,
 		IsBlocked:       isBlocked,
 		BlockedBy:       blockedBy,
+		Archived:        archive.IsArchived(record),
 		ParentID:        record.GetString("parent"),
 	}
 }
@@ -255,6 +309,8 @@ func NewTaskItemFromMap(m map[string]interface{}, boardPrefix string) TaskItem {
 	blockedBy := getStringSlice("blocked_by")
 	isBlocked := len(blockedBy) > 0
 
+	checklistDone, checklistTotal := checklist.Progress(checklist.Decode(m["checklist"]))
+
 	return TaskItem{
 		ID:              getString("id"),
 		TaskTitle:       getString("title"),
@@ -266,9 +322,19 @@ func NewTaskItemFromMap(m map[string]interface{}, boardPrefix string) TaskItem {
 		Position:        getFloat("position"),
 		DueDate:         getString("due_date"),
 		EpicID:          getString("epic"),
+		SprintID:        getString("sprint"),
+		Estimate:        getFloat("estimate"),
+		Assignees:       getStringSlice("assignees"),
+		CreatedBy:       getString("created_by"),
+		CreatedByAgent:  getString("created_by_agent"),
+		Created:         getString("created"),
+		Updated:         getString("updated"),
+		ChecklistDone:   checklistDone,
+		ChecklistTotal:  checklistTotal,
 		DisplayID:       displayID,
 		IsBlocked:       isBlocked,
 		BlockedBy:       blockedBy,
+		Archived:        getString("archived_at") != "",
 		ParentID:        getString("parent"),
 	}
 }
@@ -312,3 +378,34 @@ func ResolveEpics(tasks []TaskItem, epics []EpicOption) []TaskItem {
 
 	return tasks
 }
+
+// queryTask returns the fields queries and saved views filter on.
+func (t TaskItem) queryTask() taskquery.Task {
+	return taskquery.Task{
+		Column:         t.Column,
+		Priority:       t.Priority,
+		Type:           t.Type,
+		CreatedBy:      t.CreatedBy,
+		CreatedByAgent: t.CreatedByAgent,
+		Title:          t.TaskTitle,
+		Description:    t.TaskDescription,
+		Epic:           t.EpicID,
+		Sprint:         t.SprintID,
+		Parent:         t.ParentID,
+		DueDate:        dateOnly(t.DueDate),
+		Created:        dateOnly(t.Created),
+		Updated:        dateOnly(t.Updated),
+		Estimate:       t.Estimate,
+		Labels:         t.Labels,
+		Assignees:      t.Assignees,
+		BlockedBy:      t.BlockedBy,
+	}
+}
+
+// dateOnly returns the YYYY-MM-DD part of a stored date.
+func dateOnly(date string) string {
+	if len(date) > 10 {
+		return date[:10]
+	}
+	return date
+}
@@ -0,0 +1,595 @@
+// Package undo records task mutations as reversible operations.
+//
+// Every task create, update, and delete is stored in the operations
+// collection with before and after snapshots of the task (see
+// hooks.RegisterUndoHooks). Operations are grouped into batches, one per user
+// action: a CLI command (including `add --stdin` and multi-task deletes) or
+// a TUI bulk operation. Each actor has an undo stack of applied batches and
+// a redo stack of undone ones; recording a new batch clears the redo stack.
+//
+// Undo and redo check that every task is still in the state the batch left
+// it in. Tasks changed since by someone else are reported as conflicts and
+// nothing in the batch is reverted.
+package undo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/security"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+)
+
+// CollectionName is the name of the operations collection.
+const CollectionName = "operations"
+
+// Operation kinds.
+const (
+	KindCreate = "create"
+	KindUpdate = "update"
+	KindDelete = "delete"
+)
+
+// Operation states.
+const (
+	StatusApplied = "applied"
+	StatusUndone  = "undone"
+)
+
+// MaxBatches is the number of batches kept per actor.
+const MaxBatches = 100
+
+// maxLabelLength bounds stored labels (commands may carry long descriptions).
+const maxLabelLength = 200
+
+// HTTP headers carrying the current batch to the server.
+const (
+	HeaderBatch = "X-Egenskriven-Batch"
+	HeaderLabel = "X-Egenskriven-Label"
+	HeaderActor = "X-Egenskriven-Actor"
+)
+
+// Custom (non-persisted) record keys used to tag saves.
+const (
+	batchKey = "@undo_batch"
+	skipKey  = "@undo_skip"
+)
+
+// ErrNothingToUndo and ErrNothingToRedo are returned when a stack is empty.
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// skippedFields are not snapshotted: they are managed by PocketBase or
+// files whose content is gone once removed.
+var skippedFields = map[string]bool{
+	"id":          true,
+	"created":     true,
+	"updated":     true,
+	"attachments": true,
+}
+
+// Batch groups the operations of one user action.
+type Batch struct {
+	ID    string // Empty starts a new batch per operation
+	Label string // Description shown by undo/redo (e.g. "move WRK-1 done")
+	Actor string // Whose undo stack the batch belongs to
+}
+
+// NewBatch returns a batch with a fresh ID.
+func NewBatch(label, actor string) Batch {
+	return Batch{ID: security.RandomString(15), Label: label, Actor: actor}
+}
+
+var (
+	currentMu sync.Mutex
+	current   Batch
+)
+
+// Begin sets the batch used for operations of this process that are not
+// tagged with Tag. The CLI begins one batch per command.
+func Begin(b Batch) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = b
+}
+
+// Current returns the batch set with Begin.
+func Current() Batch {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	return current
+}
+
+// SetHeaders attaches the current batch to an API request so that the
+// server groups the operations with those of the same command.
+func SetHeaders(req *http.Request) {
+	b := Current()
+	if b.ID != "" {
+		req.Header.Set(HeaderBatch, b.ID)
+		req.Header.Set(HeaderLabel, b.Label)
+	}
+	if b.Actor != "" {
+		req.Header.Set(HeaderActor, b.Actor)
+	}
+}
+
+// Tag assigns a task save or delete to a batch, overriding Begin.
+func Tag(task *core.Record, b Batch) {
+	task.Set(batchKey, b)
+}
+
+// Skip excludes the next save or delete of a task from the undo log.
+func Skip(task *core.Record) {
+	task.Set(skipKey, true)
+}
+
+// BatchFor returns the batch a save belongs to: the tagged batch, else the
+// process batch, else a new single-operation batch. Missing labels and
+// actors are filled from the fallbacks.
+func BatchFor(task *core.Record, label, actor string) (Batch, bool) {
+	if skip, _ := task.GetRaw(skipKey).(bool); skip {
+		return Batch{}, false
+	}
+
+	b, _ := task.GetRaw(batchKey).(Batch)
+	if b.ID == "" && b.Actor == "" {
+		b = Current()
+	}
+	if b.ID == "" {
+		b.ID = security.RandomString(15)
+		b.Label = ""
+	}
+	if b.Label == "" {
+		b.Label = label
+	}
+	if b.Actor == "" {
+		b.Actor = actor
+	}
+	return b, true
+}
+
+// Snapshot returns the undoable field values of a task.
+func Snapshot(task *core.Record) map[string]any {
+	if task == nil {
+		return nil
+	}
+	snapshot := make(map[string]any)
+	for _, field := range task.Collection().Fields {
+		name := field.GetName()
+		if skippedFields[name] {
+			continue
+		}
+		snapshot[name] = normalize(task.Get(name))
+	}
+	return snapshot
+}
+
+// Changed reports whether two snapshots differ.
+func Changed(before, after map[string]any) bool {
+	return len(changedFields(before, after, false)) > 0
+}
+
+// Record stores an operation and, for the first operation of a batch,
+// clears the actor's redo stack and prunes old batches.
+func Record(app core.App, b Batch, kind string, taskID, boardID string, before, after map[string]any) error {
+	collection, err := app.FindCollectionByNameOrId(CollectionName)
+	if err != nil {
+		return fmt.Errorf("operations collection not found: %w", err)
+	}
+
+	existing, err := app.FindFirstRecordByFilter(CollectionName, "batch = {:batch}", dbx.Params{"batch": b.ID})
+	if err != nil || existing == nil {
+		if err := startBatch(app, b.Actor); err != nil {
+			return err
+		}
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("batch", b.ID)
+	record.Set("label", truncate(b.Label, maxLabelLength))
+	record.Set("actor", b.Actor)
+	record.Set("kind", kind)
+	record.Set("task", taskID)
+	record.Set("board", boardID)
+	record.Set("before", before)
+	record.Set("after", after)
+	record.Set("status", StatusApplied)
+	record.Set("seq", nextSeq())
+	return app.Save(record)
+}
+
+var (
+	seqMu   sync.Mutex
+	lastSeq int64
+)
+
+// nextSeq returns an increasing sequence number ordering operations across
+// processes (microseconds, which fit the float64 of a number field).
+func nextSeq() int64 {
+	seqMu.Lock()
+	defer seqMu.Unlock()
+	seq := time.Now().UnixMicro()
+	if seq <= lastSeq {
+		seq = lastSeq + 1
+	}
+	lastSeq = seq
+	return seq
+}
+
+// startBatch drops the actor's undone operations (the redo stack) and all
+// but the most recent MaxBatches batches.
+func startBatch(app core.App, actor string) error {
+	if _, err := app.DB().NewQuery(
+		"DELETE FROM operations WHERE actor = {:actor} AND status = {:status}",
+	).Bind(dbx.Params{"actor": actor, "status": StatusUndone}).Execute(); err != nil {
+		return err
+	}
+
+	_, err := app.DB().NewQuery(`DELETE FROM operations WHERE actor = {:actor} AND batch NOT IN (
+		SELECT batch FROM operations WHERE actor = {:actor}
+		GROUP BY batch ORDER BY MAX(seq) DESC LIMIT {:keep})`,
+	).Bind(dbx.Params{"actor": actor, "keep": MaxBatches - 1}).Execute()
+	return err
+}
+
+// Summary describes an undone or redone batch.
+type Summary struct {
+	Batch      string    `json:"batch"`
+	Label      string    `json:"label"`
+	Operations int       `json:"operations"`
+	Tasks      []string  `json:"tasks"`
+	At         time.Time `json:"at"`
+}
+
+// Conflict is a task changed by someone else after the batch.
+type Conflict struct {
+	Task   string `json:"task"`
+	Title  string `json:"title,omitempty"`
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// ConflictError is returned when a batch cannot be reverted without
+// overwriting later changes.
+type ConflictError struct {
+	Label     string
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("cannot revert %q: %d task(s) changed since", e.Label, len(e.Conflicts))
+}
+
+// Actor identifies who undoes or redoes: Name selects the undo stack and
+// Source ("cli", "tui") is recorded in the task event log. Check, when set,
+// is called before each task change with the board, the kind of change,
+// and the column before and after it; an error aborts the whole batch.
+type Actor struct {
+	Name   string
+	Source string
+	Check  func(boardID, kind, fromColumn, toColumn string) error
+}
+
+// PolicyAction is the policy action of a task change made by undo or redo.
+func PolicyAction(kind, fromColumn, toColumn string) policy.Action {
+	switch kind {
+	case KindDelete:
+		return policy.ActionDelete
+	case KindCreate:
+		if toColumn == policy.DoneColumn {
+			return policy.ActionComplete
+		}
+		return policy.ActionCreate
+	}
+	return policy.MoveAction(fromColumn, toColumn)
+}
+
+// Undo reverts the actor's most recent applied batch.
+func Undo(app core.App, by Actor) (*Summary, error) {
+	return apply(app, by, StatusApplied, StatusUndone, ErrNothingToUndo)
+}
+
+// Redo re-applies the actor's most recently undone batch.
+func Redo(app core.App, by Actor) (*Summary, error) {
+	return apply(app, by, StatusUndone, StatusApplied, ErrNothingToRedo)
+}
+
+// Stack returns the actor's batches in a state, most recent first: the
+// undo stack for StatusApplied and the redo stack for StatusUndone.
+func Stack(app core.App, actor, status string) ([]Summary, error) {
+	query := app.RecordQuery(CollectionName).
+		// A hash expression, since a filter param renders "" as a quoted
+		// string and never matches the operations of an unnamed actor
+		AndWhere(dbx.HashExp{"actor": actor, "status": status})
+	if status == StatusUndone {
+		query = query.OrderBy("undone_at DESC", "seq DESC")
+	} else {
+		query = query.OrderBy("seq DESC")
+	}
+	var ops []*core.Record
+	if err := query.All(&ops); err != nil {
+		return nil, err
+	}
+
+	var summaries []Summary
+	index := make(map[string]int)
+	for _, op := range ops {
+		i, ok := index[op.GetString("batch")]
+		if !ok {
+			i = len(summaries)
+			index[op.GetString("batch")] = i
+			summaries = append(summaries, Summary{
+				Batch: op.GetString("batch"),
+				Label: op.GetString("label"),
+				At:    op.GetDateTime("created").Time(),
+			})
+		}
+		summaries[i].Operations++
+		summaries[i].Tasks = appendUnique(summaries[i].Tasks, op.GetString("task"))
+	}
+	return summaries, nil
+}
+
+// apply moves the most recent batch in status from to status to, reverting
+// (undo) or re-applying (redo) its operations in one transaction.
+func apply(app core.App, by Actor, from, to string, empty error) (*Summary, error) {
+	stack, err := Stack(app, by.Name, from)
+	if err != nil {
+		return nil, err
+	}
+	if len(stack) == 0 {
+		return nil, empty
+	}
+	summary := stack[0]
+
+	var ops []*core.Record
+	err = app.RecordQuery(CollectionName).
+		AndWhere(dbx.HashExp{"batch": summary.Batch}).
+		OrderBy("seq ASC").
+		All(&ops)
+	if err != nil {
+		return nil, err
+	}
+
+	undoing := from == StatusApplied
+	if undoing {
+		// Revert in reverse order
+		for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+			ops[i], ops[j] = ops[j], ops[i]
+		}
+	}
+
+	var conflicts []Conflict
+	err = app.RunInTransaction(func(txApp core.App) error {
+		for _, op := range ops {
+			c, err := revert(txApp, op, undoing, by)
+			if err != nil {
+				return err
+			}
+			conflicts = append(conflicts, c...)
+		}
+		if len(conflicts) > 0 {
+			return &ConflictError{Label: summary.Label, Conflicts: conflicts}
+		}
+
+		now := time.Now().UTC()
+		for _, op := range ops {
+			op.Set("status", to)
+			op.Set("undone_at", now)
+			if err := txApp.Save(op); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// revert applies one operation backwards (undo) or forwards (redo).
+// It returns conflicts instead of overwriting changes made since.
+func revert(app core.App, op *core.Record, undoing bool, by Actor) ([]Conflict, error) {
+	var before, after map[string]any
+	op.UnmarshalJSONField("before", &before)
+	op.UnmarshalJSONField("after", &after)
+
+	// Undo applies the inverse operation from the after snapshot back to
+	// the before snapshot; redo re-applies the operation itself
+	kind := op.GetString("kind")
+	expected, target := before, after
+	if undoing {
+		expected, target = after, before
+		switch kind {
+		case KindCreate:
+			kind = KindDelete
+		case KindDelete:
+			kind = KindCreate
+		}
+	}
+
+	if by.Check != nil {
+		from, _ := expected["column"].(string)
+		to, _ := target["column"].(string)
+		if err := by.Check(op.GetString("board"), kind, from, to); err != nil {
+			return nil, err
+		}
+	}
+
+	taskID := op.GetString("task")
+	task, _ := app.FindRecordById("tasks", taskID)
+
+	switch kind {
+	case KindDelete:
+		// Remove a task the operation created (undo) or deleted (redo)
+		if task == nil {
+			return []Conflict{{Task: taskID, Title: title(expected), Reason: "task was deleted"}}, nil
+		}
+		if c := compare(task, expected, changedFields(before, after, true)); len(c) > 0 {
+			return c, nil
+		}
+		Skip(task)
+		return nil, app.Delete(task)
+
+	case KindCreate:
+		// Restore a task the operation deleted (undo) or created (redo)
+		if task != nil {
+			return []Conflict{{Task: taskID, Title: title(target), Reason: "task exists"}}, nil
+		}
+		collection, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return nil, err
+		}
+		task = core.NewRecord(collection)
+		task.Id = taskID
+		for field, value := range target {
+			task.Set(field, value)
+		}
+		taskevent.Add(task, "restored", by.Source, by.Name, nil, nil)
+		Skip(task)
+		return nil, app.Save(task)
+
+	default:
+		// Update: restore the fields the operation changed
+		if task == nil {
+			return []Conflict{{Task: taskID, Title: title(expected), Reason: "task was deleted"}}, nil
+		}
+		fields := changedFields(before, after, false)
+		if c := compare(task, expected, fields); len(c) > 0 {
+			return c, nil
+		}
+		changes := make(map[string]any, len(fields))
+		for _, field := range fields {
+			changes[field] = map[string]any{"from": expected[field], "to": target[field]}
+			task.Set(field, target[field])
+		}
+		action := "undone"
+		if !undoing {
+			action = "redone"
+		}
+		taskevent.Add(task, action, by.Source, by.Name, changes, nil)
+		Skip(task)
+		return nil, app.Save(task)
+	}
+}
+
+// compare reports fields whose current value differs from the expected
+// snapshot.
+func compare(task *core.Record, expected map[string]any, fields []string) []Conflict {
+	var conflicts []Conflict
+	for _, field := range fields {
+		// Positions shift when neighbours are reordered, which is not a
+		// conflict
+		if field == "position" {
+			continue
+		}
+		if !equal(normalize(task.Get(field)), expected[field]) {
+			conflicts = append(conflicts, Conflict{
+				Task:   task.Id,
+				Title:  task.GetString("title"),
+				Field:  field,
+				Reason: fmt.Sprintf("%s was changed since", field),
+			})
+		}
+	}
+	return conflicts
+}
+
+// changedFields returns the fields that differ between two snapshots. For
+// created or deleted tasks (whole=true) all fields of the existing side are
+// returned.
+func changedFields(before, after map[string]any, whole bool) []string {
+	var fields []string
+	if whole {
+		snapshot := after
+		if snapshot == nil {
+			snapshot = before
+		}
+		for field := range snapshot {
+			fields = append(fields, field)
+		}
+	} else {
+		for field, value := range after {
+			if !equal(before[field], value) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func title(snapshot map[string]any) string {
+	s, _ := snapshot["title"].(string)
+	return s
+}
+
+// normalize converts field values to JSON-comparable forms.
+func normalize(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// equal compares normalized values, treating nil and empty values alike.
+func equal(a, b any) bool {
+	if isEmpty(a) && isEmpty(b) {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func isEmpty(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case string:
+		return x == ""
+	case float64:
+		return x == 0
+	case bool:
+		return !x
+	case []any:
+		return len(x) == 0
+	case map[string]any:
+		return len(x) == 0
+	}
+	return false
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, item := range list {
+		if item == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
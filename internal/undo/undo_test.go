@@ -0,0 +1,317 @@
+package undo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+var me = Actor{Name: "ramtin", Source: "cli"}
+
+// setup creates minimal tasks and operations collections and records task
+// changes the way hooks.RegisterUndoHooks does.
+func setup(t *testing.T) (*pocketbase.PocketBase, *core.Collection) {
+	t.Helper()
+
+	app := testutil.NewTestApp(t)
+	tasks := testutil.CreateTestCollection(t, app, "tasks",
+		&core.TextField{Name: "title", Required: true},
+		&core.TextField{Name: "column"},
+		&core.TextField{Name: "priority"},
+		&core.TextField{Name: "board"},
+		&core.NumberField{Name: "position"},
+		&core.JSONField{Name: "labels"},
+	)
+	testutil.CreateTestCollection(t, app, CollectionName,
+		&core.TextField{Name: "batch"},
+		&core.TextField{Name: "label"},
+		&core.TextField{Name: "actor"},
+		&core.TextField{Name: "kind"},
+		&core.TextField{Name: "task"},
+		&core.TextField{Name: "board"},
+		&core.JSONField{Name: "before"},
+		&core.JSONField{Name: "after"},
+		&core.TextField{Name: "status"},
+		&core.NumberField{Name: "seq"},
+		&core.DateField{Name: "undone_at"},
+		&core.AutodateField{Name: "created", OnCreate: true},
+	)
+
+	record := func(task *core.Record, kind string, before, after map[string]any) {
+		b, ok := BatchFor(task, kind, "ramtin")
+		if ok {
+			require.NoError(t, Record(app, b, kind, task.Id, task.GetString("board"), before, after))
+		}
+	}
+	app.OnRecordCreate("tasks").BindFunc(func(e *core.RecordEvent) error {
+		if err := e.Next(); err != nil {
+			return err
+		}
+		record(e.Record, KindCreate, nil, Snapshot(e.Record))
+		return nil
+	})
+	app.OnRecordUpdate("tasks").BindFunc(func(e *core.RecordEvent) error {
+		before := Snapshot(e.Record.Original())
+		if err := e.Next(); err != nil {
+			return err
+		}
+		if after := Snapshot(e.Record); Changed(before, after) {
+			record(e.Record, KindUpdate, before, after)
+		}
+		return nil
+	})
+	app.OnRecordDelete("tasks").BindFunc(func(e *core.RecordEvent) error {
+		before := Snapshot(e.Record)
+		if err := e.Next(); err != nil {
+			return err
+		}
+		record(e.Record, KindDelete, before, nil)
+		return nil
+	})
+
+	t.Cleanup(func() { Begin(Batch{}) })
+	return app, tasks
+}
+
+func newTask(t *testing.T, app *pocketbase.PocketBase, tasks *core.Collection, title string) *core.Record {
+	t.Helper()
+
+	task := core.NewRecord(tasks)
+	task.Set("title", title)
+	task.Set("column", "todo")
+	task.Set("priority", "medium")
+	task.Set("board", "board1")
+	require.NoError(t, app.Save(task))
+	return task
+}
+
+func reload(t *testing.T, app *pocketbase.PocketBase, id string) *core.Record {
+	t.Helper()
+
+	task, err := app.FindRecordById("tasks", id)
+	require.NoError(t, err)
+	return task
+}
+
+func TestUndoRedo_Update(t *testing.T) {
+	app, tasks := setup(t)
+	task := newTask(t, app, tasks, "Fix bug")
+
+	Begin(NewBatch("move WRK-1 done", "ramtin"))
+	task = reload(t, app, task.Id)
+	task.Set("column", "done")
+	task.Set("labels", []string{"ui"})
+	require.NoError(t, app.Save(task))
+
+	summary, err := Undo(app, me)
+	require.NoError(t, err)
+	assert.Equal(t, "move WRK-1 done", summary.Label)
+	assert.Equal(t, 1, summary.Operations)
+
+	task = reload(t, app, task.Id)
+	assert.Equal(t, "todo", task.GetString("column"))
+	assert.Empty(t, task.GetStringSlice("labels"))
+
+	summary, err = Redo(app, me)
+	require.NoError(t, err)
+	assert.Equal(t, "move WRK-1 done", summary.Label)
+	assert.Equal(t, "done", reload(t, app, task.Id).GetString("column"))
+
+	// Undo and redo are not themselves recorded
+	_, err = Redo(app, me)
+	assert.ErrorIs(t, err, ErrNothingToRedo)
+	stack, err := Stack(app, "ramtin", StatusApplied)
+	require.NoError(t, err)
+	assert.Len(t, stack, 2, "create and move")
+}
+
+func TestUndoRedo_UnnamedActor(t *testing.T) {
+	app, tasks := setup(t)
+	task := newTask(t, app, tasks, "Fix bug")
+
+	// Without defaults.author the CLI records operations for actor ""
+	before := Snapshot(task)
+	task = reload(t, app, task.Id)
+	task.Set("title", "Fix the bug")
+	Skip(task)
+	require.NoError(t, app.Save(task))
+	require.NoError(t, Record(app, NewBatch("update WRK-1", ""), KindUpdate, task.Id, "board1", before, Snapshot(task)))
+
+	stack, err := Stack(app, "", StatusApplied)
+	require.NoError(t, err)
+	require.Len(t, stack, 1)
+
+	anonymous := Actor{Source: "cli"}
+	summary, err := Undo(app, anonymous)
+	require.NoError(t, err)
+	assert.Equal(t, "update WRK-1", summary.Label)
+	assert.Equal(t, "Fix bug", reload(t, app, task.Id).GetString("title"))
+
+	_, err = Redo(app, anonymous)
+	require.NoError(t, err)
+	assert.Equal(t, "Fix the bug", reload(t, app, task.Id).GetString("title"))
+}
+
+func TestUndoRedo_CreateAndDelete(t *testing.T) {
+	app, tasks := setup(t)
+
+	Begin(NewBatch("add", "ramtin"))
+	task := newTask(t, app, tasks, "New task")
+
+	_, err := Undo(app, me)
+	require.NoError(t, err)
+	_, err = app.FindRecordById("tasks", task.Id)
+	assert.Error(t, err, "undoing a create deletes the task")
+
+	_, err = Redo(app, me)
+	require.NoError(t, err)
+	assert.Equal(t, "New task", reload(t, app, task.Id).GetString("title"))
+
+	Begin(NewBatch("delete", "ramtin"))
+	require.NoError(t, app.Delete(reload(t, app, task.Id)))
+
+	_, err = Undo(app, me)
+	require.NoError(t, err)
+	restored := reload(t, app, task.Id)
+	assert.Equal(t, "New task", restored.GetString("title"))
+	assert.Equal(t, "todo", restored.GetString("column"))
+}
+
+func TestUndo_CheckDenies(t *testing.T) {
+	app, tasks := setup(t)
+
+	Begin(NewBatch("add", "ramtin"))
+	task := newTask(t, app, tasks, "New task")
+
+	var calls []string
+	denied := errors.New("denied")
+	by := me
+	by.Check = func(boardID, kind, fromColumn, toColumn string) error {
+		calls = append(calls, boardID+" "+kind+" "+fromColumn+" "+toColumn)
+		return denied
+	}
+
+	_, err := Undo(app, by)
+	assert.ErrorIs(t, err, denied)
+	assert.Equal(t, []string{"board1 delete todo "}, calls)
+
+	// Nothing was reverted and the batch can still be undone
+	reload(t, app, task.Id)
+	_, err = Undo(app, me)
+	require.NoError(t, err)
+}
+
+func TestUndo_BatchGroupsOperations(t *testing.T) {
+	app, tasks := setup(t)
+	Begin(NewBatch("setup", "ramtin"))
+	a := newTask(t, app, tasks, "A")
+	b := newTask(t, app, tasks, "B")
+
+	// A TUI-style bulk operation tags its records instead of using Begin
+	Begin(Batch{Actor: "ramtin"})
+	batch := NewBatch("move 2 tasks to done", "ramtin")
+	for _, id := range []string{a.Id, b.Id} {
+		task := reload(t, app, id)
+		task.Set("column", "done")
+		Tag(task, batch)
+		require.NoError(t, app.Save(task))
+	}
+
+	// An untagged save is its own batch
+	single := reload(t, app, a.Id)
+	single.Set("priority", "high")
+	require.NoError(t, app.Save(single))
+
+	summary, err := Undo(app, me)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Operations)
+	assert.Equal(t, "medium", reload(t, app, a.Id).GetString("priority"))
+
+	summary, err = Undo(app, me)
+	require.NoError(t, err)
+	assert.Equal(t, "move 2 tasks to done", summary.Label)
+	assert.Equal(t, 2, summary.Operations)
+	assert.Equal(t, "todo", reload(t, app, a.Id).GetString("column"))
+	assert.Equal(t, "todo", reload(t, app, b.Id).GetString("column"))
+
+	// Other actors have their own stacks
+	_, err = Undo(app, Actor{Name: "claude"})
+	assert.ErrorIs(t, err, ErrNothingToUndo)
+}
+
+func TestUndo_Conflicts(t *testing.T) {
+	app, tasks := setup(t)
+	Begin(NewBatch("setup", "ramtin"))
+	a := newTask(t, app, tasks, "A")
+	b := newTask(t, app, tasks, "B")
+
+	Begin(NewBatch("move 2 tasks", "ramtin"))
+	for _, id := range []string{a.Id, b.Id} {
+		task := reload(t, app, id)
+		task.Set("column", "in_progress")
+		require.NoError(t, app.Save(task))
+	}
+
+	// Someone else moves B on
+	Begin(NewBatch("move B", "claude"))
+	other := reload(t, app, b.Id)
+	other.Set("column", "review")
+	require.NoError(t, app.Save(other))
+
+	_, err := Undo(app, me)
+	var conflictErr *ConflictError
+	require.True(t, errors.As(err, &conflictErr))
+	require.Len(t, conflictErr.Conflicts, 1)
+	assert.Equal(t, b.Id, conflictErr.Conflicts[0].Task)
+	assert.Equal(t, "column", conflictErr.Conflicts[0].Field)
+
+	// Nothing was reverted, and the batch stays on the undo stack
+	assert.Equal(t, "in_progress", reload(t, app, a.Id).GetString("column"))
+	assert.Equal(t, "review", reload(t, app, b.Id).GetString("column"))
+	stack, err := Stack(app, "ramtin", StatusApplied)
+	require.NoError(t, err)
+	assert.Equal(t, "move 2 tasks", stack[0].Label)
+
+	// Deleted tasks conflict too
+	require.NoError(t, app.Delete(reload(t, app, b.Id)))
+	_, err = Undo(app, me)
+	require.True(t, errors.As(err, &conflictErr))
+	assert.Equal(t, "task was deleted", conflictErr.Conflicts[0].Reason)
+}
+
+func TestRecord_NewBatchClearsRedo(t *testing.T) {
+	app, tasks := setup(t)
+	Begin(NewBatch("add", "ramtin"))
+	task := newTask(t, app, tasks, "A")
+
+	Begin(NewBatch("update", "ramtin"))
+	task = reload(t, app, task.Id)
+	task.Set("priority", "high")
+	require.NoError(t, app.Save(task))
+
+	_, err := Undo(app, me)
+	require.NoError(t, err)
+
+	Begin(NewBatch("other update", "ramtin"))
+	task = reload(t, app, task.Id)
+	task.Set("column", "done")
+	require.NoError(t, app.Save(task))
+
+	_, err = Redo(app, me)
+	assert.ErrorIs(t, err, ErrNothingToRedo)
+}
+
+func TestPolicyAction(t *testing.T) {
+	assert.Equal(t, policy.ActionDelete, PolicyAction(KindDelete, "todo", ""))
+	assert.Equal(t, policy.ActionCreate, PolicyAction(KindCreate, "", "todo"))
+	assert.Equal(t, policy.ActionComplete, PolicyAction(KindCreate, "", "done"))
+	assert.Equal(t, policy.ActionComplete, PolicyAction(KindUpdate, "review", "done"))
+	assert.Equal(t, policy.ActionUpdate, PolicyAction(KindUpdate, "todo", "todo"))
+}
@@ -0,0 +1,465 @@
+// Package view reads and writes the saved filter views of the views
+// collection.
+//
+// Views are shared with the web UI, so filters use its JSON format: a list
+// of {id, field, operator, value} objects combined with match_mode "all"
+// (AND) or "any" (OR). Matches evaluates them the way the web UI does
+// (ui/src/hooks/useFilteredTasks.ts), so a view gives the same tasks in the
+// browser, the CLI and the TUI.
+//
+// Filters with the field "query" hold a task query (see package taskquery).
+// The CLI and TUI evaluate them once the view is bound with Bind.
+package view
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/taskquery"
+)
+
+// CollectionName is the name of the views collection.
+const CollectionName = "views"
+
+// Match modes.
+const (
+	MatchAll = "all"
+	MatchAny = "any"
+)
+
+// Filter operators.
+const (
+	OpIs           = "is"
+	OpIsNot        = "is_not"
+	OpIsAnyOf      = "is_any_of"
+	OpIncludesAny  = "includes_any"
+	OpIncludesAll  = "includes_all"
+	OpIncludesNone = "includes_none"
+	OpBefore       = "before"
+	OpAfter        = "after"
+	OpIsSet        = "is_set"
+	OpIsNotSet     = "is_not_set"
+	OpContains     = "contains"
+	OpMatches      = "matches" // value is a task query
+)
+
+// FieldQuery is the field of filters holding a task query.
+const FieldQuery = "query"
+
+// ErrNotFound is returned when no view matches a reference.
+var ErrNotFound = errors.New("view not found")
+
+// Filter is a single filter condition. Value is a string, a list of
+// strings for is_any_of and the includes operators, or nil.
+type Filter struct {
+	ID       string `json:"id"`
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    any    `json:"value"`
+}
+
+// NewFilter returns a filter with a random ID, as the web UI creates them.
+func NewFilter(field, operator string, value any) Filter {
+	return Filter{ID: newID(), Field: field, Operator: operator, Value: value}
+}
+
+// Values returns the filter value as a list.
+func (f Filter) Values() []string {
+	switch v := f.Value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// String describes the filter, e.g. "priority is any of high, urgent".
+func (f Filter) String() string {
+	op := strings.ReplaceAll(f.Operator, "_", " ")
+	if f.Operator == OpIsSet || f.Operator == OpIsNotSet {
+		return f.Field + " " + op
+	}
+	return f.Field + " " + op + " " + strings.Join(f.Values(), ", ")
+}
+
+// Display holds the display options of a view. The CLI and TUI keep them
+// so views round-trip, but only the web UI uses them.
+type Display struct {
+	ViewMode      string   `json:"viewMode"`
+	Density       string   `json:"density"`
+	VisibleFields []string `json:"visibleFields"`
+	GroupBy       *string  `json:"groupBy"`
+}
+
+// DefaultDisplay returns the display options the web UI starts with.
+func DefaultDisplay() Display {
+	groupBy := "column"
+	return Display{
+		ViewMode:      "board",
+		Density:       "comfortable",
+		VisibleFields: []string{"priority", "labels", "due_date"},
+		GroupBy:       &groupBy,
+	}
+}
+
+// View is a saved view.
+type View struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Board      string   `json:"board"`
+	Filters    []Filter `json:"filters"`
+	MatchMode  string   `json:"match_mode"`
+	Display    Display  `json:"display"`
+	IsFavorite bool     `json:"is_favorite"`
+
+	// queries holds the compiled query filters by filter ID
+	queries map[string]*taskquery.Filter
+}
+
+// NewQueryFilter returns a filter holding a task query.
+func NewQueryFilter(query string) Filter {
+	return NewFilter(FieldQuery, OpMatches, query)
+}
+
+// Bind compiles the query filters of the view against its board. Query
+// filters match no task until the view is bound.
+func (v *View) Bind(app core.App) error {
+	v.queries = map[string]*taskquery.Filter{}
+	for _, f := range v.Filters {
+		if f.Field != FieldQuery {
+			continue
+		}
+		values := f.Values()
+		if len(values) == 0 {
+			continue
+		}
+		compiled, err := taskquery.ParseAndCompile(app, values[0], taskquery.Options{BoardID: v.Board})
+		if err != nil {
+			return fmt.Errorf("view %s: %w", v.Name, err)
+		}
+		v.queries[f.ID] = compiled
+	}
+	return nil
+}
+
+// FromRecord reads a view record. The JSON fields may hold the JSON text
+// written by older web UI versions.
+func FromRecord(record *core.Record) (View, error) {
+	v := View{
+		ID:         record.Id,
+		Name:       record.GetString("name"),
+		Board:      record.GetString("board"),
+		MatchMode:  record.GetString("match_mode"),
+		IsFavorite: record.GetBool("is_favorite"),
+		Display:    DefaultDisplay(),
+	}
+	if v.MatchMode == "" {
+		v.MatchMode = MatchAll
+	}
+	if err := decodeField(record, "filters", &v.Filters); err != nil {
+		return v, fmt.Errorf("view %s has invalid filters: %w", v.Name, err)
+	}
+	if err := decodeField(record, "display", &v.Display); err != nil {
+		return v, fmt.Errorf("view %s has invalid display options: %w", v.Name, err)
+	}
+	return v, nil
+}
+
+// ToRecord writes a view to a record.
+func ToRecord(v View, record *core.Record) {
+	if v.Filters == nil {
+		v.Filters = []Filter{}
+	}
+	if v.MatchMode == "" {
+		v.MatchMode = MatchAll
+	}
+	record.Set("name", v.Name)
+	record.Set("board", v.Board)
+	record.Set("filters", v.Filters)
+	record.Set("match_mode", v.MatchMode)
+	record.Set("display", v.Display)
+	record.Set("is_favorite", v.IsFavorite)
+}
+
+// decodeField decodes a JSON field, unwrapping JSON stored as a string.
+func decodeField(record *core.Record, field string, dst any) error {
+	raw, err := json.Marshal(record.Get(field))
+	if err != nil {
+		return err
+	}
+	if string(raw) == "null" || string(raw) == `""` {
+		return nil
+	}
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		raw = []byte(text)
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+// List returns the views of a board, favorites first, then by name.
+func List(app core.App, boardID string) ([]View, error) {
+	records, err := app.FindAllRecords(CollectionName, dbx.HashExp{"board": boardID})
+	if err != nil {
+		return nil, err
+	}
+	views := make([]View, 0, len(records))
+	for _, record := range records {
+		v, err := FromRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		// A view whose query no longer compiles still lists
+		_ = v.Bind(app)
+		views = append(views, v)
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		if views[i].IsFavorite != views[j].IsFavorite {
+			return views[i].IsFavorite
+		}
+		return strings.ToLower(views[i].Name) < strings.ToLower(views[j].Name)
+	})
+	return views, nil
+}
+
+// Find returns the view record of a board with the given ID or name
+// (case-insensitive).
+func Find(app core.App, boardID, ref string) (*core.Record, error) {
+	if record, err := app.FindRecordById(CollectionName, ref); err == nil && record.GetString("board") == boardID {
+		return record, nil
+	}
+	records, err := app.FindAllRecords(CollectionName,
+		dbx.HashExp{"board": boardID},
+		dbx.NewExp("LOWER(name) = {:name}", dbx.Params{"name": strings.ToLower(ref)}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+	return records[0], nil
+}
+
+// Task holds the fields views filter on.
+type Task = taskquery.Task
+
+// TaskFromRecord returns the filter fields of a task record.
+func TaskFromRecord(record *core.Record) Task {
+	return taskquery.TaskFromRecord(record)
+}
+
+// Matches reports whether a task passes the filters of the view.
+func (v View) Matches(t Task) bool {
+	if len(v.Filters) == 0 {
+		return true
+	}
+	if v.MatchMode == MatchAny {
+		for _, f := range v.Filters {
+			if v.matchFilter(t, f) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, f := range v.Filters {
+		if !v.matchFilter(t, f) {
+			return false
+		}
+	}
+	return true
+}
+
+func (v View) matchFilter(t Task, f Filter) bool {
+	if f.Field == FieldQuery {
+		compiled := v.queries[f.ID]
+		return compiled != nil && compiled.Match(t)
+	}
+	return MatchFilter(t, f)
+}
+
+// MatchFilter reports whether a task passes a single filter. Unknown
+// fields and operators match, as in the web UI; query filters are matched
+// by View.Matches.
+func MatchFilter(t Task, f Filter) bool {
+	switch f.Field {
+	case "column":
+		return matchSelect(t.Column, f)
+	case "priority":
+		return matchSelect(t.Priority, f)
+	case "type":
+		return matchSelect(t.Type, f)
+	case "created_by":
+		return matchSelect(t.CreatedBy, f)
+	case "created_by_agent":
+		return matchSelect(t.CreatedByAgent, f)
+	case "epic":
+		return matchSelect(t.Epic, f)
+	case "sprint":
+		return matchSelect(t.Sprint, f)
+	case "parent":
+		return matchSelect(t.Parent, f)
+	case "labels":
+		return matchList(t.Labels, f)
+	case "assignees":
+		return matchList(t.Assignees, f)
+	case "blocked_by":
+		return matchList(t.BlockedBy, f)
+	case "due_date":
+		return matchDate(t.DueDate, f)
+	case "title":
+		return matchText(t.Title, f)
+	case "description":
+		return matchText(t.Description, f)
+	default:
+		return true
+	}
+}
+
+func matchSelect(value string, f Filter) bool {
+	switch f.Operator {
+	case OpIsSet:
+		return value != ""
+	case OpIsNotSet:
+		return value == ""
+	}
+	if value == "" {
+		return false
+	}
+	if f.Value == nil {
+		return true
+	}
+	values := f.Values()
+	switch f.Operator {
+	case OpIs:
+		return len(values) > 0 && value == values[0]
+	case OpIsNot:
+		return len(values) == 0 || value != values[0]
+	case OpIsAnyOf:
+		return contains(values, value)
+	default:
+		return true
+	}
+}
+
+func matchList(items []string, f Filter) bool {
+	switch f.Operator {
+	case OpIsSet:
+		return len(items) > 0
+	case OpIsNotSet:
+		return len(items) == 0
+	}
+	values := f.Values()
+	if len(values) == 0 {
+		return true
+	}
+	switch f.Operator {
+	case OpIncludesAny:
+		for _, v := range values {
+			if contains(items, v) {
+				return true
+			}
+		}
+		return false
+	case OpIncludesAll:
+		for _, v := range values {
+			if !contains(items, v) {
+				return false
+			}
+		}
+		return true
+	case OpIncludesNone:
+		for _, v := range values {
+			if contains(items, v) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+func matchDate(date string, f Filter) bool {
+	switch f.Operator {
+	case OpIsSet:
+		return date != ""
+	case OpIsNotSet:
+		return date == ""
+	}
+	values := f.Values()
+	if date == "" || len(values) == 0 || values[0] == "" {
+		return false
+	}
+	want := values[0]
+	if len(want) > 10 {
+		want = want[:10]
+	}
+	switch f.Operator {
+	case OpIs:
+		return date == want
+	case OpBefore:
+		return date < want
+	case OpAfter:
+		return date > want
+	default:
+		return true
+	}
+}
+
+func matchText(text string, f Filter) bool {
+	values := f.Values()
+	if len(values) == 0 || values[0] == "" {
+		return true
+	}
+	if text == "" {
+		return false
+	}
+	text, want := strings.ToLower(text), strings.ToLower(values[0])
+	switch f.Operator {
+	case OpContains:
+		return strings.Contains(text, want)
+	case OpIs:
+		return text == want
+	case OpIsNot:
+		return text != want
+	default:
+		return true
+	}
+}
+
+func contains(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// newID returns a short random ID like the web UI's filter IDs.
+func newID() string {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, 7)
+	for i := range b {
+		b[i] = chars[rand.Intn(len(chars))]
+	}
+	return string(b)
+}
@@ -0,0 +1,177 @@
+package view
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
+)
+
+func setupViews(t *testing.T) (*pocketbase.PocketBase, *core.Collection) {
+	t.Helper()
+	app := testutil.NewTestApp(t)
+	views := testutil.CreateTestCollection(t, app, CollectionName,
+		&core.TextField{Name: "name"},
+		&core.TextField{Name: "board"},
+		&core.JSONField{Name: "filters"},
+		&core.JSONField{Name: "display"},
+		&core.BoolField{Name: "is_favorite"},
+		&core.TextField{Name: "match_mode"},
+	)
+	return app, views
+}
+
+func saveView(t *testing.T, app *pocketbase.PocketBase, views *core.Collection, v View) *core.Record {
+	t.Helper()
+	record := core.NewRecord(views)
+	ToRecord(v, record)
+	require.NoError(t, app.Save(record))
+	return record
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	app, views := setupViews(t)
+
+	saved := saveView(t, app, views, View{
+		Name:    "Urgent bugs",
+		Board:   "board1",
+		Filters: []Filter{NewFilter("priority", OpIsAnyOf, []string{"urgent", "high"})},
+		Display: DefaultDisplay(),
+	})
+
+	record, err := app.FindRecordById(CollectionName, saved.Id)
+	require.NoError(t, err)
+	v, err := FromRecord(record)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Urgent bugs", v.Name)
+	assert.Equal(t, MatchAll, v.MatchMode)
+	require.Len(t, v.Filters, 1)
+	assert.Len(t, v.Filters[0].ID, 7)
+	assert.Equal(t, []string{"urgent", "high"}, v.Filters[0].Values())
+	assert.Equal(t, "board", v.Display.ViewMode)
+}
+
+func TestFromRecord_StringEncodedJSON(t *testing.T) {
+	_, views := setupViews(t)
+
+	// Older web UI versions stored the filters as JSON text
+	record := core.NewRecord(views)
+	record.Set("name", "Legacy")
+	record.Set("filters", `[{"id":"abc1234","field":"type","operator":"is","value":"bug"}]`)
+	record.Set("match_mode", "any")
+
+	v, err := FromRecord(record)
+	require.NoError(t, err)
+	require.Len(t, v.Filters, 1)
+	assert.Equal(t, "type", v.Filters[0].Field)
+	assert.Equal(t, MatchAny, v.MatchMode)
+}
+
+func TestListAndFind(t *testing.T) {
+	app, views := setupViews(t)
+
+	saveView(t, app, views, View{Name: "beta", Board: "board1"})
+	saveView(t, app, views, View{Name: "Alpha", Board: "board1"})
+	fav := saveView(t, app, views, View{Name: "Zulu", Board: "board1", IsFavorite: true})
+	saveView(t, app, views, View{Name: "Other", Board: "board2"})
+
+	list, err := List(app, "board1")
+	require.NoError(t, err)
+	names := []string{}
+	for _, v := range list {
+		names = append(names, v.Name)
+	}
+	assert.Equal(t, []string{"Zulu", "Alpha", "beta"}, names)
+
+	record, err := Find(app, "board1", "ALPHA")
+	require.NoError(t, err)
+	assert.Equal(t, "Alpha", record.GetString("name"))
+
+	record, err = Find(app, "board1", fav.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "Zulu", record.GetString("name"))
+
+	_, err = Find(app, "board1", "Other")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestMatchFilter(t *testing.T) {
+	task := Task{
+		Column:    "todo",
+		Priority:  "high",
+		Type:      "bug",
+		Title:     "Crash on save",
+		DueDate:   "2026-03-10",
+		Labels:    []string{"backend", "api"},
+		Assignees: []string{"alice"},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"is", Filter{Field: "type", Operator: OpIs, Value: "bug"}, true},
+		{"is other", Filter{Field: "type", Operator: OpIs, Value: "chore"}, false},
+		{"is not", Filter{Field: "priority", Operator: OpIsNot, Value: "low"}, true},
+		{"is any of", Filter{Field: "column", Operator: OpIsAnyOf, Value: []any{"todo", "backlog"}}, true},
+		{"is any of miss", Filter{Field: "column", Operator: OpIsAnyOf, Value: []any{"done"}}, false},
+		{"empty select", Filter{Field: "epic", Operator: OpIs, Value: "e1"}, false},
+		{"select not set", Filter{Field: "epic", Operator: OpIsNotSet}, true},
+		{"includes any", Filter{Field: "labels", Operator: OpIncludesAny, Value: []any{"ui", "api"}}, true},
+		{"includes all", Filter{Field: "labels", Operator: OpIncludesAll, Value: []any{"api", "ui"}}, false},
+		{"includes none", Filter{Field: "labels", Operator: OpIncludesNone, Value: []any{"ui"}}, true},
+		{"assignees", Filter{Field: "assignees", Operator: OpIncludesAny, Value: []any{"alice"}}, true},
+		{"list not set", Filter{Field: "blocked_by", Operator: OpIsNotSet}, true},
+		{"before", Filter{Field: "due_date", Operator: OpBefore, Value: "2026-03-11"}, true},
+		{"before same day", Filter{Field: "due_date", Operator: OpBefore, Value: "2026-03-10"}, false},
+		{"after datetime", Filter{Field: "due_date", Operator: OpAfter, Value: "2026-03-09T00:00:00.000Z"}, true},
+		{"contains", Filter{Field: "title", Operator: OpContains, Value: "CRASH"}, true},
+		{"contains miss", Filter{Field: "title", Operator: OpContains, Value: "login"}, false},
+		{"unknown field", Filter{Field: "estimate", Operator: OpIs, Value: "3"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchFilter(task, tt.filter))
+		})
+	}
+}
+
+func TestMatches_MatchMode(t *testing.T) {
+	task := Task{Type: "bug", Priority: "low"}
+	filters := []Filter{
+		{Field: "type", Operator: OpIs, Value: "bug"},
+		{Field: "priority", Operator: OpIs, Value: "urgent"},
+	}
+
+	assert.False(t, View{Filters: filters, MatchMode: MatchAll}.Matches(task))
+	assert.True(t, View{Filters: filters, MatchMode: MatchAny}.Matches(task))
+	assert.True(t, View{MatchMode: MatchAny}.Matches(task))
+}
+
+func TestMatches_QueryFilter(t *testing.T) {
+	app, _ := setupViews(t)
+	v := View{
+		Name:      "Hot",
+		Filters:   []Filter{NewQueryFilter("priority:high,urgent -label:wontfix")},
+		MatchMode: MatchAll,
+	}
+	task := Task{Priority: "urgent", Labels: []string{"backend"}}
+
+	// Query filters match nothing until the view is bound
+	assert.False(t, v.Matches(task))
+
+	require.NoError(t, v.Bind(app))
+	assert.True(t, v.Matches(task))
+	assert.False(t, v.Matches(Task{Priority: "urgent", Labels: []string{"wontfix"}}))
+
+	v.Filters = []Filter{NewQueryFilter("colour:red")}
+	assert.Error(t, v.Bind(app))
+}
@@ -0,0 +1,111 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Check if collection already exists (idempotency)
+		existing, _ := app.FindCollectionByNameOrId("time_entries")
+		if existing != nil {
+			return nil
+		}
+
+		// Find tasks collection for relation
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return fmt.Errorf("tasks collection not found: %w", err)
+		}
+
+		// Create time_entries collection for tracking time spent on tasks
+		collection := core.NewBaseCollection("time_entries")
+
+		// Task relation (required, cascade delete when task is deleted)
+		collection.Fields.Add(&core.RelationField{
+			Name:          "task",
+			CollectionId:  tasks.Id,
+			MaxSelect:     1,
+			Required:      true,
+			CascadeDelete: true,
+		})
+
+		// Who logged the time (user name, agent name, "cli", "tui")
+		collection.Fields.Add(&core.TextField{
+			Name: "actor",
+			Max:  100,
+		})
+
+		// How the entry was created
+		// - timer: explicit `time start`/`time stop`
+		// - auto: started/stopped by column changes
+		// - manual: logged after the fact with `time log`
+		collection.Fields.Add(&core.SelectField{
+			Name:     "source",
+			Required: true,
+			Values:   []string{"timer", "auto", "manual"},
+		})
+
+		// When the work started (required)
+		collection.Fields.Add(&core.DateField{
+			Name:     "started_at",
+			Required: true,
+		})
+
+		// When the work ended (empty while a timer is running)
+		collection.Fields.Add(&core.DateField{
+			Name: "ended_at",
+		})
+
+		// Duration in seconds (set when the entry is closed)
+		collection.Fields.Add(&core.NumberField{
+			Name:    "duration_seconds",
+			OnlyInt: true,
+		})
+
+		// Optional note describing the work
+		collection.Fields.Add(&core.TextField{
+			Name: "note",
+			Max:  1000,
+		})
+
+		// Auto-timestamp on creation
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "created",
+			OnCreate: true,
+		})
+
+		// Auto-timestamp on update
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "updated",
+			OnCreate: true,
+			OnUpdate: true,
+		})
+
+		// Indexes for common queries
+		collection.Indexes = []string{
+			"CREATE INDEX idx_time_entries_task ON time_entries (task)",
+			"CREATE INDEX idx_time_entries_started_at ON time_entries (started_at)",
+			"CREATE INDEX idx_time_entries_actor ON time_entries (actor)",
+		}
+
+		// API Rules - allow public access (local-first tool, no auth needed)
+		collection.ListRule = func() *string { s := ""; return &s }()
+		collection.ViewRule = func() *string { s := ""; return &s }()
+		collection.CreateRule = func() *string { s := ""; return &s }()
+		collection.UpdateRule = func() *string { s := ""; return &s }()
+		collection.DeleteRule = func() *string { s := ""; return &s }()
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		// Rollback: delete time_entries collection
+		collection, err := app.FindCollectionByNameOrId("time_entries")
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to rollback
+		}
+		return app.Delete(collection)
+	})
+}
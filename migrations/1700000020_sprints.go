@@ -0,0 +1,107 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Check if collection already exists (idempotency)
+		existing, _ := app.FindCollectionByNameOrId("sprints")
+		if existing != nil {
+			return nil
+		}
+
+		// Find boards collection for relation
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return fmt.Errorf("boards collection not found: %w", err)
+		}
+
+		// Create sprints collection for time-boxed iterations
+		collection := core.NewBaseCollection("sprints")
+
+		// Board relation (required, sprints are deleted with their board)
+		collection.Fields.Add(&core.RelationField{
+			Name:          "board",
+			CollectionId:  boards.Id,
+			MaxSelect:     1,
+			Required:      true,
+			CascadeDelete: true,
+		})
+
+		// Sprint name (e.g., "Sprint 12")
+		collection.Fields.Add(&core.TextField{
+			Name:     "name",
+			Required: true,
+			Max:      200,
+		})
+
+		// Planned start and end of the iteration
+		collection.Fields.Add(&core.DateField{
+			Name: "start_date",
+		})
+		collection.Fields.Add(&core.DateField{
+			Name: "end_date",
+		})
+
+		// Optional sprint goal
+		collection.Fields.Add(&core.TextField{
+			Name: "goal",
+			Max:  2000,
+		})
+
+		// Lifecycle state
+		// - planned: created, not started yet
+		// - active: the current sprint (at most one per board)
+		// - closed: finished
+		collection.Fields.Add(&core.SelectField{
+			Name:     "state",
+			Required: true,
+			Values:   []string{"planned", "active", "closed"},
+		})
+
+		// When the sprint was actually closed
+		collection.Fields.Add(&core.DateField{
+			Name: "closed_at",
+		})
+
+		// Auto-timestamp on creation
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "created",
+			OnCreate: true,
+		})
+
+		// Auto-timestamp on update
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "updated",
+			OnCreate: true,
+			OnUpdate: true,
+		})
+
+		// Indexes for common queries
+		collection.Indexes = []string{
+			"CREATE INDEX idx_sprints_board ON sprints (board)",
+			"CREATE INDEX idx_sprints_state ON sprints (state)",
+		}
+
+		// API Rules - allow public access (local-first tool, no auth needed)
+		collection.ListRule = func() *string { s := ""; return &s }()
+		collection.ViewRule = func() *string { s := ""; return &s }()
+		collection.CreateRule = func() *string { s := ""; return &s }()
+		collection.UpdateRule = func() *string { s := ""; return &s }()
+		collection.DeleteRule = func() *string { s := ""; return &s }()
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		// Rollback: delete sprints collection
+		collection, err := app.FindCollectionByNameOrId("sprints")
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to rollback
+		}
+		return app.Delete(collection)
+	})
+}
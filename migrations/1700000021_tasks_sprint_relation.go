@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Find tasks collection
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return err
+		}
+
+		// Skip if field already exists (idempotency)
+		if tasks.Fields.GetByName("sprint") != nil {
+			return nil
+		}
+
+		// Find sprints collection
+		sprints, err := app.FindCollectionByNameOrId("sprints")
+		if err != nil {
+			return err
+		}
+
+		// Add sprint relation field to tasks
+		tasks.Fields.Add(&core.RelationField{
+			Name:          "sprint",
+			CollectionId:  sprints.Id,
+			MaxSelect:     1,
+			CascadeDelete: false, // Tasks remain when sprint is deleted
+		})
+
+		tasks.Indexes = append(tasks.Indexes, "CREATE INDEX idx_tasks_sprint ON tasks (sprint)")
+
+		return app.Save(tasks)
+	}, func(app core.App) error {
+		// Rollback: remove sprint field from tasks
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return err
+		}
+
+		indexes := make([]string, 0, len(tasks.Indexes))
+		for _, idx := range tasks.Indexes {
+			if idx != "CREATE INDEX idx_tasks_sprint ON tasks (sprint)" {
+				indexes = append(indexes, idx)
+			}
+		}
+		tasks.Indexes = indexes
+		tasks.Fields.RemoveByName("sprint")
+
+		return app.Save(tasks)
+	})
+}
@@ -0,0 +1,69 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return err
+		}
+
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return err
+		}
+
+		// Optional size of a task, measured in the board's estimate unit
+		if tasks.Fields.GetByName("estimate") == nil {
+			tasks.Fields.Add(&core.NumberField{
+				Name: "estimate",
+				Min:  floatPtr(0),
+			})
+			if err := app.Save(tasks); err != nil {
+				return err
+			}
+		}
+
+		// Unit used for estimates on a board
+		// - "points": Story points (default when empty)
+		// - "hours": Hours of work
+		if boards.Fields.GetByName("estimate_unit") == nil {
+			boards.Fields.Add(&core.SelectField{
+				Name:     "estimate_unit",
+				Required: false,
+				Values:   []string{"points", "hours"},
+			})
+			if err := app.Save(boards); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		// Rollback: remove estimate fields
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return err
+		}
+		if tasks.Fields.GetByName("estimate") != nil {
+			tasks.Fields.RemoveByName("estimate")
+			if err := app.Save(tasks); err != nil {
+				return err
+			}
+		}
+
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return err
+		}
+		if boards.Fields.GetByName("estimate_unit") != nil {
+			boards.Fields.RemoveByName("estimate_unit")
+			return app.Save(boards)
+		}
+		return nil
+	})
+}
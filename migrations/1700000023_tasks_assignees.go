@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return err
+		}
+
+		// Skip if field already exists (idempotency)
+		if tasks.Fields.GetByName("assignees") != nil {
+			return nil
+		}
+
+		// Assignees - array of people or named agents who own the task
+		tasks.Fields.Add(&core.JSONField{
+			Name:    "assignees",
+			MaxSize: 10000,
+		})
+
+		return app.Save(tasks)
+	}, func(app core.App) error {
+		// Rollback: remove assignees field from tasks
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return err
+		}
+
+		if tasks.Fields.GetByName("assignees") == nil {
+			return nil
+		}
+
+		tasks.Fields.RemoveByName("assignees")
+		return app.Save(tasks)
+	})
+}
@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		users, err := app.FindCollectionByNameOrId("users")
+		if err != nil {
+			return err
+		}
+
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return err
+		}
+
+		// Kind distinguishes people from API tokens
+		// - "user": A human account
+		// - "token": A named API token created with `egenskriven token create`
+		if users.Fields.GetByName("kind") == nil {
+			users.Fields.Add(&core.SelectField{
+				Name:   "kind",
+				Values: []string{"user", "token"},
+			})
+		}
+
+		// Scope limits what the account may do when auth is enabled
+		// - "read": List and view records only
+		// - "write": Full access (default when empty)
+		if users.Fields.GetByName("scope") == nil {
+			users.Fields.Add(&core.SelectField{
+				Name:   "scope",
+				Values: []string{"read", "write"},
+			})
+		}
+
+		// Optional board restriction (empty = all boards)
+		if users.Fields.GetByName("board") == nil {
+			users.Fields.Add(&core.RelationField{
+				Name:          "board",
+				CollectionId:  boards.Id,
+				MaxSelect:     1,
+				CascadeDelete: true, // Tokens for a deleted board are revoked
+			})
+		}
+
+		// Accounts are managed from the CLI; disable public sign-up and
+		// self-service edits, which would let a token change its own scope
+		// or board restriction
+		users.CreateRule = nil
+		users.UpdateRule = nil
+		users.DeleteRule = nil
+
+		return app.Save(users)
+	}, func(app core.App) error {
+		// Rollback: remove token fields and restore the default rules
+		users, err := app.FindCollectionByNameOrId("users")
+		if err != nil {
+			return err
+		}
+		for _, name := range []string{"kind", "scope", "board"} {
+			if users.Fields.GetByName(name) != nil {
+				users.Fields.RemoveByName(name)
+			}
+		}
+		users.CreateRule = func() *string { s := ""; return &s }()
+		users.UpdateRule = func() *string { s := "id = @request.auth.id"; return &s }()
+		users.DeleteRule = func() *string { s := "id = @request.auth.id"; return &s }()
+		return app.Save(users)
+	})
+}
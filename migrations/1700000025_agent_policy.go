@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return err
+		}
+
+		// Agent mode enforced for this board (empty = agent.mode from config)
+		// - "autonomous": Agents may perform any action
+		// - "collaborative": Agents cannot delete or complete tasks
+		// - "supervised": Agents are read-only
+		if boards.Fields.GetByName("agent_mode") == nil {
+			boards.Fields.Add(&core.SelectField{
+				Name:   "agent_mode",
+				Values: []string{"autonomous", "collaborative", "supervised"},
+			})
+			if err := app.Save(boards); err != nil {
+				return err
+			}
+		}
+
+		users, err := app.FindCollectionByNameOrId("users")
+		if err != nil {
+			return err
+		}
+
+		// Agent name for tokens issued to an agent; requests made with the
+		// token are subject to the board's agent mode
+		if users.Fields.GetByName("agent") == nil {
+			users.Fields.Add(&core.TextField{
+				Name: "agent",
+				Max:  100,
+			})
+			if err := app.Save(users); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		// Rollback: remove policy fields
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return err
+		}
+		if boards.Fields.GetByName("agent_mode") != nil {
+			boards.Fields.RemoveByName("agent_mode")
+			if err := app.Save(boards); err != nil {
+				return err
+			}
+		}
+
+		users, err := app.FindCollectionByNameOrId("users")
+		if err != nil {
+			return err
+		}
+		if users.Fields.GetByName("agent") != nil {
+			users.Fields.RemoveByName("agent")
+			return app.Save(users)
+		}
+		return nil
+	})
+}
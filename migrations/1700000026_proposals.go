@@ -0,0 +1,142 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Check if collection already exists (idempotency)
+		existing, _ := app.FindCollectionByNameOrId("proposals")
+		if existing != nil {
+			return nil
+		}
+
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return fmt.Errorf("boards collection not found: %w", err)
+		}
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return fmt.Errorf("tasks collection not found: %w", err)
+		}
+
+		// Create proposals collection for changes agents ask humans to approve
+		collection := core.NewBaseCollection("proposals")
+
+		// Board the change applies to (proposals are deleted with their board)
+		collection.Fields.Add(&core.RelationField{
+			Name:          "board",
+			CollectionId:  boards.Id,
+			MaxSelect:     1,
+			Required:      true,
+			CascadeDelete: true,
+		})
+
+		// Target task; set on approval for new tasks. Cleared, not cascaded,
+		// when the task is deleted so the proposal stays as an audit record.
+		collection.Fields.Add(&core.RelationField{
+			Name:          "task",
+			CollectionId:  tasks.Id,
+			MaxSelect:     1,
+			CascadeDelete: false,
+		})
+
+		// Kind of change
+		collection.Fields.Add(&core.SelectField{
+			Name:     "action",
+			Required: true,
+			Values:   []string{"create", "update", "move", "delete"},
+		})
+
+		// The proposed change as parsed from the command
+		collection.Fields.Add(&core.JSONField{
+			Name:    "changes",
+			MaxSize: 100000,
+		})
+
+		// The command line the agent proposed, for display
+		collection.Fields.Add(&core.TextField{
+			Name: "command",
+			Max:  5000,
+		})
+
+		// Human-readable summary (e.g., "move WRK-1 to done")
+		collection.Fields.Add(&core.TextField{
+			Name: "summary",
+			Max:  500,
+		})
+
+		// Why the agent wants the change
+		collection.Fields.Add(&core.TextField{
+			Name: "rationale",
+			Max:  10000,
+		})
+
+		// Review state
+		// - pending: waiting for a human
+		// - approved: applied
+		// - rejected: discarded
+		collection.Fields.Add(&core.SelectField{
+			Name:     "status",
+			Required: true,
+			Values:   []string{"pending", "approved", "rejected"},
+		})
+
+		// Agent that filed the proposal and human that reviewed it
+		collection.Fields.Add(&core.TextField{
+			Name: "proposed_by",
+			Max:  100,
+		})
+		collection.Fields.Add(&core.TextField{
+			Name: "reviewed_by",
+			Max:  100,
+		})
+		collection.Fields.Add(&core.TextField{
+			Name: "review_note",
+			Max:  10000,
+		})
+		collection.Fields.Add(&core.DateField{
+			Name: "reviewed_at",
+		})
+
+		// Auto-timestamp on creation
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "created",
+			OnCreate: true,
+		})
+
+		// Auto-timestamp on update
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "updated",
+			OnCreate: true,
+			OnUpdate: true,
+		})
+
+		// Indexes for common queries
+		collection.Indexes = []string{
+			"CREATE INDEX idx_proposals_board ON proposals (board)",
+			"CREATE INDEX idx_proposals_status ON proposals (status)",
+		}
+
+		// API Rules - public like the other task collections until auth is
+		// enabled; auth.SyncRules applies the auth rules at serve time
+		collection.ListRule = func() *string { s := ""; return &s }()
+		collection.ViewRule = func() *string { s := ""; return &s }()
+		collection.CreateRule = func() *string { s := ""; return &s }()
+		collection.UpdateRule = func() *string { s := ""; return &s }()
+		collection.DeleteRule = func() *string { s := ""; return &s }()
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		// Rollback: delete proposals collection
+		collection, err := app.FindCollectionByNameOrId("proposals")
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to rollback
+		}
+		return app.Delete(collection)
+	})
+}
@@ -0,0 +1,230 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Check if collection already exists (idempotency)
+		existing, _ := app.FindCollectionByNameOrId("task_events")
+		if existing != nil {
+			return nil
+		}
+
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return fmt.Errorf("boards collection not found: %w", err)
+		}
+
+		// Create task_events collection, the append-only log of task changes
+		collection := core.NewBaseCollection("task_events")
+
+		// Task ID. A plain text field rather than a relation so that events,
+		// including the deletion itself, outlive the task.
+		collection.Fields.Add(&core.TextField{
+			Name:     "task",
+			Required: true,
+			Max:      15,
+		})
+
+		// Board the task belonged to (events are deleted with their board)
+		collection.Fields.Add(&core.RelationField{
+			Name:          "board",
+			CollectionId:  boards.Id,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+
+		// What happened (created, updated, moved, deleted, blocked, ...)
+		collection.Fields.Add(&core.TextField{
+			Name:     "action",
+			Required: true,
+			Max:      50,
+		})
+
+		// Who made the change ("cli", "tui", "user", "agent", "api") and the
+		// specific user or agent name
+		collection.Fields.Add(&core.TextField{
+			Name: "actor",
+			Max:  50,
+		})
+		collection.Fields.Add(&core.TextField{
+			Name: "actor_detail",
+			Max:  100,
+		})
+
+		// Field diffs as {"field": {"from": old, "to": new}}
+		collection.Fields.Add(&core.JSONField{
+			Name:    "changes",
+			MaxSize: 100000,
+		})
+
+		// Extra context (e.g. the proposal an approved change came from)
+		collection.Fields.Add(&core.JSONField{
+			Name:    "metadata",
+			MaxSize: 10000,
+		})
+
+		// When the change happened
+		collection.Fields.Add(&core.DateField{
+			Name:     "timestamp",
+			Required: true,
+		})
+
+		// Indexes for per-task, per-board, and per-actor queries
+		collection.Indexes = []string{
+			"CREATE INDEX idx_task_events_task ON task_events (task, timestamp)",
+			"CREATE INDEX idx_task_events_board ON task_events (board, timestamp)",
+			"CREATE INDEX idx_task_events_actor ON task_events (actor_detail)",
+			"CREATE INDEX idx_task_events_timestamp ON task_events (timestamp)",
+		}
+
+		// API Rules - readable like the other task collections until auth is
+		// enabled (auth.SyncRules applies the auth rules at serve time).
+		// Events are written only by the server's hooks, never through the API.
+		collection.ListRule = func() *string { s := ""; return &s }()
+		collection.ViewRule = func() *string { s := ""; return &s }()
+		collection.CreateRule = nil
+		collection.UpdateRule = nil
+		collection.DeleteRule = nil
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+
+		// Move the history embedded in existing tasks to the event log,
+		// which replaces it
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return nil // No tasks yet
+		}
+		if tasks.Fields.GetByName("history") == nil {
+			return nil
+		}
+
+		records, err := app.FindAllRecords(tasks)
+		if err != nil {
+			return err
+		}
+		for _, task := range records {
+			var history []map[string]any
+			if err := task.UnmarshalJSONField("history", &history); err != nil {
+				continue // Empty or malformed history
+			}
+			for _, entry := range history {
+				if err := app.Save(historyEvent(collection, task, entry)); err != nil {
+					return fmt.Errorf("failed to backfill events for task %s: %w", task.Id, err)
+				}
+			}
+		}
+
+		tasks.Fields.RemoveByName("history")
+		return app.Save(tasks)
+	}, func(app core.App) error {
+		// Rollback: restore the history field from the events, then delete
+		// the task_events collection
+		collection, err := app.FindCollectionByNameOrId("task_events")
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to rollback
+		}
+
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err == nil && tasks.Fields.GetByName("history") == nil {
+			tasks.Fields.Add(&core.JSONField{
+				Name:    "history",
+				MaxSize: 100000,
+			})
+			if err := app.Save(tasks); err != nil {
+				return err
+			}
+
+			var events []*core.Record
+			err := app.RecordQuery(collection).OrderBy("timestamp ASC", "rowid ASC").All(&events)
+			if err != nil {
+				return err
+			}
+			history := make(map[string][]map[string]any)
+			for _, event := range events {
+				history[event.GetString("task")] = append(history[event.GetString("task")], historyEntry(event))
+			}
+			for taskID, entries := range history {
+				data, err := json.Marshal(entries)
+				if err != nil {
+					return err
+				}
+				// Written directly so that no task hook logs the change
+				_, err = app.DB().Update("tasks", dbx.Params{"history": string(data)}, dbx.HashExp{"id": taskID}).Execute()
+				if err != nil {
+					return fmt.Errorf("failed to restore history of task %s: %w", taskID, err)
+				}
+			}
+		}
+
+		return app.Delete(collection)
+	})
+}
+
+// historyEvent converts an entry of a task's embedded history to a
+// task_events record. Keys other than the standard ones (e.g. "proposal")
+// become metadata.
+func historyEvent(collection *core.Collection, task *core.Record, entry map[string]any) *core.Record {
+	event := core.NewRecord(collection)
+	event.Set("task", task.Id)
+	event.Set("board", task.GetString("board"))
+	event.Set("action", "updated")
+	event.Set("actor", "user")
+	event.Set("timestamp", task.GetDateTime("created"))
+
+	metadata := make(map[string]any)
+	for key, value := range entry {
+		switch key {
+		case "timestamp":
+			if s, ok := value.(string); ok {
+				if t, err := time.Parse(time.RFC3339, s); err == nil {
+					event.Set("timestamp", t.UTC())
+				}
+			}
+		case "action", "actor":
+			if s, ok := value.(string); ok && s != "" {
+				event.Set(key, s)
+			}
+		case "actor_detail", "changes":
+			event.Set(key, value)
+		default:
+			metadata[key] = value
+		}
+	}
+	if len(metadata) > 0 {
+		event.Set("metadata", metadata)
+	}
+	return event
+}
+
+// historyEntry converts a task_events record back to an entry of the
+// embedded history, for rollback.
+func historyEntry(event *core.Record) map[string]any {
+	entry := map[string]any{
+		"timestamp":    event.GetDateTime("timestamp").Time().UTC().Format(time.RFC3339),
+		"action":       event.GetString("action"),
+		"actor":        event.GetString("actor"),
+		"actor_detail": event.GetString("actor_detail"),
+	}
+	var changes any
+	if err := event.UnmarshalJSONField("changes", &changes); err == nil {
+		entry["changes"] = changes
+	}
+	var metadata map[string]any
+	if err := event.UnmarshalJSONField("metadata", &metadata); err == nil {
+		for key, value := range metadata {
+			entry[key] = value
+		}
+	}
+	return entry
+}
@@ -0,0 +1,128 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Check if collection already exists (idempotency)
+		existing, _ := app.FindCollectionByNameOrId("operations")
+		if existing != nil {
+			return nil
+		}
+
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return fmt.Errorf("boards collection not found: %w", err)
+		}
+
+		// Create operations collection, the undo/redo log of task mutations
+		collection := core.NewBaseCollection("operations")
+
+		// Batch the operation belongs to (one CLI command or TUI action)
+		collection.Fields.Add(&core.TextField{
+			Name:     "batch",
+			Required: true,
+			Max:      50,
+		})
+
+		// What the batch did (e.g. "move WRK-12 done")
+		collection.Fields.Add(&core.TextField{
+			Name: "label",
+			Max:  500,
+		})
+
+		// User or agent whose undo stack the operation is on
+		collection.Fields.Add(&core.TextField{
+			Name: "actor",
+			Max:  100,
+		})
+
+		// Operation kind: create, update, delete
+		collection.Fields.Add(&core.SelectField{
+			Name:      "kind",
+			Values:    []string{"create", "update", "delete"},
+			MaxSelect: 1,
+			Required:  true,
+		})
+
+		// Task ID. A plain text field so that deletions can be undone.
+		collection.Fields.Add(&core.TextField{
+			Name:     "task",
+			Required: true,
+			Max:      15,
+		})
+
+		// Board the task belonged to (operations are deleted with their board)
+		collection.Fields.Add(&core.RelationField{
+			Name:          "board",
+			CollectionId:  boards.Id,
+			MaxSelect:     1,
+			CascadeDelete: true,
+		})
+
+		// Task snapshots before and after the operation (null when the task
+		// did not exist)
+		collection.Fields.Add(&core.JSONField{
+			Name:    "before",
+			MaxSize: 1000000,
+		})
+		collection.Fields.Add(&core.JSONField{
+			Name:    "after",
+			MaxSize: 1000000,
+		})
+
+		// applied (on the undo stack) or undone (on the redo stack)
+		collection.Fields.Add(&core.SelectField{
+			Name:      "status",
+			Values:    []string{"applied", "undone"},
+			MaxSelect: 1,
+			Required:  true,
+		})
+
+		// Ordering of operations across processes
+		collection.Fields.Add(&core.NumberField{
+			Name:    "seq",
+			OnlyInt: true,
+		})
+
+		// When the batch was last undone or redone
+		collection.Fields.Add(&core.DateField{
+			Name: "undone_at",
+		})
+
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "created",
+			OnCreate: true,
+		})
+
+		// Indexes for stack lookups
+		collection.Indexes = []string{
+			"CREATE INDEX idx_operations_actor ON operations (actor, status, seq)",
+			"CREATE INDEX idx_operations_batch ON operations (batch)",
+		}
+
+		// API Rules - readable like the other task collections until auth is
+		// enabled (auth.SyncRules applies the auth rules at serve time).
+		// Operations are written only by the server's hooks, never through
+		// the API, so nobody can forge an entry in another actor's undo stack.
+		collection.ListRule = func() *string { s := ""; return &s }()
+		collection.ViewRule = func() *string { s := ""; return &s }()
+		collection.CreateRule = nil
+		collection.UpdateRule = nil
+		collection.DeleteRule = nil
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		// Rollback: delete operations collection
+		collection, err := app.FindCollectionByNameOrId("operations")
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to rollback
+		}
+		return app.Delete(collection)
+	})
+}
@@ -0,0 +1,83 @@
+package migrations
+
+import (
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// trashCollections are the collections whose records go to the trash
+// instead of being deleted.
+var trashCollections = []string{"tasks", "epics", "boards"}
+
+func init() {
+	m.Register(func(app core.App) error {
+		for _, name := range trashCollections {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				return err
+			}
+
+			// Skip if fields already exist (idempotency)
+			if collection.Fields.GetByName("deleted_at") != nil {
+				continue
+			}
+
+			// When the record was moved to the trash (empty if not trashed)
+			collection.Fields.Add(&core.DateField{
+				Name: "deleted_at",
+			})
+
+			// User or agent who trashed the record
+			collection.Fields.Add(&core.TextField{
+				Name: "deleted_by",
+				Max:  100,
+			})
+
+			// Board whose deletion trashed the record, so that restoring the
+			// board restores it too
+			if name != "boards" {
+				collection.Fields.Add(&core.TextField{
+					Name: "deleted_with",
+					Max:  15,
+				})
+			}
+
+			// Index for the trash filter used by every list query
+			collection.Indexes = append(collection.Indexes,
+				"CREATE INDEX idx_"+name+"_deleted_at ON "+name+" (deleted_at)")
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(app core.App) error {
+		// Rollback: remove trash fields
+		for _, name := range trashCollections {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				continue
+			}
+			if collection.Fields.GetByName("deleted_at") == nil {
+				continue
+			}
+
+			var indexes []string
+			for _, idx := range collection.Indexes {
+				if !strings.Contains(idx, "idx_"+name+"_deleted_at") {
+					indexes = append(indexes, idx)
+				}
+			}
+			collection.Indexes = indexes
+			collection.Fields.RemoveByName("deleted_at")
+			collection.Fields.RemoveByName("deleted_by")
+			collection.Fields.RemoveByName("deleted_with")
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return err
+		}
+
+		// Skip if fields already exist (idempotency)
+		if tasks.Fields.GetByName("archived_at") == nil {
+			// When the task was archived (empty if not archived). Archived
+			// tasks keep their column but are hidden from lists by default.
+			tasks.Fields.Add(&core.DateField{
+				Name: "archived_at",
+			})
+
+			// User, agent, or "auto-archive" that archived the task
+			tasks.Fields.Add(&core.TextField{
+				Name: "archived_by",
+				Max:  100,
+			})
+
+			// Index for the archive filter used by list queries
+			tasks.Indexes = append(tasks.Indexes,
+				"CREATE INDEX idx_tasks_archived_at ON tasks (archived_at)")
+
+			if err := app.Save(tasks); err != nil {
+				return err
+			}
+		}
+
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return err
+		}
+
+		// Days after which done tasks are archived automatically (0 = never)
+		if boards.Fields.GetByName("auto_archive_days") == nil {
+			boards.Fields.Add(&core.NumberField{
+				Name:    "auto_archive_days",
+				OnlyInt: true,
+				Min:     floatPtr(0),
+			})
+			if err := app.Save(boards); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func(app core.App) error {
+		// Rollback: remove archive fields
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return err
+		}
+		if tasks.Fields.GetByName("archived_at") != nil {
+			var indexes []string
+			for _, idx := range tasks.Indexes {
+				if !strings.Contains(idx, "idx_tasks_archived_at") {
+					indexes = append(indexes, idx)
+				}
+			}
+			tasks.Indexes = indexes
+			tasks.Fields.RemoveByName("archived_at")
+			tasks.Fields.RemoveByName("archived_by")
+			if err := app.Save(tasks); err != nil {
+				return err
+			}
+		}
+
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return err
+		}
+		if boards.Fields.GetByName("auto_archive_days") != nil {
+			boards.Fields.RemoveByName("auto_archive_days")
+			return app.Save(boards)
+		}
+		return nil
+	})
+}
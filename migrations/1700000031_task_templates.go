@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Check if collection already exists (idempotency)
+		existing, _ := app.FindCollectionByNameOrId("task_templates")
+		if existing != nil {
+			return nil
+		}
+
+		boards, err := app.FindCollectionByNameOrId("boards")
+		if err != nil {
+			return fmt.Errorf("boards collection not found: %w", err)
+		}
+
+		// Create task_templates collection for reusable task skeletons.
+		// Templates can also live in .egenskriven/templates/ as files.
+		collection := core.NewBaseCollection("task_templates")
+
+		// Board the template belongs to (templates are deleted with their board)
+		collection.Fields.Add(&core.RelationField{
+			Name:          "board",
+			CollectionId:  boards.Id,
+			MaxSelect:     1,
+			Required:      true,
+			CascadeDelete: true,
+		})
+
+		// Name used with `add --template <name>`, unique per board
+		collection.Fields.Add(&core.TextField{
+			Name:     "name",
+			Required: true,
+			Max:      100,
+		})
+
+		// What the template is for, shown in `template list`
+		collection.Fields.Add(&core.TextField{
+			Name: "summary",
+			Max:  500,
+		})
+
+		// Variable defaults, the parent task, and its subtasks
+		collection.Fields.Add(&core.JSONField{
+			Name:    "definition",
+			MaxSize: 100000,
+		})
+
+		// Auto-timestamp on creation
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "created",
+			OnCreate: true,
+		})
+
+		// Auto-timestamp on update
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "updated",
+			OnCreate: true,
+			OnUpdate: true,
+		})
+
+		collection.Indexes = []string{
+			"CREATE UNIQUE INDEX idx_task_templates_board_name ON task_templates (board, name)",
+		}
+
+		// API Rules - public like the other task collections until auth is
+		// enabled; auth.SyncRules applies the auth rules at serve time
+		collection.ListRule = func() *string { s := ""; return &s }()
+		collection.ViewRule = func() *string { s := ""; return &s }()
+		collection.CreateRule = func() *string { s := ""; return &s }()
+		collection.UpdateRule = func() *string { s := ""; return &s }()
+		collection.DeleteRule = func() *string { s := ""; return &s }()
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		// Rollback: delete task_templates collection
+		collection, err := app.FindCollectionByNameOrId("task_templates")
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to rollback
+		}
+		return app.Delete(collection)
+	})
+}
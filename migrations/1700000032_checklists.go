@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/checklist"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return err
+		}
+
+		// Skip if field already exists (idempotency)
+		if tasks.Fields.GetByName("checklist") != nil {
+			return nil
+		}
+
+		// Checklist items: [{"text", "done", "done_by", "done_at"}], mirrored
+		// as markdown checkboxes in the description
+		tasks.Fields.Add(&core.JSONField{
+			Name:    "checklist",
+			MaxSize: 100000,
+		})
+		if err := app.Save(tasks); err != nil {
+			return err
+		}
+
+		// Backfill checklists from the checkboxes of existing descriptions.
+		// Written directly so the backfill is not recorded as task changes.
+		records, err := app.FindAllRecords("tasks", dbx.NewExp("description LIKE '%[%]%'"))
+		if err != nil {
+			return nil // No tasks yet
+		}
+		for _, task := range records {
+			items := checklist.Parse(task.GetString("description"))
+			if len(items) == 0 {
+				continue
+			}
+			data, err := json.Marshal(items)
+			if err != nil {
+				return err
+			}
+			_, err = app.DB().Update("tasks",
+				dbx.Params{"checklist": string(data)},
+				dbx.HashExp{"id": task.Id},
+			).Execute()
+			if err != nil {
+				return fmt.Errorf("failed to backfill checklist of task %s: %w", task.Id, err)
+			}
+		}
+		return nil
+	}, func(app core.App) error {
+		// Rollback: remove checklist field
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return err
+		}
+		if tasks.Fields.GetByName("checklist") == nil {
+			return nil
+		}
+		tasks.Fields.RemoveByName("checklist")
+		return app.Save(tasks)
+	})
+}
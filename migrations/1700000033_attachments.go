@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+// attachmentCollections are the collections that can hold attachments.
+var attachmentCollections = []string{"tasks", "comments"}
+
+func init() {
+	m.Register(func(app core.App) error {
+		for _, name := range attachmentCollections {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				return err
+			}
+
+			// Skip if field already exists (idempotency)
+			if collection.Fields.GetByName("attachments") != nil {
+				continue
+			}
+
+			// Attached files. The size limits that apply are configured
+			// in the attachments section of the project config; MaxSize
+			// is only a hard ceiling.
+			collection.Fields.Add(&core.FileField{
+				Name:      "attachments",
+				MaxSelect: 100,
+				MaxSize:   1 << 30, // 1 GB
+			})
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(app core.App) error {
+		// Rollback: remove attachments fields
+		for _, name := range attachmentCollections {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				return err
+			}
+			if collection.Fields.GetByName("attachments") == nil {
+				continue
+			}
+			collection.Fields.RemoveByName("attachments")
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
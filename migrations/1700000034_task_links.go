@@ -0,0 +1,99 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		// Check if collection already exists (idempotency)
+		existing, _ := app.FindCollectionByNameOrId("task_links")
+		if existing != nil {
+			return nil
+		}
+
+		tasks, err := app.FindCollectionByNameOrId("tasks")
+		if err != nil {
+			return fmt.Errorf("tasks collection not found: %w", err)
+		}
+
+		// Create task_links collection for typed relations between tasks
+		// ("relates to", "duplicates", "caused by", "follow-up of").
+		collection := core.NewBaseCollection("task_links")
+
+		// Task the link was made from (links are deleted with either task)
+		collection.Fields.Add(&core.RelationField{
+			Name:          "source",
+			CollectionId:  tasks.Id,
+			MaxSelect:     1,
+			Required:      true,
+			CascadeDelete: true,
+		})
+
+		// Task the link points to
+		collection.Fields.Add(&core.RelationField{
+			Name:          "target",
+			CollectionId:  tasks.Id,
+			MaxSelect:     1,
+			Required:      true,
+			CascadeDelete: true,
+		})
+
+		// Relation type, read as "<source> <type> <target>"
+		collection.Fields.Add(&core.SelectField{
+			Name:      "type",
+			Values:    []string{"relates", "duplicates", "caused_by", "follow_up_of"},
+			MaxSelect: 1,
+			Required:  true,
+		})
+
+		// One-way links are only shown on the source task
+		collection.Fields.Add(&core.BoolField{
+			Name: "one_way",
+		})
+
+		// Who created the link
+		collection.Fields.Add(&core.TextField{
+			Name: "created_by",
+			Max:  100,
+		})
+
+		// Auto-timestamp on creation
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "created",
+			OnCreate: true,
+		})
+
+		// Auto-timestamp on update
+		collection.Fields.Add(&core.AutodateField{
+			Name:     "updated",
+			OnCreate: true,
+			OnUpdate: true,
+		})
+
+		collection.Indexes = []string{
+			"CREATE UNIQUE INDEX idx_task_links_unique ON task_links (source, target, type)",
+			"CREATE INDEX idx_task_links_target ON task_links (target)",
+		}
+
+		// API Rules - public like the other task collections until auth is
+		// enabled; auth.SyncRules applies the auth rules at serve time
+		collection.ListRule = func() *string { s := ""; return &s }()
+		collection.ViewRule = func() *string { s := ""; return &s }()
+		collection.CreateRule = func() *string { s := ""; return &s }()
+		collection.UpdateRule = func() *string { s := ""; return &s }()
+		collection.DeleteRule = func() *string { s := ""; return &s }()
+
+		return app.Save(collection)
+	}, func(app core.App) error {
+		// Rollback: delete task_links collection
+		collection, err := app.FindCollectionByNameOrId("task_links")
+		if err != nil {
+			return nil // Collection doesn't exist, nothing to rollback
+		}
+		return app.Delete(collection)
+	})
+}
@@ -0,0 +1,99 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/auth"
+	_ "github.com/ramtinJ95/EgenSkriven/migrations"
+)
+
+// TestAuthE2E_TokenCannotEditItself verifies that an API token cannot
+// widen its own scope or board restriction, or delete its account, through
+// the users collection API.
+func TestAuthE2E_TokenCannotEditItself(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	app := setupE2EMigratedApp(t)
+	if err := auth.ApplyRules(app, true); err != nil {
+		t.Fatalf("failed to enable auth: %v", err)
+	}
+
+	record, token, err := auth.CreateToken(app, "dashboard", auth.ScopeRead, "", 0)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	target := "/api/collections/users/records/" + record.Id
+	for _, method := range []string{http.MethodPatch, http.MethodDelete} {
+		rec := serveE2E(t, app, method, target, token, `{"scope": "write"}`)
+		if rec.Code != http.StatusForbidden && rec.Code != http.StatusNotFound {
+			t.Errorf("%s %s: expected 403 or 404, got %d: %s", method, target, rec.Code, rec.Body.String())
+		}
+	}
+
+	refreshed, err := app.FindRecordById(auth.UsersCollection, record.Id)
+	if err != nil {
+		t.Fatalf("token account was deleted: %v", err)
+	}
+	if scope := refreshed.GetString("scope"); scope != auth.ScopeRead {
+		t.Errorf("expected scope %q, got %q", auth.ScopeRead, scope)
+	}
+}
+
+// setupE2EMigratedApp creates a test app with the app migrations applied,
+// as on a real server.
+func setupE2EMigratedApp(t *testing.T) *pocketbase.PocketBase {
+	t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "egenskriven-e2e-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	app := pocketbase.NewWithConfig(pocketbase.Config{
+		DefaultDataDir: tmpDir,
+	})
+	if err := app.Bootstrap(); err != nil {
+		t.Fatalf("failed to bootstrap app: %v", err)
+	}
+	if err := app.RunAppMigrations(); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	return app
+}
+
+// serveE2E sends an API request through the app's router, authenticated
+// with token unless it is empty.
+func serveE2E(t *testing.T, app *pocketbase.PocketBase, method, target, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r, err := apis.NewRouter(app)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	mux, err := r.BuildMux()
+	if err != nil {
+		t.Fatalf("failed to build router: %v", err)
+	}
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
@@ -3,8 +3,6 @@
 package e2e
 
 import (
-	"encoding/json"
-	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -13,12 +11,14 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 
 	"github.com/ramtinJ95/EgenSkriven/internal/autoresume"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
 )
 
 // TestAutoResumeE2E_FullWorkflow tests the complete auto-resume workflow.
 // This is an integration test that verifies:
 // 1. Task moves to in_progress after auto-resume trigger
-// 2. History contains auto_resumed action entry
+// 2. The task event log contains an auto_resumed event
 // 3. All conditions must be met for trigger
 func TestAutoResumeE2E_FullWorkflow(t *testing.T) {
 	if testing.Short() {
@@ -63,31 +63,21 @@ func TestAutoResumeE2E_FullWorkflow(t *testing.T) {
 		t.Errorf("task should be in_progress after auto-resume, got %s", refreshedTask.GetString("column"))
 	}
 
-	// 6. Verify history contains auto_resumed entry
-	history := refreshedTask.Get("history")
-	historySlice, err := getHistorySlice(history)
-	if err != nil {
-		t.Fatalf("failed to parse history: %v", err)
-	}
-
-	if len(historySlice) == 0 {
-		t.Fatal("history should contain at least one entry")
-	}
-
-	lastEntry := historySlice[len(historySlice)-1]
+	// 6. Verify the event log contains the auto_resumed event
+	lastEvent := lastE2EEvent(t, app, task.Id)
 
-	if lastEntry["action"] != "auto_resumed" {
-		t.Errorf("last history entry should have action 'auto_resumed', got %v", lastEntry["action"])
+	if lastEvent.Action != "auto_resumed" {
+		t.Errorf("last event should have action 'auto_resumed', got %v", lastEvent.Action)
 	}
 
-	if lastEntry["actor"] != "system" {
-		t.Errorf("last history entry should have actor 'system', got %v", lastEntry["actor"])
+	if lastEvent.Actor != "system" {
+		t.Errorf("last event should have actor 'system', got %v", lastEvent.Actor)
 	}
 
-	// Verify changes in history
-	changes, ok := lastEntry["changes"].(map[string]any)
+	// Verify changes in the event
+	changes, ok := lastEvent.Changes.(map[string]any)
 	if !ok {
-		t.Fatal("history entry should have changes map")
+		t.Fatal("event should have changes map")
 	}
 
 	columnChange, ok := changes["column"].(map[string]any)
@@ -104,13 +94,8 @@ func TestAutoResumeE2E_FullWorkflow(t *testing.T) {
 	}
 
 	// Verify trigger comment is recorded in metadata
-	metadata, ok := lastEntry["metadata"].(map[string]any)
-	if !ok {
-		t.Fatal("history entry should have metadata")
-	}
-
-	if metadata["trigger_comment"] != comment.Id {
-		t.Errorf("metadata should reference trigger comment, got %v", metadata["trigger_comment"])
+	if lastEvent.Metadata["trigger_comment"] != comment.Id {
+		t.Errorf("metadata should reference trigger comment, got %v", lastEvent.Metadata["trigger_comment"])
 	}
 }
 
@@ -143,8 +128,8 @@ func TestAutoResumeE2E_TaskInProgressAfterTrigger(t *testing.T) {
 	}
 }
 
-// TestAutoResumeE2E_HistoryContainsAutoResumedAction verifies history entry.
-func TestAutoResumeE2E_HistoryContainsAutoResumedAction(t *testing.T) {
+// TestAutoResumeE2E_EventLogContainsAutoResumedAction verifies the logged event.
+func TestAutoResumeE2E_EventLogContainsAutoResumedAction(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping e2e test in short mode")
 	}
@@ -165,18 +150,10 @@ func TestAutoResumeE2E_HistoryContainsAutoResumedAction(t *testing.T) {
 	service := autoresume.NewService(app)
 	service.CheckAndResume(comment)
 
-	// Verify history has auto_resumed action
-	refreshedTask, _ := app.FindRecordById("tasks", task.Id)
-	history := refreshedTask.Get("history")
-
-	historySlice, err := getHistorySlice(history)
-	if err != nil || len(historySlice) == 0 {
-		t.Fatalf("expected non-empty history, err: %v", err)
-	}
-
-	lastEntry := historySlice[len(historySlice)-1]
-	if lastEntry["action"] != "auto_resumed" {
-		t.Errorf("expected 'auto_resumed' action, got '%v'", lastEntry["action"])
+	// Verify the event log has the auto_resumed action
+	lastEvent := lastE2EEvent(t, app, task.Id)
+	if lastEvent.Action != "auto_resumed" {
+		t.Errorf("expected 'auto_resumed' action, got '%v'", lastEvent.Action)
 	}
 }
 
@@ -264,8 +241,9 @@ func setupE2ETestApp(t *testing.T) *pocketbase.PocketBase {
 		t.Fatalf("failed to bootstrap app: %v", err)
 	}
 
-	// Create required collections
+	// Create required collections and log task events
 	setupE2ECollections(t, app)
+	taskevent.Bind(app)
 
 	return app
 }
@@ -294,13 +272,17 @@ func setupE2ECollections(t *testing.T, app *pocketbase.PocketBase) {
 		tasks.Fields.Add(&core.TextField{Name: "column"})
 		tasks.Fields.Add(&core.TextField{Name: "board"})
 		tasks.Fields.Add(&core.JSONField{Name: "agent_session"})
-		tasks.Fields.Add(&core.JSONField{Name: "history"})
 		tasks.Fields.Add(&core.NumberField{Name: "seq"})
 		if err := app.Save(tasks); err != nil {
 			t.Fatalf("failed to create tasks collection: %v", err)
 		}
 	}
 
+	// Task events collection
+	if _, err := app.FindCollectionByNameOrId(taskevent.CollectionName); err != nil {
+		testutil.CreateTaskEventsCollection(t, app)
+	}
+
 	// Comments collection
 	if _, err := app.FindCollectionByNameOrId("comments"); err != nil {
 		comments := core.NewBaseCollection("comments")
@@ -353,7 +335,6 @@ func createE2ETask(t *testing.T, app *pocketbase.PocketBase, boardId, title, col
 	record.Set("board", boardId)
 	record.Set("column", column)
 	record.Set("seq", 1)
-	record.Set("history", []any{})
 
 	if err := app.Save(record); err != nil {
 		t.Fatalf("failed to create task: %v", err)
@@ -387,30 +368,18 @@ func createE2EComment(t *testing.T, app *pocketbase.PocketBase, taskId, content,
 	return record
 }
 
-// getHistorySlice parses history field handling types.JSONRaw.
-func getHistorySlice(history any) ([]map[string]any, error) {
-	if history == nil {
-		return nil, fmt.Errorf("history is nil")
-	}
+// lastE2EEvent returns the most recent logged event of a task.
+func lastE2EEvent(t *testing.T, app *pocketbase.PocketBase, taskId string) taskevent.Event {
+	t.Helper()
 
-	// Handle []any (in-memory)
-	if slice, ok := history.([]any); ok {
-		result := make([]map[string]any, 0, len(slice))
-		for _, item := range slice {
-			if m, ok := item.(map[string]any); ok {
-				result = append(result, m)
-			}
-		}
-		return result, nil
+	events, err := taskevent.ForTask(app, taskId)
+	if err != nil {
+		t.Fatalf("failed to query task events: %v", err)
 	}
-
-	// Handle types.JSONRaw (from DB) - parse as JSON
-	raw := []byte(fmt.Sprintf("%s", history))
-	var result []map[string]any
-	if err := json.Unmarshal(raw, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal history: %w", err)
+	if len(events) == 0 {
+		t.Fatal("task should have at least one event")
 	}
-	return result, nil
+	return events[len(events)-1]
 }
 
 // extractE2EMentions extracts @mentions from content.
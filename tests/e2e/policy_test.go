@@ -0,0 +1,352 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/ramtinJ95/EgenSkriven/internal/auth"
+	"github.com/ramtinJ95/EgenSkriven/internal/hooks"
+	"github.com/ramtinJ95/EgenSkriven/internal/policy"
+	"github.com/ramtinJ95/EgenSkriven/internal/undo"
+)
+
+// TestPolicyE2E_AgentCannotChangeAgentMode verifies that an agent cannot
+// lift the policy of its board through the API, while humans can.
+func TestPolicyE2E_AgentCannotChangeAgentMode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	app := setupE2EMigratedApp(t)
+	hooks.RegisterPolicyHooks(app)
+
+	boards, err := app.FindCollectionByNameOrId("boards")
+	if err != nil {
+		t.Fatalf("boards collection not found: %v", err)
+	}
+	board := core.NewRecord(boards)
+	board.Set("name", "Work")
+	board.Set("prefix", "WRK")
+	board.Set("agent_mode", policy.ModeSupervised)
+	if err := app.Save(board); err != nil {
+		t.Fatalf("failed to create board: %v", err)
+	}
+
+	record, token, err := auth.CreateToken(app, "bot", auth.ScopeWrite, "", 0)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	record.Set("agent", "claude")
+	if err := app.Save(record); err != nil {
+		t.Fatalf("failed to set token agent: %v", err)
+	}
+
+	target := "/api/collections/boards/records/" + board.Id
+	rec := serveE2E(t, app, http.MethodPatch, target, token, `{"agent_mode": "autonomous"}`)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("agent changing agent_mode: expected 403, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	refreshed, err := app.FindRecordById("boards", board.Id)
+	if err != nil {
+		t.Fatalf("failed to find board: %v", err)
+	}
+	if mode := refreshed.GetString("agent_mode"); mode != policy.ModeSupervised {
+		t.Errorf("expected agent_mode %q, got %q", policy.ModeSupervised, mode)
+	}
+
+	// Humans are not restricted
+	rec = serveE2E(t, app, http.MethodPatch, target, "", `{"agent_mode": "autonomous"}`)
+	if rec.Code != http.StatusOK {
+		t.Errorf("human changing agent_mode: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestPolicyE2E_AgentWritesFollowBoardMode verifies that every API write
+// to a record of a supervised board is denied to agents, whichever
+// collection the record is in, while humans are not restricted.
+func TestPolicyE2E_AgentWritesFollowBoardMode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	app := setupE2EMigratedApp(t)
+	hooks.RegisterPolicyHooks(app)
+
+	board := createE2ERecord(t, app, "boards", map[string]any{
+		"name":       "Work",
+		"prefix":     "WRK",
+		"agent_mode": policy.ModeSupervised,
+	})
+	task := createE2ERecord(t, app, "tasks", map[string]any{
+		"title":      "Fix login",
+		"board":      board.Id,
+		"type":       "bug",
+		"priority":   "medium",
+		"column":     "todo",
+		"position":   1000,
+		"created_by": "user",
+	})
+	other := createE2ERecord(t, app, "tasks", map[string]any{
+		"title":      "Write docs",
+		"board":      board.Id,
+		"type":       "chore",
+		"priority":   "low",
+		"column":     "todo",
+		"position":   2000,
+		"created_by": "user",
+	})
+
+	record, token, err := auth.CreateToken(app, "bot", auth.ScopeWrite, "", 0)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	record.Set("agent", "claude")
+	if err := app.Save(record); err != nil {
+		t.Fatalf("failed to set token agent: %v", err)
+	}
+
+	tests := []struct {
+		collection string
+		fields     map[string]any
+	}{
+		{"epics", map[string]any{"title": "Auth", "board": board.Id}},
+		{"sprints", map[string]any{"name": "Sprint 1", "board": board.Id, "state": "planned"}},
+		{"views", map[string]any{"name": "Urgent", "board": board.Id, "match_mode": "all"}},
+		{"task_templates", map[string]any{"name": "bug-report", "board": board.Id}},
+		{"comments", map[string]any{"task": task.Id, "content": "Looks good", "author_type": "human"}},
+		{"sessions", map[string]any{
+			"task": task.Id, "tool": "claude-code", "external_ref": "abc-123",
+			"ref_type": "uuid", "working_dir": "/tmp", "status": "active",
+		}},
+		{"time_entries", map[string]any{
+			"task": task.Id, "source": "manual", "started_at": "2026-01-05 09:00:00.000Z",
+		}},
+		{"task_links", map[string]any{"source": task.Id, "target": other.Id, "type": "relates"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.collection, func(t *testing.T) {
+			body, err := json.Marshal(tt.fields)
+			if err != nil {
+				t.Fatalf("failed to encode body: %v", err)
+			}
+			existing := createE2ERecord(t, app, tt.collection, tt.fields)
+			base := "/api/collections/" + tt.collection + "/records"
+			target := base + "/" + existing.Id
+
+			requests := []struct{ method, target string }{
+				{http.MethodPost, base},
+				{http.MethodPatch, target},
+				{http.MethodDelete, target},
+			}
+			for _, r := range requests {
+				rec := serveE2E(t, app, r.method, r.target, token, string(body))
+				if rec.Code != http.StatusForbidden {
+					t.Errorf("agent %s %s: expected 403, got %d: %s", r.method, r.target, rec.Code, rec.Body.String())
+				}
+			}
+			if _, err := app.FindRecordById(tt.collection, existing.Id); err != nil {
+				t.Errorf("record was deleted by the agent: %v", err)
+			}
+
+			// Humans are not restricted. The existing record goes first so
+			// the new one does not collide with it on unique fields.
+			for _, r := range []struct{ method, target string }{requests[1], requests[2], requests[0]} {
+				rec := serveE2E(t, app, r.method, r.target, "", string(body))
+				if rec.Code >= http.StatusBadRequest {
+					t.Errorf("human %s %s: expected success, got %d: %s", r.method, r.target, rec.Code, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+// TestPolicyE2E_AgentCanProposeButNotReview verifies that agents on a
+// supervised board can create proposals, but not approve or discard them.
+func TestPolicyE2E_AgentCanProposeButNotReview(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	app := setupE2EMigratedApp(t)
+	hooks.RegisterPolicyHooks(app)
+
+	board := createE2ERecord(t, app, "boards", map[string]any{
+		"name":       "Work",
+		"prefix":     "WRK",
+		"agent_mode": policy.ModeSupervised,
+	})
+
+	record, token, err := auth.CreateToken(app, "bot", auth.ScopeWrite, "", 0)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	record.Set("agent", "claude")
+	if err := app.Save(record); err != nil {
+		t.Fatalf("failed to set token agent: %v", err)
+	}
+
+	body := `{"board": "` + board.Id + `", "action": "create", "status": "pending"}`
+	rec := serveE2E(t, app, http.MethodPost, "/api/collections/proposals/records", token, body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("agent creating a proposal: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode proposal: %v", err)
+	}
+
+	target := "/api/collections/proposals/records/" + created.ID
+	for _, method := range []string{http.MethodPatch, http.MethodDelete} {
+		rec := serveE2E(t, app, method, target, token, `{"status": "approved"}`)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("agent %s %s: expected 403, got %d: %s", method, target, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// createE2ERecord saves a record with the given fields.
+func createE2ERecord(t *testing.T, app *pocketbase.PocketBase, collection string, fields map[string]any) *core.Record {
+	t.Helper()
+
+	c, err := app.FindCollectionByNameOrId(collection)
+	if err != nil {
+		t.Fatalf("%s collection not found: %v", collection, err)
+	}
+	record := core.NewRecord(c)
+	record.Load(fields)
+	if err := app.Save(record); err != nil {
+		t.Fatalf("failed to create %s record: %v", collection, err)
+	}
+	return record
+}
+
+// TestPolicyE2E_TrashThroughUpdateIsADelete verifies that an agent that
+// may not delete cannot move a task to the trash by setting its trash
+// fields in an update.
+func TestPolicyE2E_TrashThroughUpdateIsADelete(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	app := setupE2EMigratedApp(t)
+	hooks.RegisterPolicyHooks(app)
+
+	board := createE2ERecord(t, app, "boards", map[string]any{
+		"name":       "Work",
+		"prefix":     "WRK",
+		"agent_mode": policy.ModeCollaborative,
+	})
+	task := createE2ERecord(t, app, "tasks", map[string]any{
+		"title":      "Fix login",
+		"board":      board.Id,
+		"type":       "bug",
+		"priority":   "medium",
+		"column":     "todo",
+		"position":   1000,
+		"created_by": "user",
+	})
+
+	record, token, err := auth.CreateToken(app, "bot", auth.ScopeWrite, "", 0)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	record.Set("agent", "claude")
+	if err := app.Save(record); err != nil {
+		t.Fatalf("failed to set token agent: %v", err)
+	}
+
+	target := "/api/collections/tasks/records/" + task.Id
+	for _, body := range []string{
+		`{"deleted_at": "2026-01-05 09:00:00.000Z"}`,
+		`{"deleted_by": "claude"}`,
+		`{"title": "Fix login page", "deleted_with": "` + board.Id + `"}`,
+	} {
+		rec := serveE2E(t, app, http.MethodPatch, target, token, body)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("agent PATCH %s: expected 403, got %d: %s", body, rec.Code, rec.Body.String())
+		}
+	}
+
+	refreshed, err := app.FindRecordById("tasks", task.Id)
+	if err != nil {
+		t.Fatalf("failed to find task: %v", err)
+	}
+	if refreshed.GetString("deleted_at") != "" || refreshed.GetString("title") != "Fix login" {
+		t.Errorf("task was changed: deleted_at=%q title=%q", refreshed.GetString("deleted_at"), refreshed.GetString("title"))
+	}
+
+	// Plain updates are allowed in collaborative mode
+	rec := serveE2E(t, app, http.MethodPatch, target, token, `{"title": "Fix login page"}`)
+	if rec.Code != http.StatusOK {
+		t.Errorf("agent plain update: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestPolicyE2E_TrashNamesTheTokenNotTheHeader verifies that a delete
+// through the API is attributed to the token that made it, not to the
+// actor a client claims in its headers.
+func TestPolicyE2E_TrashNamesTheTokenNotTheHeader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	app := setupE2EMigratedApp(t)
+	hooks.RegisterPolicyHooks(app)
+	hooks.RegisterTrashHooks(app)
+
+	board := createE2ERecord(t, app, "boards", map[string]any{"name": "Work", "prefix": "WRK"})
+	task := createE2ERecord(t, app, "tasks", map[string]any{
+		"title":      "Fix login",
+		"board":      board.Id,
+		"type":       "bug",
+		"priority":   "medium",
+		"column":     "todo",
+		"position":   1000,
+		"created_by": "user",
+	})
+
+	_, token, err := auth.CreateToken(app, "laptop", auth.ScopeWrite, "", 0)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	r, err := apis.NewRouter(app)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	mux, err := r.BuildMux()
+	if err != nil {
+		t.Fatalf("failed to build router: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodDelete, "/api/collections/tasks/records/"+task.Id, nil)
+	req.Header.Set("Authorization", token)
+	req.Header.Set(undo.HeaderActor, "mallory")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	trashed, err := app.FindRecordById("tasks", task.Id)
+	if err != nil {
+		t.Fatalf("task was not kept in the trash: %v", err)
+	}
+	if by := trashed.GetString("deleted_by"); by != "laptop" {
+		t.Errorf("expected deleted_by %q, got %q", "laptop", by)
+	}
+}
@@ -6,7 +6,6 @@
 package performance
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"sync"
@@ -18,6 +17,8 @@ import (
 
 	"github.com/ramtinJ95/EgenSkriven/internal/autoresume"
 	"github.com/ramtinJ95/EgenSkriven/internal/resume"
+	"github.com/ramtinJ95/EgenSkriven/internal/taskevent"
+	"github.com/ramtinJ95/EgenSkriven/internal/testutil"
 )
 
 // ========== Performance Targets ==========
@@ -57,7 +58,9 @@ func TestE2E_BlockResumeWorkflow_Performance(t *testing.T) {
 	start := time.Now()
 	_ = createPerfComment(t, app, task.Id, "I have a question: What should I implement next?", "agent")
 	task.Set("column", "need_input")
-	addHistory(task, "blocked", "agent")
+	taskevent.Add(task, "blocked", "agent", "", map[string]any{
+		"column": map[string]any{"from": "in_progress", "to": "need_input"},
+	}, nil)
 	if err := app.Save(task); err != nil {
 		t.Fatalf("failed to block task: %v", err)
 	}
@@ -391,8 +394,9 @@ func setupPerfTestApp(t *testing.T) *pocketbase.PocketBase {
 		t.Fatalf("failed to bootstrap app: %v", err)
 	}
 
-	// Setup collections
+	// Setup collections and log task events
 	setupPerfCollections(t, app)
+	taskevent.Bind(app)
 
 	return app
 }
@@ -444,7 +448,6 @@ func setupPerfCollections(t *testing.T, app *pocketbase.PocketBase) {
 			Values:   []string{"backlog", "todo", "in_progress", "need_input", "review", "done"},
 		})
 		tasks.Fields.Add(&core.NumberField{Name: "position"})
-		tasks.Fields.Add(&core.JSONField{Name: "history"})
 		tasks.Fields.Add(&core.JSONField{Name: "agent_session"})
 		tasks.Fields.Add(&core.AutodateField{Name: "created", OnCreate: true})
 		tasks.Fields.Add(&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true})
@@ -458,6 +461,11 @@ func setupPerfCollections(t *testing.T, app *pocketbase.PocketBase) {
 		}
 	}
 
+	// Task events collection
+	if _, err := app.FindCollectionByNameOrId(taskevent.CollectionName); err != nil {
+		testutil.CreateTaskEventsCollection(t, app)
+	}
+
 	// Comments collection with indexes
 	if _, err := app.FindCollectionByNameOrId("comments"); err != nil {
 		tasks, _ := app.FindCollectionByNameOrId("tasks")
@@ -525,24 +533,3 @@ func createPerfComment(t *testing.T, app *pocketbase.PocketBase, taskId, content
 	}
 	return record
 }
-
-func addHistory(task *core.Record, action, actor string) {
-	history := task.Get("history")
-	var historySlice []map[string]any
-	if history != nil {
-		if data, err := json.Marshal(history); err == nil {
-			json.Unmarshal(data, &historySlice)
-		}
-	}
-	if historySlice == nil {
-		historySlice = []map[string]any{}
-	}
-	historySlice = append(historySlice, map[string]any{
-		"action":     action,
-		"actor":      actor,
-		"from":       "in_progress",
-		"to":         "need_input",
-		"timestamp":  time.Now().UTC().Format(time.RFC3339),
-	})
-	task.Set("history", historySlice)
-}
@@ -1,24 +1,28 @@
-import { type HistoryEntry } from '../types/task'
+import { type FieldChange, type TaskEvent } from '../types/task'
+import { useTaskEvents } from '../hooks/useTaskEvents'
 import styles from './ActivityLog.module.css'
 
 interface ActivityLogProps {
-  history: HistoryEntry[]
+  taskId: string
   created: string
 }
 
 /**
- * ActivityLog displays the history of changes to a task.
+ * ActivityLog displays the history of changes to a task, read from the
+ * task_events collection.
  * 
  * Features:
- * - Shows all history entries sorted newest first
+ * - Shows all task events sorted newest first
  * - Relative timestamps (e.g., "2h ago", "3d ago")
  * - Actor icons (user, agent, CLI)
  * - Human-readable action descriptions
  * - Always shows task creation at the bottom
  */
-export function ActivityLog({ history, created }: ActivityLogProps) {
-  // Sort history newest first
-  const sortedHistory = [...(history || [])].sort(
+export function ActivityLog({ taskId, created }: ActivityLogProps) {
+  const { events } = useTaskEvents(taskId)
+
+  // Sort events newest first
+  const sortedEvents = [...events].sort(
     (a, b) => new Date(b.timestamp).getTime() - new Date(a.timestamp).getTime()
   )
 
@@ -58,19 +62,31 @@ export function ActivityLog({ history, created }: ActivityLogProps) {
     }
   }
 
+  // Find the first field change of an event ({"field": {"from", "to"}})
+  const firstChange = (event: TaskEvent): ({ field: string } & FieldChange) | null => {
+    for (const [field, value] of Object.entries(event.changes || {})) {
+      if (value && typeof value === 'object' && 'to' in value) {
+        const change = value as FieldChange
+        return { field, from: change.from, to: change.to }
+      }
+    }
+    return null
+  }
+
   // Format action description
-  const formatAction = (entry: HistoryEntry): string => {
-    switch (entry.action) {
+  const formatAction = (event: TaskEvent): string => {
+    const change = firstChange(event)
+    switch (event.action) {
       case 'created':
         return 'created this task'
       case 'moved':
-        if (entry.changes?.field === 'column') {
-          return `moved to ${formatColumnName(String(entry.changes.to))}`
+        if (change?.field === 'column') {
+          return `moved to ${formatColumnName(String(change.to))}`
         }
         return 'moved this task'
       case 'updated':
-        if (entry.changes) {
-          const { field, from, to } = entry.changes
+        if (change) {
+          const { field, from, to } = change
           if (field === 'priority') {
             return `changed priority from ${from} to ${to}`
           }
@@ -101,7 +117,7 @@ export function ActivityLog({ history, created }: ActivityLogProps) {
       case 'deleted':
         return 'deleted this task'
       default:
-        return entry.action
+        return event.action.replace(/_/g, ' ')
     }
   }
 
@@ -134,8 +150,8 @@ export function ActivityLog({ history, created }: ActivityLogProps) {
     }
   }
 
-  // Don't show if no history and no created date
-  if ((!history || history.length === 0) && !created) {
+  // Don't show if no events and no created date
+  if (events.length === 0 && !created) {
     return null
   }
 
@@ -144,21 +160,21 @@ export function ActivityLog({ history, created }: ActivityLogProps) {
       <h3 className={styles.header}>Activity</h3>
       
       <div className={styles.list}>
-        {sortedHistory.map((entry, index) => (
-          <div key={index} className={styles.item}>
+        {sortedEvents.map((event) => (
+          <div key={event.id} className={styles.item}>
             <span className={styles.icon}>
-              {getActorIcon(entry.actor)}
+              {getActorIcon(event.actor)}
             </span>
             <div className={styles.content}>
               <span className={styles.actor}>
-                {formatActor(entry.actor, entry.actor_detail)}
+                {formatActor(event.actor, event.actor_detail)}
               </span>{' '}
               <span className={styles.action}>
-                {formatAction(entry)}
+                {formatAction(event)}
               </span>
             </div>
             <span className={styles.time}>
-              {formatTime(entry.timestamp)}
+              {formatTime(event.timestamp)}
             </span>
           </div>
         ))}
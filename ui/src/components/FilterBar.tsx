@@ -12,6 +12,13 @@ const FIELD_LABELS: Record<FilterField, string> = {
   epic: 'Epic',
   created_by: 'Created By',
   title: 'Title',
+  created_by_agent: 'Agent',
+  sprint: 'Sprint',
+  parent: 'Parent',
+  assignees: 'Assignees',
+  blocked_by: 'Blocked By',
+  description: 'Description',
+  query: 'Query',
 }
 
 // Human-readable operator labels
@@ -27,6 +34,7 @@ const OPERATOR_LABELS: Record<string, string> = {
   is_set: 'is set',
   is_not_set: 'is not set',
   contains: 'contains',
+  matches: 'matches',
 }
 
 interface FilterPillProps {
@@ -34,6 +34,7 @@ const OPERATOR_LABELS: Record<FilterOperator, string> = {
   is_set: 'is set',
   is_not_set: 'is not set',
   contains: 'contains',
+  matches: 'matches',
 }
 
 // Epic type from PocketBase
@@ -321,7 +322,7 @@ export function FilterBuilder({ isOpen, onClose, tasks }: FilterBuilderProps) {
                     )}
                     <div className={styles.filterPill}>
                       <span className={styles.filterField}>
-                        {FILTER_FIELDS.find((f) => f.value === filter.field)?.label}
+                        {FILTER_FIELDS.find((f) => f.value === filter.field)?.label ?? filter.field}
                       </span>
                       <span className={styles.filterOperator}>
                         {OPERATOR_LABELS[filter.operator]}
@@ -371,7 +371,7 @@ export function TaskDetail({ task, tasks, onClose, onUpdate, onTaskClick, curren
 
           {/* Activity Log */}
           <ActivityLog
-            history={task.history || []}
+            taskId={task.id}
             created={task.created}
           />
 
@@ -50,6 +50,7 @@ export function useBoards(): UseBoardsReturn {
     const fetchBoards = async () => {
       try {
         const records = await pb.collection('boards').getFullList<Board>({
+          filter: 'deleted_at = ""',
           sort: 'name',
         })
         setBoards(records)
@@ -77,6 +78,11 @@ export function useBoards(): UseBoardsReturn {
             break
 
           case 'update':
+            // Deleting a board moves it to the trash, which is an update
+            if (event.record.deleted_at) {
+              setBoards((prev) => prev.filter((b) => b.id !== event.record.id))
+              break
+            }
             // Update or add board in state
             setBoards((prev) => {
               const existingIndex = prev.findIndex((b) => b.id === event.record.id)
@@ -63,7 +63,7 @@ export function useEpics(boardId?: string): UseEpicsReturn {
     const fetchEpics = async () => {
       try {
         const records = await pb.collection('epics').getFullList<Epic>({
-          filter: `board = "${boardId}"`,
+          filter: `board = "${boardId}" && deleted_at = ""`,
           sort: 'title',
         })
         setEpics(records)
@@ -100,6 +100,11 @@ export function useEpics(boardId?: string): UseEpicsReturn {
             break
 
           case 'update':
+            // Deleting an epic moves it to the trash, which is an update
+            if (event.record.deleted_at) {
+              setEpics((prev) => prev.filter((e) => e.id !== event.record.id))
+              break
+            }
             // Update or add epic in state
             setEpics((prev) => {
               const existingIndex = prev.findIndex((e) => e.id === event.record.id)
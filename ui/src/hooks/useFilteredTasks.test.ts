@@ -11,6 +11,7 @@ const {
   matchDateFilter,
   matchRelationFilter,
   matchTextFilter,
+  matchQueryFilter,
   matchesSearch,
 } = filterHelpers
 
@@ -280,6 +281,57 @@ describe('matchTextFilter', () => {
   })
 })
 
+describe('matchQueryFilter', () => {
+  beforeEach(() => {
+    vi.useFakeTimers()
+    vi.setSystemTime(new Date(2024, 2, 10, 12))
+  })
+
+  afterEach(() => {
+    vi.useRealTimers()
+  })
+
+  it('matches field terms and comma-separated values', () => {
+    const task = createMockTask({ priority: 'high', labels: ['Backend'] })
+    expect(matchQueryFilter(task, 'priority:high,urgent label:backend')).toBe(true)
+    expect(matchQueryFilter(task, 'priority:high -label:backend')).toBe(false)
+  })
+
+  it('matches is: states', () => {
+    const blocked = createMockTask({ blocked_by: ['task-2'] })
+    expect(matchQueryFilter(blocked, 'is:blocked')).toBe(true)
+    expect(matchQueryFilter(createMockTask(), 'is:blocked')).toBe(false)
+    expect(matchQueryFilter(createMockTask(), 'is:ready')).toBe(true)
+  })
+
+  it('compares dates by day relative to today', () => {
+    const task = createMockTask({ due_date: '2024-03-12 00:00:00.000Z' })
+    expect(matchQueryFilter(task, 'due:<7d')).toBe(true)
+    expect(matchQueryFilter(task, 'due:<2d')).toBe(false)
+    expect(matchQueryFilter(task, 'due:2024-03-12')).toBe(true)
+    expect(matchQueryFilter(createMockTask(), 'due:none')).toBe(true)
+  })
+
+  it('supports OR, groups and free text', () => {
+    const task = createMockTask({ title: 'Fix login', priority: 'low', type: 'bug' })
+    expect(matchQueryFilter(task, 'priority:high OR type:bug')).toBe(true)
+    expect(matchQueryFilter(task, '-(priority:low type:bug)')).toBe(false)
+    expect(matchQueryFilter(task, 'login')).toBe(true)
+  })
+
+  it('matches nothing when the query does not compile', () => {
+    expect(matchQueryFilter(createMockTask(), 'priority:')).toBe(false)
+    expect(matchQueryFilter(createMockTask(), 'unknown:value')).toBe(false)
+    expect(matchQueryFilter(createMockTask({ assignees: ['alice'] }), 'assignee:me')).toBe(false)
+  })
+
+  it('is applied by matchesFilter', () => {
+    const filter = { id: '1', field: 'query' as const, operator: 'matches' as const, value: 'type:bug' }
+    expect(matchesFilter(createMockTask({ type: 'bug' }), filter)).toBe(true)
+    expect(matchesFilter(createMockTask({ type: 'feature' }), filter)).toBe(false)
+  })
+})
+
 describe('matchesSearch', () => {
   it('returns true for empty query', () => {
     const task = createMockTask({ title: 'Test' })
@@ -357,6 +409,25 @@ describe('matchesFilter (integration)', () => {
     const filter = { id: '1', field: 'title' as const, operator: 'contains' as const, value: 'critical' }
     expect(matchesFilter(task, filter)).toBe(true)
   })
+
+  it('matches assignees filter saved by the CLI', () => {
+    const task = createMockTask({ assignees: ['alice', 'bob'] })
+    const filter = { id: '1', field: 'assignees' as const, operator: 'includes_any' as const, value: ['bob'] }
+    expect(matchesFilter(task, filter)).toBe(true)
+  })
+
+  it('matches blocked_by is_not_set filter saved by the CLI', () => {
+    const blocked = createMockTask({ blocked_by: ['task-2'] })
+    const filter = { id: '1', field: 'blocked_by' as const, operator: 'is_not_set' as const, value: null }
+    expect(matchesFilter(blocked, filter)).toBe(false)
+    expect(matchesFilter(createMockTask(), filter)).toBe(true)
+  })
+
+  it('matches sprint filter saved by the CLI', () => {
+    const task = createMockTask({ sprint: 'sprint-1' })
+    const filter = { id: '1', field: 'sprint' as const, operator: 'is_any_of' as const, value: ['sprint-1', 'sprint-2'] }
+    expect(matchesFilter(task, filter)).toBe(true)
+  })
 })
 
 describe('filter combination (AND/OR logic)', () => {
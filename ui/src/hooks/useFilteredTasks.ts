@@ -2,6 +2,8 @@ import { useMemo, useEffect } from 'react'
 import type { Task } from '../types/task'
 import type { Filter } from '../stores/filters'
 import { useFilterStore } from '../stores/filters'
+import { compileQuery, matchQuery } from '../lib/taskQuery'
+import type { QueryNode } from '../lib/taskQuery'
 
 // Debounce delay for search (ms)
 const SEARCH_DEBOUNCE_MS = 300
@@ -41,9 +43,14 @@ function matchesFilter(task: Task, filter: Filter): boolean {
     case 'priority':
     case 'type':
     case 'created_by':
+    case 'created_by_agent':
+    case 'sprint':
+    case 'parent':
       return matchSelectFilter(task[field], operator, value)
     case 'labels':
-      return matchLabelsFilter(task.labels || [], operator, value as string[])
+    case 'assignees':
+    case 'blocked_by':
+      return matchLabelsFilter(task[field] || [], operator, value as string[])
     case 'due_date':
       return matchDateFilter(task.due_date, operator, value as string)
     case 'epic':
@@ -53,14 +60,17 @@ function matchesFilter(task: Task, filter: Filter): boolean {
         value as string
       )
     case 'title':
-      return matchTextFilter(task.title, operator, value as string)
+    case 'description':
+      return matchTextFilter(task[field], operator, value as string)
+    case 'query':
+      return matchQueryFilter(task, value as string)
     default:
       return true
   }
 }
 
 /**
- * Match single-select fields (column, priority, type, created_by)
+ * Match single-select fields (column, priority, type, created_by, sprint, parent)
  */
 function matchSelectFilter(
   taskValue: string | undefined,
@@ -90,7 +100,7 @@ function matchSelectFilter(
 }
 
 /**
- * Match array fields (labels)
+ * Match array fields (labels, assignees, blocked_by)
  */
 function matchLabelsFilter(
   taskLabels: string[],
@@ -207,6 +217,29 @@ function matchTextFilter(
   }
 }
 
+// Compiled queries by text; null for queries that do not compile
+const compiledQueries = new Map<string, QueryNode | null>()
+
+/**
+ * Match task queries saved by views (operator "matches"), such as
+ * "priority:high is:blocked". Queries that do not compile match nothing,
+ * as in the TUI.
+ */
+function matchQueryFilter(task: Task, query: string | null): boolean {
+  if (!query) return true
+
+  let compiled = compiledQueries.get(query)
+  if (compiled === undefined) {
+    try {
+      compiled = compileQuery(query)
+    } catch {
+      compiled = null
+    }
+    compiledQueries.set(query, compiled)
+  }
+  return compiled !== null && matchQuery(compiled, task)
+}
+
 /**
  * Check if a task matches the search query
  * Searches in title, description, and task ID
@@ -279,5 +312,6 @@ export const filterHelpers = {
   matchDateFilter,
   matchRelationFilter,
   matchTextFilter,
+  matchQueryFilter,
   matchesSearch,
 }
@@ -0,0 +1,104 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest'
+import { renderHook, waitFor, act } from '@testing-library/react'
+import { useTaskEvents } from './useTaskEvents'
+import type { TaskEvent } from '../types/task'
+
+// Create mock functions we can inspect
+const mockGetFullList = vi.fn().mockResolvedValue([])
+const mockSubscribe = vi.fn().mockResolvedValue(undefined)
+const mockUnsubscribe = vi.fn()
+
+// Mock PocketBase
+vi.mock('../lib/pb', () => ({
+  pb: {
+    collection: vi.fn(() => ({
+      getFullList: mockGetFullList,
+      subscribe: mockSubscribe,
+      unsubscribe: mockUnsubscribe,
+    })),
+  },
+}))
+
+const makeEvent = (id: string, task: string, action: string): TaskEvent => ({
+  id,
+  collectionId: 'task_events',
+  collectionName: 'task_events',
+  task,
+  action,
+  actor: 'cli',
+  changes: null,
+  timestamp: '2025-01-15 10:00:00.000Z',
+})
+
+describe('useTaskEvents', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockGetFullList.mockResolvedValue([])
+    mockSubscribe.mockResolvedValue(undefined)
+  })
+
+  it('fetches the events of the task oldest first', async () => {
+    mockGetFullList.mockResolvedValue([makeEvent('e1', 'task-123', 'created')])
+
+    const { result } = renderHook(() => useTaskEvents('task-123'))
+
+    await waitFor(() => {
+      expect(result.current.loading).toBe(false)
+    })
+    expect(mockGetFullList).toHaveBeenCalledWith({
+      filter: 'task = "task-123"',
+      sort: '+timestamp',
+    })
+    expect(result.current.events.map((e) => e.id)).toEqual(['e1'])
+  })
+
+  it('does not fetch when taskId is empty', async () => {
+    const { result } = renderHook(() => useTaskEvents(''))
+
+    await waitFor(() => {
+      expect(result.current.loading).toBe(false)
+    })
+    expect(mockGetFullList).not.toHaveBeenCalled()
+    expect(mockSubscribe).not.toHaveBeenCalled()
+  })
+
+  it('sets error state when fetch fails', async () => {
+    mockGetFullList.mockRejectedValue(new Error('Network error'))
+
+    const { result } = renderHook(() => useTaskEvents('task-123'))
+
+    await waitFor(() => {
+      expect(result.current.loading).toBe(false)
+      expect(result.current.error).toBeInstanceOf(Error)
+    })
+  })
+
+  it('adds new events of the task from the subscription', async () => {
+    const { result } = renderHook(() => useTaskEvents('task-123'))
+
+    await waitFor(() => {
+      expect(result.current.loading).toBe(false)
+    })
+    expect(mockSubscribe).toHaveBeenCalledWith('*', expect.any(Function))
+    const handler = mockSubscribe.mock.calls[0][1]
+
+    act(() => {
+      handler({ action: 'create', record: makeEvent('e2', 'other-task', 'moved') })
+      handler({ action: 'create', record: makeEvent('e3', 'task-123', 'moved') })
+      handler({ action: 'create', record: makeEvent('e3', 'task-123', 'moved') })
+    })
+
+    expect(result.current.events.map((e) => e.id)).toEqual(['e3'])
+  })
+
+  it('unsubscribes on unmount', async () => {
+    const { unmount } = renderHook(() => useTaskEvents('task-123'))
+
+    await waitFor(() => {
+      expect(mockSubscribe).toHaveBeenCalled()
+    })
+    unmount()
+
+    expect(mockUnsubscribe).toHaveBeenCalledWith('*')
+  })
+})
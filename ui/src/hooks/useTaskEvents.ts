@@ -0,0 +1,80 @@
+import { useEffect, useState } from 'react'
+import { pb } from '../lib/pb'
+import type { TaskEvent } from '../types/task'
+
+interface UseTaskEventsReturn {
+  events: TaskEvent[]
+  loading: boolean
+  error: Error | null
+}
+
+/**
+ * Hook for fetching the event log of a task from the task_events collection.
+ *
+ * Events are returned oldest first. New events written while the task is
+ * open are added through a real-time subscription.
+ *
+ * @param taskId - The task ID to fetch events for
+ */
+export function useTaskEvents(taskId: string): UseTaskEventsReturn {
+  const [events, setEvents] = useState<TaskEvent[]>([])
+  const [loading, setLoading] = useState(true)
+  const [error, setError] = useState<Error | null>(null)
+
+  // Fetch events on mount or when taskId changes
+  useEffect(() => {
+    if (!taskId) {
+      setEvents([])
+      setLoading(false)
+      return
+    }
+
+    let cancelled = false
+    const fetchEvents = async () => {
+      setLoading(true)
+      setError(null)
+
+      try {
+        const records = await pb.collection('task_events').getFullList<TaskEvent>({
+          filter: `task = "${taskId}"`,
+          sort: '+timestamp',
+        })
+        if (!cancelled) setEvents(records)
+      } catch (err) {
+        console.error('[useTaskEvents] Failed to fetch events:', err)
+        if (!cancelled) {
+          setError(err instanceof Error ? err : new Error('Failed to fetch task events'))
+        }
+      } finally {
+        if (!cancelled) setLoading(false)
+      }
+    }
+
+    fetchEvents()
+    return () => {
+      cancelled = true
+    }
+  }, [taskId])
+
+  // Add events written for this task while it is open
+  useEffect(() => {
+    if (!taskId) return
+
+    pb.collection('task_events')
+      .subscribe<TaskEvent>('*', (event) => {
+        if (event.action !== 'create' || event.record.task !== taskId) return
+        setEvents((prev) =>
+          prev.some((e) => e.id === event.record.id) ? prev : [...prev, event.record]
+        )
+      })
+      .catch((err) => {
+        console.error('[useTaskEvents] Subscription failed:', err)
+      })
+
+    return () => {
+      pb.collection('task_events').unsubscribe('*')
+    }
+  }, [taskId])
+
+  return { events, loading, error }
+}
@@ -10,6 +10,10 @@ const debugLog = (...args: unknown[]) => {
   }
 }
 
+// Deleted tasks stay in the trash until restored or purged, and archived
+// tasks are hidden from the board until unarchived
+const ACTIVE = 'deleted_at = "" && archived_at = ""'
+
 interface UseTasksReturn {
   tasks: Task[]
   loading: boolean
@@ -53,13 +57,14 @@ export function useTasks(boardId?: string): UseTasksReturn {
     const fetchTasks = async () => {
       setLoading(true)
       try {
-        const options: { sort: string; filter?: string } = {
+        const options: { sort: string; filter: string } = {
           sort: 'position',
+          filter: ACTIVE,
         }
         
         // Filter by board if boardId is provided
         if (boardId) {
-          options.filter = `board = "${boardId}"`
+          options.filter = `board = "${boardId}" && ${ACTIVE}`
         }
         
         const records = await pb.collection('tasks').getFullList<Task>(options)
@@ -111,8 +116,12 @@ export function useTasks(boardId?: string): UseTasksReturn {
             break
 
           case 'update':
-            // If task was moved to a different board, remove it from this view
-            if (boardId && taskBoardId !== boardId) {
+            // Deleting a task moves it to the trash, which is an update
+            if (event.record.deleted_at || event.record.archived_at) {
+              debugLog('Removing task - moved to trash or archived')
+              setTasks((prev) => prev.filter((t) => t.id !== event.record.id))
+            } else if (boardId && taskBoardId !== boardId) {
+              // If task was moved to a different board, remove it from this view
               debugLog('Removing task - moved to different board')
               setTasks((prev) => prev.filter((t) => t.id !== event.record.id))
             } else {
@@ -223,9 +232,9 @@ export function useTasks(boardId?: string): UseTasksReturn {
       } catch (err) {
         // Rollback on error - refetch all tasks
         console.error('[useTasks] Update failed, refetching:', err)
-        const options: { sort: string; filter?: string } = { sort: 'position' }
+        const options: { sort: string; filter: string } = { sort: 'position', filter: ACTIVE }
         if (boardId) {
-          options.filter = `board = "${boardId}"`
+          options.filter = `board = "${boardId}" && ${ACTIVE}`
         }
         const records = await pb.collection('tasks').getFullList<Task>(options)
         setTasks(records)
@@ -281,9 +290,9 @@ export function useTasks(boardId?: string): UseTasksReturn {
       } catch (err) {
         // Rollback - refetch
         console.error('[useTasks] Move failed, refetching:', err)
-        const options: { sort: string; filter?: string } = { sort: 'position' }
+        const options: { sort: string; filter: string } = { sort: 'position', filter: ACTIVE }
         if (boardId) {
-          options.filter = `board = "${boardId}"`
+          options.filter = `board = "${boardId}" && ${ACTIVE}`
         }
         const records = await pb.collection('tasks').getFullList<Task>(options)
         setTasks(records)
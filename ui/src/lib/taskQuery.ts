@@ -0,0 +1,604 @@
+import type { Task } from '../types/task'
+
+/**
+ * Task query language shared with 'list --query', the TUI filter bar and
+ * saved views (see internal/taskquery). A query is a list of terms that
+ * must all match:
+ *
+ *   priority:high,urgent label:backend -label:wontfix due:<7d is:blocked
+ *
+ * Epic, sprint and parent terms compare record IDs: the web UI does not
+ * resolve names, so a view that refers to an epic by title matches no
+ * tasks here.
+ */
+
+type Op = ':' | '<' | '<=' | '>' | '>='
+
+type Kind = 'select' | 'list' | 'date' | 'number' | 'text' | 'is' | 'has'
+
+export type QueryNode =
+  | { kind: 'and'; nodes: QueryNode[] }
+  | { kind: 'or'; nodes: QueryNode[] }
+  | { kind: 'not'; node: QueryNode }
+  | { kind: 'term'; field: string; op: Op; values: string[] }
+
+// Canonical field names and their kind
+const FIELDS: Record<string, Kind> = {
+  column: 'select',
+  priority: 'select',
+  type: 'select',
+  by: 'select',
+  agent: 'select',
+  epic: 'select',
+  sprint: 'select',
+  parent: 'select',
+  label: 'list',
+  assignee: 'list',
+  due: 'date',
+  created: 'date',
+  updated: 'date',
+  estimate: 'number',
+  text: 'text',
+  title: 'text',
+  is: 'is',
+  has: 'has',
+}
+
+// Alternative field names
+const ALIASES: Record<string, string> = {
+  col: 'column',
+  status: 'column',
+  in: 'column',
+  p: 'priority',
+  prio: 'priority',
+  t: 'type',
+  created_by: 'by',
+  'created-by': 'by',
+  l: 'label',
+  labels: 'label',
+  assignees: 'assignee',
+  assigned: 'assignee',
+  due_date: 'due',
+  est: 'estimate',
+  q: 'text',
+}
+
+const IS_VALUES = [
+  'blocked',
+  'unblocked',
+  'ready',
+  'open',
+  'done',
+  'overdue',
+  'subtask',
+  'toplevel',
+  'assigned',
+  'unassigned',
+]
+const HAS_VALUES = ['due', 'epic', 'sprint', 'parent', 'estimate', 'label', 'assignee']
+
+const VALID_VALUES: Record<string, string[]> = {
+  priority: ['low', 'medium', 'high', 'urgent'],
+  type: ['bug', 'feature', 'chore'],
+  by: ['user', 'agent', 'cli'],
+}
+
+/**
+ * Error thrown for queries that cannot be parsed
+ */
+export class QuerySyntaxError extends Error {
+  constructor(pos: number, msg: string) {
+    super(`invalid query at position ${pos + 1}: ${msg}`)
+    this.name = 'QuerySyntaxError'
+  }
+}
+
+type Token =
+  | { kind: 'term'; pos: number; text: string; term: QueryNode }
+  | { kind: 'not' | 'or' | 'lparen' | 'rparen'; pos: number; text: string }
+
+function canonicalField(name: string): string {
+  const lower = name.toLowerCase()
+  if (ALIASES[lower]) return ALIASES[lower]
+  return lower in FIELDS ? lower : ''
+}
+
+function canonicalHas(value: string): string {
+  return canonicalField(value) || value.toLowerCase()
+}
+
+function isNoneOrAny(value: string): boolean {
+  const lower = value.toLowerCase()
+  return lower === 'none' || lower === 'any'
+}
+
+function includesFold(items: string[], value: string): boolean {
+  const lower = value.toLowerCase()
+  return items.some((item) => item.toLowerCase() === lower)
+}
+
+function isSpace(c: string): boolean {
+  return /\s/.test(c)
+}
+
+function isWordChar(c: string): boolean {
+  return /[A-Za-z0-9_-]/.test(c)
+}
+
+// Reads a double-quoted string; \" escapes a quote
+function lexQuoted(s: string, start: number): [string, number] {
+  let value = ''
+  for (let i = start + 1; i < s.length; i++) {
+    if (s[i] === '\\' && s[i + 1] === '"') {
+      value += '"'
+      i++
+    } else if (s[i] === '"') {
+      return [value, i + 1]
+    } else {
+      value += s[i]
+    }
+  }
+  throw new QuerySyntaxError(start, 'unterminated quote')
+}
+
+// Reads the comparison and the comma-separated values of a term
+function lexValues(s: string, start: number, field: string): [QueryNode, number] {
+  let i = start
+  let op: Op = ':'
+  for (const candidate of ['<=', '>=', '<', '>'] as const) {
+    if (s.startsWith(candidate, i)) {
+      op = candidate
+      i += candidate.length
+      break
+    }
+  }
+  const kind = FIELDS[field]
+  if (op !== ':' && kind !== 'date' && kind !== 'number') {
+    throw new QuerySyntaxError(i - op.length, `'${field}' cannot be compared with ${op}`)
+  }
+
+  const values: string[] = []
+  for (;;) {
+    let value: string
+    if (s[i] === '"') {
+      const [quoted, next] = lexQuoted(s, i)
+      value = quoted
+      i = next
+    } else {
+      const valueStart = i
+      while (i < s.length && !isSpace(s[i]) && !',()'.includes(s[i])) i++
+      value = s.slice(valueStart, i)
+    }
+    if (value === '') {
+      throw new QuerySyntaxError(i, `missing value for '${field}'`)
+    }
+    values.push(value)
+    if (s[i] !== ',') break
+    i++
+  }
+
+  if (op !== ':' && values.length > 1) {
+    throw new QuerySyntaxError(i, `'${field}${op}' takes a single value`)
+  }
+  const term: QueryNode = { kind: 'term', field, op, values }
+  const error = validateTerm(field, op, values)
+  if (error) throw new QuerySyntaxError(i, error)
+  return [term, i]
+}
+
+// Reads a field:value term, a quoted phrase, a bare word, or OR
+function lexTerm(s: string, start: number): [Token, number] {
+  if (s[start] === '"') {
+    const [value, next] = lexQuoted(s, start)
+    return [
+      {
+        kind: 'term',
+        pos: start,
+        text: s.slice(start, next),
+        term: { kind: 'term', field: 'text', op: ':', values: [value] },
+      },
+      next,
+    ]
+  }
+
+  let i = start
+  while (i < s.length && isWordChar(s[i])) i++
+  const name = s.slice(start, i)
+
+  if (s[i] === ':' && name !== '') {
+    const field = canonicalField(name)
+    if (!field) {
+      throw new QuerySyntaxError(
+        start,
+        `unknown field '${name}' (fields: ${Object.keys(FIELDS).sort().join(', ')})`
+      )
+    }
+    const [term, next] = lexValues(s, i + 1, field)
+    return [{ kind: 'term', pos: start, text: s.slice(start, next), term }, next]
+  }
+
+  // A bare word runs to the next space or parenthesis
+  while (i < s.length && !isSpace(s[i]) && s[i] !== '(' && s[i] !== ')') i++
+  const word = s.slice(start, i)
+  if (word === 'OR' || word === '|') {
+    return [{ kind: 'or', pos: start, text: word }, i]
+  }
+  return [
+    {
+      kind: 'term',
+      pos: start,
+      text: word,
+      term: { kind: 'term', field: 'text', op: ':', values: [word] },
+    },
+    i,
+  ]
+}
+
+function lex(s: string): Token[] {
+  const tokens: Token[] = []
+  let i = 0
+  while (i < s.length) {
+    const c = s[i]
+    if (isSpace(c)) {
+      i++
+    } else if (c === '(') {
+      tokens.push({ kind: 'lparen', pos: i, text: c })
+      i++
+    } else if (c === ')') {
+      tokens.push({ kind: 'rparen', pos: i, text: c })
+      i++
+    } else if (c === '-' && i + 1 < s.length && !isSpace(s[i + 1]) && s[i + 1] !== ')') {
+      tokens.push({ kind: 'not', pos: i, text: c })
+      i++
+    } else {
+      const [token, next] = lexTerm(s, i)
+      tokens.push(token)
+      i = next
+    }
+  }
+  return tokens
+}
+
+// Checks values that do not need the database; returns an error message
+function validateTerm(field: string, op: Op, values: string[]): string | null {
+  for (const v of values) {
+    switch (FIELDS[field]) {
+      case 'is':
+        if (!includesFold(IS_VALUES, v)) {
+          return `unknown state 'is:${v}' (states: ${IS_VALUES.join(', ')})`
+        }
+        break
+      case 'has':
+        if (!includesFold(HAS_VALUES, canonicalHas(v))) {
+          return `unknown field 'has:${v}' (fields: ${HAS_VALUES.join(', ')})`
+        }
+        break
+      case 'date':
+        if (isNoneOrAny(v)) {
+          if (op !== ':') return `'${v}' cannot be compared with ${op}`
+        } else if (!parseDay(v, new Date())) {
+          return `invalid date '${v}' (use YYYY-MM-DD, today, or an offset like 7d, -2w, 1m)`
+        }
+        break
+      case 'number':
+        if (!(isNoneOrAny(v) && op === ':') && parseNumber(v) === null) {
+          return `invalid number '${v}'`
+        }
+        break
+    }
+    const valid = VALID_VALUES[field]
+    if (valid && !includesFold(valid, v)) {
+      return `invalid ${field} '${v}', must be one of: ${valid.join(', ')}`
+    }
+  }
+  return null
+}
+
+function simplify(node: QueryNode): QueryNode {
+  return node.kind === 'and' && node.nodes.length === 1 ? node.nodes[0] : node
+}
+
+class Parser {
+  tokens: Token[]
+  pos = 0
+
+  constructor(tokens: Token[]) {
+    this.tokens = tokens
+  }
+
+  peek(): Token | undefined {
+    return this.tokens[this.pos]
+  }
+
+  parseOr(): QueryNode {
+    const first = this.parseAnd()
+    const nodes: QueryNode[] = [first]
+    for (let tok = this.peek(); tok?.kind === 'or'; tok = this.peek()) {
+      this.pos++
+      const next = this.parseAnd()
+      if (next.nodes.length === 0) {
+        throw new QuerySyntaxError(tok.pos, 'OR needs a term on both sides')
+      }
+      nodes.push(next)
+    }
+    if (nodes.length === 1) return first
+    if (first.nodes.length === 0) {
+      throw new QuerySyntaxError(0, 'OR needs a term on both sides')
+    }
+    return { kind: 'or', nodes: nodes.map(simplify) }
+  }
+
+  // Parses terms up to OR, ')' or the end
+  parseAnd(): QueryNode & { kind: 'and' } {
+    const and: QueryNode & { kind: 'and' } = { kind: 'and', nodes: [] }
+    let tok = this.peek()
+    while (tok && tok.kind !== 'or' && tok.kind !== 'rparen') {
+      and.nodes.push(this.parseUnary(tok))
+      tok = this.peek()
+    }
+    return and
+  }
+
+  parseUnary(tok: Token): QueryNode {
+    this.pos++
+    switch (tok.kind) {
+      case 'not': {
+        const next = this.peek()
+        if (!next) throw new QuerySyntaxError(tok.pos, "'-' must be followed by a term")
+        return { kind: 'not', node: this.parseUnary(next) }
+      }
+      case 'lparen': {
+        const node = this.parseOr()
+        if (this.peek()?.kind !== 'rparen') {
+          throw new QuerySyntaxError(tok.pos, "unclosed '('")
+        }
+        this.pos++
+        return simplify(node)
+      }
+      case 'term':
+        return tok.term
+      default:
+        throw new QuerySyntaxError(tok.pos, `unexpected '${tok.text}'`)
+    }
+  }
+}
+
+/**
+ * Parse a query. An empty query parses to an empty "and" that matches
+ * every task. Throws QuerySyntaxError for invalid queries.
+ */
+export function parseQuery(input: string): QueryNode {
+  const parser = new Parser(lex(input))
+  const node = parser.parseOr()
+  const rest = parser.peek()
+  if (rest) throw new QuerySyntaxError(rest.pos, `unexpected '${rest.text}'`)
+  return node.kind === 'and' ? node : { kind: 'and', nodes: [node] }
+}
+
+/**
+ * Parse a query for matching. Like Compile in the CLI, this rejects
+ * "assignee:me", which needs the CLI config to know who "me" is.
+ */
+export function compileQuery(input: string): QueryNode {
+  const node = parseQuery(input)
+  const check = (n: QueryNode): void => {
+    switch (n.kind) {
+      case 'and':
+      case 'or':
+        n.nodes.forEach(check)
+        break
+      case 'not':
+        check(n.node)
+        break
+      case 'term':
+        if (n.field === 'assignee' && n.values.some((v) => v.toLowerCase() === 'me')) {
+          throw new Error("cannot determine who 'me' is in the web UI")
+        }
+    }
+  }
+  check(node)
+  return node
+}
+
+// ========== Matching ==========
+
+// Formats a date as YYYY-MM-DD
+function formatDay(d: Date): string {
+  return d.toISOString().slice(0, 10)
+}
+
+/**
+ * Parse today, tomorrow, yesterday, an offset from today such as 7d, -2w
+ * or 3m, or a YYYY-MM-DD date. Returns null for anything else.
+ */
+function parseDay(value: string, now: Date): Date | null {
+  const today = new Date(Date.UTC(now.getFullYear(), now.getMonth(), now.getDate()))
+  const lower = value.toLowerCase()
+  const offset = (days: number, months = 0) =>
+    new Date(
+      Date.UTC(today.getUTCFullYear(), today.getUTCMonth() + months, today.getUTCDate() + days)
+    )
+
+  if (lower === 'today' || lower === 'now') return today
+  if (lower === 'tomorrow') return offset(1)
+  if (lower === 'yesterday') return offset(-1)
+  if (/^\d{4}-\d{2}-\d{2}$/.test(value)) {
+    const date = new Date(`${value}T00:00:00Z`)
+    return isNaN(date.getTime()) || formatDay(date) !== value ? null : date
+  }
+  const match = /^([+-]?\d+)([dwm])$/.exec(value)
+  if (!match) return null
+  const n = parseInt(match[1], 10)
+  switch (match[2]) {
+    case 'd':
+      return offset(n)
+    case 'w':
+      return offset(7 * n)
+    default:
+      return offset(0, n)
+  }
+}
+
+function parseNumber(value: string): number | null {
+  if (value.trim() === '') return null
+  const n = Number(value)
+  return isNaN(n) ? null : n
+}
+
+// Returns the first 10 characters (YYYY-MM-DD) of a stored date
+function taskDay(value: string | undefined): string {
+  return (value || '').slice(0, 10)
+}
+
+function matchSelect(value: string | undefined, values: string[], fold: boolean): boolean {
+  if (!value) return values.some((v) => v.toLowerCase() === 'none')
+  return values.some((v) => {
+    const lower = v.toLowerCase()
+    if (lower === 'any') return true
+    if (lower === 'none') return false
+    return fold ? value.toLowerCase() === lower : value === v
+  })
+}
+
+function matchList(items: string[] | undefined, values: string[]): boolean {
+  if (!items?.length) return values.some((v) => v.toLowerCase() === 'none')
+  return values.some((v) => {
+    const lower = v.toLowerCase()
+    if (lower === 'any') return true
+    if (lower === 'none') return false
+    return items.some((item) => item.toLowerCase() === lower)
+  })
+}
+
+// Dates compare by day: due:<7d is due before the day a week from today,
+// and due:2025-03-01 is that whole day
+function matchDate(value: string, op: Op, values: string[], now: Date): boolean {
+  return values.some((v) => {
+    const lower = v.toLowerCase()
+    if (lower === 'none') return value === ''
+    if (lower === 'any') return value !== ''
+    if (value === '') return false
+    const d = parseDay(v, now)
+    if (!d) return false
+    const day = formatDay(d)
+    const next = formatDay(new Date(d.getTime() + 24 * 60 * 60 * 1000))
+    switch (op) {
+      case '<':
+        return value < day
+      case '<=':
+        return value < next
+      case '>':
+        return value >= next
+      case '>=':
+        return value >= day
+      default:
+        return value >= day && value < next
+    }
+  })
+}
+
+// Unset estimates count as 0
+function matchNumber(estimate: number, op: Op, values: string[]): boolean {
+  return values.some((v) => {
+    const lower = v.toLowerCase()
+    if (lower === 'none') return estimate === 0
+    if (lower === 'any') return estimate > 0
+    const n = parseNumber(v)
+    if (n === null) return false
+    switch (op) {
+      case '<':
+        return estimate < n
+      case '<=':
+        return estimate <= n
+      case '>':
+        return estimate > n
+      case '>=':
+        return estimate >= n
+      default:
+        return estimate === n
+    }
+  })
+}
+
+function matchState(task: Task, state: string, now: Date): boolean {
+  const blocked = (task.blocked_by?.length ?? 0) > 0
+  const done = task.column === 'done'
+  switch (state) {
+    case 'blocked':
+      return blocked
+    case 'unblocked':
+      return !blocked
+    case 'ready':
+      return (task.column === 'todo' || task.column === 'backlog') && !blocked
+    case 'open':
+      return !done
+    case 'done':
+      return done
+    case 'overdue':
+      return matchDate(taskDay(task.due_date), '<', ['today'], now) && !done
+    case 'subtask':
+      return !!task.parent
+    case 'toplevel':
+      return !task.parent
+    case 'assigned':
+      return (task.assignees?.length ?? 0) > 0
+    default: // unassigned
+      return (task.assignees?.length ?? 0) === 0
+  }
+}
+
+function matchTerm(task: Task, field: string, op: Op, values: string[], now: Date): boolean {
+  switch (field) {
+    case 'column':
+    case 'priority':
+    case 'type':
+      return matchSelect(task[field], values, true)
+    case 'by':
+      return matchSelect(task.created_by, values, true)
+    case 'agent':
+      return matchSelect(task.created_by_agent, values, true)
+    case 'epic':
+    case 'sprint':
+    case 'parent':
+      return matchSelect(task[field], values, false)
+    case 'label':
+      return matchList(task.labels, values)
+    case 'assignee':
+      return matchList(task.assignees, values)
+    case 'due':
+      return matchDate(taskDay(task.due_date), op, values, now)
+    case 'created':
+    case 'updated':
+      return matchDate(taskDay(task[field]), op, values, now)
+    case 'estimate':
+      return matchNumber((task as Task & { estimate?: number }).estimate ?? 0, op, values)
+    case 'text':
+    case 'title': {
+      const value = values[0].toLowerCase()
+      const texts = field === 'text' ? [task.title, task.description] : [task.title]
+      return texts.some((text) => (text || '').toLowerCase().includes(value))
+    }
+    case 'is':
+      return values.some((v) => matchState(task, v.toLowerCase(), now))
+    case 'has':
+      return values.some((v) => !matchTerm(task, canonicalHas(v), ':', ['none'], now))
+    default:
+      return false
+  }
+}
+
+/**
+ * Check if a task matches a parsed query
+ */
+export function matchQuery(node: QueryNode, task: Task, now: Date = new Date()): boolean {
+  switch (node.kind) {
+    case 'and':
+      return node.nodes.every((n) => matchQuery(n, task, now))
+    case 'or':
+      return node.nodes.some((n) => matchQuery(n, task, now))
+    case 'not':
+      return !matchQuery(node.node, task, now)
+    case 'term':
+      return matchTerm(task, node.field, node.op, node.values, now)
+  }
+}
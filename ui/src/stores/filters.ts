@@ -14,6 +14,7 @@ export type FilterOperator =
   | 'is_set'
   | 'is_not_set'
   | 'contains'
+  | 'matches'
 
 // Fields that can be filtered
 export type FilterField =
@@ -25,6 +26,15 @@ export type FilterField =
   | 'epic'
   | 'created_by'
   | 'title'
+  // Saved by 'egenskriven view create'
+  | 'created_by_agent'
+  | 'sprint'
+  | 'parent'
+  | 'assignees'
+  | 'blocked_by'
+  | 'description'
+  // Task query such as "priority:high is:blocked" (see lib/taskQuery.ts)
+  | 'query'
 
 // Single filter definition
 export interface Filter {
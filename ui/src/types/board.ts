@@ -16,6 +16,8 @@ export interface Board extends RecordModel {
   resume_mode?: ResumeMode
   /** Internal sequence counter for task IDs - not typically used by UI */
   next_seq?: number
+  /** Set while the board is in the trash */
+  deleted_at?: string
 }
 
 // Default columns for new boards
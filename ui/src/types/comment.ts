@@ -17,6 +17,7 @@ export interface Comment extends RecordModel {
   author_type: AuthorType // Who created the comment
   author_id?: string // Username, agent name, or identifier
   metadata?: CommentMetadata // Additional context
+  attachments?: string[] // Stored names of attached files (served from /api/files)
   created: string // ISO timestamp (auto-populated by PocketBase)
 }
 
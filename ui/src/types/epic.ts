@@ -9,6 +9,7 @@ export interface Epic extends RecordModel {
   description?: string
   color?: string // Hex color (e.g., "#3B82F6")
   board: string // Board ID this epic belongs to
+  deleted_at?: string // Set while the epic is in the trash
 }
 
 /**
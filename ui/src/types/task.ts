@@ -13,17 +13,31 @@ export type Column = 'backlog' | 'todo' | 'in_progress' | 'need_input' | 'review
 // Creator type
 export type CreatedBy = 'user' | 'agent' | 'cli'
 
-// History entry for activity tracking
-export interface HistoryEntry {
+// Field change recorded in a task event
+export interface FieldChange {
+  from: unknown
+  to: unknown
+}
+
+// Task event record from PocketBase, the activity log of a task
+// Aligns with migrations/1700000027_task_events.go schema
+export interface TaskEvent extends RecordModel {
+  task: string                // Task ID
+  board?: string              // Board ID
+  action: string              // created, updated, moved, blocked, archived, ...
+  actor: string               // user, cli, tui, api, agent, system
+  actor_detail?: string       // Agent or account name
+  changes?: Record<string, FieldChange | unknown> | null
+  metadata?: Record<string, unknown> | null
   timestamp: string
-  action: 'created' | 'updated' | 'moved' | 'completed' | 'deleted'
-  actor: CreatedBy
-  actor_detail?: string
-  changes?: {
-    field: string
-    from: unknown
-    to: unknown
-  }
+}
+
+// Checklist item, mirrored as a "- [ ]" checkbox in the description
+export interface ChecklistItem {
+  text: string
+  done: boolean
+  done_by?: string
+  done_at?: string
 }
 
 // Task record from PocketBase
@@ -40,12 +54,19 @@ export interface Task extends RecordModel {
   due_date?: string           // Optional due date
   parent?: string             // Parent task ID for sub-tasks
   epic?: string               // Epic ID (relation to epics collection)
+  sprint?: string             // Sprint ID (relation to sprints collection)
+  assignees?: string[]        // Names of the people or agents assigned
   created_by: CreatedBy
   created_by_agent?: string   // Agent identifier (e.g., "claude", "opencode")
-  history?: HistoryEntry[]    // Activity tracking array
   board?: string              // Board ID (relation to boards collection)
   seq?: number                // Per-board sequence number for display IDs
   agent_session?: AgentSession // Current linked agent session (JSON field)
+  deleted_at?: string         // Set while the task is in the trash
+  deleted_by?: string         // Who moved the task to the trash
+  archived_at?: string        // Set while the task is archived
+  archived_by?: string        // Who archived the task ("auto-archive" for the board policy)
+  checklist?: ChecklistItem[] // Kept in sync with the description's checkboxes
+  attachments?: string[]      // Stored names of attached files (served from /api/files)
 }
 
 // All possible columns in display order